@@ -0,0 +1,30 @@
+package main
+
+import (
+	"context"
+	"os"
+
+	"golang.org/x/term"
+
+	"github.com/jmagar/nugs-cli/internal/catalog"
+)
+
+// resolveArtist maps an artist argument (ID, alias or name) to an artist ID.
+func resolveArtist(ctx context.Context, cfg *Config, jsonLevel, query string) (string, error) {
+	return catalog.ResolveArtistID(ctx, query, cfg, jsonLevel, artistPrompt(jsonLevel), buildCatalogDeps())
+}
+
+// resolveArtists maps each artist argument to an artist ID.
+func resolveArtists(ctx context.Context, cfg *Config, jsonLevel string, queries []string) ([]string, error) {
+	return catalog.ResolveArtistIDs(ctx, queries, cfg, jsonLevel, artistPrompt(jsonLevel), buildCatalogDeps())
+}
+
+// artistPrompt offers an interactive choice between ambiguous artist matches
+// only when attached to a terminal and not producing JSON; otherwise nil, so
+// ambiguity is a hard error.
+func artistPrompt(jsonLevel string) catalog.ArtistPrompt {
+	if jsonLevel != "" || !term.IsTerminal(int(os.Stdin.Fd())) || !term.IsTerminal(int(os.Stdout.Fd())) {
+		return nil
+	}
+	return catalog.NewArtistPrompt(os.Stdin, os.Stdout)
+}
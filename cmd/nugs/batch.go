@@ -6,6 +6,7 @@ import (
 	"context"
 
 	"github.com/jmagar/nugs-cli/internal/download"
+	"github.com/jmagar/nugs-cli/internal/model"
 )
 
 func artist(ctx context.Context, artistID string, cfg *Config, streamParams *StreamParams) error {
@@ -16,6 +17,10 @@ func playlist(ctx context.Context, plistID, legacyToken string, cfg *Config, str
 	return download.Playlist(ctx, plistID, legacyToken, cfg, streamParams, cat, buildDownloadDeps())
 }
 
+func tracks(ctx context.Context, name string, showTracks []model.ShowTrack, cfg *Config, streamParams *StreamParams) error {
+	return download.Tracks(ctx, name, showTracks, cfg, streamParams, buildDownloadDeps())
+}
+
 func paidLstream(ctx context.Context, query, uguID string, cfg *Config, streamParams *StreamParams) error {
 	return download.PaidLstream(ctx, query, uguID, cfg, streamParams, buildDownloadDeps())
 }
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/jmagar/nugs-cli/internal/cache"
+	"github.com/jmagar/nugs-cli/internal/history"
 )
 
 func readArtistMetaCache(artistID string) ([]*ArtistMeta, time.Time, error) {
@@ -15,3 +16,13 @@ func readArtistMetaCache(artistID string) ([]*ArtistMeta, time.Time, error) {
 func writeArtistMetaCache(artistID string, pages []*ArtistMeta) error {
 	return cache.WriteArtistMetaCache(artistID, pages)
 }
+
+// downloadHistory returns the history store in the cache directory, or nil
+// when the cache directory is unavailable so downloads proceed unrecorded.
+func downloadHistory() *history.Store {
+	store, err := history.Default()
+	if err != nil {
+		return nil
+	}
+	return store
+}
@@ -4,12 +4,16 @@ package main
 
 import (
 	"context"
+	"strings"
 
 	"github.com/jmagar/nugs-cli/internal/api"
 	"github.com/jmagar/nugs-cli/internal/catalog"
 	"github.com/jmagar/nugs-cli/internal/model"
 )
 
+// defaultLatestLimit is how many shows `catalog latest` lists by default.
+const defaultLatestLimit = 15
+
 // buildCatalogDeps wires root-level callbacks into the internal/catalog package.
 func buildCatalogDeps() *catalog.Deps {
 	return &catalog.Deps{
@@ -21,10 +25,13 @@ func buildCatalogDeps() *catalog.Deps {
 		ListRemoteArtistFolders: listRemoteArtistFolders,
 		Album:                   album,
 		Playlist:                playlist,
+		Tracks:                  tracks,
 		SetCurrentProgressBox:   setCurrentProgressBox,
 		GetShowMediaType:        getShowMediaType,
 		FormatDuration:          formatDuration,
 		GetArtistMetaCached:     getArtistMetaCached,
+		History:                 downloadHistory(),
+		Events:                  eventBus,
 	}
 }
 
@@ -36,6 +43,10 @@ func catalogUpdate(ctx context.Context, jsonLevel string) error {
 	return catalog.CatalogUpdate(ctx, jsonLevel, buildCatalogDeps())
 }
 
+func catalogFullCrawl(ctx context.Context, jsonLevel string) error {
+	return catalog.CatalogFullCrawl(ctx, jsonLevel, buildCatalogDeps())
+}
+
 func catalogCacheStatus(jsonLevel string) error {
 	return catalog.CatalogCacheStatus(jsonLevel, buildCatalogDeps())
 }
@@ -45,7 +56,27 @@ func catalogStats(ctx context.Context, cfg *Config, jsonLevel string) error {
 }
 
 func catalogLatest(ctx context.Context, limit int, jsonLevel string) error {
-	return catalog.CatalogLatest(ctx, limit, jsonLevel)
+	return catalog.CatalogLatest(ctx, limit, jsonLevel, buildCatalogDeps())
+}
+
+// catalogSearch runs a search query, first replacing artist:<alias> terms
+// with the aliased artist ID.
+func catalogSearch(cfg *Config, args []string, jsonLevel string) error {
+	terms := make([]string, len(args))
+	for i, arg := range args {
+		terms[i] = arg
+		key, value, ok := strings.Cut(arg, ":")
+		if !ok || !strings.EqualFold(key, "artist") {
+			continue
+		}
+		for alias, id := range cfg.ArtistAliases {
+			if strings.EqualFold(strings.TrimSpace(alias), strings.TrimSpace(value)) {
+				terms[i] = "artist:" + id
+				break
+			}
+		}
+	}
+	return catalog.CatalogSearch(terms, jsonLevel)
 }
 
 func catalogGapsForArtist(ctx context.Context, artistId string, cfg *Config, jsonLevel string, idsOnly bool, mediaFilter MediaType) error {
@@ -57,7 +88,7 @@ func catalogGaps(ctx context.Context, artistIds []string, cfg *Config, jsonLevel
 }
 
 func catalogGapsFill(ctx context.Context, artistId string, cfg *Config, streamParams *StreamParams, jsonLevel string, mediaFilter MediaType) error {
-	_, err := catalog.CatalogGapsFill(ctx, artistId, cfg, streamParams, jsonLevel, mediaFilter, buildCatalogDeps())
+	_, err := catalog.CatalogGapsFill(ctx, artistId, cfg, streamParams, jsonLevel, mediaFilter, nil, buildCatalogDeps())
 	return err
 }
 
@@ -20,6 +20,7 @@ func TestNormalizeCliAliases(t *testing.T) {
 		{name: "grab multiple ids", in: []string{"grab", "23329", "23790"}, want: []string{"23329", "23790"}},
 		{name: "catalog short update", in: []string{"update"}, want: []string{"catalog", "update"}},
 		{name: "catalog short gaps", in: []string{"gaps", "1125"}, want: []string{"catalog", "gaps", "1125"}},
+		{name: "search short", in: []string{"search", "venue:red rocks"}, want: []string{"catalog", "search", "venue:red rocks"}},
 		{name: "refresh short", in: []string{"refresh", "enable"}, want: []string{"catalog", "config", "enable"}},
 		{name: "unchanged old catalog", in: []string{"catalog", "gaps", "1125"}, want: []string{"catalog", "gaps", "1125"}},
 		// Media modifier cases
@@ -28,6 +29,10 @@ func TestNormalizeCliAliases(t *testing.T) {
 		{name: "list both modifier", in: []string{"list", "both"}, want: []string{"list", "artists", "both"}},
 		{name: "list artist video", in: []string{"list", "1125", "video"}, want: []string{"list", "1125", "video"}},
 		{name: "list artist audio", in: []string{"list", "1125", "audio"}, want: []string{"list", "1125", "audio"}},
+		{name: "list artist name words", in: []string{"list", "billy", "strings"}, want: []string{"list", "billy strings"}},
+		{name: "list artist name venue", in: []string{"list", "billy", "strings", "shows", "Red", "Rocks"}, want: []string{"list", "billy strings", "shows", "Red", "Rocks"}},
+		{name: "list artist name latest", in: []string{"list", "goose", "latest", "5"}, want: []string{"list", "goose", "latest", "5"}},
+		{name: "list artist name media", in: []string{"list", "billy", "strings", "video"}, want: []string{"list", "billy strings", "video"}},
 		{name: "list artist venue not media", in: []string{"list", "1125", "Red Rocks"}, want: []string{"list", "1125", "shows", "Red Rocks"}},
 	}
 
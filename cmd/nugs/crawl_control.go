@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
@@ -109,7 +110,10 @@ func startCrawlHotkeysIfNeeded(urls []string) func() {
 	}
 }
 
-func waitIfPausedOrCancelled() error {
+// waitIfPausedOrCancelled blocks while downloads are paused. It returns
+// ErrCrawlCancelled once the crawl is cancelled, and ctx's error when ctx ends
+// first, so an API cancel or server shutdown never waits on a paused crawl.
+func waitIfPausedOrCancelled(ctx context.Context) error {
 	for {
 		control := readRuntimeControlCached()
 		controlPaused := control.Pause
@@ -160,7 +164,11 @@ func waitIfPausedOrCancelled() error {
 			}
 		}
 
-		time.Sleep(100 * time.Millisecond)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(100 * time.Millisecond):
+		}
 	}
 	return nil
 }
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jmagar/nugs-cli/internal/cache"
+	"github.com/jmagar/nugs-cli/internal/catalog"
+	"github.com/jmagar/nugs-cli/internal/daemon"
+)
+
+// handleDaemonCommand routes post-auth "daemon". Returns true if handled.
+// The startup sign-in only checks the credentials; each watch check signs in
+// again for current stream parameters.
+func handleDaemonCommand(ctx context.Context, cfg *Config) (bool, error) {
+	if len(cfg.Urls) == 0 || cfg.Urls[0] != "daemon" {
+		return false, nil
+	}
+	if len(cfg.Urls) > 1 {
+		printInfo("Usage: nugs daemon")
+		return true, errors.New("daemon takes no arguments")
+	}
+	jobs, err := daemonJobs(cfg)
+	if err != nil {
+		return true, wrapCommandError("daemon", err)
+	}
+	d, err := daemon.Default(jobs)
+	if err != nil {
+		return true, wrapCommandError("daemon", err)
+	}
+	for _, job := range jobs {
+		printInfo(fmt.Sprintf("Scheduled %s: %s", job.Name, job.Schedule))
+	}
+	printInfo("Press Ctrl+C to stop")
+	return true, wrapCommandError("daemon", d.Run(ctx))
+}
+
+// daemonJobs builds the watch and catalog refresh jobs the config enables.
+// The config is read once, so schedule, watch list and credential changes
+// need a daemon restart.
+func daemonJobs(cfg *Config) ([]daemon.Job, error) {
+	var jobs []daemon.Job
+	if len(catalog.WatchEntries(cfg)) > 0 {
+		interval, err := catalog.WatchInterval(cfg)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, daemon.Job{
+			Name:     "watch",
+			Schedule: "every " + shortDuration(interval),
+			Next: func(last, now time.Time) (time.Time, error) {
+				if last.IsZero() {
+					return now, nil
+				}
+				return last.Add(interval), nil
+			},
+			Run: func(ctx context.Context) error {
+				return runTrackedJob(func() error {
+					// The subscription end date and stream tokens go stale
+					// over a long-running daemon, so sign in per check.
+					streamParams, _, _, err := authenticateForDownloads(ctx, cfg)
+					if err != nil {
+						return err
+					}
+					return watchCheck(ctx, cfg, streamParams, "", MediaTypeUnknown)
+				})
+			},
+		})
+	}
+	if cfg.CatalogAutoRefresh {
+		// Validate the schedule up front rather than on the first tick.
+		if _, err := catalog.NextAutoRefresh(cfg, time.Time{}, time.Now()); err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, daemon.Job{
+			Name:     "catalog-refresh",
+			Schedule: fmt.Sprintf("%s at %s %s", cfg.CatalogRefreshInterval, cfg.CatalogRefreshTime, cfg.CatalogRefreshTimezone),
+			// Keyed off the catalog cache rather than the job's own last run, so
+			// updates by watch checks or manual runs push the refresh back.
+			Next: func(_, now time.Time) (time.Time, error) {
+				var lastUpdated time.Time
+				if meta, err := cache.ReadCacheMeta(); err == nil && meta != nil {
+					lastUpdated = meta.LastUpdated
+				}
+				return catalog.NextAutoRefresh(cfg, lastUpdated, now)
+			},
+			Run: func(ctx context.Context) error {
+				return runTrackedJob(func() error {
+					return catalogUpdate(ctx, "")
+				})
+			},
+		})
+	}
+	if len(jobs) == 0 {
+		return nil, errors.New("nothing to schedule: add artists with 'nugs watch add <artistID>' or enable catalog auto-refresh with 'nugs refresh enable'")
+	}
+	return jobs, nil
+}
+
+// runTrackedJob publishes runtime status around one daemon job, so
+// `nugs status` shows its progress as it would for a foreground command.
+func runTrackedJob(fn func() error) error {
+	initRuntimeStatus()
+	err := fn()
+	finalizeRuntimeStatus(runtimeFinalState(false, err))
+	return err
+}
+
+// shortDuration drops zero trailing units: 1h0m0s prints as 1h.
+func shortDuration(d time.Duration) string {
+	s := d.String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
+
+// printDaemonStatus shows the daemon schedule under `nugs status`.
+func printDaemonStatus() { daemon.PrintStatus() }
@@ -12,7 +12,7 @@ import (
 // buildDownloadDeps creates the Deps struct wiring root-package callbacks
 // into the internal/download package.
 func buildDownloadDeps() *download.Deps {
-	return &download.Deps{
+	deps := &download.Deps{
 		WaitIfPausedOrCancelled: waitIfPausedOrCancelled,
 		IsCrawlCancelledErr:     isCrawlCancelledErr,
 		SetCurrentProgressBox:   setCurrentProgressBox,
@@ -20,10 +20,20 @@ func buildDownloadDeps() *download.Deps {
 		RenderCompletionSummary: renderCompletionSummary,
 		UploadToRclone:          uploadToRclone,
 		RemotePathExists:        remotePathExists,
+		VerifyUpload:            verifyUpload,
 		PrintProgress:           printProgress,
 		UpdateSpeedHistory:      updateSpeedHistory,
 		CalculateETA:            calculateETA,
+		History:                 downloadHistory(),
+		Events:                  eventBus,
 	}
+	if downloadStream != nil {
+		// --json: progress goes to the NDJSON stream instead of the terminal.
+		deps.RenderProgressBox = downloadStream.Progress
+		deps.RenderCompletionSummary = nil
+		deps.PrintProgress = nil
+	}
+	return deps
 }
 
 func album(ctx context.Context, albumID string, cfg *Config, streamParams *StreamParams, artResp *AlbArtResp, batchState *BatchProgressState, progressBox *ProgressBoxState) error {
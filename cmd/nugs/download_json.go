@@ -0,0 +1,53 @@
+package main
+
+// Command adapters for the --json download stream.
+
+import (
+	"os"
+
+	"github.com/jmagar/nugs-cli/internal/download"
+	"github.com/jmagar/nugs-cli/internal/report"
+)
+
+// downloadStream writes the NDJSON progress stream and run summary for a
+// download run with --json. It stays nil otherwise.
+var downloadStream *report.Stream
+
+// streamsDownloadJSON reports whether urls is a download that --json reports
+// as a stream: the "<artist> latest|full" shorthand, or only URLs and IDs.
+func streamsDownloadJSON(urls []string) bool {
+	if len(urls) == 0 {
+		return false
+	}
+	if (len(urls) == 2 || len(urls) == 3) && (urls[1] == "latest" || urls[1] == "full") {
+		return true
+	}
+	for _, u := range urls {
+		if itemID, _ := checkURL(u); itemID == "" {
+			return false
+		}
+	}
+	return true
+}
+
+// startDownloadStream points stdout at stderr so the usual messages stay
+// visible, writes the stream to the real stdout, and subscribes it to the
+// lifecycle events. Call after initEventBus.
+func startDownloadStream(jsonLevel string) {
+	stdout := os.Stdout
+	os.Stdout = os.Stderr
+	downloadStream = report.New(stdout, getCurrentProgressBox, jsonLevel == JSONLevelMinimal)
+	eventBus = eventBus.Subscribe(downloadStream.Event)
+}
+
+// finishDownloadStream writes the run summary.
+func finishDownloadStream(cancelled bool, err error) {
+	cancelled = cancelled || isCrawlCancelledErr(err)
+	if writeErr := downloadStream.Finish(err, downloadErrorClass(err), cancelled); writeErr != nil {
+		printWarning("Failed to write JSON output: " + writeErr.Error())
+	}
+}
+
+func downloadErrorClass(err error) string {
+	return download.ErrorClass(err)
+}
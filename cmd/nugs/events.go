@@ -0,0 +1,27 @@
+package main
+
+// Command adapters for download lifecycle events.
+
+import (
+	"github.com/jmagar/nugs-cli/internal/events"
+)
+
+// eventBus delivers lifecycle events to the configured hooks, event log and
+// notifiers. It stays nil (events disabled) when none are configured.
+var eventBus *events.Bus
+
+// initEventBus builds eventBus from the loaded config.
+func initEventBus(cfg *Config) error {
+	bus, err := events.New(cfg)
+	if err != nil {
+		return err
+	}
+	eventBus = bus
+	return nil
+}
+
+// closeEventBus waits for queued events to reach the hooks, event log and
+// notifiers before the process exits.
+func closeEventBus() {
+	eventBus.Close()
+}
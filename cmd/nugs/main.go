@@ -13,6 +13,9 @@ import (
 	"syscall"
 
 	"github.com/jmagar/nugs-cli/internal/api"
+	"github.com/jmagar/nugs-cli/internal/helpers"
+	"github.com/jmagar/nugs-cli/internal/model"
+	"github.com/jmagar/nugs-cli/internal/storage"
 	"github.com/jmagar/nugs-cli/internal/ui"
 )
 
@@ -129,12 +132,15 @@ func bootstrap() (*Config, string, error) {
 // cancel, completion), runtime tracking, environment setup, command routing (list,
 // catalog, artist shortcuts), authentication, and dispatching URL downloads.
 func run(cfg *Config, jsonLevel string) (runErr error) {
-	if maybeDetachAndExit(os.Args[1:], cfg.Urls) {
+	// A detached session would lose the JSON output, so --json runs stay in
+	// the foreground.
+	if jsonLevel == "" && maybeDetachAndExit(os.Args[1:], cfg.Urls) {
 		return nil
 	}
 
 	if len(cfg.Urls) == 1 && cfg.Urls[0] == "status" {
 		printRuntimeStatus()
+		printDaemonStatus()
 		return nil
 	}
 
@@ -178,17 +184,28 @@ func run(cfg *Config, jsonLevel string) (runErr error) {
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer stop()
 
+	if err := initEventBus(cfg); err != nil {
+		return err
+	}
+	defer closeEventBus()
+
 	// Auto-refresh catalog cache if needed
 	err := autoRefreshIfNeeded(ctx, cfg)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Auto-refresh warning: %v\n", err)
 	}
 
-	// Check if rclone is available when enabled
+	// Check the storage backend settings, and that rclone is available when
+	// any upload destination uses it
 	if cfg.RcloneEnabled {
-		err = checkRcloneAvailable(ctx, jsonLevel != "")
-		if err != nil {
-			return fmt.Errorf("rclone check failed: %w", err)
+		if err := storage.Validate(cfg); err != nil {
+			return err
+		}
+		if slices.Contains(helpers.StorageBackends(cfg), model.StorageBackendRclone) {
+			err = checkRcloneAvailable(ctx, jsonLevel != "")
+			if err != nil {
+				return fmt.Errorf("rclone check failed: %w", err)
+			}
 		}
 	}
 	printStartupEnvironment(ctx, cfg, jsonLevel)
@@ -212,10 +229,40 @@ func run(cfg *Config, jsonLevel string) (runErr error) {
 	if handled, err := handleWatchCommand(ctx, cfg, jsonLevel); handled {
 		return err
 	}
+	if handled, err := handleVerifyCommand(ctx, cfg, jsonLevel); handled {
+		return err
+	}
+	if handled, err := handleTranscodeCommand(ctx, cfg, jsonLevel); handled {
+		return err
+	}
+	if handled, err := handleRemoteCommand(ctx, cfg, jsonLevel); handled {
+		return err
+	}
+	if handled, err := handleQueueCommand(cfg, jsonLevel); handled {
+		return err
+	}
+	if handled, err := handleSongCommand(ctx, cfg, jsonLevel); handled {
+		return err
+	}
+	if handled, err := handlePlaylistCommand(cfg, jsonLevel); handled {
+		return err
+	}
+
+	if cfg.TrackSelector != "" {
+		if err := checkTrackSelectorTargets(cfg.Urls); err != nil {
+			return err
+		}
+	}
+
+	// Download with --json: stream NDJSON progress and end with a summary
+	if jsonLevel != "" && streamsDownloadJSON(cfg.Urls) {
+		startDownloadStream(jsonLevel)
+		defer func() { finishDownloadStream(runCancelled, runErr) }()
+	}
 
 	// Handle "<artistID> latest/full" shorthand
 	if len(cfg.Urls) == 2 || len(cfg.Urls) == 3 {
-		if handled, err := handleArtistShorthand(cfg); handled {
+		if handled, err := handleArtistShorthand(ctx, cfg, jsonLevel); handled {
 			return err
 		}
 	}
@@ -235,6 +282,26 @@ func run(cfg *Config, jsonLevel string) (runErr error) {
 		return err
 	}
 
+	// Handle "queue run" (requires auth)
+	if handled, err := handleQueueRunCommand(ctx, cfg, streamParams, legacyToken, uguID, jsonLevel); handled {
+		return err
+	}
+
+	// Handle "song grab" (requires auth)
+	if handled, err := handleSongGrabCommand(ctx, cfg, streamParams, jsonLevel); handled {
+		return err
+	}
+
+	// Handle "serve" (requires auth)
+	if handled, err := handleServeCommand(ctx, cfg, streamParams, legacyToken, uguID); handled {
+		return err
+	}
+
+	// Handle "daemon" (requires auth)
+	if handled, err := handleDaemonCommand(ctx, cfg); handled {
+		return err
+	}
+
 	runCancelled, runErr = dispatch(ctx, cfg, streamParams, legacyToken, uguID)
 	return runErr
 }
@@ -323,7 +390,10 @@ func handleListCommand(ctx context.Context, cfg *Config, jsonLevel string) (bool
 		return true, wrapCommandError("list artists", err)
 	}
 
-	artistId := subCmd
+	artistId, err := resolveArtist(ctx, cfg, jsonLevel, subCmd)
+	if err != nil {
+		return true, err
+	}
 
 	// Extract media modifier from remaining args after artist ID
 	remainingArgs := cfg.Urls[2:]
@@ -354,7 +424,7 @@ func handleListCommand(ctx context.Context, cfg *Config, jsonLevel string) (bool
 	}
 
 	// Default: list all shows for artist (with optional media filter)
-	err := listArtistShows(ctx, artistId, jsonLevel, mediaFilter)
+	err = listArtistShows(ctx, artistId, jsonLevel, mediaFilter)
 	return true, wrapCommandError("list shows", err)
 }
 
@@ -393,7 +463,7 @@ func handleCatalogCommand(ctx context.Context, cfg *Config, jsonLevel string) (b
 	}
 
 	if len(cfg.Urls) < 2 {
-		printInfo("Usage: nugs catalog update")
+		printInfo("Usage: nugs catalog update [full]")
 		fmt.Println("       catalog cache")
 		fmt.Println("       catalog stats")
 		fmt.Println("       catalog latest [limit]")
@@ -401,6 +471,7 @@ func handleCatalogCommand(ctx context.Context, cfg *Config, jsonLevel string) (b
 		fmt.Println("       catalog gaps <artist_id> [...] [fill]")
 		fmt.Println("       catalog gaps <artist_id> [...] --ids-only")
 		fmt.Println("       catalog coverage [artist_ids...]")
+		fmt.Println("       catalog search <query...>")
 		fmt.Println("       catalog config enable|disable|set")
 		fmt.Println()
 		fmt.Println("       Note: catalog = cached/offline, list = live API")
@@ -410,13 +481,19 @@ func handleCatalogCommand(ctx context.Context, cfg *Config, jsonLevel string) (b
 	subCmd := cfg.Urls[1]
 	switch subCmd {
 	case "update":
+		if len(cfg.Urls) > 2 {
+			if cfg.Urls[2] != "full" {
+				return true, fmt.Errorf("unknown catalog update mode %q (expected 'full')", cfg.Urls[2])
+			}
+			return true, wrapCommandError("catalog update full", catalogFullCrawl(ctx, jsonLevel))
+		}
 		return true, wrapCommandError("catalog update", catalogUpdate(ctx, jsonLevel))
 	case "cache":
 		return true, wrapCommandError("catalog cache status", catalogCacheStatus(jsonLevel))
 	case "stats":
 		return true, wrapCommandError("catalog stats", catalogStats(ctx, cfg, jsonLevel))
 	case "latest":
-		limit := 15
+		limit := defaultLatestLimit
 		argsAfterLatest := []string{}
 		if len(cfg.Urls) > 2 {
 			argsAfterLatest = cfg.Urls[2:]
@@ -462,6 +539,10 @@ func handleCatalogCommand(ctx context.Context, cfg *Config, jsonLevel string) (b
 		if len(artistIds) == 0 {
 			return true, errors.New("catalog gaps requires at least one artist ID")
 		}
+		artistIds, err := resolveArtists(ctx, cfg, jsonLevel, artistIds)
+		if err != nil {
+			return true, err
+		}
 		return true, wrapCommandError("catalog gaps", catalogGaps(ctx, artistIds, cfg, jsonLevel, idsOnly, mediaFilter))
 	case "list":
 		if len(cfg.Urls) < 3 {
@@ -472,6 +553,10 @@ func handleCatalogCommand(ctx context.Context, cfg *Config, jsonLevel string) (b
 		// Extract media modifier from args after "catalog list"
 		argsAfterList := cfg.Urls[2:]
 		mediaFilter, artistIds := parseMediaModifier(argsAfterList)
+		artistIds, err := resolveArtists(ctx, cfg, jsonLevel, artistIds)
+		if err != nil {
+			return true, err
+		}
 
 		return true, wrapCommandError("catalog list", catalogList(ctx, artistIds, cfg, jsonLevel, mediaFilter))
 	case "coverage":
@@ -482,8 +567,20 @@ func handleCatalogCommand(ctx context.Context, cfg *Config, jsonLevel string) (b
 
 		// Extract media modifier
 		mediaFilter, artistIds := parseMediaModifier(argsAfterCoverage)
+		artistIds, err := resolveArtists(ctx, cfg, jsonLevel, artistIds)
+		if err != nil {
+			return true, err
+		}
 
 		return true, wrapCommandError("catalog coverage", catalogCoverage(ctx, artistIds, cfg, jsonLevel, mediaFilter))
+	case "search":
+		if len(cfg.Urls) < 3 {
+			printInfo("Usage: nugs search <query...>")
+			fmt.Println("       Fields: artist: venue: city: state: title: year: date: media: limit:")
+			fmt.Println("       Example: nugs search venue:\"red rocks\" year:2021..2023 media:video")
+			return true, nil
+		}
+		return true, wrapCommandError("catalog search", catalogSearch(cfg, cfg.Urls[2:], jsonLevel))
 	case "config":
 		if len(cfg.Urls) < 3 {
 			printInfo("Usage: nugs catalog config enable|disable|set")
@@ -505,13 +602,21 @@ func handleCatalogCommand(ctx context.Context, cfg *Config, jsonLevel string) (b
 	}
 }
 
-// handleArtistShorthand handles "<artistID> latest/full [media]" shortcuts.
-// Returns true if the input was handled (including error cases).
+// handleArtistShorthand handles "<artist> latest/full [media]" shortcuts,
+// where <artist> is an ID, alias or name. Returns true if the input was
+// handled (including error cases).
 // Accepts optional media type modifier: "audio", "video", or "both"
-func handleArtistShorthand(cfg *Config) (bool, error) {
+func handleArtistShorthand(ctx context.Context, cfg *Config, jsonLevel string) (bool, error) {
 	artistID, err := strconv.Atoi(cfg.Urls[0])
 	if err != nil {
-		return false, nil
+		if !isArtistShortcut(cfg.Urls[1]) || strings.Contains(cfg.Urls[0], "://") {
+			return false, nil
+		}
+		resolved, resolveErr := resolveArtist(ctx, cfg, jsonLevel, cfg.Urls[0])
+		if resolveErr != nil {
+			return true, resolveErr
+		}
+		artistID, _ = strconv.Atoi(resolved)
 	}
 
 	// Check for media type modifier (3rd arg: "audio", "video", "both")
@@ -561,6 +666,16 @@ func handleArtistShorthand(cfg *Config) (bool, error) {
 	return false, nil // continue to auth+dispatch with rewritten URL
 }
 
+// isArtistShortcut reports whether word is an artist shortcut ("latest" or
+// "full") or a catalog subcommand that handleArtistShorthand corrects.
+func isArtistShortcut(word string) bool {
+	switch word {
+	case "latest", "full", "gaps", "update", "cache", "stats", "config", "coverage", "list":
+		return true
+	}
+	return false
+}
+
 // handleCatalogGapsFill handles the "catalog gaps <artist_id> [...] fill" command
 // which requires authentication.
 func handleCatalogGapsFill(ctx context.Context, cfg *Config, streamParams *StreamParams, jsonLevel string) error {
@@ -573,6 +688,10 @@ func handleCatalogGapsFill(ctx context.Context, cfg *Config, streamParams *Strea
 		fmt.Println("Usage: catalog gaps <artist_id> [...] [audio|video|both] fill")
 		return errors.New("catalog gaps fill requires at least one artist ID")
 	}
+	artistIds, err := resolveArtists(ctx, cfg, jsonLevel, artistIds)
+	if err != nil {
+		return err
+	}
 	var failures []error
 	for idx, artistId := range artistIds {
 		if idx > 0 && jsonLevel == "" {
@@ -598,38 +717,27 @@ func dispatch(ctx context.Context, cfg *Config, streamParams *StreamParams, lega
 	completedItems := 0
 	var failures []error
 	for albumNum, _url := range cfg.Urls {
-		if err := waitIfPausedOrCancelled(); err != nil {
+		if err := waitIfPausedOrCancelled(ctx); err != nil {
 			if isCrawlCancelledErr(err) {
 				printWarning("Crawl cancelled")
 				return true, err
 			}
+			return false, err
 		}
 		errorsBefore := ui.RunErrorCount.Load()
 		warningsBefore := ui.RunWarningCount.Load()
 		fmt.Printf("\n%s%s Item %d of %d%s\n", colorBold, symbolPackage, albumNum+1, albumTotal, colorReset)
-		itemId, mediaType := checkURL(_url)
-		if itemId == "" {
+		downloadStream.ItemStarted(albumNum+1, albumTotal, _url)
+		if itemId, _ := checkURL(_url); itemId == "" {
 			fmt.Println("Invalid URL:", _url)
-			failures = append(failures, fmt.Errorf("item %d: invalid URL %q", albumNum+1, _url))
+			invalidErr := fmt.Errorf("item %d: invalid URL %q", albumNum+1, _url)
+			failures = append(failures, invalidErr)
+			downloadStream.ItemDone(invalidErr, downloadErrorClass(invalidErr))
 			continue
 		}
-		switch mediaType {
-		case urlTypeAlbum:
-			itemErr = album(ctx, itemId, cfg, streamParams, nil, nil, nil)
-		case urlTypePlaylist, urlTypeLibraryPlaylist:
-			itemErr = playlist(ctx, itemId, legacyToken, cfg, streamParams, false)
-		case urlTypeShortenedURL:
-			itemErr = catalogPlist(ctx, itemId, legacyToken, cfg, streamParams)
-		case urlTypeVideo, urlTypeLibraryWebcast:
-			itemErr = video(ctx, itemId, "", cfg, streamParams, nil, false, nil)
-		case urlTypeArtist:
-			itemErr = artist(ctx, itemId, cfg, streamParams)
-		case urlTypeLivestreamExcl, urlTypeLivestreamWatch, urlTypeLivestreamArch:
-			itemErr = video(ctx, itemId, "", cfg, streamParams, nil, true, nil)
-		case urlTypePaidLivestream:
-			itemErr = paidLstream(ctx, itemId, uguID, cfg, streamParams)
-		case urlTypeNumericID:
-			itemErr = album(ctx, itemId, cfg, streamParams, nil, nil, nil)
+		itemErr = dispatchURL(ctx, cfg, streamParams, legacyToken, uguID, _url)
+		if !isCrawlCancelledErr(itemErr) {
+			downloadStream.ItemDone(itemErr, downloadErrorClass(itemErr))
 		}
 		if itemErr != nil {
 			if isCrawlCancelledErr(itemErr) {
@@ -656,3 +764,52 @@ func dispatch(ctx context.Context, cfg *Config, streamParams *StreamParams, lega
 	printInfo(fmt.Sprintf("Total health: errors=%d warnings=%d", ui.RunErrorCount.Load(), ui.RunWarningCount.Load()))
 	return false, errors.Join(failures...)
 }
+
+// checkTrackSelectorTargets rejects --tracks unless every URL is a single
+// release. Playlists, videos, livestreams, artist downloads and the other
+// commands would ignore the selection or apply it to every show.
+func checkTrackSelectorTargets(urls []string) error {
+	for _, u := range urls {
+		if itemID, mediaType := checkURL(u); itemID == "" || (mediaType != urlTypeAlbum && mediaType != urlTypeNumericID) {
+			return fmt.Errorf("--tracks only applies to release URLs and IDs, not %q", u)
+		}
+	}
+	return nil
+}
+
+// configForMedia returns cfg, or a copy whose defaultOutputs is replaced by a
+// per-item media modifier ("audio", "video", "both").
+func configForMedia(cfg *Config, media string) *Config {
+	if media == "" {
+		return cfg
+	}
+	itemCfg := *cfg
+	itemCfg.DefaultOutputs = media
+	return &itemCfg
+}
+
+// dispatchURL resolves one URL or numeric ID and routes it to the matching
+// download entry point. It is shared by dispatch and the queue worker.
+func dispatchURL(ctx context.Context, cfg *Config, streamParams *StreamParams, legacyToken, uguID, _url string) error {
+	itemId, mediaType := checkURL(_url)
+	if itemId == "" {
+		return fmt.Errorf("invalid URL %q", _url)
+	}
+	switch mediaType {
+	case urlTypeAlbum, urlTypeNumericID:
+		return album(ctx, itemId, cfg, streamParams, nil, nil, nil)
+	case urlTypePlaylist, urlTypeLibraryPlaylist:
+		return playlist(ctx, itemId, legacyToken, cfg, streamParams, false)
+	case urlTypeShortenedURL:
+		return catalogPlist(ctx, itemId, legacyToken, cfg, streamParams)
+	case urlTypeVideo, urlTypeLibraryWebcast:
+		return video(ctx, itemId, "", cfg, streamParams, nil, false, nil)
+	case urlTypeArtist:
+		return artist(ctx, itemId, cfg, streamParams)
+	case urlTypeLivestreamExcl, urlTypeLivestreamWatch, urlTypeLivestreamArch:
+		return video(ctx, itemId, "", cfg, streamParams, nil, true, nil)
+	case urlTypePaidLivestream:
+		return paidLstream(ctx, itemId, uguID, cfg, streamParams)
+	}
+	return nil
+}
@@ -7,6 +7,7 @@ import (
 	"os"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/jmagar/nugs-cli/internal/testutil"
 )
@@ -63,7 +64,7 @@ func TestDispatchReturnsAggregateErrorForInvalidItem(t *testing.T) {
 
 func TestArtistShorthandReturnsErrorInsteadOfExiting(t *testing.T) {
 	cfg := &Config{Urls: []string{"1125", "gaps"}}
-	handled, err := handleArtistShorthand(cfg)
+	handled, err := handleArtistShorthand(context.Background(), cfg, "")
 	if !handled || err == nil {
 		t.Fatalf("handleArtistShorthand() = (%v, %v), want handled error", handled, err)
 	}
@@ -99,3 +100,38 @@ func TestRuntimeFinalStateRecognizesWrappedCancellation(t *testing.T) {
 		t.Fatalf("runtimeFinalState() = %q, want failed", got)
 	}
 }
+
+func TestWaitIfPausedReturnsWhenContextEnds(t *testing.T) {
+	testutil.WithTempHome(t)
+	crawlerCtrl.paused.Store(true)
+	t.Cleanup(func() { crawlerCtrl.paused.Store(false) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+	done := make(chan error, 1)
+	go func() { done <- waitIfPausedOrCancelled(ctx) }()
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("waitIfPausedOrCancelled() = %v, want context.DeadlineExceeded", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("waitIfPausedOrCancelled() kept waiting after its context ended")
+	}
+}
+
+func TestCheckTrackSelectorTargets(t *testing.T) {
+	if err := checkTrackSelectorTargets([]string{"23329", "https://play.nugs.net/release/23790"}); err != nil {
+		t.Fatalf("releases: %v", err)
+	}
+	for _, urls := range [][]string{
+		{"1125", "latest"},
+		{"https://play.nugs.net/artist/1125"},
+		{"https://play.nugs.net/library/playlist/42"},
+		{"23329", "https://play.nugs.net/#/videos/artist/1/x/99"},
+	} {
+		if err := checkTrackSelectorTargets(urls); err == nil {
+			t.Errorf("checkTrackSelectorTargets(%q) = nil, want an error", urls)
+		}
+	}
+}
@@ -7,6 +7,9 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/jmagar/nugs-cli/internal/helpers"
+	"github.com/jmagar/nugs-cli/internal/model"
+	"github.com/jmagar/nugs-cli/internal/storage"
 	"github.com/jmagar/nugs-cli/internal/ui"
 )
 
@@ -35,6 +38,31 @@ func printStartupEnvironment(ctx context.Context, cfg *Config, jsonLevel string)
 	printKeyValue("FFmpeg Binary", cfg.FfmpegNameStr, colorCyan)
 	printKeyValue("Audio Output", cfg.OutPath, colorCyan)
 	printKeyValue("Video Output", getVideoOutPath(cfg), colorCyan)
+	if cfg.RcloneEnabled && len(cfg.UploadDestinations) > 0 {
+		for _, dest := range cfg.UploadDestinations {
+			destCfg := helpers.DestinationConfig(cfg, dest)
+			desc := storage.Describe(destCfg, false)
+			if video := storage.Describe(destCfg, true); video != desc {
+				desc += " (video: " + video + ")"
+			}
+			if media := helpers.DestinationMedia(dest); media != model.MediaTypeBoth {
+				desc += ", " + media.String() + " only"
+			}
+			if dest.Optional {
+				desc += ", optional"
+			}
+			printKeyValue("Destination "+dest.Name, desc, colorCyan)
+		}
+		fmt.Println("")
+		return
+	}
+	if backend := helpers.StorageBackend(cfg); cfg.RcloneEnabled && backend != model.StorageBackendRclone {
+		printKeyValue("Storage Backend", backend, colorYellow)
+		printKeyValue("Remote Audio Path", storage.Describe(cfg, false), colorCyan)
+		printKeyValue("Remote Video Path", storage.Describe(cfg, true), colorCyan)
+		fmt.Println("")
+		return
+	}
 	rcloneAudioPath := "Disabled"
 	rcloneVideoPath := "Disabled"
 	if cfg.RcloneEnabled {
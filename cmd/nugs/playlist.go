@@ -0,0 +1,34 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/jmagar/nugs-cli/internal/catalog"
+)
+
+func printPlaylistUsage() {
+	printInfo("Usage: nugs playlist export <file.m3u8|file.xspf> <query...>")
+	fmt.Println("       The query uses the nugs search syntax, e.g. venue:\"red rocks\" year:2023")
+}
+
+// handlePlaylistCommand routes "playlist export". Returns true if handled.
+func handlePlaylistCommand(cfg *Config, jsonLevel string) (bool, error) {
+	if len(cfg.Urls) == 0 || cfg.Urls[0] != "playlist" {
+		return false, nil
+	}
+	if len(cfg.Urls) < 2 {
+		printPlaylistUsage()
+		return true, nil
+	}
+	switch subCmd := cfg.Urls[1]; subCmd {
+	case "export":
+		if len(cfg.Urls) < 4 {
+			printPlaylistUsage()
+			return true, nil
+		}
+		return true, wrapCommandError("playlist export", catalog.PlaylistExport(cfg.Urls[2], cfg.Urls[3:], cfg, jsonLevel, buildCatalogDeps()))
+	default:
+		printPlaylistUsage()
+		return true, fmt.Errorf("unknown playlist command: %s", subCmd)
+	}
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/jmagar/nugs-cli/internal/catalog"
+	"github.com/jmagar/nugs-cli/internal/queue"
+)
+
+func printQueueUsage() {
+	printInfo("Usage: nugs queue add <id|url>... [audio|video|both]")
+	fmt.Println("       nugs queue list")
+	fmt.Println("       nugs queue remove <queue_id>")
+	fmt.Println("       nugs queue reorder <queue_id> <position>")
+	fmt.Println("       nugs queue run")
+}
+
+func parseQueueID(arg string) (int, error) {
+	id, err := strconv.Atoi(arg)
+	if err != nil || id < 1 {
+		return 0, fmt.Errorf("queue ID must be a positive integer: %q", arg)
+	}
+	return id, nil
+}
+
+// handleQueueCommand routes pre-auth "queue" subcommands (add/list/remove/reorder).
+// Returns false for "queue run", which needs auth and is handled by handleQueueRunCommand.
+func handleQueueCommand(cfg *Config, jsonLevel string) (bool, error) {
+	if len(cfg.Urls) == 0 || cfg.Urls[0] != "queue" {
+		return false, nil
+	}
+	if len(cfg.Urls) < 2 {
+		printQueueUsage()
+		return true, nil
+	}
+	subCmd := cfg.Urls[1]
+	if subCmd == "run" {
+		return false, nil
+	}
+	q, err := queue.Default()
+	if err != nil {
+		return true, err
+	}
+
+	switch subCmd {
+	case "add":
+		mediaFilter, urls := parseMediaModifier(cfg.Urls[2:])
+		if len(urls) == 0 {
+			return true, errors.New("queue add requires at least one release ID or URL")
+		}
+		for _, u := range urls {
+			if itemId, _ := checkURL(u); itemId == "" {
+				return true, fmt.Errorf("queue add: invalid URL %q", u)
+			}
+		}
+		media := ""
+		if mediaFilter != MediaTypeUnknown {
+			media = mediaFilter.String()
+		}
+		items, err := q.Add(media, urls...)
+		if err != nil {
+			return true, wrapCommandError("queue add", err)
+		}
+		if jsonLevel != "" {
+			return true, catalog.PrintJSON(map[string]any{"queued": items})
+		}
+		for _, item := range items {
+			printSuccess(fmt.Sprintf("Queued #%d %s", item.ID, item.URL))
+		}
+	case "list":
+		items, err := q.Items()
+		if err != nil {
+			return true, wrapCommandError("queue list", err)
+		}
+		return true, wrapCommandError("queue list", queue.PrintItems(items, jsonLevel))
+	case "remove":
+		if len(cfg.Urls) != 3 {
+			return true, errors.New("queue remove requires a queue ID")
+		}
+		id, err := parseQueueID(cfg.Urls[2])
+		if err != nil {
+			return true, err
+		}
+		if err := q.Remove(id); err != nil {
+			return true, wrapCommandError("queue remove", err)
+		}
+		printSuccess(fmt.Sprintf("Removed #%d from the queue", id))
+	case "reorder":
+		if len(cfg.Urls) != 4 {
+			return true, errors.New("queue reorder requires a queue ID and a position")
+		}
+		id, err := parseQueueID(cfg.Urls[2])
+		if err != nil {
+			return true, err
+		}
+		position, err := strconv.Atoi(cfg.Urls[3])
+		if err != nil || position < 1 {
+			return true, fmt.Errorf("queue position must be a positive integer: %q", cfg.Urls[3])
+		}
+		if err := q.Move(id, position); err != nil {
+			return true, wrapCommandError("queue reorder", err)
+		}
+		printSuccess(fmt.Sprintf("Moved #%d to position %d", id, position))
+	default:
+		printQueueUsage()
+		return true, fmt.Errorf("unknown queue command: %s", subCmd)
+	}
+	return true, nil
+}
+
+// handleQueueRunCommand routes post-auth "queue run". Returns true if handled.
+func handleQueueRunCommand(ctx context.Context, cfg *Config, streamParams *StreamParams, legacyToken, uguID, jsonLevel string) (bool, error) {
+	if len(cfg.Urls) != 2 || cfg.Urls[0] != "queue" || cfg.Urls[1] != "run" {
+		return false, nil
+	}
+	q, err := queue.Default()
+	if err != nil {
+		return true, err
+	}
+	summary, runErr := q.Run(ctx, &queue.Deps{
+		Process: func(ctx context.Context, item queue.Item) error {
+			return dispatchURL(ctx, configForMedia(cfg, item.Media), streamParams, legacyToken, uguID, item.URL)
+		},
+		WaitIfPausedOrCancelled: waitIfPausedOrCancelled,
+		IsCrawlCancelledErr:     isCrawlCancelledErr,
+	})
+	if isCrawlCancelledErr(runErr) {
+		printWarning("Queue run cancelled; unfinished items stay queued")
+	}
+	if jsonLevel != "" {
+		if err := catalog.PrintJSON(summary); err != nil {
+			return true, err
+		}
+	} else {
+		fmt.Println()
+		printSection("Queue Summary")
+		printInfo(fmt.Sprintf("Completed %d | retried %d | failed %d", summary.Completed, summary.Retries, summary.Failed))
+	}
+	return true, wrapCommandError("queue run", runErr)
+}
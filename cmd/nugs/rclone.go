@@ -4,12 +4,16 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"maps"
 	"time"
 
 	"github.com/dustin/go-humanize"
+	"github.com/jmagar/nugs-cli/internal/helpers"
 	"github.com/jmagar/nugs-cli/internal/model"
 	"github.com/jmagar/nugs-cli/internal/rclone"
+	"github.com/jmagar/nugs-cli/internal/storage"
 )
 
 const (
@@ -29,7 +33,30 @@ func uploadToRclone(ctx context.Context, localPath string, artistFolder string,
 	if progressBox != nil {
 		return uploadWithProgressBox(ctx, localPath, artistFolder, cfg, progressBox, isVideo)
 	}
-	return rclone.UploadToRclone(ctx, localPath, artistFolder, cfg, nil, isVideo, nil, nil, nil)
+	return uploadToStorage(ctx, localPath, artistFolder, cfg, nil, isVideo, nil)
+}
+
+// uploadToStorage uploads through rclone or the native provider selected by
+// storageBackend.
+func uploadToStorage(ctx context.Context, localPath, artistFolder string, cfg *Config, progressFn rclone.UploadProgressFunc, isVideo bool, onPreUpload func(totalBytes int64)) error {
+	if helpers.StorageBackend(cfg) == model.StorageBackendRclone {
+		return rclone.UploadToRclone(ctx, localPath, artistFolder, cfg, progressFn, isVideo, onPreUpload, nil, nil)
+	}
+	provider, err := storage.ForConfig(cfg)
+	if err != nil {
+		return err
+	}
+	hooks := model.StorageHooks{OnPreUpload: onPreUpload}
+	if progressFn != nil {
+		hooks.OnProgress = func(p model.UploadProgress) {
+			progressFn(p.Percent, p.Speed, p.Uploaded, p.Total)
+		}
+	}
+	return provider.Upload(ctx, cfg, model.UploadRequest{
+		LocalPath:    localPath,
+		ArtistFolder: artistFolder,
+		IsVideo:      isVideo,
+	}, hooks)
 }
 
 // uploadWithProgressBox handles upload with progress box updates and phase tracking.
@@ -91,7 +118,7 @@ func uploadWithProgressBox(ctx context.Context, localPath string, artistFolder s
 		renderProgressBox(progressBox)
 	}
 
-	err := rclone.UploadToRclone(ctx, localPath, artistFolder, cfg, progressFn, isVideo, onPreUpload, nil, nil)
+	err := uploadToStorage(ctx, localPath, artistFolder, cfg, progressFn, isVideo, onPreUpload)
 
 	// Set upload duration and final state (success or error)
 	progressBox.Mu.Lock()
@@ -123,10 +150,114 @@ func uploadWithProgressBox(ctx context.Context, localPath string, artistFolder s
 
 func parseHumanizedBytes(s string) int64 { return rclone.ParseHumanizedBytes(s) }
 
+// presenceDestinations returns the destinations that decide whether a show is
+// already uploaded, and whether each of them must have it. Required
+// destinations all must; optional ones only count when every destination that
+// accepts the media is optional, and then any one of them is enough.
+func presenceDestinations(cfg *Config, isVideo bool) (dests []model.UploadDestination, requireAll bool) {
+	all := helpers.DestinationsForMedia(cfg, isVideo)
+	for _, dest := range all {
+		if !dest.Optional {
+			dests = append(dests, dest)
+		}
+	}
+	if len(dests) == 0 {
+		return all, false
+	}
+	return dests, true
+}
+
+// remotePathExists reports whether remotePath exists on every required upload
+// destination that accepts the media, so a newly added destination is filled
+// in rather than skipped.
 func remotePathExists(ctx context.Context, remotePath string, cfg *Config, isVideo bool) (bool, error) {
-	return rclone.RemotePathExists(ctx, remotePath, cfg, isVideo)
+	if len(cfg.UploadDestinations) == 0 {
+		return destinationPathExists(ctx, remotePath, cfg, isVideo)
+	}
+	dests, requireAll := presenceDestinations(cfg, isVideo)
+	var errs []error
+	for _, dest := range dests {
+		exists, err := destinationPathExists(ctx, remotePath, helpers.DestinationConfig(cfg, dest), isVideo)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", dest.Name, err))
+			continue
+		}
+		if exists && !requireAll {
+			return true, nil
+		}
+		if !exists && requireAll {
+			return false, nil
+		}
+	}
+	if len(errs) > 0 || !requireAll || len(dests) == 0 {
+		return false, errors.Join(errs...)
+	}
+	return true, nil
 }
 
+func destinationPathExists(ctx context.Context, remotePath string, cfg *Config, isVideo bool) (bool, error) {
+	if helpers.StorageBackend(cfg) == model.StorageBackendRclone {
+		return rclone.RemotePathExists(ctx, remotePath, cfg, isVideo)
+	}
+	provider, err := storage.ForConfig(cfg)
+	if err != nil {
+		return false, err
+	}
+	return provider.PathExists(ctx, cfg, remotePath, isVideo)
+}
+
+// listRemoteArtistFolders returns the folders under artistFolder that count
+// as uploaded: those on every required destination that accepts the media, or
+// on any destination when all of them are optional. Coverage lists each
+// destination on its own.
 func listRemoteArtistFolders(ctx context.Context, artistFolder string, cfg *Config, isVideo bool) (map[string]struct{}, error) {
-	return rclone.ListRemoteArtistFolders(ctx, artistFolder, cfg, isVideo)
+	if len(cfg.UploadDestinations) == 0 {
+		return listDestinationArtistFolders(ctx, artistFolder, cfg, isVideo)
+	}
+	dests, requireAll := presenceDestinations(cfg, isVideo)
+	folders := make(map[string]struct{})
+	for i, dest := range dests {
+		destFolders, err := listDestinationArtistFolders(ctx, artistFolder, helpers.DestinationConfig(cfg, dest), isVideo)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", dest.Name, err)
+		}
+		if !requireAll || i == 0 {
+			maps.Copy(folders, destFolders)
+			continue
+		}
+		maps.DeleteFunc(folders, func(name string, _ struct{}) bool {
+			_, ok := destFolders[name]
+			return !ok
+		})
+	}
+	return folders, nil
+}
+
+func listDestinationArtistFolders(ctx context.Context, artistFolder string, cfg *Config, isVideo bool) (map[string]struct{}, error) {
+	if helpers.StorageBackend(cfg) == model.StorageBackendRclone {
+		return rclone.ListRemoteArtistFolders(ctx, artistFolder, cfg, isVideo)
+	}
+	provider, err := storage.ForConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return provider.ListArtistFolders(ctx, cfg, artistFolder, isVideo)
+}
+
+// verifyUpload checks an upload on a single destination before local files
+// are deleted.
+func verifyUpload(ctx context.Context, localPath, artistFolder string, cfg *Config, isVideo bool) error {
+	provider, err := storage.ForConfig(cfg)
+	if err != nil {
+		return err
+	}
+	verifier, ok := provider.(model.StorageVerifier)
+	if !ok {
+		return fmt.Errorf("%s storage cannot verify uploads", helpers.StorageBackend(cfg))
+	}
+	return verifier.Verify(ctx, cfg, model.UploadRequest{
+		LocalPath:    localPath,
+		ArtistFolder: artistFolder,
+		IsVideo:      isVideo,
+	})
 }
@@ -1,6 +1,9 @@
 package main
 
 import (
+	"context"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -261,3 +264,52 @@ func BenchmarkSpeedCalculation(b *testing.B) {
 		}
 	}
 }
+
+func TestRemotePresenceNeedsEveryRequiredDestination(t *testing.T) {
+	oldDest, newDest, optionalDest := t.TempDir(), t.TempDir(), t.TempDir()
+	for _, dir := range []string{oldDest, optionalDest} {
+		if err := os.MkdirAll(filepath.Join(dir, "Goose", "Goose - Show"), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.MkdirAll(filepath.Join(newDest, "Goose"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	cfg := &Config{
+		RcloneEnabled: true,
+		UploadDestinations: []model.UploadDestination{
+			{Name: "old", StorageBackend: model.StorageBackendLocal, RclonePath: oldDest},
+			{Name: "new", StorageBackend: model.StorageBackendLocal, RclonePath: newDest},
+			{Name: "spare", StorageBackend: model.StorageBackendLocal, RclonePath: optionalDest, Optional: true},
+		},
+	}
+	ctx := context.Background()
+
+	exists, err := remotePathExists(ctx, "Goose/Goose - Show", cfg, false)
+	if err != nil || exists {
+		t.Fatalf("remotePathExists = %v, %v; want false while the new destination lacks the show", exists, err)
+	}
+	folders, err := listRemoteArtistFolders(ctx, "Goose", cfg, false)
+	if err != nil || len(folders) != 0 {
+		t.Fatalf("listRemoteArtistFolders = %v, %v; want no folders present everywhere", folders, err)
+	}
+
+	if err := os.MkdirAll(filepath.Join(newDest, "Goose", "Goose - Show"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if exists, err := remotePathExists(ctx, "Goose/Goose - Show", cfg, false); err != nil || !exists {
+		t.Fatalf("remotePathExists = %v, %v; want true once every required destination has it", exists, err)
+	}
+	if folders, err := listRemoteArtistFolders(ctx, "Goose", cfg, false); err != nil || len(folders) != 1 {
+		t.Fatalf("listRemoteArtistFolders = %v, %v; want the show", folders, err)
+	}
+
+	// With only optional destinations, any one of them is enough.
+	cfg.UploadDestinations = []model.UploadDestination{
+		{Name: "new", StorageBackend: model.StorageBackendLocal, RclonePath: t.TempDir(), Optional: true},
+		{Name: "spare", StorageBackend: model.StorageBackendLocal, RclonePath: optionalDest, Optional: true},
+	}
+	if exists, err := remotePathExists(ctx, "Goose/Goose - Show", cfg, false); err != nil || !exists {
+		t.Fatalf("remotePathExists with optional destinations = %v, %v; want true", exists, err)
+	}
+}
@@ -0,0 +1,25 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmagar/nugs-cli/internal/catalog"
+)
+
+// handleRemoteCommand routes "remote verify <artist_id>". Returns true if handled.
+func handleRemoteCommand(ctx context.Context, cfg *Config, jsonLevel string) (bool, error) {
+	if len(cfg.Urls) == 0 || cfg.Urls[0] != "remote" {
+		return false, nil
+	}
+	if len(cfg.Urls) != 3 || cfg.Urls[1] != "verify" {
+		printInfo("Usage: nugs remote verify <artist_id>")
+		fmt.Println("       Re-checks uploaded show folders against their checksum manifests.")
+		return true, nil
+	}
+	artistID, err := resolveArtist(ctx, cfg, jsonLevel, cfg.Urls[2])
+	if err != nil {
+		return true, wrapCommandError("remote verify", err)
+	}
+	return true, wrapCommandError("remote verify", catalog.RemoteVerify(ctx, artistID, cfg, jsonLevel, buildCatalogDeps()))
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jmagar/nugs-cli/internal/catalog"
+	"github.com/jmagar/nugs-cli/internal/server"
+)
+
+// handleServeCommand routes post-auth "serve [addr]". Returns true if handled.
+func handleServeCommand(ctx context.Context, cfg *Config, streamParams *StreamParams, legacyToken, uguID string) (bool, error) {
+	if len(cfg.Urls) == 0 || cfg.Urls[0] != "serve" {
+		return false, nil
+	}
+	if len(cfg.Urls) > 2 {
+		printInfo("Usage: nugs serve [host:port|unix:/path/to/socket]")
+		return true, errors.New("serve takes at most one listen address")
+	}
+	addr := server.DefaultAddr
+	if len(cfg.Urls) == 2 {
+		addr = cfg.Urls[1]
+	}
+	listener, err := server.Listen(addr)
+	if err != nil {
+		return true, wrapCommandError("serve", err)
+	}
+
+	// serve is a read-only command for detach purposes, so publish runtime
+	// status here; this also clears any stale pause/cancel request.
+	initRuntimeStatus()
+	srv := server.New(&server.Deps{
+		Grab: func(ctx context.Context, url, media string) error {
+			return dispatchURL(ctx, configForMedia(cfg, media), streamParams, legacyToken, uguID, url)
+		},
+		ValidURL: func(url string) bool {
+			itemId, _ := checkURL(url)
+			return itemId != ""
+		},
+		SetPaused:           setCrawlPaused,
+		ProgressBox:         getCurrentProgressBox,
+		IsCrawlCancelledErr: isCrawlCancelledErr,
+		CatalogQuery:        catalogQuery(cfg),
+		ResolveArtist: func(ctx context.Context, query string) (string, error) {
+			return catalog.ResolveArtistID(ctx, query, cfg, JSONLevelStandard, nil, buildCatalogDeps())
+		},
+	})
+	printSuccess(fmt.Sprintf("Serving the nugs API on %s", listener.Addr()))
+	printInfo("Press Ctrl+C to stop")
+	serveErr := srv.Serve(ctx, listener)
+	finalizeRuntimeStatus(runtimeFinalState(false, serveErr))
+	return true, wrapCommandError("serve", serveErr)
+}
+
+// setCrawlPaused pauses or resumes downloads the same way Shift-P does.
+func setCrawlPaused(paused bool) error {
+	crawlerCtrl.paused.Store(paused)
+	return requestRuntimePause(paused)
+}
+
+// catalogQuery runs a server catalog request through the same catalog
+// handlers as `nugs catalog <command> --json standard`, collecting their JSON
+// output instead of printing it.
+func catalogQuery(cfg *Config) func(ctx context.Context, req server.CatalogRequest) ([]byte, error) {
+	return func(ctx context.Context, req server.CatalogRequest) ([]byte, error) {
+		var out bytes.Buffer
+		deps := buildCatalogDeps()
+		deps.JSONOutput = &out
+		var artistIds []string
+		if req.ArtistID != "" {
+			artistIds = []string{req.ArtistID}
+		}
+		var err error
+		switch req.Command {
+		case "cache":
+			err = catalog.CatalogCacheStatus(JSONLevelStandard, deps)
+		case "stats":
+			err = catalog.CatalogStats(ctx, cfg, JSONLevelStandard, deps)
+		case "latest":
+			limit := req.Limit
+			if limit == 0 {
+				limit = defaultLatestLimit
+			}
+			err = catalog.CatalogLatest(ctx, limit, JSONLevelStandard, deps)
+		case "list":
+			err = catalog.CatalogList(ctx, artistIds, cfg, JSONLevelStandard, req.Media, deps)
+		case "gaps":
+			err = catalog.CatalogGaps(ctx, artistIds, cfg, JSONLevelStandard, false, req.Media, deps)
+		case "coverage":
+			err = catalog.CatalogCoverage(ctx, artistIds, cfg, JSONLevelStandard, req.Media, deps)
+		default:
+			err = fmt.Errorf("unknown catalog command %q", req.Command)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("catalog %s: %w", req.Command, err)
+		}
+		return out.Bytes(), nil
+	}
+}
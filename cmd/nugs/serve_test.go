@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/jmagar/nugs-cli/internal/cache"
+	"github.com/jmagar/nugs-cli/internal/model"
+	"github.com/jmagar/nugs-cli/internal/server"
+	"github.com/jmagar/nugs-cli/internal/testutil"
+)
+
+func TestCatalogQueryRunsInProcess(t *testing.T) {
+	testutil.WithTempHome(t)
+	var latest model.LatestCatalogResp
+	if err := json.Unmarshal([]byte(`{"Response":{"recentItems":[
+		{"containerID":2,"artistID":1125,"artistName":"Billy Strings","containerInfo":"Second"},
+		{"containerID":1,"artistID":1125,"artistName":"Billy Strings","containerInfo":"First"}
+	]}}`), &latest); err != nil {
+		t.Fatal(err)
+	}
+	if err := cache.WriteCatalogCache(&latest, 0, time.Duration.String); err != nil {
+		t.Fatal(err)
+	}
+
+	var out []byte
+	var err error
+	stdout := testutil.CaptureStdout(t, func() {
+		out, err = catalogQuery(&Config{})(context.Background(), server.CatalogRequest{Command: "latest", Limit: 1})
+	})
+	if err != nil {
+		t.Fatalf("catalogQuery: %v", err)
+	}
+	if stdout != "" {
+		t.Fatalf("catalog query printed %q to stdout", stdout)
+	}
+	var got struct {
+		Total int `json:"total"`
+		Shows []struct {
+			ContainerID int `json:"containerID"`
+		} `json:"shows"`
+	}
+	if err := json.Unmarshal(out, &got); err != nil {
+		t.Fatalf("output %q is not JSON: %v", out, err)
+	}
+	if got.Total != 1 || len(got.Shows) != 1 || got.Shows[0].ContainerID != 2 {
+		t.Fatalf("latest = %+v, want only the newest show", got)
+	}
+}
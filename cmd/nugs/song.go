@@ -0,0 +1,83 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jmagar/nugs-cli/internal/catalog"
+)
+
+func printSongUsage() {
+	printInfo("Usage: nugs song list <artist> <song...> [missing]")
+	fmt.Println("       nugs song stats <artist> [year]")
+	fmt.Println("       nugs song grab <artist> <song...>")
+	fmt.Println("       Quote artist names with spaces: nugs song list \"billy strings\" dust in a baggie")
+}
+
+// handleSongCommand routes pre-auth "song list" and "song stats". Returns
+// false for "song grab", which needs auth and is handled by
+// handleSongGrabCommand.
+func handleSongCommand(ctx context.Context, cfg *Config, jsonLevel string) (bool, error) {
+	if len(cfg.Urls) == 0 || cfg.Urls[0] != "song" {
+		return false, nil
+	}
+	if len(cfg.Urls) < 3 {
+		printSongUsage()
+		return true, nil
+	}
+	subCmd := cfg.Urls[1]
+	switch subCmd {
+	case "grab":
+		if len(cfg.Urls) < 4 {
+			printSongUsage()
+			return true, nil
+		}
+		return false, nil
+	case "list":
+		args := cfg.Urls[3:]
+		missingOnly := len(args) > 1 && args[len(args)-1] == "missing"
+		if missingOnly {
+			args = args[:len(args)-1]
+		}
+		if len(args) == 0 {
+			printSongUsage()
+			return true, nil
+		}
+		artistID, err := resolveArtist(ctx, cfg, jsonLevel, cfg.Urls[2])
+		if err != nil {
+			return true, wrapCommandError("song list", err)
+		}
+		return true, wrapCommandError("song list", catalog.SongList(ctx, artistID, strings.Join(args, " "), cfg, jsonLevel, missingOnly, buildCatalogDeps()))
+	case "stats":
+		if len(cfg.Urls) > 4 {
+			printSongUsage()
+			return true, nil
+		}
+		year := ""
+		if len(cfg.Urls) == 4 {
+			year = cfg.Urls[3]
+		}
+		artistID, err := resolveArtist(ctx, cfg, jsonLevel, cfg.Urls[2])
+		if err != nil {
+			return true, wrapCommandError("song stats", err)
+		}
+		return true, wrapCommandError("song stats", catalog.SongStats(ctx, artistID, year, cfg, jsonLevel, buildCatalogDeps()))
+	default:
+		printSongUsage()
+		return true, fmt.Errorf("unknown song command: %s", subCmd)
+	}
+}
+
+// handleSongGrabCommand routes post-auth "song grab". Returns true if handled.
+func handleSongGrabCommand(ctx context.Context, cfg *Config, streamParams *StreamParams, jsonLevel string) (bool, error) {
+	if len(cfg.Urls) < 4 || cfg.Urls[0] != "song" || cfg.Urls[1] != "grab" {
+		return false, nil
+	}
+	artistID, err := resolveArtist(ctx, cfg, jsonLevel, cfg.Urls[2])
+	if err != nil {
+		return true, wrapCommandError("song grab", err)
+	}
+	query := strings.Join(cfg.Urls[3:], " ")
+	return true, wrapCommandError("song grab", catalog.SongGrab(ctx, artistID, query, cfg, streamParams, jsonLevel, buildCatalogDeps()))
+}
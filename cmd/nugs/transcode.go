@@ -0,0 +1,30 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/jmagar/nugs-cli/internal/catalog"
+)
+
+// handleTranscodeCommand routes "transcode". Returns true if handled.
+func handleTranscodeCommand(ctx context.Context, cfg *Config, jsonLevel string) (bool, error) {
+	if len(cfg.Urls) == 0 || cfg.Urls[0] != "transcode" {
+		return false, nil
+	}
+	if len(cfg.Urls) < 2 {
+		printInfo("Usage: nugs transcode <path|artist> [profile...]")
+		fmt.Println("       Makes the transcodeProfiles copies of audio already downloaded.")
+		return true, nil
+	}
+	target := cfg.Urls[1]
+	if _, err := os.Stat(target); err != nil {
+		artistID, resolveErr := resolveArtist(ctx, cfg, jsonLevel, target)
+		if resolveErr != nil {
+			return true, wrapCommandError("transcode", fmt.Errorf("%q is neither an existing path nor an artist: %w", target, resolveErr))
+		}
+		target = artistID
+	}
+	return true, wrapCommandError("transcode", catalog.Transcode(ctx, target, cfg.Urls[2:], cfg, jsonLevel, buildCatalogDeps()))
+}
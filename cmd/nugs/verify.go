@@ -0,0 +1,30 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/jmagar/nugs-cli/internal/catalog"
+)
+
+// handleVerifyCommand routes "verify <path|artist>". Returns true if handled.
+func handleVerifyCommand(ctx context.Context, cfg *Config, jsonLevel string) (bool, error) {
+	if len(cfg.Urls) == 0 || cfg.Urls[0] != "verify" {
+		return false, nil
+	}
+	if len(cfg.Urls) != 2 {
+		printInfo("Usage: nugs verify <path|artist>")
+		fmt.Println("       Checks FLAC frame CRCs and MD5, and MP4 structure, of downloaded audio.")
+		return true, nil
+	}
+	target := cfg.Urls[1]
+	if _, err := os.Stat(target); err != nil {
+		artistID, resolveErr := resolveArtist(ctx, cfg, jsonLevel, target)
+		if resolveErr != nil {
+			return true, wrapCommandError("verify", fmt.Errorf("%q is neither an existing path nor an artist: %w", target, resolveErr))
+		}
+		target = artistID
+	}
+	return true, wrapCommandError("verify", catalog.Verify(ctx, target, cfg, jsonLevel, buildCatalogDeps()))
+}
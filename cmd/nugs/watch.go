@@ -9,9 +9,9 @@ import (
 	"github.com/jmagar/nugs-cli/internal/notify"
 )
 
-// watchAdd adds an artist to the watch list.
-func watchAdd(cfg *Config, artistID string) error {
-	return catalog.WatchAdd(cfg, artistID)
+// watchAdd adds an artist to the watch list, or updates its rules.
+func watchAdd(cfg *Config, artistID string, rules []string) error {
+	return catalog.WatchAdd(cfg, artistID, rules)
 }
 
 // watchRemove removes an artist from the watch list.
@@ -27,7 +27,11 @@ func watchList(cfg *Config, jsonLevel string) error {
 // watchCheck updates the catalog and runs gap-fill for all watched artists.
 func watchCheck(ctx context.Context, cfg *Config, streamParams *StreamParams, jsonLevel string, mediaFilter MediaType) error {
 	deps := buildCatalogDeps()
-	deps.Notify = notify.BuildNotifier(cfg.GotifyURL, cfg.GotifyToken)
+	notifier, err := notify.BuildNotifier(cfg)
+	if err != nil {
+		return err
+	}
+	deps.Notify = notifier
 	return catalog.WatchCheck(ctx, cfg, streamParams, jsonLevel, mediaFilter, deps)
 }
 
@@ -49,7 +53,7 @@ func handleWatchCommand(ctx context.Context, cfg *Config, jsonLevel string) (boo
 	}
 
 	if len(cfg.Urls) < 2 {
-		printInfo("Usage: nugs watch add <artistID>")
+		printInfo("Usage: nugs watch add <artistID> [since:YYYY] [until:YYYY] [venue:name] [media:audio|video|both] [archival:skip] [max:N]")
 		fmt.Println("       nugs watch remove <artistID>")
 		fmt.Println("       nugs watch list")
 		fmt.Println("       nugs watch check")
@@ -70,14 +74,22 @@ func handleWatchCommand(ctx context.Context, cfg *Config, jsonLevel string) (boo
 		if len(cfg.Urls) < 3 {
 			return true, errors.New("watch add requires an artist ID")
 		}
-		if err := watchAdd(cfg, cfg.Urls[2]); err != nil {
+		artistID, err := resolveArtist(ctx, cfg, jsonLevel, cfg.Urls[2])
+		if err != nil {
+			return true, fmt.Errorf("watch add failed: %w", err)
+		}
+		if err := watchAdd(cfg, artistID, cfg.Urls[3:]); err != nil {
 			return true, fmt.Errorf("watch add failed: %w", err)
 		}
 	case "remove":
 		if len(cfg.Urls) < 3 {
 			return true, errors.New("watch remove requires an artist ID")
 		}
-		if err := watchRemove(cfg, cfg.Urls[2]); err != nil {
+		artistID, err := resolveArtist(ctx, cfg, jsonLevel, cfg.Urls[2])
+		if err != nil {
+			return true, fmt.Errorf("watch remove failed: %w", err)
+		}
+		if err := watchRemove(cfg, artistID); err != nil {
 			return true, fmt.Errorf("watch remove failed: %w", err)
 		}
 	case "list":
@@ -93,13 +105,16 @@ func handleWatchCommand(ctx context.Context, cfg *Config, jsonLevel string) (boo
 			return true, fmt.Errorf("watch disable failed: %w", err)
 		}
 	default:
-		// Bare numeric ID shorthand: "nugs watch 1125" → "nugs watch add 1125"
-		if err := watchAdd(cfg, subCmd); err != nil {
+		// Bare artist shorthand: "nugs watch 1125" → "nugs watch add 1125"
+		artistID, err := resolveArtist(ctx, cfg, jsonLevel, subCmd)
+		if err != nil {
+			return true, fmt.Errorf("watch add failed: %w", err)
+		}
+		if err := watchAdd(cfg, artistID, cfg.Urls[2:]); err != nil {
 			return true, fmt.Errorf("watch add failed: %w", err)
 		}
 	}
 
-	_ = ctx // ctx unused for pre-auth commands but kept for signature consistency
 	return true, nil
 }
 
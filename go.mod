@@ -6,6 +6,7 @@ require (
 	github.com/alexflint/go-arg v1.6.1
 	github.com/dustin/go-humanize v1.0.1
 	github.com/grafov/m3u8 v0.12.1
+	go.etcd.io/bbolt v1.4.3
 	golang.org/x/sys v0.47.0
 	golang.org/x/term v0.45.0
 )
@@ -32,6 +32,11 @@ const (
 	SubInfoURL    = "https://subscriptions.nugs.net/api/v1/me/subscriptions"
 	UserInfoURL   = "https://id.nugs.net/connect/userinfo"
 	PlayerURL     = "https://play.nugs.net/"
+
+	// ArtworkBaseURL resolves the relative image paths returned in show metadata.
+	ArtworkBaseURL = "https://secure.livedownloads.com"
+	// maxArtworkBytes caps cover art downloads; real covers are well under 10MB.
+	maxArtworkBytes = 20 << 20
 )
 
 var (
@@ -372,6 +377,58 @@ func GetAlbumMeta(ctx context.Context, albumId string) (*model.AlbumMeta, error)
 	return &obj, nil
 }
 
+// ResolveArtworkURL turns an image path from show metadata into an absolute URL.
+// Absolute URLs are returned unchanged; bare file names are treated as show images.
+func ResolveArtworkURL(path string) string {
+	path = strings.TrimSpace(path)
+	switch {
+	case path == "":
+		return ""
+	case strings.HasPrefix(path, "http://"), strings.HasPrefix(path, "https://"):
+		return path
+	case strings.HasPrefix(path, "//"):
+		return "https:" + path
+	case strings.HasPrefix(path, "/"):
+		return ArtworkBaseURL + path
+	case strings.Contains(path, "/"):
+		return ArtworkBaseURL + "/" + path
+	default:
+		return ArtworkBaseURL + "/images/shows/" + path
+	}
+}
+
+// GetArtwork downloads the image at path (see ResolveArtworkURL) and returns
+// its raw bytes. Requests share the catalog rate limit and circuit breaker.
+func GetArtwork(ctx context.Context, path string) ([]byte, error) {
+	artURL := ResolveArtworkURL(path)
+	if artURL == "" {
+		return nil, errors.New("empty artwork path")
+	}
+	resp, err := retryDo(ctx, "catalog.artwork", func() (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, artURL, nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Add("User-Agent", UserAgent)
+		return req, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = drainAndClose(resp.Body) }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API catalog.artwork failed: %s", resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxArtworkBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("read artwork: %w", err)
+	}
+	if len(data) > maxArtworkBytes {
+		return nil, fmt.Errorf("artwork exceeds %d bytes", maxArtworkBytes)
+	}
+	return data, nil
+}
+
 // GetPlistMeta retrieves playlist metadata.
 func GetPlistMeta(ctx context.Context, plistId, email, legacyToken string, cat bool) (*model.PlistMeta, error) {
 	var apiPath string
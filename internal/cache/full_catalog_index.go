@@ -0,0 +1,217 @@
+package cache
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/jmagar/nugs-cli/internal/model"
+)
+
+// The full catalog index lives beside the catalog generations rather than in
+// them: it is rebuilt only by "catalog update full" and merged into by every
+// plain update, so it must survive generation swaps.
+const (
+	fullCatalogIndexFile = "full-catalog-containers.json"
+	fullCatalogMetaFile  = "full-catalog-meta.json"
+	fullCatalogCrawlFile = "full-catalog-crawl.json"
+)
+
+// ErrNoFullCatalog is returned by UpdateFullCatalog before the first full crawl.
+var ErrNoFullCatalog = errors.New("no full catalog index - run 'nugs catalog update full' first")
+
+// fullCatalogMemo keeps the last parsed index so coverage runs over hundreds
+// of artists parse the multi-megabyte file once. It is keyed by the size and
+// modification time of both files.
+var fullCatalogMemo struct {
+	sync.Mutex
+	key   [2]fileStamp
+	index *model.FullCatalogIndex
+	meta  *model.FullCatalogMeta
+}
+
+type fileStamp struct {
+	size    int64
+	modTime time.Time
+}
+
+func stampFile(path string) (fileStamp, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fileStamp{}, err
+	}
+	return fileStamp{size: info.Size(), modTime: info.ModTime()}, nil
+}
+
+func readFullCatalogLocked(cacheDir string) (*model.FullCatalogIndex, *model.FullCatalogMeta, error) {
+	metaData, err := os.ReadFile(filepath.Join(cacheDir, fullCatalogMetaFile))
+	if err != nil {
+		return nil, nil, err
+	}
+	var meta model.FullCatalogMeta
+	if err := json.Unmarshal(metaData, &meta); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse full catalog metadata: %w", err)
+	}
+	indexData, err := os.ReadFile(filepath.Join(cacheDir, fullCatalogIndexFile))
+	if err != nil {
+		return nil, nil, err
+	}
+	var index model.FullCatalogIndex
+	if err := json.Unmarshal(indexData, &index); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse full catalog index: %w", err)
+	}
+	if index.Containers == nil {
+		index.Containers = map[int]model.FullCatalogShow{}
+	}
+	return &index, &meta, nil
+}
+
+// ReadFullCatalog reads the full catalog index and its metadata. It returns
+// nil values (not an error) when no full crawl has completed yet. The
+// returned values are shared between callers and must not be modified.
+func ReadFullCatalog() (*model.FullCatalogIndex, *model.FullCatalogMeta, error) {
+	cacheDir, err := GetCacheDir()
+	if err != nil {
+		return nil, nil, err
+	}
+	var (
+		index *model.FullCatalogIndex
+		meta  *model.FullCatalogMeta
+	)
+	err = WithCacheLock(func() error {
+		var key [2]fileStamp
+		var statErr error
+		if key[0], statErr = stampFile(filepath.Join(cacheDir, fullCatalogMetaFile)); statErr != nil {
+			return statErr
+		}
+		if key[1], statErr = stampFile(filepath.Join(cacheDir, fullCatalogIndexFile)); statErr != nil {
+			return statErr
+		}
+
+		fullCatalogMemo.Lock()
+		defer fullCatalogMemo.Unlock()
+		if fullCatalogMemo.index != nil && fullCatalogMemo.key == key {
+			index, meta = fullCatalogMemo.index, fullCatalogMemo.meta
+			return nil
+		}
+		var readErr error
+		index, meta, readErr = readFullCatalogLocked(cacheDir)
+		if readErr != nil {
+			return readErr
+		}
+		fullCatalogMemo.key, fullCatalogMemo.index, fullCatalogMemo.meta = key, index, meta
+		return nil
+	})
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return index, meta, nil
+}
+
+func writeFullCatalogLocked(cacheDir string, index *model.FullCatalogIndex, meta *model.FullCatalogMeta) error {
+	indexData, err := json.Marshal(index)
+	if err != nil {
+		return fmt.Errorf("failed to marshal full catalog index: %w", err)
+	}
+	metaData, err := json.MarshalIndent(meta, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal full catalog metadata: %w", err)
+	}
+	if err := WriteFileAtomic(filepath.Join(cacheDir, fullCatalogIndexFile), indexData, 0644); err != nil {
+		return fmt.Errorf("failed to write full catalog index: %w", err)
+	}
+	if err := WriteFileAtomic(filepath.Join(cacheDir, fullCatalogMetaFile), metaData, 0644); err != nil {
+		return fmt.Errorf("failed to write full catalog metadata: %w", err)
+	}
+	return nil
+}
+
+// WriteFullCatalog replaces the full catalog index and its metadata.
+func WriteFullCatalog(index *model.FullCatalogIndex, meta *model.FullCatalogMeta) error {
+	cacheDir, err := GetCacheDir()
+	if err != nil {
+		return err
+	}
+	return WithCacheLock(func() error {
+		return writeFullCatalogLocked(cacheDir, index, meta)
+	})
+}
+
+// UpdateFullCatalog applies fn to a private copy of the full catalog under
+// the cache lock and writes the result. It returns ErrNoFullCatalog when no
+// full crawl has completed yet.
+func UpdateFullCatalog(fn func(index *model.FullCatalogIndex, meta *model.FullCatalogMeta) error) error {
+	cacheDir, err := GetCacheDir()
+	if err != nil {
+		return err
+	}
+	return WithCacheLock(func() error {
+		index, meta, err := readFullCatalogLocked(cacheDir)
+		if errors.Is(err, os.ErrNotExist) {
+			return ErrNoFullCatalog
+		}
+		if err != nil {
+			return err
+		}
+		if err := fn(index, meta); err != nil {
+			return err
+		}
+		return writeFullCatalogLocked(cacheDir, index, meta)
+	})
+}
+
+// ReadFullCatalogCrawl reads the checkpoint of an interrupted full crawl.
+// Returns nil (not an error) when there is nothing to resume.
+func ReadFullCatalogCrawl() (*model.FullCatalogCrawl, error) {
+	cacheDir, err := GetCacheDir()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(filepath.Join(cacheDir, fullCatalogCrawlFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read full catalog crawl state: %w", err)
+	}
+	var state model.FullCatalogCrawl
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("failed to parse full catalog crawl state: %w", err)
+	}
+	if state.Containers == nil {
+		state.Containers = map[int]model.FullCatalogShow{}
+	}
+	return &state, nil
+}
+
+// WriteFullCatalogCrawl checkpoints an in-progress full crawl.
+func WriteFullCatalogCrawl(state *model.FullCatalogCrawl) error {
+	cacheDir, err := GetCacheDir()
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("failed to marshal full catalog crawl state: %w", err)
+	}
+	return WriteFileAtomic(filepath.Join(cacheDir, fullCatalogCrawlFile), data, 0644)
+}
+
+// RemoveFullCatalogCrawl deletes the crawl checkpoint once a crawl completes.
+func RemoveFullCatalogCrawl() error {
+	cacheDir, err := GetCacheDir()
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(filepath.Join(cacheDir, fullCatalogCrawlFile)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
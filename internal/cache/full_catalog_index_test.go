@@ -0,0 +1,84 @@
+package cache
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jmagar/nugs-cli/internal/model"
+)
+
+func TestFullCatalogRoundTripAndUpdate(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	index, meta, err := ReadFullCatalog()
+	if err != nil || index != nil || meta != nil {
+		t.Fatalf("ReadFullCatalog() before crawl = %v, %v, %v; want nil values", index, meta, err)
+	}
+	if err := UpdateFullCatalog(func(*model.FullCatalogIndex, *model.FullCatalogMeta) error { return nil }); !errors.Is(err, ErrNoFullCatalog) {
+		t.Fatalf("UpdateFullCatalog() before crawl error = %v, want ErrNoFullCatalog", err)
+	}
+
+	written := &model.FullCatalogIndex{Containers: map[int]model.FullCatalogShow{
+		10: {ContainerID: 10, ArtistID: 1, ArtistName: "Artist", Media: model.MediaTypeBoth, Downloadable: true},
+	}}
+	if err := WriteFullCatalog(written, &model.FullCatalogMeta{FullCrawlAt: time.Now(), TotalShows: 1}); err != nil {
+		t.Fatal(err)
+	}
+	index, meta, err = ReadFullCatalog()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := index.Containers[10]; got.Media != model.MediaTypeBoth || !got.Downloadable || meta.TotalShows != 1 {
+		t.Fatalf("ReadFullCatalog() = %+v, %+v", got, meta)
+	}
+
+	err = UpdateFullCatalog(func(index *model.FullCatalogIndex, meta *model.FullCatalogMeta) error {
+		index.Containers[11] = model.FullCatalogShow{ContainerID: 11, ArtistID: 1}
+		meta.TotalShows = 2
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The memoized copy must be invalidated by the write.
+	index, meta, err = ReadFullCatalog()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(index.Containers) != 2 || meta.TotalShows != 2 {
+		t.Fatalf("after update: %d containers, meta %+v", len(index.Containers), meta)
+	}
+}
+
+func TestFullCatalogCrawlCheckpoint(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	if state, err := ReadFullCatalogCrawl(); err != nil || state != nil {
+		t.Fatalf("ReadFullCatalogCrawl() with no checkpoint = %v, %v", state, err)
+	}
+	want := &model.FullCatalogCrawl{
+		StartedAt:   time.Now().UTC().Truncate(time.Second),
+		DoneArtists: []int{1, 2},
+		Containers:  map[int]model.FullCatalogShow{10: {ContainerID: 10, ArtistID: 1}},
+	}
+	if err := WriteFullCatalogCrawl(want); err != nil {
+		t.Fatal(err)
+	}
+	got, err := ReadFullCatalogCrawl()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.StartedAt.Equal(want.StartedAt) || len(got.DoneArtists) != 2 || got.Containers[10].ArtistID != 1 {
+		t.Fatalf("ReadFullCatalogCrawl() = %+v", got)
+	}
+	if err := RemoveFullCatalogCrawl(); err != nil {
+		t.Fatal(err)
+	}
+	if state, _ := ReadFullCatalogCrawl(); state != nil {
+		t.Fatal("checkpoint still present after RemoveFullCatalogCrawl")
+	}
+	if err := RemoveFullCatalogCrawl(); err != nil {
+		t.Fatalf("second RemoveFullCatalogCrawl() error = %v", err)
+	}
+}
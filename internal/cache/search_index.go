@@ -0,0 +1,43 @@
+package cache
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/jmagar/nugs-cli/internal/model"
+)
+
+const searchIndexFile = "search-index.json"
+
+// ReadSearchIndex reads the search index built by the last catalog update.
+// A missing index returns os.ErrNotExist.
+func ReadSearchIndex() (*model.SearchIndex, error) {
+	cacheDir, err := GetCacheDir()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(filepath.Join(cacheDir, searchIndexFile))
+	if err != nil {
+		return nil, err
+	}
+	var index model.SearchIndex
+	if err := json.Unmarshal(data, &index); err != nil {
+		return nil, fmt.Errorf("failed to parse search index: %w", err)
+	}
+	return &index, nil
+}
+
+// WriteSearchIndex atomically replaces the search index.
+func WriteSearchIndex(index *model.SearchIndex) error {
+	cacheDir, err := GetCacheDir()
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(index)
+	if err != nil {
+		return fmt.Errorf("failed to marshal search index: %w", err)
+	}
+	return atomicWriteFile(filepath.Join(cacheDir, searchIndexFile), data)
+}
@@ -0,0 +1,80 @@
+package cache
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/jmagar/nugs-cli/internal/model"
+)
+
+const setlistIndexFile = "setlist-index.json"
+
+// ReadSetlistIndex reads the song index built from cached artist metadata.
+// A missing index returns os.ErrNotExist.
+func ReadSetlistIndex() (*model.SetlistIndex, error) {
+	cacheDir, err := GetCacheDir()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(filepath.Join(cacheDir, setlistIndexFile))
+	if err != nil {
+		return nil, err
+	}
+	var index model.SetlistIndex
+	if err := json.Unmarshal(data, &index); err != nil {
+		return nil, fmt.Errorf("failed to parse setlist index: %w", err)
+	}
+	return &index, nil
+}
+
+// WriteSetlistIndex atomically replaces the setlist index.
+func WriteSetlistIndex(index *model.SetlistIndex) error {
+	cacheDir, err := GetCacheDir()
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(index)
+	if err != nil {
+		return fmt.Errorf("failed to marshal setlist index: %w", err)
+	}
+	return atomicWriteFile(filepath.Join(cacheDir, setlistIndexFile), data)
+}
+
+// CachedArtistMeta maps each artist with cached metadata pages, in either
+// the artist metadata cache or the full catalog shards, to the newest
+// modification time among its files.
+func CachedArtistMeta() (map[string]time.Time, error) {
+	cacheDir, err := GetCacheDir()
+	if err != nil {
+		return nil, err
+	}
+	artists := map[string]time.Time{}
+	for _, dir := range []string{filepath.Join(cacheDir, "artists"), filepath.Join(cacheDir, fullCatalogShardDir)} {
+		entries, err := os.ReadDir(dir)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			name := entry.Name()
+			if entry.IsDir() || !strings.HasPrefix(name, "artist_") || !strings.HasSuffix(name, ".json") {
+				continue
+			}
+			id := strings.TrimSuffix(strings.TrimPrefix(name, "artist_"), ".json")
+			modTime := artists[id]
+			if info, err := entry.Info(); err == nil && info.ModTime().After(modTime) {
+				modTime = info.ModTime()
+			}
+			artists[id] = modTime
+		}
+	}
+	return artists, nil
+}
@@ -28,33 +28,12 @@ func ShouldAutoRefresh(cfg *model.Config) (bool, error) {
 		return true, nil
 	}
 
-	loc, err := time.LoadLocation(cfg.CatalogRefreshTimezone)
+	hour, minute, loc, err := parseRefreshSchedule(cfg)
 	if err != nil {
-		return false, fmt.Errorf("invalid timezone %s: %w", cfg.CatalogRefreshTimezone, err)
+		return false, err
 	}
 
 	now := time.Now().In(loc)
-
-	matches := timePattern.FindStringSubmatch(cfg.CatalogRefreshTime)
-	if matches == nil {
-		return false, fmt.Errorf("invalid refresh time format: %s (expected HH:MM)", cfg.CatalogRefreshTime)
-	}
-
-	var hour, minute int
-	if _, err := fmt.Sscanf(matches[1], "%d", &hour); err != nil {
-		return false, fmt.Errorf("invalid hour in refresh time: %s", matches[1])
-	}
-	if _, err := fmt.Sscanf(matches[2], "%d", &minute); err != nil {
-		return false, fmt.Errorf("invalid minute in refresh time: %s", matches[2])
-	}
-
-	if hour < 0 || hour > 23 {
-		return false, fmt.Errorf("hour must be 00-23, got %d", hour)
-	}
-	if minute < 0 || minute > 59 {
-		return false, fmt.Errorf("minute must be 00-59, got %d", minute)
-	}
-
 	todayRefreshTime := time.Date(now.Year(), now.Month(), now.Day(), hour, minute, 0, 0, loc)
 
 	switch cfg.CatalogRefreshInterval {
@@ -76,6 +55,75 @@ func ShouldAutoRefresh(cfg *model.Config) (bool, error) {
 	return false, nil
 }
 
+// NextAutoRefresh returns when the catalog is next due under the auto-refresh
+// schedule, given when it was last updated (zero if never). Hourly refreshes
+// fall an hour after the last update; daily ones at the first refresh time
+// after it; weekly ones at the first refresh time a week or more after it.
+// A refresh that is already due returns now.
+func NextAutoRefresh(cfg *model.Config, lastUpdated, now time.Time) (time.Time, error) {
+	hour, minute, loc, err := parseRefreshSchedule(cfg)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if lastUpdated.IsZero() {
+		return now, nil
+	}
+
+	var next time.Time
+	switch cfg.CatalogRefreshInterval {
+	case "hourly":
+		next = lastUpdated.Add(time.Hour)
+	case "daily":
+		next = nextRefreshSlot(lastUpdated, hour, minute, loc)
+	case "weekly":
+		next = nextRefreshSlot(lastUpdated.Add(7*24*time.Hour-time.Nanosecond), hour, minute, loc)
+	default:
+		return time.Time{}, fmt.Errorf("invalid refresh interval %q (must be 'hourly', 'daily', or 'weekly')", cfg.CatalogRefreshInterval)
+	}
+	if next.Before(now) {
+		return now, nil
+	}
+	return next, nil
+}
+
+// nextRefreshSlot returns the first hour:minute in loc strictly after t.
+func nextRefreshSlot(t time.Time, hour, minute int, loc *time.Location) time.Time {
+	local := t.In(loc)
+	slot := time.Date(local.Year(), local.Month(), local.Day(), hour, minute, 0, 0, loc)
+	if !slot.After(t) {
+		slot = time.Date(local.Year(), local.Month(), local.Day()+1, hour, minute, 0, 0, loc)
+	}
+	return slot
+}
+
+// parseRefreshSchedule validates catalogRefreshTime and catalogRefreshTimezone.
+func parseRefreshSchedule(cfg *model.Config) (hour, minute int, loc *time.Location, err error) {
+	loc, err = time.LoadLocation(cfg.CatalogRefreshTimezone)
+	if err != nil {
+		return 0, 0, nil, fmt.Errorf("invalid timezone %s: %w", cfg.CatalogRefreshTimezone, err)
+	}
+
+	matches := timePattern.FindStringSubmatch(cfg.CatalogRefreshTime)
+	if matches == nil {
+		return 0, 0, nil, fmt.Errorf("invalid refresh time format: %s (expected HH:MM)", cfg.CatalogRefreshTime)
+	}
+
+	if _, err := fmt.Sscanf(matches[1], "%d", &hour); err != nil {
+		return 0, 0, nil, fmt.Errorf("invalid hour in refresh time: %s", matches[1])
+	}
+	if _, err := fmt.Sscanf(matches[2], "%d", &minute); err != nil {
+		return 0, 0, nil, fmt.Errorf("invalid minute in refresh time: %s", matches[2])
+	}
+
+	if hour < 0 || hour > 23 {
+		return 0, 0, nil, fmt.Errorf("hour must be 00-23, got %d", hour)
+	}
+	if minute < 0 || minute > 59 {
+		return 0, 0, nil, fmt.Errorf("minute must be 00-59, got %d", minute)
+	}
+	return hour, minute, loc, nil
+}
+
 // AutoRefreshIfNeeded checks and performs auto-refresh if needed.
 func AutoRefreshIfNeeded(ctx context.Context, cfg *model.Config, deps *Deps) error {
 	should, err := ShouldAutoRefresh(cfg)
@@ -0,0 +1,63 @@
+package catalog
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jmagar/nugs-cli/internal/model"
+)
+
+func TestNextAutoRefresh(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("tzdata unavailable: %v", err)
+	}
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2026, 3, day, hour, minute, 0, 0, ny)
+	}
+	tests := []struct {
+		name        string
+		interval    string
+		lastUpdated time.Time
+		now         time.Time
+		want        time.Time
+	}{
+		{name: "never updated", interval: "daily", now: at(10, 9, 0), want: at(10, 9, 0)},
+		{name: "hourly", interval: "hourly", lastUpdated: at(10, 8, 30), now: at(10, 9, 0), want: at(10, 9, 30)},
+		{name: "daily before slot", interval: "daily", lastUpdated: at(10, 4, 0), now: at(10, 4, 30), want: at(10, 5, 0)},
+		{name: "daily after slot", interval: "daily", lastUpdated: at(10, 5, 10), now: at(10, 9, 0), want: at(11, 5, 0)},
+		{name: "daily missed slot is due", interval: "daily", lastUpdated: at(9, 6, 0), now: at(10, 9, 0), want: at(10, 9, 0)},
+		{name: "weekly", interval: "weekly", lastUpdated: at(10, 6, 0), now: at(10, 9, 0), want: at(18, 5, 0)},
+		{name: "weekly on the slot", interval: "weekly", lastUpdated: at(10, 5, 0), now: at(10, 9, 0), want: at(17, 5, 0)},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := &model.Config{CatalogRefreshTime: "05:00", CatalogRefreshTimezone: "America/New_York", CatalogRefreshInterval: tc.interval}
+			got, err := NextAutoRefresh(cfg, tc.lastUpdated, tc.now)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !got.Equal(tc.want) {
+				t.Fatalf("NextAutoRefresh = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestNextAutoRefreshErrors(t *testing.T) {
+	tests := []struct {
+		cfg  model.Config
+		want string
+	}{
+		{cfg: model.Config{CatalogRefreshTime: "5am", CatalogRefreshInterval: "daily"}, want: "expected HH:MM"},
+		{cfg: model.Config{CatalogRefreshTime: "05:00", CatalogRefreshTimezone: "Mars/Olympus", CatalogRefreshInterval: "daily"}, want: "invalid timezone"},
+		{cfg: model.Config{CatalogRefreshTime: "05:00", CatalogRefreshInterval: "monthly"}, want: "invalid refresh interval"},
+	}
+	for _, tc := range tests {
+		_, err := NextAutoRefresh(&tc.cfg, time.Now().Add(-time.Hour), time.Now())
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("NextAutoRefresh(%+v) error = %v, want %q", tc.cfg, err, tc.want)
+		}
+	}
+}
@@ -4,8 +4,11 @@ package catalog
 
 import (
 	"context"
+	"io"
 	"time"
 
+	"github.com/jmagar/nugs-cli/internal/events"
+	"github.com/jmagar/nugs-cli/internal/history"
 	"github.com/jmagar/nugs-cli/internal/model"
 )
 
@@ -27,6 +30,10 @@ type Deps struct {
 	// Playlist downloads a catalog playlist by GUID.
 	Playlist func(ctx context.Context, plistId, legacyToken string, cfg *model.Config, streamParams *model.StreamParams, cat bool) error
 
+	// Tracks downloads tracks from several shows into one named folder.
+	// Used by song grab.
+	Tracks func(ctx context.Context, name string, tracks []model.ShowTrack, cfg *model.Config, streamParams *model.StreamParams) error
+
 	// SetCurrentProgressBox registers (or clears) the global progress box.
 	SetCurrentProgressBox func(box *model.ProgressBoxState)
 
@@ -49,6 +56,16 @@ type Deps struct {
 
 	// Notify sends a push notification. nil means notifications are disabled.
 	Notify func(ctx context.Context, title, message string, priority int) error
+
+	// History is the download history consulted as a presence source by gaps
+	// and coverage. nil means presence comes from folder names alone.
+	History *history.Store
+
+	// Events receives the gap-fill summary event. nil disables it.
+	Events *events.Bus
+
+	// JSONOutput receives the handlers' --json output. nil means stdout.
+	JSONOutput io.Writer
 }
 
 // GapFillResult summarises the outcome of a CatalogGapsFill call.
@@ -0,0 +1,54 @@
+package catalog
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmagar/nugs-cli/internal/helpers"
+	"github.com/jmagar/nugs-cli/internal/model"
+)
+
+// destinationCoverage counts, for each configured upload destination, how
+// many of the analysed shows have a folder there. It returns nil when the
+// config has no uploadDestinations.
+func destinationCoverage(ctx context.Context, cfg *model.Config, analysis *model.ArtistCatalogAnalysis, mediaFilter model.MediaType, deps *Deps) (map[string]int, error) {
+	if !cfg.RcloneEnabled || len(cfg.UploadDestinations) == 0 || deps.ListRemoteArtistFolders == nil {
+		return nil, nil
+	}
+	artistFolder := helpers.ArtistFolderName(cfg, analysis.ArtistName)
+	depth := helpers.ShowFolderDepth(cfg)
+	targets := []bool{false}
+	switch mediaFilter {
+	case model.MediaTypeVideo:
+		targets = []bool{true}
+	case model.MediaTypeBoth, model.MediaTypeUnknown:
+		targets = []bool{false, true}
+	}
+
+	counts := make(map[string]int, len(cfg.UploadDestinations))
+	for _, dest := range cfg.UploadDestinations {
+		destCfg := helpers.DestinationConfig(cfg, dest)
+		destMedia := helpers.DestinationMedia(dest)
+		folders := make(map[string]struct{})
+		for _, isVideo := range targets {
+			if (isVideo && !destMedia.HasVideo()) || (!isVideo && !destMedia.HasAudio()) {
+				continue
+			}
+			remoteFolders, err := listRemoteShowFolders(ctx, artistFolder, depth, destCfg, isVideo, deps)
+			if err != nil {
+				return counts, fmt.Errorf("destination %s: %w", dest.Name, err)
+			}
+			for name := range remoteFolders {
+				folders[name] = struct{}{}
+			}
+		}
+		present := 0
+		for _, status := range analysis.Shows {
+			if _, ok := folders[helpers.ShowRelativePath(cfg, status.Show)]; ok {
+				present++
+			}
+		}
+		counts[dest.Name] = present
+	}
+	return counts, nil
+}
@@ -0,0 +1,432 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/jmagar/nugs-cli/internal/cache"
+	"github.com/jmagar/nugs-cli/internal/model"
+	"github.com/jmagar/nugs-cli/internal/ui"
+)
+
+const (
+	// fullCrawlWorkers bounds concurrent artist fetches. The API client's
+	// rate limiter is the real throttle; a small pool keeps it saturated.
+	fullCrawlWorkers = 4
+	// fullCrawlCheckpointEvery is how many artists finish between crawl
+	// checkpoints, bounding the work lost to an interruption.
+	fullCrawlCheckpointEvery = 25
+)
+
+// fullCatalogEntry converts one container into a full catalog index entry.
+func fullCatalogEntry(show *model.AlbArtResp, deps *Deps) model.FullCatalogShow {
+	entry := model.FullCatalogShow{
+		ContainerID:                   show.ContainerID,
+		ArtistID:                      show.ArtistID,
+		ArtistName:                    show.ArtistName,
+		ContainerInfo:                 show.ContainerInfo,
+		PerformanceDate:               show.PerformanceDate,
+		PerformanceDateFormatted:      show.PerformanceDateFormatted,
+		PerformanceDateShortYearFirst: show.PerformanceDateShortYearFirst,
+		PerformanceDateYear:           show.PerformanceDateYear,
+		Venue:                         show.Venue,
+		VenueName:                     show.VenueName,
+		VenueCity:                     show.VenueCity,
+		VenueState:                    show.VenueState,
+		AvailabilityTypeStr:           show.AvailabilityTypeStr,
+		ReleaseDateFormatted:          show.ReleaseDateFormatted,
+		Downloadable:                  IsShowDownloadable(show),
+	}
+	if entry.Downloadable {
+		entry.Media = model.MediaTypeAudio
+		if deps.GetShowMediaType != nil {
+			entry.Media = deps.GetShowMediaType(show)
+		}
+	}
+	return entry
+}
+
+// fullCatalogEntries converts an artist's metadata pages into index entries.
+// Containers without an artist ID inherit artistID, which is what they were
+// fetched under.
+func fullCatalogEntries(artistID int, pages []*model.ArtistMeta, deps *Deps) []model.FullCatalogShow {
+	shows, _ := CollectArtistShows(pages)
+	entries := make([]model.FullCatalogShow, 0, len(shows))
+	for _, show := range shows {
+		if show == nil || show.ContainerID == 0 {
+			continue
+		}
+		entry := fullCatalogEntry(show, deps)
+		if entry.ArtistID == 0 {
+			entry.ArtistID = artistID
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// recentItemEntries converts catalog.latest items into index entries. They
+// carry no media information, so they are recorded as not downloadable until
+// an artist fetch replaces them.
+func recentItemEntries(catalog *model.LatestCatalogResp) map[int]model.FullCatalogShow {
+	entries := make(map[int]model.FullCatalogShow, len(catalog.Response.RecentItems))
+	for _, item := range catalog.Response.RecentItems {
+		if item.ContainerID == 0 {
+			continue
+		}
+		entries[item.ContainerID] = model.FullCatalogShow{
+			ContainerID:              item.ContainerID,
+			ArtistID:                 item.ArtistID,
+			ArtistName:               item.ArtistName,
+			ContainerInfo:            item.ContainerInfo,
+			PerformanceDate:          item.PerformanceDateStr,
+			PerformanceDateFormatted: item.ShowDateFormattedShort,
+			Venue:                    item.Venue,
+			VenueCity:                item.VenueCity,
+			VenueState:               item.VenueState,
+		}
+	}
+	return entries
+}
+
+// setFullCatalogTotals recomputes the show and artist counts in meta.
+func setFullCatalogTotals(index *model.FullCatalogIndex, meta *model.FullCatalogMeta) {
+	artists := make(map[int]struct{})
+	for _, entry := range index.Containers {
+		artists[entry.ArtistID] = struct{}{}
+	}
+	meta.TotalShows = len(index.Containers)
+	meta.TotalArtists = len(artists)
+}
+
+// fullCatalogArtistShows returns an artist's downloadable shows from the
+// full catalog index. ok is false when there is no index, the artist is not
+// in it, or the artist's entries are marked stale.
+func fullCatalogArtistShows(artistID string) (shows []model.FullCatalogShow, artistName string, ok bool) {
+	id, err := strconv.Atoi(artistID)
+	if err != nil {
+		return nil, "", false
+	}
+	index, meta, err := cache.ReadFullCatalog()
+	if err != nil || index == nil || slices.Contains(meta.StaleArtists, id) {
+		return nil, "", false
+	}
+	found := false
+	for _, entry := range index.Containers {
+		if entry.ArtistID != id {
+			continue
+		}
+		found = true
+		if artistName == "" {
+			artistName = entry.ArtistName
+		}
+		if entry.Downloadable {
+			shows = append(shows, entry)
+		}
+	}
+	return shows, artistName, found
+}
+
+// fullCatalogArtists returns every indexed artist with NumShows set to its
+// downloadable show count. ok is false when there is no full catalog index.
+func fullCatalogArtists() (artists []model.Artist, ok bool) {
+	index, _, err := cache.ReadFullCatalog()
+	if err != nil || index == nil {
+		return nil, false
+	}
+	byID := make(map[int]int)
+	for _, entry := range index.Containers {
+		if !entry.Downloadable {
+			continue
+		}
+		i, seen := byID[entry.ArtistID]
+		if !seen {
+			i = len(artists)
+			byID[entry.ArtistID] = i
+			artists = append(artists, model.Artist{ArtistID: entry.ArtistID, ArtistName: entry.ArtistName})
+		}
+		artists[i].NumShows++
+	}
+	sort.Slice(artists, func(i, j int) bool { return artists[i].ArtistID < artists[j].ArtistID })
+	return artists, true
+}
+
+type fullCrawlResult struct {
+	artist  model.Artist
+	entries []model.FullCatalogShow
+	err     error
+}
+
+// CatalogFullCrawl fetches every artist's containers and writes the full
+// catalog index. Progress is checkpointed, so an interrupted crawl resumes
+// where it stopped when run again. Artists that fail to fetch are recorded
+// as stale; gap analysis falls back to the API for them.
+func CatalogFullCrawl(ctx context.Context, jsonLevel string, deps *Deps) error {
+	if deps.FetchArtistList == nil || deps.GetArtistMetaCached == nil {
+		return fmt.Errorf("FetchArtistList and GetArtistMetaCached callbacks must be configured")
+	}
+
+	state, err := cache.ReadFullCatalogCrawl()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: discarding unreadable crawl checkpoint: %v\n", err)
+		state = nil
+	}
+	if state == nil {
+		state = &model.FullCatalogCrawl{StartedAt: time.Now(), Containers: map[int]model.FullCatalogShow{}}
+	}
+	resumed := len(state.DoneArtists) > 0
+	// Artists that failed last time are retried.
+	state.FailedArtists = nil
+
+	artistList, err := deps.FetchArtistList(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to fetch artist list: %w", err)
+	}
+	done := make(map[int]struct{}, len(state.DoneArtists))
+	for _, id := range state.DoneArtists {
+		done[id] = struct{}{}
+	}
+	var pending []model.Artist
+	for _, artist := range artistList.Response.Artists {
+		if _, ok := done[artist.ArtistID]; !ok {
+			pending = append(pending, artist)
+		}
+	}
+	total := len(state.DoneArtists) + len(pending)
+	if jsonLevel == "" {
+		if resumed {
+			ui.PrintInfo(fmt.Sprintf("Resuming full catalog crawl: %d of %d artists already done", len(state.DoneArtists), total))
+		} else {
+			ui.PrintInfo(fmt.Sprintf("Crawling the full catalog: %d artists", total))
+		}
+	}
+
+	startTime := time.Now()
+	jobs := make(chan model.Artist)
+	results := make(chan fullCrawlResult)
+	var wg sync.WaitGroup
+	for range min(fullCrawlWorkers, max(len(pending), 1)) {
+		wg.Go(func() {
+			for artist := range jobs {
+				artistID := strconv.Itoa(artist.ArtistID)
+				pages, _, staleUse, fetchErr := deps.GetArtistMetaCached(ctx, artistID, 0)
+				if fetchErr == nil && staleUse {
+					fetchErr = errors.New("fetch failed; only stale cached metadata is available")
+				}
+				result := fullCrawlResult{artist: artist, err: fetchErr}
+				if fetchErr == nil {
+					result.entries = fullCatalogEntries(artist.ArtistID, pages, deps)
+				}
+				results <- result
+			}
+		})
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+	go func() {
+		defer close(jobs)
+		for _, artist := range pending {
+			select {
+			case <-ctx.Done():
+				return
+			case jobs <- artist:
+			}
+		}
+	}()
+
+	// Results are applied on this goroutine only, so state needs no lock.
+	finished := 0
+	for result := range results {
+		finished++
+		switch {
+		case result.err != nil && ctx.Err() != nil:
+			// Interrupted mid-fetch: leave the artist for the resumed crawl.
+			continue
+		case result.err != nil:
+			state.FailedArtists = append(state.FailedArtists, result.artist.ArtistID)
+			if jsonLevel == "" {
+				ui.PrintWarning(fmt.Sprintf("Failed to crawl %s (%d): %v", result.artist.ArtistName, result.artist.ArtistID, result.err))
+			}
+		default:
+			for _, entry := range result.entries {
+				state.Containers[entry.ContainerID] = entry
+			}
+		}
+		state.DoneArtists = append(state.DoneArtists, result.artist.ArtistID)
+		if finished%fullCrawlCheckpointEvery == 0 {
+			if err := cache.WriteFullCatalogCrawl(state); err != nil {
+				fmt.Fprintf(os.Stderr, "warning: failed to checkpoint crawl: %v\n", err)
+			}
+			if jsonLevel == "" {
+				fmt.Printf("  %d/%d artists crawled (%d shows)\n", len(state.DoneArtists), total, len(state.Containers))
+			}
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		if writeErr := cache.WriteFullCatalogCrawl(state); writeErr != nil {
+			fmt.Fprintf(os.Stderr, "warning: failed to checkpoint crawl: %v\n", writeErr)
+		}
+		return fmt.Errorf("full catalog crawl interrupted after %d of %d artists; run 'nugs catalog update full' again to resume: %w",
+			len(state.DoneArtists), total, err)
+	}
+
+	index := &model.FullCatalogIndex{Containers: state.Containers}
+	// Recent containers missing from every artist listing are recorded as
+	// known-but-not-downloadable so plain updates do not refetch their
+	// artists each time.
+	if latest, readErr := cache.ReadCatalogCache(); readErr == nil {
+		for id, entry := range recentItemEntries(latest) {
+			if _, ok := index.Containers[id]; !ok {
+				index.Containers[id] = entry
+			}
+		}
+	}
+	crawlDuration := time.Since(startTime).Round(time.Second).String()
+	if deps.FormatDuration != nil {
+		crawlDuration = deps.FormatDuration(time.Since(startTime))
+	}
+	meta := &model.FullCatalogMeta{
+		FullCrawlAt:   time.Now(),
+		CrawlDuration: crawlDuration,
+		StaleArtists:  slices.Sorted(slices.Values(state.FailedArtists)),
+	}
+	setFullCatalogTotals(index, meta)
+	if err := cache.WriteFullCatalog(index, meta); err != nil {
+		return err
+	}
+	if err := cache.RemoveFullCatalogCrawl(); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to remove crawl checkpoint: %v\n", err)
+	}
+	// The search index takes song titles from the setlist index, so the
+	// setlist index is rebuilt first.
+	if _, err := rebuildSetlistIndex(); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to rebuild setlist index: %v\n", err)
+	}
+	if _, err := rebuildSearchIndex(); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to rebuild search index: %v\n", err)
+	}
+
+	if jsonLevel != "" {
+		return PrintJSON(map[string]any{
+			"success":       true,
+			"resumed":       resumed,
+			"totalArtists":  meta.TotalArtists,
+			"totalShows":    meta.TotalShows,
+			"failedArtists": meta.StaleArtists,
+			"crawlTime":     meta.CrawlDuration,
+		})
+	}
+	fmt.Printf("✓ Full catalog crawl complete\n")
+	fmt.Printf("  Artists: %s%d%s\n", ui.ColorGreen, meta.TotalArtists, ui.ColorReset)
+	fmt.Printf("  Shows: %s%d%s\n", ui.ColorGreen, meta.TotalShows, ui.ColorReset)
+	fmt.Printf("  Crawl time: %s%s%s\n", ui.ColorCyan, meta.CrawlDuration, ui.ColorReset)
+	if len(meta.StaleArtists) > 0 {
+		ui.PrintWarning(fmt.Sprintf("%d artist(s) failed and will be read from the API; run 'nugs catalog update full' to retry", len(meta.StaleArtists)))
+	}
+	return nil
+}
+
+// fullCatalogMerge summarises an incremental merge into the full catalog.
+type fullCatalogMerge struct {
+	Indexed        bool // a full catalog index exists
+	Added          int
+	ArtistsUpdated int
+	ArtistsFailed  int
+}
+
+// mergeIntoFullCatalog folds containers from a catalog update into the full
+// catalog index. Each artist with unknown containers, and each stale artist,
+// is refetched so its entries keep accurate media and availability. It is a
+// no-op before the first full crawl.
+func mergeIntoFullCatalog(ctx context.Context, latest *model.LatestCatalogResp, deps *Deps) (fullCatalogMerge, error) {
+	var merge fullCatalogMerge
+	index, meta, err := cache.ReadFullCatalog()
+	if err != nil || index == nil || deps.GetArtistMetaCached == nil {
+		return merge, err
+	}
+	merge.Indexed = true
+
+	recent := recentItemEntries(latest)
+	unknownByArtist := make(map[int][]model.FullCatalogShow)
+	for id, entry := range recent {
+		if _, ok := index.Containers[id]; !ok {
+			unknownByArtist[entry.ArtistID] = append(unknownByArtist[entry.ArtistID], entry)
+		}
+	}
+	if len(unknownByArtist) == 0 && len(meta.StaleArtists) == 0 {
+		return merge, cache.UpdateFullCatalog(func(_ *model.FullCatalogIndex, meta *model.FullCatalogMeta) error {
+			meta.LastIncrementalAt = time.Now()
+			return nil
+		})
+	}
+
+	// Stale artists are retried alongside those with new containers.
+	artistIDs := slices.Clone(meta.StaleArtists)
+	for id := range unknownByArtist {
+		if !slices.Contains(artistIDs, id) {
+			artistIDs = append(artistIDs, id)
+		}
+	}
+	sort.Ints(artistIDs)
+	refreshed := make(map[int][]model.FullCatalogShow, len(artistIDs))
+	var failed []int
+	for _, artistID := range artistIDs {
+		if err := ctx.Err(); err != nil {
+			return merge, err
+		}
+		pages, _, staleUse, fetchErr := deps.GetArtistMetaCached(ctx, strconv.Itoa(artistID), 0)
+		if fetchErr != nil || staleUse {
+			failed = append(failed, artistID)
+			continue
+		}
+		refreshed[artistID] = fullCatalogEntries(artistID, pages, deps)
+	}
+
+	err = cache.UpdateFullCatalog(func(index *model.FullCatalogIndex, meta *model.FullCatalogMeta) error {
+		for artistID, entries := range refreshed {
+			for id, entry := range index.Containers {
+				if entry.ArtistID == artistID {
+					delete(index.Containers, id)
+				}
+			}
+			for _, entry := range entries {
+				index.Containers[entry.ContainerID] = entry
+			}
+			meta.StaleArtists = slices.DeleteFunc(meta.StaleArtists, func(id int) bool { return id == artistID })
+		}
+		for _, artistID := range artistIDs {
+			// Containers still unknown after a refresh are not in the
+			// artist's downloadable listing; record them so the next update
+			// does not refetch the artist for them.
+			for _, entry := range unknownByArtist[artistID] {
+				if _, ok := index.Containers[entry.ContainerID]; !ok {
+					index.Containers[entry.ContainerID] = entry
+				}
+			}
+		}
+		for _, artistID := range failed {
+			if !slices.Contains(meta.StaleArtists, artistID) {
+				meta.StaleArtists = append(meta.StaleArtists, artistID)
+			}
+		}
+		sort.Ints(meta.StaleArtists)
+		meta.LastIncrementalAt = time.Now()
+		setFullCatalogTotals(index, meta)
+		return nil
+	})
+	for _, entries := range unknownByArtist {
+		merge.Added += len(entries)
+	}
+	merge.ArtistsUpdated = len(refreshed)
+	merge.ArtistsFailed = len(failed)
+	return merge, err
+}
@@ -0,0 +1,162 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jmagar/nugs-cli/internal/cache"
+	"github.com/jmagar/nugs-cli/internal/model"
+	"github.com/jmagar/nugs-cli/internal/testutil"
+)
+
+func artistList(ids ...int) *model.ArtistListResp {
+	resp := &model.ArtistListResp{}
+	for _, id := range ids {
+		resp.Response.Artists = append(resp.Response.Artists, model.Artist{ArtistID: id, ArtistName: "Artist " + strconv.Itoa(id)})
+	}
+	return resp
+}
+
+func TestCatalogFullCrawlResumesAfterInterruption(t *testing.T) {
+	testutil.WithTempHome(t)
+
+	var (
+		mu        sync.Mutex
+		fetched   = map[string]int{}
+		interrupt = true
+	)
+	ctx, cancel := context.WithCancel(context.Background())
+	deps := &Deps{
+		FetchArtistList: func(context.Context) (*model.ArtistListResp, error) { return artistList(1, 2, 3), nil },
+		GetArtistMetaCached: func(ctx context.Context, artistID string, ttl time.Duration) ([]*model.ArtistMeta, bool, bool, error) {
+			if ttl != 0 {
+				t.Errorf("crawl TTL = %s, want 0", ttl)
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			if artistID == "2" && interrupt {
+				interrupt = false
+				cancel()
+				return nil, false, false, ctx.Err()
+			}
+			fetched[artistID]++
+			id, _ := strconv.Atoi(artistID)
+			return []*model.ArtistMeta{artistPageWithShow("Artist "+artistID, id*10, "Show")}, false, false, nil
+		},
+		GetShowMediaType: func(*model.AlbArtResp) model.MediaType { return model.MediaTypeVideo },
+	}
+
+	testutil.CaptureStdout(t, func() {
+		if err := CatalogFullCrawl(ctx, "", deps); !errors.Is(err, context.Canceled) {
+			t.Fatalf("interrupted crawl error = %v, want context.Canceled", err)
+		}
+	})
+	if state, err := cache.ReadFullCatalogCrawl(); err != nil || state == nil || slices.Contains(state.DoneArtists, 2) {
+		t.Fatalf("checkpoint after interruption = %+v, %v", state, err)
+	}
+	if index, _, _ := cache.ReadFullCatalog(); index != nil {
+		t.Fatal("index written by an interrupted crawl")
+	}
+
+	testutil.CaptureStdout(t, func() {
+		if err := CatalogFullCrawl(context.Background(), "", deps); err != nil {
+			t.Fatalf("resumed crawl error = %v", err)
+		}
+	})
+	for _, id := range []string{"1", "2", "3"} {
+		if fetched[id] != 1 {
+			t.Errorf("artist %s fetched %d times, want 1", id, fetched[id])
+		}
+	}
+	index, meta, err := cache.ReadFullCatalog()
+	if err != nil || index == nil {
+		t.Fatalf("ReadFullCatalog() = %v, %v", index, err)
+	}
+	if meta.TotalShows != 3 || meta.TotalArtists != 3 || len(meta.StaleArtists) != 0 {
+		t.Fatalf("meta = %+v", meta)
+	}
+	if entry := index.Containers[20]; entry.ArtistID != 2 || entry.Media != model.MediaTypeVideo || !entry.Downloadable {
+		t.Fatalf("entry 20 = %+v", entry)
+	}
+	if state, _ := cache.ReadFullCatalogCrawl(); state != nil {
+		t.Fatal("checkpoint left behind after a completed crawl")
+	}
+}
+
+func TestCatalogUpdateMergesIntoFullCatalogAndAnalysisStaysLocal(t *testing.T) {
+	testutil.WithTempHome(t)
+
+	index := &model.FullCatalogIndex{Containers: map[int]model.FullCatalogShow{
+		10: {ContainerID: 10, ArtistID: 1, ArtistName: "Artist 1", PerformanceDate: "2024-01-01", Media: model.MediaTypeAudio, Downloadable: true},
+	}}
+	if err := cache.WriteFullCatalog(index, &model.FullCatalogMeta{FullCrawlAt: time.Now()}); err != nil {
+		t.Fatal(err)
+	}
+
+	requests := map[string]int{}
+	deps := makeDeps(func(context.Context) (*model.LatestCatalogResp, error) {
+		return buildUpdateCatalog([]showSpec{
+			{10, 1, "Artist 1", "01/01/24", "Old"},
+			{20, 2, "Artist 2", "02/01/25", "New"},
+			{30, 3, "Artist 3", "03/01/25", "Unreachable"},
+		}), nil
+	})
+	deps.GetShowMediaType = func(*model.AlbArtResp) model.MediaType { return model.MediaTypeAudio }
+	deps.GetArtistMetaCached = func(_ context.Context, artistID string, _ time.Duration) ([]*model.ArtistMeta, bool, bool, error) {
+		requests[artistID]++
+		if artistID == "3" {
+			return nil, false, false, errors.New("unavailable")
+		}
+		page := artistPageWithShow("Artist 2", 20, "New")
+		page.Response.Containers = append(page.Response.Containers, artistPageWithShow("Artist 2", 21, "Older").Response.Containers...)
+		return []*model.ArtistMeta{page}, false, false, nil
+	}
+
+	testutil.CaptureStdout(t, func() {
+		if err := CatalogUpdate(context.Background(), "", deps); err != nil {
+			t.Fatalf("CatalogUpdate() error = %v", err)
+		}
+	})
+	if requests["1"] != 0 || requests["2"] != 1 || requests["3"] != 1 {
+		t.Fatalf("merge requests = %v, want only artists 2 and 3", requests)
+	}
+	merged, meta, err := cache.ReadFullCatalog()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !merged.Containers[21].Downloadable || merged.Containers[30].Downloadable || !slices.Equal(meta.StaleArtists, []int{3}) {
+		t.Fatalf("merged = %+v, stale = %v", merged.Containers, meta.StaleArtists)
+	}
+	if meta.TotalShows != 4 || meta.LastIncrementalAt.IsZero() {
+		t.Fatalf("meta = %+v", meta)
+	}
+
+	cfg := &model.Config{OutPath: t.TempDir(), DefaultOutputs: "audio"}
+	analysis, err := AnalyzeArtistCatalogMediaAware(context.Background(), "2", cfg, "", model.MediaTypeAudio, deps)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if requests["2"] != 1 || analysis.TotalShows != 2 || analysis.Missing != 2 || !analysis.CacheUsed {
+		t.Fatalf("analysis from index: requests %v, analysis %+v", requests, analysis)
+	}
+	// Stale artists fall back to the API.
+	_, _ = AnalyzeArtistCatalogMediaAware(context.Background(), "3", cfg, "", model.MediaTypeAudio, deps)
+	if requests["3"] != 2 {
+		t.Fatalf("stale artist requests = %d, want a fallback fetch", requests["3"])
+	}
+
+	// A second update with nothing new retries only the stale artist.
+	testutil.CaptureStdout(t, func() {
+		if err := CatalogUpdate(context.Background(), "", deps); err != nil {
+			t.Fatalf("second CatalogUpdate() error = %v", err)
+		}
+	})
+	if requests["2"] != 1 || requests["3"] != 3 {
+		t.Fatalf("second update requests = %v, want only a stale retry", requests)
+	}
+}
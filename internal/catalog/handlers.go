@@ -8,7 +8,9 @@ import (
 	"os/signal"
 	"path"
 	"path/filepath"
+	"slices"
 	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -184,6 +186,16 @@ func CatalogUpdate(ctx context.Context, jsonLevel string, deps *Deps) error {
 		}
 	}
 
+	// Fold new containers into the full catalog index, when one exists, so
+	// gaps and stats keep reading local data. Best-effort like the above.
+	merge, mergeErr := mergeIntoFullCatalog(ctx, catalog, deps)
+	if mergeErr != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to update full catalog index: %v\n", mergeErr)
+	}
+	if _, indexErr := rebuildSearchIndex(); indexErr != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to rebuild search index: %v\n", indexErr)
+	}
+
 	cacheDir, _ := cache.GetCacheDir()
 
 	if jsonLevel != "" {
@@ -213,7 +225,14 @@ func CatalogUpdate(ctx context.Context, jsonLevel string, deps *Deps) error {
 			"cacheDir":     cacheDir,
 			"newShowsList": newShowsData,
 		}
-		if err := PrintJSON(output); err != nil {
+		if merge.Indexed {
+			output["fullCatalog"] = map[string]any{
+				"showsMerged":    merge.Added,
+				"artistsUpdated": merge.ArtistsUpdated,
+				"artistsFailed":  merge.ArtistsFailed,
+			}
+		}
+		if err := deps.printJSON(output); err != nil {
 			return err
 		}
 	} else {
@@ -221,6 +240,12 @@ func CatalogUpdate(ctx context.Context, jsonLevel string, deps *Deps) error {
 		fmt.Printf("  Total shows: %s%d%s\n", ui.ColorGreen, len(catalog.Response.RecentItems), ui.ColorReset)
 		fmt.Printf("  Update time: %s%s%s\n", ui.ColorCyan, deps.FormatDuration(updateDuration), ui.ColorReset)
 		fmt.Printf("  Cache location: %s\n", cacheDir)
+		if merge.Indexed {
+			fmt.Printf("  Full catalog: %s%d%s show(s) merged from %d artist(s)\n", ui.ColorGreen, merge.Added, ui.ColorReset, merge.ArtistsUpdated)
+			if merge.ArtistsFailed > 0 {
+				ui.PrintWarning(fmt.Sprintf("%d artist(s) could not be refreshed and will be read from the API", merge.ArtistsFailed))
+			}
+		}
 
 		switch {
 		case isFirstUpdate:
@@ -268,7 +293,7 @@ func CatalogCacheStatus(jsonLevel string, deps *Deps) error {
 	if meta == nil {
 		if jsonLevel != "" {
 			output := map[string]any{"exists": false}
-			if err := PrintJSON(output); err != nil {
+			if err := deps.printJSON(output); err != nil {
 				return err
 			}
 		} else {
@@ -287,6 +312,10 @@ func CatalogCacheStatus(jsonLevel string, deps *Deps) error {
 	ageHuman := deps.FormatDuration(age)
 	fileSizeBytes := fileInfo.Size()
 	fileSizeHuman := fmt.Sprintf("%.1f MB", float64(fileSizeBytes)/(1024*1024))
+	_, fullMeta, fullErr := cache.ReadFullCatalog()
+	if fullErr != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to read full catalog index: %v\n", fullErr)
+	}
 
 	if jsonLevel != "" {
 		output := map[string]any{
@@ -301,7 +330,10 @@ func CatalogCacheStatus(jsonLevel string, deps *Deps) error {
 			"fileSizeHuman": fileSizeHuman,
 			"cacheDir":      cacheDir,
 		}
-		if err := PrintJSON(output); err != nil {
+		if fullMeta != nil {
+			output["fullCatalog"] = fullMeta
+		}
+		if err := deps.printJSON(output); err != nil {
 			return err
 		}
 	} else {
@@ -313,6 +345,15 @@ func CatalogCacheStatus(jsonLevel string, deps *Deps) error {
 		ui.PrintKeyValue("Artists", fmt.Sprintf("%d unique", meta.TotalArtists), ui.ColorCyan)
 		ui.PrintKeyValue("Cache Size", fileSizeHuman, "")
 		ui.PrintKeyValue("Version", meta.CacheVersion, "")
+		if fullMeta != nil {
+			ui.PrintKeyValue("Full Crawl", fmt.Sprintf("%s (%d shows, %d artists)",
+				fullMeta.FullCrawlAt.Format("2006-01-02 15:04:05"), fullMeta.TotalShows, fullMeta.TotalArtists), ui.ColorGreen)
+			if len(fullMeta.StaleArtists) > 0 {
+				ui.PrintKeyValue("Stale Artists", fmt.Sprintf("%d (read from the API)", len(fullMeta.StaleArtists)), ui.ColorYellow)
+			}
+		} else {
+			ui.PrintKeyValue("Full Crawl", "none - run 'nugs catalog update full' for offline gaps/stats", "")
+		}
 
 		if age.Hours() > 24 {
 			fmt.Println()
@@ -339,6 +380,7 @@ func countDownloadedShowsPerArtist(ctx context.Context, cfg *model.Config) (map[
 	}
 
 	artistShows := make(map[string]map[string]struct{})
+	depth := helpers.ShowFolderDepth(cfg)
 	for _, basePath := range basePaths {
 		entries, err := os.ReadDir(basePath)
 		if err != nil {
@@ -349,17 +391,11 @@ func countDownloadedShowsPerArtist(ctx context.Context, cfg *model.Config) (map[
 				continue
 			}
 			artistFolder := entry.Name()
-			subEntries, err := os.ReadDir(filepath.Join(basePath, artistFolder))
-			if err != nil {
-				continue
-			}
 			if _, exists := artistShows[artistFolder]; !exists {
 				artistShows[artistFolder] = make(map[string]struct{})
 			}
-			for _, sub := range subEntries {
-				if sub.IsDir() {
-					artistShows[artistFolder][sub.Name()] = struct{}{}
-				}
+			for _, showFolder := range listLocalShowFolders(filepath.Join(basePath, artistFolder), depth) {
+				artistShows[artistFolder][showFolder] = struct{}{}
 			}
 		}
 	}
@@ -377,19 +413,22 @@ func countDownloadedShowsPerArtist(ctx context.Context, cfg *model.Config) (map[
 }
 
 // CatalogStats shows catalog statistics.
-// It uses catalog.artists (authoritative per-artist show counts) as the
-// denominator and scans the canonical storage target for "what I have".
+// It uses the full catalog index, or catalog.artists (authoritative per-artist
+// show counts) before the first full crawl, as the denominator and scans the
+// canonical storage target for "what I have".
 func CatalogStats(ctx context.Context, cfg *model.Config, jsonLevel string, deps *Deps) error {
-	if deps.FetchArtistList == nil {
-		return fmt.Errorf("FetchArtistList callback not configured")
-	}
-
-	// Fetch authoritative artist list from Nugs — NumShows is the real total.
-	artistListResp, err := deps.FetchArtistList(ctx)
-	if err != nil {
-		return fmt.Errorf("failed to fetch artist list: %w", err)
+	artists, fromIndex := fullCatalogArtists()
+	if !fromIndex {
+		if deps.FetchArtistList == nil {
+			return fmt.Errorf("FetchArtistList callback not configured")
+		}
+		// Fetch authoritative artist list from Nugs — NumShows is the real total.
+		artistListResp, err := deps.FetchArtistList(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to fetch artist list: %w", err)
+		}
+		artists = artistListResp.Response.Artists
 	}
-	artists := artistListResp.Response.Artists
 
 	// Count downloaded shows from the canonical storage target (remote if rclone
 	// enabled, local otherwise). One bulk rclone lsf call when remote.
@@ -411,7 +450,7 @@ func CatalogStats(ctx context.Context, cfg *model.Config, jsonLevel string, deps
 	totalNugsShows := 0
 	for i, a := range artists {
 		stats[i] = artistStat{id: a.ArtistID, name: a.ArtistName, nugsTotal: a.NumShows}
-		sanitizedToIdx[helpers.Sanitise(a.ArtistName)] = i
+		sanitizedToIdx[helpers.ArtistFolderName(cfg, a.ArtistName)] = i
 		totalNugsShows += a.NumShows
 	}
 
@@ -467,7 +506,7 @@ func CatalogStats(ctx context.Context, cfg *model.Config, jsonLevel string, deps
 			"overallPct":      overallPct,
 			"topArtists":      topArtists,
 		}
-		return PrintJSON(output)
+		return deps.printJSON(output)
 	}
 
 	ui.PrintHeader("Catalog Statistics")
@@ -515,7 +554,7 @@ func CatalogStats(ctx context.Context, cfg *model.Config, jsonLevel string, deps
 }
 
 // CatalogLatest shows latest additions to catalog.
-func CatalogLatest(_ context.Context, limit int, jsonLevel string) error {
+func CatalogLatest(_ context.Context, limit int, jsonLevel string, deps *Deps) error {
 	catalog, err := cache.ReadCatalogCache()
 	if err != nil {
 		return err
@@ -545,7 +584,7 @@ func CatalogLatest(_ context.Context, limit int, jsonLevel string) error {
 			"total": len(items),
 			"limit": limit,
 		}
-		if err := PrintJSON(output); err != nil {
+		if err := deps.printJSON(output); err != nil {
 			return err
 		}
 	} else {
@@ -614,7 +653,7 @@ func CatalogGapsForArtist(ctx context.Context, artistId string, cfg *model.Confi
 			"cacheUsed":     analysis.CacheUsed,
 			"cacheStaleUse": analysis.CacheStaleUse,
 		}
-		if err := PrintJSON(output); err != nil {
+		if err := deps.printJSON(output); err != nil {
 			return err
 		}
 	} else {
@@ -673,14 +712,34 @@ func CatalogGaps(ctx context.Context, artistIds []string, cfg *model.Config, jso
 }
 
 // CatalogGapsFill downloads all missing shows for an artist.
-func CatalogGapsFill(ctx context.Context, artistId string, cfg *model.Config, streamParams *model.StreamParams, jsonLevel string, mediaFilter model.MediaType, deps *Deps) (GapFillResult, error) {
+//
+// rule, when set, narrows the selection to the missing shows matching a watch
+// entry: its media type applies when mediaFilter is unset, shows outside its
+// dates, venue or archival rules are skipped, and the run stops after its
+// download cap. An explicit mediaFilter from the command line wins over the
+// rule's media type.
+func CatalogGapsFill(ctx context.Context, artistId string, cfg *model.Config, streamParams *model.StreamParams, jsonLevel string, mediaFilter model.MediaType, rule *model.WatchEntry, deps *Deps) (GapFillResult, error) {
+	if rule != nil && rule.Media != "" && mediaFilter == model.MediaTypeUnknown {
+		mediaFilter = model.ParseMediaType(rule.Media)
+	}
 	analysis, err := AnalyzeArtistCatalog(ctx, artistId, cfg, jsonLevel, mediaFilter, deps)
 	if err != nil {
 		return GapFillResult{}, err
 	}
 	missingShows := analysis.MissingShows
+	skippedByRules := 0
+	if rule != nil {
+		missingShows = slices.DeleteFunc(slices.Clone(missingShows), func(status model.ShowStatus) bool {
+			return !watchRuleMatches(rule, status.Show)
+		})
+		skippedByRules = len(analysis.MissingShows) - len(missingShows)
+	}
 
 	if len(missingShows) == 0 {
+		message := "No missing shows found"
+		if skippedByRules > 0 {
+			message = "No missing shows match the watch rules"
+		}
 		if jsonLevel != "" {
 			output := map[string]any{
 				"success":    true,
@@ -688,12 +747,18 @@ func CatalogGapsFill(ctx context.Context, artistId string, cfg *model.Config, st
 				"artistName": analysis.ArtistName,
 				"totalShows": analysis.TotalShows,
 				"downloaded": 0,
-				"message":    "No missing shows found",
+				"message":    message,
 				"cacheUsed":  analysis.CacheUsed,
 			}
-			if err := PrintJSON(output); err != nil {
+			if rule != nil {
+				output["skippedByRules"] = skippedByRules
+			}
+			if err := deps.printJSON(output); err != nil {
 				return GapFillResult{}, err
 			}
+		} else if skippedByRules > 0 {
+			ui.PrintInfo(fmt.Sprintf("%d missing show(s) for %s%s%s skipped by watch rules (%s)",
+				skippedByRules, ui.ColorCyan, analysis.ArtistName, ui.ColorReset, describeWatchRule(*rule)))
 		} else {
 			ui.PrintSuccess(fmt.Sprintf("All shows already downloaded for %s%s%s", ui.ColorCyan, analysis.ArtistName, ui.ColorReset))
 		}
@@ -703,6 +768,12 @@ func CatalogGapsFill(ctx context.Context, artistId string, cfg *model.Config, st
 	if jsonLevel == "" {
 		ui.PrintHeader(fmt.Sprintf("Filling Gaps: %s", analysis.ArtistName))
 		ui.PrintKeyValue("Total Missing", fmt.Sprintf("%d shows", len(missingShows)), ui.ColorYellow)
+		if rule != nil {
+			ui.PrintKeyValue("Watch Rules", describeWatchRule(*rule), ui.ColorCyan)
+			if skippedByRules > 0 {
+				ui.PrintKeyValue("Skipped by Rules", fmt.Sprintf("%d shows", skippedByRules), ui.ColorYellow)
+			}
+		}
 		fmt.Println()
 	}
 
@@ -710,6 +781,7 @@ func CatalogGapsFill(ctx context.Context, artistId string, cfg *model.Config, st
 	failedCount := 0
 	var failedShows []map[string]any
 	interrupted := false
+	limitReached := false
 
 	batchState := &model.BatchProgressState{
 		TotalAlbums: len(missingShows),
@@ -748,6 +820,10 @@ func CatalogGapsFill(ctx context.Context, artistId string, cfg *model.Config, st
 		if interrupted {
 			break
 		}
+		if rule != nil && rule.MaxDownloads > 0 && successCount >= rule.MaxDownloads {
+			limitReached = true
+			break
+		}
 
 		batchState.CurrentAlbum = i + 1
 		batchState.CurrentTitle = show.ContainerInfo
@@ -796,6 +872,9 @@ func CatalogGapsFill(ctx context.Context, artistId string, cfg *model.Config, st
 		Failed:      failedCount,
 		Interrupted: interrupted,
 	}
+	if deps.Events != nil && ctx.Err() == nil {
+		deps.Events.Publish(ctx, gapFillSummaryEvent(artistId, result, len(missingShows), skippedByRules, failedShows))
+	}
 
 	if jsonLevel != "" {
 		output := map[string]any{
@@ -813,7 +892,11 @@ func CatalogGapsFill(ctx context.Context, artistId string, cfg *model.Config, st
 			"cacheUsed":     analysis.CacheUsed,
 			"cacheStaleUse": analysis.CacheStaleUse,
 		}
-		if err := PrintJSON(output); err != nil {
+		if rule != nil {
+			output["skippedByRules"] = skippedByRules
+			output["limitReached"] = limitReached
+		}
+		if err := deps.printJSON(output); err != nil {
 			return result, err
 		}
 	} else {
@@ -834,6 +917,9 @@ func CatalogGapsFill(ctx context.Context, artistId string, cfg *model.Config, st
 					ui.ColorRed, failed["date"], ui.ColorReset, failed["title"])
 			}
 		}
+		if limitReached {
+			ui.PrintInfo(fmt.Sprintf("Stopped after %d download(s) (watch rule max %d per run)", successCount, rule.MaxDownloads))
+		}
 		if remaining > 0 {
 			ui.PrintKeyValue("Remaining", fmt.Sprintf("%d (re-run to continue)", remaining), ui.ColorYellow)
 		}
@@ -843,6 +929,29 @@ func CatalogGapsFill(ctx context.Context, artistId string, cfg *model.Config, st
 	return result, nil
 }
 
+// gapFillSummaryEvent builds the gapfill.summary event for a finished run.
+// Its error lists each failed show, or notes that the run was interrupted.
+func gapFillSummaryEvent(artistID string, result GapFillResult, missing, skipped int, failedShows []map[string]any) model.Event {
+	id, _ := strconv.Atoi(artistID)
+	var problems []string
+	for _, failed := range failedShows {
+		problems = append(problems, fmt.Sprintf("show %v: %v", failed["containerID"], failed["error"]))
+	}
+	if result.Interrupted {
+		problems = append(problems, "interrupted")
+	}
+	return model.Event{
+		Type:       model.EventGapFillSummary,
+		ArtistID:   id,
+		ArtistName: result.ArtistName,
+		Downloaded: result.Downloaded,
+		Failed:     result.Failed,
+		Missing:    missing,
+		Skipped:    skipped,
+		Error:      strings.Join(problems, "; "),
+	}
+}
+
 // CatalogCoverage shows download coverage statistics for artists.
 func CatalogCoverage(ctx context.Context, artistIds []string, cfg *model.Config, jsonLevel string, mediaFilter model.MediaType, deps *Deps) error {
 	type coverageStats struct {
@@ -851,6 +960,9 @@ func CatalogCoverage(ctx context.Context, artistIds []string, cfg *model.Config,
 		totalShows      int
 		downloadedCount int
 		coveragePct     float64
+		// destinations holds per-destination show counts when the config
+		// lists uploadDestinations.
+		destinations map[string]int
 	}
 
 	var allStats []coverageStats
@@ -903,7 +1015,7 @@ func CatalogCoverage(ctx context.Context, artistIds []string, cfg *model.Config,
 				if remoteScanErr != nil {
 					output["remoteScanError"] = remoteScanErr.Error()
 				}
-				if err := PrintJSON(output); err != nil {
+				if err := deps.printJSON(output); err != nil {
 					return err
 				}
 			} else {
@@ -923,7 +1035,7 @@ func CatalogCoverage(ctx context.Context, artistIds []string, cfg *model.Config,
 		artistMapping := make(map[string]string)
 		artistMappingNormalized := make(map[string]string)
 		for _, item := range catalog.Response.RecentItems {
-			normalizedName := helpers.Sanitise(item.ArtistName)
+			normalizedName := helpers.ArtistFolderName(cfg, item.ArtistName)
 			artistID := fmt.Sprintf("%d", item.ArtistID)
 			artistMapping[normalizedName] = artistID
 			artistMappingNormalized[NormalizeArtistFolderKey(normalizedName)] = artistID
@@ -959,7 +1071,7 @@ func CatalogCoverage(ctx context.Context, artistIds []string, cfg *model.Config,
 					"total":   0,
 					"message": "No downloaded artists found",
 				}
-				if err := PrintJSON(output); err != nil {
+				if err := deps.printJSON(output); err != nil {
 					return err
 				}
 			} else {
@@ -970,8 +1082,9 @@ func CatalogCoverage(ctx context.Context, artistIds []string, cfg *model.Config,
 	}
 
 	type coverageResult struct {
-		stats coverageStats
-		err   error
+		stats   coverageStats
+		err     error
+		destErr error
 	}
 	jobs := make(chan string)
 	results := make(chan coverageResult, len(artistIds))
@@ -988,6 +1101,10 @@ func CatalogCoverage(ctx context.Context, artistIds []string, cfg *model.Config,
 					results <- coverageResult{err: fmt.Errorf("artist %s: %w", artistID, err)}
 					continue
 				}
+				destinations, destErr := destinationCoverage(ctx, cfg, analysis, mediaFilter, deps)
+				if destErr != nil {
+					destErr = fmt.Errorf("artist %s: %w", artistID, destErr)
+				}
 				results <- coverageResult{
 					stats: coverageStats{
 						artistID:        artistID,
@@ -995,7 +1112,9 @@ func CatalogCoverage(ctx context.Context, artistIds []string, cfg *model.Config,
 						totalShows:      analysis.TotalShows,
 						downloadedCount: analysis.Downloaded,
 						coveragePct:     analysis.DownloadPct,
+						destinations:    destinations,
 					},
+					destErr: destErr,
 				}
 			}
 		}()
@@ -1023,6 +1142,12 @@ func CatalogCoverage(ctx context.Context, artistIds []string, cfg *model.Config,
 			}
 			continue
 		}
+		if result.destErr != nil {
+			failures = append(failures, result.destErr.Error())
+			if jsonLevel == "" {
+				ui.PrintWarning(fmt.Sprintf("Destination check failed for %v", result.destErr))
+			}
+		}
 		allStats = append(allStats, result.stats)
 	}
 
@@ -1032,9 +1157,16 @@ func CatalogCoverage(ctx context.Context, artistIds []string, cfg *model.Config,
 
 	totalShows := 0
 	totalDownloaded := 0
+	var destinationTotals map[string]int
+	if cfg.RcloneEnabled && len(cfg.UploadDestinations) > 0 {
+		destinationTotals = make(map[string]int, len(cfg.UploadDestinations))
+	}
 	for _, stats := range allStats {
 		totalShows += stats.totalShows
 		totalDownloaded += stats.downloadedCount
+		for name, count := range stats.destinations {
+			destinationTotals[name] += count
+		}
 	}
 	totalCoveragePct := 0.0
 	if totalShows > 0 {
@@ -1052,16 +1184,23 @@ func CatalogCoverage(ctx context.Context, artistIds []string, cfg *model.Config,
 				"missing":    stats.totalShows - stats.downloadedCount,
 				"coverage":   stats.coveragePct,
 			}
+			if destinationTotals != nil {
+				artistsData[i]["destinations"] = stats.destinations
+			}
+		}
+		summary := map[string]any{
+			"downloaded": totalDownloaded,
+			"totalShows": totalShows,
+			"missing":    totalShows - totalDownloaded,
+			"coverage":   totalCoveragePct,
+		}
+		if destinationTotals != nil {
+			summary["destinations"] = destinationTotals
 		}
 		output := map[string]any{
-			"artists": artistsData,
-			"total":   len(allStats),
-			"summary": map[string]any{
-				"downloaded": totalDownloaded,
-				"totalShows": totalShows,
-				"missing":    totalShows - totalDownloaded,
-				"coverage":   totalCoveragePct,
-			},
+			"artists":         artistsData,
+			"total":           len(allStats),
+			"summary":         summary,
 			"remoteScanError": nil,
 			"partial":         remoteScanErr != nil || len(failures) > 0,
 			"failures":        failures,
@@ -1069,22 +1208,29 @@ func CatalogCoverage(ctx context.Context, artistIds []string, cfg *model.Config,
 		if remoteScanErr != nil {
 			output["remoteScanError"] = remoteScanErr.Error()
 		}
-		if err := PrintJSON(output); err != nil {
+		if err := deps.printJSON(output); err != nil {
 			return err
 		}
 	} else {
 		ui.PrintHeader("Download Coverage Statistics")
 		ui.PrintKeyValue("Artists", fmt.Sprintf("%d", len(allStats)), ui.ColorCyan)
 		ui.PrintKeyValue("Overall", fmt.Sprintf("%d/%d (%.1f%%)", totalDownloaded, totalShows, totalCoveragePct), ui.ColorGreen)
-		fmt.Println()
-
-		table := ui.NewTable([]ui.TableColumn{
+		columns := []ui.TableColumn{
 			{Header: "Artist ID", Width: 12, Align: "right"},
 			{Header: "Artist Name", Width: 40, Align: "left"},
 			{Header: "Downloaded", Width: 12, Align: "right"},
 			{Header: "Total", Width: 10, Align: "right"},
 			{Header: "Coverage", Width: 12, Align: "right"},
-		})
+		}
+		if destinationTotals != nil {
+			for _, dest := range cfg.UploadDestinations {
+				ui.PrintKeyValue("On "+dest.Name, fmt.Sprintf("%d/%d", destinationTotals[dest.Name], totalShows), ui.ColorCyan)
+				columns = append(columns, ui.TableColumn{Header: dest.Name, Width: max(len(dest.Name), 8), Align: "right"})
+			}
+		}
+		fmt.Println()
+
+		table := ui.NewTable(columns)
 
 		for _, stats := range allStats {
 			coverageColor := ui.ColorGreen
@@ -1095,13 +1241,19 @@ func CatalogCoverage(ctx context.Context, artistIds []string, cfg *model.Config,
 				coverageColor = ui.ColorRed
 			}
 
-			table.AddRow(
+			row := []string{
 				stats.artistID,
 				stats.artistName,
 				fmt.Sprintf("%d", stats.downloadedCount),
 				fmt.Sprintf("%d", stats.totalShows),
 				fmt.Sprintf("%s%.1f%%%s", coverageColor, stats.coveragePct, ui.ColorReset),
-			)
+			}
+			if destinationTotals != nil {
+				for _, dest := range cfg.UploadDestinations {
+					row = append(row, fmt.Sprintf("%d", stats.destinations[dest.Name]))
+				}
+			}
+			table.AddRow(row...)
 		}
 
 		table.Print()
@@ -1185,7 +1337,7 @@ func CatalogListForArtist(ctx context.Context, artistId string, cfg *model.Confi
 			"cacheUsed":     analysis.CacheUsed,
 			"cacheStaleUse": analysis.CacheStaleUse,
 		}
-		if err := PrintJSON(output); err != nil {
+		if err := deps.printJSON(output); err != nil {
 			return err
 		}
 	} else {
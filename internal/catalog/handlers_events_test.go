@@ -0,0 +1,75 @@
+package catalog
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jmagar/nugs-cli/internal/cache"
+	"github.com/jmagar/nugs-cli/internal/events"
+	"github.com/jmagar/nugs-cli/internal/model"
+	"github.com/jmagar/nugs-cli/internal/testutil"
+)
+
+func TestCatalogGapsFillPublishesSummary(t *testing.T) {
+	testutil.WithTempHome(t)
+	index := &model.FullCatalogIndex{Containers: map[int]model.FullCatalogShow{}}
+	for _, show := range []model.FullCatalogShow{
+		{ContainerID: 1, PerformanceDate: "2024-08-01", VenueName: "Red Rocks Amphitheatre"},
+		{ContainerID: 2, PerformanceDate: "2024-07-01", VenueName: "The Capitol Theatre"},
+	} {
+		show.ArtistID, show.ArtistName = 1125, "Billy Strings"
+		show.ContainerInfo = show.PerformanceDate + " " + show.VenueName
+		show.Media, show.Downloadable = model.MediaTypeAudio, true
+		index.Containers[show.ContainerID] = show
+	}
+	if err := cache.WriteFullCatalog(index, &model.FullCatalogMeta{FullCrawlAt: time.Now()}); err != nil {
+		t.Fatal(err)
+	}
+	logPath := filepath.Join(t.TempDir(), "events.jsonl")
+	bus, err := events.New(&model.Config{EventLog: logPath})
+	if err != nil {
+		t.Fatal(err)
+	}
+	deps := &Deps{
+		Events: bus,
+		GetArtistMetaCached: func(context.Context, string, time.Duration) ([]*model.ArtistMeta, bool, bool, error) {
+			t.Fatal("artist metadata fetched despite full catalog index")
+			return nil, false, false, nil
+		},
+		Album: func(_ context.Context, albumID string, _ *model.Config, _ *model.StreamParams, _ *model.AlbArtResp, _ *model.BatchProgressState, _ *model.ProgressBoxState) error {
+			if albumID == "2" {
+				return errors.New("no tracks")
+			}
+			return nil
+		},
+	}
+	cfg := &model.Config{OutPath: t.TempDir(), DefaultOutputs: "audio"}
+
+	testutil.CaptureStdout(t, func() {
+		if _, err := CatalogGapsFill(context.Background(), "1125", cfg, &model.StreamParams{}, "standard", model.MediaTypeUnknown, nil, deps); err != nil {
+			t.Fatal(err)
+		}
+	})
+	bus.Close()
+
+	data, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var e model.Event
+	if err := json.Unmarshal(data, &e); err != nil {
+		t.Fatalf("event log %q: %v", data, err)
+	}
+	if e.Type != model.EventGapFillSummary || e.ArtistID != 1125 || e.ArtistName != "Billy Strings" {
+		t.Errorf("event = %+v, want gapfill.summary for artist 1125", e)
+	}
+	if e.Downloaded != 1 || e.Failed != 1 || e.Missing != 2 || !strings.Contains(e.Error, "show 2: no tracks") {
+		t.Errorf("event = %+v, want 1 downloaded, 1 failed (show 2) of 2 missing", e)
+	}
+}
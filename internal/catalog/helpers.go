@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"maps"
 	"os"
 	"os/exec"
 	"path"
@@ -14,8 +16,10 @@ import (
 	"unicode"
 
 	"github.com/jmagar/nugs-cli/internal/helpers"
+	"github.com/jmagar/nugs-cli/internal/history"
 	"github.com/jmagar/nugs-cli/internal/model"
 	"github.com/jmagar/nugs-cli/internal/rclone"
+	"github.com/jmagar/nugs-cli/internal/storage"
 	"github.com/jmagar/nugs-cli/internal/ui"
 )
 
@@ -25,6 +29,9 @@ type ArtistPresenceIndex struct {
 	LocalFolders  map[string]struct{}
 	RemoteFolders map[string]struct{}
 	RemoteListErr error
+	// Recorded holds container IDs found in the download history. They count
+	// as present whatever their folders are now called.
+	Recorded map[int]struct{}
 }
 
 var (
@@ -53,12 +60,59 @@ func WarnRemoteCheckError(err error) {
 	}
 }
 
-// ListAllRemoteArtistFolders lists all artist folders on the remote.
+// destinationScanConfigs returns one config per upload destination for
+// whole-remote scans. A destination limited to one media type scans only that
+// media's base path.
+func destinationScanConfigs(cfg *model.Config) []*model.Config {
+	if len(cfg.UploadDestinations) == 0 {
+		return []*model.Config{cfg}
+	}
+	configs := make([]*model.Config, 0, len(cfg.UploadDestinations))
+	for _, dest := range cfg.UploadDestinations {
+		destCfg := helpers.DestinationConfig(cfg, dest)
+		switch helpers.DestinationMedia(dest) {
+		case model.MediaTypeAudio:
+			destCfg.RcloneVideoPath = ""
+		case model.MediaTypeVideo:
+			destCfg.RclonePath = helpers.GetRcloneBasePath(destCfg, true)
+		}
+		configs = append(configs, destCfg)
+	}
+	return configs
+}
+
+// ListAllRemoteArtistFolders lists all artist folders on the remote, across
+// every upload destination.
 func ListAllRemoteArtistFolders(ctx context.Context, cfg *model.Config) (map[string]struct{}, error) {
 	folders := make(map[string]struct{})
 	if !cfg.RcloneEnabled {
 		return folders, nil
 	}
+	for _, destCfg := range destinationScanConfigs(cfg) {
+		destFolders, err := listRemoteArtistFolderNames(ctx, destCfg)
+		if err != nil {
+			return nil, err
+		}
+		maps.Copy(folders, destFolders)
+	}
+	return folders, nil
+}
+
+// listRemoteArtistFolderNames lists the artist folders under one
+// destination's audio path.
+func listRemoteArtistFolderNames(ctx context.Context, cfg *model.Config) (map[string]struct{}, error) {
+	folders := make(map[string]struct{})
+	if helpers.StorageBackend(cfg) != model.StorageBackendRclone {
+		provider, err := storage.ForConfig(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrRemoteArtistFolderListFailed, err)
+		}
+		folders, err = provider.ListArtistFolders(ctx, cfg, "", false)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrRemoteArtistFolderListFailed, err)
+		}
+		return folders, nil
+	}
 
 	remoteDest := cfg.RcloneRemote + ":" + cfg.RclonePath
 	cmd := exec.CommandContext(ctx, "rclone", "lsf", remoteDest, "--dirs-only")
@@ -107,9 +161,9 @@ func ShowExists(ctx context.Context, show *model.AlbArtResp, cfg *model.Config,
 	resolver := helpers.NewConfigPathResolver(cfg)
 	albumPath := resolver.LocalShowPath(show, mediaType)
 
-	// Check local existence
+	// Check local existence; a --tracks selection is not the show
 	_, err := os.Stat(albumPath)
-	if err == nil {
+	if err == nil && !helpers.IsPartialShow(albumPath) {
 		return true
 	}
 
@@ -149,10 +203,11 @@ func CollectArtistShows(artistMetas []*model.ArtistMeta) (allShows []*model.AlbA
 //   - Both/Unknown: checks both OutPath and VideoOutPath (if different)
 func BuildArtistPresenceIndex(ctx context.Context, artistName string, cfg *model.Config, deps *Deps, mediaFilter model.MediaType) ArtistPresenceIndex {
 	idx := ArtistPresenceIndex{
-		ArtistFolder:  helpers.Sanitise(artistName),
+		ArtistFolder:  helpers.ArtistFolderName(cfg, artistName),
 		LocalFolders:  make(map[string]struct{}),
 		RemoteFolders: make(map[string]struct{}),
 	}
+	depth := helpers.ShowFolderDepth(cfg)
 
 	resolver := helpers.NewConfigPathResolver(cfg)
 	pathsToCheck := resolver.LocalBasesForFilter(mediaFilter)
@@ -162,14 +217,17 @@ func BuildArtistPresenceIndex(ctx context.Context, artistName string, cfg *model
 
 	// Build combined index from all relevant paths
 	for _, basePath := range pathsToCheck {
-		localArtistPath := filepath.Join(basePath, idx.ArtistFolder)
-		if entries, err := os.ReadDir(localArtistPath); err == nil {
-			for _, entry := range entries {
-				if !entry.IsDir() {
-					continue
-				}
-				idx.LocalFolders[entry.Name()] = struct{}{}
-			}
+		for _, showPath := range listLocalShowFolders(filepath.Join(basePath, idx.ArtistFolder), depth) {
+			idx.LocalFolders[showPath] = struct{}{}
+		}
+	}
+
+	if deps.History != nil && !cfg.SkipHistory {
+		recorded, err := deps.History.Containers(historyMedia(mediaFilter))
+		if err != nil {
+			ui.PrintWarning(fmt.Sprintf("Download history unavailable, using folder names only: %v", err))
+		} else {
+			idx.Recorded = recorded
 		}
 	}
 
@@ -182,7 +240,7 @@ func BuildArtistPresenceIndex(ctx context.Context, artistName string, cfg *model
 		}
 
 		for _, isVideo := range remoteTargets {
-			remoteFolders, err := deps.ListRemoteArtistFolders(ctx, idx.ArtistFolder, cfg, isVideo)
+			remoteFolders, err := listRemoteShowFolders(ctx, idx.ArtistFolder, depth, cfg, isVideo, deps)
 			if err != nil {
 				mediaType := "audio"
 				if isVideo {
@@ -202,9 +260,72 @@ func BuildArtistPresenceIndex(ctx context.Context, artistName string, cfg *model
 	return idx
 }
 
+// historyMedia maps a media filter to the history media kind it accepts;
+// both and unknown accept any recorded download.
+func historyMedia(mediaFilter model.MediaType) string {
+	switch mediaFilter {
+	case model.MediaTypeAudio:
+		return history.MediaAudio
+	case model.MediaTypeVideo:
+		return history.MediaVideo
+	default:
+		return ""
+	}
+}
+
+// listLocalShowFolders returns the directories exactly depth levels below
+// artistPath as slash-separated relative paths, leaving out folders that hold
+// only a --tracks selection.
+func listLocalShowFolders(artistPath string, depth int) []string {
+	entries, err := os.ReadDir(artistPath)
+	if err != nil {
+		return nil
+	}
+	var folders []string
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		if depth <= 1 {
+			if !helpers.IsPartialShow(filepath.Join(artistPath, entry.Name())) {
+				folders = append(folders, entry.Name())
+			}
+			continue
+		}
+		for _, sub := range listLocalShowFolders(filepath.Join(artistPath, entry.Name()), depth-1) {
+			folders = append(folders, path.Join(entry.Name(), sub))
+		}
+	}
+	return folders
+}
+
+// listRemoteShowFolders lists remote show folders depth levels below the
+// artist folder, walking intermediate levels (e.g. year folders) one listing
+// at a time.
+func listRemoteShowFolders(ctx context.Context, artistFolder string, depth int, cfg *model.Config, isVideo bool, deps *Deps) (map[string]struct{}, error) {
+	folders, err := deps.ListRemoteArtistFolders(ctx, artistFolder, cfg, isVideo)
+	if err != nil || depth <= 1 {
+		return folders, err
+	}
+	nested := make(map[string]struct{})
+	for name := range folders {
+		children, err := listRemoteShowFolders(ctx, path.Join(artistFolder, name), depth-1, cfg, isVideo, deps)
+		if err != nil {
+			return nil, err
+		}
+		for child := range children {
+			nested[path.Join(name, child)] = struct{}{}
+		}
+	}
+	return nested, nil
+}
+
 // IsShowDownloaded checks if a show is downloaded using the pre-built index.
 func IsShowDownloaded(ctx context.Context, show *model.AlbArtResp, idx ArtistPresenceIndex, cfg *model.Config, deps *Deps) bool {
-	albumFolder := helpers.BuildAlbumFolderName(show.ArtistName, show.ContainerInfo)
+	if _, ok := idx.Recorded[show.ContainerID]; ok {
+		return true
+	}
+	albumFolder := helpers.ShowRelativePath(cfg, show)
 
 	if _, ok := idx.LocalFolders[albumFolder]; ok {
 		return true
@@ -229,9 +350,39 @@ func IsShowDownloaded(ctx context.Context, show *model.AlbArtResp, idx ArtistPre
 
 // CountRemoteShowsPerArtist counts downloaded show subdirectories per artist
 // using a single bulk `rclone lsf --recursive --dirs-only` call per remote
-// path. Audio and video paths are deduplicated so a show present in both is
-// counted once. Returns (artistFolder→count, total, error).
+// path, or a level-by-level listing for native storage backends. Audio and
+// video paths, and upload destinations, are deduplicated so a show present in
+// several is counted once.
+// Returns (artistFolder→count, total, error).
 func CountRemoteShowsPerArtist(ctx context.Context, cfg *model.Config) (map[string]int, int, error) {
+	// artistShows[artistFolder][showFolder] — deduplicates across paths.
+	artistShows := make(map[string]map[string]struct{})
+	depth := helpers.ShowFolderDepth(cfg)
+	for _, destCfg := range destinationScanConfigs(cfg) {
+		collect := collectRcloneShows
+		if helpers.StorageBackend(destCfg) != model.StorageBackendRclone {
+			collect = collectStorageShows
+		}
+		if err := collect(ctx, destCfg, depth, artistShows); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	counts := make(map[string]int, len(artistShows))
+	total := 0
+	for artistFolder, shows := range artistShows {
+		count := len(shows)
+		if count > 0 {
+			counts[artistFolder] = count
+			total += count
+		}
+	}
+	return counts, total, nil
+}
+
+// collectRcloneShows adds the show folders under each rclone remote path to
+// artistShows.
+func collectRcloneShows(ctx context.Context, cfg *model.Config, depth int, artistShows map[string]map[string]struct{}) error {
 	// Collect unique remote destinations to scan.
 	audioDest := cfg.RcloneRemote + ":" + cfg.RclonePath
 	remoteDests := []string{audioDest}
@@ -242,9 +393,6 @@ func CountRemoteShowsPerArtist(ctx context.Context, cfg *model.Config) (map[stri
 		}
 	}
 
-	// artistShows[artistFolder][showFolder] — deduplicates across paths.
-	artistShows := make(map[string]map[string]struct{})
-
 	for _, dest := range remoteDests {
 		cmd := exec.CommandContext(ctx, "rclone", "lsf", dest, "--dirs-only", "--recursive")
 		var output []byte
@@ -258,7 +406,7 @@ func CountRemoteShowsPerArtist(ctx context.Context, cfg *model.Config) (map[stri
 			if errors.As(err, &exitErr) && exitErr.ExitCode() == 3 {
 				continue // remote path doesn't exist yet — not an error
 			}
-			return nil, 0, fmt.Errorf("rclone lsf %s: %w", dest, err)
+			return fmt.Errorf("rclone lsf %s: %w", dest, err)
 		}
 
 		for _, line := range strings.Split(string(output), "\n") {
@@ -266,12 +414,12 @@ func CountRemoteShowsPerArtist(ctx context.Context, cfg *model.Config) (map[stri
 			if line == "" {
 				continue
 			}
-			// Entries look like "Artist Name/Show Folder/" — split into exactly
-			// two parts (artist + show) to stay at depth 1 within the remote base.
-			parts := strings.SplitN(strings.TrimSuffix(line, "/"), "/", 3)
-			if len(parts) == 2 {
+			// Entries look like "Artist Name/Show Folder/" — keep only entries
+			// exactly at show depth (deeper for templates like Artist/Year/Show).
+			parts := strings.SplitN(strings.TrimSuffix(line, "/"), "/", depth+2)
+			if len(parts) == depth+1 {
 				artistFolder := parts[0]
-				showFolder := parts[1]
+				showFolder := path.Join(parts[1:]...)
 				if _, exists := artistShows[artistFolder]; !exists {
 					artistShows[artistFolder] = make(map[string]struct{})
 				}
@@ -279,25 +427,83 @@ func CountRemoteShowsPerArtist(ctx context.Context, cfg *model.Config) (map[stri
 			}
 		}
 	}
+	return nil
+}
 
-	counts := make(map[string]int, len(artistShows))
-	total := 0
-	for artistFolder, shows := range artistShows {
-		count := len(shows)
-		if count > 0 {
-			counts[artistFolder] = count
-			total += count
+// collectStorageShows adds the show folders found through the configured
+// native storage provider to artistShows.
+func collectStorageShows(ctx context.Context, cfg *model.Config, depth int, artistShows map[string]map[string]struct{}) error {
+	provider, err := storage.ForConfig(cfg)
+	if err != nil {
+		return err
+	}
+	bases := []bool{false}
+	if cfg.RcloneVideoPath != "" && cfg.RcloneVideoPath != cfg.RclonePath {
+		bases = append(bases, true)
+	}
+	for _, isVideo := range bases {
+		artists, err := provider.ListArtistFolders(ctx, cfg, "", isVideo)
+		if err != nil {
+			return err
+		}
+		for artistFolder := range artists {
+			shows, err := listShowFolders(ctx, provider, cfg, artistFolder, depth, isVideo)
+			if err != nil {
+				return err
+			}
+			for _, showFolder := range shows {
+				if _, exists := artistShows[artistFolder]; !exists {
+					artistShows[artistFolder] = make(map[string]struct{})
+				}
+				artistShows[artistFolder][showFolder] = struct{}{}
+			}
 		}
 	}
-	return counts, total, nil
+	return nil
+}
+
+// listShowFolders returns the folders depth levels below dir, relative to
+// dir, e.g. "2023/Show" for a depth of 2.
+func listShowFolders(ctx context.Context, provider model.StorageProvider, cfg *model.Config, dir string, depth int, isVideo bool) ([]string, error) {
+	children, err := provider.ListArtistFolders(ctx, cfg, dir, isVideo)
+	if err != nil {
+		return nil, err
+	}
+	var folders []string
+	for child := range children {
+		if depth <= 1 {
+			folders = append(folders, child)
+			continue
+		}
+		nested, err := listShowFolders(ctx, provider, cfg, path.Join(dir, child), depth-1, isVideo)
+		if err != nil {
+			return nil, err
+		}
+		for _, folder := range nested {
+			folders = append(folders, path.Join(child, folder))
+		}
+	}
+	return folders, nil
 }
 
 // PrintJSON marshals data to JSON and prints it.
 func PrintJSON(data any) error {
+	return writeJSON(os.Stdout, data)
+}
+
+// printJSON prints data like PrintJSON, to d.JSONOutput when it is set.
+func (d *Deps) printJSON(data any) error {
+	if d == nil || d.JSONOutput == nil {
+		return PrintJSON(data)
+	}
+	return writeJSON(d.JSONOutput, data)
+}
+
+func writeJSON(w io.Writer, data any) error {
 	jsonData, err := json.MarshalIndent(data, "", "  ")
 	if err != nil {
 		return fmt.Errorf("failed to marshal JSON: %w", err)
 	}
-	fmt.Println(string(jsonData))
-	return nil
+	_, err = fmt.Fprintln(w, string(jsonData))
+	return err
 }
@@ -0,0 +1,48 @@
+package catalog
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jmagar/nugs-cli/internal/history"
+	"github.com/jmagar/nugs-cli/internal/model"
+)
+
+func TestAnalyzeArtistTreatsRecordedShowAsPresentAfterRename(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	store := history.Open(t.TempDir())
+	if err := store.Record(history.Entry{ContainerID: 10, ArtistID: 1, Media: history.MediaAudio, LocalPath: "/music/renamed"}); err != nil {
+		t.Fatal(err)
+	}
+	deps := &Deps{
+		GetArtistMetaCached: func(context.Context, string, time.Duration) ([]*model.ArtistMeta, bool, bool, error) {
+			return []*model.ArtistMeta{artistPageWithShow("Artist", 10, "Show")}, false, false, nil
+		},
+		GetShowMediaType: func(*model.AlbArtResp) model.MediaType { return model.MediaTypeBoth },
+		History:          store,
+	}
+
+	tests := []struct {
+		name           string
+		cfg            *model.Config
+		filter         model.MediaType
+		wantDownloaded int
+	}{
+		{"audio record satisfies audio gaps", &model.Config{}, model.MediaTypeAudio, 1},
+		{"audio record does not satisfy video gaps", &model.Config{}, model.MediaTypeVideo, 0},
+		{"skipHistory falls back to folders", &model.Config{SkipHistory: true}, model.MediaTypeAudio, 0},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.cfg.OutPath = t.TempDir()
+			analysis, err := AnalyzeArtistCatalogMediaAware(context.Background(), "1", tc.cfg, "", tc.filter, deps)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if analysis.Downloaded != tc.wantDownloaded {
+				t.Fatalf("Downloaded = %d, want %d", analysis.Downloaded, tc.wantDownloaded)
+			}
+		})
+	}
+}
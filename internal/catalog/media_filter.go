@@ -28,7 +28,7 @@ func ShowExistsForMedia(ctx context.Context, show *model.AlbArtResp, cfg *model.
 
 	// Check local path (using media-specific base path)
 	localPath := resolver.LocalShowPath(show, mediaType)
-	if _, err := os.Stat(localPath); err == nil {
+	if _, err := os.Stat(localPath); err == nil && !helpers.IsPartialShow(localPath) {
 		return true
 	}
 
@@ -50,7 +50,13 @@ func ShowExistsForMedia(ctx context.Context, show *model.AlbArtResp, cfg *model.
 
 // ShowExistsForMediaIndexed checks if a show exists using pre-built folder index (fast).
 func ShowExistsForMediaIndexed(ctx context.Context, show *model.AlbArtResp, cfg *model.Config, mediaType model.MediaType, idx *ArtistPresenceIndex, deps *Deps) bool {
-	albumFolder := helpers.BuildAlbumFolderName(show.ArtistName, show.ContainerInfo)
+	// Fast path: the download history knows the container regardless of
+	// what its folder is called now.
+	if _, recorded := idx.Recorded[show.ContainerID]; recorded {
+		return true
+	}
+
+	albumFolder := helpers.ShowRelativePath(cfg, show)
 	resolver := helpers.NewConfigPathResolver(cfg)
 
 	// Fast path: check local index
@@ -144,12 +150,7 @@ func IsShowDownloadable(show *model.AlbArtResp) bool {
 // classifyShows iterates all shows, applies media filtering, and populates the analysis
 // with show statuses and download counts.
 func classifyShows(ctx context.Context, allShows []*model.AlbArtResp, mediaFilter model.MediaType, presenceIdx *ArtistPresenceIndex, cfg *model.Config, deps *Deps, analysis *model.ArtistCatalogAnalysis) {
-	type candidate struct {
-		show      *model.AlbArtResp
-		showMedia model.MediaType
-	}
-
-	candidates := make([]candidate, 0, len(allShows))
+	candidates := make([]showCandidate, 0, len(allShows))
 	for _, show := range allShows {
 		if !IsShowDownloadable(show) {
 			continue
@@ -164,9 +165,20 @@ func classifyShows(ctx context.Context, allShows []*model.AlbArtResp, mediaFilte
 		if !MatchesMediaFilter(showMedia, mediaFilter) {
 			continue
 		}
-		candidates = append(candidates, candidate{show: show, showMedia: showMedia})
+		candidates = append(candidates, showCandidate{show: show, showMedia: showMedia})
 	}
+	classifyCandidates(ctx, candidates, mediaFilter, presenceIdx, cfg, deps, analysis)
+}
 
+// showCandidate is a downloadable show that matches the media filter.
+type showCandidate struct {
+	show      *model.AlbArtResp
+	showMedia model.MediaType
+}
+
+// classifyCandidates checks presence for each candidate and populates the
+// analysis with show statuses and download counts.
+func classifyCandidates(ctx context.Context, candidates []showCandidate, mediaFilter model.MediaType, presenceIdx *ArtistPresenceIndex, cfg *model.Config, deps *Deps, analysis *model.ArtistCatalogAnalysis) {
 	if len(candidates) == 0 {
 		return
 	}
@@ -220,6 +232,11 @@ func AnalyzeArtistCatalogMediaAware(ctx context.Context, artistID string, cfg *m
 		return nil, fmt.Errorf("GetArtistMetaCached callback not configured")
 	}
 
+	// After a full crawl the index answers without any metadata at all.
+	if shows, artistName, ok := fullCatalogArtistShows(artistID); ok {
+		return analyzeFromFullCatalog(ctx, artistID, shows, artistName, cfg, jsonLevel, mediaFilter, deps), nil
+	}
+
 	// A fresh durable shard is authoritative for warm catalog analysis and keeps
 	// normal coverage/gap commands within their zero-network request budget.
 	// A catalog update invalidates older shards so new releases are fetched.
@@ -253,6 +270,38 @@ func AnalyzeArtistCatalogMediaAware(ctx context.Context, artistID string, cfg *m
 		return allShows[i].PerformanceDate > allShows[j].PerformanceDate
 	})
 
+	analysis := newArtistAnalysis(ctx, artistID, artistName, cfg, jsonLevel, mediaFilter, deps)
+	analysis.CacheUsed, analysis.CacheStaleUse = cacheUsed, cacheStaleUse
+	classifyShows(ctx, allShows, analysis.MediaFilter, &analysis.presence, cfg, deps, analysis.ArtistCatalogAnalysis)
+	return analysis.finish(), nil
+}
+
+// analyzeFromFullCatalog analyzes an artist from the full catalog index,
+// whose entries already record downloadability and media type.
+func analyzeFromFullCatalog(ctx context.Context, artistID string, shows []model.FullCatalogShow, artistName string, cfg *model.Config, jsonLevel string, mediaFilter model.MediaType, deps *Deps) *model.ArtistCatalogAnalysis {
+	sort.Slice(shows, func(i, j int) bool {
+		return shows[i].PerformanceDate > shows[j].PerformanceDate
+	})
+	analysis := newArtistAnalysis(ctx, artistID, artistName, cfg, jsonLevel, mediaFilter, deps)
+	analysis.CacheUsed = true
+	candidates := make([]showCandidate, 0, len(shows))
+	for _, show := range shows {
+		if MatchesMediaFilter(show.Media, analysis.MediaFilter) {
+			candidates = append(candidates, showCandidate{show: show.AlbArtResp(), showMedia: show.Media})
+		}
+	}
+	classifyCandidates(ctx, candidates, analysis.MediaFilter, &analysis.presence, cfg, deps, analysis.ArtistCatalogAnalysis)
+	return analysis.finish()
+}
+
+// artistAnalysis is an analysis in progress together with its presence index.
+type artistAnalysis struct {
+	*model.ArtistCatalogAnalysis
+	presence ArtistPresenceIndex
+}
+
+// newArtistAnalysis resolves the media filter and builds the presence index.
+func newArtistAnalysis(ctx context.Context, artistID, artistName string, cfg *model.Config, jsonLevel string, mediaFilter model.MediaType, deps *Deps) *artistAnalysis {
 	// Default to config default outputs if no filter specified
 	if mediaFilter == model.MediaTypeUnknown {
 		mediaFilter = model.ParseMediaType(cfg.DefaultOutputs)
@@ -266,24 +315,26 @@ func AnalyzeArtistCatalogMediaAware(ctx context.Context, artistID string, cfg *m
 		ui.PrintWarning(fmt.Sprintf("Remote artist folder bulk check failed, falling back to per-show checks: %v", presenceIdx.RemoteListErr))
 	}
 
-	analysis := &model.ArtistCatalogAnalysis{
-		ArtistID:      artistID,
-		ArtistName:    artistName,
-		Shows:         make([]model.ShowStatus, 0, len(allShows)),
-		MissingShows:  make([]model.ShowStatus, 0, len(allShows)),
-		CacheUsed:     cacheUsed,
-		CacheStaleUse: cacheStaleUse,
-		MediaFilter:   mediaFilter,
+	return &artistAnalysis{
+		ArtistCatalogAnalysis: &model.ArtistCatalogAnalysis{
+			ArtistID:     artistID,
+			ArtistName:   artistName,
+			Shows:        []model.ShowStatus{},
+			MissingShows: []model.ShowStatus{},
+			MediaFilter:  mediaFilter,
+		},
+		presence: presenceIdx,
 	}
+}
 
-	classifyShows(ctx, allShows, mediaFilter, &presenceIdx, cfg, deps, analysis)
-
+// finish fills in the totals and percentages.
+func (a *artistAnalysis) finish() *model.ArtistCatalogAnalysis {
+	analysis := a.ArtistCatalogAnalysis
 	analysis.TotalShows = len(analysis.Shows)
 	analysis.Missing = len(analysis.MissingShows)
 	if analysis.TotalShows > 0 {
 		analysis.DownloadPct = float64(analysis.Downloaded) / float64(analysis.TotalShows) * 100
 		analysis.MissingPct = float64(analysis.Missing) / float64(analysis.TotalShows) * 100
 	}
-
-	return analysis, nil
+	return analysis
 }
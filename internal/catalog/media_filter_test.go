@@ -6,6 +6,7 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"strings"
 	"testing"
 
 	"github.com/jmagar/nugs-cli/internal/helpers"
@@ -78,6 +79,29 @@ func TestBuildArtistPresenceIndex_BothFilterReadsAudioAndVideoTrees(t *testing.T
 	}
 }
 
+func TestBuildArtistPresenceIndex_SkipsPartialShows(t *testing.T) {
+	cfg := &model.Config{OutPath: t.TempDir()}
+	artist := "Billy Strings"
+	fullShow := helpers.BuildAlbumFolderName(artist, "Full Show")
+	partialShow := helpers.BuildAlbumFolderName(artist, "Partial Show")
+	for _, show := range []string{fullShow, partialShow} {
+		if err := os.MkdirAll(filepath.Join(cfg.OutPath, helpers.Sanitise(artist), show), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(cfg.OutPath, helpers.Sanitise(artist), partialShow, helpers.PartialShowMarker), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	idx := BuildArtistPresenceIndex(context.Background(), artist, cfg, &Deps{}, model.MediaTypeAudio)
+	if _, ok := idx.LocalFolders[fullShow]; !ok {
+		t.Errorf("expected %q in local folder index", fullShow)
+	}
+	if _, ok := idx.LocalFolders[partialShow]; ok {
+		t.Errorf("partial --tracks folder %q should not count as downloaded", partialShow)
+	}
+}
+
 func TestBuildArtistPresenceIndex_BothFilterReadsAudioAndVideoRemoteTrees(t *testing.T) {
 	cfg := &model.Config{
 		OutPath:       t.TempDir(),
@@ -305,6 +329,47 @@ func TestListAllRemoteArtistFolders_WrapsErrorWithSentinel(t *testing.T) {
 	}
 }
 
+func TestRemoteListingsUseLocalStorageBackend(t *testing.T) {
+	audioRoot := t.TempDir()
+	videoRoot := t.TempDir()
+	for _, dir := range []string{
+		filepath.Join(audioRoot, "Phish", "2023", "Phish - 2023-12-31"),
+		filepath.Join(audioRoot, "Phish", "2024", "Phish - 2024-08-01"),
+		filepath.Join(audioRoot, "Goose", "2023", "Goose - 2023-07-04"),
+		filepath.Join(videoRoot, "Phish", "2023", "Phish - 2023-12-31"),
+		filepath.Join(videoRoot, "Phish", "2022", "Phish - 2022-04-20"),
+	} {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// No rclone binary is needed on PATH for a native backend.
+	t.Setenv("PATH", t.TempDir())
+	cfg := &model.Config{
+		RcloneEnabled:   true,
+		StorageBackend:  model.StorageBackendLocal,
+		RclonePath:      audioRoot,
+		RcloneVideoPath: videoRoot,
+		FolderTemplate:  "{artist}/{year}/{artist} - {container}",
+	}
+
+	artists, err := ListAllRemoteArtistFolders(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("ListAllRemoteArtistFolders() error = %v", err)
+	}
+	if len(artists) != 2 {
+		t.Fatalf("artists = %v, want Phish and Goose", artists)
+	}
+
+	counts, total, err := CountRemoteShowsPerArtist(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("CountRemoteShowsPerArtist() error = %v", err)
+	}
+	if counts["Phish"] != 3 || counts["Goose"] != 1 || total != 4 {
+		t.Fatalf("counts = %v total = %d, want Phish 3 (audio and video deduplicated), Goose 1", counts, total)
+	}
+}
+
 func TestClassifyShows_IntegrationWithPresenceIndex(t *testing.T) {
 	artist := "Billy Strings"
 	artistFolder := helpers.Sanitise(artist)
@@ -503,3 +568,103 @@ func TestBuildArtistPresenceIndex_RemoteListErrorStopsEarly(t *testing.T) {
 		t.Fatalf("expected empty remote folders after error, got %d", len(idx.RemoteFolders))
 	}
 }
+
+func TestBuildArtistPresenceIndex_FolderTemplateWithYearLevel(t *testing.T) {
+	cfg := &model.Config{
+		OutPath:        t.TempDir(),
+		RcloneEnabled:  true,
+		FolderTemplate: "{artist}/{year}/{date} {venue}",
+	}
+	localShow := &model.AlbArtResp{ArtistName: "Goose", PerformanceDate: "6/22/2024", VenueName: "Forest Hills Stadium"}
+	remoteShow := &model.AlbArtResp{ArtistName: "Goose", PerformanceDate: "12/31/2023", VenueName: "MSG"}
+	missingShow := &model.AlbArtResp{ArtistName: "Goose", PerformanceDate: "1/1/2022", VenueName: "Nowhere"}
+
+	localPath := helpers.NewConfigPathResolver(cfg).LocalShowPath(localShow, model.MediaTypeAudio)
+	if want := filepath.Join("Goose", "2024", "2024-06-22 Forest Hills Stadium"); !filepath.IsAbs(localPath) || !strings.HasSuffix(localPath, want) {
+		t.Fatalf("LocalShowPath = %q, want suffix %q", localPath, want)
+	}
+	if err := os.MkdirAll(localPath, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	var listed []string
+	deps := &Deps{
+		ListRemoteArtistFolders: func(_ context.Context, folder string, _ *model.Config, _ bool) (map[string]struct{}, error) {
+			listed = append(listed, folder)
+			switch folder {
+			case "Goose":
+				return map[string]struct{}{"2023": {}}, nil
+			case "Goose/2023":
+				return map[string]struct{}{"2023-12-31 MSG": {}}, nil
+			}
+			return map[string]struct{}{}, nil
+		},
+	}
+
+	idx := BuildArtistPresenceIndex(context.Background(), "Goose", cfg, deps, model.MediaTypeAudio)
+	if idx.RemoteListErr != nil {
+		t.Fatalf("unexpected remote list error: %v", idx.RemoteListErr)
+	}
+	if len(listed) != 2 {
+		t.Fatalf("remote listings = %v, want artist folder then year folder", listed)
+	}
+	for _, show := range []*model.AlbArtResp{localShow, remoteShow} {
+		if !ShowExistsForMediaIndexed(context.Background(), show, cfg, model.MediaTypeAudio, &idx, deps) {
+			t.Fatalf("expected %s to be found via templated index", show.PerformanceDate)
+		}
+	}
+	if ShowExistsForMediaIndexed(context.Background(), missingShow, cfg, model.MediaTypeAudio, &idx, deps) {
+		t.Fatal("missing show reported as downloaded")
+	}
+}
+
+func TestDestinationCoverageCountsShowsPerDestination(t *testing.T) {
+	cfg := &model.Config{
+		RcloneEnabled: true,
+		RclonePath:    "/Music",
+		UploadDestinations: []model.UploadDestination{
+			{Name: "nas", RcloneRemote: "nas"},
+			{Name: "cold", RcloneRemote: "s3", Media: "audio"},
+		},
+	}
+	shows := []*model.AlbArtResp{
+		{ArtistName: "Goose", ContainerInfo: "2023-07-04 Red Rocks"},
+		{ArtistName: "Goose", ContainerInfo: "2023-07-05 Red Rocks"},
+		{ArtistName: "Goose", ContainerInfo: "2023-07-06 Red Rocks"},
+	}
+	analysis := &model.ArtistCatalogAnalysis{ArtistName: "Goose"}
+	for _, show := range shows {
+		analysis.Shows = append(analysis.Shows, model.ShowStatus{Show: show})
+	}
+	folder := func(show *model.AlbArtResp) string { return helpers.ShowRelativePath(cfg, show) }
+	remote := map[string]map[bool][]string{
+		"nas": {false: {folder(shows[0]), folder(shows[1])}, true: {folder(shows[2])}},
+		"s3":  {false: {folder(shows[0])}, true: {folder(shows[1]), folder(shows[2])}},
+	}
+	deps := &Deps{
+		ListRemoteArtistFolders: func(_ context.Context, artistFolder string, destCfg *model.Config, isVideo bool) (map[string]struct{}, error) {
+			if artistFolder != "Goose" {
+				t.Fatalf("artist folder = %q", artistFolder)
+			}
+			folders := map[string]struct{}{}
+			for _, name := range remote[destCfg.RcloneRemote][isVideo] {
+				folders[name] = struct{}{}
+			}
+			return folders, nil
+		},
+	}
+
+	counts, err := destinationCoverage(context.Background(), cfg, analysis, model.MediaTypeBoth, deps)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// cold only takes audio, so its video folders are not consulted.
+	if counts["nas"] != 3 || counts["cold"] != 1 {
+		t.Fatalf("counts = %v, want nas 3, cold 1", counts)
+	}
+
+	counts, err = destinationCoverage(context.Background(), &model.Config{RcloneEnabled: true}, analysis, model.MediaTypeBoth, deps)
+	if err != nil || counts != nil {
+		t.Fatalf("single-destination config = %v, %v, want nil", counts, err)
+	}
+}
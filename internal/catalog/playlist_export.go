@@ -0,0 +1,193 @@
+package catalog
+
+import (
+	"cmp"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/jmagar/nugs-cli/internal/helpers"
+	"github.com/jmagar/nugs-cli/internal/history"
+	"github.com/jmagar/nugs-cli/internal/model"
+	"github.com/jmagar/nugs-cli/internal/playlist"
+	"github.com/jmagar/nugs-cli/internal/search"
+	"github.com/jmagar/nugs-cli/internal/ui"
+)
+
+// playlistAudioExts are the track files picked up from show folders that
+// have no playlist of their own.
+var playlistAudioExts = map[string]bool{".flac": true, ".m4a": true, ".mp4": true, ".mp3": true}
+
+// PlaylistExport runs `nugs playlist export <file> <query...>`: it writes
+// one playlist covering the local audio of every show matching the search
+// query, oldest first. The format follows the file extension.
+func PlaylistExport(outPath string, args []string, cfg *model.Config, jsonLevel string, deps *Deps) error {
+	format, err := playlist.FormatForPath(outPath)
+	if err != nil {
+		return err
+	}
+	query, err := search.Parse(args...)
+	if err != nil {
+		return err
+	}
+	index, err := loadSearchIndex()
+	if err != nil {
+		return err
+	}
+	results := search.Run(index, query)
+	slices.SortStableFunc(results, func(a, b model.SearchShow) int {
+		return cmp.Compare(a.Date, b.Date)
+	})
+
+	absOut, err := filepath.Abs(outPath)
+	if err != nil {
+		return fmt.Errorf("failed to resolve %s: %w", outPath, err)
+	}
+	outDir := filepath.Dir(absOut)
+	recorded := recordedAudioFolders(cfg, deps)
+
+	var (
+		entries []playlist.Entry
+		found   int
+		missing []model.SearchShow
+	)
+	for _, show := range results {
+		folder := localShowFolder(cfg, show, recorded)
+		showEntries, err := showPlaylistEntries(folder, show)
+		if err != nil || len(showEntries) == 0 {
+			missing = append(missing, show)
+			continue
+		}
+		found++
+		for _, entry := range showEntries {
+			entry.Location = playlist.RelativeLocation(outDir, entry.Location)
+			entries = append(entries, entry)
+		}
+	}
+
+	label := strings.Join(args, " ")
+	if len(entries) > 0 {
+		if err := playlist.Save(absOut, format, label, entries); err != nil {
+			return err
+		}
+	}
+
+	if jsonLevel != "" {
+		missingIDs := make([]int, len(missing))
+		for i, show := range missing {
+			missingIDs[i] = show.ContainerID
+		}
+		return PrintJSON(map[string]any{
+			"query":   label,
+			"path":    absOut,
+			"format":  format,
+			"written": len(entries) > 0,
+			"matches": len(results),
+			"shows":   found,
+			"tracks":  len(entries),
+			"missing": missingIDs,
+		})
+	}
+
+	if len(results) == 0 {
+		ui.PrintInfo(fmt.Sprintf("No shows match %q", label))
+		return nil
+	}
+	if len(entries) == 0 {
+		ui.PrintInfo(fmt.Sprintf("None of the %d matching show(s) are downloaded locally; no playlist written", len(results)))
+		return nil
+	}
+	ui.PrintSuccess(fmt.Sprintf("Wrote %s: %d track(s) from %d show(s)", absOut, len(entries), found))
+	if len(missing) > 0 {
+		ui.PrintInfo(fmt.Sprintf("%d matching show(s) not found locally", len(missing)))
+	}
+	return nil
+}
+
+// recordedAudioFolders maps container IDs to the local folders the download
+// history recorded for their audio.
+func recordedAudioFolders(cfg *model.Config, deps *Deps) map[int]string {
+	folders := map[int]string{}
+	if deps == nil || deps.History == nil || cfg.SkipHistory {
+		return folders
+	}
+	recorded, err := deps.History.Entries()
+	if err != nil {
+		ui.PrintWarning(fmt.Sprintf("Download history unavailable, using folder names only: %v", err))
+		return folders
+	}
+	for _, entry := range recorded {
+		if entry.Media == history.MediaAudio && entry.LocalPath != "" {
+			folders[entry.ContainerID] = entry.LocalPath
+		}
+	}
+	return folders
+}
+
+// localShowFolder returns where a show's audio lives: the recorded download
+// folder when it still exists, else the folder template under outPath.
+func localShowFolder(cfg *model.Config, show model.SearchShow, recorded map[int]string) string {
+	if folder, ok := recorded[show.ContainerID]; ok {
+		if info, err := os.Stat(folder); err == nil && info.IsDir() {
+			return folder
+		}
+	}
+	segments := helpers.ShowPathSegments(cfg, &model.AlbArtResp{
+		ArtistID:        show.ArtistID,
+		ArtistName:      show.ArtistName,
+		ContainerID:     show.ContainerID,
+		ContainerInfo:   show.Title,
+		PerformanceDate: show.Date,
+		VenueName:       show.Venue,
+		VenueCity:       show.City,
+		VenueState:      show.State,
+	})
+	return filepath.Join(append([]string{cfg.OutPath}, segments...)...)
+}
+
+// showPlaylistEntries lists a show folder's tracks with absolute locations,
+// from the folder's own M3U8 when present, else from its audio files in name
+// order.
+func showPlaylistEntries(folder string, show model.SearchShow) ([]playlist.Entry, error) {
+	own := filepath.Join(folder, filepath.Base(folder)+"."+playlist.FormatM3U8)
+	if entries, err := playlist.ReadM3U8File(own); err == nil {
+		var out []playlist.Entry
+		for _, entry := range entries {
+			location := filepath.FromSlash(entry.Location)
+			if !filepath.IsAbs(location) {
+				location = filepath.Join(folder, location)
+			}
+			if _, err := os.Stat(location); err != nil {
+				continue
+			}
+			entry.Location = location
+			if artist := show.ArtistName + " - "; show.ArtistName != "" && strings.HasPrefix(entry.Title, artist) {
+				entry.Title = strings.TrimPrefix(entry.Title, artist)
+			}
+			entry.Artist, entry.Album = show.ArtistName, show.Title
+			out = append(out, entry)
+		}
+		if len(out) > 0 {
+			return out, nil
+		}
+	}
+
+	files, err := os.ReadDir(folder)
+	if err != nil {
+		return nil, err
+	}
+	var out []playlist.Entry
+	for _, file := range files {
+		if file.IsDir() || !playlistAudioExts[strings.ToLower(filepath.Ext(file.Name()))] {
+			continue
+		}
+		out = append(out, playlist.Entry{
+			Location: filepath.Join(folder, file.Name()),
+			Artist:   show.ArtistName,
+			Album:    show.Title,
+		})
+	}
+	return out, nil
+}
@@ -0,0 +1,103 @@
+package catalog
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jmagar/nugs-cli/internal/cache"
+	"github.com/jmagar/nugs-cli/internal/helpers"
+	"github.com/jmagar/nugs-cli/internal/model"
+	"github.com/jmagar/nugs-cli/internal/playlist"
+	"github.com/jmagar/nugs-cli/internal/testutil"
+)
+
+func TestPlaylistExport(t *testing.T) {
+	testutil.WithTempHome(t)
+	if err := cache.WriteCatalogCache(buildUpdateCatalog([]showSpec{
+		{1001, 500, "Billy Strings", "2025-03-01", "Red Rocks Night 3"},
+		{1002, 500, "Billy Strings", "2025-01-01", "Red Rocks Night 1"},
+		{1003, 500, "Billy Strings", "2025-02-01", "Red Rocks Night 2"},
+	}), 0, noDurationFmt); err != nil {
+		t.Fatal(err)
+	}
+	index, err := loadSearchIndex()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := &model.Config{OutPath: t.TempDir()}
+	showDir := func(id int) string {
+		show := index.Shows[id]
+		segments := helpers.ShowPathSegments(cfg, &model.AlbArtResp{
+			ArtistName: show.ArtistName, ContainerInfo: show.Title, PerformanceDate: show.Date, VenueName: show.Venue,
+		})
+		dir := filepath.Join(append([]string{cfg.OutPath}, segments...)...)
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		return dir
+	}
+
+	// 1001 has its own playlist; 1002 only audio files; 1003 is missing.
+	late := showDir(1001)
+	for _, name := range []string{"02. B.flac", "01. A.flac"} {
+		if err := os.WriteFile(filepath.Join(late, name), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := playlist.Save(filepath.Join(late, filepath.Base(late)+".m3u8"), playlist.FormatM3U8, "", []playlist.Entry{
+		{Location: "02. B.flac", Title: "B", Artist: "Billy Strings", Duration: 90},
+		{Location: "01. A.flac", Title: "A", Artist: "Billy Strings"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	early := showDir(1002)
+	for _, name := range []string{"02. D.m4a", "01. C.m4a", "cover.jpg"} {
+		if err := os.WriteFile(filepath.Join(early, name), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	out := filepath.Join(t.TempDir(), "red rocks.m3u8")
+	stdout := testutil.CaptureStdout(t, func() {
+		if err := PlaylistExport(out, []string{"title:red rocks"}, cfg, "standard", &Deps{}); err != nil {
+			t.Fatal(err)
+		}
+	})
+	var summary struct {
+		Shows   int   `json:"shows"`
+		Tracks  int   `json:"tracks"`
+		Missing []int `json:"missing"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &summary); err != nil {
+		t.Fatalf("invalid JSON %q: %v", stdout, err)
+	}
+	if summary.Shows != 2 || summary.Tracks != 4 || len(summary.Missing) != 1 || summary.Missing[0] != 1003 {
+		t.Fatalf("summary = %+v", summary)
+	}
+
+	entries, err := playlist.ReadM3U8File(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, entry := range entries {
+		got = append(got, filepath.Base(entry.Location))
+	}
+	want := []string{"01. C.m4a", "02. D.m4a", "02. B.flac", "01. A.flac"}
+	if len(got) != len(want) {
+		t.Fatalf("locations = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("locations = %v, want %v", got, want)
+		}
+	}
+	if entries[2].Duration != 90 || entries[2].Title != "Billy Strings - B" {
+		t.Errorf("entry from show playlist = %+v", entries[2])
+	}
+	if resolved := filepath.Join(filepath.Dir(out), filepath.FromSlash(entries[0].Location)); resolved != filepath.Join(early, "01. C.m4a") {
+		t.Errorf("relative location resolves to %s", resolved)
+	}
+}
@@ -0,0 +1,211 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+
+	"github.com/jmagar/nugs-cli/internal/helpers"
+	"github.com/jmagar/nugs-cli/internal/manifest"
+	"github.com/jmagar/nugs-cli/internal/model"
+	"github.com/jmagar/nugs-cli/internal/storage"
+	"github.com/jmagar/nugs-cli/internal/ui"
+)
+
+// Remote show statuses reported by RemoteVerify.
+const (
+	RemoteStatusOK         = "ok"
+	RemoteStatusMismatch   = "mismatch"
+	RemoteStatusNoManifest = "no-manifest"
+	RemoteStatusError      = "error"
+)
+
+// RemoteShowResult is the outcome of checking one remote show folder.
+type RemoteShowResult struct {
+	Destination string             `json:"destination,omitempty"`
+	Show        string             `json:"show"`
+	Status      string             `json:"status"`
+	Files       int                `json:"files"`
+	Problems    []manifest.Problem `json:"problems,omitempty"`
+	Error       string             `json:"error,omitempty"`
+}
+
+// RemoteVerifySummary is the JSON output of RemoteVerify. Shows lists only
+// the folders that did not verify unless the extended or raw JSON level is
+// requested.
+type RemoteVerifySummary struct {
+	ArtistID   string             `json:"artistID"`
+	Artist     string             `json:"artist"`
+	Checked    int                `json:"checked"`
+	OK         int                `json:"ok"`
+	Mismatch   int                `json:"mismatch"`
+	NoManifest int                `json:"noManifest"`
+	Errors     int                `json:"errors"`
+	Shows      []RemoteShowResult `json:"shows"`
+}
+
+// remoteTarget is one upload destination to check.
+type remoteTarget struct {
+	name string
+	cfg  *model.Config
+}
+
+// remoteVerifyTargets lists the destinations that hold audio shows.
+func remoteVerifyTargets(cfg *model.Config) []remoteTarget {
+	if len(cfg.UploadDestinations) == 0 {
+		return []remoteTarget{{cfg: cfg}}
+	}
+	var targets []remoteTarget
+	for _, dest := range helpers.DestinationsForMedia(cfg, false) {
+		targets = append(targets, remoteTarget{name: dest.Name, cfg: helpers.DestinationConfig(cfg, dest)})
+	}
+	return targets
+}
+
+// RemoteVerify re-checks an artist's show folders on every upload
+// destination against the checksum manifest uploaded with each show. It
+// returns an error when any show fails so scripts can rely on the exit
+// status; shows uploaded before manifests existed are reported but do not
+// fail.
+func RemoteVerify(ctx context.Context, artistID string, cfg *model.Config, jsonLevel string, deps *Deps) error {
+	if !cfg.RcloneEnabled {
+		return errors.New("remote verify needs uploads enabled (rcloneEnabled)")
+	}
+	if _, err := strconv.Atoi(artistID); err != nil {
+		return fmt.Errorf("invalid artist ID %q", artistID)
+	}
+	name, err := lookupArtistName(ctx, artistID, deps)
+	if err != nil {
+		return err
+	}
+	artistFolder := helpers.ArtistFolderName(cfg, name)
+	if jsonLevel == "" {
+		ui.PrintHeader(fmt.Sprintf("Verifying remote shows for %s", name))
+	}
+
+	summary := RemoteVerifySummary{ArtistID: artistID, Artist: name, Shows: []RemoteShowResult{}}
+	verbose := jsonLevel == model.JSONLevelExtended || jsonLevel == model.JSONLevelRaw
+	for _, target := range remoteVerifyTargets(cfg) {
+		results, err := verifyRemoteArtist(ctx, target, artistFolder)
+		if err != nil {
+			if target.name != "" {
+				return fmt.Errorf("%s: %w", target.name, err)
+			}
+			return err
+		}
+		for _, result := range results {
+			switch result.Status {
+			case RemoteStatusOK:
+				summary.OK++
+			case RemoteStatusMismatch:
+				summary.Mismatch++
+			case RemoteStatusNoManifest:
+				summary.NoManifest++
+			default:
+				summary.Errors++
+			}
+			if verbose || result.Status != RemoteStatusOK {
+				summary.Shows = append(summary.Shows, result)
+			}
+			if jsonLevel == "" {
+				printRemoteShowResult(result)
+			}
+		}
+		summary.Checked += len(results)
+	}
+	failed := summary.Mismatch + summary.Errors
+
+	if jsonLevel != "" {
+		if err := PrintJSON(summary); err != nil {
+			return err
+		}
+	} else {
+		fmt.Println()
+		ui.PrintKeyValue("Shows checked", strconv.Itoa(summary.Checked), ui.ColorReset)
+		ui.PrintKeyValue("OK", strconv.Itoa(summary.OK), ui.ColorGreen)
+		ui.PrintKeyValue("Mismatch", strconv.Itoa(summary.Mismatch), ui.ColorRed)
+		ui.PrintKeyValue("Errors", strconv.Itoa(summary.Errors), ui.ColorRed)
+		ui.PrintKeyValue("No manifest", strconv.Itoa(summary.NoManifest), ui.ColorYellow)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d remote shows failed verification", failed, summary.Checked)
+	}
+	if jsonLevel == "" && summary.Checked > 0 {
+		ui.PrintSuccess("All remote shows with a manifest verified")
+	}
+	return nil
+}
+
+// verifyRemoteArtist checks every show folder under artistFolder on one
+// destination.
+func verifyRemoteArtist(ctx context.Context, target remoteTarget, artistFolder string) ([]RemoteShowResult, error) {
+	provider, err := storage.ForConfig(target.cfg)
+	if err != nil {
+		return nil, err
+	}
+	reader, ok := provider.(model.StorageReader)
+	if !ok {
+		return nil, fmt.Errorf("%s storage cannot read remote files", helpers.StorageBackend(target.cfg))
+	}
+	shows, err := listShowFolders(ctx, provider, target.cfg, artistFolder, helpers.ShowFolderDepth(target.cfg), false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list remote shows: %w", err)
+	}
+	sort.Strings(shows)
+	results := make([]RemoteShowResult, 0, len(shows))
+	for _, show := range shows {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		result := verifyRemoteShow(ctx, reader, target.cfg, path.Join(artistFolder, show))
+		result.Destination = target.name
+		result.Show = show
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// verifyRemoteShow compares one remote show folder with its manifest.
+func verifyRemoteShow(ctx context.Context, reader model.StorageReader, cfg *model.Config, showPath string) RemoteShowResult {
+	data, err := reader.ReadFile(ctx, cfg, path.Join(showPath, manifest.FileName), false)
+	if errors.Is(err, fs.ErrNotExist) {
+		return RemoteShowResult{Status: RemoteStatusNoManifest}
+	}
+	if err != nil {
+		return RemoteShowResult{Status: RemoteStatusError, Error: err.Error()}
+	}
+	m, err := manifest.Parse(data)
+	if err != nil {
+		return RemoteShowResult{Status: RemoteStatusError, Error: err.Error()}
+	}
+	hashes, err := reader.FileHashes(ctx, cfg, showPath, m.Names(), false)
+	if err != nil {
+		return RemoteShowResult{Status: RemoteStatusError, Files: len(m.Files), Error: err.Error()}
+	}
+	result := RemoteShowResult{Status: RemoteStatusOK, Files: len(m.Files), Problems: manifest.Check(m, hashes)}
+	if len(result.Problems) > 0 {
+		result.Status = RemoteStatusMismatch
+	}
+	return result
+}
+
+func printRemoteShowResult(result RemoteShowResult) {
+	label := result.Show
+	if result.Destination != "" {
+		label = result.Destination + ": " + label
+	}
+	switch result.Status {
+	case RemoteStatusOK:
+		ui.PrintSuccess(fmt.Sprintf("%s (%d files)", label, result.Files))
+	case RemoteStatusNoManifest:
+		ui.PrintWarning(fmt.Sprintf("%s: no checksum manifest", label))
+	case RemoteStatusMismatch:
+		ui.PrintError(fmt.Sprintf("%s: %v", label, manifest.Err(result.Problems)))
+	default:
+		ui.PrintError(fmt.Sprintf("%s: %s", label, result.Error))
+	}
+}
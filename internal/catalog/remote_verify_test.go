@@ -0,0 +1,76 @@
+package catalog
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jmagar/nugs-cli/internal/manifest"
+	"github.com/jmagar/nugs-cli/internal/model"
+	"github.com/jmagar/nugs-cli/internal/testutil"
+)
+
+func TestRemoteVerifyChecksShowsAgainstManifests(t *testing.T) {
+	testutil.WithTempHome(t)
+	mirror := t.TempDir()
+	cfg := &model.Config{RcloneEnabled: true, StorageBackend: model.StorageBackendLocal, RclonePath: mirror}
+	writeRemoteShow := func(name string, withManifest bool) string {
+		show := filepath.Join(mirror, "Goose", name)
+		if err := os.MkdirAll(show, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(show, "01. Arcadia.flac"), []byte("audio"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if withManifest {
+			if _, err := manifest.Write(show); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return show
+	}
+	writeRemoteShow("Goose - 2024-06-22", true)
+	corrupt := writeRemoteShow("Goose - 2024-06-23", true)
+	writeRemoteShow("Goose - 2019-01-01", false)
+	if err := os.WriteFile(filepath.Join(corrupt, "01. Arcadia.flac"), []byte("AUDIO"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	deps := &Deps{
+		GetArtistMetaCached: func(context.Context, string, time.Duration) ([]*model.ArtistMeta, bool, bool, error) {
+			return []*model.ArtistMeta{artistPageWithShow("Goose", 10, "Show")}, false, false, nil
+		},
+	}
+
+	var verifyErr error
+	out := testutil.CaptureStdout(t, func() {
+		verifyErr = RemoteVerify(context.Background(), "1125", cfg, model.JSONLevelStandard, deps)
+	})
+	if verifyErr == nil || !strings.Contains(verifyErr.Error(), "1 of 3 remote shows failed") {
+		t.Fatalf("RemoteVerify error = %v, want one failed show", verifyErr)
+	}
+	var summary RemoteVerifySummary
+	if err := json.Unmarshal([]byte(out), &summary); err != nil {
+		t.Fatalf("output is not JSON: %v\n%s", err, out)
+	}
+	if summary.OK != 1 || summary.Mismatch != 1 || summary.NoManifest != 1 || len(summary.Shows) != 2 {
+		t.Fatalf("summary = %+v", summary)
+	}
+	for _, show := range summary.Shows {
+		switch show.Show {
+		case "Goose - 2024-06-23":
+			if show.Status != RemoteStatusMismatch || len(show.Problems) != 1 || show.Problems[0].Reason != "sha256 mismatch" {
+				t.Fatalf("corrupt show = %+v", show)
+			}
+		case "Goose - 2019-01-01":
+			if show.Status != RemoteStatusNoManifest {
+				t.Fatalf("legacy show = %+v", show)
+			}
+		default:
+			t.Fatalf("unexpected show in summary: %+v", show)
+		}
+	}
+}
@@ -0,0 +1,300 @@
+package catalog
+
+import (
+	"bufio"
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/jmagar/nugs-cli/internal/helpers"
+	"github.com/jmagar/nugs-cli/internal/model"
+	"github.com/jmagar/nugs-cli/internal/ui"
+)
+
+// maxArtistCandidates caps how many fuzzy matches are offered or reported.
+const maxArtistCandidates = 10
+
+// ArtistCandidate is a cached artist that may match a name query.
+type ArtistCandidate struct {
+	ID       int    `json:"artistID"`
+	Name     string `json:"artistName"`
+	NumShows int    `json:"numShows,omitempty"`
+}
+
+func (c ArtistCandidate) String() string {
+	return fmt.Sprintf("%s (%d)", c.Name, c.ID)
+}
+
+// AmbiguousArtistError reports a name query that matched more than one
+// artist, or only approximately matched one, when no prompt was available to
+// confirm the choice.
+type AmbiguousArtistError struct {
+	Query      string
+	Candidates []ArtistCandidate
+}
+
+func (e *AmbiguousArtistError) Error() string {
+	if len(e.Candidates) == 1 {
+		return fmt.Sprintf("artist %q is not an exact match, did you mean %s? (use the full name, the numeric ID or add an artistAliases entry)",
+			e.Query, e.Candidates[0])
+	}
+	names := make([]string, len(e.Candidates))
+	for i, c := range e.Candidates {
+		names[i] = c.String()
+	}
+	return fmt.Sprintf("artist %q is ambiguous, candidates: %s (use the numeric ID or add an artistAliases entry)",
+		e.Query, strings.Join(names, ", "))
+}
+
+// ArtistPrompt asks the user to choose between candidate artists and returns
+// the index of the chosen one.
+type ArtistPrompt func(query string, candidates []ArtistCandidate) (int, error)
+
+// ResolveArtistID maps a command-line artist argument to a numeric artist ID.
+// A positive numeric ID is returned unchanged. Otherwise the query is looked
+// up in cfg.ArtistAliases, whose IDs must also be positive numbers, then
+// matched against the cached artist list: a single exact name wins, while a
+// fuzzy or typo match, or several matches, go to prompt for confirmation. A nil prompt (non-interactive or JSON mode) turns
+// them into an *AmbiguousArtistError, so a guess never starts a download or
+// a watch on its own.
+func ResolveArtistID(ctx context.Context, query string, cfg *model.Config, jsonLevel string, prompt ArtistPrompt, deps *Deps) (string, error) {
+	query = strings.TrimSpace(query)
+	if helpers.IsArtistID(query) {
+		return query, nil
+	}
+	key := normalizeArtistName(query)
+	if key == "" {
+		return "", fmt.Errorf("invalid artist %q", query)
+	}
+	if cfg != nil {
+		for alias, id := range cfg.ArtistAliases {
+			if normalizeArtistName(alias) == key {
+				if id = strings.TrimSpace(id); !helpers.IsArtistID(id) {
+					return "", fmt.Errorf("artistAliases %q maps to %q, which is not a positive numeric artist ID", alias, id)
+				}
+				return id, nil
+			}
+		}
+	}
+
+	candidates := artistCandidates(ctx, deps)
+	if len(candidates) == 0 {
+		return "", fmt.Errorf("cannot resolve artist %q: no cached artist list (run 'nugs update' or use the numeric ID)", query)
+	}
+	matches := matchArtists(key, candidates)
+	if len(matches) == 0 {
+		return "", fmt.Errorf("no artist matches %q", query)
+	}
+
+	var chosen ArtistCandidate
+	switch {
+	case len(matches) == 1 && normalizeArtistName(matches[0].Name) == key:
+		chosen = matches[0]
+	case prompt == nil:
+		return "", &AmbiguousArtistError{Query: query, Candidates: matches}
+	default:
+		idx, err := prompt(query, matches)
+		if err != nil {
+			return "", err
+		}
+		if idx < 0 || idx >= len(matches) {
+			return "", fmt.Errorf("invalid artist selection %d", idx+1)
+		}
+		chosen = matches[idx]
+	}
+	if jsonLevel == "" && normalizeArtistName(chosen.Name) != key {
+		ui.PrintInfo(fmt.Sprintf("Using %s for %q", chosen, query))
+	}
+	return strconv.Itoa(chosen.ID), nil
+}
+
+// ResolveArtistIDs resolves each argument with ResolveArtistID.
+func ResolveArtistIDs(ctx context.Context, queries []string, cfg *model.Config, jsonLevel string, prompt ArtistPrompt, deps *Deps) ([]string, error) {
+	ids := make([]string, 0, len(queries))
+	for _, query := range queries {
+		id, err := ResolveArtistID(ctx, query, cfg, jsonLevel, prompt, deps)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// NewArtistPrompt returns an ArtistPrompt that lists the candidates on out
+// and reads a 1-based choice from in. A lone approximate match is offered
+// for confirmation the same way. An empty answer cancels.
+func NewArtistPrompt(in io.Reader, out io.Writer) ArtistPrompt {
+	reader := bufio.NewReader(in)
+	return func(query string, candidates []ArtistCandidate) (int, error) {
+		if len(candidates) == 1 {
+			fmt.Fprintf(out, "No artist is named %q exactly. Closest match:\n", query)
+		} else {
+			fmt.Fprintf(out, "Several artists match %q:\n", query)
+		}
+		for i, c := range candidates {
+			fmt.Fprintf(out, "  %2d) %s", i+1, c)
+			if c.NumShows > 0 {
+				fmt.Fprintf(out, " - %d shows", c.NumShows)
+			}
+			fmt.Fprintln(out)
+		}
+		fmt.Fprintf(out, "Select an artist [1-%d, Enter to cancel]: ", len(candidates))
+		line, err := reader.ReadString('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return -1, err
+		}
+		line = strings.TrimSpace(line)
+		if line == "" {
+			return -1, errors.New("artist selection cancelled")
+		}
+		n, err := strconv.Atoi(line)
+		if err != nil || n < 1 || n > len(candidates) {
+			return -1, fmt.Errorf("invalid artist selection %q", line)
+		}
+		return n - 1, nil
+	}
+}
+
+// artistCandidates merges the cached catalog.artists list with artist names
+// from the containers index. Either source may be missing.
+func artistCandidates(ctx context.Context, deps *Deps) []ArtistCandidate {
+	byID := map[int]ArtistCandidate{}
+	if deps != nil && deps.FetchArtistList != nil {
+		if resp, err := deps.FetchArtistList(ctx); err == nil && resp != nil {
+			for _, a := range resp.Response.Artists {
+				if a.ArtistID > 0 && a.ArtistName != "" {
+					byID[a.ArtistID] = ArtistCandidate{ID: a.ArtistID, Name: a.ArtistName, NumShows: a.NumShows}
+				}
+			}
+		}
+	}
+	for id, name := range buildArtistNameMap() {
+		if _, ok := byID[id]; !ok && id > 0 && name != "" {
+			byID[id] = ArtistCandidate{ID: id, Name: name}
+		}
+	}
+	candidates := make([]ArtistCandidate, 0, len(byID))
+	for _, c := range byID {
+		candidates = append(candidates, c)
+	}
+	return candidates
+}
+
+// Match strengths, strongest first.
+const (
+	matchExact = iota + 1
+	matchPrefix
+	matchWordPrefix
+	matchSubstring
+	matchTypo
+)
+
+// matchArtists returns the candidates that best match the normalized query.
+// Exact matches shadow everything else; otherwise only the strongest kind of
+// match found is kept, ranked by show count.
+func matchArtists(key string, candidates []ArtistCandidate) []ArtistCandidate {
+	best := 0
+	var matches []ArtistCandidate
+	for _, c := range candidates {
+		strength := matchStrength(key, normalizeArtistName(c.Name))
+		if strength == 0 {
+			continue
+		}
+		switch {
+		case best == 0 || strength < best:
+			best = strength
+			matches = []ArtistCandidate{c}
+		case strength == best:
+			matches = append(matches, c)
+		}
+	}
+	slices.SortFunc(matches, func(a, b ArtistCandidate) int {
+		if c := cmp.Compare(b.NumShows, a.NumShows); c != 0 {
+			return c
+		}
+		if c := cmp.Compare(a.Name, b.Name); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.ID, b.ID)
+	})
+	if len(matches) > maxArtistCandidates {
+		matches = matches[:maxArtistCandidates]
+	}
+	return matches
+}
+
+func matchStrength(key, name string) int {
+	switch {
+	case name == "":
+		return 0
+	case name == key:
+		return matchExact
+	case strings.HasPrefix(name, key):
+		return matchPrefix
+	case strings.Contains(" "+name, " "+key):
+		return matchWordPrefix
+	case strings.Contains(name, key):
+		return matchSubstring
+	}
+	// Allow roughly one typo per four characters, against the whole name or
+	// any single word of it.
+	limit := max(1, len([]rune(key))/4)
+	if levenshtein(key, name) <= limit {
+		return matchTypo
+	}
+	for _, word := range strings.Fields(name) {
+		if len(word) > 2 && levenshtein(key, word) <= limit {
+			return matchTypo
+		}
+	}
+	return 0
+}
+
+// normalizeArtistName lowercases s, spells out "&", drops a leading "the"
+// and reduces punctuation to single spaces so "The String Cheese Incident"
+// and "string-cheese incident" compare equal.
+func normalizeArtistName(s string) string {
+	s = strings.ReplaceAll(strings.ToLower(s), "&", " and ")
+	var b strings.Builder
+	for _, r := range s {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		} else {
+			b.WriteRune(' ')
+		}
+	}
+	words := strings.Fields(b.String())
+	if len(words) > 1 && words[0] == "the" {
+		words = words[1:]
+	}
+	return strings.Join(words, " ")
+}
+
+// levenshtein returns the edit distance between a and b.
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
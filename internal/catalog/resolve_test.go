@@ -0,0 +1,146 @@
+package catalog
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/jmagar/nugs-cli/internal/model"
+	"github.com/jmagar/nugs-cli/internal/testutil"
+)
+
+func resolveDeps(artists ...model.Artist) *Deps {
+	resp := &model.ArtistListResp{}
+	resp.Response.Artists = artists
+	return &Deps{
+		FetchArtistList: func(context.Context) (*model.ArtistListResp, error) { return resp, nil },
+	}
+}
+
+var resolveArtists = []model.Artist{
+	{ArtistID: 1125, ArtistName: "Billy Strings", NumShows: 400},
+	{ArtistID: 1200, ArtistName: "Billy & the Kids", NumShows: 90},
+	{ArtistID: 461, ArtistName: "Goose", NumShows: 300},
+	{ArtistID: 62, ArtistName: "The String Cheese Incident", NumShows: 900},
+	{ArtistID: 1045, ArtistName: "Goose Creek Symphony", NumShows: 5},
+}
+
+func TestResolveArtistID(t *testing.T) {
+	testutil.WithTempHome(t)
+	cfg := &model.Config{ArtistAliases: map[string]string{"SCI": "62"}}
+	deps := resolveDeps(resolveArtists...)
+
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{query: "1125", want: "1125"},
+		{query: "sci", want: "62"},
+		{query: "goose", want: "461"},
+		{query: "GOOSE", want: "461"},
+		{query: "string cheese incident", want: "62"},
+		{query: "billy strings", want: "1125"},
+		{query: "Goose Creek Symphony", want: "1045"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.query, func(t *testing.T) {
+			got, err := ResolveArtistID(context.Background(), tc.query, cfg, "json", nil, deps)
+			if err != nil {
+				t.Fatalf("ResolveArtistID(%q) error = %v", tc.query, err)
+			}
+			if got != tc.want {
+				t.Fatalf("ResolveArtistID(%q) = %q, want %q", tc.query, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestResolveArtistIDAmbiguousWithoutPrompt(t *testing.T) {
+	testutil.WithTempHome(t)
+	_, err := ResolveArtistID(context.Background(), "billy", &model.Config{}, "json", nil, resolveDeps(resolveArtists...))
+	var ambiguous *AmbiguousArtistError
+	if !errors.As(err, &ambiguous) {
+		t.Fatalf("error = %v, want AmbiguousArtistError", err)
+	}
+	if len(ambiguous.Candidates) != 2 || ambiguous.Candidates[0].ID != 1125 {
+		t.Fatalf("candidates = %+v, want Billy Strings first of two", ambiguous.Candidates)
+	}
+	if !strings.Contains(err.Error(), "Billy Strings (1125)") || !strings.Contains(err.Error(), "Billy & the Kids (1200)") {
+		t.Fatalf("error = %q, want both candidates listed", err)
+	}
+}
+
+func TestResolveArtistIDFuzzyMatchNeedsConfirmation(t *testing.T) {
+	testutil.WithTempHome(t)
+	deps := resolveDeps(resolveArtists...)
+	for query, want := range map[string]string{"strings": "1125", "goose creek": "1045", "billy strngs": "1125"} {
+		_, err := ResolveArtistID(context.Background(), query, &model.Config{}, "", nil, deps)
+		var ambiguous *AmbiguousArtistError
+		if !errors.As(err, &ambiguous) || len(ambiguous.Candidates) != 1 || strconv.Itoa(ambiguous.Candidates[0].ID) != want {
+			t.Errorf("ResolveArtistID(%q) without a prompt = %v, want an error suggesting %s", query, err, want)
+		}
+
+		var out bytes.Buffer
+		got, err := ResolveArtistID(context.Background(), query, &model.Config{}, "", NewArtistPrompt(strings.NewReader("1\n"), &out), deps)
+		if err != nil || got != want {
+			t.Errorf("ResolveArtistID(%q) confirmed = %q, %v; want %s", query, got, err, want)
+		}
+		if !strings.Contains(out.String(), "Closest match") {
+			t.Errorf("prompt for %q = %q, want a confirmation", query, out.String())
+		}
+		if _, err := ResolveArtistID(context.Background(), query, &model.Config{}, "", NewArtistPrompt(strings.NewReader("\n"), &out), deps); err == nil {
+			t.Errorf("ResolveArtistID(%q) accepted a declined match", query)
+		}
+	}
+}
+
+func TestResolveArtistIDPrompts(t *testing.T) {
+	testutil.WithTempHome(t)
+	var out bytes.Buffer
+	prompt := NewArtistPrompt(strings.NewReader("2\n"), &out)
+	got, err := ResolveArtistID(context.Background(), "billy", &model.Config{}, "json", prompt, resolveDeps(resolveArtists...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "1200" {
+		t.Fatalf("ResolveArtistID() = %q, want 1200", got)
+	}
+	if !strings.Contains(out.String(), " 1) Billy Strings (1125) - 400 shows") {
+		t.Fatalf("prompt output = %q, want numbered candidates", out.String())
+	}
+
+	cancel := NewArtistPrompt(strings.NewReader("\n"), &out)
+	if _, err := ResolveArtistID(context.Background(), "billy", &model.Config{}, "json", cancel, resolveDeps(resolveArtists...)); err == nil {
+		t.Fatal("empty answer should cancel the selection")
+	}
+}
+
+func TestResolveArtistIDNoMatch(t *testing.T) {
+	testutil.WithTempHome(t)
+	_, err := ResolveArtistID(context.Background(), "phish", &model.Config{}, "json", nil, resolveDeps(resolveArtists...))
+	if err == nil || !strings.Contains(err.Error(), `no artist matches "phish"`) {
+		t.Fatalf("error = %v, want no match", err)
+	}
+
+	_, err = ResolveArtistID(context.Background(), "goose", &model.Config{}, "json", nil, &Deps{})
+	if err == nil || !strings.Contains(err.Error(), "no cached artist list") {
+		t.Fatalf("error = %v, want missing cache error", err)
+	}
+}
+
+func TestResolveArtistIDRejectsMalformedAlias(t *testing.T) {
+	testutil.WithTempHome(t)
+	cfg := &model.Config{ArtistAliases: map[string]string{"billy": "-5", "goose": "+12"}}
+	for _, query := range []string{"billy", "goose"} {
+		got, err := ResolveArtistID(context.Background(), query, cfg, "json", nil, resolveDeps(resolveArtists...))
+		if err == nil || !strings.Contains(err.Error(), "not a positive numeric artist ID") {
+			t.Fatalf("ResolveArtistID(%q) = %q, %v; want the malformed alias rejected", query, got, err)
+		}
+	}
+	if got, err := ResolveArtistID(context.Background(), "-1125", &model.Config{}, "json", nil, resolveDeps(resolveArtists...)); err == nil {
+		t.Fatalf("ResolveArtistID(%q) = %q, want an error for a signed ID", "-1125", got)
+	}
+}
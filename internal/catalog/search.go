@@ -0,0 +1,170 @@
+package catalog
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+
+	"github.com/jmagar/nugs-cli/internal/cache"
+	"github.com/jmagar/nugs-cli/internal/model"
+	"github.com/jmagar/nugs-cli/internal/search"
+	"github.com/jmagar/nugs-cli/internal/setlist"
+	"github.com/jmagar/nugs-cli/internal/ui"
+)
+
+// CatalogSearch runs a `nugs search` query over the local search index.
+func CatalogSearch(args []string, jsonLevel string) error {
+	query, err := search.Parse(args...)
+	if err != nil {
+		return err
+	}
+	index, err := loadSearchIndex()
+	if err != nil {
+		return err
+	}
+	results := search.Run(index, query)
+	total := len(results)
+	if len(results) > query.Limit {
+		results = results[:query.Limit]
+	}
+
+	if jsonLevel != "" {
+		shows := make([]map[string]any, len(results))
+		for i, show := range results {
+			entry := map[string]any{
+				"containerID": show.ContainerID,
+				"artistID":    show.ArtistID,
+				"artistName":  show.ArtistName,
+				"date":        show.Date,
+				"title":       show.Title,
+				"venue":       show.Venue,
+				"venueCity":   show.City,
+				"venueState":  show.State,
+			}
+			if show.Media != model.MediaTypeUnknown {
+				entry["media"] = show.Media.String()
+			}
+			shows[i] = entry
+		}
+		return PrintJSON(map[string]any{
+			"query": strings.Join(args, " "),
+			"shows": shows,
+			"total": total,
+			"limit": query.Limit,
+		})
+	}
+
+	if total == 0 {
+		ui.PrintInfo(fmt.Sprintf("No shows match %q", strings.Join(args, " ")))
+		return nil
+	}
+	ui.PrintHeader("Search Results")
+	table := ui.NewTable([]ui.TableColumn{
+		{Header: "ID", Width: 8, Align: "right"},
+		{Header: "Artist", Width: 24, Align: "left"},
+		{Header: "Date", Width: 10, Align: "left"},
+		{Header: "Venue", Width: 32, Align: "left"},
+		{Header: "Location", Width: 24, Align: "left"},
+		{Header: "Media", Width: 6, Align: "left"},
+	})
+	for _, show := range results {
+		venue := show.Venue
+		if venue == "" {
+			venue = show.Title
+		}
+		location := show.City
+		if show.State != "" {
+			location = strings.TrimPrefix(location+", "+show.State, ", ")
+		}
+		media := ""
+		if show.Media != model.MediaTypeUnknown {
+			media = show.Media.String()
+		}
+		table.AddRow(fmt.Sprintf("%d", show.ContainerID), show.ArtistName, show.Date, venue, location, media)
+	}
+	table.Print()
+	if total > len(results) {
+		fmt.Printf("\nShowing %d of %d matches (add limit:N to see more)\n", len(results), total)
+	} else {
+		fmt.Printf("\n%d match(es)\n", total)
+	}
+	return nil
+}
+
+// loadSearchIndex returns the cached search index, rebuilding it when it is
+// missing, from an older build, or older than the catalog data behind it.
+func loadSearchIndex() (*model.SearchIndex, error) {
+	index, err := cache.ReadSearchIndex()
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		fmt.Fprintf(os.Stderr, "warning: rebuilding unreadable search index: %v\n", err)
+	}
+	if err == nil && index.Version == search.IndexVersion && !searchIndexStale(index) {
+		return index, nil
+	}
+	return rebuildSearchIndex()
+}
+
+func searchIndexStale(index *model.SearchIndex) bool {
+	if meta, err := cache.ReadCacheMeta(); err == nil && meta != nil && meta.LastUpdated.After(index.BuiltAt) {
+		return true
+	}
+	if _, meta, err := cache.ReadFullCatalog(); err == nil && meta != nil {
+		if meta.FullCrawlAt.After(index.BuiltAt) || meta.LastIncrementalAt.After(index.BuiltAt) {
+			return true
+		}
+	}
+	if setlists, err := cache.ReadSetlistIndex(); err == nil && setlists.BuiltAt.After(index.BuiltAt) {
+		return true
+	}
+	return false
+}
+
+// rebuildSearchIndex indexes the containers index, the latest catalog and,
+// when a full crawl has run, the full catalog index, each taking precedence
+// over the one before. Song titles come from the cached setlist index.
+func rebuildSearchIndex() (*model.SearchIndex, error) {
+	var shows []model.FullCatalogShow
+	if containers, err := cache.ReadContainersIndex(); err == nil {
+		for id, entry := range containers.Containers {
+			shows = append(shows, model.FullCatalogShow{
+				ContainerID:     id,
+				ArtistID:        entry.ArtistID,
+				ArtistName:      entry.ArtistName,
+				ContainerInfo:   entry.ContainerInfo,
+				PerformanceDate: entry.PerformanceDate,
+			})
+		}
+	} else {
+		fmt.Fprintf(os.Stderr, "warning: skipping containers index: %v\n", err)
+	}
+	latest, latestErr := cache.ReadCatalogCache()
+	if latestErr == nil {
+		for _, entry := range recentItemEntries(latest) {
+			shows = append(shows, entry)
+		}
+	}
+	full, _, err := cache.ReadFullCatalog()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read full catalog index: %w", err)
+	}
+	if full != nil {
+		for _, entry := range full.Containers {
+			shows = append(shows, entry)
+		}
+	}
+	if latestErr != nil && full == nil && len(shows) == 0 {
+		return nil, latestErr
+	}
+	var songs []model.SongPerformance
+	if setlists, err := cache.ReadSetlistIndex(); err == nil && setlists.Version == setlist.IndexVersion {
+		songs = setlists.Performances
+	}
+
+	index := search.Build(shows, songs)
+	if err := cache.WriteSearchIndex(index); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to write search index: %v\n", err)
+	}
+	return index, nil
+}
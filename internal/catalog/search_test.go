@@ -0,0 +1,104 @@
+package catalog
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/jmagar/nugs-cli/internal/cache"
+	"github.com/jmagar/nugs-cli/internal/model"
+	"github.com/jmagar/nugs-cli/internal/setlist"
+	"github.com/jmagar/nugs-cli/internal/testutil"
+)
+
+type searchOutput struct {
+	Total int `json:"total"`
+	Shows []struct {
+		ContainerID int    `json:"containerID"`
+		Venue       string `json:"venue"`
+		Media       string `json:"media"`
+	} `json:"shows"`
+}
+
+func runSearch(t *testing.T, args ...string) searchOutput {
+	t.Helper()
+	stdout := testutil.CaptureStdout(t, func() {
+		if err := CatalogSearch(args, "standard"); err != nil {
+			t.Fatalf("CatalogSearch(%q) error = %v", args, err)
+		}
+	})
+	var out searchOutput
+	if err := json.Unmarshal([]byte(stdout), &out); err != nil {
+		t.Fatalf("invalid JSON %q: %v", stdout, err)
+	}
+	return out
+}
+
+func TestCatalogUpdateBuildsSearchIndex(t *testing.T) {
+	testutil.WithTempHome(t)
+	latest := buildUpdateCatalog([]showSpec{
+		{1001, 500, "Billy Strings", "2025-01-01", "Ryman Auditorium"},
+		{1002, 461, "Goose", "2025-02-01", "Radio City"},
+	})
+	testutil.CaptureStdout(t, func() {
+		if err := CatalogUpdate(context.Background(), "", makeDeps(func(context.Context) (*model.LatestCatalogResp, error) {
+			return latest, nil
+		})); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if _, err := cache.ReadSearchIndex(); err != nil {
+		t.Fatalf("search index not written by update: %v", err)
+	}
+
+	out := runSearch(t, "artist:billy", "ryman")
+	if out.Total != 1 || out.Shows[0].ContainerID != 1001 {
+		t.Fatalf("search = %+v, want container 1001", out)
+	}
+}
+
+func TestCatalogSearchRebuildsStaleIndexFromFullCatalog(t *testing.T) {
+	testutil.WithTempHome(t)
+	if err := cache.WriteCatalogCache(buildUpdateCatalog([]showSpec{{1001, 500, "Billy Strings", "2025-01-01", "Show A"}}), 0, noDurationFmt); err != nil {
+		t.Fatal(err)
+	}
+	if out := runSearch(t, "venue:red rocks"); out.Total != 0 {
+		t.Fatalf("search before full crawl = %+v, want no matches", out)
+	}
+
+	index := &model.FullCatalogIndex{Containers: map[int]model.FullCatalogShow{
+		1001: {ContainerID: 1001, ArtistID: 500, ArtistName: "Billy Strings", PerformanceDate: "2025-01-01", VenueName: "Red Rocks Amphitheatre", Media: model.MediaTypeAudio, Downloadable: true},
+		900:  {ContainerID: 900, ArtistID: 500, ArtistName: "Billy Strings", PerformanceDate: "2019-08-01", VenueName: "Red Rocks Amphitheatre", Media: model.MediaTypeVideo, Downloadable: true},
+	}}
+	meta := &model.FullCatalogMeta{FullCrawlAt: time.Now().Add(time.Second)}
+	if err := cache.WriteFullCatalog(index, meta); err != nil {
+		t.Fatal(err)
+	}
+
+	out := runSearch(t, "venue:red rocks", "media:audio")
+	if out.Total != 1 || out.Shows[0].ContainerID != 1001 || out.Shows[0].Media != "audio" {
+		t.Fatalf("search after full crawl = %+v, want container 1001 (audio)", out)
+	}
+}
+
+func TestCatalogSearchIndexesContainersAndSongs(t *testing.T) {
+	testutil.WithTempHome(t)
+	if err := cache.WriteCatalogCache(buildUpdateCatalog([]showSpec{{1001, 500, "Billy Strings", "2025-01-01", "Show A"}}), 0, noDurationFmt); err != nil {
+		t.Fatal(err)
+	}
+	if err := cache.WriteSetlistIndex(&model.SetlistIndex{
+		Version: setlist.IndexVersion,
+		BuiltAt: time.Now().Add(time.Second),
+		Performances: []model.SongPerformance{
+			{ContainerID: 1001, ArtistID: 500, ArtistName: "Billy Strings", Date: "2025-01-01", SongTitle: "Dust in a Baggie"},
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	out := runSearch(t, "song:dust", "artist:billy")
+	if out.Total != 1 || out.Shows[0].ContainerID != 1001 {
+		t.Fatalf("song search = %+v, want container 1001", out)
+	}
+}
@@ -0,0 +1,358 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jmagar/nugs-cli/internal/cache"
+	"github.com/jmagar/nugs-cli/internal/model"
+	"github.com/jmagar/nugs-cli/internal/setlist"
+	"github.com/jmagar/nugs-cli/internal/ui"
+)
+
+// songArtist is one artist's cached shows and indexed song performances.
+type songArtist struct {
+	id    string
+	name  string
+	shows map[int]*model.AlbArtResp
+	perfs []model.SongPerformance
+}
+
+// loadSongArtist makes sure the artist's metadata is cached, then returns
+// the artist's performances from the setlist index.
+func loadSongArtist(ctx context.Context, artistID string, deps *Deps) (*songArtist, error) {
+	id, err := strconv.Atoi(artistID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid artist ID %q", artistID)
+	}
+	pages, _, shardUpdatedAt, err := cache.ReadFullCatalogArtist(artistID)
+	if err != nil || !cache.FullCatalogArtistFresh(shardUpdatedAt, ArtistMetaCacheTTL) {
+		if deps.GetArtistMetaCached == nil {
+			return nil, fmt.Errorf("GetArtistMetaCached callback not configured")
+		}
+		pages, _, _, err = deps.GetArtistMetaCached(ctx, artistID, ArtistMetaCacheTTL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get artist metadata: %w", err)
+		}
+	}
+	shows, artistName := CollectArtistShows(pages)
+	if len(shows) == 0 {
+		return nil, fmt.Errorf("no shows found for artist %s", artistID)
+	}
+
+	index, err := loadSetlistIndex()
+	if err != nil {
+		return nil, err
+	}
+	artist := &songArtist{
+		id:    artistID,
+		name:  artistName,
+		shows: make(map[int]*model.AlbArtResp, len(shows)),
+		perfs: setlist.ForArtist(index, id),
+	}
+	for _, show := range shows {
+		artist.shows[show.ContainerID] = show
+	}
+	if len(artist.perfs) == 0 {
+		return nil, fmt.Errorf("no setlists found for %s: its cached shows list no songs", artistName)
+	}
+	return artist, nil
+}
+
+// SongList prints every performance of the artist's song matching query,
+// newest first, marking the shows already downloaded. With missingOnly set
+// only performances from shows not yet downloaded are listed.
+func SongList(ctx context.Context, artistID, query string, cfg *model.Config, jsonLevel string, missingOnly bool, deps *Deps) error {
+	artist, err := loadSongArtist(ctx, artistID, deps)
+	if err != nil {
+		return err
+	}
+	perfs, titles := setlist.Find(artist.perfs, query)
+	if len(perfs) == 0 {
+		return fmt.Errorf("no song matching %q found for %s", query, artist.name)
+	}
+
+	presence := BuildArtistPresenceIndex(ctx, artist.name, cfg, deps, model.MediaTypeAudio)
+	downloaded := make(map[int]bool)
+	for _, perf := range perfs {
+		if _, done := downloaded[perf.ContainerID]; done {
+			continue
+		}
+		show := artist.shows[perf.ContainerID]
+		downloaded[perf.ContainerID] = show != nil && IsShowDownloaded(ctx, show, presence, cfg, deps)
+	}
+	total, have := len(perfs), 0
+	listed := perfs[:0:0]
+	for _, perf := range perfs {
+		if downloaded[perf.ContainerID] {
+			have++
+			if missingOnly {
+				continue
+			}
+		}
+		listed = append(listed, perf)
+	}
+
+	if jsonLevel != "" {
+		entries := make([]map[string]any, len(listed))
+		for i, perf := range listed {
+			entries[i] = map[string]any{
+				"containerID": perf.ContainerID,
+				"date":        perf.Date,
+				"venue":       perf.Venue,
+				"songTitle":   perf.SongTitle,
+				"trackID":     perf.TrackID,
+				"setNum":      perf.SetNum,
+				"trackNum":    perf.TrackNum,
+				"duration":    perf.Duration,
+				"downloaded":  downloaded[perf.ContainerID],
+			}
+		}
+		return PrintJSON(map[string]any{
+			"artistID":     artist.id,
+			"artistName":   artist.name,
+			"query":        query,
+			"songs":        titles,
+			"performances": entries,
+			"total":        total,
+			"downloaded":   have,
+			"missing":      total - have,
+		})
+	}
+
+	ui.PrintHeader(fmt.Sprintf("%s - %s", artist.name, strings.Join(titles, ", ")))
+	if len(listed) == 0 {
+		ui.PrintSuccess(fmt.Sprintf("All %d performance(s) are from downloaded shows", total))
+		return nil
+	}
+	table := ui.NewTable([]ui.TableColumn{
+		{Header: "ID", Width: 8, Align: "right"},
+		{Header: "Date", Width: 10, Align: "left"},
+		{Header: "Venue", Width: 32, Align: "left"},
+		{Header: "Song", Width: 28, Align: "left"},
+		{Header: "Set", Width: 3, Align: "right"},
+		{Header: "Length", Width: 7, Align: "right"},
+		{Header: "", Width: 1, Align: "left"},
+	})
+	for _, perf := range listed {
+		status := fmt.Sprintf("%s%s%s", ui.ColorRed, ui.SymbolCross, ui.ColorReset)
+		if downloaded[perf.ContainerID] {
+			status = fmt.Sprintf("%s%s%s", ui.ColorGreen, ui.SymbolCheck, ui.ColorReset)
+		}
+		table.AddRow(strconv.Itoa(perf.ContainerID), perf.Date, perf.Venue, perf.SongTitle,
+			setLabel(perf.SetNum), trackLength(perf.Duration), status)
+	}
+	table.Print()
+	fmt.Printf("\n%d performance(s) in %d show(s): %s%d downloaded%s, %s%d missing%s\n",
+		total, len(downloaded), ui.ColorGreen, have, ui.ColorReset, ui.ColorRed, total-have, ui.ColorReset)
+	return nil
+}
+
+// SongStats prints how often the artist played each song, and a per-year
+// summary. A non-empty year (YYYY) restricts both to that year.
+func SongStats(ctx context.Context, artistID, year string, cfg *model.Config, jsonLevel string, deps *Deps) error {
+	if year != "" {
+		if n, err := strconv.Atoi(year); err != nil || len(year) != 4 || n < 1900 {
+			return fmt.Errorf("invalid year %q: use YYYY", year)
+		}
+	}
+	artist, err := loadSongArtist(ctx, artistID, deps)
+	if err != nil {
+		return err
+	}
+	perfs := artist.perfs
+	if year != "" {
+		perfs = setlist.InYear(perfs, year)
+		if len(perfs) == 0 {
+			return fmt.Errorf("no setlists found for %s in %s", artist.name, year)
+		}
+	}
+	songs := setlist.Stats(perfs)
+	years := setlist.Years(perfs)
+
+	if jsonLevel != "" {
+		return PrintJSON(map[string]any{
+			"artistID":   artist.id,
+			"artistName": artist.name,
+			"year":       year,
+			"songs":      songs,
+			"years":      years,
+		})
+	}
+
+	title := artist.name + " - Song Stats"
+	if year != "" {
+		title += " (" + year + ")"
+	}
+	ui.PrintHeader(title)
+	table := ui.NewTable([]ui.TableColumn{
+		{Header: "Song", Width: 36, Align: "left"},
+		{Header: "Plays", Width: 6, Align: "right"},
+		{Header: "Shows", Width: 6, Align: "right"},
+		{Header: "First", Width: 10, Align: "left"},
+		{Header: "Last", Width: 10, Align: "left"},
+	})
+	for _, song := range songs {
+		table.AddRow(song.Title, strconv.Itoa(song.Plays), strconv.Itoa(song.Shows), song.First, song.Last)
+	}
+	table.Print()
+
+	ui.PrintSection("By Year")
+	yearTable := ui.NewTable([]ui.TableColumn{
+		{Header: "Year", Width: 4, Align: "left"},
+		{Header: "Shows", Width: 6, Align: "right"},
+		{Header: "Plays", Width: 6, Align: "right"},
+		{Header: "Songs", Width: 6, Align: "right"},
+		{Header: "Most Played", Width: 36, Align: "left"},
+	})
+	for _, y := range years {
+		yearTable.AddRow(y.Year, strconv.Itoa(y.Shows), strconv.Itoa(y.Plays), strconv.Itoa(y.Songs),
+			fmt.Sprintf("%s (%d)", y.TopSong, y.TopPlays))
+	}
+	yearTable.Print()
+	fmt.Printf("\n%d song(s), %d performance(s)\n", len(songs), len(perfs))
+	return nil
+}
+
+// SongGrab downloads every performance of the artist's song matching query
+// into one "<Artist> - <Song>" folder, tagged with each track's own show.
+func SongGrab(ctx context.Context, artistID, query string, cfg *model.Config, streamParams *model.StreamParams, jsonLevel string, deps *Deps) error {
+	if deps.Tracks == nil {
+		return fmt.Errorf("Tracks callback not configured")
+	}
+	artist, err := loadSongArtist(ctx, artistID, deps)
+	if err != nil {
+		return err
+	}
+	perfs, titles := setlist.Find(artist.perfs, query)
+	if len(perfs) == 0 {
+		return fmt.Errorf("no song matching %q found for %s", query, artist.name)
+	}
+
+	// Oldest first, so the folder plays in chronological order.
+	tracks := make([]model.ShowTrack, 0, len(perfs))
+	for i := len(perfs) - 1; i >= 0; i-- {
+		if track := showTrack(artist.shows[perfs[i].ContainerID], perfs[i]); track.Track != nil {
+			tracks = append(tracks, track)
+		}
+	}
+	if len(tracks) == 0 {
+		return fmt.Errorf("no downloadable tracks found for %q", query)
+	}
+	name := fmt.Sprintf("%s - %s", artist.name, titles[0])
+	if jsonLevel == "" {
+		ui.PrintInfo(fmt.Sprintf("Grabbing %d performance(s) of %s", len(tracks), strings.Join(titles, ", ")))
+	}
+	return deps.Tracks(ctx, name, tracks, cfg, streamParams)
+}
+
+// showTrack finds the performance's track in its cached show.
+func showTrack(show *model.AlbArtResp, perf model.SongPerformance) model.ShowTrack {
+	if show == nil {
+		return model.ShowTrack{}
+	}
+	tracks := show.Songs
+	if len(tracks) == 0 {
+		tracks = show.Tracks
+	}
+	for i := range tracks {
+		track := &tracks[i]
+		if (perf.TrackID != 0 && track.TrackID == perf.TrackID) ||
+			(perf.TrackID == 0 && track.SetNum == perf.SetNum && track.TrackNum == perf.TrackNum && track.SongTitle == perf.SongTitle) {
+			return model.ShowTrack{Track: track, Show: show}
+		}
+	}
+	return model.ShowTrack{}
+}
+
+func setLabel(setNum int) string {
+	if setNum <= 0 {
+		return ""
+	}
+	return strconv.Itoa(setNum)
+}
+
+func trackLength(seconds int) string {
+	if seconds <= 0 {
+		return ""
+	}
+	return fmt.Sprintf("%d:%02d", seconds/60, seconds%60)
+}
+
+// loadSetlistIndex returns the cached setlist index, rebuilding it when it is
+// missing or from an older build. Artists whose metadata changed since they
+// were indexed are re-read; the rest are kept as indexed.
+func loadSetlistIndex() (*model.SetlistIndex, error) {
+	index, err := cache.ReadSetlistIndex()
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		fmt.Fprintf(os.Stderr, "warning: rebuilding unreadable setlist index: %v\n", err)
+	}
+	if err != nil || index.Version != setlist.IndexVersion {
+		return rebuildSetlistIndex()
+	}
+	artists, err := cache.CachedArtistMeta()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cached artists: %w", err)
+	}
+	var changed, dropped []string
+	for id, modTime := range artists {
+		if indexed, ok := index.Artists[id]; !ok || modTime.After(indexed) {
+			changed = append(changed, id)
+		}
+	}
+	for id := range index.Artists {
+		if _, ok := artists[id]; !ok {
+			dropped = append(dropped, id)
+		}
+	}
+	if len(changed) == 0 && len(dropped) == 0 {
+		return index, nil
+	}
+	return refreshSetlistIndex(index, artists, changed, dropped)
+}
+
+// rebuildSetlistIndex indexes every artist with cached metadata.
+func rebuildSetlistIndex() (*model.SetlistIndex, error) {
+	artists, err := cache.CachedArtistMeta()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cached artists: %w", err)
+	}
+	return refreshSetlistIndex(nil, artists, slices.Collect(maps.Keys(artists)), nil)
+}
+
+// refreshSetlistIndex re-reads the changed artists from both the artist
+// metadata cache and the full catalog shard, drops the dropped ones, and
+// writes the result. artists holds the current metadata modification times.
+func refreshSetlistIndex(index *model.SetlistIndex, artists map[string]time.Time, changed, dropped []string) (*model.SetlistIndex, error) {
+	pages := make(map[string][]*model.ArtistMeta, len(changed))
+	indexed := map[string]time.Time{}
+	if index != nil {
+		maps.Copy(indexed, index.Artists)
+	}
+	for _, id := range dropped {
+		delete(indexed, id)
+	}
+	for _, id := range changed {
+		if shard, _, _, err := cache.ReadFullCatalogArtist(id); err == nil {
+			pages[id] = append(pages[id], shard...)
+		}
+		if cached, _, err := cache.ReadArtistMetaCache(id); err == nil {
+			pages[id] = append(pages[id], cached...)
+		}
+		indexed[id] = artists[id]
+	}
+	index = setlist.Refresh(index, pages, dropped)
+	index.Artists = indexed
+	if err := cache.WriteSetlistIndex(index); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to write setlist index: %v\n", err)
+	}
+	return index, nil
+}
@@ -0,0 +1,201 @@
+package catalog
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/jmagar/nugs-cli/internal/cache"
+	"github.com/jmagar/nugs-cli/internal/helpers"
+	"github.com/jmagar/nugs-cli/internal/model"
+	"github.com/jmagar/nugs-cli/internal/testutil"
+)
+
+func songShow(id int, date string, songs ...string) *model.AlbArtResp {
+	show := &model.AlbArtResp{ContainerID: id, ArtistID: 1125, ArtistName: "Billy Strings", PerformanceDate: date, ContainerInfo: date + " Show", VenueName: "Venue " + date}
+	for i, title := range songs {
+		show.Songs = append(show.Songs, model.Track{TrackID: id*10 + i, SongTitle: title, SetNum: 1, TrackNum: i + 1, TotalRunningTime: 300})
+	}
+	return show
+}
+
+// songDeps serves the shows as artist 1125's metadata and caches them the
+// way the real callback does.
+func songDeps(t *testing.T, shows ...*model.AlbArtResp) *Deps {
+	t.Helper()
+	page := &model.ArtistMeta{}
+	page.Response.Containers = shows
+	return &Deps{
+		GetArtistMetaCached: func(_ context.Context, artistID string, _ time.Duration) ([]*model.ArtistMeta, bool, bool, error) {
+			pages := []*model.ArtistMeta{page}
+			if err := cache.WriteArtistMetaCache(artistID, pages); err != nil {
+				t.Fatal(err)
+			}
+			return pages, false, false, nil
+		},
+	}
+}
+
+func TestSongListMarksDownloadedShows(t *testing.T) {
+	testutil.WithTempHome(t)
+	shows := []*model.AlbArtResp{
+		songShow(1, "2023-06-10", "Dust in a Baggie", "Meet Me at the Creek"),
+		songShow(2, "2023-09-01", "Meet Me at the Creek"),
+		songShow(3, "2022-12-31", "Away From the Mire"),
+	}
+	cfg := &model.Config{OutPath: t.TempDir()}
+	downloaded := filepath.Join(cfg.OutPath, helpers.ArtistFolderName(cfg, "Billy Strings"), helpers.ShowRelativePath(cfg, shows[0]))
+	if err := os.MkdirAll(downloaded, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	var out struct {
+		Songs        []string `json:"songs"`
+		Total        int      `json:"total"`
+		Downloaded   int      `json:"downloaded"`
+		Performances []struct {
+			ContainerID int  `json:"containerID"`
+			Downloaded  bool `json:"downloaded"`
+		} `json:"performances"`
+	}
+	stdout := testutil.CaptureStdout(t, func() {
+		if err := SongList(context.Background(), "1125", "meet me at the creek", cfg, "standard", false, songDeps(t, shows...)); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if err := json.Unmarshal([]byte(stdout), &out); err != nil {
+		t.Fatalf("invalid JSON %q: %v", stdout, err)
+	}
+	if out.Total != 2 || out.Downloaded != 1 || !slices.Equal(out.Songs, []string{"Meet Me at the Creek"}) {
+		t.Fatalf("SongList() = %+v, want 2 performances with 1 downloaded", out)
+	}
+	if p := out.Performances; p[0].ContainerID != 2 || p[0].Downloaded || p[1].ContainerID != 1 || !p[1].Downloaded {
+		t.Fatalf("performances = %+v, want show 2 missing then show 1 downloaded", p)
+	}
+
+	stdout = testutil.CaptureStdout(t, func() {
+		if err := SongList(context.Background(), "1125", "creek", cfg, "standard", true, songDeps(t, shows...)); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if err := json.Unmarshal([]byte(stdout), &out); err != nil {
+		t.Fatalf("invalid JSON %q: %v", stdout, err)
+	}
+	if len(out.Performances) != 1 || out.Performances[0].ContainerID != 2 {
+		t.Fatalf("missing-only performances = %+v, want show 2", out.Performances)
+	}
+}
+
+func TestSongListRebuildsIndexForNewMetadata(t *testing.T) {
+	testutil.WithTempHome(t)
+	cfg := &model.Config{OutPath: t.TempDir()}
+	testutil.CaptureStdout(t, func() {
+		if err := SongList(context.Background(), "1125", "mire", cfg, "standard", false, songDeps(t, songShow(3, "2022-12-31", "Away From the Mire"))); err != nil {
+			t.Fatal(err)
+		}
+	})
+	index, err := cache.ReadSetlistIndex()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Metadata cached after the index was built must be picked up.
+	later := index.BuiltAt.Add(time.Second)
+	deps := songDeps(t, songShow(3, "2022-12-31", "Away From the Mire"), songShow(4, "2024-01-01", "Away From the Mire"))
+	if _, _, _, err := deps.GetArtistMetaCached(context.Background(), "1125", 0); err != nil {
+		t.Fatal(err)
+	}
+	cachePath, err := cache.GetArtistMetaCachePath("1125")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(cachePath, later, later); err != nil {
+		t.Fatal(err)
+	}
+	artist, err := loadSongArtist(context.Background(), "1125", deps)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(artist.perfs) != 2 {
+		t.Fatalf("performances after new metadata = %+v, want 2", artist.perfs)
+	}
+}
+
+func TestSetlistIndexRereadsOnlyChangedArtists(t *testing.T) {
+	testutil.WithTempHome(t)
+	goose := songShow(5, "2023-09-01", "Arcadia")
+	goose.ArtistID, goose.ArtistName = 461, "Goose"
+	writePages := func(artistID string, shows ...*model.AlbArtResp) string {
+		t.Helper()
+		page := &model.ArtistMeta{}
+		page.Response.Containers = shows
+		if err := cache.WriteArtistMetaCache(artistID, []*model.ArtistMeta{page}); err != nil {
+			t.Fatal(err)
+		}
+		path, err := cache.GetArtistMetaCachePath(artistID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	writePages("1125", songShow(3, "2022-12-31", "Away From the Mire"))
+	goosePath := writePages("461", goose)
+	if _, err := loadSetlistIndex(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Goose's file is unreadable but unchanged, so it must not be re-read.
+	info, err := os.Stat(goosePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(goosePath, []byte("not json"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(goosePath, info.ModTime(), info.ModTime()); err != nil {
+		t.Fatal(err)
+	}
+	billyPath := writePages("1125", songShow(3, "2022-12-31", "Away From the Mire"), songShow(4, "2024-01-01", "Away From the Mire"))
+	later := info.ModTime().Add(time.Second)
+	if err := os.Chtimes(billyPath, later, later); err != nil {
+		t.Fatal(err)
+	}
+
+	index, err := loadSetlistIndex()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var shows []int
+	for _, perf := range index.Performances {
+		shows = append(shows, perf.ContainerID)
+	}
+	if !slices.Equal(shows, []int{4, 5, 3}) {
+		t.Fatalf("indexed shows = %v, want billy's new show added and goose kept", shows)
+	}
+}
+
+func TestSongGrabDownloadsMatchingTracksOldestFirst(t *testing.T) {
+	testutil.WithTempHome(t)
+	deps := songDeps(t,
+		songShow(1, "2023-06-10", "Dust in a Baggie", "Meet Me at the Creek"),
+		songShow(2, "2021-09-01", "Meet Me at the Creek >"),
+	)
+	var gotName string
+	var gotTracks []model.ShowTrack
+	deps.Tracks = func(_ context.Context, name string, tracks []model.ShowTrack, _ *model.Config, _ *model.StreamParams) error {
+		gotName, gotTracks = name, tracks
+		return nil
+	}
+	if err := SongGrab(context.Background(), "1125", "meet me at the creek", &model.Config{}, &model.StreamParams{}, "standard", deps); err != nil {
+		t.Fatal(err)
+	}
+	if gotName != "Billy Strings - Meet Me at the Creek" {
+		t.Fatalf("folder name = %q", gotName)
+	}
+	if len(gotTracks) != 2 || gotTracks[0].Track.TrackID != 20 || gotTracks[1].Track.TrackID != 11 || gotTracks[1].Show.ContainerID != 1 {
+		t.Fatalf("tracks = %+v, want track 20 (show 2) then 11 (show 1)", gotTracks)
+	}
+}
@@ -0,0 +1,182 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/jmagar/nugs-cli/internal/model"
+	"github.com/jmagar/nugs-cli/internal/transcode"
+	"github.com/jmagar/nugs-cli/internal/ui"
+	"github.com/jmagar/nugs-cli/internal/verify"
+)
+
+// TranscodeSummary is the JSON output of Transcode. Files lists only the
+// failures unless the extended or raw JSON level is requested.
+type TranscodeSummary struct {
+	Target    string            `json:"target"`
+	Path      string            `json:"path"`
+	Profiles  []string          `json:"profiles"`
+	Converted int               `json:"converted"`
+	Skipped   int               `json:"skipped"`
+	Failed    int               `json:"failed"`
+	Files     []TranscodeResult `json:"files"`
+}
+
+// TranscodeResult is one copy made (or not) by Transcode.
+type TranscodeResult struct {
+	Profile string `json:"profile"`
+	Source  string `json:"source"`
+	Dest    string `json:"dest"`
+	Status  string `json:"status"` // converted, skipped or failed
+	Error   string `json:"error,omitempty"`
+}
+
+// Transcode backfills transcode profiles for audio already downloaded under a
+// path or an artist's local folder. profileNames limits the run to those
+// profiles. Copies that are already up to date are skipped.
+func Transcode(ctx context.Context, target string, profileNames []string, cfg *model.Config, jsonLevel string, deps *Deps) error {
+	if len(cfg.TranscodeProfiles) == 0 {
+		return errors.New("no transcodeProfiles configured (see docs/CONFIG.md)")
+	}
+	profiles, err := transcode.Select(cfg.TranscodeProfiles, profileNames)
+	if err != nil {
+		return err
+	}
+	root, err := resolveVerifyPath(ctx, target, cfg, deps)
+	if err != nil {
+		return err
+	}
+	root, err = filepath.Abs(root)
+	if err != nil {
+		return err
+	}
+	jobs, err := transcodeJobs(root, profiles, cfg)
+	if err != nil {
+		return err
+	}
+
+	summary := TranscodeSummary{Target: target, Path: root, Files: []TranscodeResult{}}
+	for _, profile := range profiles {
+		summary.Profiles = append(summary.Profiles, profile.Name)
+	}
+	if jsonLevel == "" {
+		ui.PrintHeader(fmt.Sprintf("Transcoding %s", root))
+		ui.PrintInfo(fmt.Sprintf("%d copies to check for %s", len(jobs), strings.Join(summary.Profiles, ", ")))
+	}
+	results := transcode.Run(ctx, cfg.FfmpegNameStr, jobs, cfg.TranscodeConcurrency, func(result transcode.Result) {
+		if jsonLevel != "" || result.Skipped {
+			return
+		}
+		rel, relErr := filepath.Rel(root, result.Source)
+		if relErr != nil {
+			rel = result.Source
+		}
+		if result.Err != nil {
+			ui.PrintError(fmt.Sprintf("%s %s: %v", result.Profile.Name, rel, result.Err))
+		} else {
+			fmt.Printf("  %s %s\n", result.Profile.Name, rel)
+		}
+	})
+
+	verbose := jsonLevel == model.JSONLevelExtended || jsonLevel == model.JSONLevelRaw
+	for _, result := range results {
+		entry := TranscodeResult{Profile: result.Profile.Name, Source: result.Source, Dest: result.Dest}
+		switch {
+		case result.Err != nil:
+			summary.Failed++
+			entry.Status, entry.Error = "failed", result.Err.Error()
+		case result.Skipped:
+			summary.Skipped++
+			entry.Status = "skipped"
+		default:
+			summary.Converted++
+			entry.Status = "converted"
+		}
+		if verbose || result.Err != nil {
+			summary.Files = append(summary.Files, entry)
+		}
+	}
+
+	if jsonLevel != "" {
+		if err := PrintJSON(summary); err != nil {
+			return err
+		}
+	} else {
+		fmt.Println()
+		ui.PrintKeyValue("Converted", strconv.Itoa(summary.Converted), ui.ColorGreen)
+		ui.PrintKeyValue("Up to date", strconv.Itoa(summary.Skipped), ui.ColorReset)
+		ui.PrintKeyValue("Failed", strconv.Itoa(summary.Failed), ui.ColorRed)
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if summary.Failed > 0 {
+		return fmt.Errorf("%d of %d transcodes failed", summary.Failed, len(results))
+	}
+	return nil
+}
+
+// transcodeJobs plans copies of every audio file under root. Folders are
+// mirrored relative to outPath when root lies inside it, else relative to
+// root's parent. Profile output folders are not descended into, so copies
+// are never transcoded again.
+func transcodeJobs(root string, profiles []model.TranscodeProfile, cfg *model.Config) ([]transcode.Job, error) {
+	base := filepath.Dir(root)
+	if outPath, err := filepath.Abs(cfg.OutPath); err == nil {
+		if rel, err := filepath.Rel(outPath, root); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			base = outPath
+		}
+	}
+	skip := map[string]bool{}
+	for _, profile := range cfg.TranscodeProfiles {
+		if abs, err := filepath.Abs(profile.OutPath); err == nil {
+			skip[abs] = true
+		}
+	}
+
+	var (
+		dirs  []string
+		files = map[string][]string{}
+	)
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if skip[path] {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !verify.IsAudioFile(path) || strings.Contains(d.Name(), ".part.") {
+			return nil
+		}
+		dir := filepath.Dir(path)
+		if _, ok := files[dir]; !ok {
+			dirs = append(dirs, dir)
+		}
+		files[dir] = append(files[dir], path)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var jobs []transcode.Job
+	for _, dir := range dirs {
+		var show *model.AlbArtResp
+		for _, profile := range profiles {
+			if profile.FolderTemplate != "" {
+				show = transcode.ShowFromTags(files[dir][0])
+				break
+			}
+		}
+		jobs = append(jobs, transcode.Jobs(profiles, base, dir, files[dir], show)...)
+	}
+	return jobs, nil
+}
@@ -0,0 +1,79 @@
+package catalog
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/jmagar/nugs-cli/internal/model"
+	"github.com/jmagar/nugs-cli/internal/testutil"
+)
+
+func TestTranscodeBackfillsProfiles(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("test uses POSIX shell script, not portable to Windows")
+	}
+	testutil.WithTempHome(t)
+	ffmpeg := filepath.Join(t.TempDir(), "ffmpeg")
+	script := "#!/bin/sh\nwhile [ $# -gt 1 ]; do\n  if [ \"$1\" = \"-i\" ]; then in=\"$2\"; fi\n  shift\ndone\ncp \"$in\" \"$1\"\n"
+	if err := os.WriteFile(ffmpeg, []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	outPath := t.TempDir()
+	phone := filepath.Join(outPath, "phone")
+	cfg := &model.Config{
+		OutPath:           outPath,
+		FfmpegNameStr:     ffmpeg,
+		TranscodeProfiles: []model.TranscodeProfile{{Name: "phone", Codec: "aac", OutPath: phone}},
+	}
+	show := filepath.Join(outPath, "Goose", "Goose - Live")
+	if err := os.MkdirAll(show, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"01. A.flac", "02. B.flac", "cover.jpg"} {
+		if err := os.WriteFile(filepath.Join(show, name), []byte(name), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	run := func() TranscodeSummary {
+		t.Helper()
+		stdout := testutil.CaptureStdout(t, func() {
+			if err := Transcode(context.Background(), outPath, nil, cfg, model.JSONLevelExtended, &Deps{}); err != nil {
+				t.Fatal(err)
+			}
+		})
+		var summary TranscodeSummary
+		if err := json.Unmarshal([]byte(stdout), &summary); err != nil {
+			t.Fatalf("invalid JSON %q: %v", stdout, err)
+		}
+		return summary
+	}
+
+	first := run()
+	if first.Converted != 2 || first.Skipped != 0 || first.Failed != 0 || len(first.Files) != 2 {
+		t.Fatalf("first run = %+v", first)
+	}
+	want := filepath.Join(phone, "Goose", "Goose - Live", "01. A.m4a")
+	if data, err := os.ReadFile(want); err != nil || string(data) != "01. A.flac" {
+		t.Fatalf("copy %s = %q, %v", want, data, err)
+	}
+
+	// The profile folder sits inside outPath; its copies must not be
+	// transcoded again.
+	second := run()
+	if second.Converted != 0 || second.Skipped != 2 {
+		t.Fatalf("second run = %+v", second)
+	}
+}
+
+func TestTranscodeRequiresProfiles(t *testing.T) {
+	err := Transcode(context.Background(), t.TempDir(), nil, &model.Config{}, "", &Deps{})
+	if err == nil {
+		t.Fatal("Transcode() without profiles succeeded, want error")
+	}
+}
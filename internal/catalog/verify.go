@@ -0,0 +1,131 @@
+package catalog
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/jmagar/nugs-cli/internal/helpers"
+	"github.com/jmagar/nugs-cli/internal/model"
+	"github.com/jmagar/nugs-cli/internal/ui"
+	"github.com/jmagar/nugs-cli/internal/verify"
+)
+
+// VerifySummary is the JSON output of Verify. Files lists only the failures
+// unless the extended or raw JSON level is requested.
+type VerifySummary struct {
+	Target  string          `json:"target"`
+	Path    string          `json:"path"`
+	Checked int             `json:"checked"`
+	OK      int             `json:"ok"`
+	Corrupt int             `json:"corrupt"`
+	Short   int             `json:"short"`
+	Files   []verify.Result `json:"files"`
+}
+
+// resolveVerifyPath maps a verify target to a local path. Existing paths are
+// used as-is; numeric targets name an artist whose folder under outPath is
+// scanned.
+func resolveVerifyPath(ctx context.Context, target string, cfg *model.Config, deps *Deps) (string, error) {
+	if _, err := os.Stat(target); err == nil {
+		return target, nil
+	}
+	if _, err := strconv.Atoi(target); err != nil {
+		return "", fmt.Errorf("%q is neither an existing path nor an artist ID", target)
+	}
+	name, err := lookupArtistName(ctx, target, deps)
+	if err != nil {
+		return "", err
+	}
+	artistPath := filepath.Join(cfg.OutPath, helpers.ArtistFolderName(cfg, name))
+	if _, err := os.Stat(artistPath); err != nil {
+		return "", fmt.Errorf("no local folder for %s at %s", name, artistPath)
+	}
+	return artistPath, nil
+}
+
+// lookupArtistName resolves an artist ID from the cached catalog, falling
+// back to the artist metadata cache or API.
+func lookupArtistName(ctx context.Context, artistID string, deps *Deps) (string, error) {
+	name := resolveArtistName(artistID)
+	if name == "" && deps != nil && deps.GetArtistMetaCached != nil {
+		pages, _, _, err := deps.GetArtistMetaCached(ctx, artistID, ArtistMetaCacheTTL)
+		if err != nil {
+			return "", fmt.Errorf("failed to resolve artist %s: %w", artistID, err)
+		}
+		_, name = CollectArtistShows(pages)
+	}
+	if name == "" {
+		return "", fmt.Errorf("artist %s not found (run 'nugs catalog update' first)", artistID)
+	}
+	return name, nil
+}
+
+// Verify checks every audio file under a path or an artist's local folder and
+// reports corrupt or short files. It returns an error when any file fails so
+// scripts can rely on the exit status.
+func Verify(ctx context.Context, target string, cfg *model.Config, jsonLevel string, deps *Deps) error {
+	root, err := resolveVerifyPath(ctx, target, cfg, deps)
+	if err != nil {
+		return err
+	}
+	if jsonLevel == "" {
+		ui.PrintHeader(fmt.Sprintf("Verifying %s", root))
+	}
+
+	summary := VerifySummary{Target: target, Path: root, Files: []verify.Result{}}
+	results, err := verify.Scan(ctx, root, func(result verify.Result) {
+		if jsonLevel != "" || result.Status == verify.StatusOK {
+			return
+		}
+		rel, relErr := filepath.Rel(root, result.Path)
+		if relErr != nil || rel == "." {
+			rel = result.Path
+		}
+		ui.PrintError(fmt.Sprintf("%s %s: %s", strings.ToUpper(result.Status), rel, result.Error))
+	})
+	if err != nil {
+		return err
+	}
+
+	verbose := jsonLevel == model.JSONLevelExtended || jsonLevel == model.JSONLevelRaw
+	for _, result := range results {
+		switch result.Status {
+		case verify.StatusOK:
+			summary.OK++
+		case verify.StatusShort:
+			summary.Short++
+		default:
+			summary.Corrupt++
+		}
+		if verbose || result.Status != verify.StatusOK {
+			summary.Files = append(summary.Files, result)
+		}
+	}
+	summary.Checked = len(results)
+	failed := summary.Corrupt + summary.Short
+
+	if jsonLevel != "" {
+		if err := PrintJSON(summary); err != nil {
+			return err
+		}
+	} else {
+		if failed > 0 {
+			fmt.Println()
+		}
+		ui.PrintKeyValue("Files checked", strconv.Itoa(summary.Checked), ui.ColorReset)
+		ui.PrintKeyValue("OK", strconv.Itoa(summary.OK), ui.ColorGreen)
+		ui.PrintKeyValue("Corrupt", strconv.Itoa(summary.Corrupt), ui.ColorRed)
+		ui.PrintKeyValue("Short", strconv.Itoa(summary.Short), ui.ColorYellow)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d files failed verification", failed, summary.Checked)
+	}
+	if jsonLevel == "" {
+		ui.PrintSuccess("All files verified")
+	}
+	return nil
+}
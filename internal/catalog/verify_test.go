@@ -0,0 +1,60 @@
+package catalog
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jmagar/nugs-cli/internal/model"
+	"github.com/jmagar/nugs-cli/internal/testutil"
+	"github.com/jmagar/nugs-cli/internal/verify"
+)
+
+func TestVerifyArtistIDScansArtistFolder(t *testing.T) {
+	testutil.WithTempHome(t)
+	cfg := &model.Config{OutPath: t.TempDir()}
+	show := filepath.Join(cfg.OutPath, "Goose", "Goose - 2024-06-22 Forest Hills")
+	if err := os.MkdirAll(show, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(show, "01. Arcadia.flac"), []byte("fLaC"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	deps := &Deps{
+		GetArtistMetaCached: func(_ context.Context, artistID string, _ time.Duration) ([]*model.ArtistMeta, bool, bool, error) {
+			if artistID != "1125" {
+				t.Fatalf("resolved artist %q, want 1125", artistID)
+			}
+			return []*model.ArtistMeta{artistPageWithShow("Goose", 10, "Show")}, false, false, nil
+		},
+	}
+
+	var verifyErr error
+	out := testutil.CaptureStdout(t, func() {
+		verifyErr = Verify(context.Background(), "1125", cfg, model.JSONLevelStandard, deps)
+	})
+	if verifyErr == nil || !strings.Contains(verifyErr.Error(), "1 of 1 files failed") {
+		t.Fatalf("Verify error = %v, want one failed file", verifyErr)
+	}
+	var summary VerifySummary
+	if err := json.Unmarshal([]byte(out), &summary); err != nil {
+		t.Fatalf("output is not JSON: %v\n%s", err, out)
+	}
+	if summary.Path != filepath.Join(cfg.OutPath, "Goose") || summary.Short != 1 || len(summary.Files) != 1 {
+		t.Fatalf("summary = %+v", summary)
+	}
+	if summary.Files[0].Status != verify.StatusShort {
+		t.Fatalf("file status = %q, want %q", summary.Files[0].Status, verify.StatusShort)
+	}
+}
+
+func TestVerifyRejectsUnknownTarget(t *testing.T) {
+	err := Verify(context.Background(), "no-such-dir", &model.Config{OutPath: t.TempDir()}, "", &Deps{})
+	if err == nil || !strings.Contains(err.Error(), "neither an existing path nor an artist ID") {
+		t.Fatalf("Verify error = %v", err)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"slices"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/jmagar/nugs-cli/internal/cache"
 	"github.com/jmagar/nugs-cli/internal/config"
@@ -30,35 +31,47 @@ func (e *WatchOutcomeError) Error() string {
 
 func (e *WatchOutcomeError) Unwrap() error { return e.CatalogUpdate }
 
-// WatchAdd adds an artist ID to the watch list in config.
-// The ID must be a valid integer. Duplicates are silently ignored.
-func WatchAdd(cfg *model.Config, artistID string) error {
+// WatchAdd adds an artist ID to the watch list in config, with optional
+// rule arguments (since:, until:, venue:, media:, archival:, max:). Re-adding
+// a watched artist without rules is a no-op; with rules it updates them.
+// Saving moves any legacy watchedArtists IDs into the structured list.
+func WatchAdd(cfg *model.Config, artistID string, rules []string) error {
 	if _, err := strconv.Atoi(artistID); err != nil {
 		return fmt.Errorf("invalid artist ID %q: must be a number", artistID)
 	}
 
-	// Deduplicate: only add if not already present.
-	if slices.Contains(cfg.WatchedArtists, artistID) {
-		name := resolveArtistName(artistID)
-		if name != "" {
-			ui.PrintInfo(fmt.Sprintf("%s (%s) is already in the watch list", artistID, name))
-		} else {
-			ui.PrintInfo(fmt.Sprintf("%s is already in the watch list", artistID))
-		}
+	label := artistID
+	if name := resolveArtistName(artistID); name != "" {
+		label = fmt.Sprintf("%s (%s)", artistID, name)
+	}
+	entries := WatchEntries(cfg)
+	i := watchEntryIndex(entries, artistID)
+	if i >= 0 && len(rules) == 0 {
+		ui.PrintInfo(fmt.Sprintf("%s is already in the watch list", label))
 		return nil
 	}
 
-	cfg.WatchedArtists = append(cfg.WatchedArtists, artistID)
-
-	if err := config.WriteConfig(cfg); err != nil {
-		return fmt.Errorf("failed to save config: %w", err)
+	entry := model.WatchEntry{ArtistID: artistID}
+	if i >= 0 {
+		entry = entries[i]
 	}
-
-	name := resolveArtistName(artistID)
-	if name != "" {
-		ui.PrintSuccess(fmt.Sprintf("Added %s (%s) to watch list", artistID, name))
+	if err := parseWatchRules(&entry, rules); err != nil {
+		return err
+	}
+	verb := "Added %s to watch list"
+	if i >= 0 {
+		entries[i] = entry
+		verb = "Updated watch rules for %s"
 	} else {
-		ui.PrintSuccess(fmt.Sprintf("Added %s to watch list", artistID))
+		entries = append(entries, entry)
+	}
+	if err := saveWatchEntries(cfg, entries); err != nil {
+		return err
+	}
+
+	ui.PrintSuccess(fmt.Sprintf(verb, label))
+	if len(rules) > 0 {
+		ui.PrintInfo("Rules: " + describeWatchRule(entry))
 	}
 	return nil
 }
@@ -66,16 +79,13 @@ func WatchAdd(cfg *model.Config, artistID string) error {
 // WatchRemove removes an artist ID from the watch list in config.
 // Returns an error if the ID is not currently watched.
 func WatchRemove(cfg *model.Config, artistID string) error {
-	if !slices.Contains(cfg.WatchedArtists, artistID) {
+	entries := WatchEntries(cfg)
+	i := watchEntryIndex(entries, artistID)
+	if i < 0 {
 		return fmt.Errorf("artist %s is not in the watch list", artistID)
 	}
-
-	cfg.WatchedArtists = slices.DeleteFunc(cfg.WatchedArtists, func(id string) bool {
-		return id == artistID
-	})
-
-	if err := config.WriteConfig(cfg); err != nil {
-		return fmt.Errorf("failed to save config: %w", err)
+	if err := saveWatchEntries(cfg, slices.Delete(entries, i, i+1)); err != nil {
+		return err
 	}
 
 	name := resolveArtistName(artistID)
@@ -87,9 +97,22 @@ func WatchRemove(cfg *model.Config, artistID string) error {
 	return nil
 }
 
-// WatchList prints the current watch list with artist names where available.
+// saveWatchEntries stores entries as the structured watch list, dropping the
+// legacy watchedArtists field, and writes the config.
+func saveWatchEntries(cfg *model.Config, entries []model.WatchEntry) error {
+	cfg.Watch = entries
+	cfg.WatchedArtists = nil
+	if err := config.WriteConfig(cfg); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+	return nil
+}
+
+// WatchList prints the current watch list and each artist's rules, with
+// artist names where available.
 func WatchList(cfg *model.Config, jsonLevel string) error {
-	if len(cfg.WatchedArtists) == 0 {
+	entries := WatchEntries(cfg)
+	if len(entries) == 0 {
 		if jsonLevel != "" {
 			return PrintJSON(map[string]any{"watchedArtists": []any{}})
 		}
@@ -101,19 +124,37 @@ func WatchList(cfg *model.Config, jsonLevel string) error {
 	nameByID := buildArtistNameMap()
 
 	if jsonLevel != "" {
-		entries := make([]map[string]any, len(cfg.WatchedArtists))
-		for i, id := range cfg.WatchedArtists {
-			entry := map[string]any{"artistID": id}
-			idInt, err := strconv.Atoi(id)
-			if err == nil {
-				if name, ok := nameByID[idInt]; ok {
-					entry["artistName"] = name
-				}
+		out := make([]map[string]any, len(entries))
+		for i, entry := range entries {
+			item := map[string]any{
+				"artistID": entry.ArtistID,
+				"rules":    describeWatchRule(entry),
+			}
+			if name := resolveArtistNameFromMap(entry.ArtistID, nameByID); name != "" {
+				item["artistName"] = name
+			}
+			if entry.Since != "" {
+				item["since"] = entry.Since
+			}
+			if entry.Until != "" {
+				item["until"] = entry.Until
+			}
+			if entry.Venue != "" {
+				item["venue"] = entry.Venue
 			}
-			entries[i] = entry
+			if entry.Media != "" {
+				item["media"] = entry.Media
+			}
+			if entry.SkipArchival {
+				item["skipArchival"] = true
+			}
+			if entry.MaxDownloads > 0 {
+				item["maxDownloads"] = entry.MaxDownloads
+			}
+			out[i] = item
 		}
 		return PrintJSON(map[string]any{
-			"watchedArtists": entries,
+			"watchedArtists": out,
 			"watchInterval":  watchIntervalOrDefault(cfg),
 		})
 	}
@@ -121,19 +162,16 @@ func WatchList(cfg *model.Config, jsonLevel string) error {
 	ui.PrintHeader("Watched Artists")
 	table := ui.NewTable([]ui.TableColumn{
 		{Header: "ID", Width: 10, Align: "right"},
-		{Header: "Artist", Width: 40, Align: "left"},
+		{Header: "Artist", Width: 30, Align: "left"},
+		{Header: "Rules", Width: 44, Align: "left"},
 	})
 
-	for _, id := range cfg.WatchedArtists {
-		name := ""
-		idInt, err := strconv.Atoi(id)
-		if err == nil {
-			name = nameByID[idInt]
-		}
+	for _, entry := range entries {
+		name := resolveArtistNameFromMap(entry.ArtistID, nameByID)
 		if name == "" {
 			name = "(name unavailable — run 'nugs catalog update' first)"
 		}
-		table.AddRow(id, name)
+		table.AddRow(entry.ArtistID, name, describeWatchRule(entry))
 	}
 	table.Print()
 
@@ -142,10 +180,12 @@ func WatchList(cfg *model.Config, jsonLevel string) error {
 	return nil
 }
 
-// WatchCheck updates the catalog then runs gap-fill for every watched artist.
-// Artists are processed sequentially. Context cancellation stops between artists.
+// WatchCheck updates the catalog then runs gap-fill for every watched artist,
+// applying each entry's rules. Artists are processed sequentially. Context
+// cancellation stops between artists.
 func WatchCheck(ctx context.Context, cfg *model.Config, streamParams *model.StreamParams, jsonLevel string, mediaFilter model.MediaType, deps *Deps) error {
-	if len(cfg.WatchedArtists) == 0 {
+	entries := WatchEntries(cfg)
+	if len(entries) == 0 {
 		if jsonLevel == "" {
 			ui.PrintInfo("No artists in watch list. Add one with: nugs watch add <artistID>")
 		}
@@ -168,7 +208,8 @@ func WatchCheck(ctx context.Context, cfg *model.Config, streamParams *model.Stre
 	var artistErrors []string
 	nameByID := buildArtistNameMap()
 
-	for _, artistID := range cfg.WatchedArtists {
+	for _, entry := range entries {
+		artistID := entry.ArtistID
 		select {
 		case <-ctx.Done():
 			return ctx.Err()
@@ -184,7 +225,7 @@ func WatchCheck(ctx context.Context, cfg *model.Config, streamParams *model.Stre
 			}
 		}
 
-		result, err := CatalogGapsFill(ctx, artistID, cfg, streamParams, jsonLevel, mediaFilter, deps)
+		result, err := CatalogGapsFill(ctx, artistID, cfg, streamParams, jsonLevel, mediaFilter, &entry, deps)
 		if err != nil {
 			if jsonLevel == "" {
 				ui.PrintWarning(fmt.Sprintf("Gap fill failed for artist %s: %v", artistID, err))
@@ -194,7 +235,7 @@ func WatchCheck(ctx context.Context, cfg *model.Config, streamParams *model.Stre
 		} else {
 			totalDownloaded += result.Downloaded
 			totalFailed += result.Failed
-			if len(cfg.WatchedArtists) > 1 {
+			if len(entries) > 1 {
 				sendArtistUpdate(ctx, deps.Notify, result, artistID)
 			}
 			if result.Interrupted {
@@ -229,7 +270,7 @@ func sendArtistUpdate(ctx context.Context, notify func(ctx context.Context, titl
 	_ = notify(ctx, "Nugs Watch", msg, 5)
 }
 
-// sendWatchSummary fires a single Gotify notification summarising the watch check outcome.
+// sendWatchSummary fires a single notification summarising the watch check outcome.
 // Sends nothing if notify is nil or there is nothing to report (all up-to-date, no errors).
 func sendWatchSummary(ctx context.Context, notify func(ctx context.Context, title, message string, priority int) error, downloaded, failed int, errs []string, catalogUpdateErr error) {
 	if notify == nil {
@@ -300,6 +341,19 @@ func resolveArtistNameFromMap(artistID string, names map[int]string) string {
 	return names[idInt]
 }
 
+// WatchInterval returns the parsed watch interval, defaulting to one hour.
+func WatchInterval(cfg *model.Config) (time.Duration, error) {
+	raw := watchIntervalOrDefault(cfg)
+	d, err := time.ParseDuration(raw)
+	if err != nil {
+		return 0, fmt.Errorf("watchInterval %q is not a valid duration (use Go syntax: 30m, 1h, 6h): %w", raw, err)
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("watchInterval %q must be positive", raw)
+	}
+	return d, nil
+}
+
 // watchIntervalOrDefault returns cfg.WatchInterval or the default "1h".
 func watchIntervalOrDefault(cfg *model.Config) string {
 	if cfg.WatchInterval != "" {
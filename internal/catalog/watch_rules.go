@@ -0,0 +1,150 @@
+package catalog
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jmagar/nugs-cli/internal/helpers"
+	"github.com/jmagar/nugs-cli/internal/model"
+)
+
+// archivalAge is how long after the show a release must appear for the
+// skipArchival rule to treat it as an archival release.
+const archivalAge = 365 * 24 * time.Hour
+
+// WatchEntries returns the watch list: cfg.Watch followed by any artist from
+// the legacy watchedArtists list without an entry of its own.
+func WatchEntries(cfg *model.Config) []model.WatchEntry {
+	entries := slices.Clone(cfg.Watch)
+	for _, id := range cfg.WatchedArtists {
+		if watchEntryIndex(entries, id) < 0 {
+			entries = append(entries, model.WatchEntry{ArtistID: id})
+		}
+	}
+	return entries
+}
+
+func watchEntryIndex(entries []model.WatchEntry, artistID string) int {
+	return slices.IndexFunc(entries, func(e model.WatchEntry) bool { return e.ArtistID == artistID })
+}
+
+// parseWatchRules applies `nugs watch add` rule arguments to entry:
+// since:, until:, venue:, media:, archival:skip|keep and max:.
+func parseWatchRules(entry *model.WatchEntry, args []string) error {
+	for _, arg := range args {
+		key, value, ok := strings.Cut(arg, ":")
+		if !ok {
+			return fmt.Errorf("invalid watch rule %q: use field:value, e.g. since:2024", arg)
+		}
+		value = strings.Trim(strings.TrimSpace(value), `"`)
+		switch strings.ToLower(key) {
+		case "since":
+			entry.Since = value
+		case "until":
+			entry.Until = value
+		case "venue":
+			entry.Venue = value
+		case "media":
+			entry.Media = value
+		case "archival":
+			switch strings.ToLower(value) {
+			case "skip":
+				entry.SkipArchival = true
+			case "keep":
+				entry.SkipArchival = false
+			default:
+				return fmt.Errorf("invalid archival rule %q: must be skip or keep", value)
+			}
+		case "max":
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				return fmt.Errorf("invalid max %q: must be a number", value)
+			}
+			entry.MaxDownloads = n
+		default:
+			return fmt.Errorf("unknown watch rule %q (use since, until, venue, media, archival or max)", key)
+		}
+	}
+	return helpers.NormalizeWatchEntry(entry)
+}
+
+// watchRuleMatches reports whether show passes rule's date, venue and
+// archival rules. Media and download caps are applied by CatalogGapsFill.
+func watchRuleMatches(rule *model.WatchEntry, show *model.AlbArtResp) bool {
+	if rule == nil || show == nil {
+		return rule == nil
+	}
+	if rule.Since != "" || rule.Until != "" {
+		date, ok := helpers.ParsePerformanceDate(helpers.ShowDate(show))
+		if !ok {
+			return false
+		}
+		day := date.Format("2006-01-02")
+		if from, _, err := helpers.DateBounds(rule.Since); err == nil && day < from {
+			return false
+		}
+		if _, to, err := helpers.DateBounds(rule.Until); err == nil && day > to {
+			return false
+		}
+	}
+	if rule.Venue != "" {
+		want := strings.ToLower(rule.Venue)
+		matched := false
+		for _, field := range []string{show.VenueName, show.Venue, show.VenueCity} {
+			if strings.Contains(strings.ToLower(field), want) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return !rule.SkipArchival || !isArchivalRelease(show)
+}
+
+// isArchivalRelease reports whether show was published more than archivalAge
+// after it was played. Shows without a release date are not archival.
+func isArchivalRelease(show *model.AlbArtResp) bool {
+	released, ok := helpers.ParsePerformanceDate(show.ReleaseDateFormatted)
+	if !ok {
+		return false
+	}
+	played, ok := helpers.ParsePerformanceDate(helpers.ShowDate(show))
+	return ok && released.Sub(played) > archivalAge
+}
+
+// describeWatchRule renders an entry's rules for `nugs watch list`.
+func describeWatchRule(entry model.WatchEntry) string {
+	var parts []string
+	switch {
+	case entry.Since != "" && entry.Until != "":
+		parts = append(parts, entry.Since+" to "+entry.Until)
+	case entry.Since != "":
+		parts = append(parts, "since "+entry.Since)
+	case entry.Until != "":
+		parts = append(parts, "until "+entry.Until)
+	}
+	if entry.Venue != "" {
+		parts = append(parts, fmt.Sprintf("venue %q", entry.Venue))
+	}
+	switch entry.Media {
+	case "audio", "video":
+		parts = append(parts, entry.Media+" only")
+	case "both":
+		parts = append(parts, "audio and video")
+	}
+	if entry.SkipArchival {
+		parts = append(parts, "no archival")
+	}
+	if entry.MaxDownloads > 0 {
+		parts = append(parts, fmt.Sprintf("max %d per run", entry.MaxDownloads))
+	}
+	if len(parts) == 0 {
+		return "all shows"
+	}
+	return strings.Join(parts, ", ")
+}
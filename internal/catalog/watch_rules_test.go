@@ -0,0 +1,198 @@
+package catalog
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jmagar/nugs-cli/internal/cache"
+	"github.com/jmagar/nugs-cli/internal/model"
+	"github.com/jmagar/nugs-cli/internal/testutil"
+)
+
+func TestWatchRuleMatches(t *testing.T) {
+	redRocks := &model.AlbArtResp{PerformanceDate: "2024-06-10", VenueName: "Red Rocks Amphitheatre", VenueCity: "Morrison", ReleaseDateFormatted: "06/12/2024"}
+	vault := &model.AlbArtResp{PerformanceDate: "1977-05-08", VenueName: "Barton Hall", VenueCity: "Ithaca", ReleaseDateFormatted: "05/08/2017"}
+	undated := &model.AlbArtResp{VenueName: "Red Rocks Amphitheatre"}
+	tests := []struct {
+		name string
+		rule model.WatchEntry
+		show *model.AlbArtResp
+		want bool
+	}{
+		{name: "no rules", rule: model.WatchEntry{}, show: vault, want: true},
+		{name: "since year", rule: model.WatchEntry{Since: "2024"}, show: redRocks, want: true},
+		{name: "since excludes older", rule: model.WatchEntry{Since: "2024"}, show: vault, want: false},
+		{name: "until month is inclusive", rule: model.WatchEntry{Until: "2024-06"}, show: redRocks, want: true},
+		{name: "until excludes later", rule: model.WatchEntry{Until: "2024-05-31"}, show: redRocks, want: false},
+		{name: "date rule needs a date", rule: model.WatchEntry{Since: "2020"}, show: undated, want: false},
+		{name: "venue substring", rule: model.WatchEntry{Venue: "red rocks"}, show: redRocks, want: true},
+		{name: "venue matches city", rule: model.WatchEntry{Venue: "ithaca"}, show: vault, want: true},
+		{name: "venue mismatch", rule: model.WatchEntry{Venue: "red rocks"}, show: vault, want: false},
+		{name: "skip archival", rule: model.WatchEntry{SkipArchival: true}, show: vault, want: false},
+		{name: "skip archival keeps recent", rule: model.WatchEntry{SkipArchival: true}, show: redRocks, want: true},
+		{name: "unknown release date is not archival", rule: model.WatchEntry{SkipArchival: true}, show: undated, want: true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := watchRuleMatches(&tc.rule, tc.show); got != tc.want {
+				t.Fatalf("watchRuleMatches(%+v) = %t, want %t", tc.rule, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestParseWatchRules(t *testing.T) {
+	entry := model.WatchEntry{ArtistID: "1125"}
+	if err := parseWatchRules(&entry, []string{"since:2024", `venue:"Red Rocks"`, "media:VIDEO", "archival:skip", "max:3"}); err != nil {
+		t.Fatal(err)
+	}
+	want := model.WatchEntry{ArtistID: "1125", Since: "2024", Venue: "Red Rocks", Media: "video", SkipArchival: true, MaxDownloads: 3}
+	if entry != want {
+		t.Fatalf("entry = %+v, want %+v", entry, want)
+	}
+	if got := describeWatchRule(entry); got != `since 2024, venue "Red Rocks", video only, no archival, max 3 per run` {
+		t.Fatalf("describeWatchRule() = %q", got)
+	}
+
+	for _, args := range [][]string{{"since:24"}, {"since:2025", "until:2024"}, {"media:vinyl"}, {"max:-1"}, {"archival:maybe"}, {"venu:x"}, {"2024"}} {
+		entry := model.WatchEntry{ArtistID: "1125"}
+		if err := parseWatchRules(&entry, args); err == nil {
+			t.Errorf("parseWatchRules(%q) succeeded, want error", args)
+		}
+	}
+}
+
+func TestWatchAddRulesMigratesLegacyList(t *testing.T) {
+	home := testutil.WithTempHome(t)
+	resetLoadedConfigPath(t)
+
+	cfg := &model.Config{WatchedArtists: []string{"1125", "461"}}
+	testutil.CaptureStdout(t, func() {
+		if err := WatchAdd(cfg, "461", []string{"since:2024", "max:2"}); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if cfg.WatchedArtists != nil {
+		t.Fatalf("legacy list = %v, want it folded into watch", cfg.WatchedArtists)
+	}
+	want := []model.WatchEntry{{ArtistID: "1125"}, {ArtistID: "461", Since: "2024", MaxDownloads: 2}}
+	if !slices.Equal(cfg.Watch, want) {
+		t.Fatalf("watch = %+v, want %+v", cfg.Watch, want)
+	}
+
+	data, err := os.ReadFile(filepath.Join(home, ".nugs", "config.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "watchedArtists") || !strings.Contains(string(data), `"since": "2024"`) {
+		t.Fatalf("saved config = %s", data)
+	}
+}
+
+func TestCatalogGapsFillAppliesWatchRules(t *testing.T) {
+	testutil.WithTempHome(t)
+	index := &model.FullCatalogIndex{Containers: map[int]model.FullCatalogShow{}}
+	for _, show := range []model.FullCatalogShow{
+		{ContainerID: 1, PerformanceDate: "2024-08-01", VenueName: "Red Rocks Amphitheatre"},
+		{ContainerID: 2, PerformanceDate: "2024-07-01", VenueName: "Red Rocks Amphitheatre"},
+		{ContainerID: 3, PerformanceDate: "2024-06-01", VenueName: "Red Rocks Amphitheatre"},
+		{ContainerID: 4, PerformanceDate: "2024-05-01", VenueName: "The Capitol Theatre"},
+		{ContainerID: 5, PerformanceDate: "2019-07-01", VenueName: "Red Rocks Amphitheatre"},
+	} {
+		show.ArtistID, show.ArtistName = 1125, "Billy Strings"
+		show.ContainerInfo = show.PerformanceDate + " " + show.VenueName
+		show.Media, show.Downloadable = model.MediaTypeAudio, true
+		index.Containers[show.ContainerID] = show
+	}
+	if err := cache.WriteFullCatalog(index, &model.FullCatalogMeta{FullCrawlAt: time.Now()}); err != nil {
+		t.Fatal(err)
+	}
+
+	var downloaded []string
+	deps := &Deps{
+		GetArtistMetaCached: func(context.Context, string, time.Duration) ([]*model.ArtistMeta, bool, bool, error) {
+			t.Fatal("artist metadata fetched despite full catalog index")
+			return nil, false, false, nil
+		},
+		Album: func(_ context.Context, albumID string, _ *model.Config, _ *model.StreamParams, _ *model.AlbArtResp, _ *model.BatchProgressState, _ *model.ProgressBoxState) error {
+			downloaded = append(downloaded, albumID)
+			return nil
+		},
+	}
+	cfg := &model.Config{OutPath: t.TempDir(), DefaultOutputs: "audio"}
+	rule := &model.WatchEntry{ArtistID: "1125", Since: "2024", Venue: "red rocks", MaxDownloads: 2}
+
+	var result GapFillResult
+	stdout := testutil.CaptureStdout(t, func() {
+		var err error
+		result, err = CatalogGapsFill(context.Background(), "1125", cfg, &model.StreamParams{}, "standard", model.MediaTypeUnknown, rule, deps)
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+	if !slices.Equal(downloaded, []string{"1", "2"}) || result.Downloaded != 2 {
+		t.Fatalf("downloaded %v (result %+v), want newest two Red Rocks shows since 2024", downloaded, result)
+	}
+	var out struct {
+		TotalMissing   int  `json:"totalMissing"`
+		Remaining      int  `json:"remaining"`
+		SkippedByRules int  `json:"skippedByRules"`
+		LimitReached   bool `json:"limitReached"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &out); err != nil {
+		t.Fatalf("invalid JSON %q: %v", stdout, err)
+	}
+	if out.TotalMissing != 3 || out.Remaining != 1 || out.SkippedByRules != 2 || !out.LimitReached {
+		t.Fatalf("output = %+v", out)
+	}
+}
+
+func TestCatalogGapsFillExplicitMediaOverridesRule(t *testing.T) {
+	testutil.WithTempHome(t)
+	index := &model.FullCatalogIndex{Containers: map[int]model.FullCatalogShow{
+		1: {ContainerID: 1, ArtistID: 1125, ArtistName: "Billy Strings", PerformanceDate: "2024-08-01", ContainerInfo: "2024-08-01 Audio Show", Media: model.MediaTypeAudio, Downloadable: true},
+		2: {ContainerID: 2, ArtistID: 1125, ArtistName: "Billy Strings", PerformanceDate: "2024-07-01", ContainerInfo: "2024-07-01 Video Show", Media: model.MediaTypeVideo, Downloadable: true},
+	}}
+	if err := cache.WriteFullCatalog(index, &model.FullCatalogMeta{FullCrawlAt: time.Now()}); err != nil {
+		t.Fatal(err)
+	}
+	rule := &model.WatchEntry{ArtistID: "1125", Media: "video"}
+
+	for _, tc := range []struct {
+		name   string
+		filter model.MediaType
+		want   []string
+	}{
+		{name: "rule media without a filter", filter: model.MediaTypeUnknown, want: []string{"2"}},
+		{name: "explicit filter wins", filter: model.MediaTypeAudio, want: []string{"1"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var downloaded []string
+			deps := &Deps{
+				GetArtistMetaCached: func(context.Context, string, time.Duration) ([]*model.ArtistMeta, bool, bool, error) {
+					t.Fatal("artist metadata fetched despite full catalog index")
+					return nil, false, false, nil
+				},
+				Album: func(_ context.Context, albumID string, _ *model.Config, _ *model.StreamParams, _ *model.AlbArtResp, _ *model.BatchProgressState, _ *model.ProgressBoxState) error {
+					downloaded = append(downloaded, albumID)
+					return nil
+				},
+			}
+			cfg := &model.Config{OutPath: t.TempDir(), VideoOutPath: t.TempDir(), DefaultOutputs: "both"}
+			testutil.CaptureStdout(t, func() {
+				if _, err := CatalogGapsFill(context.Background(), "1125", cfg, &model.StreamParams{}, "standard", tc.filter, rule, deps); err != nil {
+					t.Fatal(err)
+				}
+			})
+			if !slices.Equal(downloaded, tc.want) {
+				t.Fatalf("downloaded %v, want %v", downloaded, tc.want)
+			}
+		})
+	}
+}
@@ -14,6 +14,7 @@ import (
 	"time"
 
 	"github.com/jmagar/nugs-cli/internal/cache"
+	"github.com/jmagar/nugs-cli/internal/helpers"
 	"github.com/jmagar/nugs-cli/internal/model"
 	"github.com/jmagar/nugs-cli/internal/ui"
 )
@@ -50,7 +51,7 @@ WantedBy=timers.target
 // WatchEnable writes systemd user unit files for the watch timer and enables them.
 // Requires at least one artist in the watch list.
 func WatchEnable(cfg *model.Config) error {
-	if len(cfg.WatchedArtists) == 0 {
+	if len(WatchEntries(cfg)) == 0 {
 		return fmt.Errorf("no artists in watch list — add at least one with: nugs watch add <artistID>")
 	}
 
@@ -73,8 +74,17 @@ func WatchEnable(cfg *model.Config) error {
 		return fmt.Errorf("watch requires ffmpeg in the service PATH: %w", err)
 	}
 	if cfg.RcloneEnabled {
-		if _, err := exec.LookPath("rclone"); err != nil {
-			return fmt.Errorf("watch has rclone enabled but rclone is not in the service PATH: %w", err)
+		for _, backend := range helpers.StorageBackends(cfg) {
+			switch backend {
+			case model.StorageBackendRclone:
+				if _, err := exec.LookPath("rclone"); err != nil {
+					return fmt.Errorf("watch has rclone enabled but rclone is not in the service PATH: %w", err)
+				}
+			case model.StorageBackendSFTP:
+				if _, err := exec.LookPath("ssh"); err != nil {
+					return fmt.Errorf("watch uploads over sftp but ssh is not in the service PATH: %w", err)
+				}
+			}
 		}
 	}
 
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
 	"time"
@@ -83,7 +84,7 @@ func TestWatchAdd(t *testing.T) {
 			resetLoadedConfigPath(t)
 
 			cfg := &model.Config{WatchedArtists: append([]string{}, tc.initial...)}
-			err := WatchAdd(cfg, tc.addID)
+			err := WatchAdd(cfg, tc.addID, nil)
 
 			if tc.wantErr {
 				if err == nil {
@@ -98,14 +99,8 @@ func TestWatchAdd(t *testing.T) {
 				}
 			}
 
-			if len(cfg.WatchedArtists) != len(tc.wantList) {
-				t.Errorf("WatchedArtists = %v, want %v", cfg.WatchedArtists, tc.wantList)
-				return
-			}
-			for i, id := range tc.wantList {
-				if cfg.WatchedArtists[i] != id {
-					t.Errorf("WatchedArtists[%d] = %q, want %q", i, cfg.WatchedArtists[i], id)
-				}
+			if got := watchedIDs(cfg); !slices.Equal(got, tc.wantList) {
+				t.Errorf("watched artists = %v, want %v", got, tc.wantList)
 			}
 		})
 	}
@@ -172,20 +167,22 @@ func TestWatchRemove(t *testing.T) {
 				}
 			}
 
-			wantLen := len(tc.wantList)
-			if len(cfg.WatchedArtists) != wantLen {
-				t.Errorf("WatchedArtists = %v, want %v", cfg.WatchedArtists, tc.wantList)
-				return
-			}
-			for i, id := range tc.wantList {
-				if cfg.WatchedArtists[i] != id {
-					t.Errorf("WatchedArtists[%d] = %q, want %q", i, cfg.WatchedArtists[i], id)
-				}
+			if got := watchedIDs(cfg); !slices.Equal(got, tc.wantList) {
+				t.Errorf("watched artists = %v, want %v", got, tc.wantList)
 			}
 		})
 	}
 }
 
+// watchedIDs lists the artist IDs of cfg's watch entries, legacy ones included.
+func watchedIDs(cfg *model.Config) []string {
+	ids := []string{}
+	for _, entry := range WatchEntries(cfg) {
+		ids = append(ids, entry.ArtistID)
+	}
+	return ids
+}
+
 // TestWatchIntervalOrDefault confirms the default value is "1h".
 func TestWatchIntervalOrDefault(t *testing.T) {
 	if got := watchIntervalOrDefault(&model.Config{}); got != "1h" {
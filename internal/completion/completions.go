@@ -52,10 +52,10 @@ _nugs_completion() {
     _init_completion || return
 
     # Top-level commands
-    local commands="list catalog watch status cancel help completion"
+    local commands="list catalog search song playlist watch verify transcode remote queue serve daemon status cancel help completion"
 
     # Flags
-    local flags="-f -F -o --force-video --skip-videos --skip-chapters --json --help"
+    local flags="-f -F -o --force-video --skip-videos --skip-chapters --tracks --json --help"
 
     # Handle flag completions
     case "$prev" in
@@ -97,7 +97,9 @@ _nugs_completion() {
             ;;
         catalog)
             if [[ $cword -eq 2 ]]; then
-                COMPREPLY=($(compgen -W "update cache stats latest list gaps coverage config" -- "$cur"))
+                COMPREPLY=($(compgen -W "update cache stats latest list gaps coverage search config" -- "$cur"))
+            elif [[ "${words[2]}" == "update" && $cword -eq 3 ]]; then
+                COMPREPLY=($(compgen -W "full" -- "$cur"))
             elif [[ "${words[2]}" == "config" && $cword -eq 3 ]]; then
                 COMPREPLY=($(compgen -W "enable disable set" -- "$cur"))
             elif [[ "${words[2]}" == "gaps" && $cword -ge 4 ]]; then
@@ -109,6 +111,42 @@ _nugs_completion() {
                 COMPREPLY=($(compgen -W "add remove list check enable disable" -- "$cur"))
             fi
             ;;
+        verify)
+            if [[ $cword -eq 2 ]]; then
+                COMPREPLY=($(compgen -d -- "$cur"))
+            fi
+            ;;
+        transcode)
+            if [[ $cword -eq 2 ]]; then
+                COMPREPLY=($(compgen -d -- "$cur"))
+            fi
+            ;;
+        remote)
+            if [[ $cword -eq 2 ]]; then
+                COMPREPLY=($(compgen -W "verify" -- "$cur"))
+            fi
+            ;;
+        song)
+            if [[ $cword -eq 2 ]]; then
+                COMPREPLY=($(compgen -W "list stats grab" -- "$cur"))
+            elif [[ "${words[2]}" == "list" && $cword -ge 5 ]]; then
+                COMPREPLY=($(compgen -W "missing" -- "$cur"))
+            fi
+            ;;
+        playlist)
+            if [[ $cword -eq 2 ]]; then
+                COMPREPLY=($(compgen -W "export" -- "$cur"))
+            elif [[ "${words[2]}" == "export" && $cword -eq 3 ]]; then
+                COMPREPLY=($(compgen -f -- "$cur"))
+            fi
+            ;;
+        queue)
+            if [[ $cword -eq 2 ]]; then
+                COMPREPLY=($(compgen -W "add list remove reorder run" -- "$cur"))
+            elif [[ "${words[2]}" == "add" && $cword -ge 4 ]]; then
+                COMPREPLY=($(compgen -W "audio video both" -- "$cur"))
+            fi
+            ;;
         completion)
             if [[ $cword -eq 2 ]]; then
                 COMPREPLY=($(compgen -W "bash zsh fish powershell" -- "$cur"))
@@ -127,11 +165,20 @@ const ZshCompletion = `#compdef nugs
 # Then add to ~/.zshrc: fpath=(~/.zsh/completion $fpath)
 
 _nugs() {
-    local -a commands catalog_cmds config_cmds watch_cmds
+    local -a commands catalog_cmds config_cmds watch_cmds queue_cmds song_cmds
     commands=(
         'list:List artists or shows'
         'catalog:Catalog management commands'
+        'search:Search the cached catalog'
+        'song:Song performances and setlist stats'
+        'playlist:Export playlists of downloaded shows'
         'watch:Artist watch management'
+        'verify:Check downloaded audio for corruption'
+        'transcode:Make transcode profile copies of downloaded audio'
+        'remote:Check uploaded shows against their checksum manifests'
+        'queue:Persistent download queue'
+        'serve:Run the local HTTP API'
+        'daemon:Run scheduled watch checks and catalog refreshes'
         'status:Show runtime status'
         'cancel:Cancel running crawl'
         'help:Display help'
@@ -146,6 +193,7 @@ _nugs() {
         'list:List shows for artist'
         'gaps:Find missing shows'
         'coverage:Show collection coverage'
+        'search:Search the cached catalog'
         'config:Configure auto-refresh'
     )
 
@@ -164,6 +212,20 @@ _nugs() {
         'disable:Disable systemd watch timer'
     )
 
+    song_cmds=(
+        'list:List every performance of a song'
+        'stats:Show song play counts per artist and year'
+        'grab:Download every performance of a song'
+    )
+
+    queue_cmds=(
+        'add:Add releases to the queue'
+        'list:Show queued downloads'
+        'remove:Remove an item from the queue'
+        'reorder:Move an item to a new position'
+        'run:Download everything in the queue'
+    )
+
     _arguments -C \
         '-f[Track download format (1-5)]:format:(1 2 3 4 5)' \
         '-F[Video download format (1-5)]:format:(1 2 3 4 5)' \
@@ -171,6 +233,7 @@ _nugs() {
         '--force-video[Force video download]' \
         '--skip-videos[Skip videos in artist URLs]' \
         '--skip-chapters[Skip video chapters]' \
+        '--tracks[Download only matching tracks]:selector:' \
         '--json[JSON output level]:level:(minimal standard extended raw)' \
         '--help[Show help]' \
         '1: :->cmds' \
@@ -194,6 +257,8 @@ _nugs() {
                 catalog)
                     if [[ $CURRENT -eq 2 ]]; then
                         _describe -t catalog_cmds 'catalog commands' catalog_cmds
+                    elif [[ $words[2] == "update" && $CURRENT -eq 3 ]]; then
+                        _values 'update modes' 'full[Crawl the full catalog]'
                     elif [[ $words[2] == "config" && $CURRENT -eq 3 ]]; then
                         _describe -t config_cmds 'config commands' config_cmds
                     elif [[ $words[2] == "gaps" ]]; then
@@ -205,6 +270,37 @@ _nugs() {
                         _describe -t watch_cmds 'watch commands' watch_cmds
                     fi
                     ;;
+                verify|transcode)
+                    if [[ $CURRENT -eq 2 ]]; then
+                        _files -/
+                    fi
+                    ;;
+                remote)
+                    if [[ $CURRENT -eq 2 ]]; then
+                        _values 'remote subcommands' 'verify[Re-check remote shows against their manifests]'
+                    fi
+                    ;;
+                song)
+                    if [[ $CURRENT -eq 2 ]]; then
+                        _describe -t song_cmds 'song commands' song_cmds
+                    elif [[ $words[2] == "list" && $CURRENT -ge 5 ]]; then
+                        _values 'filter' 'missing'
+                    fi
+                    ;;
+                playlist)
+                    if [[ $CURRENT -eq 2 ]]; then
+                        _values 'playlist subcommands' 'export[Write a playlist of local shows matching a search]'
+                    elif [[ $words[2] == "export" && $CURRENT -eq 3 ]]; then
+                        _files -g '*.(m3u8|m3u|xspf)'
+                    fi
+                    ;;
+                queue)
+                    if [[ $CURRENT -eq 2 ]]; then
+                        _describe -t queue_cmds 'queue commands' queue_cmds
+                    elif [[ $words[2] == "add" && $CURRENT -ge 4 ]]; then
+                        _values 'media' 'audio' 'video' 'both'
+                    fi
+                    ;;
                 completion)
                     if [[ $CURRENT -eq 2 ]]; then
                         _values 'shells' 'bash' 'zsh' 'fish' 'powershell'
@@ -228,7 +324,16 @@ complete -c nugs -f
 # Top-level commands
 complete -c nugs -n "__fish_use_subcommand" -a "list" -d "List artists or shows"
 complete -c nugs -n "__fish_use_subcommand" -a "catalog" -d "Catalog management"
+complete -c nugs -n "__fish_use_subcommand" -a "search" -d "Search the cached catalog"
 complete -c nugs -n "__fish_use_subcommand" -a "watch" -d "Artist watch management"
+complete -c nugs -n "__fish_use_subcommand" -a "verify" -d "Check downloaded audio for corruption"
+complete -c nugs -n "__fish_use_subcommand" -a "transcode" -d "Make transcode profile copies of downloaded audio"
+complete -c nugs -n "__fish_use_subcommand" -a "song" -d "Song performances and setlist stats"
+complete -c nugs -n "__fish_use_subcommand" -a "playlist" -d "Export playlists of downloaded shows"
+complete -c nugs -n "__fish_use_subcommand" -a "remote" -d "Check uploaded shows against their checksum manifests"
+complete -c nugs -n "__fish_use_subcommand" -a "queue" -d "Persistent download queue"
+complete -c nugs -n "__fish_use_subcommand" -a "serve" -d "Run the local HTTP API"
+complete -c nugs -n "__fish_use_subcommand" -a "daemon" -d "Run scheduled watch checks and catalog refreshes"
 complete -c nugs -n "__fish_use_subcommand" -a "status" -d "Show runtime status"
 complete -c nugs -n "__fish_use_subcommand" -a "cancel" -d "Cancel running crawl"
 complete -c nugs -n "__fish_use_subcommand" -a "help" -d "Display help"
@@ -241,6 +346,7 @@ complete -c nugs -s o -d "Output path" -r
 complete -c nugs -l force-video -d "Force video download"
 complete -c nugs -l skip-videos -d "Skip videos in artist URLs"
 complete -c nugs -l skip-chapters -d "Skip video chapters"
+complete -c nugs -l tracks -d "Download only matching tracks" -r
 complete -c nugs -l json -d "JSON output level" -a "minimal standard extended raw"
 complete -c nugs -l help -d "Show help"
 
@@ -258,9 +364,11 @@ complete -c nugs -n "__fish_seen_subcommand_from catalog" -n "test (count (comma
 complete -c nugs -n "__fish_seen_subcommand_from catalog" -n "test (count (commandline -opc)) -eq 2" -a "list" -d "List shows for artist"
 complete -c nugs -n "__fish_seen_subcommand_from catalog" -n "test (count (commandline -opc)) -eq 2" -a "gaps" -d "Find missing shows"
 complete -c nugs -n "__fish_seen_subcommand_from catalog" -n "test (count (commandline -opc)) -eq 2" -a "coverage" -d "Show collection coverage"
+complete -c nugs -n "__fish_seen_subcommand_from catalog" -n "test (count (commandline -opc)) -eq 2" -a "search" -d "Search the cached catalog"
 complete -c nugs -n "__fish_seen_subcommand_from catalog" -n "test (count (commandline -opc)) -eq 2" -a "config" -d "Configure auto-refresh"
 
 # catalog config subcommands
+complete -c nugs -n "__fish_seen_subcommand_from catalog; and __fish_seen_subcommand_from update" -a "full" -d "Crawl the full catalog"
 complete -c nugs -n "__fish_seen_subcommand_from catalog; and __fish_seen_argument -s config" -a "enable" -d "Enable auto-refresh"
 complete -c nugs -n "__fish_seen_subcommand_from catalog; and __fish_seen_argument -s config" -a "disable" -d "Disable auto-refresh"
 complete -c nugs -n "__fish_seen_subcommand_from catalog; and __fish_seen_argument -s config" -a "set" -d "Configure auto-refresh settings"
@@ -277,6 +385,31 @@ complete -c nugs -n "__fish_seen_subcommand_from watch" -n "test (count (command
 complete -c nugs -n "__fish_seen_subcommand_from watch" -n "test (count (commandline -opc)) -eq 2" -a "enable" -d "Enable systemd watch timer"
 complete -c nugs -n "__fish_seen_subcommand_from watch" -n "test (count (commandline -opc)) -eq 2" -a "disable" -d "Disable systemd watch timer"
 
+# verify command
+complete -c nugs -n "__fish_seen_subcommand_from verify" -n "test (count (commandline -opc)) -eq 2" -a "(__fish_complete_directories)"
+
+# transcode command
+complete -c nugs -n "__fish_seen_subcommand_from transcode" -n "test (count (commandline -opc)) -eq 2" -a "(__fish_complete_directories)"
+
+# remote command
+complete -c nugs -n "__fish_seen_subcommand_from remote" -n "test (count (commandline -opc)) -eq 2" -a "verify" -d "Re-check remote shows against their manifests"
+
+# song command
+complete -c nugs -n "__fish_seen_subcommand_from song" -n "test (count (commandline -opc)) -eq 2" -a "list" -d "List every performance of a song"
+complete -c nugs -n "__fish_seen_subcommand_from song" -n "test (count (commandline -opc)) -eq 2" -a "stats" -d "Show song play counts per artist and year"
+complete -c nugs -n "__fish_seen_subcommand_from song" -n "test (count (commandline -opc)) -eq 2" -a "grab" -d "Download every performance of a song"
+
+# playlist command
+complete -c nugs -n "__fish_seen_subcommand_from playlist" -n "test (count (commandline -opc)) -eq 2" -a "export" -d "Write a playlist of local shows matching a search"
+complete -c nugs -n "__fish_seen_subcommand_from playlist" -n "test (count (commandline -opc)) -eq 3" -F
+
+# queue command
+complete -c nugs -n "__fish_seen_subcommand_from queue" -n "test (count (commandline -opc)) -eq 2" -a "add" -d "Add releases to the queue"
+complete -c nugs -n "__fish_seen_subcommand_from queue" -n "test (count (commandline -opc)) -eq 2" -a "list" -d "Show queued downloads"
+complete -c nugs -n "__fish_seen_subcommand_from queue" -n "test (count (commandline -opc)) -eq 2" -a "remove" -d "Remove an item from the queue"
+complete -c nugs -n "__fish_seen_subcommand_from queue" -n "test (count (commandline -opc)) -eq 2" -a "reorder" -d "Move an item to a new position"
+complete -c nugs -n "__fish_seen_subcommand_from queue" -n "test (count (commandline -opc)) -eq 2" -a "run" -d "Download everything in the queue"
+
 # completion command
 complete -c nugs -n "__fish_seen_subcommand_from completion" -n "test (count (commandline -opc)) -eq 2" -a "bash" -d "Bash completion"
 complete -c nugs -n "__fish_seen_subcommand_from completion" -n "test (count (commandline -opc)) -eq 2" -a "zsh" -d "Zsh completion"
@@ -295,7 +428,16 @@ Register-ArgumentCompleter -Native -CommandName nugs -ScriptBlock {
     $commands = @{
         'list' = 'List artists or shows'
         'catalog' = 'Catalog management commands'
+        'search' = 'Search the cached catalog'
+        'song' = 'Song performances and setlist stats'
+        'playlist' = 'Export playlists of downloaded shows'
         'watch' = 'Artist watch management'
+        'verify' = 'Check downloaded audio for corruption'
+        'transcode' = 'Make transcode profile copies of downloaded audio'
+        'remote' = 'Check uploaded shows against their checksum manifests'
+        'queue' = 'Persistent download queue'
+        'serve' = 'Run the local HTTP API'
+        'daemon' = 'Run scheduled watch checks and catalog refreshes'
         'status' = 'Show runtime status'
         'cancel' = 'Cancel running crawl'
         'help' = 'Display help'
@@ -310,6 +452,7 @@ Register-ArgumentCompleter -Native -CommandName nugs -ScriptBlock {
         'list' = 'List shows for artist'
         'gaps' = 'Find missing shows'
         'coverage' = 'Show collection coverage'
+        'search' = 'Search the cached catalog'
         'config' = 'Configure auto-refresh'
     }
 
@@ -328,6 +471,20 @@ Register-ArgumentCompleter -Native -CommandName nugs -ScriptBlock {
         'disable' = 'Disable systemd watch timer'
     }
 
+    $songCommands = @{
+        'list' = 'List every performance of a song'
+        'stats' = 'Show song play counts per artist and year'
+        'grab' = 'Download every performance of a song'
+    }
+
+    $queueCommands = @{
+        'add' = 'Add releases to the queue'
+        'list' = 'Show queued downloads'
+        'remove' = 'Remove an item from the queue'
+        'reorder' = 'Move an item to a new position'
+        'run' = 'Download everything in the queue'
+    }
+
     $shells = @('bash', 'zsh', 'fish', 'powershell')
     $jsonLevels = @('minimal', 'standard', 'extended', 'raw')
     $trackFormats = @('1', '2', '3', '4', '5')
@@ -345,6 +502,7 @@ Register-ArgumentCompleter -Native -CommandName nugs -ScriptBlock {
             [System.Management.Automation.CompletionResult]::new('--force-video', '--force-video', 'ParameterName', 'Force video download')
             [System.Management.Automation.CompletionResult]::new('--skip-videos', '--skip-videos', 'ParameterName', 'Skip videos')
             [System.Management.Automation.CompletionResult]::new('--skip-chapters', '--skip-chapters', 'ParameterName', 'Skip chapters')
+            [System.Management.Automation.CompletionResult]::new('--tracks', '--tracks', 'ParameterName', 'Download only matching tracks')
             [System.Management.Automation.CompletionResult]::new('--json', '--json', 'ParameterName', 'JSON output level')
             [System.Management.Automation.CompletionResult]::new('--help', '--help', 'ParameterName', 'Show help')
         )
@@ -403,6 +561,9 @@ Register-ArgumentCompleter -Native -CommandName nugs -ScriptBlock {
                     [System.Management.Automation.CompletionResult]::new($_.Key, $_.Key, 'ParameterValue', $_.Value)
                 } | Where-Object { $_.CompletionText -like "$wordToComplete*" }
             }
+            elseif ($position -eq 3 -and $tokens[2] -eq 'update') {
+                return [System.Management.Automation.CompletionResult]::new('full', 'full', 'ParameterValue', 'Crawl the full catalog')
+            }
             elseif ($position -eq 3 -and $tokens[2] -eq 'config') {
                 return $configCommands.GetEnumerator() | ForEach-Object {
                     [System.Management.Automation.CompletionResult]::new($_.Key, $_.Key, 'ParameterValue', $_.Value)
@@ -422,6 +583,30 @@ Register-ArgumentCompleter -Native -CommandName nugs -ScriptBlock {
                 } | Where-Object { $_.CompletionText -like "$wordToComplete*" }
             }
         }
+        'remote' {
+            if ($position -eq 2) {
+                return [System.Management.Automation.CompletionResult]::new('verify', 'verify', 'ParameterValue', 'Re-check remote shows against their manifests')
+            }
+        }
+        'song' {
+            if ($position -eq 2) {
+                return $songCommands.GetEnumerator() | ForEach-Object {
+                    [System.Management.Automation.CompletionResult]::new($_.Key, $_.Key, 'ParameterValue', $_.Value)
+                } | Where-Object { $_.CompletionText -like "$wordToComplete*" }
+            }
+        }
+        'playlist' {
+            if ($position -eq 2) {
+                return [System.Management.Automation.CompletionResult]::new('export', 'export', 'ParameterValue', 'Write a playlist of local shows matching a search')
+            }
+        }
+        'queue' {
+            if ($position -eq 2) {
+                return $queueCommands.GetEnumerator() | ForEach-Object {
+                    [System.Management.Automation.CompletionResult]::new($_.Key, $_.Key, 'ParameterValue', $_.Value)
+                } | Where-Object { $_.CompletionText -like "$wordToComplete*" }
+            }
+        }
         'completion' {
             if ($position -eq 2) {
                 return $shells | ForEach-Object {
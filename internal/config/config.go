@@ -15,8 +15,12 @@ import (
 	"syscall"
 
 	"github.com/alexflint/go-arg"
+	"github.com/jmagar/nugs-cli/internal/events"
 	"github.com/jmagar/nugs-cli/internal/helpers"
 	"github.com/jmagar/nugs-cli/internal/model"
+	"github.com/jmagar/nugs-cli/internal/notify"
+	"github.com/jmagar/nugs-cli/internal/playlist"
+	"github.com/jmagar/nugs-cli/internal/transcode"
 	"github.com/jmagar/nugs-cli/internal/ui"
 	"golang.org/x/term"
 )
@@ -397,6 +401,75 @@ func ParseCfg() (*model.Config, error) {
 		return nil, fmt.Errorf("invalid defaultOutputs: %q (must be audio, video, or both)", cfg.DefaultOutputs)
 	}
 
+	// Validate cover art settings
+	cfg.CoverArtSource = strings.ToLower(strings.TrimSpace(cfg.CoverArtSource))
+	if cfg.CoverArtSource == "" {
+		cfg.CoverArtSource = model.CoverArtSourceImg
+	}
+	validCoverArtSources := map[string]bool{model.CoverArtSourceImg: true, model.CoverArtSourceCDArt: true, model.CoverArtSourceNone: true}
+	if !validCoverArtSources[cfg.CoverArtSource] {
+		return nil, fmt.Errorf("invalid coverArtSource: %q (must be img, cdart, or none)", cfg.CoverArtSource)
+	}
+	if cfg.CoverArtMaxSize < 0 {
+		return nil, errors.New("coverArtMaxSize must be zero or a positive pixel count")
+	}
+
+	if cfg.TrackConcurrency == 0 {
+		cfg.TrackConcurrency = 1
+	}
+	if cfg.TrackConcurrency < 1 || cfg.TrackConcurrency > model.MaxTrackConcurrency {
+		return nil, fmt.Errorf("trackConcurrency must be between 1 and %d", model.MaxTrackConcurrency)
+	}
+
+	for alias, id := range cfg.ArtistAliases {
+		if !helpers.IsArtistID(strings.TrimSpace(id)) {
+			return nil, fmt.Errorf("artistAliases %q must map to a positive numeric artist ID, got %q", alias, id)
+		}
+		cfg.ArtistAliases[alias] = strings.TrimSpace(id)
+	}
+
+	watched := make(map[string]bool, len(cfg.Watch))
+	for i := range cfg.Watch {
+		if err := helpers.NormalizeWatchEntry(&cfg.Watch[i]); err != nil {
+			return nil, fmt.Errorf("watch entry %d: %w", i+1, err)
+		}
+		if watched[cfg.Watch[i].ArtistID] {
+			return nil, fmt.Errorf("watch entry %d: artist %s is listed twice", i+1, cfg.Watch[i].ArtistID)
+		}
+		watched[cfg.Watch[i].ArtistID] = true
+	}
+
+	if err := notify.Validate(cfg.Notifiers); err != nil {
+		return nil, err
+	}
+	cfg.EventLog = strings.TrimSpace(cfg.EventLog)
+	if err := events.Validate(cfg); err != nil {
+		return nil, err
+	}
+
+	for i, format := range cfg.PlaylistFormats {
+		cfg.PlaylistFormats[i] = strings.ToLower(strings.TrimSpace(format))
+	}
+	if err := playlist.ValidateFormats(cfg.PlaylistFormats); err != nil {
+		return nil, err
+	}
+
+	if err := transcode.Validate(cfg.TranscodeProfiles); err != nil {
+		return nil, err
+	}
+	if cfg.TranscodeConcurrency == 0 {
+		cfg.TranscodeConcurrency = model.DefaultTranscodeConcurrency
+	}
+	if cfg.TranscodeConcurrency < 1 || cfg.TranscodeConcurrency > model.MaxTranscodeConcurrency {
+		return nil, fmt.Errorf("transcodeConcurrency must be between 1 and %d", model.MaxTranscodeConcurrency)
+	}
+
+	cfg.FolderTemplate = strings.TrimSpace(cfg.FolderTemplate)
+	cfg.TrackTemplate = strings.TrimSpace(cfg.TrackTemplate)
+	if err := helpers.ValidateNamingTemplates(cfg.FolderTemplate, cfg.TrackTemplate); err != nil {
+		return nil, err
+	}
+
 	cfg.WantRes = ResolveRes[cfg.VideoFormat]
 	cfg.OutPath = strings.TrimSpace(cfg.OutPath)
 	cfg.VideoOutPath = strings.TrimSpace(cfg.VideoOutPath)
@@ -436,6 +509,12 @@ func ParseCfg() (*model.Config, error) {
 		ui.PrintWarning("--skip-videos is deprecated. Use 'nugs grab <id> audio' or set defaultOutputs: \"audio\" in config.json")
 	}
 	cfg.SkipChapters = args.SkipChapters
+	if args.Tracks != "" {
+		if _, err := helpers.ParseTrackSelector(args.Tracks); err != nil {
+			return nil, err
+		}
+		cfg.TrackSelector = args.Tracks
+	}
 	return cfg, nil
 }
 
@@ -478,6 +557,11 @@ func IsMediaModifier(s string) bool {
 	return false
 }
 
+// isListKeyword reports whether s ends an artist name in "nugs list <artist>".
+func isListKeyword(s string) bool {
+	return s == "shows" || s == "latest" || IsMediaModifier(s)
+}
+
 // NormalizeCliAliases maps the updated short command syntax to internal command routing.
 func NormalizeCliAliases(urls []string) []string {
 	if len(urls) == 0 {
@@ -498,9 +582,20 @@ func NormalizeCliAliases(urls []string) []string {
 		if len(urls) == 2 && IsShowCountFilterToken(urls[1]) {
 			return []string{"list", "artists", "shows", urls[1]}
 		}
+		// nugs list billy strings [shows ...] -> nugs list "billy strings" [shows ...]
+		// An unquoted artist name runs up to the first keyword; it never
+		// turns into a venue filter.
+		if _, err := strconv.Atoi(urls[1]); err != nil && urls[1] != "artists" {
+			end := 2
+			for end < len(urls) && !isListKeyword(urls[end]) {
+				end++
+			}
+			normalized := []string{"list", strings.Join(urls[1:end], " ")}
+			return append(normalized, urls[end:]...)
+		}
 		// nugs list <artist_id> ... -> handle media modifiers before venue rewrite
 		if len(urls) >= 3 {
-			if _, err := strconv.Atoi(urls[1]); err == nil && urls[2] != "shows" && urls[2] != "latest" {
+			if urls[2] != "shows" && urls[2] != "latest" {
 				// Check if urls[2] is a media modifier - don't treat as venue
 				if IsMediaModifier(urls[2]) {
 					// nugs list 1125 video -> list 1125 video (no rewrite needed)
@@ -517,7 +612,7 @@ func NormalizeCliAliases(urls []string) []string {
 		if len(urls) >= 2 {
 			return urls[1:]
 		}
-	case "update", "cache", "stats", "latest", "gaps", "coverage":
+	case "update", "cache", "stats", "latest", "gaps", "coverage", "search":
 		// Top-level catalog aliases
 		return append([]string{"catalog", urls[0]}, urls[1:]...)
 	case "refresh":
@@ -601,17 +696,27 @@ type Args struct {
 	ForceVideo   bool     `arg:"--force-video" help:"Deprecated: use a video media modifier"`
 	SkipVideos   bool     `arg:"--skip-videos" help:"Deprecated: use an audio media modifier"`
 	SkipChapters bool     `arg:"--skip-chapters" help:"Skip video chapters"`
+	Tracks       string   `arg:"--tracks" help:"Download only matching tracks, e.g. 1-5,8 or set:2 or song:<regex>"`
 }
 
 func (Args) Description() string {
 	return `Download music and videos from Nugs.net.
 
 Commands:
-  nugs grab <id|url> [audio|video|both]
+  nugs grab <id|url> [audio|video|both] [--tracks 1-5,set:2,song:<regex>]
   nugs <artist-id> latest|full [audio|video|both]
   nugs list [artists|<artist-id>]
   nugs catalog update|cache|stats|latest|list|gaps|coverage|config
+  nugs search <query...>
+  nugs song list|stats|grab <artist> [song...]
+  nugs playlist export <file.m3u8|file.xspf> <query...>
   nugs watch add|remove|list|check|enable|disable
+  nugs verify <path|artist-id>
+  nugs transcode <path|artist-id> [profile...]
+  nugs remote verify <artist-id>
+  nugs queue add|list|remove|reorder|run
+  nugs serve [host:port|unix:/path/to/socket]
+  nugs daemon
   nugs status|cancel|version
 
 Use README.md or docs/COMMANDS.md for complete examples.`
@@ -0,0 +1,193 @@
+package daemon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/jmagar/nugs-cli/internal/cache"
+	"github.com/jmagar/nugs-cli/internal/model"
+	"github.com/jmagar/nugs-cli/internal/ui"
+)
+
+// Daemon states published in the status file.
+const (
+	StateRunning = "running"
+	StateStopped = "stopped"
+	StateStale   = "stale"
+)
+
+const (
+	// minGap keeps a job whose schedule says it is still due from running
+	// back to back, e.g. a catalog refresh that did not move the cache time.
+	minGap = time.Minute
+	// retryDelay is the earliest a failed job runs again.
+	retryDelay = 15 * time.Minute
+
+	statusFileName = "daemon-status.json"
+	lockName       = ".daemon.lock"
+)
+
+// ErrRunning is returned when another process holds the daemon lock.
+var ErrRunning = errors.New("another nugs daemon is already running")
+
+// Job is one scheduled task.
+type Job struct {
+	Name     string
+	Schedule string // human-readable, shown by `nugs status`
+	// Next returns when the job is next due given its last run, which is
+	// zero if it has never run. A time at or before now means due now.
+	Next func(last, now time.Time) (time.Time, error)
+	Run  func(ctx context.Context) error
+}
+
+// Daemon runs jobs against the lock and status file in one directory.
+type Daemon struct {
+	dir   string
+	jobs  []Job
+	now   func() time.Time
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+// New returns a daemon that keeps its lock and status in dir.
+func New(dir string, jobs []Job) *Daemon {
+	return &Daemon{dir: dir, jobs: jobs, now: time.Now, sleep: sleepContext}
+}
+
+// Default returns a daemon in the nugs cache directory.
+func Default(jobs []Job) (*Daemon, error) {
+	cacheDir, err := cache.GetCacheDir()
+	if err != nil {
+		return nil, err
+	}
+	return New(cacheDir, jobs), nil
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// Run takes the daemon lock and runs jobs one at a time, each when it falls
+// due, until ctx is cancelled. Last run times carry over from the previous
+// daemon's status file so a restart does not rerun everything at once. A
+// failing job is logged and retried later; it never stops the daemon.
+func (d *Daemon) Run(ctx context.Context) error {
+	if len(d.jobs) == 0 {
+		return errors.New("no jobs to schedule")
+	}
+	lock, err := cache.AcquireLock(filepath.Join(d.dir, lockName), 0)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrRunning, err)
+	}
+	defer lock.Release()
+
+	previous, _ := readStatus(d.statusPath())
+	status := model.DaemonStatus{
+		PID:       os.Getpid(),
+		State:     StateRunning,
+		StartedAt: d.now().UTC(),
+		Jobs:      make([]model.DaemonJob, len(d.jobs)),
+	}
+	for i, job := range d.jobs {
+		status.Jobs[i] = model.DaemonJob{Name: job.Name, Schedule: job.Schedule}
+		for _, prev := range previous.Jobs {
+			if prev.Name == job.Name {
+				status.Jobs[i].LastRun = prev.LastRun
+				status.Jobs[i].LastError = prev.LastError
+			}
+		}
+	}
+	defer func() {
+		status.State = StateStopped
+		for i := range status.Jobs {
+			status.Jobs[i].Running = false
+			status.Jobs[i].NextRun = time.Time{}
+		}
+		d.publish(&status)
+	}()
+
+	for {
+		i, at, err := d.schedule(&status)
+		if err != nil {
+			return err
+		}
+		d.publish(&status)
+		if wait := at.Sub(d.now()); wait > 0 {
+			if err := d.sleep(ctx, wait); err != nil {
+				return nil
+			}
+		}
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		job := d.jobs[i]
+		status.Jobs[i].Running = true
+		d.publish(&status)
+		ui.PrintInfo(fmt.Sprintf("Running %s", job.Name))
+		runErr := job.Run(ctx)
+		status.Jobs[i].Running = false
+		status.Jobs[i].LastRun = d.now().UTC()
+		status.Jobs[i].LastError = ""
+		if runErr != nil {
+			status.Jobs[i].LastError = runErr.Error()
+		}
+		if ctx.Err() != nil {
+			return nil
+		}
+		if runErr != nil {
+			ui.PrintWarning(fmt.Sprintf("%s failed: %v", job.Name, runErr))
+		}
+	}
+}
+
+// schedule sets every job's next run and returns the earliest one.
+func (d *Daemon) schedule(status *model.DaemonStatus) (int, time.Time, error) {
+	now := d.now()
+	earliest := -1
+	var earliestAt time.Time
+	for i, job := range d.jobs {
+		last := status.Jobs[i].LastRun
+		at, err := job.Next(last, now)
+		if err != nil {
+			return 0, time.Time{}, fmt.Errorf("%s: %w", job.Name, err)
+		}
+		if !last.IsZero() {
+			gap := minGap
+			if status.Jobs[i].LastError != "" {
+				gap = retryDelay
+			}
+			if floor := last.Add(gap); at.Before(floor) {
+				at = floor
+			}
+		}
+		status.Jobs[i].NextRun = at.UTC()
+		if earliest < 0 || at.Before(earliestAt) {
+			earliest, earliestAt = i, at
+		}
+	}
+	return earliest, earliestAt, nil
+}
+
+func (d *Daemon) statusPath() string {
+	return filepath.Join(d.dir, statusFileName)
+}
+
+// publish writes status for `nugs status`. Failures only warn: the schedule
+// keeps running without it.
+func (d *Daemon) publish(status *model.DaemonStatus) {
+	status.UpdatedAt = d.now().UTC()
+	if err := writeStatus(d.statusPath(), *status); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to write daemon status: %v\n", err)
+	}
+}
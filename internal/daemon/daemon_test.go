@@ -0,0 +1,155 @@
+package daemon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/jmagar/nugs-cli/internal/cache"
+	"github.com/jmagar/nugs-cli/internal/model"
+)
+
+var testStart = time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+func testDaemon(t *testing.T, jobs []Job) (*Daemon, *time.Time) {
+	t.Helper()
+	now := testStart
+	d := New(t.TempDir(), jobs)
+	d.now = func() time.Time { return now }
+	d.sleep = func(ctx context.Context, dur time.Duration) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		now = now.Add(dur)
+		return nil
+	}
+	return d, &now
+}
+
+func every(interval time.Duration) func(last, now time.Time) (time.Time, error) {
+	return func(last, now time.Time) (time.Time, error) {
+		if last.IsZero() {
+			return now, nil
+		}
+		return last.Add(interval), nil
+	}
+}
+
+// recorder logs "name@minutes" for each run and cancels after limit runs.
+type recorder struct {
+	now    *time.Time
+	cancel context.CancelFunc
+	limit  int
+	runs   []string
+}
+
+func (r *recorder) job(name string, next func(last, now time.Time) (time.Time, error), err error) Job {
+	return Job{Name: name, Schedule: "test", Next: next, Run: func(context.Context) error {
+		r.runs = append(r.runs, fmt.Sprintf("%s@%d", name, int(r.now.Sub(testStart).Minutes())))
+		if len(r.runs) >= r.limit {
+			r.cancel()
+		}
+		return err
+	}}
+}
+
+func TestDaemonRunsJobsWhenDue(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	rec := &recorder{cancel: cancel, limit: 6}
+	d, now := testDaemon(t, nil)
+	rec.now = now
+	d.jobs = []Job{
+		rec.job("watch", every(time.Hour), nil),
+		rec.job("refresh", every(90*time.Minute), nil),
+	}
+	if err := d.Run(ctx); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	want := []string{"watch@0", "refresh@0", "watch@60", "refresh@90", "watch@120", "watch@180"}
+	if !slices.Equal(rec.runs, want) {
+		t.Fatalf("runs = %v, want %v", rec.runs, want)
+	}
+
+	status, err := readStatus(d.statusPath())
+	if err != nil {
+		t.Fatalf("readStatus: %v", err)
+	}
+	if status.State != StateStopped || len(status.Jobs) != 2 {
+		t.Fatalf("status = %+v, want stopped with 2 jobs", status)
+	}
+	if got := status.Jobs[0].LastRun; !got.Equal(testStart.Add(3 * time.Hour)) {
+		t.Fatalf("watch LastRun = %v, want %v", got, testStart.Add(3*time.Hour))
+	}
+}
+
+func TestDaemonDelaysFailedJob(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	rec := &recorder{cancel: cancel, limit: 3}
+	d, now := testDaemon(t, nil)
+	rec.now = now
+	d.jobs = []Job{rec.job("refresh", every(0), errors.New("offline"))}
+	if err := d.Run(ctx); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	want := []string{"refresh@0", "refresh@15", "refresh@30"}
+	if !slices.Equal(rec.runs, want) {
+		t.Fatalf("runs = %v, want %v", rec.runs, want)
+	}
+	status, err := readStatus(d.statusPath())
+	if err != nil {
+		t.Fatalf("readStatus: %v", err)
+	}
+	if status.Jobs[0].LastError != "offline" {
+		t.Fatalf("LastError = %q, want %q", status.Jobs[0].LastError, "offline")
+	}
+}
+
+func TestDaemonResumesFromPreviousStatus(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	rec := &recorder{cancel: cancel, limit: 1}
+	d, now := testDaemon(t, nil)
+	rec.now = now
+	d.jobs = []Job{rec.job("watch", every(time.Hour), nil)}
+	previous := model.DaemonStatus{State: StateStopped, Jobs: []model.DaemonJob{
+		{Name: "watch", LastRun: testStart.Add(-20 * time.Minute)},
+	}}
+	if err := writeStatus(d.statusPath(), previous); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Run(ctx); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if want := []string{"watch@40"}; !slices.Equal(rec.runs, want) {
+		t.Fatalf("runs = %v, want %v", rec.runs, want)
+	}
+}
+
+func TestDaemonSingleInstance(t *testing.T) {
+	d, _ := testDaemon(t, []Job{{Name: "watch", Next: every(time.Hour), Run: func(context.Context) error { return nil }}})
+	lock, err := cache.AcquireLock(filepath.Join(d.dir, lockName), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lock.Release()
+	if err := d.Run(context.Background()); !errors.Is(err, ErrRunning) {
+		t.Fatalf("Run error = %v, want ErrRunning", err)
+	}
+}
+
+func TestDaemonStopsOnJobScheduleError(t *testing.T) {
+	d, _ := testDaemon(t, []Job{{
+		Name: "refresh",
+		Next: func(time.Time, time.Time) (time.Time, error) { return time.Time{}, errors.New("bad timezone") },
+		Run:  func(context.Context) error { return nil },
+	}})
+	if err := d.Run(context.Background()); err == nil || err.Error() != "refresh: bad timezone" {
+		t.Fatalf("Run error = %v, want schedule error", err)
+	}
+}
@@ -0,0 +1,5 @@
+// Package daemon implements the in-process scheduler behind `nugs daemon`:
+// it runs jobs such as watch checks and catalog refreshes on their own
+// schedules, holds a single-instance lock in the cache directory, and
+// publishes last and next run times for `nugs status`.
+package daemon
@@ -0,0 +1,94 @@
+package daemon
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/jmagar/nugs-cli/internal/cache"
+	"github.com/jmagar/nugs-cli/internal/model"
+	"github.com/jmagar/nugs-cli/internal/runtime"
+	"github.com/jmagar/nugs-cli/internal/ui"
+)
+
+// ReadStatus returns the status published by the last daemon, reporting a
+// running daemon whose process has died as stale.
+func ReadStatus() (model.DaemonStatus, error) {
+	cacheDir, err := cache.GetCacheDir()
+	if err != nil {
+		return model.DaemonStatus{}, err
+	}
+	status, err := readStatus(filepath.Join(cacheDir, statusFileName))
+	if err != nil {
+		return model.DaemonStatus{}, err
+	}
+	if status.State == StateRunning && status.PID > 0 && !runtime.IsProcessAlive(status.PID) {
+		status.State = StateStale
+	}
+	return status, nil
+}
+
+func readStatus(path string) (model.DaemonStatus, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return model.DaemonStatus{}, err
+	}
+	var status model.DaemonStatus
+	if err := json.Unmarshal(data, &status); err != nil {
+		return model.DaemonStatus{}, fmt.Errorf("failed to parse daemon status: %w", err)
+	}
+	return status, nil
+}
+
+func writeStatus(path string, status model.DaemonStatus) error {
+	data, err := json.MarshalIndent(status, "", "  ")
+	if err != nil {
+		return err
+	}
+	return runtime.WriteFileAtomic(path, data, 0644)
+}
+
+// PrintStatus shows the daemon's jobs with their last and next runs. It
+// prints nothing when no daemon has ever run.
+func PrintStatus() {
+	status, err := ReadStatus()
+	if err != nil {
+		if !os.IsNotExist(err) {
+			fmt.Printf("Daemon status unavailable (%v)\n", err)
+		}
+		return
+	}
+	ui.PrintHeader("Nugs Daemon")
+	stateColor := ui.ColorGreen
+	if status.State != StateRunning {
+		stateColor = ui.ColorYellow
+	}
+	ui.PrintKeyValue("State", status.State, stateColor)
+	ui.PrintKeyValue("PID", fmt.Sprintf("%d", status.PID), ui.ColorCyan)
+	ui.PrintKeyValue("Started", formatTime(status.StartedAt), ui.ColorCyan)
+	ui.PrintKeyValue("Updated", formatTime(status.UpdatedAt), ui.ColorCyan)
+
+	for _, job := range status.Jobs {
+		fmt.Println()
+		ui.PrintKeyValue("Job", job.Name, ui.ColorCyan)
+		ui.PrintKeyValue("Schedule", job.Schedule, ui.ColorCyan)
+		ui.PrintKeyValue("Last run", formatTime(job.LastRun), ui.ColorCyan)
+		if job.Running {
+			ui.PrintKeyValue("Next run", "running now", ui.ColorGreen)
+		} else {
+			ui.PrintKeyValue("Next run", formatTime(job.NextRun), ui.ColorCyan)
+		}
+		if job.LastError != "" {
+			ui.PrintKeyValue("Last error", job.LastError, ui.ColorRed)
+		}
+	}
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Local().Format("2006-01-02 15:04")
+}
@@ -0,0 +1,176 @@
+package download
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	_ "image/png" // register PNG decoding for show artwork
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jmagar/nugs-cli/internal/api"
+	"github.com/jmagar/nugs-cli/internal/model"
+	"github.com/jmagar/nugs-cli/internal/tagging"
+)
+
+// coverArtJPEGQuality is used whenever artwork has to be re-encoded.
+const coverArtJPEGQuality = 90
+
+// coverArtEnabled reports whether artwork should be fetched and embedded.
+func coverArtEnabled(cfg *model.Config) bool {
+	return cfg.CoverArtSource != model.CoverArtSourceNone
+}
+
+// selectCoverArtPath picks the artwork path for a show according to source.
+// The cdart source falls back to the show image when no disc art is listed.
+func selectCoverArtPath(meta *model.AlbArtResp, source string) string {
+	if meta == nil || source == model.CoverArtSourceNone {
+		return ""
+	}
+	if source == model.CoverArtSourceCDArt {
+		for _, art := range meta.CdArtWorkList {
+			if path := strings.TrimSpace(art.ArtWorkPath); path != "" {
+				return path
+			}
+		}
+	}
+	best, bestArea := strings.TrimSpace(meta.Img.URL), meta.Img.Width*meta.Img.Height
+	for _, pic := range meta.Pics {
+		path := strings.TrimSpace(pic.URL)
+		if path == "" {
+			continue
+		}
+		if area := pic.Width * pic.Height; best == "" || area > bestArea {
+			best, bestArea = path, area
+		}
+	}
+	if best == "" {
+		best = strings.TrimSpace(meta.ExtImage)
+	}
+	return best
+}
+
+// scaleImage downsamples src with a box filter so its longest edge is at most
+// maxSize pixels. Images already within bounds are returned unchanged.
+func scaleImage(src image.Image, maxSize int) image.Image {
+	bounds := src.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	if maxSize <= 0 || (w <= maxSize && h <= maxSize) {
+		return src
+	}
+	dw, dh := maxSize, maxSize
+	if w >= h {
+		dh = max(1, h*maxSize/w)
+	} else {
+		dw = max(1, w*maxSize/h)
+	}
+	dst := image.NewRGBA64(image.Rect(0, 0, dw, dh))
+	for y := 0; y < dh; y++ {
+		y0, y1 := bounds.Min.Y+y*h/dh, bounds.Min.Y+(y+1)*h/dh
+		for x := 0; x < dw; x++ {
+			x0, x1 := bounds.Min.X+x*w/dw, bounds.Min.X+(x+1)*w/dw
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					cr, cg, cb, ca := src.At(sx, sy).RGBA()
+					r, g, b, a = r+uint64(cr), g+uint64(cg), b+uint64(cb), a+uint64(ca)
+					n++
+				}
+			}
+			dst.SetRGBA64(x, y, color.RGBA64{R: uint16(r / n), G: uint16(g / n), B: uint16(b / n), A: uint16(a / n)})
+		}
+	}
+	return dst
+}
+
+// prepareCoverArt decodes downloaded artwork, downscales it to maxSize, and
+// returns it as a JPEG. Original JPEG bytes are kept when no scaling is needed.
+func prepareCoverArt(data []byte, maxSize int) (*tagging.Picture, error) {
+	img, format, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("decode artwork: %w", err)
+	}
+	scaled := scaleImage(img, maxSize)
+	bounds := scaled.Bounds()
+	pic := &tagging.Picture{MIMEType: "image/jpeg", Width: bounds.Dx(), Height: bounds.Dy(), Data: data}
+	if format == "jpeg" && scaled == img {
+		return pic, nil
+	}
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, scaled, &jpeg.Options{Quality: coverArtJPEGQuality}); err != nil {
+		return nil, fmt.Errorf("encode artwork: %w", err)
+	}
+	pic.Data = buf.Bytes()
+	return pic, nil
+}
+
+// writeCoverArtFile writes data to folder.jpg via a temp file and rename so a
+// partial download never leaves truncated artwork behind.
+func writeCoverArtFile(albumPath string, data []byte) error {
+	tmp, err := os.CreateTemp(albumPath, "."+model.CoverArtFileName+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("create temp file: %w", err)
+	}
+	tmpPath := tmp.Name()
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("write temp file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("close temp file: %w", err)
+	}
+	if err := os.Rename(tmpPath, filepath.Join(albumPath, model.CoverArtFileName)); err != nil {
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("rename temp file: %w", err)
+	}
+	return nil
+}
+
+// saveCoverArt fetches the show's artwork and stores it as folder.jpg in
+// albumPath. Existing artwork is left alone. Failures are warnings: a show
+// without a cover is still a complete download.
+func saveCoverArt(ctx context.Context, cfg *model.Config, meta *model.AlbArtResp, albumPath string, progressBox *model.ProgressBoxState) {
+	if !coverArtEnabled(cfg) {
+		return
+	}
+	if _, err := os.Stat(filepath.Join(albumPath, model.CoverArtFileName)); err == nil {
+		return
+	}
+	artPath := selectCoverArtPath(meta, cfg.CoverArtSource)
+	if artPath == "" {
+		return
+	}
+	data, err := api.GetArtwork(ctx, artPath)
+	if err != nil {
+		reportWarning(fmt.Sprintf("Failed to download cover art: %v", err), progressBox)
+		return
+	}
+	pic, err := prepareCoverArt(data, cfg.CoverArtMaxSize)
+	if err != nil {
+		reportWarning(fmt.Sprintf("Failed to process cover art: %v", err), progressBox)
+		return
+	}
+	if err := writeCoverArtFile(albumPath, pic.Data); err != nil {
+		reportWarning(fmt.Sprintf("Failed to save cover art: %v", err), progressBox)
+	}
+}
+
+// loadCoverArt reads folder.jpg from dir for embedding. It returns nil when the
+// folder has no artwork or the file cannot be decoded.
+func loadCoverArt(dir string) *tagging.Picture {
+	data, err := os.ReadFile(filepath.Join(dir, model.CoverArtFileName))
+	if err != nil {
+		return nil
+	}
+	imgCfg, err := jpeg.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil
+	}
+	return &tagging.Picture{MIMEType: "image/jpeg", Width: imgCfg.Width, Height: imgCfg.Height, Data: data}
+}
@@ -0,0 +1,123 @@
+package download
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jmagar/nugs-cli/internal/api"
+	"github.com/jmagar/nugs-cli/internal/model"
+)
+
+func TestSelectCoverArtPath(t *testing.T) {
+	var meta model.AlbArtResp
+	err := json.Unmarshal([]byte(`{
+		"extImage": "ext.jpg",
+		"img": {"url": "/images/shows/small.jpg", "width": 300, "height": 300},
+		"pics": [
+			{"url": "/images/shows/small.jpg", "width": 300, "height": 300},
+			{"url": "/images/shows/large.jpg", "width": 1200, "height": 1200}
+		],
+		"cdArtWorkList": [
+			{"discNumber": 1, "artWorkPath": "/images/cdart/disc1.jpg"},
+			{"discNumber": 2, "artWorkPath": "/images/cdart/disc2.jpg"}
+		]
+	}`), &meta)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		source string
+		want   string
+	}{
+		{"", "/images/shows/large.jpg"},
+		{model.CoverArtSourceImg, "/images/shows/large.jpg"},
+		{model.CoverArtSourceCDArt, "/images/cdart/disc1.jpg"},
+		{model.CoverArtSourceNone, ""},
+	}
+	for _, tc := range cases {
+		if got := selectCoverArtPath(&meta, tc.source); got != tc.want {
+			t.Errorf("selectCoverArtPath(%q) = %q, want %q", tc.source, got, tc.want)
+		}
+	}
+
+	bare := &model.AlbArtResp{ExtImage: "ext.jpg"}
+	if got := selectCoverArtPath(bare, model.CoverArtSourceCDArt); got != "ext.jpg" {
+		t.Errorf("fallback = %q, want extImage", got)
+	}
+}
+
+func TestSaveCoverArtDownscalesToFolderJPEG(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 40, 20))
+	for y := 0; y < 20; y++ {
+		for x := 0; x < 40; x++ {
+			src.Set(x, y, color.RGBA{R: 200, G: 40, B: 40, A: 255})
+		}
+	}
+	var pngBytes bytes.Buffer
+	if err := png.Encode(&pngBytes, src); err != nil {
+		t.Fatal(err)
+	}
+
+	var requested string
+	client := &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		requested = req.URL.String()
+		resp := httpResponse(http.StatusOK, "")
+		resp.Body = io.NopCloser(bytes.NewReader(pngBytes.Bytes()))
+		return resp, nil
+	})}
+	ctx := api.WithHTTPClient(context.Background(), client)
+
+	meta := &model.AlbArtResp{}
+	meta.Img.URL = "/images/shows/cover.png"
+	albumPath := t.TempDir()
+	cfg := &model.Config{CoverArtSource: model.CoverArtSourceImg, CoverArtMaxSize: 10}
+	saveCoverArt(ctx, cfg, meta, albumPath, nil)
+
+	if requested != api.ArtworkBaseURL+"/images/shows/cover.png" {
+		t.Fatalf("requested %q, want resolved artwork URL", requested)
+	}
+	data, err := os.ReadFile(filepath.Join(albumPath, model.CoverArtFileName))
+	if err != nil {
+		t.Fatalf("folder.jpg not written: %v", err)
+	}
+	imgCfg, err := jpeg.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("folder.jpg is not a JPEG: %v", err)
+	}
+	if imgCfg.Width != 10 || imgCfg.Height != 5 {
+		t.Fatalf("folder.jpg is %dx%d, want 10x5", imgCfg.Width, imgCfg.Height)
+	}
+
+	pic := loadCoverArt(albumPath)
+	if pic == nil || pic.Width != 10 || !bytes.Equal(pic.Data, data) {
+		t.Fatalf("loadCoverArt = %+v, want folder.jpg contents", pic)
+	}
+}
+
+func TestSaveCoverArtSkippedWhenDisabled(t *testing.T) {
+	client := &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		t.Fatalf("unexpected artwork request to %s", req.URL)
+		return nil, nil
+	})}
+	ctx := api.WithHTTPClient(context.Background(), client)
+
+	meta := &model.AlbArtResp{}
+	meta.Img.URL = "/images/shows/cover.jpg"
+	albumPath := t.TempDir()
+	saveCoverArt(ctx, &model.Config{CoverArtSource: model.CoverArtSourceNone}, meta, albumPath, nil)
+
+	if _, err := os.Stat(filepath.Join(albumPath, model.CoverArtFileName)); !os.IsNotExist(err) {
+		t.Fatalf("folder.jpg should not exist when cover art is disabled, stat err = %v", err)
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"net/http"
 	"os"
 	"os/exec"
+	"path"
 	"path/filepath"
 	"regexp"
 	"sort"
@@ -24,7 +25,9 @@ import (
 	"github.com/jmagar/nugs-cli/internal/api"
 	"github.com/jmagar/nugs-cli/internal/helpers"
 	"github.com/jmagar/nugs-cli/internal/model"
+	"github.com/jmagar/nugs-cli/internal/playlist"
 	"github.com/jmagar/nugs-cli/internal/ui"
+	"github.com/jmagar/nugs-cli/internal/verify"
 )
 
 var bitrateRegex = regexp.MustCompile(`[\w]+(?:_(\d+)k_v\d+)`)
@@ -50,16 +53,16 @@ func updateWriteCounterProgress(wc *model.WriteCounter, n int) int64 {
 	var speed int64
 	toDivideBy := time.Now().UnixMilli() - wc.StartTime
 	if toDivideBy != 0 {
-		speed = int64(wc.Downloaded) * model.KBpsDivisor / toDivideBy
+		speed = (wc.Downloaded - wc.ResumedAt) * model.KBpsDivisor / toDivideBy
 	}
 	return speed
 }
 
 // writeCounterWrite implements the io.Writer interface for WriteCounter.
 // It updates download progress and calls the appropriate progress callback.
-func writeCounterWrite(wc *model.WriteCounter, p []byte, deps *Deps) (int, error) {
+func writeCounterWrite(ctx context.Context, wc *model.WriteCounter, p []byte, deps *Deps) (int, error) {
 	if deps.WaitIfPausedOrCancelled != nil {
-		if err := deps.WaitIfPausedOrCancelled(); err != nil {
+		if err := deps.WaitIfPausedOrCancelled(ctx); err != nil {
 			return 0, err
 		}
 	}
@@ -75,68 +78,61 @@ func writeCounterWrite(wc *model.WriteCounter, p []byte, deps *Deps) (int, error
 }
 
 // DownloadTrack downloads a single audio track file from the given URL.
+// Interrupted transfers leave a .part file that the next call resumes.
 func DownloadTrack(ctx context.Context, trackPath, _url string, onProgress func(downloaded, total, speed int64), printNewline bool, deps *Deps) error {
+	_, err := downloadTrack(ctx, trackPath, _url, onProgress, printNewline, deps)
+	return err
+}
+
+// downloadTrack is DownloadTrack returning the server's Content-Length for the
+// full file, or 0 when unknown.
+func downloadTrack(ctx context.Context, trackPath, _url string, onProgress func(downloaded, total, speed int64), printNewline bool, deps *Deps) (int64, error) {
 	if deps.WaitIfPausedOrCancelled != nil {
-		if err := deps.WaitIfPausedOrCancelled(); err != nil {
-			return err
+		if err := deps.WaitIfPausedOrCancelled(ctx); err != nil {
+			return 0, err
 		}
 	}
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, _url, nil)
-	if err != nil {
-		return err
-	}
-	req.Header.Add("Referer", api.PlayerURL)
-	req.Header.Add("User-Agent", api.UserAgent)
-	req.Header.Add("Range", "bytes=0-")
-	do, err := api.Do(req)
-	if err != nil {
-		return err
-	}
-	defer func() {
-		_, _ = io.Copy(io.Discard, io.LimitReader(do.Body, 64<<10))
-		_ = do.Body.Close()
-	}()
-	if do.StatusCode != http.StatusOK && do.StatusCode != http.StatusPartialContent {
-		return errors.New(do.Status)
-	}
-	f, err := os.OpenFile(trackPath, os.O_CREATE|os.O_WRONLY, 0644)
-	if err != nil {
-		return err
-	}
-	totalBytes := do.ContentLength
-	totalStr := model.UnknownSizeLabelLower
-	if totalBytes > 0 {
-		totalStr = humanize.Bytes(uint64(totalBytes))
-	}
-	counter := &writeCounterAdapter{
-		wc: &model.WriteCounter{
-			Total:      totalBytes,
-			TotalStr:   totalStr,
-			StartTime:  time.Now().UnixMilli(),
-			OnProgress: onProgress,
+	header := make(http.Header)
+	header.Add("Referer", api.PlayerURL)
+	header.Add("User-Agent", api.UserAgent)
+	size, err := downloadResumable(ctx, resumableDownload{
+		URL:      _url,
+		DestPath: trackPath,
+		Header:   header,
+		Progress: func(total, offset int64) io.Writer {
+			totalStr := model.UnknownSizeLabelLower
+			if total > 0 {
+				totalStr = humanize.Bytes(uint64(total))
+			}
+			return &writeCounterAdapter{
+				ctx: ctx,
+				wc: &model.WriteCounter{
+					Total:      total,
+					TotalStr:   totalStr,
+					Downloaded: offset,
+					ResumedAt:  offset,
+					StartTime:  time.Now().UnixMilli(),
+					OnProgress: onProgress,
+				},
+				deps: deps,
+			}
 		},
-		deps: deps,
-	}
-	_, err = io.Copy(f, io.TeeReader(do.Body, counter))
-	closeErr := f.Close()
+	})
 	if printNewline {
 		fmt.Println("")
 	}
-	if err = errors.Join(err, closeErr); err != nil {
-		os.Remove(trackPath)
-		return err
-	}
-	return nil
+	return size, err
 }
 
 // writeCounterAdapter wraps WriteCounter to satisfy io.Writer using Deps.
 type writeCounterAdapter struct {
+	ctx  context.Context
 	wc   *model.WriteCounter
 	deps *Deps
 }
 
 func (a *writeCounterAdapter) Write(p []byte) (int, error) {
-	return writeCounterWrite(a.wc, p, a.deps)
+	return writeCounterWrite(a.ctx, a.wc, p, a.deps)
 }
 
 // ExtractBitrate extracts the bitrate from a manifest URL.
@@ -399,6 +395,7 @@ func HlsOnly(ctx context.Context, trackPath, manUrl, ffmpegNameStr string, onPro
 	tempPath := tempFile.Name()
 	tempFile.Close()
 	defer os.Remove(tempPath)
+	defer removePartFiles(tempPath+partFileSuffix, tempPath+partSidecarSuffix)
 
 	err = DownloadTrack(ctx, tempPath, tsUrl, onProgress, printNewline, deps)
 	if err != nil {
@@ -439,7 +436,7 @@ func selectTrackQuality(ctx context.Context, track *model.Track, streamParams *m
 		if qual != nil {
 			isHlsOnly := strings.Contains(track.TrackURL, ".m3u8?")
 			if isHlsOnly {
-				ui.PrintInfo("HLS-only track. Only AAC is available, tags currently unsupported")
+				ui.PrintInfo("HLS-only track. Only AAC is available")
 				if err := ParseHlsMasterContext(ctx, qual); err != nil {
 					return nil, true, wantFmt, err
 				}
@@ -472,7 +469,7 @@ func selectTrackQuality(ctx context.Context, track *model.Track, streamParams *m
 
 	isHlsOnly := CheckIfHlsOnly(quals)
 	if isHlsOnly {
-		ui.PrintInfo("HLS-only track. Only AAC is available, tags currently unsupported")
+		ui.PrintInfo("HLS-only track. Only AAC is available")
 		chosenQual := quals[0]
 		if err := ParseHlsMasterContext(ctx, chosenQual); err != nil {
 			return nil, true, wantFmt, err
@@ -495,7 +492,8 @@ func selectTrackQuality(ctx context.Context, track *model.Track, streamParams *m
 
 // buildTrackProgressCallback creates a progress callback for track downloads.
 // It updates both the progress box (if provided) and falls back to simple progress display.
-func buildTrackProgressCallback(progressBox *model.ProgressBoxState, deps *Deps, trackNum, trackTotal int, accumulatedBeforeCurrent int64) func(downloaded, total, speed int64) {
+// With parallel downloads the progress box shows the aggregate of every in-flight track.
+func buildTrackProgressCallback(progressBox *model.ProgressBoxState, deps *Deps, trackNum, trackTotal int) func(downloaded, total, speed int64) {
 	return func(downloaded, total, speed int64) {
 		if progressBox == nil {
 			trackPercentage := 0
@@ -515,51 +513,46 @@ func buildTrackProgressCallback(progressBox *model.ProgressBoxState, deps *Deps,
 			return
 		}
 
+		progressBox.Mu.Lock()
+		active := progressBox.UpdateActiveTrackLocked(trackNum, downloaded, total, speed)
+
 		trackPercentage := 0
 		trackTotalStr := model.UnknownSizeLabelLower
-		if total > 0 {
-			trackPercentage = int((float64(downloaded) / float64(total)) * model.MaxProgressPercent)
+		if active.Total > 0 {
+			trackPercentage = int((float64(active.Downloaded) / float64(active.Total)) * model.MaxProgressPercent)
 			if trackPercentage > model.MaxProgressPercent {
 				trackPercentage = model.MaxProgressPercent
 			}
-			trackTotalStr = humanize.Bytes(uint64(total))
-		}
-		trackProgress := 0.0
-		if total > 0 {
-			trackProgress = float64(downloaded) / float64(total)
-			if trackProgress > 1 {
-				trackProgress = 1
-			}
+			trackTotalStr = humanize.Bytes(uint64(active.Total))
 		}
-		showPercentage := int(((float64(trackNum-1) + trackProgress) / float64(trackTotal)) * model.MaxProgressPercent)
+		showPercentage := int(((float64(progressBox.FinishedTracks) + active.Progress) / float64(trackTotal)) * model.MaxProgressPercent)
 		if showPercentage > model.MaxProgressPercent {
 			showPercentage = model.MaxProgressPercent
 		}
 
-		progressBox.Mu.Lock()
 		progressBox.DownloadPercent = trackPercentage
-		progressBox.DownloadSpeed = humanize.Bytes(uint64(speed))
-		progressBox.Downloaded = humanize.Bytes(uint64(downloaded))
+		progressBox.DownloadSpeed = humanize.Bytes(uint64(active.Speed))
+		progressBox.Downloaded = humanize.Bytes(uint64(active.Downloaded))
 		progressBox.DownloadTotal = trackTotalStr
 		progressBox.ShowPercent = showPercentage
-		progressBox.ShowDownloaded = humanize.Bytes(uint64(accumulatedBeforeCurrent + downloaded))
+		progressBox.ShowDownloaded = humanize.Bytes(uint64(progressBox.AccumulatedBytes + active.Downloaded))
 
-		// Update ShowTotal dynamically using the full track size (not bytes downloaded so far)
+		// Update ShowTotal dynamically using full track sizes (not bytes downloaded so far)
 		// so the displayed total stays stable throughout each track's download.
-		if total > 0 {
-			// Estimate: completed tracks + full current track size + remaining tracks at same size
-			estimatedRemaining := total * int64(trackTotal-trackNum)
-			estimatedTotal := accumulatedBeforeCurrent + total + estimatedRemaining
+		if active.Sized > 0 {
+			// Estimate: completed tracks + in-flight track sizes + unstarted tracks at the average in-flight size
+			notStarted := max(trackTotal-progressBox.FinishedTracks-active.Count, 0)
+			estimatedRemaining := active.Total / int64(active.Sized) * int64(notStarted)
+			estimatedTotal := progressBox.AccumulatedBytes + active.Total + estimatedRemaining
 			progressBox.ShowTotal = humanize.Bytes(uint64(estimatedTotal))
 		}
 
-		if speed > 0 && total > 0 && downloaded < total {
+		if active.Speed > 0 && active.Remaining > 0 {
 			if deps.UpdateSpeedHistory != nil {
-				progressBox.SpeedHistory = deps.UpdateSpeedHistory(progressBox.SpeedHistory, float64(speed))
+				progressBox.SpeedHistory = deps.UpdateSpeedHistory(progressBox.SpeedHistory, float64(active.Speed))
 			}
-			remaining := total - downloaded
 			if deps.CalculateETA != nil {
-				progressBox.DownloadETA = deps.CalculateETA(progressBox.SpeedHistory, remaining)
+				progressBox.DownloadETA = deps.CalculateETA(progressBox.SpeedHistory, active.Remaining)
 			}
 		} else {
 			progressBox.DownloadETA = ""
@@ -573,16 +566,53 @@ func buildTrackProgressCallback(progressBox *model.ProgressBoxState, deps *Deps,
 }
 
 // ProcessTrack downloads a single track, handling quality selection and progress updates.
-func ProcessTrack(ctx context.Context, folPath string, trackNum, trackTotal int, cfg *model.Config, track *model.Track, streamParams *model.StreamParams, progressBox *model.ProgressBoxState, deps *Deps) error {
+// Unless cfg.SkipVerify is set, new tracks are verified before tagging and
+// existing tracks are verified before being skipped, with corrupt ones fetched again.
+// meta supplies the show-level fields embedded as tags; nil skips tagging.
+func ProcessTrack(ctx context.Context, folPath string, trackNum, trackTotal int, cfg *model.Config, track *model.Track, meta *model.AlbArtResp, streamParams *model.StreamParams, progressBox *model.ProgressBoxState, deps *Deps) error {
+	_, err := processTrack(ctx, folPath, wholeTrackPosition(trackNum, trackTotal), cfg, track, meta, streamParams, progressBox, deps)
+	return err
+}
+
+// trackPosition places a track in a download. Num and Total count the tracks
+// being downloaded and drive progress; FileNum and FileTotal number the file
+// name and tags, and differ only when --tracks picks part of a release.
+type trackPosition struct {
+	Num, Total         int
+	FileNum, FileTotal int
+}
+
+func wholeTrackPosition(trackNum, trackTotal int) trackPosition {
+	return trackPosition{Num: trackNum, Total: trackTotal, FileNum: trackNum, FileTotal: trackTotal}
+}
+
+// trackFile describes the file ProcessTrack left on disk for a track.
+type trackFile struct {
+	Path   string
+	Format int
+}
+
+// trackPaths lists the paths of the tracks that were written.
+func trackPaths(files []trackFile) []string {
+	paths := make([]string, 0, len(files))
+	for _, file := range files {
+		if file.Path != "" {
+			paths = append(paths, file.Path)
+		}
+	}
+	return paths
+}
+
+func processTrack(ctx context.Context, folPath string, pos trackPosition, cfg *model.Config, track *model.Track, meta *model.AlbArtResp, streamParams *model.StreamParams, progressBox *model.ProgressBoxState, deps *Deps) (trackFile, error) {
 	if deps.WaitIfPausedOrCancelled != nil {
-		if err := deps.WaitIfPausedOrCancelled(); err != nil {
-			return err
+		if err := deps.WaitIfPausedOrCancelled(ctx); err != nil {
+			return trackFile{}, err
 		}
 	}
 	origWantFmt := cfg.Format
 	chosenQual, isHlsOnly, resolvedFmt, err := selectTrackQuality(ctx, track, streamParams, origWantFmt)
 	if err != nil {
-		return err
+		return trackFile{}, err
 	}
 	if !isHlsOnly && resolvedFmt != origWantFmt && origWantFmt != 4 {
 		ui.PrintInfo("Unavailable in your chosen format")
@@ -593,17 +623,31 @@ func ProcessTrack(ctx context.Context, folPath string, trackNum, trackTotal int,
 		}
 	}
 	if chosenQual == nil {
-		return fmt.Errorf("no supported format found for track %d", trackNum)
+		return trackFile{}, fmt.Errorf("no supported format found for track %d", pos.FileNum)
 	}
 
-	trackFname := fmt.Sprintf(
-		"%02d. %s%s", trackNum, helpers.Sanitise(track.SongTitle), chosenQual.Extension,
-	)
+	trackFname := helpers.TrackFileName(cfg, meta, helpers.TrackNameInfo{
+		Number: pos.FileNum,
+		Total:  pos.FileTotal,
+		Disc:   trackDiscNumber(track),
+		Title:  track.SongTitle,
+		ID:     track.TrackID,
+	}) + chosenQual.Extension
 	trackPath := filepath.Join(folPath, trackFname)
+	file := trackFile{Path: trackPath, Format: resolvedFmt}
 	exists, err := helpers.FileExists(trackPath)
 	if err != nil {
 		ui.PrintError("Failed to check if track already exists locally")
-		return err
+		return trackFile{}, err
+	}
+	if exists && !cfg.SkipVerify {
+		if verifyErr := verifyTrack(trackPath); verifyErr != nil {
+			reportWarning(fmt.Sprintf("Existing track %d failed verification, downloading again: %v", pos.FileNum, verifyErr), progressBox)
+			if _, err := quarantineTrack(trackPath); err != nil {
+				return trackFile{}, fmt.Errorf("set aside unverified track: %w", err)
+			}
+			exists = false
+		}
 	}
 	if exists {
 		ui.PrintInfo(fmt.Sprintf("Track exists %s skipping", ui.SymbolArrow))
@@ -611,34 +655,49 @@ func ProcessTrack(ctx context.Context, folPath string, trackNum, trackTotal int,
 			progressBox.Mu.Lock()
 			progressBox.SkippedTracks++
 			progressBox.Mu.Unlock()
-			skipMsg := fmt.Sprintf("Skipped track %d - already exists", trackNum)
+			skipMsg := fmt.Sprintf("Skipped track %d - already exists", pos.FileNum)
 			progressBox.SetMessage(model.MessagePriorityStatus, skipMsg, model.SkipMessageDuration)
 		}
-		return nil
+		return file, nil
 	}
 
-	var accumulatedBeforeCurrent int64
 	if progressBox != nil {
 		progressBox.Mu.Lock()
-		progressBox.TrackNumber = trackNum
-		progressBox.TrackTotal = trackTotal
+		progressBox.TrackNumber = pos.Num
+		progressBox.TrackTotal = pos.Total
 		progressBox.TrackName = track.SongTitle
 		progressBox.TrackFormat = chosenQual.Specs
 		progressBox.RcloneEnabled = cfg.RcloneEnabled
-		accumulatedBeforeCurrent = progressBox.AccumulatedBytes
 		progressBox.Mu.Unlock()
 	}
-	showProgress := buildTrackProgressCallback(progressBox, deps, trackNum, trackTotal, accumulatedBeforeCurrent)
+	showProgress := buildTrackProgressCallback(progressBox, deps, pos.Num, pos.Total)
 
+	var expectedSize int64
 	if isHlsOnly {
 		err = HlsOnly(ctx, trackPath, chosenQual.URL, cfg.FfmpegNameStr, showProgress, false, deps)
 	} else {
-		err = DownloadTrack(ctx, trackPath, chosenQual.URL, showProgress, false, deps)
+		expectedSize, err = downloadTrack(ctx, trackPath, chosenQual.URL, showProgress, false, deps)
 	}
 	if err != nil {
 		ui.PrintError("Failed to download track")
-		return err
+		return trackFile{}, err
+	}
+	// The size is compared before tagging changes it; the audio is decoded
+	// after tagging, so the file that is uploaded is the one verified.
+	if !cfg.SkipVerify && expectedSize > 0 {
+		if err := verify.Size(trackPath, expectedSize); err != nil {
+			return trackFile{}, rejectTrack(ctx, trackPath, trackFname, err, meta, track, pos, deps)
+		}
 	}
+	tagTrack(trackPath, cfg, meta, track, pos.FileNum, pos.FileTotal, progressBox)
+	if !cfg.SkipVerify {
+		if err := verifyTrack(trackPath); err != nil {
+			return trackFile{}, rejectTrack(ctx, trackPath, trackFname, err, meta, track, pos, deps)
+		}
+		// A good download supersedes any copy set aside by an earlier run.
+		_ = os.Remove(trackPath + corruptSuffix)
+	}
+	deps.publish(ctx, trackEvent(model.EventTrackDone, meta, track, pos, trackPath, nil))
 
 	if progressBox != nil {
 		var trackSize int64
@@ -646,11 +705,10 @@ func ProcessTrack(ctx context.Context, folPath string, trackNum, trackTotal int,
 			trackSize = stat.Size()
 		}
 		progressBox.Mu.Lock()
-		progressBox.AccumulatedBytes += trackSize
-		progressBox.AccumulatedTracks++
+		progressBox.AccumulateTrackLocked(pos.Num, trackSize)
 		progressBox.Mu.Unlock()
 	}
-	return nil
+	return file, nil
 }
 
 // PreCalculateShowSize calculates the total size of all tracks in a show.
@@ -778,30 +836,69 @@ func handleVideoOnlyAlbum(ctx context.Context, albumID string, cfg *model.Config
 	return true, Video(ctx, albumID, "", cfg, streamParams, meta, false, nil, deps)
 }
 
-func buildAlbumFolderName(meta *model.AlbArtResp) string {
-	albumFolder := helpers.BuildAlbumFolderName(meta.ArtistName, meta.ContainerInfo)
+func buildAlbumFolderName(cfg *model.Config, segments []string, meta *model.AlbArtResp) string {
+	albumFolder := segments[len(segments)-1]
 	fmt.Println(albumFolder)
-	fullName := meta.ArtistName + " - " + strings.TrimRight(meta.ContainerInfo, " ")
-	if len([]rune(fullName)) > model.AlbumFolderMaxRunes {
-		fmt.Printf("Album folder name was chopped because it exceeds %d characters.\n", model.AlbumFolderMaxRunes)
+	if cfg.FolderTemplate == "" {
+		fullName := meta.ArtistName + " - " + strings.TrimRight(meta.ContainerInfo, " ")
+		if len([]rune(fullName)) > model.AlbumFolderMaxRunes {
+			fmt.Printf("Album folder name was chopped because it exceeds %d characters.\n", model.AlbumFolderMaxRunes)
+		}
 	}
 	return albumFolder
 }
 
-func prepareAlbumPaths(ctx context.Context, cfg *model.Config, meta *model.AlbArtResp, deps *Deps) (string, string, bool, error) {
-	artistFolder := helpers.Sanitise(meta.ArtistName)
-	artistPath := filepath.Join(cfg.OutPath, artistFolder)
+// prepareAlbumPaths creates the show folder laid out by the folder template.
+// The returned parent folder is remote-relative (slash separated) and is where
+// uploads place the show folder. A partial download adds its tracks to a show
+// folder that already exists instead of skipping the show, marking a folder
+// it creates with helpers.PartialShowMarker; a full download does the same
+// when the folder of trackTotal tracks is partial or was left incomplete.
+func prepareAlbumPaths(ctx context.Context, cfg *model.Config, meta *model.AlbArtResp, trackTotal int, partial bool, deps *Deps) (string, string, bool, error) {
+	segments := helpers.ShowPathSegments(cfg, meta)
+	parentFolder := path.Join(segments[:len(segments)-1]...)
+	artistPath := filepath.Join(append([]string{cfg.OutPath}, segments[:len(segments)-1]...)...)
 	if err := helpers.MakeDirs(artistPath); err != nil {
 		ui.PrintError("Failed to make artist folder")
 		return "", "", false, err
 	}
-	albumFolder := buildAlbumFolderName(meta)
+	albumFolder := buildAlbumFolderName(cfg, segments, meta)
 	albumPath := filepath.Join(artistPath, albumFolder)
+	if partial {
+		_, statErr := os.Stat(albumPath)
+		if err := helpers.MakeDirs(albumPath); err != nil {
+			ui.PrintError("Failed to make album folder")
+			return "", "", false, err
+		}
+		// Only a folder this selection creates is marked; adding tracks to a
+		// complete show does not make it partial.
+		if os.IsNotExist(statErr) {
+			if err := os.WriteFile(filepath.Join(albumPath, helpers.PartialShowMarker), []byte(cfg.TrackSelector+"\n"), 0644); err != nil {
+				return "", "", false, fmt.Errorf("mark partial show folder: %w", err)
+			}
+		}
+		return parentFolder, albumPath, false, nil
+	}
 	if stat, statErr := os.Stat(albumPath); statErr == nil && stat.IsDir() {
-		ui.PrintInfo(fmt.Sprintf("Show already exists locally %s skipping", ui.SymbolArrow))
-		return "", "", true, nil
+		reason := incompleteShowReason(albumPath, trackTotal)
+		// Shows in the history were verified when they finished; anything
+		// else (older downloads, or history turned off) is checked here so
+		// a truncated track is not skipped forever. A folder that passes is
+		// recorded, so it is decoded only once.
+		if reason == "" && !cfg.SkipVerify && !audioRecorded(cfg, meta, deps) {
+			var verified []string
+			if reason, verified = corruptShowReason(albumPath); reason == "" {
+				recordAlbumHistory(cfg, meta, nil, nil, verified, albumPath, nil, deps)
+			}
+		}
+		if reason == "" {
+			ui.PrintInfo(fmt.Sprintf("Show already exists locally %s skipping", ui.SymbolArrow))
+			return "", "", true, nil
+		}
+		ui.PrintInfo(fmt.Sprintf("Resuming incomplete show (%s)", reason))
+		return parentFolder, albumPath, false, nil
 	}
-	remoteShowPath := artistFolder + "/" + albumFolder
+	remoteShowPath := path.Join(parentFolder, albumFolder)
 	ui.PrintInfo(fmt.Sprintf("Checking remote for: %s%s%s", ui.ColorCyan, albumFolder, ui.ColorReset))
 	exists, err := deps.CheckRemotePathExists(ctx, remoteShowPath, cfg, false)
 	if err != nil {
@@ -814,7 +911,44 @@ func prepareAlbumPaths(ctx context.Context, cfg *model.Config, meta *model.AlbAr
 		ui.PrintError("Failed to make album folder")
 		return "", "", false, err
 	}
-	return artistFolder, albumPath, false, nil
+	return parentFolder, albumPath, false, nil
+}
+
+// incompleteShowReason returns why an existing show folder of trackTotal
+// tracks needs the download to resume, or "" when it looks complete: it holds
+// only a --tracks selection, .part files from an interrupted download, or
+// fewer audio files than tracks. A folder of joined single-file sets (with CUE sheets) is complete.
+// Files are counted rather than matched by name, so changing trackTemplate
+// does not make every show look incomplete.
+func incompleteShowReason(albumPath string, trackTotal int) string {
+	if helpers.IsPartialShow(albumPath) {
+		return "partial --tracks download"
+	}
+	entries, err := os.ReadDir(albumPath)
+	if err != nil {
+		return ""
+	}
+	var audioFiles int
+	var joinedSets bool
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() {
+			continue
+		}
+		if strings.HasSuffix(name, partFileSuffix) || strings.HasSuffix(name, partSidecarSuffix) {
+			return "interrupted download"
+		}
+		switch strings.ToLower(filepath.Ext(name)) {
+		case ".cue":
+			joinedSets = true
+		case ".flac", ".m4a", ".mp4":
+			audioFiles++
+		}
+	}
+	if joinedSets || audioFiles >= trackTotal {
+		return ""
+	}
+	return fmt.Sprintf("%d of %d tracks present", audioFiles, trackTotal)
 }
 
 func calculateAlbumShowSize(ctx context.Context, tracks []model.Track, streamParams *model.StreamParams, cfg *model.Config) (int64, string) {
@@ -867,28 +1001,38 @@ func prepareAlbumProgressBox(meta *model.AlbArtResp, cfg *model.Config, batchSta
 	return progressBox, created
 }
 
-func downloadAlbumAudio(ctx context.Context, tracks []model.Track, albumPath, artistFolder string, cfg *model.Config, streamParams *model.StreamParams, progressBox *model.ProgressBoxState, downloadVideo bool, deps *Deps) error {
-	trackTotal := len(tracks)
-	var failures []error
-	for trackNum, track := range tracks {
-		if deps.WaitIfPausedOrCancelled != nil {
-			if err := deps.WaitIfPausedOrCancelled(); err != nil {
-				return err
-			}
-		}
-		trackNum++
-		err := ProcessTrack(ctx, albumPath, trackNum, trackTotal, cfg, &track, streamParams, progressBox, deps)
-		if err != nil {
-			if deps.IsCrawlCancelledErr != nil && deps.IsCrawlCancelledErr(err) {
-				return err
-			}
+// downloadAlbumAudio downloads the tracks at the picked indexes, keeping
+// their release numbering. The show is recorded in the download history only
+// when every track was downloaded.
+func downloadAlbumAudio(ctx context.Context, meta *model.AlbArtResp, tracks []model.Track, picked []int, albumPath, artistFolder string, cfg *model.Config, streamParams *model.StreamParams, progressBox *model.ProgressBoxState, downloadVideo bool, deps *Deps) error {
+	trackTotal := len(picked)
+	deps.publish(ctx, showEvent(model.EventShowStarted, meta, model.MediaTypeAudio, albumPath))
+	saveCoverArt(ctx, cfg, meta, albumPath, progressBox)
+	files := make([]trackFile, trackTotal)
+	trackErrs, stopErr := runTrackJobs(ctx, trackTotal, trackConcurrency(cfg), progressBox, deps, func(ctx context.Context, trackNum int) error {
+		index := picked[trackNum-1]
+		track := tracks[index]
+		pos := trackPosition{Num: trackNum, Total: trackTotal, FileNum: index + 1, FileTotal: len(tracks)}
+		file, err := processTrack(ctx, albumPath, pos, cfg, &track, meta, streamParams, progressBox, deps)
+		files[trackNum-1] = file
+		if err != nil && (deps.IsCrawlCancelledErr == nil || !deps.IsCrawlCancelledErr(err)) {
 			if progressBox != nil {
 				progressBox.Mu.Lock()
 				progressBox.ErrorTracks++
 				progressBox.Mu.Unlock()
 			}
 			helpers.ReportErr("Track failed.", err)
-			failures = append(failures, fmt.Errorf("track %d (%s): %w", trackNum, track.SongTitle, err))
+		}
+		return err
+	})
+	if stopErr != nil {
+		deps.publishShowDone(ctx, meta, model.MediaTypeAudio, albumPath, stopErr)
+		return stopErr
+	}
+	var failures []error
+	for i, err := range trackErrs {
+		if err != nil {
+			failures = append(failures, fmt.Errorf("track %d (%s): %w", picked[i]+1, tracks[picked[i]].SongTitle, err))
 		}
 	}
 	if progressBox != nil {
@@ -898,17 +1042,65 @@ func downloadAlbumAudio(ctx context.Context, tracks []model.Track, albumPath, ar
 		progressBox.TotalDuration = time.Since(progressBox.StartTime)
 		progressBox.Mu.Unlock()
 	}
-	if cfg.RcloneEnabled && len(failures) == 0 {
+	if len(failures) == 0 {
+		finishAlbumFiles(ctx, cfg, meta, tracks, picked, files, albumPath, progressBox, deps)
+	}
+	partial := len(picked) < len(tracks)
+	if cfg.RcloneEnabled && len(failures) == 0 && partial {
+		ui.PrintInfo("Partial --tracks download kept local; only complete shows are uploaded")
+	}
+	if cfg.RcloneEnabled && len(failures) == 0 && !partial {
 		if err := deps.UploadPath(ctx, albumPath, artistFolder, cfg, progressBox, false); err != nil {
 			helpers.ReportErr("Upload failed.", err)
 			failures = append(failures, fmt.Errorf("upload album: %w", err))
+		} else {
+			deps.publishUploadDone(ctx, meta, albumPath, artistFolder, cfg, false)
 		}
 	}
 	if !downloadVideo && deps.RenderCompletionSummary != nil {
 		deps.RenderCompletionSummary(progressBox)
 		fmt.Println("")
 	}
-	return errors.Join(failures...)
+	err := errors.Join(failures...)
+	deps.publishShowDone(ctx, meta, model.MediaTypeAudio, albumPath, err)
+	return err
+}
+
+// finishAlbumFiles runs once every picked track is on disk: it joins sets
+// into single files when configured, then writes playlists, clears the
+// partial marker and writes the history entry for complete downloads, and
+// makes the transcoded copies, all before any upload.
+func finishAlbumFiles(ctx context.Context, cfg *model.Config, meta *model.AlbArtResp, tracks []model.Track, picked []int, files []trackFile, albumPath string, progressBox *model.ProgressBoxState, deps *Deps) {
+	complete := len(picked) == len(tracks)
+	showTracks := make([]model.ShowTrack, len(picked))
+	for i, index := range picked {
+		showTracks[i] = model.ShowTrack{Track: &tracks[index], Show: meta}
+	}
+	entries := trackEntries(albumPath, showTracks, files)
+	outputs := trackPaths(files)
+	if cfg.SingleFileSets && !complete {
+		ui.PrintInfo("Single-file sets are only built for complete shows; keeping separate tracks")
+	} else if sets := renderSingleFileSets(ctx, cfg, meta, albumPath, showTracks, files, progressBox); sets != nil {
+		entries, outputs = entries[:0], outputs[:0]
+		for _, set := range sets {
+			entries = append(entries, playlist.Entry{
+				Location: playlist.RelativeLocation(albumPath, set.Path),
+				Title:    set.Title,
+				Artist:   meta.ArtistName,
+				Album:    meta.ContainerInfo,
+				Duration: int(set.Duration.Round(time.Second) / time.Second),
+			})
+			outputs = append(outputs, set.Path)
+		}
+	}
+	savePlaylist(cfg, albumPath, meta.ContainerInfo, entries, progressBox)
+	if complete {
+		if err := os.Remove(filepath.Join(albumPath, helpers.PartialShowMarker)); err != nil && !os.IsNotExist(err) {
+			reportWarning(fmt.Sprintf("Failed to clear partial show marker: %v", err), progressBox)
+		}
+		recordAlbumHistory(cfg, meta, tracks, files, outputs, albumPath, progressBox, deps)
+	}
+	transcodeFiles(ctx, cfg, albumPath, outputs, meta, progressBox)
 }
 
 func downloadAlbumVideo(ctx context.Context, albumID string, cfg *model.Config, streamParams *model.StreamParams, meta *model.AlbArtResp, progressBox *model.ProgressBoxState, hadAudio bool, deps *Deps) error {
@@ -924,6 +1116,25 @@ func downloadAlbumVideo(ctx context.Context, albumID string, cfg *model.Config,
 	return nil
 }
 
+// selectAlbumTracks returns the indexes of the tracks cfg.TrackSelector
+// picks, in release order, and whether that is fewer than all of them.
+// Without a selector every track is picked.
+func selectAlbumTracks(cfg *model.Config, tracks []model.Track) ([]int, bool, error) {
+	picked := make([]int, 0, len(tracks))
+	if cfg.TrackSelector == "" {
+		for i := range tracks {
+			picked = append(picked, i)
+		}
+		return picked, false, nil
+	}
+	sel, err := helpers.ParseTrackSelector(cfg.TrackSelector)
+	if err != nil {
+		return nil, false, err
+	}
+	picked = sel.Select(tracks)
+	return picked, len(picked) < len(tracks), nil
+}
+
 // Album downloads an album or show from Nugs.net using the provided albumID.
 // If albumID is empty, uses the provided artResp metadata instead of fetching it.
 func Album(ctx context.Context, albumID string, cfg *model.Config, streamParams *model.StreamParams, artResp *model.AlbArtResp, batchState *model.BatchProgressState, progressBox *model.ProgressBoxState, deps *Deps) error {
@@ -936,18 +1147,38 @@ func Album(ctx context.Context, albumID string, cfg *model.Config, streamParams
 	if skuID == 0 && trackTotal < 1 {
 		return model.ErrReleaseHasNoContent
 	}
+	picked, partial, err := selectAlbumTracks(cfg, tracks)
+	if err != nil {
+		return err
+	}
+	if len(picked) == 0 && trackTotal > 0 {
+		ui.PrintInfo(fmt.Sprintf("No tracks match --tracks %q %s skipping", cfg.TrackSelector, ui.SymbolArrow))
+		return nil
+	}
 	downloadAudio, downloadVideo := resolveAlbumDownloadModes(cfg, meta)
+	if cfg.TrackSelector != "" {
+		// A track selection picks audio tracks; the video is one file.
+		downloadVideo = false
+	}
 	handled, err := handleVideoOnlyAlbum(ctx, albumID, cfg, streamParams, meta, trackTotal, skuID, downloadVideo, deps)
 	if handled {
 		return err
 	}
 
-	artistFolder, albumPath, skipped, err := prepareAlbumPaths(ctx, cfg, meta, deps)
+	artistFolder, albumPath, skipped, err := prepareAlbumPaths(ctx, cfg, meta, trackTotal, partial, deps)
 	if err != nil || skipped {
 		return err
 	}
 
-	totalShowSize, showTotalStr := calculateAlbumShowSize(ctx, tracks, streamParams, cfg)
+	selected := make([]model.Track, len(picked))
+	for i, index := range picked {
+		selected[i] = tracks[index]
+	}
+	if partial {
+		ui.PrintInfo(fmt.Sprintf("Downloading %d of %d tracks", len(picked), trackTotal))
+	}
+	trackTotal = len(picked)
+	totalShowSize, showTotalStr := calculateAlbumShowSize(ctx, selected, streamParams, cfg)
 	progressBox, created := prepareAlbumProgressBox(meta, cfg, batchState, progressBox, trackTotal, totalShowSize, showTotalStr, deps)
 	if created {
 		defer func() {
@@ -958,7 +1189,7 @@ func Album(ctx context.Context, albumID string, cfg *model.Config, streamParams
 	}
 	var failures []error
 	if downloadAudio && trackTotal > 0 {
-		if err := downloadAlbumAudio(ctx, tracks, albumPath, artistFolder, cfg, streamParams, progressBox, downloadVideo, deps); err != nil {
+		if err := downloadAlbumAudio(ctx, meta, tracks, picked, albumPath, artistFolder, cfg, streamParams, progressBox, downloadVideo, deps); err != nil {
 			failures = append(failures, err)
 		}
 	}
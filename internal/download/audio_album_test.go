@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	"github.com/jmagar/nugs-cli/internal/api"
+	"github.com/jmagar/nugs-cli/internal/helpers"
 	"github.com/jmagar/nugs-cli/internal/model"
 )
 
@@ -97,7 +98,7 @@ func TestDownloadAlbumAudioDoesNotUploadPartialAlbum(t *testing.T) {
 		TrackURL:  "https://cdn.example.test/audio.flac16/failed.flac",
 	}}
 
-	err := downloadAlbumAudio(ctx, tracks, t.TempDir(), "artist", cfg, &model.StreamParams{}, nil, false, deps)
+	err := downloadAlbumAudio(ctx, &model.AlbArtResp{}, tracks, []int{0}, t.TempDir(), "artist", cfg, &model.StreamParams{}, nil, false, deps)
 	if !errors.Is(err, errDownload) {
 		t.Fatalf("downloadAlbumAudio error = %v, want wrapped download failure", err)
 	}
@@ -171,3 +172,36 @@ func TestHandleVideoOnlyAlbumSkipsWhenDisabled(t *testing.T) {
 		t.Fatalf("handleVideoOnlyAlbum() handled = %v, want true", handled)
 	}
 }
+
+func TestTrackSelectionMarksFolderUntilFullDownload(t *testing.T) {
+	var ranges []string
+	server, ctx := rangeServer(t, `"v1"`, &ranges)
+	cfg := stubAudioConfig(t, 2)
+	cfg.RcloneEnabled = true
+	cfg.TrackSelector = "2"
+	meta := stubShow(server.URL, "Song 1", "Song 2", "Song 3")
+	var uploads []string
+	deps := &Deps{
+		UploadToRclone: func(_ context.Context, localPath, _ string, _ *model.Config, _ *model.ProgressBoxState, _ bool) error {
+			uploads = append(uploads, localPath)
+			return nil
+		},
+		RemotePathExists: func(context.Context, string, *model.Config, bool) (bool, error) { return false, nil },
+	}
+	albumPath := helpers.NewConfigPathResolver(cfg).LocalShowPath(meta, model.MediaTypeAudio)
+
+	if err := Album(ctx, "", cfg, &model.StreamParams{}, meta, nil, nil, deps); err != nil {
+		t.Fatalf("partial Album: %v", err)
+	}
+	if !helpers.IsPartialShow(albumPath) || len(uploads) != 0 || len(ranges) != 1 {
+		t.Fatalf("after --tracks: partial = %v, uploads = %v, requests = %d; want a marked local folder with one track", helpers.IsPartialShow(albumPath), uploads, len(ranges))
+	}
+
+	cfg.TrackSelector = ""
+	if err := Album(ctx, "", cfg, &model.StreamParams{}, meta, nil, nil, deps); err != nil {
+		t.Fatalf("full Album: %v", err)
+	}
+	if helpers.IsPartialShow(albumPath) || len(uploads) != 1 || len(ranges) != 3 {
+		t.Fatalf("after full download: partial = %v, uploads = %v, requests = %d; want the two missing tracks fetched and one upload", helpers.IsPartialShow(albumPath), uploads, len(ranges))
+	}
+}
@@ -67,7 +67,7 @@ func processArtistAlbums(ctx context.Context, meta []*model.ArtistMeta, cfg *mod
 	for _, m := range meta {
 		for _, container := range m.Response.Containers {
 			if deps.WaitIfPausedOrCancelled != nil {
-				if err := deps.WaitIfPausedOrCancelled(); err != nil {
+				if err := deps.WaitIfPausedOrCancelled(ctx); err != nil {
 					return err
 				}
 			}
@@ -114,25 +114,59 @@ func Playlist(ctx context.Context, plistId, legacyToken string, cfg *model.Confi
 		return err
 	}
 	meta := _meta.Response
-	plistName := meta.PlayListName
-	fmt.Println(plistName)
-	if len(plistName) > 120 {
-		plistName = plistName[:120]
+	fmt.Println(meta.PlayListName)
+
+	tracks := make([]model.ShowTrack, len(meta.Items))
+	for i := range meta.Items {
+		item := &meta.Items[i]
+		container := item.PlaylistContainer
+		tracks[i] = model.ShowTrack{
+			Track: &item.Track,
+			Show: &model.AlbArtResp{
+				ArtistID:        container.ArtistID,
+				ArtistName:      container.ArtistName,
+				ContainerID:     container.ContainerID,
+				ContainerInfo:   container.ContainerInfo,
+				PerformanceDate: container.PerformanceDate,
+				Venue:           container.Venue,
+				VenueName:       container.VenueName,
+				VenueCity:       container.VenueCity,
+				VenueState:      container.VenueState,
+			},
+		}
+	}
+	return downloadTrackSet(ctx, meta.PlayListName, "Downloading Playlist", tracks, cfg, streamParams, deps)
+}
+
+// Tracks downloads tracks taken from any number of shows into one folder
+// named name under the output path, playlist style. Each track keeps the
+// tags of the show it came from.
+func Tracks(ctx context.Context, name string, tracks []model.ShowTrack, cfg *model.Config, streamParams *model.StreamParams, deps *Deps) error {
+	if len(tracks) == 0 {
+		return fmt.Errorf("no tracks to download for %s", name)
+	}
+	return downloadTrackSet(ctx, name, "Downloading Tracks", tracks, cfg, streamParams, deps)
+}
+
+// downloadTrackSet downloads tracks into a folder named name under the
+// output path and uploads the folder when rclone is enabled.
+func downloadTrackSet(ctx context.Context, name, label string, tracks []model.ShowTrack, cfg *model.Config, streamParams *model.StreamParams, deps *Deps) error {
+	if len(name) > 120 {
+		name = name[:120]
 		fmt.Println(
 			"Playlist folder name was chopped because it exceeds 120 characters.")
 	}
-	plistPath := filepath.Join(cfg.OutPath, helpers.Sanitise(plistName))
-	err = helpers.MakeDirs(plistPath)
+	plistPath := filepath.Join(cfg.OutPath, helpers.Sanitise(name))
+	err := helpers.MakeDirs(plistPath)
 	if err != nil {
 		ui.PrintError("Failed to make playlist folder")
 		return err
 	}
-	trackTotal := len(meta.Items)
 
 	// Initialize progress box for this playlist
 	progressBox := &model.ProgressBoxState{
-		ShowTitle:      plistName,
-		ShowNumber:     "Downloading Playlist",
+		ShowTitle:      name,
+		ShowNumber:     label,
 		RcloneEnabled:  cfg.RcloneEnabled,
 		ShowDownloaded: "0 B",
 		ShowTotal:      "calculating...",
@@ -148,48 +182,51 @@ func Playlist(ctx context.Context, plistId, legacyToken string, cfg *model.Confi
 		}
 	}()
 
-	tracks := make([]*model.Track, trackTotal)
-	for i := range meta.Items {
-		tracks[i] = &meta.Items[i].Track
-	}
-	if err := processPlaylistTracks(ctx, tracks, plistPath, cfg, streamParams, progressBox, deps); err != nil {
+	files, err := processPlaylistTracks(ctx, tracks, plistPath, cfg, streamParams, progressBox, deps)
+	if err != nil {
 		return err
 	}
+	savePlaylistFiles(cfg, plistPath, name, tracks, files, progressBox)
+	transcodeFiles(ctx, cfg, plistPath, trackPaths(files), nil, progressBox)
 
 	// Upload to rclone if enabled
 	if cfg.RcloneEnabled {
 		// Playlists don't have artist folder structure
 		err = deps.UploadPath(ctx, plistPath, "", cfg, progressBox, false)
 		if err != nil {
-			ui.PrintError(fmt.Sprintf("Playlist upload failed (%s): %v", plistName, err))
-			return fmt.Errorf("playlist upload failed (%s): %w", plistName, err)
+			ui.PrintError(fmt.Sprintf("Playlist upload failed (%s): %v", name, err))
+			return fmt.Errorf("playlist upload failed (%s): %w", name, err)
 		}
 	}
 
 	return nil
 }
 
-// processPlaylistTracks iterates over playlist tracks, downloading each with pause/cancel support.
-func processPlaylistTracks(ctx context.Context, tracks []*model.Track, plistPath string, cfg *model.Config, streamParams *model.StreamParams, progressBox *model.ProgressBoxState, deps *Deps) error {
+// processPlaylistTracks downloads playlist tracks, in parallel when configured, with pause/cancel support.
+// It returns the file written for each track.
+func processPlaylistTracks(ctx context.Context, tracks []model.ShowTrack, plistPath string, cfg *model.Config, streamParams *model.StreamParams, progressBox *model.ProgressBoxState, deps *Deps) ([]trackFile, error) {
 	trackTotal := len(tracks)
-	var failures []error
-	for trackNum, track := range tracks {
-		if deps.WaitIfPausedOrCancelled != nil {
-			if err := deps.WaitIfPausedOrCancelled(); err != nil {
-				return err
-			}
-		}
-		trackNum++
-		if err := ProcessTrack(ctx, plistPath, trackNum, trackTotal, cfg, track, streamParams, progressBox, deps); err != nil {
-			if deps.IsCrawlCancelledErr != nil && deps.IsCrawlCancelledErr(err) {
-				return err
-			}
+	files := make([]trackFile, trackTotal)
+	trackErrs, stopErr := runTrackJobs(ctx, trackTotal, trackConcurrency(cfg), progressBox, deps, func(ctx context.Context, trackNum int) error {
+		item := tracks[trackNum-1]
+		file, err := processTrack(ctx, plistPath, wholeTrackPosition(trackNum, trackTotal), cfg, item.Track, item.Show, streamParams, progressBox, deps)
+		files[trackNum-1] = file
+		if err != nil && (deps.IsCrawlCancelledErr == nil || !deps.IsCrawlCancelledErr(err)) {
 			ui.PrintError(fmt.Sprintf("Track %d/%d failed (%s): %v",
-				trackNum, trackTotal, track.SongTitle, err))
-			failures = append(failures, fmt.Errorf("track %d/%d (%s): %w", trackNum, trackTotal, track.SongTitle, err))
+				trackNum, trackTotal, item.Track.SongTitle, err))
 		}
+		return err
+	})
+	if stopErr != nil {
+		return nil, stopErr
 	}
-	return errors.Join(failures...)
+	var failures []error
+	for i, err := range trackErrs {
+		if err != nil {
+			failures = append(failures, fmt.Errorf("track %d/%d (%s): %w", i+1, trackTotal, tracks[i].Track.SongTitle, err))
+		}
+	}
+	return files, errors.Join(failures...)
 }
 
 // PaidLstream downloads a paid livestream video.
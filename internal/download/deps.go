@@ -6,8 +6,14 @@ package download
 import (
 	"context"
 	"errors"
+	"fmt"
+	"os"
 
+	"github.com/jmagar/nugs-cli/internal/events"
+	"github.com/jmagar/nugs-cli/internal/history"
+	"github.com/jmagar/nugs-cli/internal/manifest"
 	"github.com/jmagar/nugs-cli/internal/model"
+	"github.com/jmagar/nugs-cli/internal/ui"
 )
 
 var errStorageDependencyMissing = errors.New("rclone is enabled but no storage provider is configured")
@@ -17,8 +23,9 @@ var errStorageDependencyMissing = errors.New("rclone is enabled but no storage p
 // The root package wires these up before calling any download functions.
 type Deps struct {
 	// WaitIfPausedOrCancelled checks if the crawl is paused or cancelled.
-	// Returns ErrCrawlCancelled if cancelled, blocks while paused.
-	WaitIfPausedOrCancelled func() error
+	// Returns ErrCrawlCancelled if cancelled, blocks while paused, and
+	// returns ctx's error if ctx ends while waiting.
+	WaitIfPausedOrCancelled func(ctx context.Context) error
 
 	// IsCrawlCancelledErr checks if an error is a crawl cancellation error.
 	IsCrawlCancelledErr func(error) bool
@@ -38,9 +45,20 @@ type Deps struct {
 	// RemotePathExists checks if a path exists on the rclone remote.
 	RemotePathExists func(ctx context.Context, remotePath string, cfg *model.Config, isVideo bool) (bool, error)
 
+	// VerifyUpload checks a finished upload against the local files before
+	// they are deleted after uploading to several destinations.
+	VerifyUpload func(ctx context.Context, localPath, artistFolder string, cfg *model.Config, isVideo bool) error
+
 	// Storage can be injected for tests or alternate storage backends.
 	Storage model.StorageProvider
 
+	// History records completed downloads. nil disables recording.
+	History *history.Store
+
+	// Events receives lifecycle events (show started, track done, upload
+	// done, ...). nil disables them.
+	Events *events.Bus
+
 	// PrintProgress renders a progress bar line for simple downloads.
 	PrintProgress func(percentage int, speed, downloaded, total string)
 
@@ -51,8 +69,39 @@ type Deps struct {
 	CalculateETA func(speedHistory []float64, remaining int64) string
 }
 
-// UploadPath uploads local content via legacy callback or the injected storage provider.
+// UploadPath uploads local content via legacy callback or the injected storage
+// provider, fanning out to every matching destination when the config lists
+// uploadDestinations. Show folders get a checksum manifest first so it is
+// uploaded with them. Failures are wrapped in an UploadError.
 func (d *Deps) UploadPath(ctx context.Context, localPath, artistFolder string, cfg *model.Config, progressBox *model.ProgressBoxState, isVideo bool) error {
+	if cfg != nil && cfg.RcloneEnabled {
+		writeManifest(localPath)
+	}
+	var err error
+	if cfg != nil && cfg.RcloneEnabled && len(cfg.UploadDestinations) > 0 {
+		err = d.uploadToDestinations(ctx, localPath, artistFolder, cfg, progressBox, isVideo)
+	} else {
+		err = d.uploadOne(ctx, localPath, artistFolder, cfg, progressBox, isVideo)
+	}
+	if err != nil {
+		return &UploadError{Err: err}
+	}
+	return nil
+}
+
+// writeManifest saves the checksum manifest of a show folder. A failure only
+// warns: the delete step builds the manifest again before checking hashes.
+func writeManifest(localPath string) {
+	info, err := os.Stat(localPath)
+	if err != nil || !info.IsDir() {
+		return
+	}
+	if _, err := manifest.Write(localPath); err != nil {
+		ui.PrintWarning(fmt.Sprintf("Failed to write checksum manifest: %v", err))
+	}
+}
+
+func (d *Deps) uploadOne(ctx context.Context, localPath, artistFolder string, cfg *model.Config, progressBox *model.ProgressBoxState, isVideo bool) error {
 	if d != nil && d.UploadToRclone != nil {
 		return d.UploadToRclone(ctx, localPath, artistFolder, cfg, progressBox, isVideo)
 	}
@@ -69,6 +118,24 @@ func (d *Deps) UploadPath(ctx context.Context, localPath, artistFolder string, c
 	return nil
 }
 
+// verifyUpload checks an upload via the legacy callback or a storage
+// provider that implements model.StorageVerifier.
+func (d *Deps) verifyUpload(ctx context.Context, localPath, artistFolder string, cfg *model.Config, isVideo bool) error {
+	if d != nil && d.VerifyUpload != nil {
+		return d.VerifyUpload(ctx, localPath, artistFolder, cfg, isVideo)
+	}
+	if d != nil {
+		if verifier, ok := d.Storage.(model.StorageVerifier); ok {
+			return verifier.Verify(ctx, cfg, model.UploadRequest{
+				LocalPath:    localPath,
+				ArtistFolder: artistFolder,
+				IsVideo:      isVideo,
+			})
+		}
+	}
+	return errStorageDependencyMissing
+}
+
 // CheckRemotePathExists checks remote path existence via legacy callback or storage provider.
 func (d *Deps) CheckRemotePathExists(ctx context.Context, remotePath string, cfg *model.Config, isVideo bool) (bool, error) {
 	if d != nil && d.RemotePathExists != nil {
@@ -3,8 +3,11 @@ package download
 import (
 	"context"
 	"errors"
+	"os"
+	"path/filepath"
 	"testing"
 
+	"github.com/jmagar/nugs-cli/internal/manifest"
 	"github.com/jmagar/nugs-cli/internal/model"
 )
 
@@ -88,3 +91,26 @@ func TestDepsCheckRemotePathExistsUsesStorageProviderWhenLegacyCallbackMissing(t
 		t.Fatal("expected isVideo=true")
 	}
 }
+
+func TestDepsUploadPathWritesManifestBeforeUpload(t *testing.T) {
+	show := t.TempDir()
+	if err := os.WriteFile(filepath.Join(show, "01.flac"), []byte("audio"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	deps := &Deps{
+		UploadToRclone: func(_ context.Context, localPath, _ string, _ *model.Config, _ *model.ProgressBoxState, _ bool) error {
+			data, err := os.ReadFile(filepath.Join(localPath, manifest.FileName))
+			if err != nil {
+				t.Fatalf("manifest not written before upload: %v", err)
+			}
+			m, err := manifest.Parse(data)
+			if err != nil || len(m.Files) != 1 || m.Files[0].Path != "01.flac" {
+				t.Fatalf("manifest = %+v, %v", m, err)
+			}
+			return nil
+		},
+	}
+	if err := deps.UploadPath(context.Background(), show, "artist", &model.Config{RcloneEnabled: true}, nil, false); err != nil {
+		t.Fatalf("UploadPath() error = %v", err)
+	}
+}
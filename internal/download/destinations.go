@@ -0,0 +1,69 @@
+package download
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/jmagar/nugs-cli/internal/helpers"
+	"github.com/jmagar/nugs-cli/internal/model"
+	"github.com/jmagar/nugs-cli/internal/ui"
+)
+
+// uploadToDestinations uploads localPath to every destination that accepts
+// its media. Optional destinations only warn on failure. With
+// deleteAfterUpload, local files are removed once every required destination
+// has the upload and has been verified.
+func (d *Deps) uploadToDestinations(ctx context.Context, localPath, artistFolder string, cfg *model.Config, progressBox *model.ProgressBoxState, isVideo bool) error {
+	dests := helpers.DestinationsForMedia(cfg, isVideo)
+	if len(dests) == 0 {
+		return nil
+	}
+
+	var (
+		required []model.UploadDestination
+		failures []error
+	)
+	for _, dest := range dests {
+		destCfg := helpers.DestinationConfig(cfg, dest)
+		// Deletion waits for every destination, so none may delete on its own.
+		destCfg.DeleteAfterUpload = false
+		if progressBox == nil {
+			ui.PrintInfo(fmt.Sprintf("Upload destination: %s", dest.Name))
+		}
+		err := d.uploadOne(ctx, localPath, artistFolder, destCfg, progressBox, isVideo)
+		switch {
+		case err != nil && ctx.Err() != nil:
+			return err
+		case err != nil && dest.Optional:
+			ui.PrintWarning(fmt.Sprintf("Upload to optional destination %s failed: %v", dest.Name, err))
+		case err != nil:
+			failures = append(failures, fmt.Errorf("%s: %w", dest.Name, err))
+		default:
+			if !dest.Optional {
+				required = append(required, dest)
+			}
+		}
+	}
+	if len(failures) > 0 {
+		return fmt.Errorf("upload failed for %d destination(s): %w", len(failures), errors.Join(failures...))
+	}
+	if !cfg.DeleteAfterUpload || len(required) == 0 {
+		return nil
+	}
+
+	for _, dest := range required {
+		if err := d.verifyUpload(ctx, localPath, artistFolder, helpers.DestinationConfig(cfg, dest), isVideo); err != nil {
+			return fmt.Errorf("upload verification failed for %s - NOT deleting local files: %w", dest.Name, err)
+		}
+	}
+	if progressBox == nil {
+		ui.PrintSuccess(fmt.Sprintf("Upload verified on %d destination(s)", len(required)))
+		fmt.Printf("Deleting local files: %s\n", localPath)
+	}
+	if err := os.RemoveAll(localPath); err != nil {
+		return fmt.Errorf("failed to delete local files: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package download
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jmagar/nugs-cli/internal/model"
+)
+
+func destinationDeps(t *testing.T, failing map[string]bool) (*Deps, *[]string, *[]string) {
+	t.Helper()
+	var uploads, verified []string
+	deps := &Deps{
+		UploadToRclone: func(_ context.Context, _, _ string, cfg *model.Config, _ *model.ProgressBoxState, _ bool) error {
+			if cfg.DeleteAfterUpload {
+				t.Error("destination upload ran with deleteAfterUpload set")
+			}
+			if len(cfg.UploadDestinations) != 0 {
+				t.Error("destination config still lists destinations")
+			}
+			uploads = append(uploads, cfg.RcloneRemote)
+			if failing[cfg.RcloneRemote] {
+				return errors.New("remote unreachable")
+			}
+			return nil
+		},
+		VerifyUpload: func(_ context.Context, _, _ string, cfg *model.Config, _ bool) error {
+			verified = append(verified, cfg.RcloneRemote)
+			return nil
+		},
+	}
+	return deps, &uploads, &verified
+}
+
+func destinationConfig(localDir string) *model.Config {
+	return &model.Config{
+		OutPath:           localDir,
+		RcloneEnabled:     true,
+		DeleteAfterUpload: true,
+		RcloneRemote:      "unused",
+		RclonePath:        "/Music",
+		UploadDestinations: []model.UploadDestination{
+			{Name: "nas", RcloneRemote: "nas"},
+			{Name: "cold", RcloneRemote: "s3"},
+			{Name: "backup", RcloneRemote: "backup", Optional: true},
+			{Name: "videos", RcloneRemote: "video", Media: "video"},
+		},
+	}
+}
+
+func TestUploadPathFansOutAndDeletesAfterRequiredDestinations(t *testing.T) {
+	show := filepath.Join(t.TempDir(), "show")
+	if err := os.MkdirAll(show, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	deps, uploads, verified := destinationDeps(t, map[string]bool{"backup": true})
+
+	if err := deps.UploadPath(context.Background(), show, "Phish", destinationConfig(filepath.Dir(show)), nil, false); err != nil {
+		t.Fatalf("UploadPath() error = %v", err)
+	}
+	if got := strings.Join(*uploads, ","); got != "nas,s3,backup" {
+		t.Fatalf("uploads = %s, want nas,s3,backup (video-only destination skipped)", got)
+	}
+	if got := strings.Join(*verified, ","); got != "nas,s3" {
+		t.Fatalf("verified = %s, want only required destinations", got)
+	}
+	if _, err := os.Stat(show); !os.IsNotExist(err) {
+		t.Fatal("local show not deleted after every required destination succeeded")
+	}
+}
+
+func TestUploadPathKeepsLocalFilesWhenRequiredDestinationFails(t *testing.T) {
+	show := filepath.Join(t.TempDir(), "show")
+	if err := os.MkdirAll(show, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	deps, uploads, verified := destinationDeps(t, map[string]bool{"nas": true})
+
+	err := deps.UploadPath(context.Background(), show, "Phish", destinationConfig(filepath.Dir(show)), nil, false)
+	if err == nil || !strings.Contains(err.Error(), "nas: remote unreachable") {
+		t.Fatalf("UploadPath() error = %v, want nas failure", err)
+	}
+	if len(*uploads) != 3 {
+		t.Fatalf("uploads = %v, want the remaining destinations still attempted", *uploads)
+	}
+	if len(*verified) != 0 {
+		t.Fatalf("verified = %v, want no verification after a failure", *verified)
+	}
+	if _, err := os.Stat(show); err != nil {
+		t.Fatalf("local show removed despite failed required destination: %v", err)
+	}
+}
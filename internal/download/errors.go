@@ -0,0 +1,66 @@
+package download
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"net"
+
+	"github.com/jmagar/nugs-cli/internal/api"
+	"github.com/jmagar/nugs-cli/internal/model"
+	"github.com/jmagar/nugs-cli/internal/verify"
+)
+
+// Error classes reported in lifecycle events and the --json run summary.
+const (
+	ErrorClassCancelled      = "cancelled"
+	ErrorClassUpload         = "upload"
+	ErrorClassVerify         = "verify"
+	ErrorClassNoContent      = "no_content"
+	ErrorClassAPIUnavailable = "api_unavailable"
+	ErrorClassTimeout        = "timeout"
+	ErrorClassNetwork        = "network"
+	ErrorClassFilesystem     = "filesystem"
+	ErrorClassOther          = "other"
+)
+
+// UploadError wraps a failure to upload a finished download.
+type UploadError struct {
+	Err error
+}
+
+func (e *UploadError) Error() string { return e.Err.Error() }
+
+func (e *UploadError) Unwrap() error { return e.Err }
+
+// ErrorClass sorts a download error into one of the ErrorClass constants,
+// or returns "" for a nil error. Upload failures are classed as uploads
+// even when the cause was a network error.
+func ErrorClass(err error) string {
+	var (
+		uploadErr *UploadError
+		netErr    net.Error
+		pathErr   *fs.PathError
+	)
+	switch {
+	case err == nil:
+		return ""
+	case errors.Is(err, context.Canceled):
+		return ErrorClassCancelled
+	case errors.As(err, &uploadErr):
+		return ErrorClassUpload
+	case errors.Is(err, verify.ErrCorrupt), errors.Is(err, verify.ErrTruncated):
+		return ErrorClassVerify
+	case errors.Is(err, model.ErrReleaseHasNoContent):
+		return ErrorClassNoContent
+	case errors.Is(err, api.ErrCircuitOpen):
+		return ErrorClassAPIUnavailable
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+		return ErrorClassTimeout
+	case errors.As(err, &netErr):
+		return ErrorClassNetwork
+	case errors.As(err, &pathErr):
+		return ErrorClassFilesystem
+	}
+	return ErrorClassOther
+}
@@ -0,0 +1,42 @@
+package download
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"os"
+	"testing"
+
+	"github.com/jmagar/nugs-cli/internal/api"
+	"github.com/jmagar/nugs-cli/internal/model"
+	"github.com/jmagar/nugs-cli/internal/verify"
+)
+
+func TestErrorClass(t *testing.T) {
+	timeout := &net.OpError{Op: "dial", Net: "tcp", Err: os.ErrDeadlineExceeded}
+	refused := &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{nil, ""},
+		{fmt.Errorf("track 3: %w", context.Canceled), ErrorClassCancelled},
+		{&UploadError{Err: refused}, ErrorClassUpload},
+		{fmt.Errorf("track 2: %w", fmt.Errorf("%w: bad frame", verify.ErrCorrupt)), ErrorClassVerify},
+		{fmt.Errorf("%w: bad", verify.ErrTruncated), ErrorClassVerify},
+		{fmt.Errorf("release: %w", model.ErrReleaseHasNoContent), ErrorClassNoContent},
+		{fmt.Errorf("get meta: %w", api.ErrCircuitOpen), ErrorClassAPIUnavailable},
+		{fmt.Errorf("get: %w", timeout), ErrorClassTimeout},
+		{context.DeadlineExceeded, ErrorClassTimeout},
+		{fmt.Errorf("get: %w", refused), ErrorClassNetwork},
+		{&fs.PathError{Op: "mkdir", Path: "/music", Err: fs.ErrPermission}, ErrorClassFilesystem},
+		{errors.New("ffmpeg exited 1"), ErrorClassOther},
+	}
+	for _, tc := range tests {
+		if got := ErrorClass(tc.err); got != tc.want {
+			t.Errorf("ErrorClass(%v) = %q, want %q", tc.err, got, tc.want)
+		}
+	}
+}
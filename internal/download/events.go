@@ -0,0 +1,83 @@
+package download
+
+import (
+	"context"
+	"path"
+	"strings"
+
+	"github.com/jmagar/nugs-cli/internal/helpers"
+	"github.com/jmagar/nugs-cli/internal/model"
+)
+
+// publish sends e to the event bus, if one is configured.
+func (d *Deps) publish(ctx context.Context, e model.Event) {
+	if d != nil && d.Events != nil {
+		d.Events.Publish(ctx, e)
+	}
+}
+
+// showEvent returns an event of the given type describing meta, which may
+// be nil when a single track is downloaded without show metadata.
+func showEvent(eventType string, meta *model.AlbArtResp, media model.MediaType, localPath string) model.Event {
+	e := model.Event{Type: eventType, Media: media.String(), Path: localPath}
+	if meta != nil {
+		e.ContainerID = meta.ContainerID
+		e.ArtistID = meta.ArtistID
+		e.ArtistName = meta.ArtistName
+		e.Show = strings.TrimSpace(meta.ContainerInfo)
+		e.Date = helpers.ShowDate(meta)
+	}
+	return e
+}
+
+// publishShowDone reports the end of a show's audio or video download, with
+// err as its error. Cancelled downloads are not reported.
+func (d *Deps) publishShowDone(ctx context.Context, meta *model.AlbArtResp, media model.MediaType, localPath string, err error) {
+	if d == nil || ctx.Err() != nil || (err != nil && d.IsCrawlCancelledErr != nil && d.IsCrawlCancelledErr(err)) {
+		return
+	}
+	e := showEvent(model.EventShowDone, meta, media, localPath)
+	if err != nil {
+		e.Error, e.ErrorClass = err.Error(), ErrorClass(err)
+	}
+	d.publish(ctx, e)
+}
+
+// publishUploadDone reports a finished upload of localPath.
+func (d *Deps) publishUploadDone(ctx context.Context, meta *model.AlbArtResp, localPath, artistFolder string, cfg *model.Config, isVideo bool) {
+	media := model.MediaTypeAudio
+	if isVideo {
+		media = model.MediaTypeVideo
+	}
+	e := showEvent(model.EventUploadDone, meta, media, localPath)
+	e.Remote = uploadRemote(cfg, artistFolder, isVideo)
+	d.publish(ctx, e)
+}
+
+// uploadRemote describes where an upload went: the names of the matching
+// uploadDestinations, or remote:path for the top-level storage settings.
+func uploadRemote(cfg *model.Config, artistFolder string, isVideo bool) string {
+	if len(cfg.UploadDestinations) > 0 {
+		var names []string
+		for _, dest := range helpers.DestinationsForMedia(cfg, isVideo) {
+			names = append(names, dest.Name)
+		}
+		return strings.Join(names, ",")
+	}
+	remotePath := path.Join(helpers.GetRcloneBasePath(cfg, isVideo), artistFolder)
+	if cfg.RcloneRemote == "" {
+		return remotePath
+	}
+	return cfg.RcloneRemote + ":" + remotePath
+}
+
+// trackEvent returns a track.done or verify.failed event for one track.
+func trackEvent(eventType string, meta *model.AlbArtResp, track *model.Track, pos trackPosition, trackPath string, err error) model.Event {
+	e := showEvent(eventType, meta, model.MediaTypeAudio, trackPath)
+	e.Track = pos.FileNum
+	e.TrackTitle = track.SongTitle
+	if err != nil {
+		e.Error, e.ErrorClass = err.Error(), ErrorClass(err)
+	}
+	return e
+}
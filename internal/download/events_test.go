@@ -0,0 +1,95 @@
+package download
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jmagar/nugs-cli/internal/events"
+	"github.com/jmagar/nugs-cli/internal/model"
+)
+
+// eventLogBus returns a bus that writes to a JSON-lines log and a function
+// that closes the bus and reads the events it logged.
+func eventLogBus(t *testing.T) (*events.Bus, func() []model.Event) {
+	t.Helper()
+	logPath := filepath.Join(t.TempDir(), "events.jsonl")
+	bus, err := events.New(&model.Config{EventLog: logPath})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return bus, func() []model.Event {
+		bus.Close()
+		f, err := os.Open(logPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		var logged []model.Event
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			var e model.Event
+			if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+				t.Fatal(err)
+			}
+			logged = append(logged, e)
+		}
+		return logged
+	}
+}
+
+func TestDownloadAlbumAudioPublishesLifecycleEvents(t *testing.T) {
+	ctx := verifyTestContext(validTestM4A())
+	bus, logged := eventLogBus(t)
+	deps := &Deps{
+		Events: bus,
+		UploadToRclone: func(context.Context, string, string, *model.Config, *model.ProgressBoxState, bool) error {
+			return nil
+		},
+	}
+	cfg := &model.Config{Format: 1, RcloneEnabled: true, RcloneRemote: "gdrive", RclonePath: "Music", SkipTagging: true, TrackConcurrency: 1}
+	meta := &model.AlbArtResp{ContainerID: 42, ArtistID: 7, ArtistName: "Goose", ContainerInfo: "Live"}
+	tracks := []model.Track{{TrackID: 501, SongTitle: "Song"}, {TrackID: 502, SongTitle: "Other"}}
+	albumPath := t.TempDir()
+
+	if err := downloadAlbumAudio(ctx, meta, tracks, []int{0, 1}, albumPath, "Goose", cfg, &model.StreamParams{}, nil, false, deps); err != nil {
+		t.Fatalf("downloadAlbumAudio: %v", err)
+	}
+
+	got := logged()
+	want := []string{model.EventShowStarted, model.EventTrackDone, model.EventTrackDone, model.EventUploadDone, model.EventShowDone}
+	if len(got) != len(want) {
+		t.Fatalf("events = %+v, want types %v", got, want)
+	}
+	for i, e := range got {
+		if e.Type != want[i] || e.ContainerID != 42 || e.ArtistName != "Goose" {
+			t.Errorf("event %d = %+v, want %s for container 42", i, e, want[i])
+		}
+	}
+	if got[1].Track != 1 || got[1].TrackTitle != "Song" || got[1].Path != filepath.Join(albumPath, "01. Song.m4a") {
+		t.Errorf("track.done = %+v", got[1])
+	}
+	if got[3].Remote != "gdrive:Music/Goose" || got[3].Path != albumPath {
+		t.Errorf("upload.done = %+v, want remote gdrive:Music/Goose", got[3])
+	}
+	if got[4].Error != "" || got[4].Media != "audio" {
+		t.Errorf("show.done = %+v, want a clean audio download", got[4])
+	}
+}
+
+func TestProcessTrackPublishesVerifyFailed(t *testing.T) {
+	ctx := verifyTestContext(validTestM4A()[:40])
+	bus, logged := eventLogBus(t)
+	track := &model.Track{TrackID: 1, SongTitle: "Song"}
+
+	if err := ProcessTrack(ctx, t.TempDir(), 1, 1, &model.Config{Format: 1}, track, nil, &model.StreamParams{}, nil, &Deps{Events: bus}); err == nil {
+		t.Fatal("ProcessTrack succeeded with a truncated download")
+	}
+	got := logged()
+	if len(got) != 1 || got[0].Type != model.EventVerifyFailed || got[0].Track != 1 || got[0].Error == "" {
+		t.Fatalf("events = %+v, want one verify.failed with an error", got)
+	}
+}
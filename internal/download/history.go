@@ -0,0 +1,118 @@
+package download
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/jmagar/nugs-cli/internal/history"
+	"github.com/jmagar/nugs-cli/internal/model"
+)
+
+// historyEnabled reports whether downloads should be written to the history.
+func historyEnabled(cfg *model.Config, deps *Deps) bool {
+	return deps != nil && deps.History != nil && !cfg.SkipHistory
+}
+
+// audioRecorded reports whether the history holds a finished audio download
+// of meta's show. A failed lookup counts as not recorded.
+func audioRecorded(cfg *model.Config, meta *model.AlbArtResp, deps *Deps) bool {
+	if !historyEnabled(cfg, deps) || meta.ContainerID == 0 {
+		return false
+	}
+	containers, err := deps.History.Containers(history.MediaAudio)
+	if err != nil {
+		return false
+	}
+	_, ok := containers[meta.ContainerID]
+	return ok
+}
+
+// recordAlbumHistory records a completed show's tracks, formats and the
+// checksums of outputs, the audio files left in the show folder (the track
+// files, or the joined sets). It runs before any upload so deleteAfterUpload
+// cannot remove the files first. History failures are warnings; the download
+// itself succeeded.
+func recordAlbumHistory(cfg *model.Config, meta *model.AlbArtResp, tracks []model.Track, files []trackFile, outputs []string, albumPath string, progressBox *model.ProgressBoxState, deps *Deps) {
+	if !historyEnabled(cfg, deps) || meta.ContainerID == 0 {
+		return
+	}
+	entry := history.Entry{
+		ContainerID: meta.ContainerID,
+		ArtistID:    meta.ArtistID,
+		ArtistName:  meta.ArtistName,
+		Title:       meta.ContainerInfo,
+		Media:       history.MediaAudio,
+		LocalPath:   albumPath,
+		Checksums:   make(map[string]string, len(files)),
+	}
+	formats := make(map[string]struct{})
+	for i, file := range files {
+		if file.Path == "" {
+			continue
+		}
+		if i < len(tracks) && tracks[i].TrackID != 0 {
+			entry.TrackIDs = append(entry.TrackIDs, tracks[i].TrackID)
+		}
+		formats[model.GetQualityName(file.Format)] = struct{}{}
+	}
+	for _, path := range outputs {
+		if err := addFileToEntry(&entry, path); err != nil {
+			reportWarning(fmt.Sprintf("Failed to record history for %s: %v", filepath.Base(path), err), progressBox)
+			return
+		}
+	}
+	entry.Format = joinFormats(formats)
+	if err := deps.History.Record(entry); err != nil {
+		reportWarning(fmt.Sprintf("Failed to record download history: %v", err), progressBox)
+	}
+}
+
+// recordVideoHistory records a converted video before it is uploaded.
+func recordVideoHistory(cfg *model.Config, meta *model.AlbArtResp, vidPath, format string, progressBox *model.ProgressBoxState, deps *Deps) {
+	if !historyEnabled(cfg, deps) || meta.ContainerID == 0 {
+		return
+	}
+	entry := history.Entry{
+		ContainerID: meta.ContainerID,
+		ArtistID:    meta.ArtistID,
+		ArtistName:  meta.ArtistName,
+		Title:       meta.ContainerInfo,
+		Media:       history.MediaVideo,
+		Format:      format,
+		LocalPath:   vidPath,
+		Checksums:   make(map[string]string, 1),
+	}
+	if err := addFileToEntry(&entry, vidPath); err != nil {
+		reportWarning(fmt.Sprintf("Failed to record history for %s: %v", filepath.Base(vidPath), err), progressBox)
+		return
+	}
+	if err := deps.History.Record(entry); err != nil {
+		reportWarning(fmt.Sprintf("Failed to record download history: %v", err), progressBox)
+	}
+}
+
+func addFileToEntry(entry *history.Entry, path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	sum, err := history.FileChecksum(path)
+	if err != nil {
+		return err
+	}
+	entry.Bytes += info.Size()
+	entry.Checksums[filepath.Base(path)] = sum
+	return nil
+}
+
+func joinFormats(formats map[string]struct{}) string {
+	names := make([]string, 0, len(formats))
+	for name := range formats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
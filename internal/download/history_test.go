@@ -0,0 +1,85 @@
+package download
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"path/filepath"
+	"testing"
+
+	"github.com/jmagar/nugs-cli/internal/history"
+	"github.com/jmagar/nugs-cli/internal/model"
+)
+
+func TestDownloadAlbumAudioRecordsHistoryBeforeUpload(t *testing.T) {
+	valid := validTestM4A()
+	ctx := verifyTestContext(valid)
+	store := history.Open(t.TempDir())
+	albumPath := t.TempDir()
+	recordedBeforeUpload := false
+	deps := &Deps{
+		History: store,
+		UploadToRclone: func(context.Context, string, string, *model.Config, *model.ProgressBoxState, bool) error {
+			entries, err := store.Entries()
+			recordedBeforeUpload = err == nil && len(entries) == 1
+			return nil
+		},
+	}
+	cfg := &model.Config{Format: 1, RcloneEnabled: true, SkipTagging: true}
+	meta := &model.AlbArtResp{ContainerID: 42, ArtistID: 7, ArtistName: "Goose", ContainerInfo: "Live"}
+	tracks := []model.Track{{TrackID: 501, SongTitle: "Song"}, {TrackID: 502, SongTitle: "Other"}}
+
+	if err := downloadAlbumAudio(ctx, meta, tracks, []int{0, 1}, albumPath, "Goose", cfg, &model.StreamParams{}, nil, false, deps); err != nil {
+		t.Fatalf("downloadAlbumAudio: %v", err)
+	}
+	if !recordedBeforeUpload {
+		t.Fatal("history entry was not written before the upload started")
+	}
+	entries, err := store.Entries()
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("Entries = %+v, %v; want one entry", entries, err)
+	}
+	entry := entries[0]
+	sum := sha256.Sum256(valid)
+	if entry.ContainerID != 42 || entry.ArtistID != 7 || entry.Media != history.MediaAudio || entry.LocalPath != albumPath {
+		t.Errorf("entry = %+v, want container 42 audio at %s", entry, albumPath)
+	}
+	if len(entry.TrackIDs) != 2 || entry.TrackIDs[0] != 501 || entry.TrackIDs[1] != 502 {
+		t.Errorf("TrackIDs = %v, want [501 502]", entry.TrackIDs)
+	}
+	if entry.Format != model.GetQualityName(1) || entry.Bytes != int64(2*len(valid)) {
+		t.Errorf("format %q, bytes %d; want %q, %d", entry.Format, entry.Bytes, model.GetQualityName(1), 2*len(valid))
+	}
+	if got := entry.Checksums["01. Song.m4a"]; got != hex.EncodeToString(sum[:]) {
+		t.Errorf("checksum = %q, want SHA-256 of the track", got)
+	}
+
+	cfg.SkipHistory = true
+	meta.ContainerID = 43
+	if err := downloadAlbumAudio(ctx, meta, tracks, []int{0, 1}, t.TempDir(), "Goose", cfg, &model.StreamParams{}, nil, false, deps); err != nil {
+		t.Fatalf("downloadAlbumAudio: %v", err)
+	}
+	if entries, _ := store.Entries(); len(entries) != 1 {
+		t.Errorf("skipHistory still recorded: %+v", entries)
+	}
+}
+
+func TestDownloadAlbumAudioPartialKeepsNumberingWithoutHistory(t *testing.T) {
+	ctx := verifyTestContext(validTestM4A())
+	store := history.Open(t.TempDir())
+	albumPath := t.TempDir()
+	cfg := &model.Config{Format: 1, SkipTagging: true}
+	meta := &model.AlbArtResp{ContainerID: 42, ArtistName: "Goose", ContainerInfo: "Live"}
+	tracks := []model.Track{{TrackID: 501, SongTitle: "Song"}, {TrackID: 502, SongTitle: "Other"}, {TrackID: 503, SongTitle: "Last"}}
+
+	if err := downloadAlbumAudio(ctx, meta, tracks, []int{1}, albumPath, "Goose", cfg, &model.StreamParams{}, nil, false, &Deps{History: store}); err != nil {
+		t.Fatalf("downloadAlbumAudio: %v", err)
+	}
+	names, err := filepath.Glob(filepath.Join(albumPath, "*.m4a"))
+	if err != nil || len(names) != 1 || filepath.Base(names[0]) != "02. Other.m4a" {
+		t.Fatalf("files = %v, want only 02. Other.m4a", names)
+	}
+	if entries, _ := store.Entries(); len(entries) != 0 {
+		t.Errorf("partial download recorded history: %+v", entries)
+	}
+}
@@ -0,0 +1,84 @@
+package download
+
+import (
+	"context"
+	"sync"
+
+	"github.com/jmagar/nugs-cli/internal/model"
+)
+
+// trackConcurrency returns the configured number of parallel track downloads.
+func trackConcurrency(cfg *model.Config) int {
+	if cfg == nil || cfg.TrackConcurrency < 1 {
+		return 1
+	}
+	return min(cfg.TrackConcurrency, model.MaxTrackConcurrency)
+}
+
+// runTrackJobs runs job for track numbers 1..trackTotal on up to concurrency
+// workers, starting tracks in order. Every track start waits on the crawl
+// pause/cancel gate. A crawl cancellation (from the gate or a job) or the end
+// of ctx stops new tracks, cancels in-flight ones, and is returned as stopErr.
+// Per-track errors are returned indexed by trackNum-1.
+func runTrackJobs(ctx context.Context, trackTotal, concurrency int, progressBox *model.ProgressBoxState, deps *Deps, job func(ctx context.Context, trackNum int) error) (trackErrs []error, stopErr error) {
+	trackErrs = make([]error, trackTotal)
+	if trackTotal == 0 {
+		return trackErrs, nil
+	}
+	parentCtx := ctx
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	stop := func(err error) {
+		mu.Lock()
+		if stopErr == nil {
+			stopErr = err
+		}
+		mu.Unlock()
+		cancel()
+	}
+
+	next := make(chan int)
+	for range min(max(concurrency, 1), trackTotal) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for trackNum := range next {
+				err := job(ctx, trackNum)
+				progressBox.FinishTrack(trackNum)
+				if err != nil && deps.IsCrawlCancelledErr != nil && deps.IsCrawlCancelledErr(err) {
+					stop(err)
+					continue
+				}
+				trackErrs[trackNum-1] = err
+			}
+		}()
+	}
+
+dispatch:
+	for trackNum := 1; trackNum <= trackTotal; trackNum++ {
+		if deps.WaitIfPausedOrCancelled != nil {
+			if err := deps.WaitIfPausedOrCancelled(ctx); err != nil {
+				stop(err)
+				break
+			}
+		}
+		select {
+		case next <- trackNum:
+		case <-ctx.Done():
+			break dispatch
+		}
+	}
+	close(next)
+	wg.Wait()
+
+	if stopErr == nil && parentCtx.Err() != nil {
+		// The caller's context ended; unstarted tracks must not read as successes.
+		stopErr = parentCtx.Err()
+	}
+	return trackErrs, stopErr
+}
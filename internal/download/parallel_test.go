@@ -0,0 +1,109 @@
+package download
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jmagar/nugs-cli/internal/model"
+)
+
+func TestRunTrackJobsRunsTracksConcurrently(t *testing.T) {
+	const concurrency = 3
+	var running, peak atomic.Int32
+	release := make(chan struct{})
+	var once sync.Once
+	trackErrs, stopErr := runTrackJobs(context.Background(), 6, concurrency, nil, &Deps{}, func(_ context.Context, trackNum int) error {
+		n := running.Add(1)
+		defer running.Add(-1)
+		for {
+			old := peak.Load()
+			if n <= old || peak.CompareAndSwap(old, n) {
+				break
+			}
+		}
+		if n == concurrency {
+			once.Do(func() { close(release) })
+		}
+		select {
+		case <-release:
+		case <-time.After(2 * time.Second):
+		}
+		if trackNum == 4 {
+			return errors.New("boom")
+		}
+		return nil
+	})
+	if stopErr != nil {
+		t.Fatalf("unexpected stop error: %v", stopErr)
+	}
+	if got := peak.Load(); got != concurrency {
+		t.Fatalf("peak concurrency = %d, want %d", got, concurrency)
+	}
+	for i, err := range trackErrs {
+		if (err != nil) != (i == 3) {
+			t.Fatalf("trackErrs[%d] = %v, want only track 4 to fail", i, err)
+		}
+	}
+}
+
+func TestRunTrackJobsStopsOnCrawlCancel(t *testing.T) {
+	errCancelled := errors.New("crawl cancelled")
+	var started, gateCalls atomic.Int32
+	deps := &Deps{
+		WaitIfPausedOrCancelled: func(context.Context) error {
+			if gateCalls.Add(1) > 2 {
+				return errCancelled
+			}
+			return nil
+		},
+		IsCrawlCancelledErr: func(err error) bool { return errors.Is(err, errCancelled) },
+	}
+	box := &model.ProgressBoxState{}
+	_, stopErr := runTrackJobs(context.Background(), 10, 1, box, deps, func(_ context.Context, _ int) error {
+		started.Add(1)
+		return nil
+	})
+	if !errors.Is(stopErr, errCancelled) {
+		t.Fatalf("stopErr = %v, want crawl cancellation", stopErr)
+	}
+	if got := started.Load(); got != 2 {
+		t.Fatalf("started %d tracks after cancellation, want 2", got)
+	}
+	if box.FinishedTracks != 2 {
+		t.Fatalf("FinishedTracks = %d, want 2", box.FinishedTracks)
+	}
+}
+
+func TestTrackProgressCallbackAggregatesParallelTracks(t *testing.T) {
+	box := &model.ProgressBoxState{TrackTotal: 4}
+	deps := &Deps{}
+	first := buildTrackProgressCallback(box, deps, 1, 4)
+	second := buildTrackProgressCallback(box, deps, 2, 4)
+
+	first(50, 100, 10)
+	second(25, 100, 5)
+	if box.DownloadSpeed != "15 B" || box.ShowDownloaded != "75 B" || box.DownloadTotal != "200 B" {
+		t.Fatalf("aggregate speed=%q downloaded=%q total=%q", box.DownloadSpeed, box.ShowDownloaded, box.DownloadTotal)
+	}
+	if box.ShowTotal != "400 B" {
+		t.Fatalf("ShowTotal = %q, want estimate 400 B", box.ShowTotal)
+	}
+
+	// Track 1 completes: its bytes move into AccumulatedBytes without double counting.
+	first(100, 100, 0)
+	box.Mu.Lock()
+	box.AccumulateTrackLocked(1, 100)
+	box.Mu.Unlock()
+	box.FinishTrack(1)
+	second(50, 100, 5)
+	if box.ShowDownloaded != "150 B" {
+		t.Fatalf("ShowDownloaded = %q, want 150 B", box.ShowDownloaded)
+	}
+	if box.ShowPercent != 37 {
+		t.Fatalf("ShowPercent = %d, want 37 (1.5 of 4 tracks)", box.ShowPercent)
+	}
+}
@@ -0,0 +1,49 @@
+package download
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/jmagar/nugs-cli/internal/model"
+	"github.com/jmagar/nugs-cli/internal/playlist"
+)
+
+// savePlaylistFiles writes the configured playlist files into a downloaded
+// folder, listing each track file relative to it.
+func savePlaylistFiles(cfg *model.Config, dir, title string, tracks []model.ShowTrack, files []trackFile, progressBox *model.ProgressBoxState) {
+	savePlaylist(cfg, dir, title, trackEntries(dir, tracks, files), progressBox)
+}
+
+// savePlaylist writes entries into dir as playlist files named after it.
+// Failures are warnings because the audio itself is intact.
+func savePlaylist(cfg *model.Config, dir, title string, entries []playlist.Entry, progressBox *model.ProgressBoxState) {
+	formats := playlist.Formats(cfg)
+	if len(formats) == 0 || len(entries) == 0 {
+		return
+	}
+	if err := playlist.SaveAll(dir, filepath.Base(dir), formats, title, entries); err != nil {
+		reportWarning(fmt.Sprintf("Failed to write playlist: %v", err), progressBox)
+	}
+}
+
+// trackEntries pairs downloaded files with their tracks as playlist entries
+// relative to dir. Tracks without a file are left out.
+func trackEntries(dir string, tracks []model.ShowTrack, files []trackFile) []playlist.Entry {
+	entries := make([]playlist.Entry, 0, len(files))
+	for i, file := range files {
+		if file.Path == "" || i >= len(tracks) || tracks[i].Track == nil {
+			continue
+		}
+		entry := playlist.Entry{
+			Location: playlist.RelativeLocation(dir, file.Path),
+			Title:    tracks[i].Track.SongTitle,
+			Duration: tracks[i].Track.TotalRunningTime,
+		}
+		if show := tracks[i].Show; show != nil {
+			entry.Artist = show.ArtistName
+			entry.Album = show.ContainerInfo
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
@@ -0,0 +1,61 @@
+package download
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jmagar/nugs-cli/internal/model"
+	"github.com/jmagar/nugs-cli/internal/playlist"
+)
+
+func TestDownloadAlbumAudioWritesPlaylists(t *testing.T) {
+	ctx := verifyTestContext(validTestM4A())
+	albumPath := filepath.Join(t.TempDir(), "Goose - Live")
+	if err := os.Mkdir(albumPath, 0755); err != nil {
+		t.Fatal(err)
+	}
+	cfg := &model.Config{Format: 1, SkipTagging: true, PlaylistFormats: []string{"m3u8", "xspf"}}
+	meta := &model.AlbArtResp{ContainerID: 42, ArtistName: "Goose", ContainerInfo: "Live"}
+	tracks := []model.Track{
+		{TrackID: 501, SongTitle: "Song", TotalRunningTime: 300},
+		{TrackID: 502, SongTitle: "Other"},
+	}
+
+	if err := downloadAlbumAudio(ctx, meta, tracks, []int{0, 1}, albumPath, "Goose", cfg, &model.StreamParams{}, nil, false, &Deps{}); err != nil {
+		t.Fatalf("downloadAlbumAudio: %v", err)
+	}
+	entries, err := playlist.ReadM3U8File(filepath.Join(albumPath, "Goose - Live.m3u8"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("entries = %+v, want 2", entries)
+	}
+	if entries[0].Location != "01. Song.m4a" || entries[0].Title != "Goose - Song" || entries[0].Duration != 300 {
+		t.Errorf("first entry = %+v", entries[0])
+	}
+	if entries[1].Duration != 0 {
+		t.Errorf("unknown duration read back as %d", entries[1].Duration)
+	}
+	xspf, err := os.ReadFile(filepath.Join(albumPath, "Goose - Live.xspf"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(xspf), "<location>01.%20Song.m4a</location>") {
+		t.Errorf("xspf missing escaped location:\n%s", xspf)
+	}
+
+	cfg.PlaylistFormats = []string{"none"}
+	quiet := filepath.Join(t.TempDir(), "Quiet")
+	if err := os.Mkdir(quiet, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := downloadAlbumAudio(ctx, meta, tracks, []int{0, 1}, quiet, "Goose", cfg, &model.StreamParams{}, nil, false, &Deps{}); err != nil {
+		t.Fatalf("downloadAlbumAudio: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(quiet, "Quiet.m3u8")); !os.IsNotExist(err) {
+		t.Errorf("playlistFormats none still wrote a playlist: %v", err)
+	}
+}
@@ -1,6 +1,7 @@
 package download
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"io"
@@ -29,6 +30,24 @@ func httpResponse(status int, body string) *http.Response {
 	}
 }
 
+// stubStreamContext answers every stream-link request with streamURL and
+// serves body for each cdn.example.com request.
+func stubStreamContext(streamURL string, body []byte) context.Context {
+	client := &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		switch {
+		case strings.Contains(req.URL.Path, "/bigriver/subPlayer.aspx"):
+			return httpResponse(http.StatusOK, fmt.Sprintf(`{"streamLink":%q}`, streamURL)), nil
+		case req.URL.Host == "cdn.example.com":
+			resp := httpResponse(http.StatusOK, "")
+			resp.Body = io.NopCloser(bytes.NewReader(body))
+			return resp, nil
+		default:
+			return nil, fmt.Errorf("unexpected request: %s", req.URL)
+		}
+	})}
+	return api.WithHTTPClient(context.Background(), client)
+}
+
 func TestProcessTrack_QualityFallback_NoHang(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -58,25 +77,15 @@ func TestProcessTrack_QualityFallback_NoHang(t *testing.T) {
 
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
-			client := &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
-				switch {
-				case strings.Contains(req.URL.Path, "/bigriver/subPlayer.aspx"):
-					return httpResponse(http.StatusOK, fmt.Sprintf(`{"streamLink":%q}`, tc.streamURL)), nil
-				case req.URL.Host == "cdn.example.com":
-					return httpResponse(http.StatusOK, "audio-bytes"), nil
-				default:
-					return nil, fmt.Errorf("unexpected request: %s", req.URL.String())
-				}
-			})}
-			ctx, cancel := context.WithTimeout(api.WithHTTPClient(context.Background(), client), 5*time.Second)
+			ctx, cancel := context.WithTimeout(stubStreamContext(tc.streamURL, []byte("audio-bytes")), 5*time.Second)
 			defer cancel()
 
 			dir := t.TempDir()
-			cfg := &model.Config{Format: tc.wantFmt}
+			cfg := stubAudioConfig(t, tc.wantFmt)
 			track := &model.Track{TrackID: 1234, SongTitle: "Track Name"}
 			streamParams := &model.StreamParams{}
 
-			if err := ProcessTrack(ctx, dir, 1, 1, cfg, track, streamParams, nil, &Deps{}); err != nil {
+			if err := ProcessTrack(ctx, dir, 1, 1, cfg, track, nil, streamParams, nil, &Deps{}); err != nil {
 				t.Fatalf("ProcessTrack returned error: %v", err)
 			}
 
@@ -0,0 +1,290 @@
+package download
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/dustin/go-humanize"
+	"github.com/jmagar/nugs-cli/internal/api"
+	"github.com/jmagar/nugs-cli/internal/ui"
+)
+
+// Partial downloads live next to their destination until complete.
+const (
+	partFileSuffix    = ".part"
+	partSidecarSuffix = ".part.json"
+)
+
+// errRestartDownload signals that a partial file cannot be resumed and the
+// transfer must start again from byte zero.
+var errRestartDownload = errors.New("partial download cannot be resumed")
+
+// partSidecar records what a .part file is a prefix of, so a later run can
+// decide whether resuming it with a Range request is safe.
+type partSidecar struct {
+	URL          string `json:"url"`
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"lastModified,omitempty"`
+	Size         int64  `json:"size,omitempty"` // Expected full size; 0 when unknown
+}
+
+// resumableDownload describes one resumable HTTP transfer.
+type resumableDownload struct {
+	URL      string
+	DestPath string
+	Header   http.Header
+	// Progress returns a writer that observes the bytes being written, given
+	// the full size (-1 when unknown) and the resume offset. May be nil.
+	Progress func(total, offset int64) io.Writer
+}
+
+// sameResource reports whether two URLs name the same file. Stream URLs carry
+// signed query parameters that rotate between sessions, so only scheme, host,
+// and path are compared; the ETag and size guard against changed content.
+func sameResource(a, b string) bool {
+	ua, errA := url.Parse(a)
+	ub, errB := url.Parse(b)
+	if errA != nil || errB != nil {
+		return a == b
+	}
+	return ua.Scheme == ub.Scheme && ua.Host == ub.Host && ua.Path == ub.Path
+}
+
+// readPartSidecar loads the sidecar for a partial download, if any.
+func readPartSidecar(path string) (*partSidecar, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var sidecar partSidecar
+	if err := json.Unmarshal(data, &sidecar); err != nil {
+		return nil, err
+	}
+	return &sidecar, nil
+}
+
+// writePartSidecar atomically replaces the sidecar for a partial download.
+func writePartSidecar(path string, sidecar *partSidecar) error {
+	data, err := json.Marshal(sidecar)
+	if err != nil {
+		return err
+	}
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return fmt.Errorf("write download sidecar: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("rename download sidecar: %w", err)
+	}
+	return nil
+}
+
+// removePartFiles deletes a partial download and its sidecar.
+func removePartFiles(partPath, sidecarPath string) {
+	_ = os.Remove(partPath)
+	_ = os.Remove(sidecarPath)
+}
+
+// resumeOffset returns how many bytes of an existing .part file can be kept,
+// discarding the partial download when its sidecar does not match rawURL.
+func resumeOffset(partPath, sidecarPath, rawURL string) (int64, *partSidecar) {
+	info, err := os.Stat(partPath)
+	if err != nil || info.Size() == 0 {
+		removePartFiles(partPath, sidecarPath)
+		return 0, nil
+	}
+	sidecar, err := readPartSidecar(sidecarPath)
+	if err != nil || !sameResource(sidecar.URL, rawURL) || (sidecar.Size > 0 && info.Size() > sidecar.Size) {
+		removePartFiles(partPath, sidecarPath)
+		return 0, nil
+	}
+	return info.Size(), sidecar
+}
+
+// parseContentRange parses "bytes start-end/total"; total is -1 when "*".
+func parseContentRange(header string) (start, total int64, ok bool) {
+	spec, found := strings.CutPrefix(strings.TrimSpace(header), "bytes ")
+	if !found {
+		return 0, 0, false
+	}
+	rangePart, totalPart, found := strings.Cut(spec, "/")
+	if !found {
+		return 0, 0, false
+	}
+	startPart, _, found := strings.Cut(rangePart, "-")
+	if !found {
+		return 0, 0, false
+	}
+	start, err := strconv.ParseInt(startPart, 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	if totalPart == "*" {
+		return start, -1, true
+	}
+	total, err = strconv.ParseInt(totalPart, 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	return start, total, true
+}
+
+// downloadResumable downloads d.URL into d.DestPath through a .part file. An
+// existing .part with a matching sidecar is resumed with a Range request
+// (guarded by If-Range when a validator is known). When the server ignores the
+// range or the validator changed, the transfer restarts cleanly. Interrupted
+// transfers keep the .part and sidecar for the next run. It returns the full
+// size announced by the server, or 0 when unknown.
+func downloadResumable(ctx context.Context, d resumableDownload) (int64, error) {
+	size, err := downloadResumableAttempt(ctx, d)
+	if errors.Is(err, errRestartDownload) {
+		removePartFiles(d.DestPath+partFileSuffix, d.DestPath+partSidecarSuffix)
+		size, err = downloadResumableAttempt(ctx, d)
+	}
+	return size, err
+}
+
+func downloadResumableAttempt(ctx context.Context, d resumableDownload) (int64, error) {
+	partPath := d.DestPath + partFileSuffix
+	sidecarPath := d.DestPath + partSidecarSuffix
+	offset, sidecar := resumeOffset(partPath, sidecarPath, d.URL)
+	if sidecar != nil && sidecar.Size > 0 && offset == sidecar.Size {
+		return sidecar.Size, finishPartFile(partPath, sidecarPath, d.DestPath, sidecar.Size)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, d.URL, nil)
+	if err != nil {
+		return 0, err
+	}
+	for key, values := range d.Header {
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+	req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	if offset > 0 {
+		if sidecar.ETag != "" {
+			req.Header.Set("If-Range", sidecar.ETag)
+		} else if sidecar.LastModified != "" {
+			req.Header.Set("If-Range", sidecar.LastModified)
+		}
+	}
+	resp, err := api.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+		_ = resp.Body.Close()
+	}()
+
+	total := int64(-1)
+	switch resp.StatusCode {
+	case http.StatusPartialContent:
+		start, rangeTotal, ok := parseContentRange(resp.Header.Get("Content-Range"))
+		switch {
+		case ok && start == offset:
+			total = rangeTotal
+		case offset > 0:
+			return 0, errRestartDownload
+		case ok:
+			return 0, fmt.Errorf("unexpected Content-Range %q", resp.Header.Get("Content-Range"))
+		}
+		if sidecar != nil && sidecar.Size > 0 && total > 0 && total != sidecar.Size {
+			return 0, errRestartDownload
+		}
+	case http.StatusOK:
+		// Range ignored or the If-Range validator no longer matches.
+		offset = 0
+		if resp.ContentLength > 0 {
+			total = resp.ContentLength
+		}
+	case http.StatusRequestedRangeNotSatisfiable:
+		if offset > 0 {
+			return 0, errRestartDownload
+		}
+		return 0, errors.New(resp.Status)
+	default:
+		return 0, errors.New(resp.Status)
+	}
+	if total < 0 && resp.ContentLength > 0 {
+		total = offset + resp.ContentLength
+	}
+
+	next := &partSidecar{
+		URL:          d.URL,
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+		Size:         max(total, 0),
+	}
+	if offset > 0 && next.ETag == "" {
+		next.ETag = sidecar.ETag
+	}
+	if offset > 0 && next.LastModified == "" {
+		next.LastModified = sidecar.LastModified
+	}
+	if err := os.MkdirAll(filepath.Dir(partPath), 0755); err != nil {
+		return 0, err
+	}
+	if err := writePartSidecar(sidecarPath, next); err != nil {
+		return 0, err
+	}
+
+	f, err := os.OpenFile(partPath, os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return 0, err
+	}
+	if err := f.Truncate(offset); err != nil {
+		_ = f.Close()
+		return 0, fmt.Errorf("truncate partial download: %w", err)
+	}
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		_ = f.Close()
+		return 0, fmt.Errorf("seek partial download: %w", err)
+	}
+	if offset > 0 {
+		ui.PrintInfo(fmt.Sprintf("Resuming %s from %s", filepath.Base(d.DestPath), humanize.Bytes(uint64(offset))))
+	}
+
+	var body io.Reader = resp.Body
+	if d.Progress != nil {
+		body = io.TeeReader(resp.Body, d.Progress(total, offset))
+	}
+	_, err = io.Copy(f, body)
+	if err = errors.Join(err, f.Close()); err != nil {
+		return 0, err
+	}
+	return next.Size, finishPartFile(partPath, sidecarPath, d.DestPath, next.Size)
+}
+
+// finishPartFile checks a completed .part against the expected size and moves
+// it into place.
+func finishPartFile(partPath, sidecarPath, destPath string, size int64) error {
+	info, err := os.Stat(partPath)
+	if err != nil {
+		return err
+	}
+	if size > 0 && info.Size() < size {
+		// Connection closed early; keep the partial file for the next run.
+		return fmt.Errorf("download incomplete: got %d of %d bytes", info.Size(), size)
+	}
+	if size > 0 && info.Size() > size {
+		removePartFiles(partPath, sidecarPath)
+		return fmt.Errorf("download size mismatch: got %d bytes, expected %d", info.Size(), size)
+	}
+	if err := os.Rename(partPath, destPath); err != nil {
+		return fmt.Errorf("finalize download: %w", err)
+	}
+	_ = os.Remove(sidecarPath)
+	return nil
+}
@@ -0,0 +1,232 @@
+package download
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jmagar/nugs-cli/internal/api"
+	"github.com/jmagar/nugs-cli/internal/helpers"
+	"github.com/jmagar/nugs-cli/internal/model"
+)
+
+var resumeContent = []byte(strings.Repeat("0123456789", 100))
+
+// rangeServer serves resumeContent with ServeContent (Range + If-Range) and
+// records the Range header of each request.
+func rangeServer(t *testing.T, etag string, ranges *[]string) (*httptest.Server, context.Context) {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*ranges = append(*ranges, r.Header.Get("Range"))
+		w.Header().Set("ETag", etag)
+		http.ServeContent(w, r, "track.flac", time.Time{}, bytes.NewReader(resumeContent))
+	}))
+	t.Cleanup(server.Close)
+	return server, api.WithHTTPClient(context.Background(), server.Client())
+}
+
+// stubAudioConfig is the config for downloads from stub servers whose bodies
+// are not real audio: verification, tagging and the other steps that read
+// the files are off.
+func stubAudioConfig(t *testing.T, format int) *model.Config {
+	t.Helper()
+	return &model.Config{
+		OutPath:                t.TempDir(),
+		Format:                 format,
+		SkipVerify:             true,
+		SkipTagging:            true,
+		SkipSizePreCalculation: true,
+		SkipHistory:            true,
+		CoverArtSource:         model.CoverArtSourceNone,
+		PlaylistFormats:        []string{"none"},
+	}
+}
+
+// stubShow returns a show whose FLAC tracks, one per title, are served by
+// serverURL (see rangeServer).
+func stubShow(serverURL string, titles ...string) *model.AlbArtResp {
+	meta := &model.AlbArtResp{ContainerID: 1, ArtistName: "Goose", ContainerInfo: "2024-07-04 Red Rocks"}
+	for i, title := range titles {
+		meta.Songs = append(meta.Songs, model.Track{
+			TrackID:   i + 1,
+			SongTitle: title,
+			TrackURL:  fmt.Sprintf("%s/audio.flac16/%d.flac", serverURL, i+1),
+		})
+	}
+	return meta
+}
+
+func writePartial(t *testing.T, dest, url, etag string, n int) {
+	t.Helper()
+	if err := os.WriteFile(dest+partFileSuffix, resumeContent[:n], 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := writePartSidecar(dest+partSidecarSuffix, &partSidecar{URL: url, ETag: etag, Size: int64(len(resumeContent))}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func assertCompleted(t *testing.T, dest string) {
+	t.Helper()
+	got, err := os.ReadFile(dest)
+	if err != nil {
+		t.Fatalf("read destination: %v", err)
+	}
+	if !bytes.Equal(got, resumeContent) {
+		t.Fatalf("destination has %d bytes, want the full %d-byte body", len(got), len(resumeContent))
+	}
+	for _, leftover := range []string{dest + partFileSuffix, dest + partSidecarSuffix} {
+		if _, err := os.Stat(leftover); !os.IsNotExist(err) {
+			t.Fatalf("%s should be removed after completion", filepath.Base(leftover))
+		}
+	}
+}
+
+func TestDownloadTrackResumesPartialFile(t *testing.T) {
+	var ranges []string
+	server, ctx := rangeServer(t, `"v1"`, &ranges)
+	dest := filepath.Join(t.TempDir(), "01. Song.flac")
+	// The signed query string differs between sessions; the path still matches.
+	writePartial(t, dest, server.URL+"/track.flac?token=old", `"v1"`, 400)
+
+	var lastDownloaded, lastTotal int64
+	err := DownloadTrack(ctx, dest, server.URL+"/track.flac?token=new", func(downloaded, total, _ int64) {
+		lastDownloaded, lastTotal = downloaded, total
+	}, false, &Deps{})
+	if err != nil {
+		t.Fatalf("DownloadTrack: %v", err)
+	}
+	if len(ranges) != 1 || ranges[0] != "bytes=400-" {
+		t.Fatalf("Range headers = %q, want a single bytes=400- request", ranges)
+	}
+	if lastDownloaded != int64(len(resumeContent)) || lastTotal != int64(len(resumeContent)) {
+		t.Fatalf("progress = %d/%d, want full size including resumed bytes", lastDownloaded, lastTotal)
+	}
+	assertCompleted(t, dest)
+}
+
+func TestDownloadTrackRestartsWhenValidatorChanged(t *testing.T) {
+	var ranges []string
+	server, ctx := rangeServer(t, `"v2"`, &ranges)
+	dest := filepath.Join(t.TempDir(), "01. Song.flac")
+	if err := os.WriteFile(dest+partFileSuffix, []byte(strings.Repeat("x", 400)), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := writePartSidecar(dest+partSidecarSuffix, &partSidecar{URL: server.URL + "/track.flac", ETag: `"v1"`, Size: int64(len(resumeContent))}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := DownloadTrack(ctx, dest, server.URL+"/track.flac", nil, false, &Deps{}); err != nil {
+		t.Fatalf("DownloadTrack: %v", err)
+	}
+	assertCompleted(t, dest)
+}
+
+func TestDownloadTrackRestartsWhenRangeIgnored(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write(resumeContent)
+	}))
+	defer server.Close()
+	ctx := api.WithHTTPClient(context.Background(), server.Client())
+	dest := filepath.Join(t.TempDir(), "01. Song.flac")
+	writePartial(t, dest, server.URL+"/track.flac", "", 400)
+
+	if err := DownloadTrack(ctx, dest, server.URL+"/track.flac", nil, false, &Deps{}); err != nil {
+		t.Fatalf("DownloadTrack: %v", err)
+	}
+	assertCompleted(t, dest)
+}
+
+func TestDownloadVideoFileKeepsPartOnInterruption(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Content-Length", "1000")
+		_, _ = w.Write(resumeContent[:300])
+		w.(http.Flusher).Flush()
+		panic(http.ErrAbortHandler)
+	}))
+	defer server.Close()
+	ctx := api.WithHTTPClient(context.Background(), server.Client())
+	dest := filepath.Join(t.TempDir(), "show.ts")
+
+	if err := DownloadVideoFile(ctx, dest, server.URL+"/video.ts", func(int64, int64, int64) {}); err == nil {
+		t.Fatal("expected an error from the interrupted transfer")
+	}
+	sidecar, err := readPartSidecar(dest + partSidecarSuffix)
+	if err != nil {
+		t.Fatalf("sidecar missing after interruption: %v", err)
+	}
+	if sidecar.ETag != `"v1"` || sidecar.Size != 1000 || sidecar.URL != server.URL+"/video.ts" {
+		t.Fatalf("sidecar = %+v", sidecar)
+	}
+	if info, err := os.Stat(dest + partFileSuffix); err != nil || info.Size() != 300 {
+		t.Fatalf("part file should hold the 300 received bytes, stat = %v, %v", info, err)
+	}
+	if _, err := os.Stat(dest); !os.IsNotExist(err) {
+		t.Fatal("destination must not exist before the transfer completes")
+	}
+}
+
+func TestAlbumResumesInterruptedShow(t *testing.T) {
+	var ranges []string
+	server, ctx := rangeServer(t, `"v1"`, &ranges)
+	cfg := stubAudioConfig(t, 2)
+	meta := stubShow(server.URL, "Arcadia", "Hot Tea")
+	albumPath := helpers.NewConfigPathResolver(cfg).LocalShowPath(meta, model.MediaTypeAudio)
+	if err := os.MkdirAll(albumPath, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	trackPath := func(i int) string {
+		name := helpers.TrackFileName(cfg, meta, helpers.TrackNameInfo{Number: i + 1, Total: 2, Title: meta.Songs[i].SongTitle, ID: meta.Songs[i].TrackID})
+		return filepath.Join(albumPath, name+".flac")
+	}
+	// Track 1 finished before the interruption; track 2 stopped at 400 bytes.
+	if err := os.WriteFile(trackPath(0), resumeContent, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	writePartial(t, trackPath(1), meta.Songs[1].TrackURL, `"v1"`, 400)
+
+	if err := Album(ctx, "", cfg, &model.StreamParams{}, meta, nil, nil, &Deps{}); err != nil {
+		t.Fatalf("Album: %v", err)
+	}
+	if len(ranges) != 1 || ranges[0] != "bytes=400-" {
+		t.Fatalf("Range headers = %q, want only track 2 resumed from byte 400", ranges)
+	}
+	assertCompleted(t, trackPath(1))
+}
+
+func TestIncompleteShowReason(t *testing.T) {
+	write := func(dir string, names ...string) string {
+		for _, name := range names {
+			if err := os.WriteFile(filepath.Join(dir, name), nil, 0o644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return dir
+	}
+	tests := []struct {
+		name   string
+		files  []string
+		tracks int
+		want   string
+	}{
+		{name: "complete", files: []string{"01. A.flac", "02. B.flac", "cover.jpg"}, tracks: 2},
+		{name: "interrupted", files: []string{"01. A.flac", "02. B.flac", "03. C.flac.part", "03. C.flac.part.json"}, tracks: 3, want: "interrupted download"},
+		{name: "missing track", files: []string{"01. A.flac", "show.m3u8"}, tracks: 3, want: "1 of 3 tracks present"},
+		{name: "single-file sets", files: []string{"Show - Set 1.flac", "Show - Set 1.cue"}, tracks: 3},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := incompleteShowReason(write(t.TempDir(), tc.files...), tc.tracks); got != tc.want {
+				t.Fatalf("incompleteShowReason = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
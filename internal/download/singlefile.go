@@ -0,0 +1,303 @@
+package download
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jmagar/nugs-cli/internal/helpers"
+	"github.com/jmagar/nugs-cli/internal/model"
+	"github.com/jmagar/nugs-cli/internal/playlist"
+	"github.com/jmagar/nugs-cli/internal/tagging"
+	"github.com/jmagar/nugs-cli/internal/ui"
+	"github.com/jmagar/nugs-cli/internal/verify"
+)
+
+// setGroup holds the downloaded tracks of one set (or disc) in listing order.
+type setGroup struct {
+	num    int
+	label  string // "Set", or "Disc" when the release numbers discs
+	tracks []model.ShowTrack
+	paths  []string
+}
+
+// renderedSet is one joined set file.
+type renderedSet struct {
+	Path     string
+	Title    string
+	Duration time.Duration
+}
+
+// groupSets splits a show's tracks into sets by disc number, falling back to
+// the set number. Every track needs a file and all files must share one
+// extension, since each set is written in the tracks' own codec.
+func groupSets(tracks []model.ShowTrack, files []trackFile) ([]setGroup, error) {
+	var (
+		groups []setGroup
+		ext    string
+	)
+	index := map[int]int{}
+	for i, item := range tracks {
+		if i >= len(files) || files[i].Path == "" {
+			return nil, fmt.Errorf("track %d has no file", i+1)
+		}
+		path := files[i].Path
+		if ext == "" {
+			ext = strings.ToLower(filepath.Ext(path))
+		} else if !strings.EqualFold(filepath.Ext(path), ext) {
+			return nil, fmt.Errorf("tracks mix %s and %s files", ext, filepath.Ext(path))
+		}
+		num := trackDiscNumber(item.Track)
+		at, ok := index[num]
+		if !ok {
+			label := "Set"
+			if item.Track.DiscNum > 0 {
+				label = "Disc"
+			}
+			at = len(groups)
+			index[num] = at
+			groups = append(groups, setGroup{num: num, label: label})
+		}
+		groups[at].tracks = append(groups[at].tracks, item)
+		groups[at].paths = append(groups[at].paths, path)
+	}
+	return groups, nil
+}
+
+// renderSingleFileSets joins a finished show's tracks into one file per set
+// with ffmpeg, writes a CUE sheet beside each and removes the track files.
+// It returns nil, leaving the tracks in place, when singleFileSets is off or
+// rendering fails; failures are warnings because the tracks are intact.
+func renderSingleFileSets(ctx context.Context, cfg *model.Config, meta *model.AlbArtResp, albumPath string, tracks []model.ShowTrack, files []trackFile, progressBox *model.ProgressBoxState) []renderedSet {
+	if !cfg.SingleFileSets || len(tracks) == 0 {
+		return nil
+	}
+	warn := func(err error) []renderedSet {
+		reportWarning(fmt.Sprintf("Single-file sets skipped, keeping separate tracks: %v", err), progressBox)
+		return nil
+	}
+	if cfg.FfmpegNameStr == "" {
+		return warn(errors.New("ffmpeg is not configured"))
+	}
+	groups, err := groupSets(tracks, files)
+	if err != nil {
+		return warn(err)
+	}
+
+	base := filepath.Base(albumPath)
+	ext := filepath.Ext(groups[0].paths[0])
+	var (
+		sets    []renderedSet
+		written []string
+	)
+	for i, group := range groups {
+		name, title := base, strings.TrimSpace(meta.ContainerInfo)
+		if len(groups) > 1 {
+			name = fmt.Sprintf("%s - %s %d", base, group.label, group.num)
+			title = fmt.Sprintf("%s (%s %d)", title, group.label, group.num)
+		}
+		outPath := filepath.Join(albumPath, name+ext)
+		cuePath := filepath.Join(albumPath, name+".cue")
+		ui.PrintInfo(fmt.Sprintf("Joining %d track(s) into %s", len(group.paths), filepath.Base(outPath)))
+		set, err := renderSet(ctx, cfg, meta, group, outPath, title, i+1, len(groups))
+		if err == nil {
+			err = playlist.SaveCue(cuePath, setCueSheet(meta, group, filepath.Base(outPath), title))
+		}
+		written = append(written, outPath, cuePath)
+		if err != nil {
+			for _, path := range written {
+				os.Remove(path)
+			}
+			return warn(err)
+		}
+		sets = append(sets, set)
+	}
+
+	for _, group := range groups {
+		for _, path := range group.paths {
+			if err := os.Remove(path); err != nil {
+				reportWarning(fmt.Sprintf("Failed to remove %s: %v", filepath.Base(path), err), progressBox)
+			}
+		}
+	}
+	return sets
+}
+
+// renderSet joins one set into outPath, checks and tags it. The join goes to
+// a temporary file so a failed run never leaves a partial set behind.
+func renderSet(ctx context.Context, cfg *model.Config, meta *model.AlbArtResp, group setGroup, outPath, title string, setNum, setTotal int) (renderedSet, error) {
+	ext := filepath.Ext(outPath)
+	tmpPath := strings.TrimSuffix(outPath, ext) + ".part" + ext
+	defer os.Remove(tmpPath)
+	chapters := make([]setChapter, len(group.paths))
+	var total time.Duration
+	for i, path := range group.paths {
+		d := trackDuration(path, group.tracks[i].Track)
+		chapters[i] = setChapter{Title: group.tracks[i].Track.SongTitle, Start: total, End: total + d}
+		total += d
+	}
+	if err := joinTracks(ctx, cfg.FfmpegNameStr, group.paths, chapters, tmpPath); err != nil {
+		return renderedSet{}, err
+	}
+	if !cfg.SkipTagging {
+		tags := tagging.Tags{
+			Artist:      meta.ArtistName,
+			AlbumArtist: meta.ArtistName,
+			Album:       strings.TrimSpace(meta.ContainerInfo),
+			Title:       title,
+			Date:        helpers.ShowDate(meta),
+			Venue:       showVenue(meta),
+			City:        meta.VenueCity,
+			State:       meta.VenueState,
+			TrackNumber: setNum,
+			TrackTotal:  setTotal,
+			ContainerID: meta.ContainerID,
+		}
+		if coverArtEnabled(cfg) {
+			tags.Picture = loadCoverArt(filepath.Dir(outPath))
+		}
+		if err := tagging.WriteFile(tmpPath, tags); err != nil && !errors.Is(err, tagging.ErrUnsupportedFormat) {
+			return renderedSet{}, fmt.Errorf("failed to tag %s: %w", filepath.Base(outPath), err)
+		}
+	}
+	// Verified after tagging, so the file that is uploaded is the one checked.
+	if !cfg.SkipVerify {
+		if err := verifyTrack(tmpPath); err != nil {
+			return renderedSet{}, fmt.Errorf("joined %s failed verification: %w", filepath.Base(outPath), err)
+		}
+	}
+	if err := os.Rename(tmpPath, outPath); err != nil {
+		return renderedSet{}, err
+	}
+	return renderedSet{Path: outPath, Title: title, Duration: total}, nil
+}
+
+// setChapter marks one track's span inside a joined set.
+type setChapter struct {
+	Title      string
+	Start, End time.Duration
+}
+
+// joinTracks concatenates paths into outPath with ffmpeg's concat demuxer and
+// writes chapters so the track boundaries survive the join. FLAC is
+// re-encoded losslessly: a stream copy would keep the first input's
+// STREAMINFO, declaring that track's length and MD5 for the whole set. Other
+// formats copy the audio stream.
+func joinTracks(ctx context.Context, ffmpegNameStr string, paths []string, chapters []setChapter, outPath string) error {
+	var list strings.Builder
+	for _, path := range paths {
+		abs, err := filepath.Abs(path)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(&list, "file '%s'\n", strings.ReplaceAll(abs, "'", `'\''`))
+	}
+	listPath, err := writeJoinInput(filepath.Dir(outPath), ".concat-*.txt", list.String())
+	if err != nil {
+		return err
+	}
+	defer os.Remove(listPath)
+	chapterPath, err := writeJoinInput(filepath.Dir(outPath), ".chapters-*.txt", chapterMetadata(chapters))
+	if err != nil {
+		return err
+	}
+	defer os.Remove(chapterPath)
+
+	codec := []string{"-c", "copy"}
+	if strings.EqualFold(filepath.Ext(outPath), ".flac") {
+		codec = []string{"-c:a", "flac"}
+	}
+	args := []string{"-hide_banner", "-nostdin", "-loglevel", "error", "-y",
+		"-f", "concat", "-safe", "0", "-i", listPath,
+		"-f", "ffmetadata", "-i", chapterPath,
+		"-map", "0:a"}
+	args = append(args, codec...)
+	args = append(args, "-map_metadata", "-1", "-map_chapters", "1", outPath)
+
+	var errBuffer bytes.Buffer
+	cmd := exec.CommandContext(ctx, ffmpegNameStr, args...)
+	cmd.Stderr = &errBuffer
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("ffmpeg join: %w: %s", err, strings.TrimSpace(errBuffer.String()))
+	}
+	return nil
+}
+
+// writeJoinInput writes one of ffmpeg's input files for a join next to the
+// output and returns its path.
+func writeJoinInput(dir, pattern, content string) (string, error) {
+	f, err := os.CreateTemp(dir, pattern)
+	if err != nil {
+		return "", err
+	}
+	_, err = f.WriteString(content)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return "", err
+	}
+	return f.Name(), nil
+}
+
+// chapterMetadata renders chapters in ffmpeg's FFMETADATA format, which the
+// FLAC muxer stores as CHAPTERxxx comments and the MP4 muxer as a chapter
+// track.
+func chapterMetadata(chapters []setChapter) string {
+	escape := strings.NewReplacer(`\`, `\\`, "=", `\=`, ";", `\;`, "#", `\#`, "\n", "\\\n")
+	var b strings.Builder
+	b.WriteString(";FFMETADATA1\n")
+	for _, chapter := range chapters {
+		fmt.Fprintf(&b, "[CHAPTER]\nTIMEBASE=1/1000\nSTART=%d\nEND=%d\ntitle=%s\n",
+			chapter.Start.Milliseconds(), chapter.End.Milliseconds(), escape.Replace(chapter.Title))
+	}
+	return b.String()
+}
+
+// setCueSheet indexes a joined set. Track offsets add up the lengths of the
+// files before them.
+func setCueSheet(meta *model.AlbArtResp, group setGroup, fileName, title string) playlist.CueSheet {
+	sheet := playlist.CueSheet{
+		File:      fileName,
+		Title:     title,
+		Performer: meta.ArtistName,
+		Date:      helpers.ShowDate(meta),
+	}
+	if venue := showVenue(meta); venue != "" {
+		if location := (tagging.Tags{City: meta.VenueCity, State: meta.VenueState}).Location(); location != "" {
+			venue += ", " + location
+		}
+		sheet.Comments = append(sheet.Comments, venue)
+	}
+	if meta.ContainerID != 0 {
+		sheet.Comments = append(sheet.Comments, "nugs.net container "+strconv.Itoa(meta.ContainerID))
+	}
+	var start time.Duration
+	for i, path := range group.paths {
+		track := group.tracks[i].Track
+		sheet.Tracks = append(sheet.Tracks, playlist.CueTrack{
+			Title:     track.SongTitle,
+			Performer: meta.ArtistName,
+			Start:     start,
+		})
+		start += trackDuration(path, track)
+	}
+	return sheet
+}
+
+// trackDuration prefers the exact length in the file header over the
+// API's whole-second running time.
+func trackDuration(path string, track *model.Track) time.Duration {
+	if d, err := verify.Duration(path); err == nil {
+		return d
+	}
+	return time.Duration(track.TotalRunningTime) * time.Second
+}
@@ -0,0 +1,235 @@
+package download
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jmagar/nugs-cli/internal/api"
+	"github.com/jmagar/nugs-cli/internal/history"
+	"github.com/jmagar/nugs-cli/internal/model"
+	"github.com/jmagar/nugs-cli/internal/playlist"
+	"github.com/jmagar/nugs-cli/internal/testutil"
+	"github.com/jmagar/nugs-cli/internal/verify"
+)
+
+// fakeFfmpeg records each join's arguments and chapter input, then writes the
+// prepared file named after the output into place, standing in for a real
+// encode.
+const fakeFfmpeg = `#!/bin/sh
+dir="$FAKE_FFMPEG_DIR"
+for out; do :; done
+name=$(basename "$out")
+printf '%s\n' "$@" > "$dir/$name.args"
+while [ $# -gt 1 ]; do
+  if [ "$1" = "-f" ] && [ "$2" = "ffmetadata" ]; then cp "$4" "$dir/$name.chapters"; fi
+  shift
+done
+cp "$dir/$name" "$out"
+`
+
+func writeFakeFfmpeg(t *testing.T, script string) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("test uses POSIX shell script, not portable to Windows")
+	}
+	path := filepath.Join(t.TempDir(), "ffmpeg")
+	if err := os.WriteFile(path, []byte(script), 0o755); err != nil {
+		t.Fatalf("write mock ffmpeg: %v", err)
+	}
+	return path
+}
+
+func singleFileTracks() []model.Track {
+	return []model.Track{
+		{TrackID: 1, SongTitle: "Opener", SetNum: 1, TotalRunningTime: 61},
+		{TrackID: 2, SongTitle: "Jam", SetNum: 1, TotalRunningTime: 600},
+		{TrackID: 3, SongTitle: "Encore", SetNum: 2, TotalRunningTime: 300},
+	}
+}
+
+// singleFileFLACs encodes each of singleFileTracks as a distinct FLAC and
+// returns them with the sample counts used.
+func singleFileFLACs() (files [][]byte, left, right [][]int64) {
+	for i, n := range []int{3000, 4000, 2000} {
+		l, r := testutil.ToneSamples(n, i*7919)
+		files = append(files, testutil.EncodeFLAC(l, r, 512))
+		left, right = append(left, l), append(right, r)
+	}
+	return files, left, right
+}
+
+// flacTestContext serves files[i] as the FLAC stream of track i+1.
+func flacTestContext(files [][]byte) context.Context {
+	client := &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		switch {
+		case strings.Contains(req.URL.Path, "/bigriver/subPlayer.aspx"):
+			link := "https://cdn.example.com/audio.flac16/" + req.URL.Query().Get("trackID") + ".bin"
+			return httpResponse(http.StatusOK, `{"streamLink":"`+link+`"}`), nil
+		case req.URL.Host == "cdn.example.com":
+			id, _ := strconv.Atoi(strings.TrimSuffix(filepath.Base(req.URL.Path), ".bin"))
+			if id < 1 || id > len(files) {
+				return httpResponse(http.StatusNotFound, ""), nil
+			}
+			resp := httpResponse(http.StatusOK, "")
+			resp.Body = io.NopCloser(bytes.NewReader(files[id-1]))
+			return resp, nil
+		default:
+			return nil, fmt.Errorf("unexpected request: %s", req.URL)
+		}
+	})}
+	return api.WithHTTPClient(context.Background(), client)
+}
+
+// prepareJoin stores what the fake ffmpeg writes for a set's temporary output.
+func prepareJoin(t *testing.T, dir, setName string, data []byte) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, setName+".part.flac"), data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func singleFileFixture(t *testing.T) (albumPath, ffmpegDir string, cfg *model.Config, meta *model.AlbArtResp) {
+	t.Helper()
+	ffmpegDir = t.TempDir()
+	t.Setenv("FAKE_FFMPEG_DIR", ffmpegDir)
+	albumPath = filepath.Join(t.TempDir(), "Goose - Live")
+	if err := os.Mkdir(albumPath, 0755); err != nil {
+		t.Fatal(err)
+	}
+	cfg = &model.Config{Format: 2, SingleFileSets: true, FfmpegNameStr: writeFakeFfmpeg(t, fakeFfmpeg)}
+	meta = &model.AlbArtResp{ContainerID: 42, ArtistName: "Goose", ContainerInfo: "Live", VenueName: "Red Rocks", VenueState: "CO"}
+	return albumPath, ffmpegDir, cfg, meta
+}
+
+func TestDownloadAlbumAudioJoinsFLACSets(t *testing.T) {
+	files, left, right := singleFileFLACs()
+	albumPath, ffmpegDir, cfg, meta := singleFileFixture(t)
+	prepareJoin(t, ffmpegDir, "Goose - Live - Set 1", testutil.EncodeFLAC(slices.Concat(left[0], left[1]), slices.Concat(right[0], right[1]), 512))
+	prepareJoin(t, ffmpegDir, "Goose - Live - Set 2", files[2])
+	store := history.Open(t.TempDir())
+
+	if err := downloadAlbumAudio(flacTestContext(files), meta, singleFileTracks(), []int{0, 1, 2}, albumPath, "Goose", cfg, &model.StreamParams{}, nil, false, &Deps{History: store}); err != nil {
+		t.Fatalf("downloadAlbumAudio: %v", err)
+	}
+
+	names, err := filepath.Glob(filepath.Join(albumPath, "*"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range names {
+		names[i] = filepath.Base(names[i])
+	}
+	want := []string{"Goose - Live - Set 1.cue", "Goose - Live - Set 1.flac", "Goose - Live - Set 2.cue", "Goose - Live - Set 2.flac", "Goose - Live.m3u8"}
+	if !slices.Equal(names, want) {
+		t.Fatalf("album folder = %v, want %v", names, want)
+	}
+	set1 := filepath.Join(albumPath, "Goose - Live - Set 1.flac")
+	if err := verify.File(set1); err != nil {
+		t.Fatalf("tagged set 1 fails verification: %v", err)
+	}
+	if d, err := verify.Duration(set1); err != nil || d != 7000*time.Second/44100 {
+		t.Fatalf("set 1 declares %v (%v), want both tracks", d, err)
+	}
+
+	args, err := os.ReadFile(filepath.Join(ffmpegDir, "Goose - Live - Set 1.part.flac.args"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(args), "-c:a\nflac\n") || strings.Contains(string(args), "copy") {
+		t.Errorf("FLAC join should re-encode, got args:\n%s", args)
+	}
+	if !strings.Contains(string(args), "-map_chapters\n1\n") {
+		t.Errorf("FLAC join should map the chapter input, got args:\n%s", args)
+	}
+	chapters, err := os.ReadFile(filepath.Join(ffmpegDir, "Goose - Live - Set 1.part.flac.chapters"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range []string{";FFMETADATA1", "START=0", "END=68", "title=Opener", "START=68", "END=158", "title=Jam"} {
+		if !strings.Contains(string(chapters), line+"\n") {
+			t.Errorf("chapters missing %q:\n%s", line, chapters)
+		}
+	}
+
+	cue, err := os.ReadFile(filepath.Join(albumPath, "Goose - Live - Set 1.cue"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range []string{
+		`REM COMMENT "Red Rocks, CO"`,
+		`TITLE "Live (Set 1)"`,
+		`FILE "Goose - Live - Set 1.flac" WAVE`,
+		`    TITLE "Jam"`,
+		`    INDEX 01 00:00:05`,
+	} {
+		if !strings.Contains(string(cue), line+"\n") {
+			t.Errorf("cue sheet missing %q:\n%s", line, cue)
+		}
+	}
+
+	entries, err := playlist.ReadM3U8File(filepath.Join(albumPath, "Goose - Live.m3u8"))
+	if err != nil || len(entries) != 2 || entries[0].Location != "Goose - Live - Set 1.flac" {
+		t.Fatalf("playlist = %+v, %v; want the two sets", entries, err)
+	}
+
+	recorded, err := store.Entries()
+	if err != nil || len(recorded) != 1 {
+		t.Fatalf("history = %+v, %v", recorded, err)
+	}
+	if len(recorded[0].TrackIDs) != 3 || len(recorded[0].Checksums) != 2 || recorded[0].Checksums["Goose - Live - Set 2.flac"] == "" {
+		t.Errorf("history entry = %+v, want 3 tracks and the set checksums", recorded[0])
+	}
+}
+
+func TestStreamCopiedFLACSetFailsVerification(t *testing.T) {
+	files, _, _ := singleFileFLACs()
+	albumPath, ffmpegDir, cfg, meta := singleFileFixture(t)
+	// What "-c copy" produces: the first track's STREAMINFO followed by the
+	// frames of every track.
+	copied := append(append([]byte{}, files[0]...), files[1][8+34:]...)
+	prepareJoin(t, ffmpegDir, "Goose - Live - Set 1", copied)
+	prepareJoin(t, ffmpegDir, "Goose - Live - Set 2", files[2])
+
+	if err := downloadAlbumAudio(flacTestContext(files), meta, singleFileTracks(), []int{0, 1, 2}, albumPath, "Goose", cfg, &model.StreamParams{}, nil, false, &Deps{}); err != nil {
+		t.Fatalf("downloadAlbumAudio: %v", err)
+	}
+	tracks, _ := filepath.Glob(filepath.Join(albumPath, "0*.flac"))
+	if len(tracks) != 3 {
+		t.Fatalf("track files = %v, want all 3 kept", tracks)
+	}
+	if leftovers, _ := filepath.Glob(filepath.Join(albumPath, "*Set*")); len(leftovers) != 0 {
+		t.Fatalf("rejected join left %v behind", leftovers)
+	}
+}
+
+func TestSingleFileSetsKeepTracksWhenFfmpegFails(t *testing.T) {
+	ctx := verifyTestContext(validTestM4A())
+	albumPath := filepath.Join(t.TempDir(), "Goose - Live")
+	if err := os.Mkdir(albumPath, 0755); err != nil {
+		t.Fatal(err)
+	}
+	cfg := &model.Config{Format: 1, SkipTagging: true, SingleFileSets: true, FfmpegNameStr: writeFakeFfmpeg(t, "#!/bin/sh\necho boom >&2\nexit 1\n")}
+	meta := &model.AlbArtResp{ArtistName: "Goose", ContainerInfo: "Live"}
+
+	if err := downloadAlbumAudio(ctx, meta, singleFileTracks(), []int{0, 1, 2}, albumPath, "Goose", cfg, &model.StreamParams{}, nil, false, &Deps{}); err != nil {
+		t.Fatalf("downloadAlbumAudio: %v", err)
+	}
+	tracks, _ := filepath.Glob(filepath.Join(albumPath, "0*.m4a"))
+	if len(tracks) != 3 {
+		t.Fatalf("track files = %v, want all 3 kept", tracks)
+	}
+	if leftovers, _ := filepath.Glob(filepath.Join(albumPath, "*Set*")); len(leftovers) != 0 {
+		t.Fatalf("failed join left %v behind", leftovers)
+	}
+}
@@ -0,0 +1,88 @@
+package download
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/jmagar/nugs-cli/internal/helpers"
+	"github.com/jmagar/nugs-cli/internal/model"
+	"github.com/jmagar/nugs-cli/internal/tagging"
+	"github.com/jmagar/nugs-cli/internal/ui"
+)
+
+// trackDiscNumber returns the disc number of a track, falling back to the set number.
+func trackDiscNumber(track *model.Track) int {
+	if track.DiscNum > 0 {
+		return track.DiscNum
+	}
+	return track.SetNum
+}
+
+// showDiscTotal returns the highest disc/set number across the show's tracks.
+func showDiscTotal(meta *model.AlbArtResp) int {
+	total := 0
+	for _, tracks := range [][]model.Track{meta.Tracks, meta.Songs} {
+		for i := range tracks {
+			total = max(total, trackDiscNumber(&tracks[i]))
+		}
+	}
+	return total
+}
+
+// showVenue prefers the structured venue name over the display venue string.
+func showVenue(meta *model.AlbArtResp) string {
+	if venue := strings.TrimSpace(meta.VenueName); venue != "" {
+		return venue
+	}
+	return strings.TrimSpace(meta.Venue)
+}
+
+// buildTrackTags maps show and track metadata onto embedded tag fields.
+func buildTrackTags(meta *model.AlbArtResp, track *model.Track, trackNum, trackTotal int) tagging.Tags {
+	discNum := trackDiscNumber(track)
+	discTotal := showDiscTotal(meta)
+	if discNum == 0 {
+		discTotal = 0
+	}
+	return tagging.Tags{
+		Artist:      meta.ArtistName,
+		AlbumArtist: meta.ArtistName,
+		Album:       strings.TrimSpace(meta.ContainerInfo),
+		Title:       track.SongTitle,
+		Date:        helpers.ShowDate(meta),
+		Venue:       showVenue(meta),
+		City:        meta.VenueCity,
+		State:       meta.VenueState,
+		TrackNumber: trackNum,
+		TrackTotal:  trackTotal,
+		DiscNumber:  discNum,
+		DiscTotal:   discTotal,
+		ContainerID: meta.ContainerID,
+		TrackID:     track.TrackID,
+	}
+}
+
+// reportWarning prints a non-fatal warning and mirrors it in the progress box.
+func reportWarning(msg string, progressBox *model.ProgressBoxState) {
+	ui.PrintWarning(msg)
+	if progressBox != nil {
+		progressBox.SetMessage(model.MessagePriorityWarning, msg, model.StatusMessageDuration)
+	}
+}
+
+// tagTrack embeds metadata and, when present, the show's folder.jpg into a
+// freshly downloaded track. Tagging failures are reported as warnings because
+// the audio itself is intact.
+func tagTrack(trackPath string, cfg *model.Config, meta *model.AlbArtResp, track *model.Track, trackNum, trackTotal int, progressBox *model.ProgressBoxState) {
+	if cfg.SkipTagging || meta == nil {
+		return
+	}
+	tags := buildTrackTags(meta, track, trackNum, trackTotal)
+	if coverArtEnabled(cfg) {
+		tags.Picture = loadCoverArt(filepath.Dir(trackPath))
+	}
+	if err := tagging.WriteFile(trackPath, tags); err != nil {
+		reportWarning(fmt.Sprintf("Failed to write tags for track %d: %v", trackNum, err), progressBox)
+	}
+}
@@ -0,0 +1,55 @@
+package download
+
+import (
+	"testing"
+
+	"github.com/jmagar/nugs-cli/internal/model"
+)
+
+func TestBuildTrackTags(t *testing.T) {
+	meta := &model.AlbArtResp{
+		ArtistName:      "Billy Strings",
+		ContainerInfo:   "03/02/24 Red Rocks Amphitheatre ",
+		ContainerID:     42,
+		PerformanceDate: "3/2/2024",
+		Venue:           "Red Rocks",
+		VenueName:       "Red Rocks Amphitheatre",
+		VenueCity:       "Morrison",
+		VenueState:      "CO",
+		Tracks: []model.Track{
+			{SetNum: 1}, {SetNum: 2}, {SetNum: 3},
+		},
+	}
+	track := &model.Track{TrackID: 7, SongTitle: "Meet Me at the Creek", SetNum: 2}
+
+	got := buildTrackTags(meta, track, 5, 22)
+	if got.Artist != "Billy Strings" || got.AlbumArtist != "Billy Strings" {
+		t.Fatalf("artist tags = %q/%q", got.Artist, got.AlbumArtist)
+	}
+	if got.Album != "03/02/24 Red Rocks Amphitheatre" {
+		t.Fatalf("album = %q, want trimmed container info", got.Album)
+	}
+	if got.Date != "2024-03-02" {
+		t.Fatalf("date = %q, want 2024-03-02", got.Date)
+	}
+	if got.Venue != "Red Rocks Amphitheatre" || got.Location() != "Morrison, CO" {
+		t.Fatalf("venue = %q location = %q", got.Venue, got.Location())
+	}
+	if got.TrackNumber != 5 || got.TrackTotal != 22 {
+		t.Fatalf("track = %d/%d, want 5/22", got.TrackNumber, got.TrackTotal)
+	}
+	if got.DiscNumber != 2 || got.DiscTotal != 3 {
+		t.Fatalf("disc = %d/%d, want set 2/3", got.DiscNumber, got.DiscTotal)
+	}
+	if got.ContainerID != 42 || got.TrackID != 7 {
+		t.Fatalf("ids = %d/%d", got.ContainerID, got.TrackID)
+	}
+}
+
+func TestBuildTrackTagsOmitsDiscTotalWithoutDisc(t *testing.T) {
+	meta := &model.AlbArtResp{Songs: []model.Track{{DiscNum: 2}}}
+	got := buildTrackTags(meta, &model.Track{}, 1, 1)
+	if got.DiscNumber != 0 || got.DiscTotal != 0 {
+		t.Fatalf("disc = %d/%d, want 0/0 for a track without disc or set", got.DiscNumber, got.DiscTotal)
+	}
+}
@@ -0,0 +1,41 @@
+package download
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmagar/nugs-cli/internal/model"
+	"github.com/jmagar/nugs-cli/internal/transcode"
+	"github.com/jmagar/nugs-cli/internal/ui"
+)
+
+// transcodeFiles makes the configured portable copies of a finished
+// download's audio files, which sit in dir. show places copies for profiles
+// with a folder template; nil mirrors the download layout. It runs before the
+// upload so deleteAfterUpload cannot remove the sources first. Failures are
+// warnings because the download itself is intact.
+func transcodeFiles(ctx context.Context, cfg *model.Config, dir string, files []string, show *model.AlbArtResp, progressBox *model.ProgressBoxState) {
+	if len(cfg.TranscodeProfiles) == 0 || len(files) == 0 {
+		return
+	}
+	jobs := transcode.Jobs(cfg.TranscodeProfiles, cfg.OutPath, dir, files, show)
+	if len(jobs) == 0 {
+		return
+	}
+	ui.PrintInfo(fmt.Sprintf("Transcoding %d file(s) for %d profile(s)", len(files), len(cfg.TranscodeProfiles)))
+	var (
+		failed   int
+		firstErr error
+	)
+	for _, result := range transcode.Run(ctx, cfg.FfmpegNameStr, jobs, cfg.TranscodeConcurrency, nil) {
+		if result.Err != nil {
+			failed++
+			if firstErr == nil {
+				firstErr = result.Err
+			}
+		}
+	}
+	if failed > 0 {
+		reportWarning(fmt.Sprintf("%d of %d transcode(s) failed: %v", failed, len(jobs), firstErr), progressBox)
+	}
+}
@@ -0,0 +1,80 @@
+package download
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jmagar/nugs-cli/internal/model"
+	"github.com/jmagar/nugs-cli/internal/ui"
+	"github.com/jmagar/nugs-cli/internal/verify"
+)
+
+// verifyTrack checks the audio of a track after it is tagged, so the file
+// that is uploaded and recorded is the one verified. Formats without a
+// verifier pass.
+func verifyTrack(trackPath string) error {
+	if err := verify.File(trackPath); err != nil && !errors.Is(err, verify.ErrUnsupportedFormat) {
+		return err
+	}
+	return nil
+}
+
+// rejectTrack sets aside a freshly downloaded track that failed a check,
+// publishes verify.failed and returns the error to report for it.
+func rejectTrack(ctx context.Context, trackPath, trackFname string, verifyErr error, meta *model.AlbArtResp, track *model.Track, pos trackPosition, deps *Deps) error {
+	// Never leave a damaged file where the next run would skip it.
+	if corruptPath, qErr := quarantineTrack(trackPath); qErr == nil {
+		ui.PrintError(fmt.Sprintf("Track failed verification, kept as %s", filepath.Base(corruptPath)))
+	} else {
+		_ = os.Remove(trackPath)
+		ui.PrintError("Track failed verification")
+	}
+	err := fmt.Errorf("verify %s: %w", trackFname, verifyErr)
+	deps.publish(ctx, trackEvent(model.EventVerifyFailed, meta, track, pos, trackPath, err))
+	return err
+}
+
+// corruptShowReason verifies the audio files of an existing show folder and
+// describes the first that fails. When all pass it returns "" and the paths
+// of the files it checked.
+func corruptShowReason(albumPath string) (string, []string) {
+	entries, err := os.ReadDir(albumPath)
+	if err != nil {
+		return "", nil
+	}
+	var verified []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		switch strings.ToLower(filepath.Ext(entry.Name())) {
+		case ".flac", ".m4a", ".mp4":
+		default:
+			continue
+		}
+		trackPath := filepath.Join(albumPath, entry.Name())
+		if err := verifyTrack(trackPath); err != nil {
+			return fmt.Sprintf("%s failed verification", entry.Name()), nil
+		}
+		verified = append(verified, trackPath)
+	}
+	return "", verified
+}
+
+// corruptSuffix marks a track that failed verification. The file is set
+// aside rather than deleted so a verifier bug cannot destroy good audio.
+const corruptSuffix = ".corrupt"
+
+// quarantineTrack moves a track that failed verification out of the way so
+// the next run downloads it again, and returns the new path.
+func quarantineTrack(trackPath string) (string, error) {
+	corruptPath := trackPath + corruptSuffix
+	if err := os.Rename(trackPath, corruptPath); err != nil {
+		return "", err
+	}
+	return corruptPath, nil
+}
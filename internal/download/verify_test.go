@@ -0,0 +1,177 @@
+package download
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jmagar/nugs-cli/internal/helpers"
+	"github.com/jmagar/nugs-cli/internal/history"
+	"github.com/jmagar/nugs-cli/internal/model"
+	"github.com/jmagar/nugs-cli/internal/verify"
+)
+
+// validTestM4A builds ftyp + moov + mdat whose single sample fills mdat.
+func validTestM4A() []byte {
+	atom := func(typ string, body []byte) []byte {
+		out := binary.BigEndian.AppendUint32(nil, uint32(8+len(body)))
+		return append(append(out, typ...), body...)
+	}
+	words := func(values ...uint32) []byte {
+		var out []byte
+		for _, v := range values {
+			out = binary.BigEndian.AppendUint32(out, v)
+		}
+		return out
+	}
+	ftyp := atom("ftyp", []byte("M4A \x00\x00\x00\x00"))
+	moov := func(offset uint32) []byte {
+		stbl := atom("stbl", bytes.Join([][]byte{
+			atom("stsz", words(0, 16, 1)),
+			atom("stsc", words(0, 1, 1, 1, 1)),
+			atom("stco", words(0, 1, offset)),
+		}, nil))
+		return atom("moov", atom("trak", atom("mdia", atom("minf", stbl))))
+	}
+	offset := uint32(len(ftyp) + len(moov(0)) + 8)
+	return bytes.Join([][]byte{ftyp, moov(offset), atom("mdat", make([]byte, 16))}, nil)
+}
+
+func verifyTestContext(body []byte) context.Context {
+	return stubStreamContext("https://cdn.example.com/audio.alac16/track.bin", body)
+}
+
+func TestProcessTrackRejectsCorruptDownload(t *testing.T) {
+	ctx := verifyTestContext(validTestM4A()[:40])
+	dir := t.TempDir()
+	cfg := &model.Config{Format: 1}
+	track := &model.Track{TrackID: 1, SongTitle: "Song"}
+
+	err := ProcessTrack(ctx, dir, 1, 1, cfg, track, nil, &model.StreamParams{}, nil, &Deps{})
+	if !errors.Is(err, verify.ErrTruncated) {
+		t.Fatalf("ProcessTrack error = %v, want verify.ErrTruncated", err)
+	}
+	if _, statErr := os.Stat(filepath.Join(dir, "01. Song.m4a")); !os.IsNotExist(statErr) {
+		t.Fatalf("corrupt track should be moved aside, stat err = %v", statErr)
+	}
+	if kept, statErr := os.ReadFile(filepath.Join(dir, "01. Song.m4a.corrupt")); statErr != nil || len(kept) != 40 {
+		t.Fatalf("corrupt track should be kept as .corrupt, got %d bytes (%v)", len(kept), statErr)
+	}
+}
+
+func TestProcessTrackRedownloadsCorruptExistingTrack(t *testing.T) {
+	valid := validTestM4A()
+	ctx := verifyTestContext(valid)
+	dir := t.TempDir()
+	trackPath := filepath.Join(dir, "01. Song.m4a")
+	if err := os.WriteFile(trackPath, valid[:len(valid)-4], 0644); err != nil {
+		t.Fatal(err)
+	}
+	progressBox := &model.ProgressBoxState{}
+
+	cfg := &model.Config{Format: 1}
+	track := &model.Track{TrackID: 1, SongTitle: "Song"}
+	if err := ProcessTrack(ctx, dir, 1, 1, cfg, track, nil, &model.StreamParams{}, progressBox, &Deps{}); err != nil {
+		t.Fatalf("ProcessTrack: %v", err)
+	}
+	got, err := os.ReadFile(trackPath)
+	if err != nil || !bytes.Equal(got, valid) {
+		t.Fatalf("track was not replaced with the downloaded copy (err %v)", err)
+	}
+	if progressBox.SkippedTracks != 0 {
+		t.Fatalf("SkippedTracks = %d, want 0 for a re-downloaded track", progressBox.SkippedTracks)
+	}
+	if _, err := os.Stat(trackPath + ".corrupt"); !os.IsNotExist(err) {
+		t.Fatalf("set-aside copy should be removed once the new download verifies, stat err = %v", err)
+	}
+}
+
+func TestProcessTrackVerifiesTaggedFile(t *testing.T) {
+	files, _, _ := singleFileFLACs()
+	dir := t.TempDir()
+	cfg := &model.Config{Format: 2}
+	meta := &model.AlbArtResp{ContainerID: 42, ArtistName: "Goose", ContainerInfo: "Live"}
+	track := &model.Track{TrackID: 1, SongTitle: "Song"}
+
+	if err := ProcessTrack(flacTestContext(files), dir, 1, 1, cfg, track, meta, &model.StreamParams{}, nil, &Deps{}); err != nil {
+		t.Fatalf("ProcessTrack: %v", err)
+	}
+	trackPath := filepath.Join(dir, "01. Song.flac")
+	got, err := os.ReadFile(trackPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The file left on disk is the tagged one, and it still verifies.
+	if len(got) == len(files[0]) || !bytes.Contains(got, []byte("TITLE=Song")) {
+		t.Fatalf("track was not tagged (%d bytes, download %d)", len(got), len(files[0]))
+	}
+	if err := verify.File(trackPath); err != nil {
+		t.Fatalf("tagged track fails verification: %v", err)
+	}
+}
+
+func TestPrepareAlbumPathsReverifiesUnrecordedShow(t *testing.T) {
+	valid := validTestM4A()
+	store := history.Open(t.TempDir())
+	cfg := &model.Config{OutPath: t.TempDir(), Format: 1}
+	meta := &model.AlbArtResp{ContainerID: 7, ArtistName: "Goose", ContainerInfo: "Live"}
+	albumPath := helpers.NewConfigPathResolver(cfg).LocalShowPath(meta, model.MediaTypeAudio)
+	if err := os.MkdirAll(albumPath, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for name, data := range map[string][]byte{"01. A.m4a": valid, "02. B.m4a": valid[:len(valid)-4]} {
+		if err := os.WriteFile(filepath.Join(albumPath, name), data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	deps := &Deps{History: store}
+
+	if _, _, skip, err := prepareAlbumPaths(context.Background(), cfg, meta, 2, false, deps); err != nil || skip {
+		t.Fatalf("prepareAlbumPaths = skip %v, %v; want the truncated show resumed", skip, err)
+	}
+	if err := store.Record(history.Entry{ContainerID: 7, Media: history.MediaAudio}); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, skip, err := prepareAlbumPaths(context.Background(), cfg, meta, 2, false, deps); err != nil || !skip {
+		t.Fatalf("prepareAlbumPaths = skip %v, %v; want a recorded show skipped without re-verifying", skip, err)
+	}
+}
+
+func TestPrepareAlbumPathsRecordsVerifiedShow(t *testing.T) {
+	valid := validTestM4A()
+	store := history.Open(t.TempDir())
+	cfg := &model.Config{OutPath: t.TempDir(), Format: 1}
+	meta := &model.AlbArtResp{ContainerID: 7, ArtistName: "Goose", ContainerInfo: "Live"}
+	albumPath := helpers.NewConfigPathResolver(cfg).LocalShowPath(meta, model.MediaTypeAudio)
+	if err := os.MkdirAll(albumPath, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	trackPath := filepath.Join(albumPath, "01. A.m4a")
+	if err := os.WriteFile(trackPath, valid, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	deps := &Deps{History: store}
+
+	if _, _, skip, err := prepareAlbumPaths(context.Background(), cfg, meta, 1, false, deps); err != nil || !skip {
+		t.Fatalf("prepareAlbumPaths = skip %v, %v; want a verified show skipped", skip, err)
+	}
+	entries, err := store.Entries()
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("Entries = %v, %v; want the verified show recorded", entries, err)
+	}
+	if entries[0].LocalPath != albumPath || entries[0].Checksums["01. A.m4a"] == "" {
+		t.Fatalf("entry = %+v; want the show folder and its checksums", entries[0])
+	}
+
+	// The recorded show is trusted: a later run does not decode it again.
+	if err := os.WriteFile(trackPath, valid[:len(valid)-4], 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, skip, err := prepareAlbumPaths(context.Background(), cfg, meta, 1, false, deps); err != nil || !skip {
+		t.Fatalf("prepareAlbumPaths = skip %v, %v; want the recorded show skipped", skip, err)
+	}
+}
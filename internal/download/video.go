@@ -255,64 +255,23 @@ func GetSegUrlsContext(ctx context.Context, manifestUrl, query string) ([]string
 }
 
 // DownloadVideoFile downloads a video file from a URL with progress tracking.
+// Interrupted transfers leave a .part file that the next call resumes.
 func DownloadVideoFile(ctx context.Context, videoPath, _url string, onProgress func(downloaded, total, speed int64)) error {
-	f, err := os.OpenFile(videoPath, os.O_CREATE|os.O_WRONLY, 0644)
-	if err != nil {
-		return err
-	}
-	defer f.Close()
-
-	stat, err := f.Stat()
-	if err != nil {
-		return err
-	}
-	startByte := stat.Size()
-
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, _url, nil)
-	if err != nil {
-		return err
-	}
-	req.Header.Add("Range", fmt.Sprintf("bytes=%d-", startByte))
-	do, err := api.Do(req)
-	if err != nil {
-		return err
-	}
-	defer do.Body.Close()
-	if do.StatusCode != http.StatusOK && do.StatusCode != http.StatusPartialContent {
-		return errors.New(do.Status)
-	}
-
-	// Server ignored Range header — restart from beginning
-	if do.StatusCode == http.StatusOK && startByte > 0 {
-		startByte = 0
-		if err := f.Truncate(0); err != nil {
-			return fmt.Errorf("failed to truncate file for full re-download: %w", err)
-		}
-		if _, err := f.Seek(0, io.SeekStart); err != nil {
-			return fmt.Errorf("failed to seek to start: %w", err)
-		}
-	}
-
-	if startByte > 0 {
-		fmt.Printf("TS already exists locally, resuming from byte %d...\n", startByte)
-		// Seek to the correct position before writing resumed data
-		if _, err := f.Seek(int64(startByte), io.SeekStart); err != nil {
-			return fmt.Errorf("failed to seek to resume position: %w", err)
-		}
-	}
-
-	totalBytes := do.ContentLength
-	counter := &model.WriteCounter{
-		Total:      totalBytes,
-		TotalStr:   humanize.Bytes(uint64(totalBytes)),
-		StartTime:  time.Now().UnixMilli(),
-		Downloaded: startByte,
-		OnProgress: onProgress,
-	}
-
-	// Use a simple writer that doesn't need deps (video has its own progress callbacks)
-	_, err = io.Copy(f, io.TeeReader(do.Body, &simpleWriteCounter{wc: counter}))
-	err = errors.Join(err, f.Close())
+	_, err := downloadResumable(ctx, resumableDownload{
+		URL:      _url,
+		DestPath: videoPath,
+		Progress: func(total, offset int64) io.Writer {
+			// Use a simple writer that doesn't need deps (video has its own progress callbacks)
+			return &simpleWriteCounter{wc: &model.WriteCounter{
+				Total:      total,
+				TotalStr:   humanize.Bytes(uint64(max(total, 0))),
+				StartTime:  time.Now().UnixMilli(),
+				Downloaded: offset,
+				ResumedAt:  offset,
+				OnProgress: onProgress,
+			}}
+		},
+	})
 	if onProgress == nil {
 		fmt.Println("")
 	}
@@ -641,16 +600,23 @@ func resolveManifestAndVariant(ctx context.Context, meta *model.AlbArtResp, vide
 	return manifestUrl, variant, retRes, nil
 }
 
-// prepareVideoPathsAndCheck creates directories and checks for existing files locally/remotely.
-// Returns artistFolder, vidPathTs, vidPath, and whether the video should be skipped.
+// prepareVideoPathsAndCheck creates the video's show folder, laid out by the
+// folder template like an audio show, and checks for existing files
+// locally/remotely. Returns the remote-relative show folder the video is
+// uploaded into, vidPathTs, vidPath, and whether the video should be skipped.
 func prepareVideoPathsAndCheck(ctx context.Context, meta *model.AlbArtResp, videoFname, retRes string, cfg *model.Config, deps *Deps) (string, string, string, bool, error) {
-	artistFolder := helpers.Sanitise(meta.ArtistName)
-	artistPath := filepath.Join(helpers.GetVideoOutPath(cfg), artistFolder)
-	if err := helpers.MakeDirs(artistPath); err != nil {
-		ui.PrintError("Failed to make artist folder")
+	resolver := helpers.NewConfigPathResolver(cfg)
+	showPath := resolver.LocalShowPath(meta, model.MediaTypeVideo)
+	if showPath == "" {
+		return "", "", "", false, fmt.Errorf("video folder for %q escapes the video output path", meta.ContainerInfo)
+	}
+	if err := helpers.MakeDirs(showPath); err != nil {
+		ui.PrintError("Failed to make video folder")
 		return "", "", "", false, err
 	}
-	vidPathNoExt := filepath.Join(artistPath, helpers.Sanitise(videoFname+"_"+retRes))
+	remoteFolder := resolver.RemoteShowPath(meta)
+	videoName := helpers.Sanitise(videoFname + "_" + retRes)
+	vidPathNoExt := filepath.Join(showPath, videoName)
 	vidPathTs := vidPathNoExt + ".ts"
 	vidPath := vidPathNoExt + ".mp4"
 	exists, err := helpers.FileExists(vidPath)
@@ -658,12 +624,17 @@ func prepareVideoPathsAndCheck(ctx context.Context, meta *model.AlbArtResp, vide
 		ui.PrintError("Failed to check if video already exists locally")
 		return "", "", "", false, err
 	}
+	if !exists && cfg.FolderTemplate == "" {
+		// Earlier versions saved videos directly in the artist folder.
+		legacy := filepath.Join(helpers.GetVideoOutPath(cfg), helpers.Sanitise(meta.ArtistName), videoName+".mp4")
+		exists, _ = helpers.FileExists(legacy)
+	}
 	if exists {
 		ui.PrintInfo(fmt.Sprintf("Video exists %s skipping", ui.SymbolArrow))
 		return "", "", "", true, nil
 	}
 	if cfg.RcloneEnabled {
-		remoteVideoPath := path.Join(artistFolder, filepath.Base(vidPath))
+		remoteVideoPath := path.Join(remoteFolder, filepath.Base(vidPath))
 		ui.PrintInfo(fmt.Sprintf("Checking remote for video: %s%s%s", ui.ColorCyan, filepath.Base(vidPath), ui.ColorReset))
 		remoteExists, checkErr := deps.CheckRemotePathExists(ctx, remoteVideoPath, cfg, true)
 		if checkErr != nil {
@@ -673,7 +644,7 @@ func prepareVideoPathsAndCheck(ctx context.Context, meta *model.AlbArtResp, vide
 			return "", "", "", true, nil
 		}
 	}
-	return artistFolder, vidPathTs, vidPath, false, nil
+	return remoteFolder, vidPathTs, vidPath, false, nil
 }
 
 // downloadVideoContent downloads the video content (livestream segments or single file).
@@ -726,7 +697,8 @@ func downloadVideoContent(ctx context.Context, vidPathTs, manBaseUrl string, seg
 }
 
 // convertAndUploadVideo handles chapter extraction, TS-to-MP4 conversion, and optional upload.
-func convertAndUploadVideo(ctx context.Context, vidPathTs, vidPath, artistFolder string, meta *model.AlbArtResp, cfg *model.Config, chapsAvail bool, progressBox *model.ProgressBoxState, deps *Deps) error {
+// The converted file is recorded in the download history before the upload.
+func convertAndUploadVideo(ctx context.Context, vidPathTs, vidPath, remoteFolder, format string, meta *model.AlbArtResp, cfg *model.Config, chapsAvail bool, progressBox *model.ProgressBoxState, deps *Deps) error {
 	var chapsFilePath string
 	if chapsAvail {
 		dur, getDurErr := GetDurationContext(ctx, vidPathTs, cfg.FfmpegNameStr)
@@ -762,6 +734,7 @@ func convertAndUploadVideo(ctx context.Context, vidPathTs, vidPath, artistFolder
 	if err := os.Remove(vidPathTs); err != nil {
 		ui.PrintError("Failed to delete TS")
 	}
+	recordVideoHistory(cfg, meta, vidPath, format, progressBox, deps)
 	if cfg.RcloneEnabled {
 		if progressBox != nil {
 			if err := progressBox.SetPhase(model.PhaseUpload); err != nil {
@@ -772,15 +745,16 @@ func convertAndUploadVideo(ctx context.Context, vidPathTs, vidPath, artistFolder
 				deps.RenderProgressBox(progressBox)
 			}
 		}
-		if err := deps.UploadPath(ctx, vidPath, artistFolder, cfg, progressBox, true); err != nil {
+		if err := deps.UploadPath(ctx, vidPath, remoteFolder, cfg, progressBox, true); err != nil {
 			return fmt.Errorf("upload video: %w", err)
 		}
+		deps.publishUploadDone(ctx, meta, vidPath, remoteFolder, cfg, true)
 	}
 	return nil
 }
 
 // Video downloads a video from Nugs.net using the provided videoID.
-func Video(ctx context.Context, videoID, uguID string, cfg *model.Config, streamParams *model.StreamParams, _meta *model.AlbArtResp, isLstream bool, progressBox *model.ProgressBoxState, deps *Deps) error {
+func Video(ctx context.Context, videoID, uguID string, cfg *model.Config, streamParams *model.StreamParams, _meta *model.AlbArtResp, isLstream bool, progressBox *model.ProgressBoxState, deps *Deps) (err error) {
 	meta, skuID, err := resolveVideoMeta(ctx, videoID, _meta, isLstream)
 	if err != nil {
 		return err
@@ -796,21 +770,28 @@ func Video(ctx context.Context, videoID, uguID string, cfg *model.Config, stream
 	}
 
 	chapsAvail := !cfg.SkipChapters && len(meta.VideoChapters) > 0
-	videoFname := helpers.BuildAlbumFolderName(meta.ArtistName, meta.ContainerInfo, model.VideoNameMaxRunes)
-	fmt.Println(videoFname)
-	if len([]rune(meta.ArtistName+" - "+strings.TrimRight(meta.ContainerInfo, " "))) > model.VideoNameMaxRunes {
+	// The file is named after the templated show folder it is saved in.
+	segments := helpers.ShowPathSegments(cfg, meta)
+	videoFname := segments[len(segments)-1]
+	if runes := []rune(videoFname); len(runes) > model.VideoNameMaxRunes {
+		videoFname = strings.TrimSpace(string(runes[:model.VideoNameMaxRunes]))
 		fmt.Printf("Video filename was chopped because it exceeds %d characters.\n", model.VideoNameMaxRunes)
 	}
+	fmt.Println(videoFname)
 
 	manifestUrl, variant, retRes, err := resolveManifestAndVariant(ctx, meta, videoID, uguID, skuID, streamParams, cfg)
 	if err != nil {
 		return err
 	}
 
-	artistFolder, vidPathTs, vidPath, skipped, err := prepareVideoPathsAndCheck(ctx, meta, videoFname, retRes, cfg, deps)
+	remoteFolder, vidPathTs, vidPath, skipped, err := prepareVideoPathsAndCheck(ctx, meta, videoFname, retRes, cfg, deps)
 	if err != nil || skipped {
 		return err
 	}
+	deps.publish(ctx, showEvent(model.EventShowStarted, meta, model.MediaTypeVideo, vidPath))
+	defer func() {
+		deps.publishShowDone(ctx, meta, model.MediaTypeVideo, vidPath, err)
+	}()
 
 	manBaseUrl, query, err := GetManifestBase(manifestUrl)
 	if err != nil {
@@ -854,7 +835,7 @@ func Video(ctx context.Context, videoID, uguID string, cfg *model.Config, stream
 		return err
 	}
 
-	if err := convertAndUploadVideo(ctx, vidPathTs, vidPath, artistFolder, meta, cfg, chapsAvail, progressBox, deps); err != nil {
+	if err := convertAndUploadVideo(ctx, vidPathTs, vidPath, remoteFolder, retRes, meta, cfg, chapsAvail, progressBox, deps); err != nil {
 		return err
 	}
 
@@ -0,0 +1,51 @@
+package download
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jmagar/nugs-cli/internal/helpers"
+	"github.com/jmagar/nugs-cli/internal/model"
+)
+
+func TestPrepareVideoPathsFollowsFolderTemplate(t *testing.T) {
+	cfg := &model.Config{OutPath: t.TempDir(), VideoOutPath: t.TempDir(), FolderTemplate: "{artist}/{year}/{date} {venue}"}
+	meta := &model.AlbArtResp{ArtistName: "Goose", ContainerInfo: "Red Rocks", PerformanceDate: "07/04/2024", VenueName: "Red Rocks"}
+
+	remoteFolder, tsPath, vidPath, skipped, err := prepareVideoPathsAndCheck(context.Background(), meta, "2024-07-04 Red Rocks", "1080p", cfg, &Deps{})
+	if err != nil || skipped {
+		t.Fatalf("prepareVideoPathsAndCheck = skipped %v, %v", skipped, err)
+	}
+	showPath := helpers.NewConfigPathResolver(cfg).LocalShowPath(meta, model.MediaTypeVideo)
+	if want := filepath.Join(showPath, "2024-07-04 Red Rocks_1080p.mp4"); vidPath != want {
+		t.Fatalf("video path = %q, want %q", vidPath, want)
+	}
+	if tsPath != filepath.Join(showPath, "2024-07-04 Red Rocks_1080p.ts") {
+		t.Errorf("ts path = %q", tsPath)
+	}
+	if want := "Goose/" + helpers.ShowRelativePath(cfg, meta); remoteFolder != want {
+		t.Errorf("remote folder = %q, want %q", remoteFolder, want)
+	}
+	// Gap detection looks for the templated show folder.
+	if info, err := os.Stat(showPath); err != nil || !info.IsDir() {
+		t.Fatalf("show folder %q not created: %v", showPath, err)
+	}
+}
+
+func TestPrepareVideoPathsFindsLegacyFlatVideo(t *testing.T) {
+	cfg := &model.Config{OutPath: t.TempDir()}
+	meta := &model.AlbArtResp{ArtistName: "Goose", ContainerInfo: "Red Rocks"}
+	legacy := filepath.Join(cfg.OutPath, "Goose", "Goose - Red Rocks_1080p.mp4")
+	if err := os.MkdirAll(filepath.Dir(legacy), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(legacy, []byte("video"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, _, skipped, err := prepareVideoPathsAndCheck(context.Background(), meta, "Goose - Red Rocks", "1080p", cfg, &Deps{}); err != nil || !skipped {
+		t.Fatalf("prepareVideoPathsAndCheck = skipped %v, %v; want the flat video found", skipped, err)
+	}
+}
@@ -0,0 +1,4 @@
+// Package events delivers download lifecycle events (show started, track
+// done, show done, upload done, verify failed, gap-fill summary) to shell
+// hooks, a JSON-lines event log and the configured notifiers.
+package events
@@ -0,0 +1,295 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jmagar/nugs-cli/internal/model"
+	"github.com/jmagar/nugs-cli/internal/notify"
+	"github.com/jmagar/nugs-cli/internal/ui"
+)
+
+// AnyEvent matches every event type in a hook's "event" field.
+const AnyEvent = "*"
+
+const (
+	// queueSize is how many events may wait for hooks, the event log and
+	// notifiers; further progress events are dropped until the queue drains.
+	queueSize = 64
+	// terminalWait bounds how long Publish waits for queue space for a
+	// terminal event before dropping it.
+	terminalWait = 30 * time.Second
+	// closeTimeout bounds how long Close waits for queued events.
+	closeTimeout = 30 * time.Second
+)
+
+// Bus fans each published event out to hooks, the event log and notifiers.
+// A nil *Bus is valid and drops every event.
+type Bus struct {
+	hooks        []model.HookConfig
+	logPath      string
+	notifier     notify.Notifier
+	notifyEvents map[string]bool
+	subscribers  []func(model.Event)
+
+	logMu sync.Mutex
+	now   func() time.Time
+
+	// queue feeds the delivery goroutine, started by the first Publish.
+	queueMu sync.Mutex
+	queue   chan queuedEvent
+	done    chan struct{}
+	closed  bool
+}
+
+// queuedEvent is an event waiting for delivery with its publisher's context.
+type queuedEvent struct {
+	ctx context.Context
+	e   model.Event
+}
+
+// New builds the bus described by cfg. It returns nil when no hooks, event
+// log or notified events are configured.
+func New(cfg *model.Config) (*Bus, error) {
+	if err := Validate(cfg); err != nil {
+		return nil, err
+	}
+	b := &Bus{hooks: cfg.Hooks, logPath: cfg.EventLog, now: time.Now}
+	if len(cfg.NotifyEvents) > 0 {
+		m, err := notify.FromConfig(cfg)
+		if err != nil {
+			return nil, err
+		}
+		if m != nil {
+			b.notifier = m
+			b.notifyEvents = make(map[string]bool, len(cfg.NotifyEvents))
+			for _, t := range cfg.NotifyEvents {
+				b.notifyEvents[t] = true
+			}
+		}
+	}
+	if len(b.hooks) == 0 && b.logPath == "" && b.notifier == nil {
+		return nil, nil
+	}
+	return b, nil
+}
+
+// Subscribe adds fn as an in-process sink that receives every event, and
+// returns the bus. On a nil bus it returns a new one with fn as its only sink.
+// Subscribe before publishing starts.
+func (b *Bus) Subscribe(fn func(model.Event)) *Bus {
+	if b == nil {
+		b = &Bus{now: time.Now}
+	}
+	b.subscribers = append(b.subscribers, fn)
+	return b
+}
+
+// Validate checks the hooks, eventLog and notifyEvents settings.
+func Validate(cfg *model.Config) error {
+	for i, hook := range cfg.Hooks {
+		if hook.Event != AnyEvent && !slices.Contains(model.EventTypes, hook.Event) {
+			return fmt.Errorf("hook %d: unknown event %q (want one of %s or %q)",
+				i+1, hook.Event, strings.Join(model.EventTypes, ", "), AnyEvent)
+		}
+		if strings.TrimSpace(hook.Command) == "" {
+			return fmt.Errorf("hook %d: command is required", i+1)
+		}
+	}
+	for _, t := range cfg.NotifyEvents {
+		if !slices.Contains(model.EventTypes, t) {
+			return fmt.Errorf("notifyEvents: unknown event %q (want one of %s)", t, strings.Join(model.EventTypes, ", "))
+		}
+	}
+	if cfg.EventLog != "" && !filepath.IsAbs(cfg.EventLog) {
+		return fmt.Errorf("eventLog must be an absolute path, got %q", cfg.EventLog)
+	}
+	return nil
+}
+
+// Publish stamps e with the current time and hands it to every sink.
+// Subscribers are called in order before Publish returns. Hooks, the event
+// log and notifiers run on a background goroutine so a slow script or
+// endpoint never holds up a track worker. When the queue is full a progress
+// event is dropped for them with a warning, while a terminal event (see
+// isTerminal) waits up to terminalWait for space. Delivery failures are
+// printed as warnings and never fail the download.
+func (b *Bus) Publish(ctx context.Context, e model.Event) {
+	if b == nil {
+		return
+	}
+	if e.Time.IsZero() {
+		e.Time = b.now()
+	}
+	for _, fn := range b.subscribers {
+		fn(e)
+	}
+	if len(b.hooks) == 0 && b.logPath == "" && b.notifier == nil {
+		return
+	}
+
+	b.queueMu.Lock()
+	defer b.queueMu.Unlock()
+	if b.closed {
+		return
+	}
+	if b.queue == nil {
+		b.queue = make(chan queuedEvent, queueSize)
+		b.done = make(chan struct{})
+		go b.run()
+	}
+	// Delivery outlives a cancelled download so its show.done still
+	// reaches the hooks; each hook has its own timeout.
+	item := queuedEvent{ctx: context.WithoutCancel(ctx), e: e}
+	select {
+	case b.queue <- item:
+		return
+	default:
+	}
+	if isTerminal(e.Type) {
+		timer := time.NewTimer(terminalWait)
+		defer timer.Stop()
+		select {
+		case b.queue <- item:
+			return
+		case <-timer.C:
+		}
+	}
+	ui.PrintWarning(fmt.Sprintf("Event queue full, dropped %s for hooks, event log and notifiers", e.Type))
+}
+
+// isTerminal reports whether t ends a show, an upload or a run. Those events
+// carry the outcome, so Publish waits for queue space rather than drop them.
+func isTerminal(t string) bool {
+	switch t {
+	case model.EventShowDone, model.EventUploadDone, model.EventGapFillSummary:
+		return true
+	}
+	return false
+}
+
+// Close stops accepting events for the background sinks and waits up to
+// closeTimeout for the queued ones to be delivered. Subscribers still
+// receive later events. It is safe on a nil or unused bus.
+func (b *Bus) Close() {
+	if b == nil {
+		return
+	}
+	b.queueMu.Lock()
+	if b.closed || b.queue == nil {
+		b.closed = true
+		b.queueMu.Unlock()
+		return
+	}
+	b.closed = true
+	close(b.queue)
+	b.queueMu.Unlock()
+
+	select {
+	case <-b.done:
+	case <-time.After(closeTimeout):
+		ui.PrintWarning(fmt.Sprintf("Gave up waiting for %d queued event(s) after %s", len(b.queue), closeTimeout))
+	}
+}
+
+// run delivers queued events in order until Close.
+func (b *Bus) run() {
+	defer close(b.done)
+	for item := range b.queue {
+		b.deliver(item.ctx, item.e)
+	}
+}
+
+// deliver sends e to the event log, matching hooks and notifiers.
+func (b *Bus) deliver(ctx context.Context, e model.Event) {
+	if b.logPath != "" {
+		if err := b.appendLog(e); err != nil {
+			ui.PrintWarning(fmt.Sprintf("Event log: %v", err))
+		}
+	}
+	for i, hook := range b.hooks {
+		if hook.Event != AnyEvent && hook.Event != e.Type {
+			continue
+		}
+		if err := runHook(ctx, hook.Command, e); err != nil {
+			ui.PrintWarning(fmt.Sprintf("Hook %d (%s): %v", i+1, e.Type, err))
+		}
+	}
+	if b.notifier != nil && b.notifyEvents[e.Type] {
+		title, message, priority := notification(e)
+		if err := b.notifier.Notify(ctx, title, message, priority); err != nil {
+			ui.PrintWarning(fmt.Sprintf("Event notification: %v", err))
+		}
+	}
+}
+
+// appendLog writes e as one JSON line. Appends are serialised so concurrent
+// track events never interleave.
+func (b *Bus) appendLog(e model.Event) error {
+	line, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("marshal %s: %w", e.Type, err)
+	}
+	b.logMu.Lock()
+	defer b.logMu.Unlock()
+	if err := os.MkdirAll(filepath.Dir(b.logPath), 0700); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(b.logPath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(append(line, '\n'))
+	return errors.Join(err, f.Close())
+}
+
+// notification formats e for the notifiers. Verification failures and gap
+// fills with failed shows use priority 7, like watch errors.
+func notification(e model.Event) (title, message string, priority int) {
+	show := showLabel(e)
+	priority = 5
+	switch e.Type {
+	case model.EventShowStarted:
+		return "Nugs Download Started", show, priority
+	case model.EventTrackDone:
+		return "Nugs Track Done", fmt.Sprintf("%s: %d. %s", show, e.Track, e.TrackTitle), priority
+	case model.EventShowDone:
+		if e.Error != "" {
+			return "Nugs Download Failed", fmt.Sprintf("%s\n%s", show, e.Error), 7
+		}
+		return "Nugs Download Done", show, priority
+	case model.EventUploadDone:
+		return "Nugs Upload Done", fmt.Sprintf("%s\nUploaded to %s", show, e.Remote), priority
+	case model.EventVerifyFailed:
+		return "Nugs Verify Failed", fmt.Sprintf("%s: %d. %s\n%s", show, e.Track, e.TrackTitle, e.Error), 7
+	case model.EventGapFillSummary:
+		message = fmt.Sprintf("%s: %d downloaded, %d failed of %d missing", e.ArtistName, e.Downloaded, e.Failed, e.Missing)
+		if e.Error != "" {
+			message += "\n" + e.Error
+		}
+		if e.Failed > 0 {
+			return "Nugs Gap Fill Error", message, 7
+		}
+		return "Nugs Gap Fill", message, priority
+	}
+	return "Nugs " + e.Type, show, priority
+}
+
+// showLabel names the show as "Artist - Show", or whichever part is known.
+func showLabel(e model.Event) string {
+	switch {
+	case e.ArtistName == "":
+		return e.Show
+	case e.Show == "":
+		return e.ArtistName
+	}
+	return e.ArtistName + " - " + e.Show
+}
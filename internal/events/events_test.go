@@ -0,0 +1,243 @@
+package events
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jmagar/nugs-cli/internal/model"
+)
+
+type recordingNotifier struct {
+	titles     []string
+	priorities []int
+}
+
+func (r *recordingNotifier) Notify(_ context.Context, title, _ string, priority int) error {
+	r.titles = append(r.titles, title)
+	r.priorities = append(r.priorities, priority)
+	return nil
+}
+
+func TestNewReturnsNilWithoutSinks(t *testing.T) {
+	b, err := New(&model.Config{GotifyURL: "https://gotify.example", GotifyToken: "t"})
+	if err != nil || b != nil {
+		t.Fatalf("New = %v, %v; want nil bus", b, err)
+	}
+	// Publishing to a nil bus is a no-op.
+	b.Publish(context.Background(), model.Event{Type: model.EventShowDone})
+}
+
+func TestSubscribe(t *testing.T) {
+	var got []string
+	var b *Bus
+	b = b.Subscribe(func(e model.Event) { got = append(got, e.Type) })
+	b.Publish(context.Background(), model.Event{Type: model.EventShowStarted})
+	b.Publish(context.Background(), model.Event{Type: model.EventShowDone})
+	if strings.Join(got, ",") != "show.started,show.done" {
+		t.Fatalf("subscriber got %v", got)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  model.Config
+		want string
+	}{
+		{name: "unknown hook event", cfg: model.Config{Hooks: []model.HookConfig{{Event: "show.finished", Command: "true"}}}, want: `hook 1: unknown event "show.finished"`},
+		{name: "empty command", cfg: model.Config{Hooks: []model.HookConfig{{Event: "*"}}}, want: "hook 1: command is required"},
+		{name: "unknown notify event", cfg: model.Config{NotifyEvents: []string{"track"}}, want: `notifyEvents: unknown event "track"`},
+		{name: "relative log", cfg: model.Config{EventLog: "events.jsonl"}, want: "eventLog must be an absolute path"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := Validate(&tc.cfg); err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Fatalf("Validate error = %v, want %q", err, tc.want)
+			}
+		})
+	}
+}
+
+func TestPublishAppendsEventLog(t *testing.T) {
+	logPath := filepath.Join(t.TempDir(), "logs", "events.jsonl")
+	b, err := New(&model.Config{EventLog: logPath})
+	if err != nil {
+		t.Fatal(err)
+	}
+	stamp := time.Date(2026, 6, 1, 20, 0, 0, 0, time.UTC)
+	b.now = func() time.Time { return stamp }
+
+	b.Publish(context.Background(), model.Event{Type: model.EventShowStarted, ContainerID: 42, ArtistName: "Goose"})
+	b.Publish(context.Background(), model.Event{Type: model.EventShowDone, ContainerID: 42, Path: "/music/Goose/show"})
+	b.Close()
+
+	f, err := os.Open(logPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	var got []model.Event
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var e model.Event
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			t.Fatalf("line %q is not JSON: %v", scanner.Text(), err)
+		}
+		got = append(got, e)
+	}
+	if len(got) != 2 || got[0].Type != model.EventShowStarted || got[1].Path != "/music/Goose/show" {
+		t.Fatalf("events = %+v", got)
+	}
+	if !got[0].Time.Equal(stamp) {
+		t.Errorf("time = %v, want %v", got[0].Time, stamp)
+	}
+}
+
+func TestPublishRunsMatchingHooks(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hook test uses a POSIX shell")
+	}
+	dir := t.TempDir()
+	out := filepath.Join(dir, "hook.out")
+	b, err := New(&model.Config{Hooks: []model.HookConfig{
+		{Event: model.EventUploadDone, Command: `printf '%s|%s|%s|%s\n' "$NUGS_EVENT" "$NUGS_CONTAINER_ID" "$NUGS_PATH" "$NUGS_REMOTE" >> "` + out + `"`},
+		{Event: AnyEvent, Command: `printf 'any %s\n' "$NUGS_EVENT" >> "` + out + `"`},
+		{Event: model.EventShowDone, Command: "exit 3"},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b.Publish(context.Background(), model.Event{Type: model.EventUploadDone, ContainerID: 42, Path: "/music/Goose show", Remote: "gdrive:Music"})
+	b.Publish(context.Background(), model.Event{Type: model.EventShowDone, ContainerID: 42})
+	b.Close()
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "upload.done|42|/music/Goose show|gdrive:Music\nany upload.done\nany show.done\n"
+	if string(data) != want {
+		t.Fatalf("hook output = %q, want %q", data, want)
+	}
+}
+
+func TestPublishDoesNotWaitForStuckHooks(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hook test uses a POSIX shell")
+	}
+	dir := t.TempDir()
+	release, out := filepath.Join(dir, "release"), filepath.Join(dir, "hook.out")
+	b, err := New(&model.Config{Hooks: []model.HookConfig{
+		{Event: AnyEvent, Command: `while [ ! -f "` + release + `" ]; do sleep 0.01; done; echo "$NUGS_TRACK" >> "` + out + `"`},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now()
+	for i := range queueSize + 10 {
+		b.Publish(context.Background(), model.Event{Type: model.EventTrackDone, Track: i + 1})
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("Publish blocked for %s behind a stuck hook", elapsed)
+	}
+	if err := os.WriteFile(release, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	b.Close()
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Fields(string(data))
+	// The hook holding the first event frees one queue slot at most.
+	if len(lines) < queueSize || len(lines) > queueSize+1 || lines[0] != "1" {
+		t.Fatalf("hook ran for events %v, want the first %d or %d in order", lines, queueSize, queueSize+1)
+	}
+}
+
+func TestPublishWaitsForSpaceForTerminalEvents(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hook test uses a POSIX shell")
+	}
+	dir := t.TempDir()
+	started, release, out := filepath.Join(dir, "started"), filepath.Join(dir, "release"), filepath.Join(dir, "hook.out")
+	b, err := New(&model.Config{Hooks: []model.HookConfig{
+		{Event: AnyEvent, Command: `touch "` + started + `"; while [ ! -f "` + release + `" ]; do sleep 0.01; done; echo "$NUGS_EVENT" >> "` + out + `"`},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Once the hook holds the first event, the rest fill the queue.
+	b.Publish(context.Background(), model.Event{Type: model.EventTrackDone, Track: 1})
+	for deadline := time.Now().Add(5 * time.Second); ; time.Sleep(10 * time.Millisecond) {
+		if _, err := os.Stat(started); err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("hook did not start")
+		}
+	}
+	for i := range queueSize {
+		b.Publish(context.Background(), model.Event{Type: model.EventTrackDone, Track: i + 2})
+	}
+	time.AfterFunc(200*time.Millisecond, func() { _ = os.WriteFile(release, nil, 0o644) })
+	start := time.Now()
+	b.Publish(context.Background(), model.Event{Type: model.EventShowDone, ContainerID: 42})
+	if elapsed := time.Since(start); elapsed < 100*time.Millisecond {
+		t.Fatalf("Publish returned after %s; want it to wait for queue space", elapsed)
+	}
+	b.Close()
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Fields(string(data))
+	if len(lines) == 0 || lines[len(lines)-1] != model.EventShowDone {
+		t.Fatalf("hook ran for %v, want show.done delivered last", lines)
+	}
+}
+
+func TestHookEnv(t *testing.T) {
+	env, err := hookEnv(model.Event{Type: model.EventGapFillSummary, ArtistID: 1125, ArtistName: "Billy Strings", Downloaded: 2, Missing: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	joined := strings.Join(env, "\n")
+	for _, want := range []string{"NUGS_EVENT=gapfill.summary", "NUGS_ARTIST_ID=1125", "NUGS_DOWNLOADED=2", "NUGS_FAILED=0", "NUGS_MISSING=3", `"artistName":"Billy Strings"`} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("env missing %q:\n%s", want, joined)
+		}
+	}
+	if strings.Contains(joined, "NUGS_PATH=") {
+		t.Errorf("env has an empty NUGS_PATH:\n%s", joined)
+	}
+}
+
+func TestPublishNotifiesSelectedEvents(t *testing.T) {
+	n := &recordingNotifier{}
+	b := &Bus{notifier: n, notifyEvents: map[string]bool{model.EventVerifyFailed: true, model.EventGapFillSummary: true}, now: time.Now}
+
+	b.Publish(context.Background(), model.Event{Type: model.EventTrackDone, Track: 1})
+	b.Publish(context.Background(), model.Event{Type: model.EventVerifyFailed, Track: 3, Error: "bad checksum"})
+	b.Publish(context.Background(), model.Event{Type: model.EventGapFillSummary, Downloaded: 4, Missing: 4})
+	b.Close()
+
+	if strings.Join(n.titles, ",") != "Nugs Verify Failed,Nugs Gap Fill" {
+		t.Fatalf("titles = %v", n.titles)
+	}
+	if n.priorities[0] != 7 || n.priorities[1] != 5 {
+		t.Errorf("priorities = %v, want [7 5]", n.priorities)
+	}
+}
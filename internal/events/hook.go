@@ -0,0 +1,103 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jmagar/nugs-cli/internal/model"
+)
+
+const (
+	// hookTimeout bounds one hook so a stuck script cannot hold up the
+	// events queued behind it for long.
+	hookTimeout = time.Minute
+	// maxHookOutput is how much of a failing hook's output is reported.
+	maxHookOutput = 2048
+)
+
+// runHook runs command through the shell with the event in its environment.
+// Its output is only shown when it fails, to keep progress rendering intact.
+func runHook(ctx context.Context, command string, e model.Event) error {
+	ctx, cancel := context.WithTimeout(ctx, hookTimeout)
+	defer cancel()
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", command)
+	}
+	env, err := hookEnv(e)
+	if err != nil {
+		return err
+	}
+	cmd.Env = append(os.Environ(), env...)
+	var output bytes.Buffer
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+
+	if err := cmd.Run(); err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			err = fmt.Errorf("timed out after %s", hookTimeout)
+		}
+		if out := strings.TrimSpace(output.String()); out != "" {
+			if len(out) > maxHookOutput {
+				out = out[len(out)-maxHookOutput:]
+			}
+			return fmt.Errorf("%w: %s", err, out)
+		}
+		return err
+	}
+	return nil
+}
+
+// hookEnv returns the NUGS_* variables for e. Zero-valued fields are left
+// out; NUGS_EVENT_JSON always carries the whole event.
+func hookEnv(e model.Event) ([]string, error) {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return nil, fmt.Errorf("marshal %s: %w", e.Type, err)
+	}
+	env := []string{"NUGS_EVENT=" + e.Type, "NUGS_EVENT_JSON=" + string(data)}
+	add := func(key, value string) {
+		if value != "" {
+			env = append(env, key+"="+value)
+		}
+	}
+	addInt := func(key string, value int) {
+		if value != 0 {
+			add(key, strconv.Itoa(value))
+		}
+	}
+	addInt("NUGS_CONTAINER_ID", e.ContainerID)
+	addInt("NUGS_ARTIST_ID", e.ArtistID)
+	add("NUGS_ARTIST_NAME", e.ArtistName)
+	add("NUGS_SHOW", e.Show)
+	add("NUGS_DATE", e.Date)
+	add("NUGS_MEDIA", e.Media)
+	add("NUGS_PATH", e.Path)
+	addInt("NUGS_TRACK", e.Track)
+	add("NUGS_TRACK_TITLE", e.TrackTitle)
+	add("NUGS_REMOTE", e.Remote)
+	add("NUGS_ERROR", e.Error)
+	add("NUGS_ERROR_CLASS", e.ErrorClass)
+	if e.Type == model.EventGapFillSummary {
+		// Counts are meaningful even when zero.
+		env = append(env,
+			"NUGS_DOWNLOADED="+strconv.Itoa(e.Downloaded),
+			"NUGS_FAILED="+strconv.Itoa(e.Failed),
+			"NUGS_MISSING="+strconv.Itoa(e.Missing),
+			"NUGS_SKIPPED="+strconv.Itoa(e.Skipped),
+		)
+	}
+	return env, nil
+}
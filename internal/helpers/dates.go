@@ -0,0 +1,68 @@
+package helpers
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jmagar/nugs-cli/internal/model"
+)
+
+// performanceDateLayouts lists the date formats seen in Nugs API responses,
+// most specific first.
+var performanceDateLayouts = []string{
+	"2006-01-02",
+	"1/2/2006",
+	"2006/01/02",
+	"06/01/02", // performanceDateShortYearFirst
+}
+
+// ParsePerformanceDate parses a performance date in any known API layout.
+func ParsePerformanceDate(s string) (time.Time, bool) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return time.Time{}, false
+	}
+	for _, layout := range performanceDateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// ShowDate returns the show's performance date as YYYY-MM-DD when any of the
+// date fields parse, otherwise the first non-empty raw value.
+func ShowDate(show *model.AlbArtResp) string {
+	if show == nil {
+		return ""
+	}
+	candidates := []string{show.PerformanceDate, show.PerformanceDateShortYearFirst, show.PerformanceDateFormatted}
+	for _, candidate := range candidates {
+		if t, ok := ParsePerformanceDate(candidate); ok {
+			return t.Format("2006-01-02")
+		}
+	}
+	for _, candidate := range candidates {
+		if trimmed := strings.TrimSpace(candidate); trimmed != "" {
+			return trimmed
+		}
+	}
+	return ""
+}
+
+// DateBounds returns the first and last day, as YYYY-MM-DD, covered by a
+// YYYY, YYYY-MM or YYYY-MM-DD value.
+func DateBounds(s string) (from, to string, err error) {
+	s = strings.TrimSpace(s)
+	if t, err := time.Parse("2006-01-02", s); err == nil {
+		return t.Format("2006-01-02"), t.Format("2006-01-02"), nil
+	}
+	if t, err := time.Parse("2006-01", s); err == nil {
+		return t.Format("2006-01-02"), t.AddDate(0, 1, -1).Format("2006-01-02"), nil
+	}
+	if t, err := time.Parse("2006", s); err == nil {
+		return t.Format("2006-01-02"), t.AddDate(1, 0, -1).Format("2006-01-02"), nil
+	}
+	return "", "", fmt.Errorf("invalid date %q: use YYYY, YYYY-MM or YYYY-MM-DD", s)
+}
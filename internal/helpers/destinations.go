@@ -0,0 +1,89 @@
+package helpers
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/jmagar/nugs-cli/internal/model"
+)
+
+// DefaultDestinationName names the implicit destination formed by the
+// top-level storage settings when uploadDestinations is empty.
+const DefaultDestinationName = "default"
+
+// UploadDestinations returns the configured upload destinations, or the
+// top-level storage settings as a single destination.
+func UploadDestinations(cfg *model.Config) []model.UploadDestination {
+	if cfg == nil || len(cfg.UploadDestinations) == 0 {
+		return []model.UploadDestination{{Name: DefaultDestinationName}}
+	}
+	return cfg.UploadDestinations
+}
+
+// DestinationMedia returns the media a destination accepts.
+func DestinationMedia(dest model.UploadDestination) model.MediaType {
+	if strings.TrimSpace(dest.Media) == "" {
+		return model.MediaTypeBoth
+	}
+	return model.ParseMediaType(strings.TrimSpace(dest.Media))
+}
+
+// DestinationsForMedia returns the destinations that accept audio or video.
+func DestinationsForMedia(cfg *model.Config, isVideo bool) []model.UploadDestination {
+	var dests []model.UploadDestination
+	for _, dest := range UploadDestinations(cfg) {
+		media := DestinationMedia(dest)
+		if (isVideo && media.HasVideo()) || (!isVideo && media.HasAudio()) {
+			dests = append(dests, dest)
+		}
+	}
+	return dests
+}
+
+// DestinationConfig returns a copy of cfg with dest's storage settings in
+// place of the top-level ones, so single-destination code can act on it.
+// The copy has no uploadDestinations of its own.
+func DestinationConfig(cfg *model.Config, dest model.UploadDestination) *model.Config {
+	out := *cfg
+	out.UploadDestinations = nil
+	override := func(field *string, value string) {
+		if value != "" {
+			*field = value
+		}
+	}
+	override(&out.StorageBackend, dest.StorageBackend)
+	override(&out.RcloneRemote, dest.RcloneRemote)
+	if dest.RclonePath != "" {
+		out.RclonePath = dest.RclonePath
+		// A destination with its own audio path must not inherit the
+		// top-level video path.
+		out.RcloneVideoPath = dest.RclonePath
+	}
+	override(&out.RcloneVideoPath, dest.RcloneVideoPath)
+	override(&out.SftpHost, dest.SftpHost)
+	override(&out.SftpIdentityFile, dest.SftpIdentityFile)
+	override(&out.S3Endpoint, dest.S3Endpoint)
+	override(&out.S3Region, dest.S3Region)
+	override(&out.S3Bucket, dest.S3Bucket)
+	override(&out.S3AccessKey, dest.S3AccessKey)
+	override(&out.S3SecretKey, dest.S3SecretKey)
+	if dest.SftpPort > 0 {
+		out.SftpPort = dest.SftpPort
+	}
+	if dest.Transfers > 0 {
+		out.RcloneTransfers = dest.Transfers
+	}
+	return &out
+}
+
+// StorageBackends returns the distinct backends used by the destinations.
+func StorageBackends(cfg *model.Config) []string {
+	var backends []string
+	for _, dest := range UploadDestinations(cfg) {
+		backend := StorageBackend(DestinationConfig(cfg, dest))
+		if !slices.Contains(backends, backend) {
+			backends = append(backends, backend)
+		}
+	}
+	return backends
+}
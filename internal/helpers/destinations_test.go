@@ -0,0 +1,58 @@
+package helpers
+
+import (
+	"testing"
+
+	"github.com/jmagar/nugs-cli/internal/model"
+)
+
+func TestDestinationConfigOverridesAndInherits(t *testing.T) {
+	cfg := &model.Config{
+		RcloneEnabled:     true,
+		RcloneRemote:      "gdrive",
+		RclonePath:        "/Music",
+		RcloneVideoPath:   "/Video",
+		RcloneTransfers:   8,
+		DeleteAfterUpload: true,
+		S3AccessKey:       "shared",
+		UploadDestinations: []model.UploadDestination{
+			{Name: "nas", StorageBackend: "local", RclonePath: "/mnt/nas/music"},
+			{Name: "cold", StorageBackend: "s3", S3Bucket: "archive", Transfers: 2, Media: "audio"},
+			{Name: "tube", RcloneRemote: "video", RcloneVideoPath: "/Concerts", Media: "video"},
+		},
+	}
+
+	nas := DestinationConfig(cfg, cfg.UploadDestinations[0])
+	if nas.StorageBackend != "local" || nas.RclonePath != "/mnt/nas/music" || GetRcloneBasePath(nas, true) != "/mnt/nas/music" {
+		t.Fatalf("nas config = backend %q audio %q video %q", nas.StorageBackend, nas.RclonePath, GetRcloneBasePath(nas, true))
+	}
+	if nas.UploadDestinations != nil || !nas.DeleteAfterUpload || nas.RcloneTransfers != 8 {
+		t.Fatalf("nas config did not inherit top-level settings: %+v", nas)
+	}
+	if len(cfg.UploadDestinations) != 3 || cfg.RclonePath != "/Music" {
+		t.Fatal("DestinationConfig modified the original config")
+	}
+
+	cold := DestinationConfig(cfg, cfg.UploadDestinations[1])
+	if cold.S3Bucket != "archive" || cold.S3AccessKey != "shared" || cold.RcloneTransfers != 2 || cold.RclonePath != "/Music" {
+		t.Fatalf("cold config = %+v", cold)
+	}
+
+	tube := DestinationConfig(cfg, cfg.UploadDestinations[2])
+	if tube.RcloneRemote != "video" || GetRcloneBasePath(tube, true) != "/Concerts" {
+		t.Fatalf("tube config = remote %q video %q", tube.RcloneRemote, GetRcloneBasePath(tube, true))
+	}
+
+	if got := DestinationsForMedia(cfg, true); len(got) != 2 || got[0].Name != "nas" || got[1].Name != "tube" {
+		t.Fatalf("video destinations = %+v", got)
+	}
+	if got := DestinationsForMedia(cfg, false); len(got) != 2 || got[1].Name != "cold" {
+		t.Fatalf("audio destinations = %+v", got)
+	}
+	if got := StorageBackends(cfg); len(got) != 3 {
+		t.Fatalf("StorageBackends = %v, want local, s3, rclone", got)
+	}
+	if got := UploadDestinations(&model.Config{}); len(got) != 1 || got[0].Name != DefaultDestinationName {
+		t.Fatalf("implicit destination = %+v", got)
+	}
+}
@@ -128,3 +128,51 @@ func TestConfigPathResolver_ShowPaths(t *testing.T) {
 		t.Fatalf("unexpected remote path %q", remotePath)
 	}
 }
+
+func TestShowDate_NormalizesKnownLayouts(t *testing.T) {
+	tests := []struct {
+		name string
+		show *model.AlbArtResp
+		want string
+	}{
+		{"iso", &model.AlbArtResp{PerformanceDate: "2024-03-02"}, "2024-03-02"},
+		{"us slashes", &model.AlbArtResp{PerformanceDate: "3/2/2024"}, "2024-03-02"},
+		{"short year first", &model.AlbArtResp{PerformanceDateShortYearFirst: "24/03/02"}, "2024-03-02"},
+		{"unparseable falls back to raw", &model.AlbArtResp{PerformanceDate: "Spring 1977"}, "Spring 1977"},
+		{"nil show", nil, ""},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := ShowDate(tc.show); got != tc.want {
+				t.Fatalf("ShowDate() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestDateBounds(t *testing.T) {
+	tests := []struct{ in, from, to string }{
+		{"2024", "2024-01-01", "2024-12-31"},
+		{"2024-02", "2024-02-01", "2024-02-29"},
+		{"2024-06-10", "2024-06-10", "2024-06-10"},
+	}
+	for _, tc := range tests {
+		from, to, err := DateBounds(tc.in)
+		if err != nil || from != tc.from || to != tc.to {
+			t.Errorf("DateBounds(%q) = %q, %q, %v; want %q, %q", tc.in, from, to, err, tc.from, tc.to)
+		}
+	}
+	if _, _, err := DateBounds("6/10/2024"); err == nil {
+		t.Error("DateBounds(6/10/2024) succeeded, want error")
+	}
+}
+
+func TestIsArtistID(t *testing.T) {
+	for s, want := range map[string]bool{
+		"1125": true, "0461": true, "": false, "0": false, "-5": false, "+12": false, "12a": false, " 12": false,
+	} {
+		if got := IsArtistID(s); got != want {
+			t.Errorf("IsArtistID(%q) = %v, want %v", s, got, want)
+		}
+	}
+}
@@ -0,0 +1,272 @@
+package helpers
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/jmagar/nugs-cli/internal/model"
+)
+
+// Default naming templates reproduce the historical "Artist/Artist - Show"
+// tree and "01. Title" track names.
+const (
+	DefaultFolderTemplate = "{artist}/{artist} - {container}"
+	DefaultTrackTemplate  = "{track:02}. {title}"
+)
+
+var (
+	// ErrInvalidNamingTemplate indicates a folder or track template cannot be rendered.
+	ErrInvalidNamingTemplate = errors.New("invalid naming template")
+
+	showPlaceholders = map[string]bool{
+		"artist": false, "artistId": true, "container": false, "containerId": true,
+		"date": false, "year": false, "month": false, "day": false,
+		"venue": false, "city": false, "state": false,
+	}
+	trackPlaceholders = map[string]bool{
+		"track": true, "trackTotal": true, "disc": true, "title": false, "trackId": true,
+	}
+)
+
+// TrackNameInfo carries the per-track values available to the track template.
+type TrackNameInfo struct {
+	Number int
+	Total  int
+	Disc   int
+	Title  string
+	ID     int
+}
+
+// templateToken is one parsed {name} or {name:0N} placeholder.
+type templateToken struct {
+	name  string
+	width int
+}
+
+// parseTemplate splits tpl into literal text and placeholders, calling emit for
+// each literal (token == nil) or placeholder.
+func parseTemplate(tpl string, emit func(literal string, token *templateToken) error) error {
+	for tpl != "" {
+		open := strings.IndexByte(tpl, '{')
+		if open < 0 {
+			return emit(tpl, nil)
+		}
+		if open > 0 {
+			if err := emit(tpl[:open], nil); err != nil {
+				return err
+			}
+		}
+		closeIdx := strings.IndexByte(tpl[open:], '}')
+		if closeIdx < 0 {
+			return fmt.Errorf("%w: unterminated placeholder in %q", ErrInvalidNamingTemplate, tpl)
+		}
+		spec := tpl[open+1 : open+closeIdx]
+		tpl = tpl[open+closeIdx+1:]
+		token := &templateToken{name: spec}
+		if name, width, ok := strings.Cut(spec, ":"); ok {
+			n, err := strconv.Atoi(width)
+			if err != nil || n < 1 || n > 9 || !strings.HasPrefix(width, "0") {
+				return fmt.Errorf("%w: bad width in {%s}", ErrInvalidNamingTemplate, spec)
+			}
+			token.name, token.width = name, n
+		}
+		if err := emit("", token); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateTemplate checks that tpl only uses placeholders from allowed.
+func validateTemplate(tpl string, allowed ...map[string]bool) error {
+	return parseTemplate(tpl, func(_ string, token *templateToken) error {
+		if token == nil {
+			return nil
+		}
+		for _, set := range allowed {
+			if numeric, ok := set[token.name]; ok {
+				if token.width > 0 && !numeric {
+					return fmt.Errorf("%w: {%s} is not numeric and cannot be padded", ErrInvalidNamingTemplate, token.name)
+				}
+				return nil
+			}
+		}
+		return fmt.Errorf("%w: unknown placeholder {%s}", ErrInvalidNamingTemplate, token.name)
+	})
+}
+
+// ValidateNamingTemplates checks folder and track templates. The folder
+// template needs at least an artist segment and a show segment, and its first
+// segment may only use {artist} so gap detection can find an artist's shows.
+func ValidateNamingTemplates(folderTpl, trackTpl string) error {
+	if folderTpl != "" {
+		segments := strings.Split(folderTpl, "/")
+		if len(segments) < 2 {
+			return fmt.Errorf("%w: folderTemplate %q needs an artist folder and a show folder", ErrInvalidNamingTemplate, folderTpl)
+		}
+		for _, segment := range segments {
+			if trimmed := strings.TrimSpace(segment); trimmed == "" || trimmed == "." || trimmed == ".." {
+				return fmt.Errorf("%w: folderTemplate %q has an empty or relative path segment", ErrInvalidNamingTemplate, folderTpl)
+			}
+		}
+		if err := validateTemplate(segments[0], map[string]bool{"artist": false}); err != nil {
+			return fmt.Errorf("folderTemplate artist folder: %w", err)
+		}
+		if err := validateTemplate(folderTpl, showPlaceholders); err != nil {
+			return fmt.Errorf("folderTemplate: %w", err)
+		}
+	}
+	if trackTpl != "" {
+		if strings.Contains(trackTpl, "/") {
+			return fmt.Errorf("%w: trackTemplate %q must not contain '/'", ErrInvalidNamingTemplate, trackTpl)
+		}
+		if err := validateTemplate(trackTpl, showPlaceholders, trackPlaceholders); err != nil {
+			return fmt.Errorf("trackTemplate: %w", err)
+		}
+	}
+	return nil
+}
+
+// showTemplateValues returns placeholder values for a show.
+func showTemplateValues(show *model.AlbArtResp) map[string]any {
+	if show == nil {
+		return map[string]any{}
+	}
+	values := map[string]any{
+		"artist":      show.ArtistName,
+		"artistId":    show.ArtistID,
+		"container":   strings.TrimRight(show.ContainerInfo, " "),
+		"containerId": show.ContainerID,
+		"date":        ShowDate(show),
+		"year":        strings.TrimSpace(show.PerformanceDateYear),
+		"venue":       strings.TrimSpace(show.VenueName),
+		"city":        strings.TrimSpace(show.VenueCity),
+		"state":       strings.TrimSpace(show.VenueState),
+	}
+	if values["venue"] == "" {
+		values["venue"] = strings.TrimSpace(show.Venue)
+	}
+	for _, candidate := range []string{show.PerformanceDate, show.PerformanceDateShortYearFirst, show.PerformanceDateFormatted} {
+		if t, ok := ParsePerformanceDate(candidate); ok {
+			values["year"] = t.Format("2006")
+			values["month"] = t.Format("01")
+			values["day"] = t.Format("02")
+			break
+		}
+	}
+	return values
+}
+
+// templateSeparators are dropped next to a placeholder that renders empty.
+const templateSeparators = " -_.,"
+
+// renderTemplate substitutes values into tpl. Numbers of zero are rendered
+// empty unless padded. Separators after an empty placeholder, or before one
+// that ends the template, are dropped: "{disc}-{track:02}" renders "05" for a
+// single-set show and "{date} {venue}" renders just the date without a venue.
+func renderTemplate(tpl string, values map[string]any) string {
+	var b strings.Builder
+	// literalAt is where the text written since the last non-empty value
+	// starts; only that text is trimmed after a final empty placeholder.
+	literalAt, lastEmpty := 0, false
+	_ = parseTemplate(tpl, func(literal string, token *templateToken) error {
+		if token == nil {
+			if lastEmpty {
+				literal = strings.TrimLeft(literal, templateSeparators)
+			}
+			if literal != "" {
+				lastEmpty = false
+			}
+			b.WriteString(literal)
+			return nil
+		}
+		before := b.Len()
+		switch v := values[token.name].(type) {
+		case string:
+			b.WriteString(v)
+		case int:
+			if token.width > 0 {
+				fmt.Fprintf(&b, "%0*d", token.width, v)
+			} else if v != 0 {
+				b.WriteString(strconv.Itoa(v))
+			}
+		}
+		if lastEmpty = b.Len() == before; !lastEmpty {
+			literalAt = b.Len()
+		}
+		return nil
+	})
+	out := b.String()
+	if lastEmpty {
+		out = out[:literalAt] + strings.TrimRight(out[literalAt:], templateSeparators)
+	}
+	return out
+}
+
+// renderName renders one path component, truncating to limit runes before
+// sanitising, matching BuildAlbumFolderName.
+func renderName(tpl string, values map[string]any, limit int) string {
+	name := renderTemplate(tpl, values)
+	if runes := []rune(name); limit > 0 && len(runes) > limit {
+		name = string(runes[:limit])
+	}
+	return Sanitise(name)
+}
+
+// folderTemplate returns the configured folder template or the default.
+func folderTemplate(cfg *model.Config) string {
+	if cfg != nil && strings.TrimSpace(cfg.FolderTemplate) != "" {
+		return cfg.FolderTemplate
+	}
+	return DefaultFolderTemplate
+}
+
+// ShowPathSegments renders the folder template for show into sanitised path
+// components. The first component is the artist folder and the last is the
+// show folder that receives the tracks.
+func ShowPathSegments(cfg *model.Config, show *model.AlbArtResp) []string {
+	values := showTemplateValues(show)
+	parts := strings.Split(folderTemplate(cfg), "/")
+	segments := make([]string, len(parts))
+	for i, part := range parts {
+		segments[i] = renderName(part, values, model.AlbumFolderMaxRunes)
+	}
+	return segments
+}
+
+// ShowRelativePath returns the show path relative to the artist folder using
+// forward slashes, e.g. "2024/2024-03-02 Red Rocks". It is the key used by
+// artist presence indexes.
+func ShowRelativePath(cfg *model.Config, show *model.AlbArtResp) string {
+	return path.Join(ShowPathSegments(cfg, show)[1:]...)
+}
+
+// ArtistFolderName renders the artist folder for artistName.
+func ArtistFolderName(cfg *model.Config, artistName string) string {
+	return ShowPathSegments(cfg, &model.AlbArtResp{ArtistName: artistName})[0]
+}
+
+// ShowFolderDepth returns how many directory levels sit between the artist
+// folder and a show folder (1 for the default layout).
+func ShowFolderDepth(cfg *model.Config) int {
+	return strings.Count(folderTemplate(cfg), "/")
+}
+
+// TrackFileName renders the track template (without extension). show may be
+// nil for tracks that have no show context.
+func TrackFileName(cfg *model.Config, show *model.AlbArtResp, track TrackNameInfo) string {
+	tpl := DefaultTrackTemplate
+	if cfg != nil && strings.TrimSpace(cfg.TrackTemplate) != "" {
+		tpl = cfg.TrackTemplate
+	}
+	values := showTemplateValues(show)
+	values["track"] = track.Number
+	values["trackTotal"] = track.Total
+	values["disc"] = track.Disc
+	values["title"] = track.Title
+	values["trackId"] = track.ID
+	return renderName(tpl, values, 0)
+}
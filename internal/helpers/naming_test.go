@@ -0,0 +1,97 @@
+package helpers
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jmagar/nugs-cli/internal/model"
+)
+
+func TestDefaultTemplatesMatchLegacyNames(t *testing.T) {
+	show := &model.AlbArtResp{ArtistName: "Billy Strings", ContainerInfo: "03/02/24 Red Rocks Amphitheatre  "}
+	segments := ShowPathSegments(&model.Config{}, show)
+	want := []string{Sanitise(show.ArtistName), BuildAlbumFolderName(show.ArtistName, show.ContainerInfo)}
+	if len(segments) != 2 || segments[0] != want[0] || segments[1] != want[1] {
+		t.Fatalf("ShowPathSegments = %q, want %q", segments, want)
+	}
+
+	title := "AC/DC: Back in Black?"
+	got := TrackFileName(nil, show, TrackNameInfo{Number: 7, Title: title})
+	if legacy := fmt.Sprintf("%02d. %s", 7, Sanitise(title)); got != legacy {
+		t.Fatalf("TrackFileName = %q, want %q", got, legacy)
+	}
+}
+
+func TestCustomTemplatesRenderShowAndTrack(t *testing.T) {
+	cfg := &model.Config{
+		FolderTemplate: "{artist}/{year}/{date} {venue}",
+		TrackTemplate:  "{disc}-{track:02} {title}",
+	}
+	show := &model.AlbArtResp{
+		ArtistName:      "Billy Strings",
+		PerformanceDate: "3/2/2024",
+		Venue:           "Red Rocks",
+		VenueName:       "Red Rocks Amphitheatre",
+	}
+
+	resolver := NewConfigPathResolver(cfg)
+	if got, want := resolver.RemoteShowPath(show), "Billy Strings/2024/2024-03-02 Red Rocks Amphitheatre"; got != want {
+		t.Fatalf("RemoteShowPath = %q, want %q", got, want)
+	}
+	if got := ShowRelativePath(cfg, show); got != "2024/2024-03-02 Red Rocks Amphitheatre" {
+		t.Fatalf("ShowRelativePath = %q", got)
+	}
+	if got := ShowFolderDepth(cfg); got != 2 {
+		t.Fatalf("ShowFolderDepth = %d, want 2", got)
+	}
+	if got := TrackFileName(cfg, show, TrackNameInfo{Number: 5, Disc: 2, Title: "Dust in a Baggie"}); got != "2-05 Dust in a Baggie" {
+		t.Fatalf("TrackFileName = %q", got)
+	}
+}
+
+func TestValidateNamingTemplates(t *testing.T) {
+	valid := [][2]string{
+		{"", ""},
+		{DefaultFolderTemplate, DefaultTrackTemplate},
+		{"{artist}/{year}/{date} {venue}", "{disc}-{track:02} {title}"},
+	}
+	for _, tc := range valid {
+		if err := ValidateNamingTemplates(tc[0], tc[1]); err != nil {
+			t.Errorf("ValidateNamingTemplates(%q, %q) = %v", tc[0], tc[1], err)
+		}
+	}
+
+	invalid := [][2]string{
+		{"{artist} - {container}", ""},      // no show level
+		{"{year}/{artist}/{container}", ""}, // artist folder depends on the show
+		{"{artist}/../{container}", ""},
+		{"{artist}/{bogus}", ""},
+		{"{artist}/{venue:03}", ""},
+		{"", "{track:2} {title}"},
+		{"", "{disc}/{track} {title}"},
+		{"", "{track} {title"},
+	}
+	for _, tc := range invalid {
+		if err := ValidateNamingTemplates(tc[0], tc[1]); !errors.Is(err, ErrInvalidNamingTemplate) {
+			t.Errorf("ValidateNamingTemplates(%q, %q) = %v, want ErrInvalidNamingTemplate", tc[0], tc[1], err)
+		}
+	}
+}
+
+func TestRenderTemplateDropsSeparatorsAroundEmptyValues(t *testing.T) {
+	values := map[string]any{"disc": 0, "track": 5, "title": "Tweezer...", "date": "2024-03-02", "venue": "", "city": ""}
+	for tpl, want := range map[string]string{
+		"{disc}-{track:02} {title}": "05 Tweezer...",
+		"{date} {venue}":            "2024-03-02",
+		"{date} - {venue} - {city}": "2024-03-02",
+		"{date} - {venue} - {disc}": "2024-03-02",
+		"{title}{venue}":            "Tweezer...",
+		"{date} {venue}{track:02}":  "2024-03-02 05",
+		"{track:02}. {title}":       "05. Tweezer...",
+	} {
+		if got := renderTemplate(tpl, values); got != want {
+			t.Errorf("renderTemplate(%q) = %q, want %q", tpl, got, want)
+		}
+	}
+}
@@ -109,6 +109,18 @@ func FileExists(path string) (bool, error) {
 	return false, err
 }
 
+// PartialShowMarker is the file a --tracks download leaves in a show folder
+// it creates, so the folder is not taken for a complete show. A later full
+// download of the show removes it.
+const PartialShowMarker = ".nugs-partial"
+
+// IsPartialShow reports whether the show folder at dir holds only a --tracks
+// selection.
+func IsPartialShow(dir string) bool {
+	_, err := os.Stat(filepath.Join(dir, PartialShowMarker))
+	return err == nil
+}
+
 // ValidatePath checks that a path does not contain dangerous characters or traversal sequences.
 func ValidatePath(path string) error {
 	if strings.ContainsAny(path, "\x00\n\r") {
@@ -142,6 +154,14 @@ func GetRcloneBasePath(cfg *model.Config, isVideo bool) string {
 	return cfg.RclonePath
 }
 
+// StorageBackend returns the configured storage backend, defaulting to rclone.
+func StorageBackend(cfg *model.Config) string {
+	if cfg == nil || strings.TrimSpace(cfg.StorageBackend) == "" {
+		return model.StorageBackendRclone
+	}
+	return strings.ToLower(strings.TrimSpace(cfg.StorageBackend))
+}
+
 // CalculateLocalSize walks the directory tree and calculates total size in bytes.
 func CalculateLocalSize(localPath string) int64 {
 	var totalSize int64
@@ -218,27 +238,27 @@ func (r *ConfigPathResolver) LocalBasesForFilter(mediaFilter model.MediaType) []
 	return uniqueNonEmptyPaths(paths)
 }
 
-// LocalShowPath returns the artist/show local path for the given media type.
+// LocalShowPath returns the local show path for the given media type, laid
+// out by the configured folder template.
 func (r *ConfigPathResolver) LocalShowPath(show *model.AlbArtResp, mediaType model.MediaType) string {
 	if r == nil || show == nil {
 		return ""
 	}
-	albumFolder := BuildAlbumFolderName(show.ArtistName, show.ContainerInfo)
 	root := r.LocalBaseForMedia(mediaType)
-	showPath, err := JoinWithinRoot(root, Sanitise(show.ArtistName), albumFolder)
+	showPath, err := JoinWithinRoot(root, ShowPathSegments(r.cfg, show)...)
 	if err != nil {
 		return ""
 	}
 	return showPath
 }
 
-// RemoteShowPath returns the artist/show remote-relative path.
+// RemoteShowPath returns the remote-relative show path laid out by the
+// configured folder template.
 func (r *ConfigPathResolver) RemoteShowPath(show *model.AlbArtResp) string {
-	if show == nil {
+	if r == nil || show == nil {
 		return ""
 	}
-	albumFolder := BuildAlbumFolderName(show.ArtistName, show.ContainerInfo)
-	return path.Join(Sanitise(show.ArtistName), albumFolder)
+	return path.Join(ShowPathSegments(r.cfg, show)...)
 }
 
 func uniqueNonEmptyPaths(paths []string) []string {
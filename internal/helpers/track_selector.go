@@ -0,0 +1,152 @@
+package helpers
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/jmagar/nugs-cli/internal/model"
+)
+
+// ErrInvalidTrackSelector indicates a --tracks value cannot be parsed.
+var ErrInvalidTrackSelector = errors.New("invalid track selector")
+
+// TrackSelector picks tracks from a release. Conditions of the same kind are
+// alternatives ("1-3,7", "set:1,set:3"); conditions of different kinds must
+// all hold ("set:2,song:jam").
+type TrackSelector struct {
+	positions []numRange
+	sets      []numRange
+	discs     []numRange
+	song      *regexp.Regexp
+}
+
+type numRange struct{ lo, hi int }
+
+func (r numRange) contains(n int) bool { return n >= r.lo && n <= r.hi }
+
+// ParseTrackSelector parses a comma-separated list of track positions or
+// ranges ("3", "1-5"), set numbers ("set:2"), disc numbers ("disc:1-2") and
+// at most one case-insensitive title regex ("song:dark star"). The regex runs
+// to the end of the value, so it may itself contain commas.
+func ParseTrackSelector(s string) (*TrackSelector, error) {
+	sel := &TrackSelector{}
+	rest := strings.TrimSpace(s)
+	if rest == "" {
+		return nil, fmt.Errorf("%w: empty", ErrInvalidTrackSelector)
+	}
+	for rest != "" {
+		term := rest
+		if lower := strings.ToLower(rest); !strings.HasPrefix(lower, "song:") {
+			term, rest, _ = strings.Cut(rest, ",")
+		} else {
+			rest = ""
+		}
+		term = strings.TrimSpace(term)
+		rest = strings.TrimSpace(rest)
+		if term == "" {
+			continue
+		}
+		if err := sel.add(term); err != nil {
+			return nil, err
+		}
+	}
+	if len(sel.positions) == 0 && len(sel.sets) == 0 && len(sel.discs) == 0 && sel.song == nil {
+		return nil, fmt.Errorf("%w: %q selects nothing", ErrInvalidTrackSelector, s)
+	}
+	return sel, nil
+}
+
+func (sel *TrackSelector) add(term string) error {
+	key, value, hasKey := strings.Cut(term, ":")
+	if !hasKey {
+		r, err := parseNumRange(term)
+		if err != nil {
+			return err
+		}
+		sel.positions = append(sel.positions, r)
+		return nil
+	}
+	switch strings.ToLower(strings.TrimSpace(key)) {
+	case "set":
+		r, err := parseNumRange(value)
+		if err != nil {
+			return err
+		}
+		sel.sets = append(sel.sets, r)
+	case "disc":
+		r, err := parseNumRange(value)
+		if err != nil {
+			return err
+		}
+		sel.discs = append(sel.discs, r)
+	case "song":
+		value = strings.TrimSpace(value)
+		if value == "" {
+			return fmt.Errorf("%w: empty song pattern", ErrInvalidTrackSelector)
+		}
+		re, err := regexp.Compile("(?i)" + value)
+		if err != nil {
+			return fmt.Errorf("%w: song pattern %q: %v", ErrInvalidTrackSelector, value, err)
+		}
+		sel.song = re
+	default:
+		return fmt.Errorf("%w: unknown condition %q (use N, N-M, set:N, disc:N or song:<regex>)", ErrInvalidTrackSelector, key)
+	}
+	return nil
+}
+
+// parseNumRange parses "N" or "N-M" with 1 <= N <= M.
+func parseNumRange(s string) (numRange, error) {
+	s = strings.TrimSpace(s)
+	loStr, hiStr, isRange := strings.Cut(s, "-")
+	if !isRange {
+		hiStr = loStr
+	}
+	lo, loErr := strconv.Atoi(strings.TrimSpace(loStr))
+	hi, hiErr := strconv.Atoi(strings.TrimSpace(hiStr))
+	if loErr != nil || hiErr != nil || lo < 1 || hi < lo {
+		return numRange{}, fmt.Errorf("%w: %q is not a number or range like 3 or 1-5", ErrInvalidTrackSelector, s)
+	}
+	return numRange{lo: lo, hi: hi}, nil
+}
+
+// Select returns the indexes of the selected tracks, in release order.
+// Positions count from 1 in the release's track listing; a track's disc is
+// its disc number, or its set number when the release has no discs.
+func (sel *TrackSelector) Select(tracks []model.Track) []int {
+	var picked []int
+	for i := range tracks {
+		if sel.matches(i+1, &tracks[i]) {
+			picked = append(picked, i)
+		}
+	}
+	return picked
+}
+
+func (sel *TrackSelector) matches(position int, track *model.Track) bool {
+	disc := track.DiscNum
+	if disc <= 0 {
+		disc = track.SetNum
+	}
+	return anyRange(sel.positions, position) &&
+		anyRange(sel.sets, track.SetNum) &&
+		anyRange(sel.discs, disc) &&
+		(sel.song == nil || sel.song.MatchString(track.SongTitle))
+}
+
+// anyRange reports whether n is in one of ranges; no ranges means no
+// condition.
+func anyRange(ranges []numRange, n int) bool {
+	if len(ranges) == 0 {
+		return true
+	}
+	for _, r := range ranges {
+		if r.contains(n) {
+			return true
+		}
+	}
+	return false
+}
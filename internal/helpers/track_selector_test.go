@@ -0,0 +1,55 @@
+package helpers
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/jmagar/nugs-cli/internal/model"
+)
+
+func selectorTracks() []model.Track {
+	return []model.Track{
+		{SongTitle: "Jack Straw", SetNum: 1},
+		{SongTitle: "Tennessee Jed", SetNum: 1},
+		{SongTitle: "Dark Star", SetNum: 2},
+		{SongTitle: "Drums, Space", SetNum: 2},
+		{SongTitle: "Dark Star Reprise", SetNum: 2},
+		{SongTitle: "U.S. Blues", SetNum: 3, DiscNum: 4},
+	}
+}
+
+func TestTrackSelectorSelect(t *testing.T) {
+	tests := []struct {
+		selector string
+		want     []int
+	}{
+		{selector: "2", want: []int{1}},
+		{selector: "1-3,6", want: []int{0, 1, 2, 5}},
+		{selector: "set:2", want: []int{2, 3, 4}},
+		{selector: "set:1, set:3", want: []int{0, 1, 5}},
+		{selector: "disc:4", want: []int{5}},
+		{selector: "disc:3", want: nil},
+		{selector: "song:dark star", want: []int{2, 4}},
+		{selector: "song:^dark star$", want: []int{2}},
+		{selector: "set:2,song:drums, space", want: []int{3}},
+		{selector: "1-4,song:star", want: []int{2}},
+	}
+	for _, tc := range tests {
+		sel, err := ParseTrackSelector(tc.selector)
+		if err != nil {
+			t.Fatalf("ParseTrackSelector(%q) error = %v", tc.selector, err)
+		}
+		if got := sel.Select(selectorTracks()); !slices.Equal(got, tc.want) {
+			t.Errorf("Select(%q) = %v, want %v", tc.selector, got, tc.want)
+		}
+	}
+}
+
+func TestParseTrackSelectorErrors(t *testing.T) {
+	for _, s := range []string{"", " , ", "0", "5-2", "abc", "set:", "set:x", "track:3", "song:", "song:(", "1,-3"} {
+		if _, err := ParseTrackSelector(s); !errors.Is(err, ErrInvalidTrackSelector) {
+			t.Errorf("ParseTrackSelector(%q) error = %v, want ErrInvalidTrackSelector", s, err)
+		}
+	}
+}
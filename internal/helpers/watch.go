@@ -0,0 +1,59 @@
+package helpers
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/jmagar/nugs-cli/internal/model"
+)
+
+// IsArtistID reports whether s is a positive decimal artist ID. Unlike
+// strconv.Atoi it rejects signs, so "-5" and "+12" are not IDs.
+func IsArtistID(s string) bool {
+	if s == "" || strings.TrimLeft(s, "0123456789") != "" {
+		return false
+	}
+	n, err := strconv.Atoi(s)
+	return err == nil && n > 0
+}
+
+// NormalizeWatchEntry trims a watch entry and checks its rules: a numeric
+// artist ID, parseable and ordered since/until dates, a known media type and
+// a non-negative download cap.
+func NormalizeWatchEntry(entry *model.WatchEntry) error {
+	entry.ArtistID = strings.TrimSpace(entry.ArtistID)
+	entry.Since = strings.TrimSpace(entry.Since)
+	entry.Until = strings.TrimSpace(entry.Until)
+	entry.Venue = strings.TrimSpace(entry.Venue)
+	entry.Media = strings.ToLower(strings.TrimSpace(entry.Media))
+	if _, err := strconv.Atoi(entry.ArtistID); err != nil {
+		return fmt.Errorf("invalid artist ID %q: must be a number", entry.ArtistID)
+	}
+	var from, to string
+	if entry.Since != "" {
+		var err error
+		if from, _, err = DateBounds(entry.Since); err != nil {
+			return fmt.Errorf("since: %w", err)
+		}
+	}
+	if entry.Until != "" {
+		var err error
+		if _, to, err = DateBounds(entry.Until); err != nil {
+			return fmt.Errorf("until: %w", err)
+		}
+	}
+	if from != "" && to != "" && from > to {
+		return fmt.Errorf("since %s is after until %s", entry.Since, entry.Until)
+	}
+	switch entry.Media {
+	case "", "audio", "video", "both":
+	default:
+		return fmt.Errorf("invalid media %q: must be audio, video or both", entry.Media)
+	}
+	if entry.MaxDownloads < 0 {
+		return errors.New("maxDownloads must be zero or positive")
+	}
+	return nil
+}
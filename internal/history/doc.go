@@ -0,0 +1,4 @@
+// Package history keeps a persistent record of completed downloads and
+// uploads in an embedded bbolt database under the cache directory, so
+// collection presence survives renamed or moved show folders.
+package history
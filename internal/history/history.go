@@ -0,0 +1,325 @@
+package history
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/jmagar/nugs-cli/internal/cache"
+)
+
+// Media kinds recorded in an Entry.
+const (
+	MediaAudio = "audio"
+	MediaVideo = "video"
+)
+
+const (
+	historyFileName = "history.db"
+
+	// openTimeout bounds the wait for another process holding the database.
+	openTimeout = 10 * time.Second
+)
+
+var (
+	// entriesBucket maps a container ID and media kind to the JSON Entry.
+	entriesBucket = []byte("entries")
+	// localBucket indexes entries by local path: its keys are the cleaned
+	// path, a NUL byte and the entry key, with empty values.
+	localBucket = []byte("localPaths")
+)
+
+// storeMu serialises in-process access; bbolt's file lock covers other
+// processes.
+var storeMu sync.Mutex
+
+// Entry records one release downloaded in one media type. A later entry for
+// the same container and media replaces the earlier one.
+type Entry struct {
+	ContainerID  int               `json:"containerId"`
+	ArtistID     int               `json:"artistId,omitempty"`
+	ArtistName   string            `json:"artistName,omitempty"`
+	Title        string            `json:"title,omitempty"`
+	Media        string            `json:"media"`
+	Format       string            `json:"format,omitempty"`
+	TrackIDs     []int             `json:"trackIds,omitempty"`
+	Bytes        int64             `json:"bytes"`
+	Checksums    map[string]string `json:"checksums,omitempty"` // file name -> SHA-256 hex
+	LocalPath    string            `json:"localPath,omitempty"`
+	RemotePath   string            `json:"remotePath,omitempty"`
+	DownloadedAt time.Time         `json:"downloadedAt"`
+	UploadedAt   time.Time         `json:"uploadedAt,omitzero"`
+}
+
+// key returns the entry's database key: the big-endian container ID followed
+// by the media kind, so one container's entries sort together.
+func (e Entry) key() []byte {
+	k := make([]byte, 8, 8+len(e.Media))
+	binary.BigEndian.PutUint64(k, uint64(e.ContainerID))
+	return append(k, e.Media...)
+}
+
+func splitKey(k []byte) (containerID int, media string, ok bool) {
+	if len(k) < 8 {
+		return 0, "", false
+	}
+	return int(binary.BigEndian.Uint64(k[:8])), string(k[8:]), true
+}
+
+func localKey(path string, entryKey []byte) []byte {
+	return append([]byte(localPrefix(path)), entryKey...)
+}
+
+func localPrefix(path string) string {
+	return filepath.Clean(path) + "\x00"
+}
+
+// Store is the download history database at a fixed path. The zero value is
+// not usable; create one with Open or Default.
+type Store struct {
+	path string
+}
+
+// Open returns the store kept in dir. The database is created on first use.
+func Open(dir string) *Store {
+	return &Store{path: filepath.Join(dir, historyFileName)}
+}
+
+// Default returns the store in the nugs cache directory.
+func Default() (*Store, error) {
+	cacheDir, err := cache.GetCacheDir()
+	if err != nil {
+		return nil, err
+	}
+	return Open(cacheDir), nil
+}
+
+// Path returns the database file path.
+func (s *Store) Path() string {
+	return s.path
+}
+
+// update runs fn in a read-write transaction.
+func (s *Store) update(fn func(tx *bolt.Tx) error) error {
+	return s.withDB(func(db *bolt.DB) error { return db.Update(fn) })
+}
+
+// view runs fn in a read-only transaction. fn is not called when there is no
+// history yet.
+func (s *Store) view(fn func(tx *bolt.Tx) error) error {
+	if !fileExists(s.path) {
+		return nil
+	}
+	return s.withDB(func(db *bolt.DB) error { return db.View(fn) })
+}
+
+// withDB opens the database for the length of fn. Opening per call keeps the
+// file lock short, so downloads, uploads and `nugs serve` can share it.
+func (s *Store) withDB(fn func(db *bolt.DB) error) error {
+	storeMu.Lock()
+	defer storeMu.Unlock()
+	if err := os.MkdirAll(filepath.Dir(s.path), 0700); err != nil {
+		return fmt.Errorf("failed to create history directory: %w", err)
+	}
+	db, err := bolt.Open(s.path, 0600, &bolt.Options{Timeout: openTimeout})
+	if err != nil {
+		return fmt.Errorf("failed to open history: %w", err)
+	}
+	defer func() {
+		if closeErr := db.Close(); closeErr != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to close history: %v\n", closeErr)
+		}
+	}()
+	if err := s.prepare(db); err != nil {
+		return err
+	}
+	return fn(db)
+}
+
+// prepare creates the buckets the first time the database is opened.
+func (s *Store) prepare(db *bolt.DB) error {
+	ready := false
+	if err := db.View(func(tx *bolt.Tx) error {
+		ready = tx.Bucket(entriesBucket) != nil && tx.Bucket(localBucket) != nil
+		return nil
+	}); err != nil {
+		return fmt.Errorf("failed to read history: %w", err)
+	}
+	if ready {
+		return nil
+	}
+	err := db.Update(func(tx *bolt.Tx) error {
+		for _, name := range [][]byte{entriesBucket, localBucket} {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to prepare history: %w", err)
+	}
+	return nil
+}
+
+// putEntry stores entry, replacing any earlier entry for the same container
+// and media and moving its local path index.
+func putEntry(tx *bolt.Tx, entry Entry) error {
+	entries, local := tx.Bucket(entriesBucket), tx.Bucket(localBucket)
+	key := entry.key()
+	if old := entries.Get(key); old != nil {
+		var previous Entry
+		if json.Unmarshal(old, &previous) == nil && previous.LocalPath != "" {
+			if err := local.Delete(localKey(previous.LocalPath, key)); err != nil {
+				return err
+			}
+		}
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal history entry: %w", err)
+	}
+	if err := entries.Put(key, data); err != nil {
+		return err
+	}
+	if entry.LocalPath != "" {
+		return local.Put(localKey(entry.LocalPath, key), nil)
+	}
+	return nil
+}
+
+// Record stores a download entry, replacing any earlier entry for the same
+// container and media.
+func (s *Store) Record(entry Entry) error {
+	if entry.ContainerID == 0 {
+		return fmt.Errorf("history entry has no container ID")
+	}
+	if entry.Media == "" {
+		entry.Media = MediaAudio
+	}
+	if entry.DownloadedAt.IsZero() {
+		entry.DownloadedAt = time.Now().UTC()
+	}
+	return s.update(func(tx *bolt.Tx) error {
+		return putEntry(tx, entry)
+	})
+}
+
+// RecordUpload attaches remotePath to every entry whose local path is
+// localPath. It reports whether any entry matched; uploads of content the
+// history never saw downloaded are not recorded.
+func (s *Store) RecordUpload(localPath, remotePath string, at time.Time) (bool, error) {
+	if at.IsZero() {
+		at = time.Now().UTC()
+	}
+	prefix := []byte(localPrefix(localPath))
+	matched := false
+	err := s.update(func(tx *bolt.Tx) error {
+		var keys [][]byte
+		c := tx.Bucket(localBucket).Cursor()
+		for k, _ := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, _ = c.Next() {
+			keys = append(keys, bytes.Clone(k[len(prefix):]))
+		}
+		entries := tx.Bucket(entriesBucket)
+		for _, key := range keys {
+			data := entries.Get(key)
+			if data == nil {
+				continue
+			}
+			var entry Entry
+			if err := json.Unmarshal(data, &entry); err != nil {
+				return fmt.Errorf("failed to parse history entry: %w", err)
+			}
+			entry.RemotePath = remotePath
+			entry.UploadedAt = at
+			if err := putEntry(tx, entry); err != nil {
+				return err
+			}
+			matched = true
+		}
+		return nil
+	})
+	return matched, err
+}
+
+// Entries returns the live entries ordered by download time.
+func (s *Store) Entries() ([]Entry, error) {
+	var out []Entry
+	err := s.view(func(tx *bolt.Tx) error {
+		return tx.Bucket(entriesBucket).ForEach(func(_, data []byte) error {
+			var entry Entry
+			if err := json.Unmarshal(data, &entry); err != nil {
+				return fmt.Errorf("failed to parse history entry: %w", err)
+			}
+			out = append(out, entry)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	sortEntries(out)
+	return out, nil
+}
+
+func sortEntries(out []Entry) {
+	sort.Slice(out, func(i, j int) bool {
+		if !out[i].DownloadedAt.Equal(out[j].DownloadedAt) {
+			return out[i].DownloadedAt.Before(out[j].DownloadedAt)
+		}
+		if out[i].ContainerID != out[j].ContainerID {
+			return out[i].ContainerID < out[j].ContainerID
+		}
+		return out[i].Media < out[j].Media
+	})
+}
+
+// Containers returns the recorded container IDs. media limits the result to
+// one media kind; an empty media matches either. Only keys are read.
+func (s *Store) Containers(media string) (map[int]struct{}, error) {
+	ids := make(map[int]struct{})
+	err := s.view(func(tx *bolt.Tx) error {
+		return tx.Bucket(entriesBucket).ForEach(func(k, _ []byte) error {
+			id, kind, ok := splitKey(k)
+			if ok && (media == "" || kind == media) {
+				ids[id] = struct{}{}
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return !errors.Is(err, os.ErrNotExist)
+}
+
+// FileChecksum returns the hex SHA-256 digest of a file.
+func FileChecksum(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,68 @@
+package history
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestStoreRecordReplacesAndUploadAttachesRemotePath(t *testing.T) {
+	store := Open(t.TempDir())
+	base := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	showPath := filepath.Join(t.TempDir(), "Goose", "Goose - Show")
+
+	for _, entry := range []Entry{
+		{ContainerID: 10, ArtistID: 1, Media: MediaAudio, Format: "AAC 150kbps", LocalPath: showPath, DownloadedAt: base},
+		{ContainerID: 10, ArtistID: 1, Media: MediaVideo, LocalPath: showPath + ".mp4", DownloadedAt: base.Add(time.Minute)},
+		{ContainerID: 10, ArtistID: 1, Media: MediaAudio, Format: "FLAC 16/44.1", TrackIDs: []int{7, 8}, LocalPath: showPath, DownloadedAt: base.Add(2 * time.Minute)},
+	} {
+		if err := store.Record(entry); err != nil {
+			t.Fatalf("Record: %v", err)
+		}
+	}
+	matched, err := store.RecordUpload(showPath+string(filepath.Separator), "remote:Music/Goose/Goose - Show", base.Add(time.Hour))
+	if err != nil || !matched {
+		t.Fatalf("RecordUpload = %v, %v; want match", matched, err)
+	}
+	if matched, err := store.RecordUpload("/elsewhere", "remote:x", time.Time{}); err != nil || matched {
+		t.Fatalf("RecordUpload for unknown path = %v, %v; want no match", matched, err)
+	}
+
+	entries, err := store.Entries()
+	if err != nil {
+		t.Fatalf("Entries: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("Entries returned %d entries, want 2: %+v", len(entries), entries)
+	}
+	video, audio := entries[0], entries[1]
+	if video.Media != MediaVideo || video.RemotePath != "" {
+		t.Errorf("video entry = %+v, want unuploaded video", video)
+	}
+	if audio.Format != "FLAC 16/44.1" || len(audio.TrackIDs) != 2 {
+		t.Errorf("audio entry = %+v, want the re-download to replace the first record", audio)
+	}
+	if audio.RemotePath != "remote:Music/Goose/Goose - Show" || !audio.UploadedAt.Equal(base.Add(time.Hour)) {
+		t.Errorf("audio upload = %q at %v, want recorded remote path", audio.RemotePath, audio.UploadedAt)
+	}
+
+	ids, err := store.Containers(MediaVideo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := ids[10]; !ok || len(ids) != 1 {
+		t.Errorf("Containers(video) = %v, want {10}", ids)
+	}
+}
+
+func TestStoreWithoutHistory(t *testing.T) {
+	store := Open(t.TempDir())
+	ids, err := store.Containers("")
+	if err != nil || len(ids) != 0 {
+		t.Fatalf("Containers = %v, %v; want empty", ids, err)
+	}
+	if _, err := os.Stat(store.Path()); !os.IsNotExist(err) {
+		t.Fatalf("reading an empty history created %s", store.Path())
+	}
+}
@@ -0,0 +1,3 @@
+// Package manifest writes the per-show checksum manifest that is uploaded
+// with every show folder, and checks remote copies against it.
+package manifest
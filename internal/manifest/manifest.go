@@ -0,0 +1,215 @@
+package manifest
+
+import (
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jmagar/nugs-cli/internal/cache"
+	"github.com/jmagar/nugs-cli/internal/model"
+)
+
+// FileName is the manifest written at the top of each show folder.
+const FileName = "nugs-manifest.json"
+
+// Version is the manifest format version written by Build.
+const Version = 1
+
+// Hash algorithms recorded for every file.
+const (
+	AlgoSHA256 = "sha256"
+	AlgoMD5    = "md5"
+)
+
+// Manifest lists every file of a show with its size and digests. MD5 is kept
+// alongside SHA-256 because many remotes (S3 ETags, Google Drive, ...) only
+// expose MD5 without downloading the file.
+type Manifest struct {
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"createdAt"`
+	Files     []File    `json:"files"`
+}
+
+// File is one manifest entry. Path is slash-separated and relative to the
+// show folder.
+type File struct {
+	Path   string `json:"path"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+	MD5    string `json:"md5"`
+}
+
+// Names returns the manifest paths in order.
+func (m *Manifest) Names() []string {
+	names := make([]string, len(m.Files))
+	for i, file := range m.Files {
+		names[i] = file.Path
+	}
+	return names
+}
+
+// hashFile returns the size, SHA-256 and MD5 of the file at p in one pass.
+func hashFile(p string) (File, error) {
+	f, err := os.Open(p)
+	if err != nil {
+		return File{}, err
+	}
+	defer f.Close()
+	sha, sum := sha256.New(), md5.New()
+	n, err := io.Copy(io.MultiWriter(sha, sum), f)
+	if err != nil {
+		return File{}, fmt.Errorf("failed to hash %s: %w", p, err)
+	}
+	return File{Size: n, SHA256: hex.EncodeToString(sha.Sum(nil)), MD5: hex.EncodeToString(sum.Sum(nil))}, nil
+}
+
+// Build hashes every regular file under dir except an existing manifest.
+func Build(dir string) (*Manifest, error) {
+	m := &Manifest{Version: Version, CreatedAt: time.Now().UTC(), Files: []File{}}
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			return walkErr
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if rel == FileName {
+			return nil
+		}
+		file, err := hashFile(p)
+		if err != nil {
+			return err
+		}
+		file.Path = rel
+		m.Files = append(m.Files, file)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to build manifest for %s: %w", dir, err)
+	}
+	sort.Slice(m.Files, func(i, j int) bool { return m.Files[i].Path < m.Files[j].Path })
+	return m, nil
+}
+
+// Write builds the manifest for dir and saves it as dir/FileName.
+func Write(dir string) (*Manifest, error) {
+	m, err := Build(dir)
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal manifest: %w", err)
+	}
+	if err := cache.WriteFileAtomic(filepath.Join(dir, FileName), append(data, '\n'), 0644); err != nil {
+		return nil, fmt.Errorf("failed to write manifest: %w", err)
+	}
+	return m, nil
+}
+
+// Parse decodes a manifest read from disk or a remote.
+func Parse(data []byte) (*Manifest, error) {
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("invalid manifest: %w", err)
+	}
+	if m.Version < 1 || m.Version > Version {
+		return nil, fmt.Errorf("unsupported manifest version %d", m.Version)
+	}
+	for _, file := range m.Files {
+		if file.Path == "" || strings.HasPrefix(file.Path, "/") || strings.Contains("/"+file.Path+"/", "/../") {
+			return nil, fmt.Errorf("invalid manifest path %q", file.Path)
+		}
+	}
+	return &m, nil
+}
+
+// ForUpload returns the manifest to check an upload of localPath against:
+// the saved manifest of a show folder, or one built on the spot for folders
+// without one and for single files (keyed by the file name).
+func ForUpload(localPath string) (*Manifest, error) {
+	info, err := os.Stat(localPath)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		file, err := hashFile(localPath)
+		if err != nil {
+			return nil, err
+		}
+		file.Path = filepath.Base(localPath)
+		return &Manifest{Version: Version, CreatedAt: time.Now().UTC(), Files: []File{file}}, nil
+	}
+	data, err := os.ReadFile(filepath.Join(localPath, FileName))
+	if err == nil {
+		return Parse(data)
+	}
+	if !os.IsNotExist(err) {
+		return nil, err
+	}
+	return Build(localPath)
+}
+
+// Problem is one file whose remote copy does not match the manifest.
+type Problem struct {
+	Path   string `json:"path"`
+	Reason string `json:"reason"`
+}
+
+// Check compares remote digests with the manifest. Every manifest file must
+// be present with a matching SHA-256 or MD5; a remote that offers neither
+// is a problem rather than a pass.
+func Check(m *Manifest, remote map[string]model.FileHash) []Problem {
+	var problems []Problem
+	for _, file := range m.Files {
+		hash, ok := remote[file.Path]
+		if !ok {
+			problems = append(problems, Problem{Path: file.Path, Reason: "missing"})
+			continue
+		}
+		var want string
+		switch hash.Algo {
+		case AlgoSHA256:
+			want = file.SHA256
+		case AlgoMD5:
+			want = file.MD5
+		}
+		switch {
+		case want == "" || hash.Value == "":
+			problems = append(problems, Problem{Path: file.Path, Reason: fmt.Sprintf("no comparable hash (remote offers %q)", hash.Algo)})
+		case !strings.EqualFold(want, hash.Value):
+			problems = append(problems, Problem{Path: file.Path, Reason: fmt.Sprintf("%s mismatch", hash.Algo)})
+		}
+	}
+	return problems
+}
+
+// Err summarises problems as an error, or returns nil when there are none.
+func Err(problems []Problem) error {
+	if len(problems) == 0 {
+		return nil
+	}
+	const shown = 5
+	parts := make([]string, 0, min(len(problems), shown))
+	for _, problem := range problems[:min(len(problems), shown)] {
+		parts = append(parts, problem.Path+": "+problem.Reason)
+	}
+	if len(problems) > shown {
+		parts = append(parts, fmt.Sprintf("and %d more", len(problems)-shown))
+	}
+	return fmt.Errorf("%d file(s) failed checksum verification: %s", len(problems), strings.Join(parts, "; "))
+}
@@ -0,0 +1,84 @@
+package manifest
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jmagar/nugs-cli/internal/model"
+)
+
+func TestWriteAndCheck(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "disc 2"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	for name, body := range map[string]string{"01.flac": "abc", "disc 2/01.flac": ""} {
+		if err := os.WriteFile(filepath.Join(dir, filepath.FromSlash(name)), []byte(body), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	written, err := Write(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Rebuilding must skip the manifest itself.
+	m, err := ForUpload(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(m.Files) != 2 || len(written.Files) != 2 {
+		t.Fatalf("manifest files = %+v", m.Files)
+	}
+	first := m.Files[0]
+	if first.Path != "01.flac" || first.Size != 3 ||
+		first.SHA256 != "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad" ||
+		first.MD5 != "900150983cd24fb0d6963f7d28e17f72" {
+		t.Fatalf("first entry = %+v", first)
+	}
+
+	remote := map[string]model.FileHash{
+		"01.flac":        {Algo: AlgoMD5, Value: strings.ToUpper(first.MD5)},
+		"disc 2/01.flac": {Algo: AlgoSHA256, Value: m.Files[1].SHA256},
+	}
+	if problems := Check(m, remote); len(problems) != 0 {
+		t.Fatalf("Check() = %v, want no problems", problems)
+	}
+	delete(remote, "disc 2/01.flac")
+	remote["01.flac"] = model.FileHash{Algo: "crc32", Value: "1234"}
+	problems := Check(m, remote)
+	if len(problems) != 2 || problems[0].Reason != `no comparable hash (remote offers "crc32")` || problems[1].Reason != "missing" {
+		t.Fatalf("Check() = %+v", problems)
+	}
+	if err := Err(problems); err == nil || !strings.HasPrefix(err.Error(), "2 file(s) failed") {
+		t.Fatalf("Err() = %v", err)
+	}
+}
+
+func TestForUploadSingleFile(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "show.mp4")
+	if err := os.WriteFile(file, []byte("video"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	m, err := ForUpload(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(m.Files) != 1 || m.Files[0].Path != "show.mp4" || m.Files[0].Size != 5 {
+		t.Fatalf("single-file manifest = %+v", m.Files)
+	}
+}
+
+func TestParseRejectsBadManifests(t *testing.T) {
+	for _, data := range []string{
+		`not json`,
+		`{"version": 9, "files": []}`,
+		`{"version": 1, "files": [{"path": "../escape.flac"}]}`,
+		`{"version": 1, "files": [{"path": "/abs.flac"}]}`,
+	} {
+		if _, err := Parse([]byte(data)); err == nil {
+			t.Fatalf("Parse(%s) succeeded, want error", data)
+		}
+	}
+}
@@ -66,3 +66,129 @@ type ArtistListCache struct {
 	CachedAt time.Time       `json:"cachedAt"`
 	Resp     *ArtistListResp `json:"resp"`
 }
+
+// FullCatalogShow is one container in the full catalog index. It keeps the
+// fields used for folder naming plus the media type and downloadability
+// computed at crawl time, so gap analysis needs no artist metadata.
+type FullCatalogShow struct {
+	ContainerID                   int       `json:"containerID"`
+	ArtistID                      int       `json:"artistID"`
+	ArtistName                    string    `json:"artistName"`
+	ContainerInfo                 string    `json:"containerInfo"`
+	PerformanceDate               string    `json:"performanceDate,omitempty"`
+	PerformanceDateFormatted      string    `json:"performanceDateFormatted,omitempty"`
+	PerformanceDateShortYearFirst string    `json:"performanceDateShortYearFirst,omitempty"`
+	PerformanceDateYear           string    `json:"performanceDateYear,omitempty"`
+	Venue                         string    `json:"venue,omitempty"`
+	VenueName                     string    `json:"venueName,omitempty"`
+	VenueCity                     string    `json:"venueCity,omitempty"`
+	VenueState                    string    `json:"venueState,omitempty"`
+	AvailabilityTypeStr           string    `json:"availabilityTypeStr,omitempty"`
+	ReleaseDateFormatted          string    `json:"releaseDateFormatted,omitempty"`
+	Media                         MediaType `json:"media"`
+	Downloadable                  bool      `json:"downloadable"`
+}
+
+// AlbArtResp returns a metadata stub carrying the indexed naming fields.
+func (s FullCatalogShow) AlbArtResp() *AlbArtResp {
+	return &AlbArtResp{
+		ContainerID:                   s.ContainerID,
+		ArtistID:                      s.ArtistID,
+		ArtistName:                    s.ArtistName,
+		ContainerInfo:                 s.ContainerInfo,
+		PerformanceDate:               s.PerformanceDate,
+		PerformanceDateFormatted:      s.PerformanceDateFormatted,
+		PerformanceDateShortYearFirst: s.PerformanceDateShortYearFirst,
+		PerformanceDateYear:           s.PerformanceDateYear,
+		Venue:                         s.Venue,
+		VenueName:                     s.VenueName,
+		VenueCity:                     s.VenueCity,
+		VenueState:                    s.VenueState,
+		AvailabilityTypeStr:           s.AvailabilityTypeStr,
+		ReleaseDateFormatted:          s.ReleaseDateFormatted,
+	}
+}
+
+// FullCatalogIndex maps container IDs to full catalog entries.
+type FullCatalogIndex struct {
+	Containers map[int]FullCatalogShow `json:"containers"`
+}
+
+// FullCatalogMeta stores metadata about the full catalog index.
+type FullCatalogMeta struct {
+	FullCrawlAt       time.Time `json:"fullCrawlAt"`
+	LastIncrementalAt time.Time `json:"lastIncrementalAt,omitzero"`
+	CrawlDuration     string    `json:"crawlDuration"`
+	TotalShows        int       `json:"totalShows"`
+	TotalArtists      int       `json:"totalArtists"`
+	// StaleArtists could not be fetched by the last crawl or update; their
+	// index entries may be incomplete, so analysis falls back to the API.
+	StaleArtists []int `json:"staleArtists,omitempty"`
+}
+
+// FullCatalogCrawl is the checkpoint of an in-progress full catalog crawl.
+type FullCatalogCrawl struct {
+	StartedAt     time.Time               `json:"startedAt"`
+	DoneArtists   []int                   `json:"doneArtists"`
+	FailedArtists []int                   `json:"failedArtists,omitempty"`
+	Containers    map[int]FullCatalogShow `json:"containers"`
+}
+
+// SearchIndex is the local inverted index behind `nugs search`. Terms maps
+// "field:token" keys (for example "venue:rocks") to sorted container IDs.
+type SearchIndex struct {
+	Version int                `json:"version"`
+	BuiltAt time.Time          `json:"builtAt"`
+	Shows   map[int]SearchShow `json:"shows"`
+	Terms   map[string][]int   `json:"terms"`
+}
+
+// SearchShow is one searchable container. Date is YYYY-MM-DD when the
+// performance date parses, otherwise the raw API value. Songs holds the titles
+// the setlist index records for the show.
+type SearchShow struct {
+	ContainerID int       `json:"containerID"`
+	ArtistID    int       `json:"artistID"`
+	ArtistName  string    `json:"artistName"`
+	Title       string    `json:"title"`
+	Date        string    `json:"date,omitempty"`
+	Venue       string    `json:"venue,omitempty"`
+	City        string    `json:"city,omitempty"`
+	State       string    `json:"state,omitempty"`
+	Media       MediaType `json:"media,omitempty"`
+	Songs       []string  `json:"songs,omitempty"`
+}
+
+// SetlistIndex records every song performance found in cached artist
+// metadata, for the `nugs song` commands. Artists maps each indexed artist ID
+// to the modification time of the metadata it was indexed from, so only
+// artists with newer metadata are re-read.
+type SetlistIndex struct {
+	Version      int                  `json:"version"`
+	BuiltAt      time.Time            `json:"builtAt"`
+	Artists      map[string]time.Time `json:"artists,omitempty"`
+	Performances []SongPerformance    `json:"performances"`
+}
+
+// SongPerformance is one track of one show. Date is YYYY-MM-DD when the
+// performance date parses, otherwise the raw API value.
+type SongPerformance struct {
+	ArtistID    int    `json:"artistID"`
+	ArtistName  string `json:"artistName"`
+	ContainerID int    `json:"containerID"`
+	Date        string `json:"date,omitempty"`
+	Venue       string `json:"venue,omitempty"`
+	SongID      int    `json:"songID,omitempty"`
+	SongTitle   string `json:"songTitle"`
+	TrackID     int    `json:"trackID,omitempty"`
+	SetNum      int    `json:"setNum,omitempty"`
+	TrackNum    int    `json:"trackNum,omitempty"`
+	Duration    int    `json:"duration,omitempty"` // seconds
+}
+
+// ShowTrack pairs a track with the show it was taken from, which supplies
+// the track's embedded tags when tracks from several shows share a folder.
+type ShowTrack struct {
+	Track *Track
+	Show  *AlbArtResp
+}
@@ -13,6 +13,15 @@ const (
 	JSONLevelRaw      = "raw"
 )
 
+// Cover art sources selectable via Config.CoverArtSource
+const (
+	CoverArtSourceImg   = "img"   // Highest-resolution show image (img/pics)
+	CoverArtSourceCDArt = "cdart" // First cdArtWorkList entry, falling back to img
+	CoverArtSourceNone  = "none"  // Do not fetch or embed artwork
+
+	CoverArtFileName = "folder.jpg"
+)
+
 // Message priority constants for progress box messages
 const (
 	MessagePriorityStatus  = 1 // Info messages (cyan, info symbol)
@@ -41,6 +50,11 @@ const (
 	PreCalcPerRequestTimeout = 5 * time.Second
 	PreCalcMaxTimeout        = 60 * time.Second
 
+	MaxTrackConcurrency = 16
+
+	DefaultTranscodeConcurrency = 2
+	MaxTranscodeConcurrency     = 16
+
 	AlbumFolderMaxRunes = 120
 	VideoNameMaxRunes   = 110
 
@@ -0,0 +1,53 @@
+package model
+
+import "time"
+
+// Lifecycle event types published by downloads and gap fills.
+const (
+	EventShowStarted    = "show.started"
+	EventTrackDone      = "track.done"
+	EventShowDone       = "show.done"
+	EventUploadDone     = "upload.done"
+	EventVerifyFailed   = "verify.failed"
+	EventGapFillSummary = "gapfill.summary"
+)
+
+// EventTypes lists every lifecycle event type in the order they occur.
+var EventTypes = []string{
+	EventShowStarted,
+	EventTrackDone,
+	EventVerifyFailed,
+	EventUploadDone,
+	EventShowDone,
+	EventGapFillSummary,
+}
+
+// Event is one download lifecycle event. Fields that do not apply to a type
+// are left empty; it is written as-is to the event log and hook environment.
+type Event struct {
+	Type        string    `json:"type"`
+	Time        time.Time `json:"time"`
+	ContainerID int       `json:"containerId,omitempty"`
+	ArtistID    int       `json:"artistId,omitempty"`
+	ArtistName  string    `json:"artistName,omitempty"`
+	Show        string    `json:"show,omitempty"`  // container info, e.g. "2024-06-01 Red Rocks"
+	Date        string    `json:"date,omitempty"`  // show date, YYYY-MM-DD when known
+	Media       string    `json:"media,omitempty"` // audio or video
+	Path        string    `json:"path,omitempty"`  // local show folder or track file
+	Track       int       `json:"track,omitempty"` // 1-based track number
+	TrackTitle  string    `json:"trackTitle,omitempty"`
+	Remote      string    `json:"remote,omitempty"` // upload destination
+	Error       string    `json:"error,omitempty"`
+	ErrorClass  string    `json:"errorClass,omitempty"` // e.g. verify, upload, network
+	Downloaded  int       `json:"downloaded,omitempty"` // gap-fill counts
+	Failed      int       `json:"failed,omitempty"`
+	Missing     int       `json:"missing,omitempty"`
+	Skipped     int       `json:"skipped,omitempty"`
+}
+
+// HookConfig runs a shell command for events of one type, or every type when
+// Event is "*". Event fields are passed as NUGS_* environment variables.
+type HookConfig struct {
+	Event   string `json:"event"`
+	Command string `json:"command"`
+}
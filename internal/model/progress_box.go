@@ -85,6 +85,10 @@ type ProgressBoxState struct {
 	// Batch progress (Tier 4 enhancement)
 	BatchState *BatchProgressState // Optional batch context for multi-album operations
 
+	// Parallel track tracking (keyed by track number)
+	ActiveTracks   map[int]*ActiveTrackProgress // Tracks currently downloading
+	FinishedTracks int                          // Tracks done for this album (downloaded, skipped, or failed)
+
 	// Render-state tracking for smart redraw decisions
 	LastRenderedTrackNumber     int
 	LastRenderedMessagePriority int
@@ -93,6 +97,87 @@ type ProgressBoxState struct {
 	ForceRender                 bool
 }
 
+// ActiveTrackProgress is the latest progress sample for one in-flight track.
+type ActiveTrackProgress struct {
+	Downloaded  int64
+	Total       int64
+	Speed       int64
+	Accumulated bool // Bytes already folded into AccumulatedBytes
+}
+
+// ActiveTrackTotals aggregates progress across all in-flight tracks.
+type ActiveTrackTotals struct {
+	Downloaded int64   // Bytes downloaded by tracks not yet in AccumulatedBytes
+	Remaining  int64   // Bytes left across tracks with a known size
+	Total      int64   // Sum of known track sizes
+	Speed      int64   // Combined download speed in bytes/sec
+	Progress   float64 // Sum of per-track completion fractions
+	Count      int     // Number of in-flight tracks
+	Sized      int     // In-flight tracks with a known size, not yet accumulated
+}
+
+// UpdateActiveTrackLocked records a progress sample for trackNum and returns
+// the aggregate across all in-flight tracks.
+// REQUIRES: Caller must hold s.Mu lock.
+func (s *ProgressBoxState) UpdateActiveTrackLocked(trackNum int, downloaded, total, speed int64) ActiveTrackTotals {
+	if s.ActiveTracks == nil {
+		s.ActiveTracks = make(map[int]*ActiveTrackProgress)
+	}
+	track := s.ActiveTracks[trackNum]
+	if track == nil {
+		track = &ActiveTrackProgress{}
+		s.ActiveTracks[trackNum] = track
+	}
+	track.Downloaded, track.Total, track.Speed = downloaded, total, speed
+	return s.activeTrackTotalsLocked()
+}
+
+// activeTrackTotalsLocked sums in-flight track progress.
+// REQUIRES: Caller must hold s.Mu lock.
+func (s *ProgressBoxState) activeTrackTotalsLocked() ActiveTrackTotals {
+	var totals ActiveTrackTotals
+	for _, track := range s.ActiveTracks {
+		totals.Count++
+		if track.Accumulated {
+			totals.Progress++
+			continue
+		}
+		totals.Downloaded += track.Downloaded
+		totals.Speed += track.Speed
+		if track.Total > 0 {
+			totals.Sized++
+			totals.Total += track.Total
+			totals.Remaining += max(track.Total-track.Downloaded, 0)
+			totals.Progress += min(float64(track.Downloaded)/float64(track.Total), 1)
+		}
+	}
+	return totals
+}
+
+// AccumulateTrackLocked folds a completed track's size into AccumulatedBytes.
+// The track stays in ActiveTracks (counted as fully complete) until
+// FinishTrack so show progress never dips between the two calls.
+// REQUIRES: Caller must hold s.Mu lock.
+func (s *ProgressBoxState) AccumulateTrackLocked(trackNum int, size int64) {
+	s.AccumulatedBytes += size
+	s.AccumulatedTracks++
+	if track := s.ActiveTracks[trackNum]; track != nil {
+		track.Accumulated = true
+	}
+}
+
+// FinishTrack marks trackNum as done, whether it downloaded, was skipped, or failed.
+// Thread-safe: acquires mutex internally.
+func (s *ProgressBoxState) FinishTrack(trackNum int) {
+	if s == nil {
+		return
+	}
+	s.Mu.Lock()
+	defer s.Mu.Unlock()
+	delete(s.ActiveTracks, trackNum)
+	s.FinishedTracks++
+}
+
 // SetPhase sets the current operation phase with transition validation.
 // Returns an error if the phase transition is invalid.
 // Thread-safe: acquires mutex internally.
@@ -314,6 +399,10 @@ func (s *ProgressBoxState) ResetForNewAlbum(showTitle, showNumber string, trackT
 	s.DownloadETA = ""
 	s.UploadETA = ""
 
+	// Reset parallel track tracking
+	s.ActiveTracks = nil
+	s.FinishedTracks = 0
+
 	// Reset completion tracking
 	s.SkippedTracks = 0
 	s.ErrorTracks = 0
@@ -374,5 +463,65 @@ type WriteCounter struct {
 	Downloaded int64
 	Percentage int
 	StartTime  int64
+	ResumedAt  int64 // Bytes already on disk when a resumed transfer started; excluded from speed
 	OnProgress func(downloaded, total, speed int64)
 }
+
+// ProgressSnapshot is a JSON copy of a progress box, shared by the HTTP API's
+// event feed and the --json download stream.
+type ProgressSnapshot struct {
+	Show            string `json:"show"`
+	ShowNumber      string `json:"showNumber,omitempty"`
+	Phase           string `json:"phase,omitempty"`
+	TrackNumber     int    `json:"trackNumber"`
+	TrackTotal      int    `json:"trackTotal"`
+	TrackName       string `json:"trackName,omitempty"`
+	TrackFormat     string `json:"trackFormat,omitempty"`
+	DownloadPercent int    `json:"downloadPercent"`
+	DownloadSpeed   string `json:"downloadSpeed,omitempty"`
+	Downloaded      string `json:"downloaded,omitempty"`
+	DownloadTotal   string `json:"downloadTotal,omitempty"`
+	DownloadETA     string `json:"downloadEta,omitempty"`
+	UploadPercent   int    `json:"uploadPercent"`
+	UploadSpeed     string `json:"uploadSpeed,omitempty"`
+	UploadETA       string `json:"uploadEta,omitempty"`
+	ShowPercent     int    `json:"showPercent"`
+	CompletedTracks int    `json:"completedTracks"`
+	Bytes           int64  `json:"bytes"` // bytes of completed tracks
+	SkippedTracks   int    `json:"skippedTracks"`
+	ErrorTracks     int    `json:"errorTracks"`
+	Paused          bool   `json:"paused"`
+	Cancelled       bool   `json:"cancelled"`
+	Complete        bool   `json:"complete"`
+}
+
+// Snapshot copies the box's progress under its lock.
+func (s *ProgressBoxState) Snapshot() ProgressSnapshot {
+	s.Mu.Lock()
+	defer s.Mu.Unlock()
+	return ProgressSnapshot{
+		Show:            s.ShowTitle,
+		ShowNumber:      s.ShowNumber,
+		Phase:           s.CurrentPhase,
+		TrackNumber:     s.TrackNumber,
+		TrackTotal:      s.TrackTotal,
+		TrackName:       s.TrackName,
+		TrackFormat:     s.TrackFormat,
+		DownloadPercent: s.DownloadPercent,
+		DownloadSpeed:   s.DownloadSpeed,
+		Downloaded:      s.Downloaded,
+		DownloadTotal:   s.DownloadTotal,
+		DownloadETA:     s.DownloadETA,
+		UploadPercent:   s.UploadPercent,
+		UploadSpeed:     s.UploadSpeed,
+		UploadETA:       s.UploadETA,
+		ShowPercent:     s.ShowPercent,
+		CompletedTracks: s.AccumulatedTracks,
+		Bytes:           s.AccumulatedBytes,
+		SkippedTracks:   s.SkippedTracks,
+		ErrorTracks:     s.ErrorTracks,
+		Paused:          s.IsPaused,
+		Cancelled:       s.IsCancelled,
+		Complete:        s.IsComplete,
+	}
+}
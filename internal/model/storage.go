@@ -2,6 +2,35 @@ package model
 
 import "context"
 
+// Storage backends selected by Config.StorageBackend.
+const (
+	StorageBackendRclone = "rclone"
+	StorageBackendLocal  = "local"
+	StorageBackendSFTP   = "sftp"
+	StorageBackendS3     = "s3"
+)
+
+// UploadDestination is one place uploads are copied to when a config lists
+// several. Empty storage fields inherit the top-level config values.
+type UploadDestination struct {
+	Name             string `json:"name"`
+	StorageBackend   string `json:"storageBackend,omitempty"`
+	RcloneRemote     string `json:"rcloneRemote,omitempty"`
+	RclonePath       string `json:"rclonePath,omitempty"`
+	RcloneVideoPath  string `json:"rcloneVideoPath,omitempty"`
+	Transfers        int    `json:"transfers,omitempty"`
+	Media            string `json:"media,omitempty"`    // audio, video, or empty for both
+	Optional         bool   `json:"optional,omitempty"` // failures do not block deleteAfterUpload
+	SftpHost         string `json:"sftpHost,omitempty"`
+	SftpPort         int    `json:"sftpPort,omitempty"`
+	SftpIdentityFile string `json:"sftpIdentityFile,omitempty"`
+	S3Endpoint       string `json:"s3Endpoint,omitempty"`
+	S3Region         string `json:"s3Region,omitempty"`
+	S3Bucket         string `json:"s3Bucket,omitempty"`
+	S3AccessKey      string `json:"s3AccessKey,omitempty"`
+	S3SecretKey      string `json:"s3SecretKey,omitempty"`
+}
+
 // UploadProgress describes one upload progress update from a storage backend.
 type UploadProgress struct {
 	Percent  int
@@ -31,3 +60,28 @@ type StorageProvider interface {
 	PathExists(ctx context.Context, cfg *Config, remotePath string, isVideo bool) (bool, error)
 	ListArtistFolders(ctx context.Context, cfg *Config, artistFolder string, isVideo bool) (map[string]struct{}, error)
 }
+
+// StorageVerifier is implemented by providers that can check a finished
+// upload against the local files without uploading again.
+type StorageVerifier interface {
+	Verify(ctx context.Context, cfg *Config, req UploadRequest) error
+}
+
+// FileHash is a digest of a remote file. Algo is "sha256" or "md5",
+// whichever the backend could provide.
+type FileHash struct {
+	Algo  string
+	Value string
+}
+
+// StorageReader is implemented by providers that can read uploaded files
+// back, which checksum manifests need. Paths are relative to the media base
+// path, like PathExists.
+type StorageReader interface {
+	// ReadFile returns the contents of a remote file. A missing file yields
+	// an error wrapping fs.ErrNotExist.
+	ReadFile(ctx context.Context, cfg *Config, remotePath string, isVideo bool) ([]byte, error)
+	// FileHashes hashes the named files below remoteDir, keyed by name.
+	// Missing files are left out of the result.
+	FileHashes(ctx context.Context, cfg *Config, remoteDir string, names []string, isVideo bool) (map[string]FileHash, error)
+}
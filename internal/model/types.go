@@ -4,36 +4,108 @@ import "time"
 
 // Config holds the user's configuration.
 type Config struct {
-	Email                  string   `json:"email"`
-	Password               string   `json:"password"`
-	Urls                   []string `json:"-"`
-	Format                 int      `json:"format"`
-	OutPath                string   `json:"outPath"`
-	VideoOutPath           string   `json:"videoOutPath,omitempty"`
-	VideoFormat            int      `json:"videoFormat"`
-	DefaultOutputs         string   `json:"defaultOutputs,omitempty"`
-	WantRes                string   `json:"wantRes,omitempty"`
-	Token                  string   `json:"token"`
-	UseFfmpegEnvVar        bool     `json:"useFfmpegEnvVar"`
-	FfmpegNameStr          string   `json:"ffmpegNameStr,omitempty"`
-	ForceVideo             bool     `json:"forceVideo,omitempty"`
-	SkipVideos             bool     `json:"skipVideos,omitempty"`
-	SkipChapters           bool     `json:"skipChapters,omitempty"`
-	RcloneEnabled          bool     `json:"rcloneEnabled,omitempty"`
-	RcloneRemote           string   `json:"rcloneRemote,omitempty"`
-	RclonePath             string   `json:"rclonePath,omitempty"`
-	RcloneVideoPath        string   `json:"rcloneVideoPath,omitempty"`
-	DeleteAfterUpload      bool     `json:"deleteAfterUpload,omitempty"`
-	RcloneTransfers        int      `json:"rcloneTransfers,omitempty"`
-	CatalogAutoRefresh     bool     `json:"catalogAutoRefresh,omitempty"`
-	CatalogRefreshTime     string   `json:"catalogRefreshTime,omitempty"`
-	CatalogRefreshTimezone string   `json:"catalogRefreshTimezone,omitempty"`
-	CatalogRefreshInterval string   `json:"catalogRefreshInterval,omitempty"`
-	WatchedArtists         []string `json:"watchedArtists,omitempty"`
-	WatchInterval          string   `json:"watchInterval,omitempty"` // Go duration string: "1h", "30m", "6h"
-	GotifyURL              string   `json:"gotifyUrl,omitempty"`
-	GotifyToken            string   `json:"gotifyToken,omitempty"`
-	SkipSizePreCalculation bool     `json:"skipSizePreCalculation,omitempty"`
+	Email                  string              `json:"email"`
+	Password               string              `json:"password"`
+	Urls                   []string            `json:"-"`
+	TrackSelector          string              `json:"-"` // --tracks; see helpers.ParseTrackSelector
+	Format                 int                 `json:"format"`
+	OutPath                string              `json:"outPath"`
+	VideoOutPath           string              `json:"videoOutPath,omitempty"`
+	VideoFormat            int                 `json:"videoFormat"`
+	DefaultOutputs         string              `json:"defaultOutputs,omitempty"`
+	WantRes                string              `json:"wantRes,omitempty"`
+	Token                  string              `json:"token"`
+	UseFfmpegEnvVar        bool                `json:"useFfmpegEnvVar"`
+	FfmpegNameStr          string              `json:"ffmpegNameStr,omitempty"`
+	ForceVideo             bool                `json:"forceVideo,omitempty"`
+	SkipVideos             bool                `json:"skipVideos,omitempty"`
+	SkipChapters           bool                `json:"skipChapters,omitempty"`
+	RcloneEnabled          bool                `json:"rcloneEnabled,omitempty"`
+	RcloneRemote           string              `json:"rcloneRemote,omitempty"`
+	RclonePath             string              `json:"rclonePath,omitempty"`
+	RcloneVideoPath        string              `json:"rcloneVideoPath,omitempty"`
+	DeleteAfterUpload      bool                `json:"deleteAfterUpload,omitempty"`
+	RcloneTransfers        int                 `json:"rcloneTransfers,omitempty"`
+	StorageBackend         string              `json:"storageBackend,omitempty"` // rclone (default), local, sftp or s3
+	SftpHost               string              `json:"sftpHost,omitempty"`       // [user@]host or an ssh config alias
+	SftpPort               int                 `json:"sftpPort,omitempty"`
+	SftpIdentityFile       string              `json:"sftpIdentityFile,omitempty"`
+	S3Endpoint             string              `json:"s3Endpoint,omitempty"` // e.g. http://minio.lan:9000; empty uses AWS
+	S3Region               string              `json:"s3Region,omitempty"`
+	S3Bucket               string              `json:"s3Bucket,omitempty"`
+	S3AccessKey            string              `json:"s3AccessKey,omitempty"`
+	S3SecretKey            string              `json:"s3SecretKey,omitempty"`
+	UploadDestinations     []UploadDestination `json:"uploadDestinations,omitempty"`
+	CatalogAutoRefresh     bool                `json:"catalogAutoRefresh,omitempty"`
+	CatalogRefreshTime     string              `json:"catalogRefreshTime,omitempty"`
+	CatalogRefreshTimezone string              `json:"catalogRefreshTimezone,omitempty"`
+	CatalogRefreshInterval string              `json:"catalogRefreshInterval,omitempty"`
+	Watch                  []WatchEntry        `json:"watch,omitempty"`
+	WatchedArtists         []string            `json:"watchedArtists,omitempty"` // legacy plain list; folded into Watch on the next write
+	ArtistAliases          map[string]string   `json:"artistAliases,omitempty"`  // alias → artist ID, e.g. "billy": "1125"
+	WatchInterval          string              `json:"watchInterval,omitempty"`  // Go duration string: "1h", "30m", "6h"
+	GotifyURL              string              `json:"gotifyUrl,omitempty"`
+	GotifyToken            string              `json:"gotifyToken,omitempty"`
+	Notifiers              []NotifierConfig    `json:"notifiers,omitempty"`
+	Hooks                  []HookConfig        `json:"hooks,omitempty"`
+	EventLog               string              `json:"eventLog,omitempty"`     // JSON-lines file that receives every lifecycle event
+	NotifyEvents           []string            `json:"notifyEvents,omitempty"` // event types also sent to the notifiers
+	SkipSizePreCalculation bool                `json:"skipSizePreCalculation,omitempty"`
+	SkipTagging            bool                `json:"skipTagging,omitempty"`
+	SkipVerify             bool                `json:"skipVerify,omitempty"`
+	SkipHistory            bool                `json:"skipHistory,omitempty"`
+	CoverArtSource         string              `json:"coverArtSource,omitempty"`  // img (default), cdart, or none
+	CoverArtMaxSize        int                 `json:"coverArtMaxSize,omitempty"` // longest edge in pixels; 0 keeps the original
+	FolderTemplate         string              `json:"folderTemplate,omitempty"`  // e.g. "{artist}/{year}/{date} {venue}"
+	TrackTemplate          string              `json:"trackTemplate,omitempty"`   // e.g. "{disc}-{track:02} {title}"
+	TrackConcurrency       int                 `json:"trackConcurrency,omitempty"`
+	PlaylistFormats        []string            `json:"playlistFormats,omitempty"` // m3u8 (default) and/or xspf, or ["none"]
+	SingleFileSets         bool                `json:"singleFileSets,omitempty"`  // join each set into one file with a CUE sheet
+	TranscodeProfiles      []TranscodeProfile  `json:"transcodeProfiles,omitempty"`
+	TranscodeConcurrency   int                 `json:"transcodeConcurrency,omitempty"`
+}
+
+// TranscodeProfile is a named portable copy made with FFmpeg after each
+// download and by `nugs transcode`. Copies land under OutPath, mirroring the
+// download layout unless FolderTemplate is set, and keep the source file
+// names unless FileTemplate is set.
+type TranscodeProfile struct {
+	Name           string `json:"name"`
+	Codec          string `json:"codec"`                    // aac, opus, mp3, flac or alac
+	Bitrate        string `json:"bitrate,omitempty"`        // e.g. "256k"; lossy codecs only
+	SampleRate     int    `json:"sampleRate,omitempty"`     // Hz; 0 keeps the source rate
+	OutPath        string `json:"outPath"`                  // root folder of the copies
+	FolderTemplate string `json:"folderTemplate,omitempty"` // e.g. "{artist}/{date} {venue}"
+	FileTemplate   string `json:"fileTemplate,omitempty"`   // e.g. "{track:02} - {title}"
+}
+
+// NotifierConfig is one notification destination. Priorities use Gotify's
+// 0-10 scale; messages below MinPriority are not sent to it.
+type NotifierConfig struct {
+	Type        string            `json:"type"`                  // gotify, ntfy, webhook or smtp
+	URL         string            `json:"url,omitempty"`         // gotify server, ntfy topic or webhook URL
+	Token       string            `json:"token,omitempty"`       // gotify app token or ntfy access token
+	MinPriority int               `json:"minPriority,omitempty"` // 0-10
+	Body        string            `json:"body,omitempty"`        // webhook body template, e.g. {"text": {{json .Message}}}
+	Headers     map[string]string `json:"headers,omitempty"`     // extra webhook request headers
+	Host        string            `json:"host,omitempty"`        // smtp server
+	Port        int               `json:"port,omitempty"`        // smtp port; 587 by default, 465 for implicit TLS
+	Username    string            `json:"username,omitempty"`    // smtp login
+	Password    string            `json:"password,omitempty"`    // smtp password
+	From        string            `json:"from,omitempty"`        // smtp sender address
+	To          []string          `json:"to,omitempty"`          // smtp recipients
+}
+
+// WatchEntry is one watched artist and the rules that pick which of its
+// missing shows `nugs watch check` downloads. Unset rules match every show.
+type WatchEntry struct {
+	ArtistID     string `json:"artistID"`
+	Since        string `json:"since,omitempty"`        // YYYY, YYYY-MM or YYYY-MM-DD, inclusive
+	Until        string `json:"until,omitempty"`        // YYYY, YYYY-MM or YYYY-MM-DD, inclusive
+	Venue        string `json:"venue,omitempty"`        // case-insensitive substring of the venue or city
+	Media        string `json:"media,omitempty"`        // audio, video or both; overrides the check's media filter
+	SkipArchival bool   `json:"skipArchival,omitempty"` // skip releases published over a year after the show
+	MaxDownloads int    `json:"maxDownloads,omitempty"` // stop after this many downloads per run; 0 is unlimited
 }
 
 // Transport is used as a custom HTTP transport.
@@ -100,6 +172,25 @@ type RuntimeStatus struct {
 	Warnings   int    `json:"warnings"`
 }
 
+// DaemonStatus is the schedule `nugs daemon` publishes for `nugs status`.
+type DaemonStatus struct {
+	PID       int         `json:"pid"`
+	State     string      `json:"state"` // running, stopped, or stale when the process died
+	StartedAt time.Time   `json:"startedAt"`
+	UpdatedAt time.Time   `json:"updatedAt"`
+	Jobs      []DaemonJob `json:"jobs"`
+}
+
+// DaemonJob is one scheduled daemon task and its last and next runs.
+type DaemonJob struct {
+	Name      string    `json:"name"`
+	Schedule  string    `json:"schedule"`
+	Running   bool      `json:"running,omitempty"`
+	LastRun   time.Time `json:"lastRun,omitzero"`
+	LastError string    `json:"lastError,omitempty"`
+	NextRun   time.Time `json:"nextRun,omitzero"`
+}
+
 // RuntimeControl holds pause/cancel signals for crawl control.
 type RuntimeControl struct {
 	Pause     bool   `json:"pause"`
@@ -0,0 +1,195 @@
+// Package notify sends push notifications to the destinations in the config:
+// Gotify, ntfy, JSON webhooks and SMTP email, several at once.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/jmagar/nugs-cli/internal/model"
+)
+
+// Provider types accepted in a notifier's "type" field.
+const (
+	TypeGotify  = "gotify"
+	TypeNtfy    = "ntfy"
+	TypeWebhook = "webhook"
+	TypeSMTP    = "smtp"
+)
+
+// MaxPriority is the top of the Gotify-style 0-10 priority scale.
+const MaxPriority = 10
+
+// Notifier delivers one notification. Priority uses the 0-10 scale; each
+// provider maps it onto its own.
+type Notifier interface {
+	Notify(ctx context.Context, title, message string, priority int) error
+}
+
+var httpClient = &http.Client{
+	Timeout: 5 * time.Second,
+	CheckRedirect: func(_ *http.Request, _ []*http.Request) error {
+		// Tokens and webhook URLs are credentials. Never replay them to a
+		// redirect target.
+		return http.ErrUseLastResponse
+	},
+}
+
+// validateServerURL requires HTTPS, except for loopback hosts, and rejects
+// userinfo, query strings and fragments.
+func validateServerURL(service, raw string) (*url.URL, error) {
+	u, err := url.Parse(raw)
+	if err != nil || u.Hostname() == "" {
+		return nil, fmt.Errorf("%s: invalid server URL", service)
+	}
+	if u.User != nil {
+		return nil, fmt.Errorf("%s: server URL must not contain userinfo", service)
+	}
+	if u.RawQuery != "" || u.Fragment != "" {
+		return nil, fmt.Errorf("%s: server URL must not contain query parameters or fragments", service)
+	}
+	if u.Scheme == "https" {
+		return u, nil
+	}
+	if u.Scheme == "http" && isLoopback(u.Hostname()) {
+		return u, nil
+	}
+	return nil, fmt.Errorf("%s: HTTPS is required for non-loopback servers", service)
+}
+
+func isLoopback(host string) bool {
+	ip := net.ParseIP(host)
+	return host == "localhost" || ip != nil && ip.IsLoopback()
+}
+
+// post sends body to requestURL and treats any non-2xx response as an error.
+func post(ctx context.Context, service, requestURL string, body []byte, header http.Header) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, requestURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("%s: create request failed: %w", service, err)
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s: send failed: %w", service, err)
+	}
+	defer func() {
+		_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("%s: server returned %d", service, resp.StatusCode)
+	}
+	return nil
+}
+
+// route is one configured destination and the lowest priority it receives.
+type route struct {
+	name        string
+	notifier    Notifier
+	minPriority int
+}
+
+// Multi fans a notification out to several destinations.
+type Multi struct {
+	routes []route
+}
+
+// Notify sends to every destination whose minimum priority the message
+// meets. A failing destination does not stop the others; their errors are
+// joined.
+func (m *Multi) Notify(ctx context.Context, title, message string, priority int) error {
+	var errs []error
+	for _, r := range m.routes {
+		if priority < r.minPriority {
+			continue
+		}
+		if err := r.notifier.Notify(ctx, title, message, priority); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", r.name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// New builds the notifier described by one config entry.
+func New(cfg model.NotifierConfig) (Notifier, error) {
+	switch strings.ToLower(strings.TrimSpace(cfg.Type)) {
+	case TypeGotify:
+		return NewGotify(cfg.URL, cfg.Token)
+	case TypeNtfy:
+		return NewNtfy(cfg.URL, cfg.Token)
+	case TypeWebhook:
+		return NewWebhook(cfg.URL, cfg.Body, cfg.Headers)
+	case TypeSMTP:
+		return NewSMTP(cfg.Host, cfg.Port, cfg.Username, cfg.Password, cfg.From, cfg.To)
+	case "":
+		return nil, errors.New("type is required (gotify, ntfy, webhook or smtp)")
+	default:
+		return nil, fmt.Errorf("unknown type %q (use gotify, ntfy, webhook or smtp)", cfg.Type)
+	}
+}
+
+// Validate checks every configured notifier.
+func Validate(notifiers []model.NotifierConfig) error {
+	for i, cfg := range notifiers {
+		if _, err := newRoute(i, cfg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func newRoute(i int, cfg model.NotifierConfig) (route, error) {
+	if cfg.MinPriority < 0 || cfg.MinPriority > MaxPriority {
+		return route{}, fmt.Errorf("notifier %d: minPriority must be between 0 and %d", i+1, MaxPriority)
+	}
+	n, err := New(cfg)
+	if err != nil {
+		return route{}, fmt.Errorf("notifier %d: %w", i+1, err)
+	}
+	name := fmt.Sprintf("notifier %d (%s)", i+1, strings.ToLower(strings.TrimSpace(cfg.Type)))
+	return route{name: name, notifier: n, minPriority: cfg.MinPriority}, nil
+}
+
+// FromConfig builds a Multi from the notifiers list plus the legacy
+// gotifyUrl/gotifyToken pair. It returns nil when nothing is configured.
+func FromConfig(cfg *model.Config) (*Multi, error) {
+	m := &Multi{}
+	if cfg.GotifyURL != "" && cfg.GotifyToken != "" {
+		// Checked on send, as before, so an old config keeps loading.
+		m.routes = append(m.routes, route{name: TypeGotify, notifier: &Gotify{serverURL: cfg.GotifyURL, token: cfg.GotifyToken}})
+	}
+	for i, entry := range cfg.Notifiers {
+		r, err := newRoute(i, entry)
+		if err != nil {
+			return nil, err
+		}
+		m.routes = append(m.routes, r)
+	}
+	if len(m.routes) == 0 {
+		return nil, nil
+	}
+	return m, nil
+}
+
+// BuildNotifier returns a Notify function for every configured destination,
+// or nil (disabling notifications) when there are none.
+func BuildNotifier(cfg *model.Config) (func(ctx context.Context, title, message string, priority int) error, error) {
+	m, err := FromConfig(cfg)
+	if err != nil || m == nil {
+		return nil, err
+	}
+	return m.Notify, nil
+}
@@ -0,0 +1,250 @@
+package notify
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"strings"
+	"testing"
+
+	"github.com/jmagar/nugs-cli/internal/model"
+)
+
+type recordingNotifier struct {
+	calls []int
+	err   error
+}
+
+func (r *recordingNotifier) Notify(_ context.Context, _, _ string, priority int) error {
+	r.calls = append(r.calls, priority)
+	return r.err
+}
+
+func TestMultiMinPriorityAndErrors(t *testing.T) {
+	all := &recordingNotifier{}
+	errorsOnly := &recordingNotifier{}
+	broken := &recordingNotifier{err: errors.New("down")}
+	m := &Multi{routes: []route{
+		{name: "broken", notifier: broken},
+		{name: "all", notifier: all},
+		{name: "errors", notifier: errorsOnly, minPriority: 7},
+	}}
+
+	err := m.Notify(context.Background(), "Nugs Watch", "3 new show(s) downloaded", 5)
+	if err == nil || !strings.Contains(err.Error(), "broken: down") {
+		t.Fatalf("Notify error = %v, want the broken route's error", err)
+	}
+	_ = m.Notify(context.Background(), "Nugs Watch Error", "failed", 7)
+
+	if len(all.calls) != 2 {
+		t.Errorf("all calls = %v, want both messages", all.calls)
+	}
+	if len(errorsOnly.calls) != 1 || errorsOnly.calls[0] != 7 {
+		t.Errorf("errors-only calls = %v, want [7]", errorsOnly.calls)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  model.NotifierConfig
+		want string
+	}{
+		{name: "missing type", cfg: model.NotifierConfig{URL: "https://ntfy.sh/nugs"}, want: "type is required"},
+		{name: "unknown type", cfg: model.NotifierConfig{Type: "pager"}, want: `unknown type "pager"`},
+		{name: "priority range", cfg: model.NotifierConfig{Type: "ntfy", URL: "https://ntfy.sh/nugs", MinPriority: 11}, want: "minPriority"},
+		{name: "gotify token", cfg: model.NotifierConfig{Type: "gotify", URL: "https://gotify.example"}, want: "url and token are required"},
+		{name: "ntfy plain http", cfg: model.NotifierConfig{Type: "ntfy", URL: "http://ntfy.example/nugs"}, want: "HTTPS is required"},
+		{name: "ntfy topic", cfg: model.NotifierConfig{Type: "ntfy", URL: "https://ntfy.sh/"}, want: "must include the topic"},
+		{name: "webhook query", cfg: model.NotifierConfig{Type: "webhook", URL: "https://hooks.example/x?token=1"}, want: "query parameters"},
+		{name: "webhook template", cfg: model.NotifierConfig{Type: "webhook", URL: "https://hooks.example/x", Body: `{"text": {{.Message}`}, want: "invalid body template"},
+		{name: "webhook json", cfg: model.NotifierConfig{Type: "webhook", URL: "https://hooks.example/x", Body: `{"text": "{{.Message}}"}`}, want: "valid JSON"},
+		{name: "smtp recipients", cfg: model.NotifierConfig{Type: "smtp", Host: "mail.example", From: "nugs@example.com"}, want: "to address"},
+		{name: "smtp from", cfg: model.NotifierConfig{Type: "smtp", Host: "mail.example", From: "nugs", To: []string{"me@example.com"}}, want: "invalid from address"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := Validate([]model.NotifierConfig{tc.cfg})
+			if err == nil || !strings.Contains(err.Error(), tc.want) || !strings.HasPrefix(err.Error(), "notifier 1: ") {
+				t.Fatalf("Validate error = %v, want notifier 1 and %q", err, tc.want)
+			}
+		})
+	}
+
+	ok := []model.NotifierConfig{
+		{Type: "Gotify", URL: "https://gotify.example", Token: "t"},
+		{Type: "ntfy", URL: "https://ntfy.sh/nugs", MinPriority: 7},
+		{Type: "webhook", URL: "https://discord.com/api/webhooks/1/abc", Body: `{"content": {{json (printf "%s: %s" .Title .Message)}}}`},
+		{Type: "smtp", Host: "smtp.example.com", From: "Nugs <nugs@example.com>", To: []string{"me@example.com"}},
+	}
+	if err := Validate(ok); err != nil {
+		t.Fatalf("Validate(valid) error = %v", err)
+	}
+}
+
+func TestFromConfig(t *testing.T) {
+	m, err := FromConfig(&model.Config{})
+	if err != nil || m != nil {
+		t.Fatalf("FromConfig(empty) = %v, %v; want nil", m, err)
+	}
+	m, err = FromConfig(&model.Config{
+		GotifyURL:   "https://gotify.example",
+		GotifyToken: "t",
+		Notifiers:   []model.NotifierConfig{{Type: "ntfy", URL: "https://ntfy.sh/nugs"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(m.routes) != 2 || m.routes[0].name != "gotify" || m.routes[1].name != "notifier 1 (ntfy)" {
+		t.Fatalf("routes = %+v, want legacy gotify then ntfy", m.routes)
+	}
+}
+
+func TestNtfyNotify(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if r.URL.Path != "/nugs" || string(body) != "2 download failure(s)" {
+			t.Errorf("request = %s %q", r.URL.Path, body)
+		}
+		if r.Header.Get("Title") != "Nugs Watch Error" || r.Header.Get("Priority") != "4" {
+			t.Errorf("headers = %v, want title and priority 4", r.Header)
+		}
+		if r.Header.Get("Authorization") != "Bearer tk_secret" {
+			t.Errorf("Authorization = %q", r.Header.Get("Authorization"))
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	n, err := NewNtfy(server.URL+"/nugs", "tk_secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := n.Notify(context.Background(), "Nugs Watch Error", "2 download failure(s)", 7); err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+}
+
+func TestWebhookRendersTemplate(t *testing.T) {
+	var got map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Content-Type") != "application/json" || r.Header.Get("X-Api-Key") != "k" {
+			t.Errorf("headers = %v", r.Header)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("body is not JSON: %v", err)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	w, err := NewWebhook(server.URL, `{"text": {{json (printf "*%s*\n%s" .Title .Message)}}, "urgent": {{if ge .Priority 7}}true{{else}}false{{end}}}`, map[string]string{"X-Api-Key": "k"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Notify(context.Background(), "Nugs Watch", "Goose: \"Red Rocks\"\n1 failed", 5); err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+	if got["text"] != "*Nugs Watch*\nGoose: \"Red Rocks\"\n1 failed" || got["urgent"] != false {
+		t.Fatalf("body = %v", got)
+	}
+}
+
+func TestWebhookDefaultBody(t *testing.T) {
+	var got map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	w, err := NewWebhook(server.URL, "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Notify(context.Background(), "Nugs Watch", "done", 5); err != nil {
+		t.Fatal(err)
+	}
+	if got["title"] != "Nugs Watch" || got["message"] != "done" || got["priority"] != float64(5) {
+		t.Fatalf("body = %v", got)
+	}
+}
+
+// fakeSMTPServer accepts one plaintext session on loopback and returns the
+// DATA it received.
+func fakeSMTPServer(t *testing.T) (int, <-chan string) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	data := make(chan string, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		tp := textproto.NewConn(conn)
+		_ = tp.PrintfLine("220 localhost ESMTP")
+		for {
+			line, err := tp.ReadLine()
+			if err != nil {
+				return
+			}
+			switch cmd := strings.ToUpper(strings.Fields(line + " ")[0]); cmd {
+			case "EHLO", "HELO":
+				_ = tp.PrintfLine("250 localhost")
+			case "DATA":
+				_ = tp.PrintfLine("354 go ahead")
+				body, _ := io.ReadAll(tp.DotReader())
+				data <- string(body)
+				_ = tp.PrintfLine("250 queued")
+			case "QUIT":
+				_ = tp.PrintfLine("221 bye")
+				return
+			default:
+				_ = tp.PrintfLine("250 ok")
+			}
+		}
+	}()
+	return ln.Addr().(*net.TCPAddr).Port, data
+}
+
+func TestSMTPNotify(t *testing.T) {
+	port, data := fakeSMTPServer(t)
+	s, err := NewSMTP("127.0.0.1", port, "", "", "Nugs <nugs@example.com>", []string{"me@example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Notify(context.Background(), "Nugs Watch Error\r\nBcc: evil@example.com", "line one\nline two", 7); err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+	msg, err := textproto.NewReader(bufio.NewReader(strings.NewReader(<-data))).ReadMIMEHeader()
+	if err != nil {
+		t.Fatalf("parse message headers: %v", err)
+	}
+	if msg.Get("Bcc") != "" || !strings.HasPrefix(msg.Get("Subject"), "=?utf-8?q?") {
+		t.Errorf("subject = %q, bcc = %q; want an encoded subject and no injected header", msg.Get("Subject"), msg.Get("Bcc"))
+	}
+	if msg.Get("To") != "me@example.com" || msg.Get("X-Priority") != "1" {
+		t.Errorf("headers = %v", msg)
+	}
+}
+
+func TestSMTPDefaultsPort(t *testing.T) {
+	s, err := NewSMTP("smtp.example.com", 0, "", "", "nugs@example.com", []string{"me@example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.port != defaultSMTPPort {
+		t.Fatalf("port = %d, want %d", s.port, defaultSMTPPort)
+	}
+}
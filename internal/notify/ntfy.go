@@ -0,0 +1,59 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Ntfy publishes messages to an ntfy topic.
+type Ntfy struct {
+	topicURL string
+	token    string
+}
+
+// NewNtfy returns an ntfy notifier for a topic URL such as
+// https://ntfy.sh/my-topic. token is an optional access token.
+func NewNtfy(topicURL, token string) (*Ntfy, error) {
+	u, err := validateServerURL("ntfy", topicURL)
+	if err != nil {
+		return nil, err
+	}
+	if strings.Trim(u.Path, "/") == "" {
+		return nil, errors.New("ntfy: url must include the topic, e.g. https://ntfy.sh/my-topic")
+	}
+	return &Ntfy{topicURL: u.String(), token: token}, nil
+}
+
+// Notify posts the message as the request body with the title and priority
+// in headers.
+func (n *Ntfy) Notify(ctx context.Context, title, message string, priority int) error {
+	header := http.Header{
+		"Title":        {title},
+		"Priority":     {strconv.Itoa(ntfyPriority(priority))},
+		"Content-Type": {"text/plain; charset=utf-8"},
+	}
+	if n.token != "" {
+		header.Set("Authorization", "Bearer "+n.token)
+	}
+	return post(ctx, "ntfy", n.topicURL, []byte(message), header)
+}
+
+// ntfyPriority maps the 0-10 scale onto ntfy's 1 (min) to 5 (urgent), so the
+// watch summary (5) lands on default and watch errors (7) on high.
+func ntfyPriority(priority int) int {
+	switch {
+	case priority <= 1:
+		return 1
+	case priority <= 3:
+		return 2
+	case priority <= 5:
+		return 3
+	case priority <= 7:
+		return 4
+	default:
+		return 5
+	}
+}
@@ -0,0 +1,151 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultSMTPPort = 587
+	// implicitTLSPort speaks TLS from the first byte; other ports must offer
+	// STARTTLS.
+	implicitTLSPort = 465
+	smtpTimeout     = 30 * time.Second
+)
+
+// SMTP emails each notification to a fixed list of recipients.
+type SMTP struct {
+	host     string
+	port     int
+	username string
+	password string
+	from     string
+	to       []string
+}
+
+// NewSMTP returns an email notifier. port defaults to 587. Servers other
+// than loopback must use TLS: implicit on port 465, STARTTLS otherwise.
+func NewSMTP(host string, port int, username, password, from string, to []string) (*SMTP, error) {
+	if host == "" {
+		return nil, errors.New("smtp: host is required")
+	}
+	if port == 0 {
+		port = defaultSMTPPort
+	}
+	if port < 1 || port > 65535 {
+		return nil, fmt.Errorf("smtp: invalid port %d", port)
+	}
+	sender, err := mail.ParseAddress(from)
+	if err != nil {
+		return nil, fmt.Errorf("smtp: invalid from address %q: %w", from, err)
+	}
+	if len(to) == 0 {
+		return nil, errors.New("smtp: at least one to address is required")
+	}
+	recipients := make([]string, len(to))
+	for i, addr := range to {
+		parsed, err := mail.ParseAddress(addr)
+		if err != nil {
+			return nil, fmt.Errorf("smtp: invalid to address %q: %w", addr, err)
+		}
+		recipients[i] = parsed.Address
+	}
+	return &SMTP{host: host, port: port, username: username, password: password, from: sender.Address, to: recipients}, nil
+}
+
+// Notify sends one plain-text email with the title as its subject.
+func (s *SMTP) Notify(ctx context.Context, title, message string, priority int) error {
+	addr := net.JoinHostPort(s.host, strconv.Itoa(s.port))
+	tlsConfig := &tls.Config{ServerName: s.host}
+	dialer := &net.Dialer{Timeout: smtpTimeout}
+	var (
+		conn net.Conn
+		err  error
+	)
+	if s.port == implicitTLSPort {
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: tlsConfig}).DialContext(ctx, "tcp", addr)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return fmt.Errorf("smtp: connect failed: %w", err)
+	}
+	deadline := time.Now().Add(smtpTimeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	_ = conn.SetDeadline(deadline)
+
+	client, err := smtp.NewClient(conn, s.host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("smtp: %w", err)
+	}
+	defer client.Close()
+
+	if s.port != implicitTLSPort {
+		if ok, _ := client.Extension("STARTTLS"); ok {
+			if err := client.StartTLS(tlsConfig); err != nil {
+				return fmt.Errorf("smtp: starttls failed: %w", err)
+			}
+		} else if !isLoopback(s.host) {
+			return errors.New("smtp: server does not offer STARTTLS; TLS is required for non-loopback servers")
+		}
+	}
+	if s.username != "" {
+		// PlainAuth itself refuses to send the password without TLS unless
+		// the server is on localhost.
+		if err := client.Auth(smtp.PlainAuth("", s.username, s.password, s.host)); err != nil {
+			return fmt.Errorf("smtp: auth failed: %w", err)
+		}
+	}
+	if err := client.Mail(s.from); err != nil {
+		return fmt.Errorf("smtp: %w", err)
+	}
+	for _, rcpt := range s.to {
+		if err := client.Rcpt(rcpt); err != nil {
+			return fmt.Errorf("smtp: recipient %s: %w", rcpt, err)
+		}
+	}
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("smtp: %w", err)
+	}
+	if _, err := w.Write(s.message(title, message, priority)); err != nil {
+		return fmt.Errorf("smtp: write failed: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("smtp: %w", err)
+	}
+	return client.Quit()
+}
+
+// message builds the RFC 5322 email. The subject is Q-encoded when needed,
+// which also keeps a title from injecting headers. Priority 7 and up (watch
+// errors) is flagged as high importance.
+func (s *SMTP) message(title, body string, priority int) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", s.from)
+	fmt.Fprintf(&buf, "To: %s\r\n", strings.Join(s.to, ", "))
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", title))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	if priority >= 7 {
+		buf.WriteString("X-Priority: 1\r\nImportance: high\r\n")
+	}
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	buf.WriteString("Content-Transfer-Encoding: 8bit\r\n\r\n")
+	buf.WriteString(strings.ReplaceAll(strings.ReplaceAll(body, "\r\n", "\n"), "\n", "\r\n"))
+	buf.WriteString("\r\n")
+	return buf.Bytes()
+}
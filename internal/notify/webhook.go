@@ -0,0 +1,85 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"text/template"
+)
+
+// DefaultWebhookBody is the body sent when a webhook sets no template.
+const DefaultWebhookBody = `{"title": {{json .Title}}, "message": {{json .Message}}, "priority": {{.Priority}}}`
+
+// Webhook posts a JSON body rendered from a template to any URL, such as a
+// Discord or Slack incoming webhook.
+type Webhook struct {
+	url    string
+	body   *template.Template
+	header http.Header
+}
+
+// webhookData is what a body template sees.
+type webhookData struct {
+	Title    string
+	Message  string
+	Priority int
+}
+
+var webhookFuncs = template.FuncMap{
+	// json quotes a value for use inside the JSON body.
+	"json": func(v any) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+}
+
+// NewWebhook returns a webhook notifier. body is a text/template with
+// .Title, .Message and .Priority and a json function for quoting; an empty
+// body uses DefaultWebhookBody. headers are added to every request.
+func NewWebhook(rawURL, body string, headers map[string]string) (*Webhook, error) {
+	u, err := validateServerURL("webhook", rawURL)
+	if err != nil {
+		return nil, err
+	}
+	if body == "" {
+		body = DefaultWebhookBody
+	}
+	tmpl, err := template.New("webhook").Funcs(webhookFuncs).Option("missingkey=error").Parse(body)
+	if err != nil {
+		return nil, fmt.Errorf("webhook: invalid body template: %w", err)
+	}
+	header := http.Header{"Content-Type": {"application/json"}}
+	for key, value := range headers {
+		header.Set(key, value)
+	}
+	w := &Webhook{url: u.String(), body: tmpl, header: header}
+	// Render a sample so a broken template fails at config load, not on the
+	// first notification.
+	if _, err := w.render("Nugs Watch", `3 new show(s) "downloaded"`, 5); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+func (w *Webhook) render(title, message string, priority int) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := w.body.Execute(&buf, webhookData{Title: title, Message: message, Priority: priority}); err != nil {
+		return nil, fmt.Errorf("webhook: render body: %w", err)
+	}
+	if !json.Valid(buf.Bytes()) {
+		return nil, errors.New("webhook: body template does not render valid JSON (quote text with {{json .Message}})")
+	}
+	return buf.Bytes(), nil
+}
+
+// Notify renders the body and posts it.
+func (w *Webhook) Notify(ctx context.Context, title, message string, priority int) error {
+	body, err := w.render(title, message, priority)
+	if err != nil {
+		return err
+	}
+	return post(ctx, "webhook", w.url, body, w.header)
+}
@@ -0,0 +1,83 @@
+package playlist
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/jmagar/nugs-cli/internal/cache"
+)
+
+// CueSheet describes one audio file holding several tracks.
+type CueSheet struct {
+	File      string // audio file name, relative to the sheet
+	Title     string
+	Performer string
+	Date      string
+	Comments  []string // written as REM lines
+	Tracks    []CueTrack
+}
+
+// CueTrack is one track of a CUE sheet. Start is its offset into the file.
+type CueTrack struct {
+	Title     string
+	Performer string
+	Start     time.Duration
+}
+
+// WriteCue writes sheet as a CUE sheet in UTF-8.
+func WriteCue(w io.Writer, sheet CueSheet) error {
+	bw := bufio.NewWriter(w)
+	if sheet.Date != "" {
+		fmt.Fprintf(bw, "REM DATE %s\n", oneLine(sheet.Date))
+	}
+	for _, comment := range sheet.Comments {
+		fmt.Fprintf(bw, "REM COMMENT %s\n", cueQuote(comment))
+	}
+	if sheet.Performer != "" {
+		fmt.Fprintf(bw, "PERFORMER %s\n", cueQuote(sheet.Performer))
+	}
+	if sheet.Title != "" {
+		fmt.Fprintf(bw, "TITLE %s\n", cueQuote(sheet.Title))
+	}
+	fmt.Fprintf(bw, "FILE %s WAVE\n", cueQuote(sheet.File))
+	for i, track := range sheet.Tracks {
+		fmt.Fprintf(bw, "  TRACK %02d AUDIO\n", i+1)
+		if track.Title != "" {
+			fmt.Fprintf(bw, "    TITLE %s\n", cueQuote(track.Title))
+		}
+		if track.Performer != "" {
+			fmt.Fprintf(bw, "    PERFORMER %s\n", cueQuote(track.Performer))
+		}
+		fmt.Fprintf(bw, "    INDEX 01 %s\n", CueTime(track.Start))
+	}
+	return bw.Flush()
+}
+
+// SaveCue atomically writes sheet to path.
+func SaveCue(path string, sheet CueSheet) error {
+	var buf bytes.Buffer
+	if err := WriteCue(&buf, sheet); err != nil {
+		return err
+	}
+	if err := cache.WriteFileAtomic(path, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write cue sheet: %w", err)
+	}
+	return nil
+}
+
+// CueTime formats an offset as MM:SS:FF, where FF counts 1/75 s frames.
+// Minutes are not capped at 99, as long sets need more.
+func CueTime(d time.Duration) string {
+	frames := max(d, 0) * 75 / time.Second
+	return fmt.Sprintf("%02d:%02d:%02d", frames/(75*60), frames/75%60, frames%75)
+}
+
+// cueQuote double-quotes s. CUE has no escape for quotes inside a value, so
+// they become single quotes.
+func cueQuote(s string) string {
+	return `"` + strings.ReplaceAll(oneLine(s), `"`, "'") + `"`
+}
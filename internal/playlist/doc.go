@@ -0,0 +1,4 @@
+// Package playlist writes the M3U8 and XSPF playlists saved with downloaded
+// shows and playlists and built by `nugs playlist export`, reads M3U8
+// playlists back, and writes the CUE sheets that index single-file sets.
+package playlist
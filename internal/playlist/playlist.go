@@ -0,0 +1,244 @@
+package playlist
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/jmagar/nugs-cli/internal/cache"
+	"github.com/jmagar/nugs-cli/internal/model"
+)
+
+// Playlist formats, named by their file extension.
+const (
+	FormatM3U8 = "m3u8"
+	FormatXSPF = "xspf"
+	// FormatNone in playlistFormats turns playlist files off.
+	FormatNone = "none"
+)
+
+// Entry is one playlist track. Location is slash-separated, either relative
+// to the playlist file or absolute. Duration is in seconds; 0 is unknown.
+type Entry struct {
+	Location string
+	Title    string
+	Artist   string
+	Album    string
+	Duration int
+}
+
+// Formats returns the formats written beside downloads: the configured
+// playlistFormats, M3U8 when unset, and none for ["none"].
+func Formats(cfg *model.Config) []string {
+	if len(cfg.PlaylistFormats) == 0 {
+		return []string{FormatM3U8}
+	}
+	if len(cfg.PlaylistFormats) == 1 && cfg.PlaylistFormats[0] == FormatNone {
+		return nil
+	}
+	return cfg.PlaylistFormats
+}
+
+// ValidateFormats checks a playlistFormats value: m3u8 and/or xspf, or none
+// on its own.
+func ValidateFormats(formats []string) error {
+	for _, format := range formats {
+		switch format {
+		case FormatM3U8, FormatXSPF:
+		case FormatNone:
+			if len(formats) != 1 {
+				return fmt.Errorf("playlistFormats: %q cannot be combined with other formats", FormatNone)
+			}
+		default:
+			return fmt.Errorf("invalid playlistFormats entry %q (must be m3u8, xspf or none)", format)
+		}
+	}
+	return nil
+}
+
+// FormatForPath picks the format from a playlist file name.
+func FormatForPath(path string) (string, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".m3u8", ".m3u":
+		return FormatM3U8, nil
+	case ".xspf":
+		return FormatXSPF, nil
+	}
+	return "", fmt.Errorf("unknown playlist format for %q: use a .m3u8 or .xspf file name", path)
+}
+
+// Save atomically writes entries to path in format.
+func Save(path, format, title string, entries []Entry) error {
+	var buf bytes.Buffer
+	var err error
+	switch format {
+	case FormatM3U8:
+		err = WriteM3U8(&buf, entries)
+	case FormatXSPF:
+		err = WriteXSPF(&buf, title, entries)
+	default:
+		err = fmt.Errorf("unknown playlist format %q", format)
+	}
+	if err != nil {
+		return err
+	}
+	if err := cache.WriteFileAtomic(path, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write playlist: %w", err)
+	}
+	return nil
+}
+
+// SaveAll writes one playlist per format into dir, named name.<format>.
+func SaveAll(dir, name string, formats []string, title string, entries []Entry) error {
+	for _, format := range formats {
+		if err := Save(filepath.Join(dir, name+"."+format), format, title, entries); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteM3U8 writes an extended M3U playlist in UTF-8.
+func WriteM3U8(w io.Writer, entries []Entry) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "#EXTM3U")
+	for _, entry := range entries {
+		duration := entry.Duration
+		if duration <= 0 {
+			duration = -1
+		}
+		fmt.Fprintf(bw, "#EXTINF:%d,%s\n", duration, oneLine(displayTitle(entry)))
+		fmt.Fprintln(bw, oneLine(entry.Location))
+	}
+	return bw.Flush()
+}
+
+// ReadM3U8 reads an M3U or M3U8 playlist. #EXTINF text becomes the Title.
+func ReadM3U8(r io.Reader) ([]Entry, error) {
+	var (
+		entries []Entry
+		pending Entry
+	)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(strings.TrimPrefix(scanner.Text(), "\ufeff"))
+		switch {
+		case line == "":
+		case strings.HasPrefix(line, "#EXTINF:"):
+			info, title, _ := strings.Cut(strings.TrimPrefix(line, "#EXTINF:"), ",")
+			pending.Title = strings.TrimSpace(title)
+			if seconds, err := strconv.Atoi(strings.TrimSpace(info)); err == nil && seconds > 0 {
+				pending.Duration = seconds
+			}
+		case strings.HasPrefix(line, "#"):
+		default:
+			pending.Location = line
+			entries = append(entries, pending)
+			pending = Entry{}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read playlist: %w", err)
+	}
+	return entries, nil
+}
+
+type xspfPlaylist struct {
+	XMLName xml.Name    `xml:"playlist"`
+	Version string      `xml:"version,attr"`
+	XMLNS   string      `xml:"xmlns,attr"`
+	Title   string      `xml:"title,omitempty"`
+	Tracks  []xspfTrack `xml:"trackList>track"`
+}
+
+type xspfTrack struct {
+	Location string `xml:"location"`
+	Title    string `xml:"title,omitempty"`
+	Creator  string `xml:"creator,omitempty"`
+	Album    string `xml:"album,omitempty"`
+	Duration int    `xml:"duration,omitempty"` // milliseconds
+}
+
+// WriteXSPF writes an XSPF version 1 playlist.
+func WriteXSPF(w io.Writer, title string, entries []Entry) error {
+	doc := xspfPlaylist{Version: "1", XMLNS: "http://xspf.org/ns/0/", Title: title}
+	for _, entry := range entries {
+		doc.Tracks = append(doc.Tracks, xspfTrack{
+			Location: locationURI(entry.Location),
+			Title:    entry.Title,
+			Creator:  entry.Artist,
+			Album:    entry.Album,
+			Duration: max(entry.Duration, 0) * 1000,
+		})
+	}
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return fmt.Errorf("failed to encode playlist: %w", err)
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// locationURI escapes a slash-separated location as an XSPF URI: a relative
+// reference, or a file URI when absolute.
+func locationURI(location string) string {
+	segments := strings.Split(location, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	escaped := strings.Join(segments, "/")
+	switch {
+	case strings.HasPrefix(location, "/"):
+		return "file://" + escaped
+	case filepath.VolumeName(filepath.FromSlash(location)) != "":
+		return "file:///" + escaped
+	}
+	return escaped
+}
+
+func displayTitle(entry Entry) string {
+	if entry.Artist != "" && entry.Title != "" {
+		return entry.Artist + " - " + entry.Title
+	}
+	if entry.Title != "" {
+		return entry.Title
+	}
+	return strings.TrimSuffix(filepath.Base(entry.Location), filepath.Ext(entry.Location))
+}
+
+func oneLine(s string) string {
+	return strings.NewReplacer("\r", " ", "\n", " ").Replace(s)
+}
+
+// RelativeLocation returns target relative to the playlist directory dir,
+// slash-separated, or target itself (absolute) when no relative path exists.
+func RelativeLocation(dir, target string) string {
+	if rel, err := filepath.Rel(dir, target); err == nil {
+		return filepath.ToSlash(rel)
+	}
+	if abs, err := filepath.Abs(target); err == nil {
+		return filepath.ToSlash(abs)
+	}
+	return filepath.ToSlash(target)
+}
+
+// ReadM3U8File reads the M3U8 playlist at path.
+func ReadM3U8File(path string) ([]Entry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ReadM3U8(f)
+}
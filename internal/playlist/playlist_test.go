@@ -0,0 +1,108 @@
+package playlist
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jmagar/nugs-cli/internal/model"
+)
+
+func TestM3U8RoundTrip(t *testing.T) {
+	entries := []Entry{
+		{Location: "01. Tweezer.flac", Title: "Tweezer", Artist: "Phish", Duration: 1200},
+		{Location: "Set 2/02. Line\nBreak.flac", Title: "Line\nBreak"},
+	}
+	var buf bytes.Buffer
+	if err := WriteM3U8(&buf, entries); err != nil {
+		t.Fatal(err)
+	}
+	want := "#EXTM3U\n#EXTINF:1200,Phish - Tweezer\n01. Tweezer.flac\n#EXTINF:-1,Line Break\nSet 2/02. Line Break.flac\n"
+	if buf.String() != want {
+		t.Fatalf("WriteM3U8 =\n%s\nwant\n%s", buf.String(), want)
+	}
+
+	got, err := ReadM3U8(strings.NewReader("\ufeff" + buf.String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0].Location != "01. Tweezer.flac" || got[0].Title != "Phish - Tweezer" || got[0].Duration != 1200 || got[1].Duration != 0 {
+		t.Fatalf("ReadM3U8 = %+v", got)
+	}
+}
+
+func TestWriteXSPF(t *testing.T) {
+	var buf bytes.Buffer
+	err := WriteXSPF(&buf, "Rock & Roll", []Entry{
+		{Location: "Show #1/01. Song?.flac", Title: "Song?", Artist: "Goose", Album: "Live", Duration: 61},
+		{Location: "/music/Goose/02. Other.flac"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		"<title>Rock &amp; Roll</title>",
+		"<location>Show%20%231/01.%20Song%3F.flac</location>",
+		"<duration>61000</duration>",
+		"<location>file:///music/Goose/02.%20Other.flac</location>",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("XSPF missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestFormats(t *testing.T) {
+	if got := Formats(&model.Config{}); !slices.Equal(got, []string{FormatM3U8}) {
+		t.Errorf("default formats = %v", got)
+	}
+	if got := Formats(&model.Config{PlaylistFormats: []string{"none"}}); got != nil {
+		t.Errorf("none formats = %v", got)
+	}
+	for _, bad := range [][]string{{"pls"}, {"none", "m3u8"}} {
+		if err := ValidateFormats(bad); err == nil {
+			t.Errorf("ValidateFormats(%v) accepted", bad)
+		}
+	}
+	if err := ValidateFormats([]string{"m3u8", "xspf"}); err != nil {
+		t.Errorf("ValidateFormats rejected m3u8+xspf: %v", err)
+	}
+}
+
+func TestWriteCue(t *testing.T) {
+	var buf bytes.Buffer
+	err := WriteCue(&buf, CueSheet{
+		File:      "Phish - Set 2.flac",
+		Title:     `Live "Baker's Dozen"`,
+		Performer: "Phish",
+		Date:      "2017-07-21",
+		Comments:  []string{"nugs.net 12345"},
+		Tracks: []CueTrack{
+			{Title: "Tweezer", Performer: "Phish"},
+			{Title: "Ghost", Performer: "Phish", Start: 101*time.Minute + 2*time.Second + 500*time.Millisecond},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `REM DATE 2017-07-21
+REM COMMENT "nugs.net 12345"
+PERFORMER "Phish"
+TITLE "Live 'Baker's Dozen'"
+FILE "Phish - Set 2.flac" WAVE
+  TRACK 01 AUDIO
+    TITLE "Tweezer"
+    PERFORMER "Phish"
+    INDEX 01 00:00:00
+  TRACK 02 AUDIO
+    TITLE "Ghost"
+    PERFORMER "Phish"
+    INDEX 01 101:02:37
+`
+	if buf.String() != want {
+		t.Fatalf("WriteCue =\n%s\nwant\n%s", buf.String(), want)
+	}
+}
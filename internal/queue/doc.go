@@ -0,0 +1,4 @@
+// Package queue implements the durable download queue behind `nugs queue`:
+// items live in a JSON file under the cache directory until they succeed,
+// failures retry with exponential backoff, and a single worker drains them.
+package queue
@@ -0,0 +1,66 @@
+package queue
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/jmagar/nugs-cli/internal/ui"
+)
+
+// listJSON is the JSON shape of `nugs queue list`.
+type listJSON struct {
+	Items []Item `json:"items"`
+	Total int    `json:"total"`
+}
+
+// PrintItems prints the queue as a table, or as JSON when jsonLevel is set.
+func PrintItems(items []Item, jsonLevel string) error {
+	if jsonLevel != "" {
+		if items == nil {
+			items = []Item{}
+		}
+		data, err := json.MarshalIndent(listJSON{Items: items, Total: len(items)}, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+	if len(items) == 0 {
+		fmt.Println("Queue is empty. Add a release with: nugs queue add <id|url>")
+		return nil
+	}
+
+	ui.PrintHeader("Download Queue")
+	table := ui.NewTable([]ui.TableColumn{
+		{Header: "ID", Width: 5, Align: "right"},
+		{Header: "State", Width: 8, Align: "left"},
+		{Header: "Tries", Width: 5, Align: "right"},
+		{Header: "Next Try", Width: 19, Align: "left"},
+		{Header: "Media", Width: 6, Align: "left"},
+		{Header: "URL", Width: 50, Align: "left"},
+	})
+	for _, item := range items {
+		next := "now"
+		switch {
+		case item.State == StateFailed:
+			next = "-"
+		case !item.NextAttemptAt.IsZero() && item.NextAttemptAt.After(time.Now()):
+			next = item.NextAttemptAt.Local().Format(time.DateTime)
+		}
+		media := item.Media
+		if media == "" {
+			media = "default"
+		}
+		table.AddRow(strconv.Itoa(item.ID), item.State, strconv.Itoa(item.Attempts), next, media, item.URL)
+	}
+	table.Print()
+	for _, item := range items {
+		if item.LastError != "" {
+			fmt.Printf("  %s#%d%s %s\n", ui.ColorYellow, item.ID, ui.ColorReset, item.LastError)
+		}
+	}
+	return nil
+}
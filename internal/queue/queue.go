@@ -0,0 +1,327 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/jmagar/nugs-cli/internal/cache"
+)
+
+// Item states. Pending items are retried until they succeed or exhaust
+// MaxAttempts, after which they are parked as failed.
+const (
+	StatePending = "pending"
+	StateFailed  = "failed"
+)
+
+const (
+	// MaxAttempts is how many times an item is tried before it is parked.
+	MaxAttempts = 5
+
+	baseBackoff = time.Minute
+	maxBackoff  = time.Hour
+
+	// retryPoll is how often a worker waiting for a retry rechecks pause,
+	// cancel and newly added items.
+	retryPoll = 5 * time.Second
+
+	queueFileName  = "queue.json"
+	queueLockName  = ".queue.lock"
+	workerLockName = ".queue-worker.lock"
+)
+
+var (
+	// ErrNotFound is returned when an item ID is not in the queue.
+	ErrNotFound = errors.New("queue item not found")
+	// ErrWorkerRunning is returned when another process is draining the queue.
+	ErrWorkerRunning = errors.New("another queue worker is already running")
+)
+
+var queueMu sync.Mutex
+
+// Item is one queued download.
+type Item struct {
+	ID            int       `json:"id"`
+	URL           string    `json:"url"`
+	Media         string    `json:"media,omitempty"` // audio, video, both; empty uses defaultOutputs
+	State         string    `json:"state"`
+	Attempts      int       `json:"attempts"`
+	AddedAt       time.Time `json:"addedAt"`
+	NextAttemptAt time.Time `json:"nextAttemptAt,omitzero"`
+	LastError     string    `json:"lastError,omitempty"`
+}
+
+type queueFile struct {
+	NextID int    `json:"nextId"`
+	Items  []Item `json:"items"`
+}
+
+// Queue is the on-disk queue in one directory.
+type Queue struct {
+	dir   string
+	now   func() time.Time
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+// Open returns the queue kept in dir.
+func Open(dir string) *Queue {
+	return &Queue{dir: dir, now: time.Now, sleep: sleepContext}
+}
+
+// Default returns the queue in the nugs cache directory.
+func Default() (*Queue, error) {
+	cacheDir, err := cache.GetCacheDir()
+	if err != nil {
+		return nil, err
+	}
+	return Open(cacheDir), nil
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// Backoff returns the delay before retrying an item that has failed
+// attempts times: one minute, doubling up to an hour.
+func Backoff(attempts int) time.Duration {
+	if attempts < 1 {
+		return 0
+	}
+	delay := baseBackoff
+	for i := 1; i < attempts && delay < maxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, maxBackoff)
+}
+
+func (q *Queue) path() string {
+	return filepath.Join(q.dir, queueFileName)
+}
+
+func (q *Queue) read() (*queueFile, error) {
+	data, err := os.ReadFile(q.path())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &queueFile{NextID: 1}, nil
+		}
+		return nil, fmt.Errorf("failed to read queue: %w", err)
+	}
+	var f queueFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("failed to parse queue %s: %w", q.path(), err)
+	}
+	if f.NextID < 1 {
+		f.NextID = 1
+	}
+	for _, item := range f.Items {
+		f.NextID = max(f.NextID, item.ID+1)
+	}
+	return &f, nil
+}
+
+func (q *Queue) withLock(fn func() error) error {
+	queueMu.Lock()
+	defer queueMu.Unlock()
+	lock, err := cache.AcquireLock(filepath.Join(q.dir, queueLockName), 50)
+	if err != nil {
+		return fmt.Errorf("failed to acquire queue lock: %w", err)
+	}
+	defer func() {
+		if releaseErr := lock.Release(); releaseErr != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to release queue lock: %v\n", releaseErr)
+		}
+	}()
+	return fn()
+}
+
+// view passes the current queue to fn under the queue lock.
+func (q *Queue) view(fn func(f *queueFile)) error {
+	return q.withLock(func() error {
+		f, err := q.read()
+		if err != nil {
+			return err
+		}
+		fn(f)
+		return nil
+	})
+}
+
+// update applies fn to the queue under the queue lock and writes the result
+// atomically so a crash never leaves a half-written file.
+func (q *Queue) update(fn func(f *queueFile) error) error {
+	return q.withLock(func() error {
+		f, err := q.read()
+		if err != nil {
+			return err
+		}
+		if err := fn(f); err != nil {
+			return err
+		}
+		data, err := json.MarshalIndent(f, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal queue: %w", err)
+		}
+		return cache.WriteFileAtomic(q.path(), data, 0600)
+	})
+}
+
+func (f *queueFile) index(id int) int {
+	for i, item := range f.Items {
+		if item.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// Items returns the queue in run order.
+func (q *Queue) Items() ([]Item, error) {
+	var items []Item
+	err := q.view(func(f *queueFile) {
+		items = append([]Item{}, f.Items...)
+	})
+	return items, err
+}
+
+// Add appends urls to the end of the queue. A URL already queued is not
+// duplicated; if it had been parked as failed it is reset to pending.
+func (q *Queue) Add(media string, urls ...string) ([]Item, error) {
+	var added []Item
+	err := q.update(func(f *queueFile) error {
+		for _, url := range urls {
+			found := false
+			for i := range f.Items {
+				if f.Items[i].URL != url || f.Items[i].Media != media {
+					continue
+				}
+				found = true
+				if f.Items[i].State == StateFailed {
+					f.Items[i].State = StatePending
+					f.Items[i].Attempts = 0
+					f.Items[i].NextAttemptAt = time.Time{}
+				}
+				added = append(added, f.Items[i])
+			}
+			if found {
+				continue
+			}
+			item := Item{
+				ID:      f.NextID,
+				URL:     url,
+				Media:   media,
+				State:   StatePending,
+				AddedAt: q.now().UTC(),
+			}
+			f.NextID++
+			f.Items = append(f.Items, item)
+			added = append(added, item)
+		}
+		return nil
+	})
+	return added, err
+}
+
+// Remove deletes an item from the queue.
+func (q *Queue) Remove(id int) error {
+	return q.update(func(f *queueFile) error {
+		i := f.index(id)
+		if i < 0 {
+			return fmt.Errorf("%w: %d", ErrNotFound, id)
+		}
+		f.Items = append(f.Items[:i], f.Items[i+1:]...)
+		return nil
+	})
+}
+
+// Move places an item at a 1-based position; positions past either end
+// are clamped.
+func (q *Queue) Move(id, position int) error {
+	return q.update(func(f *queueFile) error {
+		i := f.index(id)
+		if i < 0 {
+			return fmt.Errorf("%w: %d", ErrNotFound, id)
+		}
+		item := f.Items[i]
+		f.Items = append(f.Items[:i], f.Items[i+1:]...)
+		to := min(max(position-1, 0), len(f.Items))
+		f.Items = append(f.Items[:to], append([]Item{item}, f.Items[to:]...)...)
+		return nil
+	})
+}
+
+// next returns the first pending item that is due. When pending items exist
+// but all are backing off, it returns the earliest time one becomes due.
+func (q *Queue) next() (Item, bool, time.Time, error) {
+	var (
+		item     Item
+		found    bool
+		earliest time.Time
+	)
+	now := q.now()
+	err := q.view(func(f *queueFile) {
+		for _, candidate := range f.Items {
+			if candidate.State != StatePending {
+				continue
+			}
+			if !candidate.NextAttemptAt.After(now) {
+				item, found = candidate, true
+				return
+			}
+			if earliest.IsZero() || candidate.NextAttemptAt.Before(earliest) {
+				earliest = candidate.NextAttemptAt
+			}
+		}
+	})
+	return item, found, earliest, err
+}
+
+// complete removes a successfully downloaded item.
+func (q *Queue) complete(id int) error {
+	err := q.Remove(id)
+	if errors.Is(err, ErrNotFound) {
+		return nil // removed by the user while it ran
+	}
+	return err
+}
+
+// fail records a failed attempt and schedules the retry, parking the item
+// once it reaches MaxAttempts. It reports false when the item was removed
+// while it ran, leaving nothing to retry.
+func (q *Queue) fail(id int, cause error) (Item, bool, error) {
+	var (
+		updated Item
+		found   bool
+	)
+	err := q.update(func(f *queueFile) error {
+		i := f.index(id)
+		if i < 0 {
+			return nil
+		}
+		found = true
+		item := &f.Items[i]
+		item.Attempts++
+		item.LastError = cause.Error()
+		if item.Attempts >= MaxAttempts {
+			item.State = StateFailed
+			item.NextAttemptAt = time.Time{}
+		} else {
+			item.NextAttemptAt = q.now().Add(Backoff(item.Attempts)).UTC()
+		}
+		updated = *item
+		return nil
+	})
+	return updated, found, err
+}
@@ -0,0 +1,210 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func testQueue(t *testing.T) (*Queue, *time.Time) {
+	t.Helper()
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	q := Open(t.TempDir())
+	q.now = func() time.Time { return now }
+	q.sleep = func(_ context.Context, d time.Duration) error {
+		now = now.Add(d)
+		return nil
+	}
+	return q, &now
+}
+
+func itemURLs(t *testing.T, q *Queue) []string {
+	t.Helper()
+	items, err := q.Items()
+	if err != nil {
+		t.Fatalf("Items: %v", err)
+	}
+	urls := make([]string, 0, len(items))
+	for _, item := range items {
+		urls = append(urls, item.URL)
+	}
+	return urls
+}
+
+func TestQueueAddDedupesAndReorders(t *testing.T) {
+	q, _ := testQueue(t)
+	added, err := q.Add("", "100", "200", "300")
+	if err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if len(added) != 3 || added[0].ID != 1 || added[2].ID != 3 {
+		t.Fatalf("Add returned %+v", added)
+	}
+	again, err := q.Add("", "200")
+	if err != nil || len(again) != 1 || again[0].ID != 2 {
+		t.Fatalf("re-Add = %+v, %v; want existing item 2", again, err)
+	}
+	if _, err := q.Add("video", "200"); err != nil {
+		t.Fatalf("Add video: %v", err)
+	}
+
+	if err := q.Move(3, 1); err != nil {
+		t.Fatalf("Move: %v", err)
+	}
+	if err := q.Move(1, 99); err != nil {
+		t.Fatalf("Move past end: %v", err)
+	}
+	if err := q.Remove(4); err != nil {
+		t.Fatalf("Remove: %v", err)
+	}
+	if err := q.Remove(4); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Remove missing = %v, want ErrNotFound", err)
+	}
+	got := itemURLs(t, q)
+	want := []string{"300", "200", "100"}
+	if len(got) != len(want) {
+		t.Fatalf("queue = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("queue = %v, want %v", got, want)
+		}
+	}
+
+	if added, err := q.Add("", "400"); err != nil || added[0].ID != 5 {
+		t.Fatalf("Add after remove = %+v, %v; want ID 5", added, err)
+	}
+}
+
+func TestQueueRunRetriesWithBackoffAndParks(t *testing.T) {
+	q, now := testQueue(t)
+	start := *now
+	if _, err := q.Add("", "good", "bad"); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+
+	var calls []string
+	summary, err := q.Run(context.Background(), &Deps{
+		Process: func(_ context.Context, item Item) error {
+			calls = append(calls, item.URL)
+			if item.URL == "bad" {
+				return errors.New("boom")
+			}
+			return nil
+		},
+	})
+	if err == nil {
+		t.Fatal("Run returned nil error with a parked item")
+	}
+	if summary.Completed != 1 || summary.Retries != MaxAttempts-1 || summary.Failed != 1 {
+		t.Fatalf("summary = %+v", summary)
+	}
+	if len(calls) != 1+MaxAttempts || calls[0] != "good" {
+		t.Fatalf("calls = %v", calls)
+	}
+	// 1m + 2m + 4m + 8m of backoff before the fifth attempt.
+	if waited := now.Sub(start); waited != 15*time.Minute {
+		t.Fatalf("waited %s, want 15m", waited)
+	}
+
+	items, err := q.Items()
+	if err != nil {
+		t.Fatalf("Items: %v", err)
+	}
+	if len(items) != 1 || items[0].State != StateFailed || items[0].Attempts != MaxAttempts || items[0].LastError != "boom" {
+		t.Fatalf("items = %+v, want one parked item", items)
+	}
+
+	readded, err := q.Add("", "bad")
+	if err != nil || readded[0].State != StatePending || readded[0].Attempts != 0 {
+		t.Fatalf("re-Add failed item = %+v, %v; want reset", readded, err)
+	}
+}
+
+func TestQueueRunCancelKeepsItem(t *testing.T) {
+	q, _ := testQueue(t)
+	if _, err := q.Add("", "100", "200"); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	errCancelled := errors.New("cancelled")
+	summary, err := q.Run(context.Background(), &Deps{
+		Process: func(_ context.Context, item Item) error {
+			return errCancelled
+		},
+		IsCrawlCancelledErr: func(err error) bool { return errors.Is(err, errCancelled) },
+	})
+	if !errors.Is(err, errCancelled) {
+		t.Fatalf("Run error = %v, want cancellation", err)
+	}
+	if summary != (RunSummary{}) {
+		t.Fatalf("summary = %+v, want zero", summary)
+	}
+	items, err := q.Items()
+	if err != nil {
+		t.Fatalf("Items: %v", err)
+	}
+	if len(items) != 2 || items[0].Attempts != 0 {
+		t.Fatalf("items = %+v, want both untouched", items)
+	}
+}
+
+func TestQueueRunSkipsRetryForRemovedItem(t *testing.T) {
+	q, _ := testQueue(t)
+	if _, err := q.Add("", "100"); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	summary, err := q.Run(context.Background(), &Deps{
+		Process: func(_ context.Context, item Item) error {
+			if err := q.Remove(item.ID); err != nil {
+				t.Fatalf("Remove: %v", err)
+			}
+			return errors.New("boom")
+		},
+	})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if summary != (RunSummary{}) {
+		t.Fatalf("summary = %+v, want no retries for a removed item", summary)
+	}
+}
+
+func TestQueueRunCancelDuringBackoff(t *testing.T) {
+	q, now := testQueue(t)
+	start := *now
+	if _, err := q.Add("", "bad"); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	errCancelled := errors.New("cancelled")
+	_, err := q.Run(context.Background(), &Deps{
+		Process: func(context.Context, Item) error { return errors.New("boom") },
+		WaitIfPausedOrCancelled: func(context.Context) error {
+			if now.Sub(start) >= retryPoll {
+				return errCancelled
+			}
+			return nil
+		},
+	})
+	if !errors.Is(err, errCancelled) {
+		t.Fatalf("Run error = %v, want cancellation", err)
+	}
+	if waited := now.Sub(start); waited != retryPoll {
+		t.Fatalf("waited %s before noticing the cancel, want %s", waited, retryPoll)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	for attempts, want := range map[int]time.Duration{
+		0:  0,
+		1:  time.Minute,
+		2:  2 * time.Minute,
+		4:  8 * time.Minute,
+		7:  time.Hour,
+		20: time.Hour,
+	} {
+		if got := Backoff(attempts); got != want {
+			t.Errorf("Backoff(%d) = %s, want %s", attempts, got, want)
+		}
+	}
+}
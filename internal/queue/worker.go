@@ -0,0 +1,119 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/jmagar/nugs-cli/internal/cache"
+	"github.com/jmagar/nugs-cli/internal/ui"
+)
+
+// Deps holds the callbacks the worker needs from the command layer.
+type Deps struct {
+	// Process downloads one item through the normal grab entry points.
+	Process func(ctx context.Context, item Item) error
+
+	// WaitIfPausedOrCancelled blocks while the crawl is paused and returns
+	// ErrCrawlCancelled once it is cancelled, or ctx's error when ctx ends.
+	WaitIfPausedOrCancelled func(ctx context.Context) error
+
+	// IsCrawlCancelledErr checks if an error is a crawl cancellation error.
+	IsCrawlCancelledErr func(error) bool
+}
+
+// RunSummary counts the outcome of a Run.
+type RunSummary struct {
+	Completed int `json:"completed"`
+	Retries   int `json:"retries"` // failed attempts that were rescheduled
+	Failed    int `json:"failed"`  // items parked after MaxAttempts
+}
+
+// Run drains the queue with a single worker. Items are processed in queue
+// order; a failed item is rescheduled with backoff and the worker waits for
+// it when nothing else is due. Run returns once no pending items remain, or
+// when the crawl is cancelled, leaving the current item queued.
+func (q *Queue) Run(ctx context.Context, deps *Deps) (RunSummary, error) {
+	var summary RunSummary
+	if deps == nil || deps.Process == nil {
+		return summary, fmt.Errorf("queue worker has no download handler")
+	}
+	lock, err := cache.AcquireLock(filepath.Join(q.dir, workerLockName), 0)
+	if err != nil {
+		return summary, fmt.Errorf("%w: %v", ErrWorkerRunning, err)
+	}
+	defer func() {
+		if releaseErr := lock.Release(); releaseErr != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to release queue worker lock: %v\n", releaseErr)
+		}
+	}()
+
+	cancelled := func(err error) bool {
+		return ctx.Err() != nil || (deps.IsCrawlCancelledErr != nil && deps.IsCrawlCancelledErr(err))
+	}
+	var announced time.Time
+	for {
+		if deps.WaitIfPausedOrCancelled != nil {
+			if err := deps.WaitIfPausedOrCancelled(ctx); err != nil {
+				return summary, err
+			}
+		}
+		if err := ctx.Err(); err != nil {
+			return summary, err
+		}
+		item, ok, wakeAt, err := q.next()
+		if err != nil {
+			return summary, err
+		}
+		if !ok {
+			if wakeAt.IsZero() {
+				break
+			}
+			if !wakeAt.Equal(announced) {
+				ui.PrintInfo(fmt.Sprintf("Waiting until %s for the next retry", wakeAt.Local().Format(time.DateTime)))
+				announced = wakeAt
+			}
+			// Sleep in short steps so a pause, cancel or newly queued item
+			// is noticed before a long backoff ends.
+			if err := q.sleep(ctx, min(wakeAt.Sub(q.now()), retryPoll)); err != nil {
+				return summary, err
+			}
+			continue
+		}
+
+		fmt.Printf("\n%s%s Queue item %d%s %s\n", ui.ColorBold, ui.SymbolPackage, item.ID, ui.ColorReset, item.URL)
+		processErr := deps.Process(ctx, item)
+		if processErr == nil {
+			if err := q.complete(item.ID); err != nil {
+				return summary, err
+			}
+			summary.Completed++
+			continue
+		}
+		if cancelled(processErr) {
+			return summary, processErr
+		}
+		updated, found, err := q.fail(item.ID, processErr)
+		if err != nil {
+			return summary, err
+		}
+		if !found {
+			ui.PrintWarning(fmt.Sprintf("Queue item %d failed after it was removed from the queue: %v", item.ID, processErr))
+			continue
+		}
+		if updated.State == StateFailed {
+			summary.Failed++
+			ui.PrintError(fmt.Sprintf("Queue item %d failed %d times, giving up: %v", item.ID, updated.Attempts, processErr))
+		} else {
+			summary.Retries++
+			ui.PrintWarning(fmt.Sprintf("Queue item %d failed (attempt %d of %d), retrying in %s: %v",
+				item.ID, updated.Attempts, MaxAttempts, Backoff(updated.Attempts), processErr))
+		}
+	}
+	if summary.Failed > 0 {
+		return summary, fmt.Errorf("%d queue items failed after %d attempts", summary.Failed, MaxAttempts)
+	}
+	return summary, nil
+}
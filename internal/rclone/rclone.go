@@ -114,20 +114,23 @@ func UploadToRclone(ctx context.Context, localPath string, artistFolder string,
 	})
 }
 
+// RemoteUploadPath returns the rclone destination an upload of localPath is
+// copied to: <remote>:<base>/<artistFolder>/<basename>.
+func RemoteUploadPath(localPath, artistFolder string, cfg *model.Config, isVideo bool) string {
+	remoteParentPath := cfg.RcloneRemote + ":" + helpers.GetRcloneBasePath(cfg, isVideo)
+	if artistFolder != "" {
+		remoteParentPath += "/" + artistFolder
+	}
+	return remoteParentPath + "/" + filepath.Base(localPath)
+}
+
 // BuildRcloneUploadCommandContext constructs the rclone copy/copyto command with context support.
 func BuildRcloneUploadCommandContext(ctx context.Context, localPath, artistFolder string, cfg *model.Config, transfers int, isVideo bool) (*exec.Cmd, string, error) {
-	remoteDest := cfg.RcloneRemote + ":" + helpers.GetRcloneBasePath(cfg, isVideo)
-
 	localInfo, err := os.Stat(localPath)
 	if err != nil {
 		return nil, "", fmt.Errorf("failed to stat local path: %w", err)
 	}
-
-	remoteParentPath := remoteDest
-	if artistFolder != "" {
-		remoteParentPath += "/" + artistFolder
-	}
-	remoteFullPath := remoteParentPath + "/" + filepath.Base(localPath)
+	remoteFullPath := RemoteUploadPath(localPath, artistFolder, cfg, isVideo)
 
 	transfersFlag := fmt.Sprintf("--transfers=%d", transfers)
 	statsFlags := []string{"--progress", "--stats=1s", "--stats-one-line"}
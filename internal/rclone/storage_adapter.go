@@ -5,6 +5,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io/fs"
 	"os"
 	"os/exec"
 	"strings"
@@ -12,6 +13,8 @@ import (
 	"time"
 
 	"github.com/jmagar/nugs-cli/internal/helpers"
+	"github.com/jmagar/nugs-cli/internal/history"
+	"github.com/jmagar/nugs-cli/internal/manifest"
 	"github.com/jmagar/nugs-cli/internal/model"
 	"github.com/jmagar/nugs-cli/internal/ui"
 )
@@ -69,6 +72,8 @@ type StorageAdapter struct {
 	command            func(name string, args ...string) *exec.Cmd
 	commandContext     func(ctx context.Context, name string, args ...string) *exec.Cmd
 	exitCode           func(err error) (int, bool)
+	recordUpload       func(cfg *model.Config, localPath, remotePath string) error
+	loadManifest       func(localPath string) (*manifest.Manifest, error)
 }
 
 // NewStorageAdapter creates an rclone-backed storage adapter.
@@ -88,9 +93,25 @@ func NewStorageAdapter() *StorageAdapter {
 		command:        exec.Command,
 		commandContext: exec.CommandContext,
 		exitCode:       parseExitCode,
+		recordUpload:   recordUploadHistory,
+		loadManifest:   manifest.ForUpload,
 	}
 }
 
+// recordUploadHistory attaches the remote path to the history entries for
+// localPath.
+func recordUploadHistory(cfg *model.Config, localPath, remotePath string) error {
+	if cfg.SkipHistory {
+		return nil
+	}
+	store, err := history.Default()
+	if err != nil {
+		return err
+	}
+	_, err = store.RecordUpload(localPath, remotePath, time.Time{})
+	return err
+}
+
 func parseExitCode(err error) (int, bool) {
 	var exitErr *exec.ExitError
 	if errors.As(err, &exitErr) {
@@ -160,6 +181,11 @@ func (a *StorageAdapter) Upload(ctx context.Context, cfg *model.Config, req mode
 		return fmt.Errorf("rclone upload failed: %w", err)
 	}
 
+	if a.recordUpload != nil {
+		if err := a.recordUpload(cfg, req.LocalPath, remoteFullPath); err != nil {
+			ui.PrintWarning(fmt.Sprintf("Failed to record upload in download history: %v", err))
+		}
+	}
 	if hooks.OnComplete != nil {
 		hooks.OnComplete()
 	}
@@ -174,16 +200,8 @@ func (a *StorageAdapter) Upload(ctx context.Context, cfg *model.Config, req mode
 		ui.PrintInfo("Verifying upload integrity...")
 	}
 
-	verifyCmd, err := a.buildVerifyCommand(ctx, req.LocalPath, remoteFullPath)
-	if err != nil {
-		return fmt.Errorf("failed to build upload verification command: %w", err)
-	}
-	var verifyOut, verifyErr bytes.Buffer
-	verifyCmd.Stdout = &verifyOut
-	verifyCmd.Stderr = &verifyErr
-	if err := a.runCommand(verifyCmd); err != nil {
-		return fmt.Errorf("upload verification failed - NOT deleting local files: %w\nOutput: %s\nErrors: %s",
-			err, verifyOut.String(), verifyErr.String())
+	if err := a.verify(ctx, req.LocalPath, remoteFullPath); err != nil {
+		return fmt.Errorf("upload verification failed - NOT deleting local files: %w", err)
 	}
 
 	if progressFn == nil {
@@ -202,6 +220,173 @@ func (a *StorageAdapter) Upload(ctx context.Context, cfg *model.Config, req mode
 	return nil
 }
 
+// verify runs rclone check between the local path and its uploaded copy,
+// then compares remote hashes with the show's checksum manifest.
+func (a *StorageAdapter) verify(ctx context.Context, localPath, remoteFullPath string) error {
+	verifyCmd, err := a.buildVerifyCommand(ctx, localPath, remoteFullPath)
+	if err != nil {
+		return fmt.Errorf("failed to build upload verification command: %w", err)
+	}
+	var verifyOut, verifyErr bytes.Buffer
+	verifyCmd.Stdout = &verifyOut
+	verifyCmd.Stderr = &verifyErr
+	if err := a.runCommand(verifyCmd); err != nil {
+		return fmt.Errorf("%w\nOutput: %s\nErrors: %s", err, verifyOut.String(), verifyErr.String())
+	}
+	m, err := a.loadManifest(localPath)
+	if err != nil {
+		return fmt.Errorf("failed to load checksum manifest: %w", err)
+	}
+	hashes, err := a.remoteHashes(ctx, remoteFullPath, m.Names())
+	if err != nil {
+		return err
+	}
+	return manifest.Err(manifest.Check(m, hashes))
+}
+
+// hashsumAttempts are the rclone hashsum variants tried in order: native
+// SHA-256, native MD5, then SHA-256 computed by downloading the files.
+var hashsumAttempts = []struct {
+	algo     string
+	download bool
+}{
+	{manifest.AlgoSHA256, false},
+	{manifest.AlgoMD5, false},
+	{manifest.AlgoSHA256, true},
+}
+
+// remoteHashes hashes target (a file or directory) with rclone hashsum and
+// returns the digests of names, relative to a directory target or the base
+// name of a file target. The first variant that yields a valid digest for
+// every present file wins.
+func (a *StorageAdapter) remoteHashes(ctx context.Context, target string, names []string) (map[string]model.FileHash, error) {
+	var lastErr error
+	for _, attempt := range hashsumAttempts {
+		args := []string{"hashsum", attempt.algo, target}
+		if attempt.download {
+			args = append(args, "--download")
+		}
+		output, err := a.outputCommand(a.commandContext(ctx, "rclone", args...))
+		if err != nil {
+			if code, ok := a.exitCode(err); ok && code == 3 {
+				return map[string]model.FileHash{}, nil
+			}
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return nil, ctxErr
+			}
+			lastErr = fmt.Errorf("rclone hashsum %s: %w", attempt.algo, err)
+			continue
+		}
+		hashes, complete := parseHashsum(output, attempt.algo, names)
+		if complete {
+			return hashes, nil
+		}
+		lastErr = fmt.Errorf("remote does not support %s hashes", attempt.algo)
+	}
+	return nil, fmt.Errorf("failed to hash remote files: %w", lastErr)
+}
+
+// parseHashsum reads "<digest>  <path>" lines for names. complete is false
+// when a listed file has no usable digest, e.g. "UNSUPPORTED".
+func parseHashsum(output []byte, algo string, names []string) (map[string]model.FileHash, bool) {
+	wanted := make(map[string]struct{}, len(names))
+	for _, name := range names {
+		wanted[name] = struct{}{}
+	}
+	want := 64
+	if algo == manifest.AlgoMD5 {
+		want = 32
+	}
+	hashes := make(map[string]model.FileHash, len(names))
+	for _, line := range strings.Split(string(output), "\n") {
+		digest, name, ok := strings.Cut(strings.TrimRight(line, "\r"), "  ")
+		if !ok {
+			continue
+		}
+		if _, ok := wanted[name]; !ok {
+			continue
+		}
+		if !isHexDigest(digest, want) {
+			return nil, false
+		}
+		hashes[name] = model.FileHash{Algo: algo, Value: strings.ToLower(digest)}
+	}
+	return hashes, true
+}
+
+func isHexDigest(s string, length int) bool {
+	if len(s) != length {
+		return false
+	}
+	for _, c := range s {
+		if !strings.ContainsRune("0123456789abcdefABCDEF", c) {
+			return false
+		}
+	}
+	return true
+}
+
+// ReadFile implements model.StorageReader with rclone cat.
+func (a *StorageAdapter) ReadFile(ctx context.Context, cfg *model.Config, remotePath string, isVideo bool) ([]byte, error) {
+	if err := a.validatePath(remotePath); err != nil {
+		return nil, fmt.Errorf("invalid remote path: %w", err)
+	}
+	if ctx == nil {
+		return nil, fmt.Errorf("read context is required")
+	}
+	release, err := acquireRcloneSlot(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("waiting for rclone process slot: %w", err)
+	}
+	defer release()
+	fullPath := cfg.RcloneRemote + ":" + a.getRcloneBasePath(cfg, isVideo) + "/" + remotePath
+	output, err := a.outputCommand(a.commandContext(ctx, "rclone", "cat", fullPath))
+	if err != nil {
+		if code, ok := a.exitCode(err); ok && (code == 3 || code == 4) {
+			return nil, fmt.Errorf("%s: %w", fullPath, fs.ErrNotExist)
+		}
+		return nil, fmt.Errorf("rclone cat %s: %w", fullPath, err)
+	}
+	return output, nil
+}
+
+// FileHashes implements model.StorageReader with rclone hashsum.
+func (a *StorageAdapter) FileHashes(ctx context.Context, cfg *model.Config, remoteDir string, names []string, isVideo bool) (map[string]model.FileHash, error) {
+	if err := a.validatePath(remoteDir); err != nil {
+		return nil, fmt.Errorf("invalid remote path: %w", err)
+	}
+	if ctx == nil {
+		return nil, fmt.Errorf("hash context is required")
+	}
+	release, err := acquireRcloneSlot(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("waiting for rclone process slot: %w", err)
+	}
+	defer release()
+	return a.remoteHashes(ctx, cfg.RcloneRemote+":"+a.getRcloneBasePath(cfg, isVideo)+"/"+remoteDir, names)
+}
+
+// Verify implements model.StorageVerifier.
+func (a *StorageAdapter) Verify(ctx context.Context, cfg *model.Config, req model.UploadRequest) error {
+	if err := a.validatePath(req.LocalPath); err != nil {
+		return fmt.Errorf("invalid local path: %w", err)
+	}
+	if req.ArtistFolder != "" {
+		if err := a.validatePath(req.ArtistFolder); err != nil {
+			return fmt.Errorf("invalid artist folder: %w", err)
+		}
+	}
+	if ctx == nil {
+		return fmt.Errorf("verify context is required")
+	}
+	release, err := acquireRcloneSlot(ctx)
+	if err != nil {
+		return fmt.Errorf("waiting for rclone process slot: %w", err)
+	}
+	defer release()
+	return a.verify(ctx, req.LocalPath, RemoteUploadPath(req.LocalPath, req.ArtistFolder, cfg, req.IsVideo))
+}
+
 // PathExists implements model.StorageProvider.
 func (a *StorageAdapter) PathExists(ctx context.Context, cfg *model.Config, remotePath string, isVideo bool) (bool, error) {
 	if !cfg.RcloneEnabled {
@@ -4,8 +4,10 @@ import (
 	"context"
 	"errors"
 	"os/exec"
+	"strings"
 	"testing"
 
+	"github.com/jmagar/nugs-cli/internal/manifest"
 	"github.com/jmagar/nugs-cli/internal/model"
 )
 
@@ -42,11 +44,28 @@ func TestStorageAdapterUploadWithHooksAndDelete(t *testing.T) {
 		return exec.CommandContext(ctx, "echo"), nil
 	}
 	adapter.runCommand = func(*exec.Cmd) error { return nil }
+	adapter.loadManifest = func(string) (*manifest.Manifest, error) {
+		return &manifest.Manifest{Files: []manifest.File{{Path: "01.flac", SHA256: testSHA256}}}, nil
+	}
+	adapter.outputCommand = func(cmd *exec.Cmd) ([]byte, error) {
+		if got := strings.Join(cmd.Args[1:], " "); got != "hashsum sha256 remote:path/artist/local" {
+			t.Fatalf("hash command = %q", got)
+		}
+		return []byte(testSHA256 + "  01.flac\n"), nil
+	}
 	deletedPath := ""
 	adapter.removeAll = func(path string) error {
 		deletedPath = path
 		return nil
 	}
+	recordedRemote := ""
+	adapter.recordUpload = func(_ *model.Config, _, remotePath string) error {
+		if deletedPath != "" {
+			t.Fatal("upload recorded after local files were deleted")
+		}
+		recordedRemote = remotePath
+		return nil
+	}
 
 	preBytes := int64(0)
 	completeCalled := false
@@ -91,6 +110,51 @@ func TestStorageAdapterUploadWithHooksAndDelete(t *testing.T) {
 	if deletedPath != "/tmp/local" {
 		t.Fatalf("deleted path = %q, want /tmp/local", deletedPath)
 	}
+	if recordedRemote != "remote:path/artist/local" {
+		t.Fatalf("recorded remote path = %q, want remote:path/artist/local", recordedRemote)
+	}
+}
+
+const (
+	testSHA256 = "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad"
+	testMD5    = "900150983cd24fb0d6963f7d28e17f72"
+)
+
+func TestStorageAdapterVerifyFallsBackToMD5(t *testing.T) {
+	adapter := NewStorageAdapter()
+	adapter.buildVerifyCommand = func(ctx context.Context, _, _ string) (*exec.Cmd, error) {
+		return exec.CommandContext(ctx, "echo"), nil
+	}
+	adapter.runCommand = func(*exec.Cmd) error { return nil }
+	adapter.loadManifest = func(string) (*manifest.Manifest, error) {
+		return &manifest.Manifest{Files: []manifest.File{
+			{Path: "01.flac", SHA256: testSHA256, MD5: testMD5},
+			{Path: "disc 2/01.flac", SHA256: testSHA256, MD5: testMD5},
+		}}, nil
+	}
+	var algos []string
+	remoteMD5 := testMD5
+	adapter.outputCommand = func(cmd *exec.Cmd) ([]byte, error) {
+		algo := cmd.Args[2]
+		algos = append(algos, algo)
+		if algo == "sha256" {
+			return []byte("UNSUPPORTED  01.flac\nUNSUPPORTED  disc 2/01.flac\n"), nil
+		}
+		return []byte(testMD5 + "  01.flac\n" + remoteMD5 + "  disc 2/01.flac\n" + testMD5 + "  nugs-manifest.json\n"), nil
+	}
+
+	if err := adapter.verify(context.Background(), "/tmp/show", "remote:Music/artist/show"); err != nil {
+		t.Fatalf("verify() error = %v", err)
+	}
+	if strings.Join(algos, ",") != "sha256,md5" {
+		t.Fatalf("hashsum attempts = %v, want sha256 then md5", algos)
+	}
+
+	remoteMD5 = strings.Repeat("0", 32)
+	err := adapter.verify(context.Background(), "/tmp/show", "remote:Music/artist/show")
+	if err == nil || !strings.Contains(err.Error(), "disc 2/01.flac: md5 mismatch") {
+		t.Fatalf("verify() error = %v, want md5 mismatch", err)
+	}
 }
 
 func TestStorageAdapterPathExistsHandlesExitCodeThree(t *testing.T) {
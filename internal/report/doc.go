@@ -0,0 +1,4 @@
+// Package report writes the newline-delimited JSON stream that download
+// commands print with --json: progress samples taken from the progress box,
+// lifecycle events, per-item results and a final run summary.
+package report
@@ -0,0 +1,330 @@
+package report
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/jmagar/nugs-cli/internal/model"
+)
+
+// Record types written besides the lifecycle event types.
+const (
+	RecordProgress    = "progress"
+	RecordItemStarted = "item.started"
+	RecordItemDone    = "item.done"
+	RecordSummary     = "summary"
+)
+
+// progressInterval is how often a progress sample is written while the
+// show, phase and track stay the same.
+const progressInterval = time.Second
+
+// Stream writes one JSON object per line. A nil *Stream is valid and writes
+// nothing. All methods are safe for concurrent use.
+type Stream struct {
+	mu          sync.Mutex
+	enc         *json.Encoder
+	summaryOnly bool
+	currentBox  func() *model.ProgressBoxState
+	now         func() time.Time
+	start       time.Time
+	err         error
+
+	lastProgress   model.ProgressSnapshot
+	lastProgressAt time.Time
+
+	item         int
+	itemURL      string
+	itemFailures int
+
+	summary Summary
+}
+
+// Summary is the last record of a run.
+type Summary struct {
+	Type            string         `json:"type"`
+	Time            time.Time      `json:"time"`
+	Success         bool           `json:"success"`
+	Cancelled       bool           `json:"cancelled"`
+	DurationSeconds float64        `json:"durationSeconds"`
+	Items           ItemCounts     `json:"items"`
+	Shows           ShowCounts     `json:"shows"`
+	Tracks          TrackCounts    `json:"tracks"`
+	Bytes           int64          `json:"bytes"`
+	Uploads         Uploads        `json:"uploads"`
+	Failures        []Failure      `json:"failures"`
+	ErrorClasses    map[string]int `json:"errorClasses"`
+}
+
+// ItemCounts counts the URLs and IDs given on the command line.
+type ItemCounts struct {
+	Total     int `json:"total"`
+	Completed int `json:"completed"`
+	Failed    int `json:"failed"`
+}
+
+// ShowCounts counts finished show downloads; a show with both audio and
+// video counts once per media.
+type ShowCounts struct {
+	Completed int `json:"completed"`
+	Failed    int `json:"failed"`
+}
+
+// TrackCounts counts audio tracks across every show.
+type TrackCounts struct {
+	Downloaded int `json:"downloaded"`
+	Skipped    int `json:"skipped"`
+	Failed     int `json:"failed"`
+}
+
+// Uploads lists every finished upload and counts failed ones.
+type Uploads struct {
+	Completed int      `json:"completed"`
+	Failed    int      `json:"failed"`
+	Results   []Upload `json:"results"`
+}
+
+// Upload is one finished upload.
+type Upload struct {
+	ContainerID int    `json:"containerId,omitempty"`
+	Show        string `json:"show,omitempty"`
+	Media       string `json:"media,omitempty"`
+	Path        string `json:"path"`
+	Remote      string `json:"remote"`
+}
+
+// Failure is one failed show, or an item that failed before any show did.
+type Failure struct {
+	Item        int    `json:"item,omitempty"`
+	URL         string `json:"url,omitempty"`
+	ContainerID int    `json:"containerId,omitempty"`
+	Show        string `json:"show,omitempty"`
+	Media       string `json:"media,omitempty"`
+	ErrorClass  string `json:"errorClass"`
+	Error       string `json:"error"`
+}
+
+type progressRecord struct {
+	Type string    `json:"type"`
+	Time time.Time `json:"time"`
+	model.ProgressSnapshot
+}
+
+// showRecord is a show.done event with the show's totals from the progress
+// box.
+type showRecord struct {
+	model.Event
+	Tracks        int   `json:"tracks"`
+	SkippedTracks int   `json:"skippedTracks"`
+	FailedTracks  int   `json:"failedTracks"`
+	Bytes         int64 `json:"bytes"`
+}
+
+type itemRecord struct {
+	Type       string    `json:"type"`
+	Time       time.Time `json:"time"`
+	Item       int       `json:"item"`
+	Total      int       `json:"total,omitempty"`
+	URL        string    `json:"url"`
+	Success    *bool     `json:"success,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	ErrorClass string    `json:"errorClass,omitempty"`
+}
+
+// New returns a stream writing to w. currentBox returns the active progress
+// box, whose totals are read when a show finishes. With summaryOnly only the
+// final summary is written.
+func New(w io.Writer, currentBox func() *model.ProgressBoxState, summaryOnly bool) *Stream {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	return &Stream{
+		enc:         enc,
+		summaryOnly: summaryOnly,
+		currentBox:  currentBox,
+		now:         time.Now,
+		start:       time.Now(),
+	}
+}
+
+// write encodes v as one line, keeping the first write error for Finish.
+// Caller must hold mu.
+func (s *Stream) write(v any) {
+	if s.err == nil {
+		s.err = s.enc.Encode(v)
+	}
+}
+
+// Progress writes a sample of box. It has the signature of the progress box
+// renderer it replaces, and skips samples that repeat the last one or come
+// within a second of it without a change of show, phase or track.
+func (s *Stream) Progress(box *model.ProgressBoxState) {
+	if s == nil || box == nil || s.summaryOnly {
+		return
+	}
+	snap := box.Snapshot()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	last := s.lastProgress
+	if snap == last {
+		return
+	}
+	milestone := snap.Show != last.Show || snap.Phase != last.Phase || snap.TrackNumber != last.TrackNumber ||
+		snap.CompletedTracks != last.CompletedTracks || snap.SkippedTracks != last.SkippedTracks ||
+		snap.ErrorTracks != last.ErrorTracks || snap.Paused != last.Paused ||
+		snap.Cancelled != last.Cancelled || snap.Complete != last.Complete
+	if !milestone && now.Sub(s.lastProgressAt) < progressInterval {
+		return
+	}
+	s.lastProgress, s.lastProgressAt = snap, now
+	s.write(progressRecord{Type: RecordProgress, Time: now, ProgressSnapshot: snap})
+}
+
+// Event writes a lifecycle event and folds it into the summary. show.done
+// records carry the show's track and byte totals from the progress box.
+func (s *Stream) Event(e model.Event) {
+	if s == nil {
+		return
+	}
+	var box *model.ProgressBoxState
+	if e.Type == model.EventShowDone && s.currentBox != nil {
+		box = s.currentBox()
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch e.Type {
+	case model.EventUploadDone:
+		s.summary.Uploads.Completed++
+		s.summary.Uploads.Results = append(s.summary.Uploads.Results, Upload{
+			ContainerID: e.ContainerID,
+			Show:        e.Show,
+			Media:       e.Media,
+			Path:        e.Path,
+			Remote:      e.Remote,
+		})
+	case model.EventShowDone:
+		record := s.showDone(e, box)
+		if !s.summaryOnly {
+			s.write(record)
+		}
+		return
+	}
+	if !s.summaryOnly {
+		s.write(e)
+	}
+}
+
+// showDone adds a finished show to the summary. Caller must hold mu.
+func (s *Stream) showDone(e model.Event, box *model.ProgressBoxState) showRecord {
+	record := showRecord{Event: e}
+	switch {
+	case e.Media == model.MediaTypeVideo.String():
+		// The box holds the audio totals when a show has both, so a
+		// video's size comes from its file.
+		if info, err := os.Stat(e.Path); err == nil && e.Error == "" {
+			record.Bytes = info.Size()
+		}
+	case box != nil:
+		snap := box.Snapshot()
+		record.Tracks, record.SkippedTracks, record.FailedTracks = snap.CompletedTracks, snap.SkippedTracks, snap.ErrorTracks
+		record.Bytes = snap.Bytes
+	}
+	s.summary.Tracks.Downloaded += record.Tracks
+	s.summary.Tracks.Skipped += record.SkippedTracks
+	s.summary.Tracks.Failed += record.FailedTracks
+	s.summary.Bytes += record.Bytes
+
+	if e.Error == "" {
+		s.summary.Shows.Completed++
+		return record
+	}
+	s.summary.Shows.Failed++
+	if e.ErrorClass == "upload" {
+		s.summary.Uploads.Failed++
+	}
+	s.itemFailures++
+	s.summary.Failures = append(s.summary.Failures, Failure{
+		Item:        s.item,
+		URL:         s.itemURL,
+		ContainerID: e.ContainerID,
+		Show:        e.Show,
+		Media:       e.Media,
+		ErrorClass:  e.ErrorClass,
+		Error:       e.Error,
+	})
+	return record
+}
+
+// ItemStarted marks the start of item n of total.
+func (s *Stream) ItemStarted(n, total int, url string) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.item, s.itemURL, s.itemFailures = n, url, 0
+	s.summary.Items.Total = total
+	if !s.summaryOnly {
+		s.write(itemRecord{Type: RecordItemStarted, Time: s.now(), Item: n, Total: total, URL: url})
+	}
+}
+
+// ItemDone records the outcome of the current item. A failed item whose
+// shows reported no failure of their own is listed in the summary failures.
+func (s *Stream) ItemDone(err error, errorClass string) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	success := err == nil
+	record := itemRecord{Type: RecordItemDone, Time: s.now(), Item: s.item, URL: s.itemURL, Success: &success}
+	if success {
+		s.summary.Items.Completed++
+	} else {
+		s.summary.Items.Failed++
+		record.Error, record.ErrorClass = err.Error(), errorClass
+		if s.itemFailures == 0 {
+			s.summary.Failures = append(s.summary.Failures, Failure{Item: s.item, URL: s.itemURL, ErrorClass: errorClass, Error: err.Error()})
+		}
+	}
+	if !s.summaryOnly {
+		s.write(record)
+	}
+}
+
+// Finish writes the summary and returns the first write error. err is the
+// run's error; one that no show or item reported, such as a failed sign-in,
+// is added to the failures.
+func (s *Stream) Finish(err error, errorClass string, cancelled bool) error {
+	if s == nil {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	summary := s.summary
+	if err != nil && !cancelled && len(summary.Failures) == 0 {
+		summary.Failures = append(summary.Failures, Failure{ErrorClass: errorClass, Error: err.Error()})
+	}
+	if summary.Failures == nil {
+		summary.Failures = []Failure{}
+	}
+	if summary.Uploads.Results == nil {
+		summary.Uploads.Results = []Upload{}
+	}
+	summary.ErrorClasses = make(map[string]int)
+	for _, failure := range summary.Failures {
+		summary.ErrorClasses[failure.ErrorClass]++
+	}
+	now := s.now()
+	summary.Type = RecordSummary
+	summary.Time = now
+	summary.Cancelled = cancelled
+	summary.Success = err == nil && !cancelled && len(summary.Failures) == 0
+	summary.DurationSeconds = now.Sub(s.start).Round(time.Millisecond).Seconds()
+	s.write(summary)
+	return s.err
+}
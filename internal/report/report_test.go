@@ -0,0 +1,159 @@
+package report
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jmagar/nugs-cli/internal/model"
+)
+
+func decodeLines(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+	var records []map[string]any
+	scanner := bufio.NewScanner(buf)
+	for scanner.Scan() {
+		var record map[string]any
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			t.Fatalf("line %q is not JSON: %v", scanner.Text(), err)
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+func TestStreamSummary(t *testing.T) {
+	box := &model.ProgressBoxState{}
+	var buf bytes.Buffer
+	s := New(&buf, func() *model.ProgressBoxState { return box }, false)
+
+	video := filepath.Join(t.TempDir(), "show.mp4")
+	if err := os.WriteFile(video, make([]byte, 500), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	s.ItemStarted(1, 2, "https://play.nugs.net/release/100")
+	s.Event(model.Event{Type: model.EventShowStarted, ContainerID: 100, Show: "Red Rocks", Media: "audio"})
+	box.ResetForNewAlbum("Red Rocks", "1/1", 3, 0)
+	box.Mu.Lock()
+	box.AccumulateTrackLocked(1, 1000)
+	box.AccumulateTrackLocked(2, 2000)
+	box.SkippedTracks = 1
+	box.Mu.Unlock()
+	s.Progress(box)
+	s.Event(model.Event{Type: model.EventUploadDone, ContainerID: 100, Show: "Red Rocks", Media: "audio", Path: "/music/Red Rocks", Remote: "gdrive:Music"})
+	s.Event(model.Event{Type: model.EventShowDone, ContainerID: 100, Show: "Red Rocks", Media: "audio"})
+	s.Event(model.Event{Type: model.EventShowDone, ContainerID: 100, Show: "Red Rocks", Media: "video", Path: video})
+	s.ItemDone(nil, "")
+
+	s.ItemStarted(2, 2, "200")
+	s.Event(model.Event{Type: model.EventShowDone, ContainerID: 200, Show: "MSG", Media: "audio", Error: "upload failed", ErrorClass: "upload"})
+	s.ItemDone(errors.New("upload failed"), "upload")
+	if err := s.Finish(errors.New("1 item failed"), "other", false); err != nil {
+		t.Fatal(err)
+	}
+
+	records := decodeLines(t, &buf)
+	var types []string
+	for _, r := range records {
+		types = append(types, r["type"].(string))
+	}
+	want := []string{"item.started", "show.started", "progress", "upload.done", "show.done", "show.done", "item.done", "item.started", "show.done", "item.done", "summary"}
+	if len(types) != len(want) {
+		t.Fatalf("record types = %v, want %v", types, want)
+	}
+	for i := range want {
+		if types[i] != want[i] {
+			t.Fatalf("record types = %v, want %v", types, want)
+		}
+	}
+	if records[4]["tracks"] != float64(2) || records[4]["bytes"] != float64(3000) {
+		t.Errorf("audio show.done = %v, want 2 tracks and 3000 bytes", records[4])
+	}
+
+	var summary Summary
+	line, _ := json.Marshal(records[len(records)-1])
+	if err := json.Unmarshal(line, &summary); err != nil {
+		t.Fatal(err)
+	}
+	if summary.Success || summary.Cancelled {
+		t.Errorf("success = %v, cancelled = %v; want a failed run", summary.Success, summary.Cancelled)
+	}
+	if summary.Items != (ItemCounts{Total: 2, Completed: 1, Failed: 1}) {
+		t.Errorf("items = %+v", summary.Items)
+	}
+	if summary.Shows != (ShowCounts{Completed: 2, Failed: 1}) {
+		t.Errorf("shows = %+v", summary.Shows)
+	}
+	// The failed show reuses the box, so its totals count again.
+	if summary.Tracks != (TrackCounts{Downloaded: 4, Skipped: 2}) || summary.Bytes != 6500 {
+		t.Errorf("tracks = %+v, bytes = %d", summary.Tracks, summary.Bytes)
+	}
+	if summary.Uploads.Completed != 1 || summary.Uploads.Failed != 1 || len(summary.Uploads.Results) != 1 || summary.Uploads.Results[0].Remote != "gdrive:Music" {
+		t.Errorf("uploads = %+v", summary.Uploads)
+	}
+	if len(summary.Failures) != 1 || summary.Failures[0].Item != 2 || summary.Failures[0].URL != "200" || summary.Failures[0].ContainerID != 200 {
+		t.Errorf("failures = %+v, want only the failed show", summary.Failures)
+	}
+	if summary.ErrorClasses["upload"] != 1 || len(summary.ErrorClasses) != 1 {
+		t.Errorf("errorClasses = %v", summary.ErrorClasses)
+	}
+}
+
+func TestStreamThrottlesProgress(t *testing.T) {
+	box := &model.ProgressBoxState{}
+	box.ResetForNewAlbum("Red Rocks", "1/1", 3, 0)
+	var buf bytes.Buffer
+	s := New(&buf, nil, false)
+	now := time.Unix(1700000000, 0)
+	s.now = func() time.Time { return now }
+
+	s.Progress(box)
+	s.Progress(box) // unchanged
+	box.DownloadPercent = 10
+	s.Progress(box) // same track within a second
+	now = now.Add(progressInterval)
+	s.Progress(box)
+	box.TrackNumber = 2
+	s.Progress(box) // new track
+
+	if n := len(decodeLines(t, &buf)); n != 3 {
+		t.Fatalf("wrote %d progress records, want 3", n)
+	}
+}
+
+func TestStreamSummaryOnly(t *testing.T) {
+	var buf bytes.Buffer
+	s := New(&buf, nil, true)
+	s.ItemStarted(1, 1, "100")
+	s.Progress(&model.ProgressBoxState{ShowTitle: "Red Rocks"})
+	s.Event(model.Event{Type: model.EventShowStarted})
+	s.ItemDone(nil, "")
+	if err := s.Finish(errors.New("not signed in"), "other", false); err != nil {
+		t.Fatal(err)
+	}
+	records := decodeLines(t, &buf)
+	if len(records) != 1 || records[0]["type"] != RecordSummary {
+		t.Fatalf("records = %v, want only the summary", records)
+	}
+	failures := records[0]["failures"].([]any)
+	if len(failures) != 1 || failures[0].(map[string]any)["error"] != "not signed in" {
+		t.Errorf("failures = %v, want the run error", failures)
+	}
+}
+
+func TestNilStream(t *testing.T) {
+	var s *Stream
+	s.ItemStarted(1, 1, "100")
+	s.Progress(&model.ProgressBoxState{})
+	s.Event(model.Event{Type: model.EventShowDone})
+	s.ItemDone(nil, "")
+	if err := s.Finish(nil, "", false); err != nil {
+		t.Fatal(err)
+	}
+}
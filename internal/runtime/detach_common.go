@@ -15,8 +15,12 @@ func IsReadOnlyCommand(urls []string) bool {
 	switch urls[0] {
 	case "help", "--help", "status", "cancel", "completion":
 		return true
-	case "list":
+	case "list", "verify", "remote":
 		return true
+	case "serve":
+		return true // long-running foreground server; publishes its own runtime status
+	case "daemon":
+		return true // long-running foreground scheduler; publishes status per job
 	case "watch":
 		if len(urls) < 2 {
 			return true
@@ -27,12 +31,24 @@ func IsReadOnlyCommand(urls []string) bool {
 		default:
 			return true // add/remove/list/enable/disable are read-only
 		}
+	case "queue":
+		if len(urls) < 2 {
+			return true
+		}
+		return urls[1] != "run" // add/list/remove/reorder only edit the queue file
+	case "song":
+		if len(urls) < 2 {
+			return true
+		}
+		return urls[1] != "grab" // list/stats only read cached metadata
+	case "playlist":
+		return true // export only reads the catalog cache and local folders
 	case "catalog":
 		if len(urls) < 2 {
 			return true
 		}
 		switch urls[1] {
-		case "update", "cache", "stats", "latest", "list", "coverage", "config":
+		case "update", "cache", "stats", "latest", "list", "coverage", "search", "config":
 			return true
 		case "gaps":
 			if len(urls) >= 4 && urls[len(urls)-1] == "fill" {
@@ -0,0 +1,3 @@
+// Package search builds the local inverted index over cached catalog shows
+// and evaluates `nugs search` queries against it.
+package search
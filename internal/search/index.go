@@ -0,0 +1,137 @@
+package search
+
+import (
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/jmagar/nugs-cli/internal/helpers"
+	"github.com/jmagar/nugs-cli/internal/model"
+)
+
+// IndexVersion is bumped whenever the index layout or tokenization changes,
+// so an index written by an older build is rebuilt rather than misread.
+const IndexVersion = 2
+
+// Indexed text fields.
+const (
+	FieldArtist = "artist"
+	FieldVenue  = "venue"
+	FieldCity   = "city"
+	FieldState  = "state"
+	FieldTitle  = "title"
+	FieldSong   = "song"
+)
+
+// textFields lists the fields a bare search term is matched against. Song
+// titles are only searched with song:, so a bare "rocks" finds venues rather
+// than every show that played a song with that word in it.
+var textFields = []string{FieldArtist, FieldVenue, FieldCity, FieldState, FieldTitle}
+
+// indexedFields lists every field with entries in the inverted index.
+var indexedFields = append(slices.Clone(textFields), FieldSong)
+
+// Build indexes shows and the song titles in songs. Later entries for the
+// same container replace earlier ones, so callers pass the least complete
+// source first. A performance of a show missing from shows adds the show
+// with the details the setlist carries.
+func Build(shows []model.FullCatalogShow, songs []model.SongPerformance) *model.SearchIndex {
+	index := &model.SearchIndex{
+		Version: IndexVersion,
+		BuiltAt: time.Now(),
+		Shows:   make(map[int]model.SearchShow, len(shows)),
+		Terms:   map[string][]int{},
+	}
+	for _, entry := range shows {
+		if entry.ContainerID == 0 {
+			continue
+		}
+		index.Shows[entry.ContainerID] = searchShow(entry)
+	}
+	for _, perf := range songs {
+		if perf.ContainerID == 0 || strings.TrimSpace(perf.SongTitle) == "" {
+			continue
+		}
+		show, ok := index.Shows[perf.ContainerID]
+		if !ok {
+			show = model.SearchShow{
+				ContainerID: perf.ContainerID,
+				ArtistID:    perf.ArtistID,
+				ArtistName:  perf.ArtistName,
+				Date:        perf.Date,
+				Venue:       perf.Venue,
+			}
+		}
+		if !slices.Contains(show.Songs, perf.SongTitle) {
+			show.Songs = append(show.Songs, perf.SongTitle)
+		}
+		index.Shows[perf.ContainerID] = show
+	}
+	for id, show := range index.Shows {
+		for _, field := range indexedFields {
+			for _, token := range uniqueTokens(fieldValues(show, field)) {
+				key := field + ":" + token
+				index.Terms[key] = append(index.Terms[key], id)
+			}
+		}
+	}
+	for key := range index.Terms {
+		slices.Sort(index.Terms[key])
+	}
+	return index
+}
+
+func searchShow(entry model.FullCatalogShow) model.SearchShow {
+	venue := entry.VenueName
+	if venue == "" {
+		venue = entry.Venue
+	}
+	return model.SearchShow{
+		ContainerID: entry.ContainerID,
+		ArtistID:    entry.ArtistID,
+		ArtistName:  entry.ArtistName,
+		Title:       entry.ContainerInfo,
+		Date:        helpers.ShowDate(entry.AlbArtResp()),
+		Venue:       venue,
+		City:        entry.VenueCity,
+		State:       entry.VenueState,
+		Media:       entry.Media,
+	}
+}
+
+// fieldValues returns the text of field in show. Songs are returned one title
+// per value so a phrase never spans two songs.
+func fieldValues(show model.SearchShow, field string) []string {
+	switch field {
+	case FieldArtist:
+		return []string{show.ArtistName}
+	case FieldVenue:
+		return []string{show.Venue}
+	case FieldCity:
+		return []string{show.City}
+	case FieldState:
+		return []string{show.State}
+	case FieldTitle:
+		return []string{show.Title}
+	case FieldSong:
+		return show.Songs
+	}
+	return nil
+}
+
+// Tokenize lowercases s and splits it into letter/digit runs.
+func Tokenize(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+func uniqueTokens(values []string) []string {
+	var tokens []string
+	for _, value := range values {
+		tokens = append(tokens, Tokenize(value)...)
+	}
+	slices.Sort(tokens)
+	return slices.Compact(tokens)
+}
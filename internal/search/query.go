@@ -0,0 +1,419 @@
+package search
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jmagar/nugs-cli/internal/model"
+)
+
+// DefaultLimit caps results when the query has no limit: term.
+const DefaultLimit = 50
+
+// minPrefixLen is the shortest token that also matches longer words, so
+// "rock" finds "Rocks" but "co" only finds "CO".
+const minPrefixLen = 3
+
+const dateLayout = "2006-01-02"
+
+// Term is one text condition. An empty Field matches any text field.
+type Term struct {
+	Field  string
+	Tokens []string
+}
+
+// Query is a parsed search. All conditions must hold, except that artist:
+// conditions are alternatives: a show matches when it belongs to any of the
+// named artists or artist IDs.
+type Query struct {
+	Terms     []Term
+	Artists   []Term
+	ArtistIDs []int
+	From, To  string // inclusive YYYY-MM-DD bounds; empty is open
+	Media     model.MediaType
+	Limit     int
+}
+
+// Parse builds a Query from command-line arguments. Each argument is either
+// a field:value condition (artist:, venue:, city:, state:, title:, song:,
+// year:, date:, media:, limit:) or free text. Double quotes group words inside one
+// argument, so both `venue:"red rocks"` and a shell-quoted 'venue:red rocks'
+// work.
+func Parse(args ...string) (*Query, error) {
+	q := &Query{Limit: DefaultLimit}
+	for _, arg := range args {
+		pieces := []string{arg}
+		if strings.ContainsRune(arg, '"') {
+			pieces = splitQuoted(arg)
+		}
+		for _, piece := range pieces {
+			if err := q.add(strings.TrimSpace(piece)); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if len(q.Terms) == 0 && len(q.Artists) == 0 && len(q.ArtistIDs) == 0 && q.From == "" && q.To == "" && q.Media == model.MediaTypeUnknown {
+		return nil, errors.New("search needs at least one term, e.g. venue:\"red rocks\" year:2023")
+	}
+	if q.From != "" && q.To != "" && q.From > q.To {
+		return nil, fmt.Errorf("empty date range %s..%s", q.From, q.To)
+	}
+	return q, nil
+}
+
+func (q *Query) add(piece string) error {
+	if piece == "" {
+		return nil
+	}
+	key, value, hasKey := strings.Cut(piece, ":")
+	if !hasKey || key == "" {
+		return q.addText("", piece)
+	}
+	value = strings.TrimSpace(value)
+	switch strings.ToLower(key) {
+	case FieldArtist:
+		if id, err := strconv.Atoi(value); err == nil {
+			q.ArtistIDs = append(q.ArtistIDs, id)
+			return nil
+		}
+		tokens := Tokenize(value)
+		if len(tokens) == 0 {
+			return fmt.Errorf("empty %s: condition", FieldArtist)
+		}
+		q.Artists = append(q.Artists, Term{Field: FieldArtist, Tokens: tokens})
+	case FieldVenue, FieldCity, FieldState, FieldTitle, FieldSong:
+		return q.addText(strings.ToLower(key), value)
+	case "year":
+		return q.addRange(value, parseYear)
+	case "date":
+		return q.addRange(value, parseDate)
+	case "media":
+		media := model.ParseMediaType(value)
+		if media == model.MediaTypeUnknown {
+			return fmt.Errorf("invalid media %q: must be audio, video or both", value)
+		}
+		q.Media = media
+	case "limit":
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 {
+			return fmt.Errorf("invalid limit %q: must be a positive integer", value)
+		}
+		q.Limit = n
+	default:
+		return fmt.Errorf("unknown search field %q (use artist, venue, city, state, title, song, year, date, media or limit)", key)
+	}
+	return nil
+}
+
+func (q *Query) addText(field, value string) error {
+	tokens := Tokenize(value)
+	if len(tokens) == 0 {
+		if field == "" {
+			return nil
+		}
+		return fmt.Errorf("empty %s: condition", field)
+	}
+	q.Terms = append(q.Terms, Term{Field: field, Tokens: tokens})
+	return nil
+}
+
+// addRange narrows the date bounds to value, which is a single period or
+// "a..b" with either end optional.
+func (q *Query) addRange(value string, parse func(string) (from, to string, err error)) error {
+	lo, hi, isRange := strings.Cut(value, "..")
+	if !isRange {
+		hi = lo
+	}
+	if lo == "" && hi == "" {
+		return fmt.Errorf("invalid date range %q", value)
+	}
+	if lo != "" {
+		from, _, err := parse(lo)
+		if err != nil {
+			return err
+		}
+		if from > q.From {
+			q.From = from
+		}
+	}
+	if hi != "" {
+		_, to, err := parse(hi)
+		if err != nil {
+			return err
+		}
+		if q.To == "" || to < q.To {
+			q.To = to
+		}
+	}
+	return nil
+}
+
+func parseYear(s string) (string, string, error) {
+	year, err := strconv.Atoi(s)
+	if err != nil || year < 1900 || year > 9999 {
+		return "", "", fmt.Errorf("invalid year %q", s)
+	}
+	return fmt.Sprintf("%04d-01-01", year), fmt.Sprintf("%04d-12-31", year), nil
+}
+
+// parseDate accepts YYYY, YYYY-MM or YYYY-MM-DD and returns the first and
+// last day it covers.
+func parseDate(s string) (string, string, error) {
+	if t, err := time.Parse(dateLayout, s); err == nil {
+		return t.Format(dateLayout), t.Format(dateLayout), nil
+	}
+	if t, err := time.Parse("2006-01", s); err == nil {
+		return t.Format(dateLayout), t.AddDate(0, 1, -1).Format(dateLayout), nil
+	}
+	if from, to, err := parseYear(s); err == nil {
+		return from, to, nil
+	}
+	return "", "", fmt.Errorf("invalid date %q: use YYYY, YYYY-MM or YYYY-MM-DD", s)
+}
+
+// splitQuoted splits s on whitespace outside double quotes and drops the
+// quotes.
+func splitQuoted(s string) []string {
+	var (
+		pieces  []string
+		current strings.Builder
+		quoted  bool
+	)
+	for _, r := range s {
+		switch {
+		case r == '"':
+			quoted = !quoted
+		case !quoted && (r == ' ' || r == '\t'):
+			if current.Len() > 0 {
+				pieces = append(pieces, current.String())
+				current.Reset()
+			}
+		default:
+			current.WriteRune(r)
+		}
+	}
+	if current.Len() > 0 {
+		pieces = append(pieces, current.String())
+	}
+	return pieces
+}
+
+// Run returns every show in index matching q, newest first. The caller
+// applies q.Limit so it can report the total.
+func Run(index *model.SearchIndex, q *Query) []model.SearchShow {
+	var candidates []int
+	restricted := false
+	var keys []string
+	if len(q.Terms) > 0 || len(q.Artists) > 0 {
+		keys = sortedKeys(index.Terms)
+	}
+	// Artist IDs are not in the inverted index, so named artists only narrow
+	// the candidates when no ID can add shows of its own.
+	if len(q.Artists) > 0 && len(q.ArtistIDs) == 0 {
+		for _, term := range q.Artists {
+			candidates = union(candidates, termIDs(index, keys, term))
+		}
+		if len(candidates) == 0 {
+			return nil
+		}
+		restricted = true
+	}
+	for _, term := range q.Terms {
+		ids := termIDs(index, keys, term)
+		if restricted {
+			ids = intersect(candidates, ids)
+		}
+		candidates, restricted = ids, true
+		if len(candidates) == 0 {
+			return nil
+		}
+	}
+	if !restricted {
+		for id := range index.Shows {
+			candidates = append(candidates, id)
+		}
+	}
+
+	var results []model.SearchShow
+	for _, id := range candidates {
+		show, ok := index.Shows[id]
+		if ok && q.matches(show) {
+			results = append(results, show)
+		}
+	}
+	slices.SortFunc(results, func(a, b model.SearchShow) int {
+		if c := cmp.Compare(b.Date, a.Date); c != 0 {
+			return c
+		}
+		if c := cmp.Compare(a.ArtistName, b.ArtistName); c != 0 {
+			return c
+		}
+		return cmp.Compare(b.ContainerID, a.ContainerID)
+	})
+	return results
+}
+
+// matches applies the conditions the inverted index cannot answer: phrase
+// order, artists, dates and media.
+func (q *Query) matches(show model.SearchShow) bool {
+	for _, term := range q.Terms {
+		if len(term.Tokens) > 1 && !containsPhrase(show, term) {
+			return false
+		}
+	}
+	if (len(q.Artists) > 0 || len(q.ArtistIDs) > 0) && !q.matchesArtist(show) {
+		return false
+	}
+	if q.From != "" || q.To != "" {
+		if _, err := time.Parse(dateLayout, show.Date); err != nil {
+			return false
+		}
+		if (q.From != "" && show.Date < q.From) || (q.To != "" && show.Date > q.To) {
+			return false
+		}
+	}
+	switch q.Media {
+	case model.MediaTypeAudio:
+		return show.Media.HasAudio()
+	case model.MediaTypeVideo:
+		return show.Media.HasVideo()
+	case model.MediaTypeBoth:
+		return show.Media == model.MediaTypeBoth
+	}
+	return true
+}
+
+// matchesArtist reports whether show belongs to any artist: condition.
+func (q *Query) matchesArtist(show model.SearchShow) bool {
+	if slices.Contains(q.ArtistIDs, show.ArtistID) {
+		return true
+	}
+	for _, term := range q.Artists {
+		if hasTokens(show.ArtistName, term.Tokens) && (len(term.Tokens) == 1 || containsPhrase(show, term)) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasTokens reports whether text holds every token, matching tokens of
+// minPrefixLen or more as prefixes like the inverted index does.
+func hasTokens(text string, tokens []string) bool {
+	words := Tokenize(text)
+	for _, token := range tokens {
+		found := slices.ContainsFunc(words, func(word string) bool {
+			return word == token || (len([]rune(token)) >= minPrefixLen && strings.HasPrefix(word, token))
+		})
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func containsPhrase(show model.SearchShow, term Term) bool {
+	phrase := strings.Join(term.Tokens, " ")
+	fields := textFields
+	if term.Field != "" {
+		fields = []string{term.Field}
+	}
+	for _, field := range fields {
+		for _, value := range fieldValues(show, field) {
+			if strings.Contains(strings.Join(Tokenize(value), " "), phrase) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// termIDs returns the sorted containers holding every token of term in one
+// of its fields. Tokens of minPrefixLen or more also match as prefixes.
+func termIDs(index *model.SearchIndex, keys []string, term Term) []int {
+	fields := textFields
+	if term.Field != "" {
+		fields = []string{term.Field}
+	}
+	var ids []int
+	for i, token := range term.Tokens {
+		var tokenIDs []int
+		for _, field := range fields {
+			key := field + ":" + token
+			if len([]rune(token)) < minPrefixLen {
+				tokenIDs = union(tokenIDs, index.Terms[key])
+				continue
+			}
+			start, _ := slices.BinarySearch(keys, key)
+			for _, k := range keys[start:] {
+				if !strings.HasPrefix(k, key) {
+					break
+				}
+				tokenIDs = union(tokenIDs, index.Terms[k])
+			}
+		}
+		if i == 0 {
+			ids = tokenIDs
+		} else {
+			ids = intersect(ids, tokenIDs)
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+	}
+	return ids
+}
+
+func sortedKeys(terms map[string][]int) []string {
+	keys := make([]string, 0, len(terms))
+	for key := range terms {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// union merges two sorted ID lists.
+func union(a, b []int) []int {
+	out := make([]int, 0, len(a)+len(b))
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] < b[j]:
+			out = append(out, a[i])
+			i++
+		case a[i] > b[j]:
+			out = append(out, b[j])
+			j++
+		default:
+			out = append(out, a[i])
+			i++
+			j++
+		}
+	}
+	out = append(out, a[i:]...)
+	return append(out, b[j:]...)
+}
+
+// intersect keeps the IDs present in both sorted lists.
+func intersect(a, b []int) []int {
+	var out []int
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] < b[j]:
+			i++
+		case a[i] > b[j]:
+			j++
+		default:
+			out = append(out, a[i])
+			i++
+			j++
+		}
+	}
+	return out
+}
@@ -0,0 +1,114 @@
+package search
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/jmagar/nugs-cli/internal/model"
+)
+
+func testIndex() *model.SearchIndex {
+	return Build([]model.FullCatalogShow{
+		{ContainerID: 1, ArtistID: 1125, ArtistName: "Billy Strings", PerformanceDate: "2023-06-10", VenueName: "Red Rocks Amphitheatre", VenueCity: "Morrison", VenueState: "CO", Media: model.MediaTypeBoth},
+		{ContainerID: 2, ArtistID: 1125, ArtistName: "Billy Strings", PerformanceDate: "2021-09-18", VenueName: "Red Rocks Amphitheatre", VenueCity: "Morrison", VenueState: "CO", Media: model.MediaTypeAudio},
+		{ContainerID: 3, ArtistID: 461, ArtistName: "Goose", PerformanceDate: "6/22/2024", VenueName: "Forest Hills Stadium", VenueCity: "Queens", VenueState: "NY", Media: model.MediaTypeVideo},
+		{ContainerID: 4, ArtistID: 461, ArtistName: "Goose", PerformanceDate: "2023-12-31", Venue: "Rocks Red Hall", VenueCity: "Denver", VenueState: "CO"},
+		{ContainerID: 5, ArtistID: 62, ArtistName: "The String Cheese Incident", PerformanceDate: "2023-07-04", VenueName: "Red Rocks Amphitheatre", VenueCity: "Morrison", VenueState: "CO", Media: model.MediaTypeAudio},
+	}, []model.SongPerformance{
+		{ContainerID: 1, ArtistID: 1125, SongTitle: "Dust in a Baggie"},
+		{ContainerID: 1, ArtistID: 1125, SongTitle: "Red Daisy"},
+		{ContainerID: 2, ArtistID: 1125, SongTitle: "Dust in a Baggie"},
+		{ContainerID: 4, ArtistID: 461, SongTitle: "Arcadia"},
+		{ContainerID: 6, ArtistID: 461, ArtistName: "Goose", Date: "2022-04-01", Venue: "The Capitol Theatre", SongTitle: "Hot Tea"},
+	})
+}
+
+func ids(shows []model.SearchShow) []int {
+	out := make([]int, len(shows))
+	for i, show := range shows {
+		out[i] = show.ContainerID
+	}
+	return out
+}
+
+func TestRun(t *testing.T) {
+	index := testIndex()
+	tests := []struct {
+		name string
+		args []string
+		want []int
+	}{
+		{name: "venue phrase keeps word order", args: []string{"venue:red rocks"}, want: []int{5, 1, 2}},
+		{name: "quoted venue phrase", args: []string{`venue:"red rocks" year:2023`}, want: []int{5, 1}},
+		{name: "prefix token", args: []string{"venue:amphi"}, want: []int{5, 1, 2}},
+		{name: "free text across fields", args: []string{"morrison", "strings"}, want: []int{1, 2}},
+		{name: "artist name", args: []string{"artist:goose"}, want: []int{3, 4, 6}},
+		{name: "artist id", args: []string{"artist:1125"}, want: []int{1, 2}},
+		{name: "repeated artists are alternatives", args: []string{"artist:goose", "artist:cheese", "state:co"}, want: []int{4, 5}},
+		{name: "artist name or id", args: []string{"artist:goose", "artist:62", "year:2023"}, want: []int{4, 5}},
+		{name: "song phrase", args: []string{`song:"dust in"`}, want: []int{1, 2}},
+		{name: "song prefix", args: []string{"song:arcad"}, want: []int{4}},
+		{name: "song phrase stays within one title", args: []string{"song:baggie red"}, want: nil},
+		{name: "bare words skip songs", args: []string{"daisy"}, want: nil},
+		{name: "show known only from setlist", args: []string{"song:hot tea", "venue:capitol"}, want: []int{6}},
+		{name: "state exact", args: []string{"state:co", "artist:goose"}, want: []int{4}},
+		{name: "year range", args: []string{"year:2022..2024", "state:co"}, want: []int{4, 5, 1}},
+		{name: "month", args: []string{"date:2023-06"}, want: []int{1}},
+		{name: "open date range", args: []string{"date:2024.."}, want: []int{3}},
+		{name: "parsed non-iso date", args: []string{"date:2024-06-22"}, want: []int{3}},
+		{name: "media audio includes both", args: []string{"media:audio"}, want: []int{5, 1, 2}},
+		{name: "media video", args: []string{"media:video"}, want: []int{3, 1}},
+		{name: "no match", args: []string{"venue:fillmore"}, want: nil},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			q, err := Parse(tc.args...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := ids(Run(index, q))
+			if !slices.Equal(got, tc.want) && !(len(got) == 0 && len(tc.want) == 0) {
+				t.Fatalf("Run(%q) = %v, want %v", tc.args, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{args: nil, want: "at least one term"},
+		{args: []string{"venu:red"}, want: `unknown search field "venu"`},
+		{args: []string{"year:23"}, want: "invalid year"},
+		{args: []string{"date:2023-13"}, want: "invalid date"},
+		{args: []string{"media:vinyl"}, want: "invalid media"},
+		{args: []string{"limit:0"}, want: "invalid limit"},
+		{args: []string{"year:2024..2020"}, want: "empty date range"},
+	}
+	for _, tc := range tests {
+		_, err := Parse(tc.args...)
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("Parse(%q) error = %v, want %q", tc.args, err, tc.want)
+		}
+	}
+}
+
+func TestParseLimit(t *testing.T) {
+	q, err := Parse("goose", "limit:5")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q.Limit != 5 {
+		t.Fatalf("Limit = %d, want 5", q.Limit)
+	}
+	q, err = Parse("goose")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q.Limit != DefaultLimit {
+		t.Fatalf("Limit = %d, want default %d", q.Limit, DefaultLimit)
+	}
+}
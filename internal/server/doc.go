@@ -0,0 +1,4 @@
+// Package server implements `nugs serve`: a localhost HTTP/JSON API for
+// submitting grabs, listing jobs, streaming progress over Server-Sent Events,
+// pausing, resuming and cancelling downloads, and running catalog queries.
+package server
@@ -0,0 +1,127 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/jmagar/nugs-cli/internal/model"
+)
+
+// Event types sent on /api/events.
+const (
+	eventJob      = "job"
+	eventProgress = "progress"
+	eventPaused   = "paused"
+)
+
+// progressInterval is how often /api/events samples the progress box.
+const progressInterval = 500 * time.Millisecond
+
+type event struct {
+	Type string
+	Data any
+}
+
+// Progress is a JSON snapshot of the active progress box.
+type Progress = model.ProgressSnapshot
+
+// progress returns the current progress snapshot, or false when no
+// download is active.
+func (s *Server) progress() (Progress, bool) {
+	if s.deps.ProgressBox == nil {
+		return Progress{}, false
+	}
+	box := s.deps.ProgressBox()
+	if box == nil {
+		return Progress{}, false
+	}
+	return box.Snapshot(), true
+}
+
+func (s *Server) subscribe() chan event {
+	ch := make(chan event, 32)
+	s.subsMu.Lock()
+	s.subs[ch] = struct{}{}
+	s.subsMu.Unlock()
+	return ch
+}
+
+func (s *Server) unsubscribe(ch chan event) {
+	s.subsMu.Lock()
+	delete(s.subs, ch)
+	s.subsMu.Unlock()
+}
+
+// publish fans an event out to every /api/events stream. Slow readers
+// drop events rather than stall the worker.
+func (s *Server) publish(typ string, data any) {
+	s.subsMu.Lock()
+	defer s.subsMu.Unlock()
+	for ch := range s.subs {
+		select {
+		case ch <- event{Type: typ, Data: data}:
+		default:
+		}
+	}
+}
+
+func writeEvent(w http.ResponseWriter, typ string, data any) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", typ, payload)
+	return err
+}
+
+// handleEvents streams job changes and progress samples as Server-Sent Events.
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, "streaming unsupported")
+		return
+	}
+	ch := s.subscribe()
+	defer s.unsubscribe(ch)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	if err := writeEvent(w, "status", s.status()); err != nil {
+		return
+	}
+	flusher.Flush()
+
+	ticker := time.NewTicker(progressInterval)
+	defer ticker.Stop()
+	var (
+		last   Progress
+		active bool
+	)
+	for {
+		var err error
+		select {
+		case <-r.Context().Done():
+			return
+		case ev := <-ch:
+			err = writeEvent(w, ev.Type, ev.Data)
+		case <-ticker.C:
+			current, ok := s.progress()
+			if !ok {
+				active = false
+				continue
+			}
+			if active && current == last {
+				continue
+			}
+			last, active = current, true
+			err = writeEvent(w, eventProgress, current)
+		}
+		if err != nil {
+			return
+		}
+		flusher.Flush()
+	}
+}
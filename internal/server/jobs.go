@@ -0,0 +1,229 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Job states.
+const (
+	JobQueued    = "queued"
+	JobRunning   = "running"
+	JobCompleted = "completed"
+	JobFailed    = "failed"
+	JobCancelled = "cancelled"
+)
+
+// maxFinishedJobs bounds how many finished jobs are kept for GET /api/jobs.
+const maxFinishedJobs = 200
+
+var (
+	errJobNotFound = errors.New("job not found")
+	errJobFinished = errors.New("job already finished")
+)
+
+// Job is one submitted grab.
+type Job struct {
+	ID          int       `json:"id"`
+	URL         string    `json:"url"`
+	Media       string    `json:"media,omitempty"` // audio, video, both; empty uses defaultOutputs
+	State       string    `json:"state"`
+	Error       string    `json:"error,omitempty"`
+	SubmittedAt time.Time `json:"submittedAt"`
+	StartedAt   time.Time `json:"startedAt,omitzero"`
+	FinishedAt  time.Time `json:"finishedAt,omitzero"`
+}
+
+func (j *Job) finished() bool {
+	return j.State == JobCompleted || j.State == JobFailed || j.State == JobCancelled
+}
+
+// submit queues one job per URL and wakes the worker.
+func (s *Server) submit(media string, urls []string) []Job {
+	s.mu.Lock()
+	created := make([]Job, 0, len(urls))
+	for _, url := range urls {
+		s.nextID++
+		job := &Job{ID: s.nextID, URL: url, Media: media, State: JobQueued, SubmittedAt: time.Now().UTC()}
+		s.jobs = append(s.jobs, job)
+		created = append(created, *job)
+	}
+	s.pruneLocked()
+	s.mu.Unlock()
+
+	for _, job := range created {
+		s.publish(eventJob, job)
+	}
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+	return created
+}
+
+// pruneLocked drops the oldest finished jobs beyond maxFinishedJobs.
+func (s *Server) pruneLocked() {
+	finished := 0
+	for _, job := range s.jobs {
+		if job.finished() {
+			finished++
+		}
+	}
+	if finished <= maxFinishedJobs {
+		return
+	}
+	kept := s.jobs[:0]
+	for _, job := range s.jobs {
+		if job.finished() && finished > maxFinishedJobs {
+			finished--
+			continue
+		}
+		kept = append(kept, job)
+	}
+	s.jobs = kept
+}
+
+func (s *Server) findLocked(id int) *Job {
+	for _, job := range s.jobs {
+		if job.ID == id {
+			return job
+		}
+	}
+	return nil
+}
+
+// job returns a copy of one job.
+func (s *Server) job(id int) (Job, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if job := s.findLocked(id); job != nil {
+		return *job, true
+	}
+	return Job{}, false
+}
+
+// jobList returns copies of all jobs in submission order.
+func (s *Server) jobList() []Job {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	jobs := make([]Job, 0, len(s.jobs))
+	for _, job := range s.jobs {
+		jobs = append(jobs, *job)
+	}
+	return jobs
+}
+
+// cancel stops a running job or drops a queued one.
+func (s *Server) cancel(id int) (Job, error) {
+	s.mu.Lock()
+	job := s.findLocked(id)
+	switch {
+	case job == nil:
+		s.mu.Unlock()
+		return Job{}, fmt.Errorf("%w: %d", errJobNotFound, id)
+	case job.finished():
+		snapshot := *job
+		s.mu.Unlock()
+		return snapshot, fmt.Errorf("%w: %d", errJobFinished, id)
+	case job.State == JobRunning:
+		if s.cancelRunning != nil {
+			s.cancelRunning()
+		}
+		snapshot := *job
+		s.mu.Unlock()
+		return snapshot, nil // the worker records the final state
+	}
+	job.State = JobCancelled
+	job.FinishedAt = time.Now().UTC()
+	snapshot := *job
+	s.mu.Unlock()
+	s.publish(eventJob, snapshot)
+	return snapshot, nil
+}
+
+// cancelCurrent cancels the running job, if any, and returns its ID.
+func (s *Server) cancelCurrent() (int, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.runningID == 0 || s.cancelRunning == nil {
+		return 0, false
+	}
+	s.cancelRunning()
+	return s.runningID, true
+}
+
+// next blocks until a queued job is available and marks it running.
+func (s *Server) next(ctx context.Context) (Job, context.Context, bool) {
+	for {
+		s.mu.Lock()
+		for _, job := range s.jobs {
+			if job.State != JobQueued {
+				continue
+			}
+			jobCtx, cancel := context.WithCancel(ctx)
+			job.State = JobRunning
+			job.StartedAt = time.Now().UTC()
+			s.runningID = job.ID
+			s.cancelRunning = cancel
+			snapshot := *job
+			s.mu.Unlock()
+			s.publish(eventJob, snapshot)
+			return snapshot, jobCtx, true
+		}
+		s.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return Job{}, nil, false
+		case <-s.wake:
+		}
+	}
+}
+
+// finish records the outcome of the running job.
+func (s *Server) finish(id int, jobCtx context.Context, err error) {
+	cancelled := jobCtx.Err() != nil
+	s.mu.Lock()
+	if s.cancelRunning != nil {
+		s.cancelRunning()
+	}
+	s.runningID = 0
+	s.cancelRunning = nil
+	job := s.findLocked(id)
+	if job == nil {
+		s.mu.Unlock()
+		return
+	}
+	switch {
+	case err == nil:
+		job.State = JobCompleted
+	case cancelled || errors.Is(err, context.Canceled) ||
+		(s.deps.IsCrawlCancelledErr != nil && s.deps.IsCrawlCancelledErr(err)):
+		job.State = JobCancelled
+	default:
+		job.State = JobFailed
+	}
+	if err != nil {
+		job.Error = err.Error()
+	}
+	job.FinishedAt = time.Now().UTC()
+	snapshot := *job
+	s.pruneLocked()
+	s.mu.Unlock()
+	s.publish(eventJob, snapshot)
+}
+
+// Work runs queued jobs one at a time until ctx is cancelled. Jobs are
+// serialized because progress and crawl control are process-wide.
+func (s *Server) Work(ctx context.Context) {
+	for {
+		job, jobCtx, ok := s.next(ctx)
+		if !ok {
+			return
+		}
+		err := s.deps.Grab(jobCtx, job.URL, job.Media)
+		s.finish(job.ID, jobCtx, err)
+	}
+}
@@ -0,0 +1,425 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jmagar/nugs-cli/internal/catalog"
+	"github.com/jmagar/nugs-cli/internal/model"
+)
+
+// DefaultAddr is the listen address used when `nugs serve` gets none.
+const DefaultAddr = "127.0.0.1:8765"
+
+// maxRequestBody caps POST bodies; job submissions are small.
+const maxRequestBody = 1 << 20
+
+// catalogCommands are the read-only catalog subcommands exposed over HTTP.
+// "update", "config" and "gaps ... fill" change state and are not included.
+var catalogCommands = map[string]struct {
+	artistRequired bool
+	artistAllowed  bool
+}{
+	"cache":    {},
+	"stats":    {},
+	"latest":   {},
+	"list":     {artistRequired: true, artistAllowed: true},
+	"gaps":     {artistRequired: true, artistAllowed: true},
+	"coverage": {artistAllowed: true},
+}
+
+// Deps holds callbacks to functions that live in the command layer.
+type Deps struct {
+	// Grab downloads one release ID or URL through the normal grab entry
+	// points. media is "audio", "video", "both", or empty for defaultOutputs.
+	Grab func(ctx context.Context, url, media string) error
+
+	// ValidURL reports whether url is a release ID or URL that Grab accepts.
+	ValidURL func(url string) bool
+
+	// SetPaused pauses or resumes the running download.
+	SetPaused func(paused bool) error
+
+	// ProgressBox returns the active progress box, or nil when idle.
+	ProgressBox func() *model.ProgressBoxState
+
+	// IsCrawlCancelledErr checks if an error is a crawl cancellation error.
+	IsCrawlCancelledErr func(error) bool
+
+	// CatalogQuery runs a read-only catalog command in-process and returns
+	// its --json output.
+	CatalogQuery func(ctx context.Context, req CatalogRequest) ([]byte, error)
+
+	// ResolveArtist maps an artist argument (numeric ID, alias or name) to
+	// a numeric ID, as catalog.ResolveArtistID does without a prompt.
+	ResolveArtist func(ctx context.Context, query string) (string, error)
+}
+
+// CatalogRequest is one read-only catalog query.
+type CatalogRequest struct {
+	Command  string          // a key of catalogCommands
+	ArtistID string          // resolved numeric ID, or "" when none
+	Limit    int             // latest only; 0 keeps the default
+	Media    model.MediaType // MediaTypeUnknown when not given
+}
+
+// Server is the HTTP API and its job list.
+type Server struct {
+	deps *Deps
+	mux  *http.ServeMux
+
+	mu            sync.Mutex
+	jobs          []*Job
+	nextID        int
+	runningID     int
+	cancelRunning context.CancelFunc
+	paused        bool
+	wake          chan struct{}
+
+	subsMu sync.Mutex
+	subs   map[chan event]struct{}
+}
+
+// New returns a Server. Call Work to start running submitted jobs.
+func New(deps *Deps) *Server {
+	s := &Server{
+		deps: deps,
+		mux:  http.NewServeMux(),
+		wake: make(chan struct{}, 1),
+		subs: make(map[chan event]struct{}),
+	}
+	s.mux.HandleFunc("GET /api/status", s.handleStatus)
+	s.mux.HandleFunc("GET /api/jobs", s.handleListJobs)
+	s.mux.HandleFunc("POST /api/jobs", s.handleSubmit)
+	s.mux.HandleFunc("GET /api/jobs/{id}", s.handleGetJob)
+	s.mux.HandleFunc("POST /api/jobs/{id}/cancel", s.handleCancelJob)
+	s.mux.HandleFunc("POST /api/pause", s.handlePause(true))
+	s.mux.HandleFunc("POST /api/resume", s.handlePause(false))
+	s.mux.HandleFunc("POST /api/cancel", s.handleCancel)
+	s.mux.HandleFunc("GET /api/events", s.handleEvents)
+	s.mux.HandleFunc("GET /api/catalog/{command}", s.handleCatalog)
+	s.mux.HandleFunc("GET /api/catalog/{command}/{artist}", s.handleCatalog)
+	return s
+}
+
+// ServeHTTP rejects requests that did not come from a local client, then
+// routes the rest. Checking Host blocks DNS rebinding; checking Origin stops
+// other web pages from driving the API through the user's browser.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !isLoopbackHost(r.Host) {
+		writeError(w, http.StatusForbidden, "requests must address a loopback host")
+		return
+	}
+	if origin := r.Header.Get("Origin"); origin != "" {
+		host := strings.TrimPrefix(strings.TrimPrefix(origin, "http://"), "https://")
+		if !isLoopbackHost(host) {
+			writeError(w, http.StatusForbidden, "cross-origin requests are not allowed")
+			return
+		}
+	}
+	s.mux.ServeHTTP(w, r)
+}
+
+func isLoopbackHost(hostport string) bool {
+	host := hostport
+	if h, _, err := net.SplitHostPort(hostport); err == nil {
+		host = h
+	}
+	host = strings.Trim(host, "[]")
+	if host == "" || strings.EqualFold(host, "localhost") {
+		return true // unix socket requests carry no host
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// Listen opens addr, which is host:port on a loopback interface or
+// unix:/path/to/socket.
+func Listen(addr string) (net.Listener, error) {
+	if path, ok := strings.CutPrefix(addr, "unix:"); ok {
+		if path == "" {
+			return nil, errors.New("unix socket path is empty")
+		}
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to remove stale socket %s: %w", path, err)
+		}
+		listener, err := net.Listen("unix", path)
+		if err != nil {
+			return nil, err
+		}
+		if err := os.Chmod(path, 0600); err != nil {
+			_ = listener.Close()
+			return nil, fmt.Errorf("failed to restrict socket permissions: %w", err)
+		}
+		return listener, nil
+	}
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid listen address %q: %w", addr, err)
+	}
+	if host == "" || !isLoopbackHost(host) {
+		return nil, fmt.Errorf("listen address %q is not a loopback address", addr)
+	}
+	return net.Listen("tcp", addr)
+}
+
+// Serve runs the worker and serves the API on listener until ctx is
+// cancelled, then cancels the running job and shuts down.
+func (s *Server) Serve(ctx context.Context, listener net.Listener) error {
+	workCtx, stopWork := context.WithCancel(ctx)
+	defer stopWork()
+	workerDone := make(chan struct{})
+	go func() {
+		defer close(workerDone)
+		s.Work(workCtx)
+	}()
+
+	httpServer := &http.Server{Handler: s, ReadHeaderTimeout: 10 * time.Second}
+	serveErr := make(chan error, 1)
+	go func() { serveErr <- httpServer.Serve(listener) }()
+
+	var err error
+	select {
+	case <-ctx.Done():
+	case err = <-serveErr:
+	}
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_ = httpServer.Shutdown(shutdownCtx)
+	stopWork()
+	<-workerDone
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
+}
+
+type statusJSON struct {
+	Paused   bool      `json:"paused"`
+	Running  *Job      `json:"running,omitempty"`
+	Queued   int       `json:"queued"`
+	Progress *Progress `json:"progress,omitempty"`
+}
+
+func (s *Server) status() statusJSON {
+	s.mu.Lock()
+	status := statusJSON{Paused: s.paused}
+	for _, job := range s.jobs {
+		switch job.State {
+		case JobQueued:
+			status.Queued++
+		case JobRunning:
+			running := *job
+			status.Running = &running
+		}
+	}
+	s.mu.Unlock()
+	if progress, ok := s.progress(); ok {
+		status.Progress = &progress
+	}
+	return status
+}
+
+func writeJSON(w http.ResponseWriter, code int, data any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(data)
+}
+
+func writeError(w http.ResponseWriter, code int, msg string) {
+	writeJSON(w, code, map[string]string{"error": msg})
+}
+
+func (s *Server) handleStatus(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, s.status())
+}
+
+func (s *Server) handleListJobs(w http.ResponseWriter, _ *http.Request) {
+	jobs := s.jobList()
+	writeJSON(w, http.StatusOK, map[string]any{"jobs": jobs, "total": len(jobs)})
+}
+
+type submitRequest struct {
+	URLs  []string `json:"urls"`
+	Media string   `json:"media,omitempty"`
+}
+
+func (s *Server) handleSubmit(w http.ResponseWriter, r *http.Request) {
+	var req submitRequest
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBody))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid request body: %v", err))
+		return
+	}
+	if len(req.URLs) == 0 {
+		writeError(w, http.StatusBadRequest, "urls must list at least one release ID or URL")
+		return
+	}
+	switch req.Media {
+	case "", "audio", "video", "both":
+	default:
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("media must be audio, video or both: %q", req.Media))
+		return
+	}
+	for i, url := range req.URLs {
+		url = strings.TrimSpace(url)
+		if s.deps.ValidURL != nil && !s.deps.ValidURL(url) {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid URL %q", url))
+			return
+		}
+		req.URLs[i] = url
+	}
+	writeJSON(w, http.StatusAccepted, map[string]any{"jobs": s.submit(req.Media, req.URLs)})
+}
+
+func jobID(w http.ResponseWriter, r *http.Request) (int, bool) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || id < 1 {
+		writeError(w, http.StatusBadRequest, "job ID must be a positive integer")
+		return 0, false
+	}
+	return id, true
+}
+
+func (s *Server) handleGetJob(w http.ResponseWriter, r *http.Request) {
+	id, ok := jobID(w, r)
+	if !ok {
+		return
+	}
+	job, found := s.job(id)
+	if !found {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("%v: %d", errJobNotFound, id))
+		return
+	}
+	writeJSON(w, http.StatusOK, job)
+}
+
+func (s *Server) handleCancelJob(w http.ResponseWriter, r *http.Request) {
+	id, ok := jobID(w, r)
+	if !ok {
+		return
+	}
+	job, err := s.cancel(id)
+	switch {
+	case errors.Is(err, errJobNotFound):
+		writeError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, errJobFinished):
+		writeError(w, http.StatusConflict, err.Error())
+	default:
+		writeJSON(w, http.StatusAccepted, job)
+	}
+}
+
+func (s *Server) handleCancel(w http.ResponseWriter, _ *http.Request) {
+	id, ok := s.cancelCurrent()
+	if !ok {
+		writeError(w, http.StatusConflict, "no job is running")
+		return
+	}
+	writeJSON(w, http.StatusAccepted, map[string]int{"cancelled": id})
+}
+
+func (s *Server) handlePause(paused bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		if s.deps.SetPaused != nil {
+			if err := s.deps.SetPaused(paused); err != nil {
+				writeError(w, http.StatusInternalServerError, err.Error())
+				return
+			}
+		}
+		s.mu.Lock()
+		s.paused = paused
+		s.mu.Unlock()
+		s.publish(eventPaused, map[string]bool{"paused": paused})
+		writeJSON(w, http.StatusOK, map[string]bool{"paused": paused})
+	}
+}
+
+// handleCatalog maps GET /api/catalog/<command>[/<artist>]?media=&limit= onto
+// `nugs catalog <command> [artist] [limit] [media] --json standard` and
+// relays its JSON.
+func (s *Server) handleCatalog(w http.ResponseWriter, r *http.Request) {
+	if s.deps.CatalogQuery == nil {
+		writeError(w, http.StatusNotImplemented, "catalog queries are unavailable")
+		return
+	}
+	command := r.PathValue("command")
+	artist := r.PathValue("artist")
+	spec, ok := catalogCommands[command]
+	switch {
+	case !ok:
+		writeError(w, http.StatusNotFound, fmt.Sprintf("unknown catalog command %q", command))
+		return
+	case artist == "" && spec.artistRequired:
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("catalog %s requires an artist", command))
+		return
+	case artist != "" && !spec.artistAllowed:
+		writeError(w, http.StatusNotFound, fmt.Sprintf("catalog %s does not take an artist", command))
+		return
+	}
+
+	req := CatalogRequest{Command: command}
+	if artist != "" {
+		id, err := s.resolveArtist(r.Context(), artist)
+		if err != nil {
+			code := http.StatusNotFound
+			var ambiguous *catalog.AmbiguousArtistError
+			if errors.As(err, &ambiguous) {
+				code = http.StatusConflict
+			}
+			writeError(w, code, err.Error())
+			return
+		}
+		req.ArtistID = id
+	}
+	query := r.URL.Query()
+	if limit := query.Get("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 1 || command != "latest" {
+			writeError(w, http.StatusBadRequest, "limit must be a positive integer and only applies to latest")
+			return
+		}
+		req.Limit = n
+	}
+	if media := query.Get("media"); media != "" {
+		req.Media = model.ParseMediaType(media)
+		if req.Media == model.MediaTypeUnknown {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("media must be audio, video or both: %q", media))
+			return
+		}
+	}
+
+	out, err := s.deps.CatalogQuery(r.Context(), req)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if !json.Valid(out) {
+		writeError(w, http.StatusInternalServerError, "catalog query returned non-JSON output")
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(out)
+}
+
+// resolveArtist maps an artist path segment to a numeric ID. Without a
+// ResolveArtist callback only positive numeric IDs are accepted.
+func (s *Server) resolveArtist(ctx context.Context, artist string) (string, error) {
+	if s.deps.ResolveArtist != nil {
+		return s.deps.ResolveArtist(ctx, artist)
+	}
+	if n, err := strconv.Atoi(artist); err != nil || n < 1 {
+		return "", fmt.Errorf("unknown artist %q", artist)
+	}
+	return artist, nil
+}
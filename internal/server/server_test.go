@@ -0,0 +1,281 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jmagar/nugs-cli/internal/catalog"
+	"github.com/jmagar/nugs-cli/internal/model"
+)
+
+func startTestServer(t *testing.T, deps *Deps) (*Server, *httptest.Server) {
+	t.Helper()
+	srv := New(deps)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		srv.Work(ctx)
+	}()
+	ts := httptest.NewServer(srv)
+	t.Cleanup(func() {
+		ts.Close()
+		cancel()
+		<-done
+	})
+	return srv, ts
+}
+
+func doJSON(t *testing.T, method, url, body string, out any) int {
+	t.Helper()
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("NewRequest: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("%s %s: %v", method, url, err)
+	}
+	defer resp.Body.Close()
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			t.Fatalf("decode %s %s: %v", method, url, err)
+		}
+	}
+	return resp.StatusCode
+}
+
+func waitForState(t *testing.T, srv *Server, id int, state string) Job {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if job, ok := srv.job(id); ok && job.State == state {
+			return job
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	job, _ := srv.job(id)
+	t.Fatalf("job %d state = %q, want %q", id, job.State, state)
+	return job
+}
+
+func TestSubmitRunsJobsInOrderAndCancels(t *testing.T) {
+	release := make(chan struct{})
+	var grabbed []string
+	srv, ts := startTestServer(t, &Deps{
+		Grab: func(ctx context.Context, url, media string) error {
+			grabbed = append(grabbed, url+"/"+media)
+			switch url {
+			case "1":
+				<-release
+				return nil
+			case "2":
+				return errors.New("boom")
+			case "4":
+				<-ctx.Done()
+				return ctx.Err()
+			}
+			return nil
+		},
+		ValidURL: func(url string) bool { return url != "bad" },
+	})
+
+	if code := doJSON(t, http.MethodPost, ts.URL+"/api/jobs", `{"urls":["bad"]}`, nil); code != http.StatusBadRequest {
+		t.Fatalf("invalid URL status = %d, want 400", code)
+	}
+	if code := doJSON(t, http.MethodPost, ts.URL+"/api/jobs", `{"urls":["1"],"media":"flac"}`, nil); code != http.StatusBadRequest {
+		t.Fatalf("invalid media status = %d, want 400", code)
+	}
+
+	var submitted struct{ Jobs []Job }
+	if code := doJSON(t, http.MethodPost, ts.URL+"/api/jobs", `{"urls":["1","2","3","4"],"media":"video"}`, &submitted); code != http.StatusAccepted {
+		t.Fatalf("submit status = %d, want 202", code)
+	}
+	if len(submitted.Jobs) != 4 || submitted.Jobs[0].ID != 1 || submitted.Jobs[3].ID != 4 {
+		t.Fatalf("submitted = %+v", submitted.Jobs)
+	}
+
+	waitForState(t, srv, 1, JobRunning)
+	var cancelled Job
+	if code := doJSON(t, http.MethodPost, ts.URL+"/api/jobs/3/cancel", "", &cancelled); code != http.StatusAccepted || cancelled.State != JobCancelled {
+		t.Fatalf("cancel queued job = %d %+v", code, cancelled)
+	}
+	close(release)
+
+	waitForState(t, srv, 1, JobCompleted)
+	if failed := waitForState(t, srv, 2, JobFailed); failed.Error != "boom" {
+		t.Fatalf("job 2 error = %q, want boom", failed.Error)
+	}
+	waitForState(t, srv, 4, JobRunning)
+	var current map[string]int
+	if code := doJSON(t, http.MethodPost, ts.URL+"/api/cancel", "", &current); code != http.StatusAccepted || current["cancelled"] != 4 {
+		t.Fatalf("cancel running = %d %v", code, current)
+	}
+	waitForState(t, srv, 4, JobCancelled)
+	if code := doJSON(t, http.MethodPost, ts.URL+"/api/jobs/4/cancel", "", nil); code != http.StatusConflict {
+		t.Fatalf("cancel finished job status = %d, want 409", code)
+	}
+
+	want := []string{"1/video", "2/video", "4/video"}
+	if strings.Join(grabbed, ",") != strings.Join(want, ",") {
+		t.Fatalf("grabbed = %v, want %v", grabbed, want)
+	}
+	var list struct {
+		Jobs  []Job
+		Total int
+	}
+	doJSON(t, http.MethodGet, ts.URL+"/api/jobs", "", &list)
+	if list.Total != 4 {
+		t.Fatalf("list total = %d, want 4", list.Total)
+	}
+}
+
+func TestEventsStreamJobAndProgress(t *testing.T) {
+	box := &model.ProgressBoxState{ShowTitle: "Goose 2024-05-01", TrackNumber: 2, TrackTotal: 10, DownloadPercent: 40}
+	release := make(chan struct{})
+	var paused []bool
+	_, ts := startTestServer(t, &Deps{
+		Grab: func(ctx context.Context, url, media string) error {
+			<-release
+			return nil
+		},
+		ProgressBox: func() *model.ProgressBoxState { return box },
+		SetPaused: func(p bool) error {
+			paused = append(paused, p)
+			return nil
+		},
+	})
+	defer close(release)
+
+	resp, err := http.Get(ts.URL + "/api/events")
+	if err != nil {
+		t.Fatalf("GET events: %v", err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("Content-Type = %q", ct)
+	}
+	events := make(chan string, 16)
+	go func() {
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			if name, ok := strings.CutPrefix(scanner.Text(), "event: "); ok {
+				events <- name
+			}
+		}
+		close(events)
+	}()
+	expect := func(name string) {
+		t.Helper()
+		timeout := time.After(5 * time.Second)
+		for {
+			select {
+			case got, ok := <-events:
+				if !ok {
+					t.Fatalf("stream closed waiting for %q", name)
+				}
+				if got == name {
+					return
+				}
+			case <-timeout:
+				t.Fatalf("timed out waiting for %q event", name)
+			}
+		}
+	}
+
+	expect("status")
+	doJSON(t, http.MethodPost, ts.URL+"/api/jobs", `{"urls":["23329"]}`, nil)
+	expect(eventJob)
+	expect(eventProgress)
+	if code := doJSON(t, http.MethodPost, ts.URL+"/api/pause", "", nil); code != http.StatusOK {
+		t.Fatalf("pause status = %d", code)
+	}
+	expect(eventPaused)
+	var status statusJSON
+	doJSON(t, http.MethodGet, ts.URL+"/api/status", "", &status)
+	if !status.Paused || status.Running == nil || status.Progress == nil || status.Progress.TrackTotal != 10 {
+		t.Fatalf("status = %+v", status)
+	}
+	if len(paused) != 1 || !paused[0] {
+		t.Fatalf("SetPaused calls = %v", paused)
+	}
+}
+
+func TestCatalogQueryRequestAndValidation(t *testing.T) {
+	var got CatalogRequest
+	_, ts := startTestServer(t, &Deps{
+		Grab: func(context.Context, string, string) error { return nil },
+		CatalogQuery: func(_ context.Context, req CatalogRequest) ([]byte, error) {
+			got = req
+			return []byte(`{"ok":true}`), nil
+		},
+		ResolveArtist: func(_ context.Context, query string) (string, error) {
+			switch query {
+			case "1125", "billy":
+				return "1125", nil
+			case "goose":
+				return "", &catalog.AmbiguousArtistError{Query: query, Candidates: []catalog.ArtistCandidate{{ID: 1, Name: "Goose"}, {ID: 2, Name: "Goose Creek"}}}
+			}
+			return "", fmt.Errorf("no artist matches %q", query)
+		},
+	})
+
+	var out map[string]bool
+	if code := doJSON(t, http.MethodGet, ts.URL+"/api/catalog/gaps/billy?media=video", "", &out); code != http.StatusOK || !out["ok"] {
+		t.Fatalf("gaps = %d %v", code, out)
+	}
+	if want := (CatalogRequest{Command: "gaps", ArtistID: "1125", Media: model.MediaTypeVideo}); got != want {
+		t.Fatalf("request = %+v, want %+v", got, want)
+	}
+	doJSON(t, http.MethodGet, ts.URL+"/api/catalog/latest?limit=5", "", nil)
+	if want := (CatalogRequest{Command: "latest", Limit: 5}); got != want {
+		t.Fatalf("request = %+v, want %+v", got, want)
+	}
+
+	for path, want := range map[string]int{
+		"/api/catalog/update":          http.StatusNotFound,
+		"/api/catalog/gaps":            http.StatusBadRequest,
+		"/api/catalog/stats/1125":      http.StatusNotFound,
+		"/api/catalog/list/abc":        http.StatusNotFound,
+		"/api/catalog/list/goose":      http.StatusConflict,
+		"/api/catalog/stats?limit=3":   http.StatusBadRequest,
+		"/api/catalog/stats?media=wav": http.StatusBadRequest,
+	} {
+		if code := doJSON(t, http.MethodGet, ts.URL+path, "", nil); code != want {
+			t.Errorf("GET %s = %d, want %d", path, code, want)
+		}
+	}
+}
+
+func TestRejectsNonLocalRequests(t *testing.T) {
+	_, ts := startTestServer(t, &Deps{Grab: func(context.Context, string, string) error { return nil }})
+
+	for name, mutate := range map[string]func(*http.Request){
+		"rebound host":  func(r *http.Request) { r.Host = "evil.example:8765" },
+		"remote origin": func(r *http.Request) { r.Header.Set("Origin", "https://evil.example") },
+	} {
+		req, _ := http.NewRequest(http.MethodPost, ts.URL+"/api/pause", nil)
+		mutate(req)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusForbidden {
+			t.Errorf("%s: status = %d, want 403", name, resp.StatusCode)
+		}
+	}
+
+	if _, err := Listen("0.0.0.0:0"); err == nil {
+		t.Fatal("Listen accepted a non-loopback address")
+	}
+}
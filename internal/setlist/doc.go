@@ -0,0 +1,4 @@
+// Package setlist builds the song-level index over cached artist metadata
+// and answers the `nugs song` queries: every performance of a song, and
+// per-song and per-year play counts.
+package setlist
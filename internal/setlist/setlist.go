@@ -0,0 +1,276 @@
+package setlist
+
+import (
+	"cmp"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/jmagar/nugs-cli/internal/helpers"
+	"github.com/jmagar/nugs-cli/internal/model"
+)
+
+// IndexVersion is bumped whenever the index layout changes, so an index
+// written by an older build is rebuilt rather than misread.
+const IndexVersion = 2
+
+// Build indexes the songs of every show in pages, which holds each cached
+// artist's catalog.containersAll responses.
+func Build(pages map[string][]*model.ArtistMeta) *model.SetlistIndex {
+	return Refresh(nil, pages, nil)
+}
+
+// Refresh returns a new index holding index's performances with those of the
+// artists in pages re-read from their pages and those of the artists in drop
+// removed. Other artists are carried over without reading their metadata.
+// The caller maintains the Artists map.
+func Refresh(index *model.SetlistIndex, pages map[string][]*model.ArtistMeta, drop []string) *model.SetlistIndex {
+	out := &model.SetlistIndex{Version: IndexVersion, BuiltAt: time.Now()}
+	seen := map[[2]int]struct{}{}
+	if index != nil {
+		replaced := map[int]struct{}{}
+		for _, id := range append(slices.Collect(maps.Keys(pages)), drop...) {
+			if n, err := strconv.Atoi(id); err == nil {
+				replaced[n] = struct{}{}
+			}
+		}
+		for _, perf := range index.Performances {
+			if _, ok := replaced[perf.ArtistID]; ok {
+				continue
+			}
+			out.Performances = append(out.Performances, perf)
+			if perf.TrackID != 0 {
+				seen[[2]int{perf.ContainerID, perf.TrackID}] = struct{}{}
+			}
+		}
+	}
+	for _, artistPages := range pages {
+		for _, page := range artistPages {
+			if page == nil {
+				continue
+			}
+			for _, show := range page.Response.Containers {
+				if show == nil || show.ContainerID == 0 {
+					continue
+				}
+				out.Performances = append(out.Performances, ShowPerformances(show, seen)...)
+			}
+		}
+	}
+	slices.SortFunc(out.Performances, comparePerformances)
+	return out
+}
+
+// ShowPerformances lists the songs of one show. seen, when non-nil, drops
+// tracks already indexed from another page or cache file.
+func ShowPerformances(show *model.AlbArtResp, seen map[[2]int]struct{}) []model.SongPerformance {
+	tracks := show.Songs
+	if len(tracks) == 0 {
+		tracks = show.Tracks
+	}
+	venue := show.VenueName
+	if venue == "" {
+		venue = show.Venue
+	}
+	date := helpers.ShowDate(show)
+	var perfs []model.SongPerformance
+	for i, track := range tracks {
+		title := strings.TrimSpace(track.SongTitle)
+		if title == "" {
+			continue
+		}
+		if seen != nil {
+			key := [2]int{show.ContainerID, track.TrackID}
+			if track.TrackID == 0 {
+				key[1] = -(i + 1)
+			}
+			if _, dup := seen[key]; dup {
+				continue
+			}
+			seen[key] = struct{}{}
+		}
+		perfs = append(perfs, model.SongPerformance{
+			ArtistID:    show.ArtistID,
+			ArtistName:  show.ArtistName,
+			ContainerID: show.ContainerID,
+			Date:        date,
+			Venue:       venue,
+			SongID:      track.SongID,
+			SongTitle:   title,
+			TrackID:     track.TrackID,
+			SetNum:      track.SetNum,
+			TrackNum:    track.TrackNum,
+			Duration:    track.TotalRunningTime,
+		})
+	}
+	return perfs
+}
+
+// comparePerformances orders newest show first, then by position in the show.
+func comparePerformances(a, b model.SongPerformance) int {
+	if c := cmp.Compare(b.Date, a.Date); c != 0 {
+		return c
+	}
+	if c := cmp.Compare(a.ContainerID, b.ContainerID); c != 0 {
+		return c
+	}
+	if c := cmp.Compare(a.SetNum, b.SetNum); c != 0 {
+		return c
+	}
+	return cmp.Compare(a.TrackNum, b.TrackNum)
+}
+
+// NormalizeTitle folds case and punctuation so "Tweezer Reprise",
+// "tweezer-reprise" and "Tweezer Reprise >" compare equal.
+func NormalizeTitle(s string) string {
+	return strings.Join(strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}), " ")
+}
+
+// ForArtist returns the artist's performances.
+func ForArtist(index *model.SetlistIndex, artistID int) []model.SongPerformance {
+	var perfs []model.SongPerformance
+	for _, perf := range index.Performances {
+		if perf.ArtistID == artistID {
+			perfs = append(perfs, perf)
+		}
+	}
+	return perfs
+}
+
+// Find returns the performances of the song named by query, newest first,
+// and the distinct titles that matched. A title equal to the query wins;
+// otherwise every title containing the query's words in order matches.
+func Find(perfs []model.SongPerformance, query string) ([]model.SongPerformance, []string) {
+	key := NormalizeTitle(query)
+	if key == "" {
+		return nil, nil
+	}
+	var exact, partial []model.SongPerformance
+	for _, perf := range perfs {
+		title := NormalizeTitle(perf.SongTitle)
+		switch {
+		case title == key:
+			exact = append(exact, perf)
+		case strings.Contains(" "+title+" ", " "+key+" ") || strings.HasPrefix(title, key):
+			partial = append(partial, perf)
+		}
+	}
+	matches := exact
+	if len(matches) == 0 {
+		matches = partial
+	}
+	return matches, Titles(matches)
+}
+
+// Titles lists the distinct song titles in perfs, most played first.
+func Titles(perfs []model.SongPerformance) []string {
+	stats := Stats(perfs)
+	titles := make([]string, len(stats))
+	for i, stat := range stats {
+		titles[i] = stat.Title
+	}
+	return titles
+}
+
+// SongStat summarises how often a song was played.
+type SongStat struct {
+	Title string `json:"songTitle"`
+	Plays int    `json:"plays"`
+	Shows int    `json:"shows"`
+	First string `json:"firstPlayed,omitempty"`
+	Last  string `json:"lastPlayed,omitempty"`
+}
+
+// Stats groups perfs by song, most played first. A song played twice in
+// one show (a reprise, say) counts two plays but one show.
+func Stats(perfs []model.SongPerformance) []SongStat {
+	byTitle := map[string]*SongStat{}
+	shows := map[string]map[int]struct{}{}
+	var order []string
+	for _, perf := range perfs {
+		key := NormalizeTitle(perf.SongTitle)
+		stat, ok := byTitle[key]
+		if !ok {
+			stat = &SongStat{Title: perf.SongTitle}
+			byTitle[key] = stat
+			shows[key] = map[int]struct{}{}
+			order = append(order, key)
+		}
+		stat.Plays++
+		shows[key][perf.ContainerID] = struct{}{}
+		if perf.Date != "" {
+			if stat.First == "" || perf.Date < stat.First {
+				stat.First = perf.Date
+			}
+			if perf.Date > stat.Last {
+				stat.Last = perf.Date
+			}
+		}
+	}
+	stats := make([]SongStat, 0, len(order))
+	for _, key := range order {
+		stat := byTitle[key]
+		stat.Shows = len(shows[key])
+		stats = append(stats, *stat)
+	}
+	slices.SortStableFunc(stats, func(a, b SongStat) int {
+		if c := cmp.Compare(b.Plays, a.Plays); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Title, b.Title)
+	})
+	return stats
+}
+
+// YearStat summarises one year of an artist's indexed shows.
+type YearStat struct {
+	Year     string `json:"year"`
+	Shows    int    `json:"shows"`
+	Plays    int    `json:"plays"`
+	Songs    int    `json:"distinctSongs"`
+	TopSong  string `json:"topSong,omitempty"`
+	TopPlays int    `json:"topSongPlays,omitempty"`
+}
+
+// Years groups perfs by performance year, newest first. Performances without
+// a parsed date are left out.
+func Years(perfs []model.SongPerformance) []YearStat {
+	byYear := map[string][]model.SongPerformance{}
+	for _, perf := range perfs {
+		if len(perf.Date) < 4 || perf.Date[4:5] != "-" {
+			continue
+		}
+		byYear[perf.Date[:4]] = append(byYear[perf.Date[:4]], perf)
+	}
+	years := make([]YearStat, 0, len(byYear))
+	for year, yearPerfs := range byYear {
+		stats := Stats(yearPerfs)
+		shows := map[int]struct{}{}
+		for _, perf := range yearPerfs {
+			shows[perf.ContainerID] = struct{}{}
+		}
+		ys := YearStat{Year: year, Shows: len(shows), Plays: len(yearPerfs), Songs: len(stats)}
+		if len(stats) > 0 {
+			ys.TopSong, ys.TopPlays = stats[0].Title, stats[0].Plays
+		}
+		years = append(years, ys)
+	}
+	slices.SortFunc(years, func(a, b YearStat) int { return cmp.Compare(b.Year, a.Year) })
+	return years
+}
+
+// InYear keeps the performances from year (YYYY).
+func InYear(perfs []model.SongPerformance, year string) []model.SongPerformance {
+	var out []model.SongPerformance
+	for _, perf := range perfs {
+		if strings.HasPrefix(perf.Date, year+"-") {
+			out = append(out, perf)
+		}
+	}
+	return out
+}
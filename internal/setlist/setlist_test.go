@@ -0,0 +1,113 @@
+package setlist
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/jmagar/nugs-cli/internal/model"
+)
+
+func testShow(id int, date, venue string, songs ...string) *model.AlbArtResp {
+	show := &model.AlbArtResp{ContainerID: id, ArtistID: 1125, ArtistName: "Billy Strings", PerformanceDate: date, VenueName: venue}
+	for i, title := range songs {
+		show.Songs = append(show.Songs, model.Track{TrackID: id*100 + i, SongTitle: title, SetNum: 1, TrackNum: i + 1})
+	}
+	return show
+}
+
+func testPerformances() []model.SongPerformance {
+	page := &model.ArtistMeta{}
+	page.Response.Containers = []*model.AlbArtResp{
+		testShow(1, "2023-06-10", "Red Rocks", "Dust in a Baggie", "Meet Me at the Creek", "Dust in a Baggie"),
+		testShow(2, "2023-09-01", "Ryman", "Meet Me at the Creek", "Away From the Mire"),
+		testShow(3, "2022-12-31", "Fox Theatre", "Meet Me At The Creek >", "Dust In A Baggie"),
+	}
+	// The same show cached twice must be indexed once.
+	dup := &model.ArtistMeta{}
+	dup.Response.Containers = page.Response.Containers[:1]
+	return Build(map[string][]*model.ArtistMeta{"1125": {page}, "1125-full": {dup}}).Performances
+}
+
+func TestFind(t *testing.T) {
+	perfs := testPerformances()
+	tests := []struct {
+		query      string
+		wantShows  []int
+		wantTitles []string
+	}{
+		{query: "meet me at the creek", wantShows: []int{2, 1, 3}, wantTitles: []string{"Meet Me at the Creek"}},
+		{query: "DUST IN A BAGGIE", wantShows: []int{1, 1, 3}, wantTitles: []string{"Dust in a Baggie"}},
+		{query: "mire", wantShows: []int{2}, wantTitles: []string{"Away From the Mire"}},
+		{query: "fillmore", wantShows: nil, wantTitles: nil},
+	}
+	for _, tc := range tests {
+		got, titles := Find(perfs, tc.query)
+		var shows []int
+		for _, perf := range got {
+			shows = append(shows, perf.ContainerID)
+		}
+		if !slices.Equal(shows, tc.wantShows) {
+			t.Errorf("Find(%q) shows = %v, want %v", tc.query, shows, tc.wantShows)
+		}
+		if !slices.Equal(titles, tc.wantTitles) {
+			t.Errorf("Find(%q) titles = %q, want %q", tc.query, titles, tc.wantTitles)
+		}
+	}
+}
+
+func TestStats(t *testing.T) {
+	stats := Stats(testPerformances())
+	if len(stats) != 3 {
+		t.Fatalf("Stats() = %+v, want 3 songs", stats)
+	}
+	want := SongStat{Title: "Dust in a Baggie", Plays: 3, Shows: 2, First: "2022-12-31", Last: "2023-06-10"}
+	if stats[0] != want {
+		t.Fatalf("Stats()[0] = %+v, want %+v", stats[0], want)
+	}
+}
+
+func TestYears(t *testing.T) {
+	perfs := testPerformances()
+	years := Years(perfs)
+	if len(years) != 2 || years[0].Year != "2023" || years[1].Year != "2022" {
+		t.Fatalf("Years() = %+v, want 2023 then 2022", years)
+	}
+	if y := years[0]; y.Shows != 2 || y.Plays != 5 || y.Songs != 3 || y.TopSong != "Dust in a Baggie" {
+		t.Fatalf("Years()[0] = %+v", y)
+	}
+	if got := len(InYear(perfs, "2022")); got != 2 {
+		t.Fatalf("InYear(2022) = %d performances, want 2", got)
+	}
+}
+
+func TestRefresh(t *testing.T) {
+	billy := &model.ArtistMeta{}
+	billy.Response.Containers = []*model.AlbArtResp{testShow(1, "2023-06-10", "Red Rocks", "Dust in a Baggie")}
+	goose := &model.ArtistMeta{}
+	gooseShow := testShow(2, "2023-09-01", "Forest Hills", "Arcadia")
+	gooseShow.ArtistID = 461
+	goose.Response.Containers = []*model.AlbArtResp{gooseShow}
+	cheese := &model.ArtistMeta{}
+	cheeseShow := testShow(3, "2023-07-04", "Red Rocks", "Round the Wheel")
+	cheeseShow.ArtistID = 62
+	cheese.Response.Containers = []*model.AlbArtResp{cheeseShow}
+	index := Build(map[string][]*model.ArtistMeta{"1125": {billy}, "461": {goose}, "62": {cheese}})
+
+	updated := &model.ArtistMeta{}
+	updated.Response.Containers = []*model.AlbArtResp{
+		testShow(1, "2023-06-10", "Red Rocks", "Dust in a Baggie"),
+		testShow(4, "2024-01-01", "Ryman", "Away From the Mire"),
+	}
+	refreshed := Refresh(index, map[string][]*model.ArtistMeta{"1125": {updated}}, []string{"62"})
+
+	var shows []int
+	for _, perf := range refreshed.Performances {
+		shows = append(shows, perf.ContainerID)
+	}
+	if !slices.Equal(shows, []int{4, 2, 1}) {
+		t.Fatalf("refreshed shows = %v, want billy's two shows and goose kept, cheese dropped", shows)
+	}
+	if len(index.Performances) != 3 {
+		t.Fatalf("Refresh modified the original index: %+v", index.Performances)
+	}
+}
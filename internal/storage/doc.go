@@ -0,0 +1,5 @@
+// Package storage implements model.StorageProvider natively for backends
+// other than rclone: a local or NAS mirror directory, SFTP and S3-compatible
+// object stores. The backend is chosen by the storageBackend config field;
+// rclone remains the default and lives in package rclone.
+package storage
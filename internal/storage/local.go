@@ -0,0 +1,120 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// localStore mirrors uploads into a directory on this machine, typically a
+// NAS mount.
+type localStore struct{}
+
+func (localStore) put(ctx context.Context, localPath, remotePath string, onBytes func(n int64)) error {
+	dest := filepath.FromSlash(remotePath)
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return fmt.Errorf("failed to create destination directory: %w", err)
+	}
+	src, err := os.Open(localPath)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	tmp, err := os.CreateTemp(filepath.Dir(dest), "."+filepath.Base(dest)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+	tmpPath := tmp.Name()
+	ok := false
+	defer func() {
+		if !ok {
+			_ = tmp.Close()
+			_ = os.Remove(tmpPath)
+		}
+	}()
+	if _, err := io.Copy(tmp, &countingReader{r: &contextReader{ctx: ctx, r: src}, onBytes: onBytes}); err != nil {
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmpPath, 0644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpPath, dest); err != nil {
+		return err
+	}
+	ok = true
+	return nil
+}
+
+func (localStore) size(_ context.Context, remotePath string) (int64, bool, error) {
+	info, err := os.Stat(filepath.FromSlash(remotePath))
+	if errors.Is(err, fs.ErrNotExist) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	if info.IsDir() {
+		return 0, false, nil
+	}
+	return info.Size(), true, nil
+}
+
+func (localStore) exists(_ context.Context, remotePath string) (bool, error) {
+	_, err := os.Stat(filepath.FromSlash(remotePath))
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (localStore) listDirs(_ context.Context, remotePath string) ([]string, error) {
+	entries, err := os.ReadDir(filepath.FromSlash(remotePath))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			names = append(names, entry.Name())
+		}
+	}
+	return names, nil
+}
+
+func (localStore) get(ctx context.Context, remotePath string, w io.Writer) error {
+	src, err := os.Open(filepath.FromSlash(remotePath))
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	_, err = io.Copy(w, &contextReader{ctx: ctx, r: src})
+	return err
+}
+
+func (localStore) describe(remotePath string) string {
+	return filepath.FromSlash(remotePath)
+}
+
+// contextReader stops a copy once ctx is cancelled.
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (c *contextReader) Read(p []byte) (int, error) {
+	if err := c.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return c.r.Read(p)
+}
@@ -0,0 +1,474 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jmagar/nugs-cli/internal/model"
+)
+
+const (
+	// s3PartSize is the multipart part size; files up to this size use a
+	// single PUT. 64MiB keeps a 10,000-part upload well above any show.
+	s3PartSize        = 64 << 20
+	s3DefaultRegion   = "us-east-1"
+	s3UnsignedPayload = "UNSIGNED-PAYLOAD"
+	s3EmptyHash       = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+	s3TimeFormat      = "20060102T150405Z"
+)
+
+// s3Credentials returns the access key, secret key and optional session
+// token, falling back to the standard AWS environment variables.
+func s3Credentials(cfg *model.Config) (string, string, string, error) {
+	accessKey, secretKey := cfg.S3AccessKey, cfg.S3SecretKey
+	if accessKey == "" {
+		accessKey = os.Getenv("AWS_ACCESS_KEY_ID")
+	}
+	if secretKey == "" {
+		secretKey = os.Getenv("AWS_SECRET_ACCESS_KEY")
+	}
+	if accessKey == "" || secretKey == "" {
+		return "", "", "", errors.New("storageBackend s3 needs s3AccessKey and s3SecretKey (or AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY)")
+	}
+	return accessKey, secretKey, os.Getenv("AWS_SESSION_TOKEN"), nil
+}
+
+// s3Store uploads to an S3-compatible object store (AWS, MinIO, Garage,
+// Backblaze B2, ...) using path-style addressing and SigV4.
+type s3Store struct {
+	endpoint     *url.URL
+	region       string
+	bucket       string
+	accessKey    string
+	secretKey    string
+	sessionToken string
+	partSize     int64
+	client       *http.Client
+	now          func() time.Time
+}
+
+func newS3Store(cfg *model.Config) (*s3Store, error) {
+	accessKey, secretKey, sessionToken, err := s3Credentials(cfg)
+	if err != nil {
+		return nil, err
+	}
+	region := cfg.S3Region
+	if region == "" {
+		region = s3DefaultRegion
+	}
+	rawEndpoint := cfg.S3Endpoint
+	if rawEndpoint == "" {
+		rawEndpoint = "https://s3." + region + ".amazonaws.com"
+	}
+	endpoint, err := url.Parse(rawEndpoint)
+	if err != nil || (endpoint.Scheme != "http" && endpoint.Scheme != "https") || endpoint.Host == "" {
+		return nil, fmt.Errorf("invalid s3Endpoint %q: expected http(s)://host[:port]", cfg.S3Endpoint)
+	}
+	return &s3Store{
+		endpoint:     endpoint,
+		region:       region,
+		bucket:       cfg.S3Bucket,
+		accessKey:    accessKey,
+		secretKey:    secretKey,
+		sessionToken: sessionToken,
+		partSize:     s3PartSize,
+		client:       &http.Client{},
+		now:          time.Now,
+	}, nil
+}
+
+// s3Key converts a slash path to an object key.
+func s3Key(remotePath string) string {
+	return strings.Trim(remotePath, "/")
+}
+
+// s3Escape percent-encodes s as SigV4 requires: everything except
+// unreserved characters, and '/' too unless keepSlash is set.
+func s3Escape(s string, keepSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9',
+			c == '-', c == '_', c == '.', c == '~', c == '/' && keepSlash:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+func (s *s3Store) newRequest(ctx context.Context, method, key string, query url.Values, body io.Reader) (*http.Request, error) {
+	escapedPath := strings.TrimRight(s.endpoint.EscapedPath(), "/") + "/" + s3Escape(s.bucket, false)
+	if key != "" {
+		escapedPath += "/" + s3Escape(key, true)
+	}
+	target := s.endpoint.Scheme + "://" + s.endpoint.Host + escapedPath
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+	return http.NewRequestWithContext(ctx, method, target, body)
+}
+
+// sign adds SigV4 authorization headers to req. Every header already set on
+// req is signed along with host.
+func (s *s3Store) sign(req *http.Request, payloadHash string) {
+	now := s.now().UTC()
+	amzDate := now.Format(s3TimeFormat)
+	date := amzDate[:8]
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	if s.sessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", s.sessionToken)
+	}
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.Join(strings.Fields(strings.Join(values, ",")), " ")
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	query := req.URL.Query()
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var queryParts []string
+	for _, key := range keys {
+		values := append([]string(nil), query[key]...)
+		sort.Strings(values)
+		for _, value := range values {
+			queryParts = append(queryParts, s3Escape(key, false)+"="+s3Escape(value, false))
+		}
+	}
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		strings.Join(queryParts, "&"),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	scope := date + "/" + s.region + "/s3/aws4_request"
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(requestHash[:])
+
+	signingKey := hmacSHA256([]byte("AWS4"+s.secretKey), date)
+	for _, part := range []string{s.region, "s3", "aws4_request"} {
+		signingKey = hmacSHA256(signingKey, part)
+	}
+	signature := hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s,SignedHeaders=%s,Signature=%s",
+		s.accessKey, scope, signedHeaders, signature))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// s3Error is the XML error body S3 returns.
+type s3Error struct {
+	Code    string `xml:"Code"`
+	Message string `xml:"Message"`
+}
+
+// do signs and sends req and returns an error for non-2xx responses. The
+// caller closes the body of a successful response.
+func (s *s3Store) do(req *http.Request, payloadHash string) (*http.Response, error) {
+	s.sign(req, payloadHash)
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return resp, nil
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	var apiErr s3Error
+	if xml.Unmarshal(body, &apiErr) == nil && apiErr.Code != "" {
+		return nil, fmt.Errorf("s3 %s %s: %s: %s", req.Method, req.URL.Path, apiErr.Code, apiErr.Message)
+	}
+	return nil, fmt.Errorf("s3 %s %s: HTTP %d", req.Method, req.URL.Path, resp.StatusCode)
+}
+
+func (s *s3Store) put(ctx context.Context, localPath, remotePath string, onBytes func(n int64)) error {
+	file, err := os.Open(localPath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+	key := s3Key(remotePath)
+	if info.Size() > s.partSize {
+		return s.putMultipart(ctx, file, info.Size(), key, onBytes)
+	}
+	var body io.Reader = http.NoBody // a zero-length body must not be sent chunked
+	if info.Size() > 0 {
+		body = &countingReader{r: file, onBytes: onBytes}
+	}
+	req, err := s.newRequest(ctx, http.MethodPut, key, nil, body)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = info.Size()
+	resp, err := s.do(req, s3UnsignedPayload)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+type s3InitiateResult struct {
+	UploadID string `xml:"UploadId"`
+}
+
+type s3CompletedPart struct {
+	PartNumber int    `xml:"PartNumber"`
+	ETag       string `xml:"ETag"`
+}
+
+type s3CompleteUpload struct {
+	XMLName xml.Name          `xml:"CompleteMultipartUpload"`
+	Parts   []s3CompletedPart `xml:"Part"`
+}
+
+func (s *s3Store) putMultipart(ctx context.Context, file *os.File, size int64, key string, onBytes func(n int64)) (err error) {
+	req, err := s.newRequest(ctx, http.MethodPost, key, url.Values{"uploads": {""}}, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := s.do(req, s3EmptyHash)
+	if err != nil {
+		return err
+	}
+	var initiated s3InitiateResult
+	decodeErr := xml.NewDecoder(resp.Body).Decode(&initiated)
+	_ = resp.Body.Close()
+	if decodeErr != nil || initiated.UploadID == "" {
+		return fmt.Errorf("s3 multipart upload for %s returned no upload id", key)
+	}
+	defer func() {
+		if err == nil {
+			return
+		}
+		abort, reqErr := s.newRequest(context.WithoutCancel(ctx), http.MethodDelete, key, url.Values{"uploadId": {initiated.UploadID}}, nil)
+		if reqErr == nil {
+			if resp, abortErr := s.do(abort, s3EmptyHash); abortErr == nil {
+				_ = resp.Body.Close()
+			}
+		}
+	}()
+
+	var complete s3CompleteUpload
+	for partNumber, offset := 1, int64(0); offset < size; partNumber, offset = partNumber+1, offset+s.partSize {
+		length := min(s.partSize, size-offset)
+		query := url.Values{"partNumber": {strconv.Itoa(partNumber)}, "uploadId": {initiated.UploadID}}
+		body := &countingReader{r: io.NewSectionReader(file, offset, length), onBytes: onBytes}
+		req, err := s.newRequest(ctx, http.MethodPut, key, query, body)
+		if err != nil {
+			return err
+		}
+		req.ContentLength = length
+		resp, err := s.do(req, s3UnsignedPayload)
+		if err != nil {
+			return err
+		}
+		_ = resp.Body.Close()
+		complete.Parts = append(complete.Parts, s3CompletedPart{PartNumber: partNumber, ETag: resp.Header.Get("ETag")})
+	}
+
+	payload, err := xml.Marshal(complete)
+	if err != nil {
+		return err
+	}
+	req, err = s.newRequest(ctx, http.MethodPost, key, url.Values{"uploadId": {initiated.UploadID}}, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	hash := sha256.Sum256(payload)
+	resp, err = s.do(req, hex.EncodeToString(hash[:]))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	// CompleteMultipartUpload can fail after a 200 status; the error is in
+	// the body.
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	var apiErr s3Error
+	if xml.Unmarshal(body, &apiErr) == nil && apiErr.Code != "" {
+		return fmt.Errorf("s3 complete multipart upload for %s: %s: %s", key, apiErr.Code, apiErr.Message)
+	}
+	return nil
+}
+
+func (s *s3Store) size(ctx context.Context, remotePath string) (int64, bool, error) {
+	req, err := s.newRequest(ctx, http.MethodHead, s3Key(remotePath), nil, nil)
+	if err != nil {
+		return 0, false, err
+	}
+	s.sign(req, s3EmptyHash)
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, false, err
+	}
+	_ = resp.Body.Close()
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return 0, false, nil
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return resp.ContentLength, true, nil
+	}
+	return 0, false, fmt.Errorf("s3 HEAD %s: HTTP %d", req.URL.Path, resp.StatusCode)
+}
+
+func (s *s3Store) get(ctx context.Context, remotePath string, w io.Writer) error {
+	req, err := s.newRequest(ctx, http.MethodGet, s3Key(remotePath), nil, nil)
+	if err != nil {
+		return err
+	}
+	s.sign(req, s3EmptyHash)
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return fmt.Errorf("%s: %w", s.describe(remotePath), fs.ErrNotExist)
+	case resp.StatusCode < 200 || resp.StatusCode >= 300:
+		return fmt.Errorf("s3 GET %s: HTTP %d", req.URL.Path, resp.StatusCode)
+	}
+	_, err = io.Copy(w, resp.Body)
+	return err
+}
+
+// md5 returns the object's MD5 from its ETag without downloading it. ETags
+// of multipart uploads are not an MD5 of the content, so ok is false then.
+func (s *s3Store) md5(ctx context.Context, remotePath string) (string, bool, error) {
+	req, err := s.newRequest(ctx, http.MethodHead, s3Key(remotePath), nil, nil)
+	if err != nil {
+		return "", false, err
+	}
+	s.sign(req, s3EmptyHash)
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", false, err
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", false, nil
+	}
+	etag := strings.ToLower(strings.Trim(resp.Header.Get("ETag"), `"`))
+	if len(etag) != 32 || strings.Trim(etag, "0123456789abcdef") != "" {
+		return "", false, nil
+	}
+	return etag, true, nil
+}
+
+type s3ListResult struct {
+	KeyCount              int    `xml:"KeyCount"`
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+	CommonPrefixes        []struct {
+		Prefix string `xml:"Prefix"`
+	} `xml:"CommonPrefixes"`
+}
+
+func (s *s3Store) list(ctx context.Context, query url.Values) (*s3ListResult, error) {
+	query.Set("list-type", "2")
+	req, err := s.newRequest(ctx, http.MethodGet, "", query, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.do(req, s3EmptyHash)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	var result s3ListResult
+	if err := xml.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to parse s3 listing: %w", err)
+	}
+	return &result, nil
+}
+
+// dirPrefix is the key prefix of the "directory" at remotePath.
+func dirPrefix(remotePath string) string {
+	if key := s3Key(remotePath); key != "" {
+		return key + "/"
+	}
+	return ""
+}
+
+// exists reports whether remotePath is an object or a prefix of one; object
+// stores have no real directories.
+func (s *s3Store) exists(ctx context.Context, remotePath string) (bool, error) {
+	if s3Key(remotePath) != "" {
+		if _, found, err := s.size(ctx, remotePath); err != nil || found {
+			return found, err
+		}
+	}
+	result, err := s.list(ctx, url.Values{"prefix": {dirPrefix(remotePath)}, "max-keys": {"1"}})
+	if err != nil {
+		return false, err
+	}
+	return result.KeyCount > 0, nil
+}
+
+func (s *s3Store) listDirs(ctx context.Context, remotePath string) ([]string, error) {
+	prefix := dirPrefix(remotePath)
+	query := url.Values{"prefix": {prefix}, "delimiter": {"/"}}
+	var names []string
+	for {
+		result, err := s.list(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+		for _, common := range result.CommonPrefixes {
+			if name := strings.TrimSuffix(strings.TrimPrefix(common.Prefix, prefix), "/"); name != "" {
+				names = append(names, name)
+			}
+		}
+		if !result.IsTruncated || result.NextContinuationToken == "" {
+			return names, nil
+		}
+		query.Set("continuation-token", result.NextContinuationToken)
+	}
+}
+
+func (s *s3Store) describe(remotePath string) string {
+	return "s3://" + s.bucket + "/" + s3Key(remotePath)
+}
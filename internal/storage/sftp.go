@@ -0,0 +1,795 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/jmagar/nugs-cli/internal/model"
+)
+
+// SFTP version 3 packet types and constants (draft-ietf-secsh-filexfer-02).
+const (
+	sftpInit     = 1
+	sftpVersion  = 2
+	sftpOpen     = 3
+	sftpClose    = 4
+	sftpRead     = 5
+	sftpWrite    = 6
+	sftpOpendir  = 11
+	sftpReaddir  = 12
+	sftpRemove   = 13
+	sftpMkdir    = 14
+	sftpStat     = 17
+	sftpRename   = 18
+	sftpStatus   = 101
+	sftpHandle   = 102
+	sftpData     = 103
+	sftpName     = 104
+	sftpAttrs    = 105
+	sftpExtended = 200
+
+	sftpStatusOK         = 0
+	sftpStatusEOF        = 1
+	sftpStatusNoSuchFile = 2
+
+	sftpFlagRead   = 0x01
+	sftpFlagWrite  = 0x02
+	sftpFlagCreate = 0x08
+	sftpFlagTrunc  = 0x10
+
+	sftpAttrSize        = 0x01
+	sftpAttrUIDGID      = 0x02
+	sftpAttrPermissions = 0x04
+	sftpAttrACModTime   = 0x08
+	sftpAttrExtended    = 0x80000000
+
+	sftpModeTypeMask = 0o170000
+	sftpModeDir      = 0o040000
+
+	// sftpChunkSize is the READ and WRITE payload size; OpenSSH accepts up
+	// to 256KiB but 32KiB is the limit every server must support.
+	sftpChunkSize = 32 * 1024
+	// sftpWriteWindow is the number of READ or WRITE requests kept in flight.
+	sftpWriteWindow = 16
+	// sftpMaxPacket bounds the size of a response we are willing to buffer.
+	sftpMaxPacket = 1 << 20
+
+	posixRenameExtension = "posix-rename@openssh.com"
+)
+
+// sftpStatusError is a non-OK SSH_FXP_STATUS reply. Status errors leave the
+// connection usable; any other error resets it.
+type sftpStatusError struct {
+	code uint32
+	msg  string
+}
+
+func (e *sftpStatusError) Error() string {
+	if e.msg != "" {
+		return fmt.Sprintf("sftp: %s (status %d)", e.msg, e.code)
+	}
+	return fmt.Sprintf("sftp: status %d", e.code)
+}
+
+func isSFTPStatus(err error, code uint32) bool {
+	var statusErr *sftpStatusError
+	return errors.As(err, &statusErr) && statusErr.code == code
+}
+
+// sftpPacket is a decoded response: its type and the bytes after the id.
+type sftpPacket struct {
+	typ  byte
+	data []byte
+}
+
+// sftpBuilder encodes SFTP request fields.
+type sftpBuilder []byte
+
+func (b sftpBuilder) uint32(v uint32) sftpBuilder { return binary.BigEndian.AppendUint32(b, v) }
+func (b sftpBuilder) uint64(v uint64) sftpBuilder { return binary.BigEndian.AppendUint64(b, v) }
+func (b sftpBuilder) bytes(v []byte) sftpBuilder  { return append(b.uint32(uint32(len(v))), v...) }
+func (b sftpBuilder) string(v string) sftpBuilder { return append(b.uint32(uint32(len(v))), v...) }
+
+// sftpParser decodes SFTP response fields; the first short read sticks in err.
+type sftpParser struct {
+	data []byte
+	err  error
+}
+
+func (p *sftpParser) uint32() uint32 {
+	if p.err != nil || len(p.data) < 4 {
+		p.err = errors.New("sftp: short packet")
+		return 0
+	}
+	v := binary.BigEndian.Uint32(p.data)
+	p.data = p.data[4:]
+	return v
+}
+
+func (p *sftpParser) uint64() uint64 {
+	if p.err != nil || len(p.data) < 8 {
+		p.err = errors.New("sftp: short packet")
+		return 0
+	}
+	v := binary.BigEndian.Uint64(p.data)
+	p.data = p.data[8:]
+	return v
+}
+
+func (p *sftpParser) string() string {
+	n := p.uint32()
+	if p.err != nil || uint32(len(p.data)) < n {
+		p.err = errors.New("sftp: short packet")
+		return ""
+	}
+	v := string(p.data[:n])
+	p.data = p.data[n:]
+	return v
+}
+
+// sftpFileAttrs holds the attributes nugs cares about.
+type sftpFileAttrs struct {
+	size    int64
+	hasSize bool
+	mode    uint32
+	hasMode bool
+}
+
+func (a sftpFileAttrs) isDir() bool {
+	return a.hasMode && a.mode&sftpModeTypeMask == sftpModeDir
+}
+
+func (p *sftpParser) attrs() sftpFileAttrs {
+	var attrs sftpFileAttrs
+	flags := p.uint32()
+	if flags&sftpAttrSize != 0 {
+		attrs.size, attrs.hasSize = int64(p.uint64()), true
+	}
+	if flags&sftpAttrUIDGID != 0 {
+		p.uint32()
+		p.uint32()
+	}
+	if flags&sftpAttrPermissions != 0 {
+		attrs.mode, attrs.hasMode = p.uint32(), true
+	}
+	if flags&sftpAttrACModTime != 0 {
+		p.uint32()
+		p.uint32()
+	}
+	if flags&sftpAttrExtended != 0 {
+		for range p.uint32() {
+			p.string()
+			p.string()
+			if p.err != nil {
+				break
+			}
+		}
+	}
+	return attrs
+}
+
+// readSFTPPacket reads one length-prefixed packet.
+func readSFTPPacket(r io.Reader) (byte, []byte, error) {
+	var header [5]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return 0, nil, err
+	}
+	length := binary.BigEndian.Uint32(header[:4])
+	if length < 1 || length > sftpMaxPacket {
+		return 0, nil, fmt.Errorf("sftp: invalid packet length %d", length)
+	}
+	body := make([]byte, length-1)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return 0, nil, err
+	}
+	return header[4], body, nil
+}
+
+func writeSFTPPacket(w io.Writer, typ byte, payload []byte) error {
+	packet := make([]byte, 0, 5+len(payload))
+	packet = binary.BigEndian.AppendUint32(packet, uint32(len(payload)+1))
+	packet = append(packet, typ)
+	packet = append(packet, payload...)
+	_, err := w.Write(packet)
+	return err
+}
+
+// sftpConn is one SFTP session. Requests are multiplexed by id so upload
+// workers can share a connection and pipeline writes.
+type sftpConn struct {
+	w     io.Writer
+	wmu   sync.Mutex
+	close func() error
+
+	mu         sync.Mutex
+	nextID     uint32
+	pending    map[uint32]chan sftpPacket
+	err        error
+	extensions map[string]string
+}
+
+// newSFTPConn performs the version handshake and starts the response
+// dispatcher.
+func newSFTPConn(r io.Reader, w io.Writer, closeFn func() error) (*sftpConn, error) {
+	if err := writeSFTPPacket(w, sftpInit, sftpBuilder{}.uint32(3)); err != nil {
+		return nil, fmt.Errorf("sftp handshake failed: %w", err)
+	}
+	typ, body, err := readSFTPPacket(r)
+	if err != nil {
+		return nil, fmt.Errorf("sftp handshake failed: %w", err)
+	}
+	if typ != sftpVersion {
+		return nil, fmt.Errorf("sftp handshake failed: unexpected packet type %d", typ)
+	}
+	parser := &sftpParser{data: body}
+	if version := parser.uint32(); version < 3 {
+		return nil, fmt.Errorf("sftp server speaks protocol version %d, need 3", version)
+	}
+	extensions := map[string]string{}
+	for len(parser.data) > 0 && parser.err == nil {
+		name, value := parser.string(), parser.string()
+		if parser.err == nil {
+			extensions[name] = value
+		}
+	}
+	conn := &sftpConn{w: w, close: closeFn, pending: map[uint32]chan sftpPacket{}, extensions: extensions}
+	go conn.dispatch(r)
+	return conn, nil
+}
+
+func (c *sftpConn) dispatch(r io.Reader) {
+	for {
+		typ, body, err := readSFTPPacket(r)
+		if err == nil && len(body) < 4 {
+			err = errors.New("sftp: short packet")
+		}
+		if err != nil {
+			c.fail(fmt.Errorf("sftp connection lost: %w", err))
+			return
+		}
+		id := binary.BigEndian.Uint32(body)
+		c.mu.Lock()
+		ch := c.pending[id]
+		delete(c.pending, id)
+		c.mu.Unlock()
+		if ch != nil {
+			ch <- sftpPacket{typ: typ, data: body[4:]}
+		}
+	}
+}
+
+// fail marks the connection broken and wakes every waiting request.
+func (c *sftpConn) fail(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.err == nil {
+		c.err = err
+	}
+	for id, ch := range c.pending {
+		close(ch)
+		delete(c.pending, id)
+	}
+}
+
+func (c *sftpConn) broken() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.err
+}
+
+// send writes a request and returns the channel its response arrives on.
+func (c *sftpConn) send(typ byte, payload sftpBuilder) (chan sftpPacket, error) {
+	ch := make(chan sftpPacket, 1)
+	c.mu.Lock()
+	if c.err != nil {
+		c.mu.Unlock()
+		return nil, c.err
+	}
+	c.nextID++
+	id := c.nextID
+	c.pending[id] = ch
+	c.mu.Unlock()
+
+	c.wmu.Lock()
+	err := writeSFTPPacket(c.w, typ, append(sftpBuilder{}.uint32(id), payload...))
+	c.wmu.Unlock()
+	if err != nil {
+		c.fail(fmt.Errorf("sftp connection lost: %w", err))
+		return nil, c.broken()
+	}
+	return ch, nil
+}
+
+func (c *sftpConn) wait(ctx context.Context, ch chan sftpPacket) (sftpPacket, error) {
+	select {
+	case packet, ok := <-ch:
+		if !ok {
+			return sftpPacket{}, c.broken()
+		}
+		return packet, nil
+	case <-ctx.Done():
+		return sftpPacket{}, ctx.Err()
+	}
+}
+
+func (c *sftpConn) request(ctx context.Context, typ byte, payload sftpBuilder) (sftpPacket, error) {
+	ch, err := c.send(typ, payload)
+	if err != nil {
+		return sftpPacket{}, err
+	}
+	return c.wait(ctx, ch)
+}
+
+// statusOf converts an SSH_FXP_STATUS reply to an error (nil for OK).
+func statusOf(packet sftpPacket) error {
+	if packet.typ != sftpStatus {
+		return fmt.Errorf("sftp: unexpected packet type %d", packet.typ)
+	}
+	parser := &sftpParser{data: packet.data}
+	code := parser.uint32()
+	msg := parser.string()
+	if code == sftpStatusOK {
+		return nil
+	}
+	return &sftpStatusError{code: code, msg: msg}
+}
+
+func (c *sftpConn) expectStatus(ctx context.Context, typ byte, payload sftpBuilder) error {
+	packet, err := c.request(ctx, typ, payload)
+	if err != nil {
+		return err
+	}
+	return statusOf(packet)
+}
+
+func (c *sftpConn) expectHandle(ctx context.Context, typ byte, payload sftpBuilder) (string, error) {
+	packet, err := c.request(ctx, typ, payload)
+	if err != nil {
+		return "", err
+	}
+	if packet.typ != sftpHandle {
+		return "", statusOf(packet)
+	}
+	parser := &sftpParser{data: packet.data}
+	handle := parser.string()
+	return handle, parser.err
+}
+
+func (c *sftpConn) stat(ctx context.Context, p string) (sftpFileAttrs, error) {
+	packet, err := c.request(ctx, sftpStat, sftpBuilder{}.string(p))
+	if err != nil {
+		return sftpFileAttrs{}, err
+	}
+	if packet.typ != sftpAttrs {
+		return sftpFileAttrs{}, statusOf(packet)
+	}
+	parser := &sftpParser{data: packet.data}
+	attrs := parser.attrs()
+	return attrs, parser.err
+}
+
+func (c *sftpConn) closeHandle(ctx context.Context, handle string) error {
+	return c.expectStatus(ctx, sftpClose, sftpBuilder{}.string(handle))
+}
+
+// rename moves from over to, replacing an existing file.
+func (c *sftpConn) rename(ctx context.Context, from, to string) error {
+	if _, ok := c.extensions[posixRenameExtension]; ok {
+		return c.expectStatus(ctx, sftpExtended, sftpBuilder{}.string(posixRenameExtension).string(from).string(to))
+	}
+	// Plain v3 RENAME refuses to overwrite.
+	if err := c.expectStatus(ctx, sftpRemove, sftpBuilder{}.string(to)); err != nil && !isSFTPStatus(err, sftpStatusNoSuchFile) {
+		return err
+	}
+	return c.expectStatus(ctx, sftpRename, sftpBuilder{}.string(from).string(to))
+}
+
+// writeFile streams r to the open handle with sftpWriteWindow writes in flight.
+func (c *sftpConn) writeFile(ctx context.Context, handle string, r io.Reader) error {
+	var (
+		inflight []chan sftpPacket
+		offset   uint64
+		buf      = make([]byte, sftpChunkSize)
+	)
+	drainOne := func() error {
+		packet, err := c.wait(ctx, inflight[0])
+		inflight = inflight[1:]
+		if err != nil {
+			return err
+		}
+		return statusOf(packet)
+	}
+	for {
+		n, readErr := io.ReadFull(r, buf)
+		if n > 0 {
+			ch, err := c.send(sftpWrite, sftpBuilder{}.string(handle).uint64(offset).bytes(buf[:n]))
+			if err != nil {
+				return err
+			}
+			inflight = append(inflight, ch)
+			offset += uint64(n)
+			if len(inflight) >= sftpWriteWindow {
+				if err := drainOne(); err != nil {
+					return err
+				}
+			}
+		}
+		if readErr == io.EOF || readErr == io.ErrUnexpectedEOF {
+			break
+		}
+		if readErr != nil {
+			return readErr
+		}
+	}
+	for len(inflight) > 0 {
+		if err := drainOne(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readFile streams the open handle to w with sftpWriteWindow reads in
+// flight. Short reads are completed synchronously before moving on.
+func (c *sftpConn) readFile(ctx context.Context, handle string, w io.Writer) error {
+	type readReq struct {
+		ch     chan sftpPacket
+		offset uint64
+	}
+	var (
+		inflight []readReq
+		next     uint64
+		eof      bool
+	)
+	issue := func() error {
+		ch, err := c.send(sftpRead, sftpBuilder{}.string(handle).uint64(next).uint32(sftpChunkSize))
+		if err != nil {
+			return err
+		}
+		inflight = append(inflight, readReq{ch: ch, offset: next})
+		next += sftpChunkSize
+		return nil
+	}
+	// data returns the payload of a DATA reply, or io.EOF.
+	data := func(packet sftpPacket) ([]byte, error) {
+		if packet.typ != sftpData {
+			err := statusOf(packet)
+			if err == nil || isSFTPStatus(err, sftpStatusEOF) {
+				return nil, io.EOF
+			}
+			return nil, err
+		}
+		parser := &sftpParser{data: packet.data}
+		payload := parser.string()
+		return []byte(payload), parser.err
+	}
+	for range sftpWriteWindow {
+		if err := issue(); err != nil {
+			return err
+		}
+	}
+	for len(inflight) > 0 {
+		req := inflight[0]
+		inflight = inflight[1:]
+		packet, err := c.wait(ctx, req.ch)
+		if err != nil {
+			return err
+		}
+		if eof {
+			continue
+		}
+		chunk, err := data(packet)
+		var filled uint64
+		for err == nil {
+			if _, werr := w.Write(chunk); werr != nil {
+				return werr
+			}
+			filled += uint64(len(chunk))
+			if filled >= sftpChunkSize {
+				break
+			}
+			// Short read: fetch the rest of this chunk before the next one.
+			packet, err = c.request(ctx, sftpRead, sftpBuilder{}.string(handle).uint64(req.offset+filled).uint32(uint32(sftpChunkSize-filled)))
+			if err != nil {
+				return err
+			}
+			chunk, err = data(packet)
+			if err == nil && len(chunk) == 0 {
+				err = io.EOF
+			}
+		}
+		if err == io.EOF {
+			eof = true
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if err := issue(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sftpDialer starts an SFTP subsystem and returns its reader, writer and a
+// close function.
+type sftpDialer func() (io.Reader, io.WriteCloser, func() error, error)
+
+// sftpStore uploads over the system ssh client's sftp subsystem, so keys,
+// agents and ~/.ssh/config work as they do for scp.
+type sftpStore struct {
+	host string
+	dial sftpDialer
+
+	mu   sync.Mutex
+	conn *sftpConn
+	dirs map[string]bool
+}
+
+func newSFTPStore(cfg *model.Config) *sftpStore {
+	args := []string{"-o", "BatchMode=yes"}
+	if cfg.SftpPort > 0 {
+		args = append(args, "-p", strconv.Itoa(cfg.SftpPort))
+	}
+	if cfg.SftpIdentityFile != "" {
+		args = append(args, "-i", cfg.SftpIdentityFile)
+	}
+	args = append(args, "-s", cfg.SftpHost, "sftp")
+	return &sftpStore{host: cfg.SftpHost, dial: sshDialer(args), dirs: map[string]bool{}}
+}
+
+// sshDialer runs ssh with args. The process is not bound to a request
+// context because the connection is reused across calls.
+func sshDialer(args []string) sftpDialer {
+	return func() (io.Reader, io.WriteCloser, func() error, error) {
+		cmd := exec.Command("ssh", args...)
+		stdin, err := cmd.StdinPipe()
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		stdout, err := cmd.StdoutPipe()
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		var stderr bytes.Buffer
+		cmd.Stderr = &stderr
+		if err := cmd.Start(); err != nil {
+			return nil, nil, nil, fmt.Errorf("failed to start ssh: %w", err)
+		}
+		closeFn := func() error {
+			_ = stdin.Close()
+			err := cmd.Wait()
+			if msg := strings.TrimSpace(stderr.String()); err != nil && msg != "" {
+				return fmt.Errorf("%w: %s", err, msg)
+			}
+			return err
+		}
+		return stdout, stdin, closeFn, nil
+	}
+}
+
+// session returns the shared connection, dialing a new one if the previous
+// one broke.
+func (s *sftpStore) session() (*sftpConn, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conn != nil && s.conn.broken() == nil {
+		return s.conn, nil
+	}
+	if s.conn != nil {
+		_ = s.conn.close()
+		s.conn = nil
+	}
+	r, w, closeFn, err := s.dial()
+	if err != nil {
+		return nil, err
+	}
+	conn, err := newSFTPConn(r, w, closeFn)
+	if err != nil {
+		if closeErr := closeFn(); closeErr != nil {
+			err = fmt.Errorf("%w (%v)", err, closeErr)
+		}
+		return nil, err
+	}
+	s.conn = conn
+	return conn, nil
+}
+
+// reset drops the connection after a transport error so the next call
+// redials; status errors keep it.
+func (s *sftpStore) reset(conn *sftpConn, err error) {
+	var statusErr *sftpStatusError
+	if err == nil || errors.As(err, &statusErr) || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conn == conn {
+		_ = conn.close()
+		s.conn = nil
+	}
+}
+
+func (s *sftpStore) with(fn func(conn *sftpConn) error) error {
+	conn, err := s.session()
+	if err != nil {
+		return err
+	}
+	err = fn(conn)
+	s.reset(conn, err)
+	return err
+}
+
+// mkdirAll creates dir and its parents, remembering directories it has seen.
+func (s *sftpStore) mkdirAll(ctx context.Context, conn *sftpConn, dir string) error {
+	if dir == "" || dir == "." || dir == "/" {
+		return nil
+	}
+	s.mu.Lock()
+	known := s.dirs[dir]
+	s.mu.Unlock()
+	if known {
+		return nil
+	}
+	if err := s.mkdirAll(ctx, conn, path.Dir(dir)); err != nil {
+		return err
+	}
+	mkdirErr := conn.expectStatus(ctx, sftpMkdir, sftpBuilder{}.string(dir).uint32(0))
+	if mkdirErr != nil {
+		// Another worker may have won the race, or it already existed.
+		attrs, err := conn.stat(ctx, dir)
+		if err != nil || !attrs.isDir() {
+			return fmt.Errorf("failed to create %s: %w", dir, mkdirErr)
+		}
+	}
+	s.mu.Lock()
+	s.dirs[dir] = true
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *sftpStore) put(ctx context.Context, localPath, remotePath string, onBytes func(n int64)) error {
+	src, err := os.Open(localPath)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	return s.with(func(conn *sftpConn) error {
+		if err := s.mkdirAll(ctx, conn, path.Dir(remotePath)); err != nil {
+			return err
+		}
+		tmpPath := path.Join(path.Dir(remotePath), "."+path.Base(remotePath)+".nugs-tmp")
+		handle, err := conn.expectHandle(ctx, sftpOpen, sftpBuilder{}.string(tmpPath).
+			uint32(sftpFlagWrite|sftpFlagCreate|sftpFlagTrunc).uint32(sftpAttrPermissions).uint32(0o644))
+		if err != nil {
+			return fmt.Errorf("failed to open %s: %w", tmpPath, err)
+		}
+		writeErr := conn.writeFile(ctx, handle, &countingReader{r: src, onBytes: onBytes})
+		closeErr := conn.closeHandle(ctx, handle)
+		if writeErr == nil {
+			writeErr = closeErr
+		}
+		if writeErr == nil {
+			writeErr = conn.rename(ctx, tmpPath, remotePath)
+		}
+		if writeErr != nil && conn.broken() == nil {
+			_ = conn.expectStatus(context.WithoutCancel(ctx), sftpRemove, sftpBuilder{}.string(tmpPath))
+		}
+		return writeErr
+	})
+}
+
+func (s *sftpStore) size(ctx context.Context, remotePath string) (int64, bool, error) {
+	var (
+		size  int64
+		found bool
+	)
+	err := s.with(func(conn *sftpConn) error {
+		attrs, err := conn.stat(ctx, remotePath)
+		if isSFTPStatus(err, sftpStatusNoSuchFile) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if attrs.isDir() || !attrs.hasSize {
+			return nil
+		}
+		size, found = attrs.size, true
+		return nil
+	})
+	return size, found, err
+}
+
+func (s *sftpStore) exists(ctx context.Context, remotePath string) (bool, error) {
+	found := false
+	err := s.with(func(conn *sftpConn) error {
+		_, err := conn.stat(ctx, remotePath)
+		if isSFTPStatus(err, sftpStatusNoSuchFile) {
+			return nil
+		}
+		found = err == nil
+		return err
+	})
+	return found, err
+}
+
+func (s *sftpStore) listDirs(ctx context.Context, remotePath string) ([]string, error) {
+	var names []string
+	err := s.with(func(conn *sftpConn) error {
+		handle, err := conn.expectHandle(ctx, sftpOpendir, sftpBuilder{}.string(remotePath))
+		if isSFTPStatus(err, sftpStatusNoSuchFile) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		defer func() { _ = conn.closeHandle(context.WithoutCancel(ctx), handle) }()
+		for {
+			packet, err := conn.request(ctx, sftpReaddir, sftpBuilder{}.string(handle))
+			if err != nil {
+				return err
+			}
+			if packet.typ != sftpName {
+				if err := statusOf(packet); err != nil && !isSFTPStatus(err, sftpStatusEOF) {
+					return err
+				}
+				return nil
+			}
+			parser := &sftpParser{data: packet.data}
+			for range parser.uint32() {
+				name := parser.string()
+				longName := parser.string()
+				attrs := parser.attrs()
+				if parser.err != nil {
+					return parser.err
+				}
+				if name == "." || name == ".." {
+					continue
+				}
+				if attrs.isDir() || (!attrs.hasMode && strings.HasPrefix(longName, "d")) {
+					names = append(names, name)
+				}
+			}
+			if parser.err != nil {
+				return parser.err
+			}
+		}
+	})
+	return names, err
+}
+
+func (s *sftpStore) get(ctx context.Context, remotePath string, w io.Writer) error {
+	return s.with(func(conn *sftpConn) error {
+		handle, err := conn.expectHandle(ctx, sftpOpen, sftpBuilder{}.string(remotePath).uint32(sftpFlagRead).uint32(0))
+		if isSFTPStatus(err, sftpStatusNoSuchFile) {
+			return fmt.Errorf("%s: %w", s.describe(remotePath), fs.ErrNotExist)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to open %s: %w", remotePath, err)
+		}
+		readErr := conn.readFile(ctx, handle, w)
+		closeErr := conn.closeHandle(context.WithoutCancel(ctx), handle)
+		if readErr != nil {
+			return readErr
+		}
+		return closeErr
+	})
+}
+
+func (s *sftpStore) describe(remotePath string) string {
+	return "sftp://" + s.host + "/" + trimSlash(remotePath)
+}
@@ -0,0 +1,289 @@
+package storage
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/jmagar/nugs-cli/internal/model"
+)
+
+// fakeSFTPServer serves the subset of SFTP v3 that sftpStore uses from a
+// local directory.
+type fakeSFTPServer struct {
+	root        string
+	posixRename bool
+
+	mu      sync.Mutex
+	handles map[string]any // *os.File or []fs.DirEntry
+	next    int
+	writes  int
+}
+
+func (s *fakeSFTPServer) local(p string) string {
+	return filepath.Join(s.root, filepath.FromSlash(p))
+}
+
+func (s *fakeSFTPServer) serve(r io.Reader, w io.Writer) {
+	typ, body, err := readSFTPPacket(r)
+	if err != nil || typ != sftpInit {
+		return
+	}
+	version := sftpBuilder{}.uint32(3)
+	if s.posixRename {
+		version = version.string(posixRenameExtension).string("1")
+	}
+	_ = writeSFTPPacket(w, sftpVersion, version)
+	_ = body
+	for {
+		typ, body, err := readSFTPPacket(r)
+		if err != nil {
+			return
+		}
+		parser := &sftpParser{data: body}
+		id := parser.uint32()
+		respType, payload := s.handle(typ, parser)
+		if err := writeSFTPPacket(w, respType, append(sftpBuilder{}.uint32(id), payload...)); err != nil {
+			return
+		}
+	}
+}
+
+func fakeStatus(err error) (byte, sftpBuilder) {
+	code := uint32(sftpStatusOK)
+	msg := ""
+	switch {
+	case err == nil:
+	case errors.Is(err, io.EOF):
+		code = sftpStatusEOF
+	case errors.Is(err, fs.ErrNotExist):
+		code, msg = sftpStatusNoSuchFile, err.Error()
+	default:
+		code, msg = 4, err.Error()
+	}
+	return sftpStatus, sftpBuilder{}.uint32(code).string(msg).string("")
+}
+
+func fakeAttrs(info fs.FileInfo) sftpBuilder {
+	mode := uint32(0o100644)
+	if info.IsDir() {
+		mode = 0o040755
+	}
+	return sftpBuilder{}.uint32(sftpAttrSize | sftpAttrPermissions).uint64(uint64(info.Size())).uint32(mode)
+}
+
+func (s *fakeSFTPServer) addHandle(v any) sftpBuilder {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.next++
+	name := strings.Repeat("h", s.next)
+	s.handles[name] = v
+	return sftpBuilder{}.string(name)
+}
+
+func (s *fakeSFTPServer) handle(typ byte, p *sftpParser) (byte, sftpBuilder) {
+	switch typ {
+	case sftpOpen:
+		name, pflags := p.string(), p.uint32()
+		flags := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+		if pflags == sftpFlagRead {
+			flags = os.O_RDONLY
+		}
+		file, err := os.OpenFile(s.local(name), flags, 0644)
+		if err != nil {
+			return fakeStatus(err)
+		}
+		return sftpHandle, s.addHandle(file)
+	case sftpWrite:
+		handle, offset := p.string(), p.uint64()
+		data := p.string()
+		s.mu.Lock()
+		file := s.handles[handle].(*os.File)
+		s.writes++
+		s.mu.Unlock()
+		_, err := file.WriteAt([]byte(data), int64(offset))
+		return fakeStatus(err)
+	case sftpRead:
+		handle, offset, length := p.string(), p.uint64(), p.uint32()
+		s.mu.Lock()
+		file := s.handles[handle].(*os.File)
+		s.mu.Unlock()
+		// Answer with short reads to exercise the client's resume logic.
+		buf := make([]byte, min(length, 10_000))
+		n, err := file.ReadAt(buf, int64(offset))
+		if n == 0 {
+			if err == nil {
+				err = io.EOF
+			}
+			return fakeStatus(err)
+		}
+		return sftpData, sftpBuilder{}.bytes(buf[:n])
+	case sftpClose:
+		handle := p.string()
+		s.mu.Lock()
+		v := s.handles[handle]
+		delete(s.handles, handle)
+		s.mu.Unlock()
+		if file, ok := v.(*os.File); ok {
+			return fakeStatus(file.Close())
+		}
+		return fakeStatus(nil)
+	case sftpStat:
+		info, err := os.Stat(s.local(p.string()))
+		if err != nil {
+			return fakeStatus(err)
+		}
+		return sftpAttrs, fakeAttrs(info)
+	case sftpOpendir:
+		entries, err := os.ReadDir(s.local(p.string()))
+		if err != nil {
+			return fakeStatus(err)
+		}
+		return sftpHandle, s.addHandle(entries)
+	case sftpReaddir:
+		handle := p.string()
+		s.mu.Lock()
+		entries := s.handles[handle].([]fs.DirEntry)
+		s.handles[handle] = []fs.DirEntry(nil)
+		s.mu.Unlock()
+		if len(entries) == 0 {
+			return fakeStatus(io.EOF)
+		}
+		out := sftpBuilder{}.uint32(uint32(len(entries) + 1)).string(".").string("drwxr-xr-x .").uint32(0)
+		for _, entry := range entries {
+			info, _ := entry.Info()
+			out = append(out.string(entry.Name()).string(entry.Name()), fakeAttrs(info)...)
+		}
+		return sftpName, out
+	case sftpMkdir:
+		return fakeStatus(os.Mkdir(s.local(p.string()), 0755))
+	case sftpRemove:
+		return fakeStatus(os.Remove(s.local(p.string())))
+	case sftpRename:
+		from, to := s.local(p.string()), s.local(p.string())
+		if _, err := os.Stat(to); err == nil {
+			return fakeStatus(errors.New("target exists"))
+		}
+		return fakeStatus(os.Rename(from, to))
+	case sftpExtended:
+		if p.string() != posixRenameExtension || !s.posixRename {
+			return fakeStatus(errors.New("unsupported"))
+		}
+		return fakeStatus(os.Rename(s.local(p.string()), s.local(p.string())))
+	}
+	return fakeStatus(errors.New("unsupported request"))
+}
+
+// newFakeSFTPStore connects an sftpStore to a fakeSFTPServer over pipes.
+func newFakeSFTPStore(server *fakeSFTPServer) (*sftpStore, *int) {
+	dials := 0
+	store := &sftpStore{host: "nas", dirs: map[string]bool{}}
+	store.dial = func() (io.Reader, io.WriteCloser, func() error, error) {
+		dials++
+		clientR, serverW := io.Pipe()
+		serverR, clientW := io.Pipe()
+		go func() {
+			server.serve(serverR, serverW)
+			_ = serverW.Close()
+		}()
+		closeFn := func() error {
+			_ = clientW.Close()
+			return clientR.Close()
+		}
+		return clientR, clientW, closeFn, nil
+	}
+	return store, &dials
+}
+
+func TestSFTPProviderRoundTrip(t *testing.T) {
+	for _, posixRename := range []bool{true, false} {
+		server := &fakeSFTPServer{root: t.TempDir(), posixRename: posixRename, handles: map[string]any{}}
+		store, dials := newFakeSFTPStore(server)
+		provider := newProvider(store)
+		provider.recordUpload = nil
+		cfg := &model.Config{RcloneEnabled: true, RclonePath: "/music", DeleteAfterUpload: true, RcloneTransfers: 3}
+
+		srcDir := t.TempDir()
+		var src string
+		// Uploading twice exercises replacing existing files.
+		for range 2 {
+			src = writeShow(t, srcDir)
+			err := provider.Upload(context.Background(), cfg, model.UploadRequest{LocalPath: src, ArtistFolder: "Phish"},
+				model.StorageHooks{OnProgress: func(model.UploadProgress) {}})
+			if err != nil {
+				t.Fatalf("posixRename=%v: Upload() error = %v", posixRename, err)
+			}
+		}
+		if _, err := os.Stat(src); !os.IsNotExist(err) {
+			t.Fatal("local show not deleted after verified upload")
+		}
+		data, err := os.ReadFile(filepath.Join(server.root, "music", "Phish", "Phish - 1997-12-31", "01. Intro.flac"))
+		if err != nil || len(data) != 70_000 {
+			t.Fatalf("uploaded file: %d bytes, err %v", len(data), err)
+		}
+		if server.writes < 2*3 {
+			t.Fatalf("expected chunked writes, got %d", server.writes)
+		}
+		leftovers, _ := filepath.Glob(filepath.Join(server.root, "music", "Phish", "Phish - 1997-12-31", ".*.nugs-tmp"))
+		if len(leftovers) != 0 {
+			t.Fatalf("temp files left behind: %v", leftovers)
+		}
+
+		exists, err := provider.PathExists(context.Background(), cfg, "Phish/Phish - 1997-12-31", false)
+		if err != nil || !exists {
+			t.Fatalf("PathExists = %v, %v", exists, err)
+		}
+		exists, err = provider.PathExists(context.Background(), cfg, "Phish/nope", false)
+		if err != nil || exists {
+			t.Fatalf("PathExists(missing) = %v, %v", exists, err)
+		}
+		folders, err := provider.ListArtistFolders(context.Background(), cfg, "Phish", false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := folders["Phish - 1997-12-31"]; !ok || len(folders) != 1 {
+			t.Fatalf("ListArtistFolders = %v", folders)
+		}
+		missing, err := provider.ListArtistFolders(context.Background(), cfg, "Nobody", false)
+		if err != nil || len(missing) != 0 {
+			t.Fatalf("missing artist listing = %v, %v", missing, err)
+		}
+		if *dials != 1 {
+			t.Fatalf("dialed %d times, want the connection reused", *dials)
+		}
+	}
+}
+
+func TestSFTPStoreRedialsAfterConnectionLoss(t *testing.T) {
+	server := &fakeSFTPServer{root: t.TempDir(), handles: map[string]any{}}
+	store, dials := newFakeSFTPStore(server)
+	if _, err := store.exists(context.Background(), "/"); err != nil {
+		t.Fatal(err)
+	}
+	// Kill the transport under the live connection. The next call either
+	// fails and resets the connection or finds it already broken; the call
+	// after that must succeed on a fresh connection.
+	_ = store.conn.close()
+	if _, err := store.exists(context.Background(), "/"); err != nil {
+		if _, err := store.exists(context.Background(), "/"); err != nil {
+			t.Fatalf("exists after redial: %v", err)
+		}
+	}
+	if *dials != 2 {
+		t.Fatalf("dials = %d, want 2", *dials)
+	}
+}
+
+func TestSFTPParserRejectsShortPackets(t *testing.T) {
+	p := &sftpParser{data: binary.BigEndian.AppendUint32(nil, 10)}
+	if p.string(); p.err == nil {
+		t.Fatal("expected short packet error")
+	}
+}
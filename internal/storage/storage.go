@@ -0,0 +1,592 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/dustin/go-humanize"
+
+	"github.com/jmagar/nugs-cli/internal/helpers"
+	"github.com/jmagar/nugs-cli/internal/history"
+	"github.com/jmagar/nugs-cli/internal/manifest"
+	"github.com/jmagar/nugs-cli/internal/model"
+	"github.com/jmagar/nugs-cli/internal/rclone"
+	"github.com/jmagar/nugs-cli/internal/ui"
+)
+
+// progressInterval throttles upload progress callbacks.
+const progressInterval = 500 * time.Millisecond
+
+// fileStore is the minimal file API a backend provides. Paths are
+// slash-separated and rooted at the backend, e.g. "/Music/Artist/Show".
+type fileStore interface {
+	// put uploads one local file to remotePath, creating parent
+	// directories, and reports bytes sent through onBytes.
+	put(ctx context.Context, localPath, remotePath string, onBytes func(n int64)) error
+	// size returns the size of the file at remotePath, or false if absent.
+	size(ctx context.Context, remotePath string) (int64, bool, error)
+	// exists reports whether remotePath is a file or directory.
+	exists(ctx context.Context, remotePath string) (bool, error)
+	// listDirs returns the names of the directories directly under
+	// remotePath; a missing remotePath lists as empty.
+	listDirs(ctx context.Context, remotePath string) ([]string, error)
+	// get streams the file at remotePath to w. A missing file yields an
+	// error wrapping fs.ErrNotExist.
+	get(ctx context.Context, remotePath string, w io.Writer) error
+	// describe formats remotePath for messages and history.
+	describe(remotePath string) string
+}
+
+// md5Store is implemented by stores that can report a file's MD5 without
+// downloading it.
+type md5Store interface {
+	md5(ctx context.Context, remotePath string) (string, bool, error)
+}
+
+// Provider implements model.StorageProvider on top of a fileStore, with the
+// same folder semantics as rclone copy and lsf.
+type Provider struct {
+	store        fileStore
+	removeAll    func(path string) error
+	recordUpload func(cfg *model.Config, localPath, remotePath string) error
+}
+
+func newProvider(store fileStore) *Provider {
+	return &Provider{store: store, removeAll: os.RemoveAll, recordUpload: recordUploadHistory}
+}
+
+var (
+	providersMu sync.Mutex
+	providers   = map[string]*Provider{}
+)
+
+// ForConfig returns the storage provider selected by cfg.StorageBackend.
+// Native providers are cached per backend settings so SFTP connections are
+// reused across calls.
+func ForConfig(cfg *model.Config) (model.StorageProvider, error) {
+	if err := Validate(cfg); err != nil {
+		return nil, err
+	}
+	backend := helpers.StorageBackend(cfg)
+	if backend == model.StorageBackendRclone {
+		return rclone.NewStorageAdapter(), nil
+	}
+	key := fmt.Sprintf("%s|%s|%d|%s|%s|%s|%s|%s|%s", backend, cfg.SftpHost, cfg.SftpPort, cfg.SftpIdentityFile,
+		cfg.S3Endpoint, cfg.S3Region, cfg.S3Bucket, cfg.S3AccessKey, cfg.S3SecretKey)
+	providersMu.Lock()
+	defer providersMu.Unlock()
+	if provider, ok := providers[key]; ok {
+		return provider, nil
+	}
+	var store fileStore
+	switch backend {
+	case model.StorageBackendLocal:
+		store = localStore{}
+	case model.StorageBackendSFTP:
+		store = newSFTPStore(cfg)
+	case model.StorageBackendS3:
+		s3, err := newS3Store(cfg)
+		if err != nil {
+			return nil, err
+		}
+		store = s3
+	}
+	provider := newProvider(store)
+	providers[key] = provider
+	return provider, nil
+}
+
+// Validate checks that the settings for the selected backend, or for every
+// upload destination, are complete.
+func Validate(cfg *model.Config) error {
+	if len(cfg.UploadDestinations) == 0 {
+		return validateBackend(cfg)
+	}
+	seen := make(map[string]struct{}, len(cfg.UploadDestinations))
+	required := 0
+	for i, dest := range cfg.UploadDestinations {
+		name := strings.TrimSpace(dest.Name)
+		if name == "" {
+			return fmt.Errorf("uploadDestinations[%d] needs a name", i)
+		}
+		if _, ok := seen[name]; ok {
+			return fmt.Errorf("upload destination %q is listed twice", name)
+		}
+		seen[name] = struct{}{}
+		if media := strings.TrimSpace(dest.Media); media != "" && model.ParseMediaType(media) == model.MediaTypeUnknown {
+			return fmt.Errorf("upload destination %q: unknown media %q (expected audio, video or both)", name, dest.Media)
+		}
+		if err := validateBackend(helpers.DestinationConfig(cfg, dest)); err != nil {
+			return fmt.Errorf("upload destination %q: %w", name, err)
+		}
+		if !dest.Optional {
+			required++
+		}
+	}
+	if cfg.DeleteAfterUpload && required == 0 {
+		return errors.New("deleteAfterUpload needs at least one upload destination that is not optional")
+	}
+	return nil
+}
+
+func validateBackend(cfg *model.Config) error {
+	backend := helpers.StorageBackend(cfg)
+	switch backend {
+	case model.StorageBackendRclone:
+		return nil
+	case model.StorageBackendLocal:
+		for _, base := range []string{cfg.RclonePath, cfg.RcloneVideoPath} {
+			if base != "" && !filepath.IsAbs(base) {
+				return fmt.Errorf("storageBackend local needs absolute rclonePath/rcloneVideoPath, got %q", base)
+			}
+		}
+		if cfg.RclonePath == "" {
+			return errors.New("storageBackend local needs rclonePath set to the mirror directory")
+		}
+	case model.StorageBackendSFTP:
+		if cfg.SftpHost == "" || cfg.SftpHost[0] == '-' {
+			return errors.New("storageBackend sftp needs sftpHost set to [user@]host or an ssh config alias")
+		}
+		if cfg.RclonePath == "" {
+			return errors.New("storageBackend sftp needs rclonePath set to the remote directory")
+		}
+	case model.StorageBackendS3:
+		if cfg.S3Bucket == "" {
+			return errors.New("storageBackend s3 needs s3Bucket")
+		}
+		if _, _, _, err := s3Credentials(cfg); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unknown storageBackend %q (expected rclone, local, sftp or s3)", cfg.StorageBackend)
+	}
+	return nil
+}
+
+// Describe formats the remote base path for isVideo, e.g. for startup output.
+func Describe(cfg *model.Config, isVideo bool) string {
+	base := helpers.GetRcloneBasePath(cfg, isVideo)
+	switch helpers.StorageBackend(cfg) {
+	case model.StorageBackendLocal:
+		return base
+	case model.StorageBackendSFTP:
+		return "sftp://" + cfg.SftpHost + "/" + trimSlash(base)
+	case model.StorageBackendS3:
+		return "s3://" + cfg.S3Bucket + "/" + trimSlash(base)
+	}
+	return cfg.RcloneRemote + ":" + base
+}
+
+func trimSlash(p string) string {
+	for len(p) > 0 && p[0] == '/' {
+		p = p[1:]
+	}
+	return p
+}
+
+// recordUploadHistory attaches the remote path to the history entries for
+// localPath.
+func recordUploadHistory(cfg *model.Config, localPath, remotePath string) error {
+	if cfg.SkipHistory {
+		return nil
+	}
+	store, err := history.Default()
+	if err != nil {
+		return err
+	}
+	_, err = store.RecordUpload(localPath, remotePath, time.Time{})
+	return err
+}
+
+// uploadFile is one file of an upload and its path below the upload root.
+type uploadFile struct {
+	localPath string
+	relPath   string // slash-separated; empty when the upload is one file
+	size      int64
+}
+
+// collectUploadFiles lists the regular files under localPath.
+func collectUploadFiles(localPath string) ([]uploadFile, int64, error) {
+	info, err := os.Stat(localPath)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to stat local path: %w", err)
+	}
+	if !info.IsDir() {
+		return []uploadFile{{localPath: localPath, size: info.Size()}}, info.Size(), nil
+	}
+	var (
+		files []uploadFile
+		total int64
+	)
+	err = filepath.WalkDir(localPath, func(p string, d fs.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			return walkErr
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		fileInfo, err := d.Info()
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(localPath, p)
+		if err != nil {
+			return err
+		}
+		files = append(files, uploadFile{localPath: p, relPath: filepath.ToSlash(rel), size: fileInfo.Size()})
+		total += fileInfo.Size()
+		return nil
+	})
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to scan local path: %w", err)
+	}
+	return files, total, nil
+}
+
+// progressTracker aggregates bytes across concurrent file uploads and
+// reports throttled model.UploadProgress updates.
+type progressTracker struct {
+	total    int64
+	sent     atomic.Int64
+	start    time.Time
+	mu       sync.Mutex
+	last     time.Time
+	onUpdate func(model.UploadProgress)
+}
+
+func (t *progressTracker) add(n int64) {
+	sent := t.sent.Add(n)
+	if t.onUpdate == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	now := time.Now()
+	if sent < t.total && now.Sub(t.last) < progressInterval {
+		return
+	}
+	t.last = now
+	t.onUpdate(t.snapshot(sent, now))
+}
+
+func (t *progressTracker) snapshot(sent int64, now time.Time) model.UploadProgress {
+	percent := 100
+	if t.total > 0 {
+		percent = int(sent * 100 / t.total)
+	}
+	speed := "0 B/s"
+	if elapsed := now.Sub(t.start).Seconds(); elapsed > 0 {
+		speed = humanize.IBytes(uint64(float64(sent)/elapsed)) + "/s"
+	}
+	return model.UploadProgress{
+		Percent:  percent,
+		Speed:    speed,
+		Uploaded: humanize.IBytes(uint64(sent)),
+		Total:    humanize.IBytes(uint64(t.total)),
+	}
+}
+
+// Upload implements model.StorageProvider. A directory is copied to
+// <base>/<artistFolder>/<dir name>; a single file is copied to
+// <base>/<artistFolder>/<file name>.
+func (p *Provider) Upload(ctx context.Context, cfg *model.Config, req model.UploadRequest, hooks model.StorageHooks) error {
+	if !cfg.RcloneEnabled {
+		return nil
+	}
+	if err := helpers.ValidatePath(req.LocalPath); err != nil {
+		return fmt.Errorf("invalid local path: %w", err)
+	}
+	if req.ArtistFolder != "" {
+		if err := helpers.ValidatePath(req.ArtistFolder); err != nil {
+			return fmt.Errorf("invalid artist folder: %w", err)
+		}
+	}
+	if ctx == nil {
+		return fmt.Errorf("upload context is required")
+	}
+
+	files, totalBytes, err := collectUploadFiles(req.LocalPath)
+	if err != nil {
+		return err
+	}
+	remoteRoot := uploadRoot(cfg, req)
+	remoteDisplay := p.store.describe(remoteRoot)
+
+	if hooks.OnPreUpload != nil && totalBytes > 0 {
+		hooks.OnPreUpload(totalBytes)
+	}
+	if hooks.OnProgress == nil {
+		ui.PrintUpload(fmt.Sprintf("Uploading to %s%s%s...", ui.ColorBold, remoteDisplay, ui.ColorReset))
+	}
+	tracker := &progressTracker{total: totalBytes, start: time.Now(), onUpdate: hooks.OnProgress}
+	if err := p.uploadFiles(ctx, cfg, files, remoteRoot, tracker); err != nil {
+		return fmt.Errorf("%s upload failed: %w", helpers.StorageBackend(cfg), err)
+	}
+	if hooks.OnProgress != nil {
+		hooks.OnProgress(tracker.snapshot(tracker.sent.Load(), time.Now()))
+	}
+
+	if p.recordUpload != nil {
+		if err := p.recordUpload(cfg, req.LocalPath, remoteDisplay); err != nil {
+			ui.PrintWarning(fmt.Sprintf("Failed to record upload in download history: %v", err))
+		}
+	}
+	if hooks.OnComplete != nil {
+		hooks.OnComplete()
+	}
+	if hooks.OnProgress == nil {
+		ui.PrintSuccess("Upload complete!")
+	}
+
+	if !cfg.DeleteAfterUpload {
+		return nil
+	}
+	if hooks.OnProgress == nil {
+		ui.PrintInfo("Verifying upload integrity...")
+	}
+	if err := p.verify(ctx, req.LocalPath, files, remoteRoot); err != nil {
+		return fmt.Errorf("upload verification failed - NOT deleting local files: %w", err)
+	}
+	if hooks.OnProgress == nil {
+		ui.PrintSuccess("Upload verified successfully")
+		fmt.Printf("Deleting local files: %s\n", req.LocalPath)
+	}
+	if hooks.OnDeleteAfterUpload != nil {
+		hooks.OnDeleteAfterUpload(req.LocalPath)
+	}
+	if err := p.removeAll(req.LocalPath); err != nil {
+		return fmt.Errorf("failed to delete local files: %w", err)
+	}
+	if hooks.OnProgress == nil {
+		ui.PrintSuccess("Local files deleted")
+	}
+	return nil
+}
+
+// uploadRoot is the remote path a local file or directory is uploaded to.
+func uploadRoot(cfg *model.Config, req model.UploadRequest) string {
+	return path.Join(helpers.GetRcloneBasePath(cfg, req.IsVideo), req.ArtistFolder, filepath.Base(req.LocalPath))
+}
+
+// Verify implements model.StorageVerifier by comparing remote sizes with
+// the local files and remote hashes with the checksum manifest.
+func (p *Provider) Verify(ctx context.Context, cfg *model.Config, req model.UploadRequest) error {
+	if err := helpers.ValidatePath(req.LocalPath); err != nil {
+		return fmt.Errorf("invalid local path: %w", err)
+	}
+	if err := helpers.ValidatePath(req.ArtistFolder); err != nil {
+		return fmt.Errorf("invalid artist folder: %w", err)
+	}
+	files, _, err := collectUploadFiles(req.LocalPath)
+	if err != nil {
+		return err
+	}
+	return p.verify(ctx, req.LocalPath, files, uploadRoot(cfg, req))
+}
+
+// uploadFiles uploads files with up to rcloneTransfers in parallel.
+func (p *Provider) uploadFiles(ctx context.Context, cfg *model.Config, files []uploadFile, remoteRoot string, tracker *progressTracker) error {
+	transfers := cfg.RcloneTransfers
+	if transfers <= 0 {
+		transfers = 4
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	jobs := make(chan uploadFile)
+	var (
+		wg       sync.WaitGroup
+		errOnce  sync.Once
+		firstErr error
+	)
+	for range min(transfers, max(len(files), 1)) {
+		wg.Go(func() {
+			for file := range jobs {
+				remotePath := path.Join(remoteRoot, file.relPath)
+				if err := p.store.put(ctx, file.localPath, remotePath, tracker.add); err != nil {
+					errOnce.Do(func() {
+						firstErr = fmt.Errorf("%s: %w", file.relPath, err)
+						cancel()
+					})
+				}
+			}
+		})
+	}
+feed:
+	for _, file := range files {
+		select {
+		case jobs <- file:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(jobs)
+	wg.Wait()
+	if firstErr != nil {
+		return firstErr
+	}
+	return ctx.Err()
+}
+
+// verify checks that every uploaded file exists remotely with its local
+// size, then that remote hashes match the manifest for localPath.
+func (p *Provider) verify(ctx context.Context, localPath string, files []uploadFile, remoteRoot string) error {
+	for _, file := range files {
+		remotePath := path.Join(remoteRoot, file.relPath)
+		size, ok, err := p.store.size(ctx, remotePath)
+		if err != nil {
+			return fmt.Errorf("%s: %w", remotePath, err)
+		}
+		if !ok {
+			return fmt.Errorf("%s is missing", remotePath)
+		}
+		if size != file.size {
+			return fmt.Errorf("%s is %d bytes, want %d", remotePath, size, file.size)
+		}
+	}
+	m, err := manifest.ForUpload(localPath)
+	if err != nil {
+		return fmt.Errorf("failed to load checksum manifest: %w", err)
+	}
+	remoteDir := remoteRoot
+	if len(files) == 1 && files[0].relPath == "" {
+		remoteDir = path.Dir(remoteRoot)
+	}
+	hashes, err := p.hashes(ctx, remoteDir, m.Names())
+	if err != nil {
+		return err
+	}
+	return manifest.Err(manifest.Check(m, hashes))
+}
+
+// hash returns the digest of one remote file: the store's MD5 when it can
+// report one, otherwise the SHA-256 of the downloaded contents. found is
+// false for a missing file.
+func (p *Provider) hash(ctx context.Context, remotePath string) (model.FileHash, bool, error) {
+	if store, ok := p.store.(md5Store); ok {
+		sum, ok, err := store.md5(ctx, remotePath)
+		if err != nil {
+			return model.FileHash{}, false, err
+		}
+		if ok {
+			return model.FileHash{Algo: manifest.AlgoMD5, Value: sum}, true, nil
+		}
+	}
+	h := sha256.New()
+	if err := p.store.get(ctx, remotePath, h); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return model.FileHash{}, false, nil
+		}
+		return model.FileHash{}, false, err
+	}
+	return model.FileHash{Algo: manifest.AlgoSHA256, Value: hex.EncodeToString(h.Sum(nil))}, true, nil
+}
+
+// hashes hashes the named files below remoteDir, leaving out missing ones.
+func (p *Provider) hashes(ctx context.Context, remoteDir string, names []string) (map[string]model.FileHash, error) {
+	hashes := make(map[string]model.FileHash, len(names))
+	for _, name := range names {
+		hash, found, err := p.hash(ctx, path.Join(remoteDir, name))
+		if err != nil {
+			return nil, fmt.Errorf("failed to hash %s: %w", p.store.describe(path.Join(remoteDir, name)), err)
+		}
+		if found {
+			hashes[name] = hash
+		}
+	}
+	return hashes, nil
+}
+
+// ReadFile implements model.StorageReader.
+func (p *Provider) ReadFile(ctx context.Context, cfg *model.Config, remotePath string, isVideo bool) ([]byte, error) {
+	if err := helpers.ValidatePath(remotePath); err != nil {
+		return nil, fmt.Errorf("invalid remote path: %w", err)
+	}
+	if ctx == nil {
+		return nil, fmt.Errorf("read context is required")
+	}
+	var buf bytes.Buffer
+	if err := p.store.get(ctx, path.Join(helpers.GetRcloneBasePath(cfg, isVideo), remotePath), &buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// FileHashes implements model.StorageReader.
+func (p *Provider) FileHashes(ctx context.Context, cfg *model.Config, remoteDir string, names []string, isVideo bool) (map[string]model.FileHash, error) {
+	if err := helpers.ValidatePath(remoteDir); err != nil {
+		return nil, fmt.Errorf("invalid remote path: %w", err)
+	}
+	if ctx == nil {
+		return nil, fmt.Errorf("hash context is required")
+	}
+	return p.hashes(ctx, path.Join(helpers.GetRcloneBasePath(cfg, isVideo), remoteDir), names)
+}
+
+// PathExists implements model.StorageProvider.
+func (p *Provider) PathExists(ctx context.Context, cfg *model.Config, remotePath string, isVideo bool) (bool, error) {
+	if !cfg.RcloneEnabled {
+		return false, nil
+	}
+	if err := helpers.ValidatePath(remotePath); err != nil {
+		return false, fmt.Errorf("invalid remote path: %w", err)
+	}
+	if ctx == nil {
+		return false, fmt.Errorf("path check context is required")
+	}
+	timeoutCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	return p.store.exists(timeoutCtx, path.Join(helpers.GetRcloneBasePath(cfg, isVideo), remotePath))
+}
+
+// ListArtistFolders implements model.StorageProvider. An empty artistFolder
+// lists the artist folders under the base path.
+func (p *Provider) ListArtistFolders(ctx context.Context, cfg *model.Config, artistFolder string, isVideo bool) (map[string]struct{}, error) {
+	folders := make(map[string]struct{})
+	if !cfg.RcloneEnabled {
+		return folders, nil
+	}
+	if err := helpers.ValidatePath(artistFolder); err != nil {
+		return nil, fmt.Errorf("invalid artist folder: %w", err)
+	}
+	if ctx == nil {
+		return nil, fmt.Errorf("folder listing context is required")
+	}
+	timeoutCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	names, err := p.store.listDirs(timeoutCtx, path.Join(helpers.GetRcloneBasePath(cfg, isVideo), artistFolder))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list remote artist folders: %w", err)
+	}
+	for _, name := range names {
+		folders[name] = struct{}{}
+	}
+	return folders, nil
+}
+
+// countingReader reports bytes read through onBytes.
+type countingReader struct {
+	r       io.Reader
+	onBytes func(n int64)
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	if n > 0 && c.onBytes != nil {
+		c.onBytes(int64(n))
+	}
+	return n, err
+}
@@ -0,0 +1,282 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jmagar/nugs-cli/internal/model"
+)
+
+// writeShow creates a small show folder with nested files.
+func writeShow(t *testing.T, dir string) string {
+	t.Helper()
+	show := filepath.Join(dir, "Phish - 1997-12-31")
+	files := map[string]string{
+		"01. Intro.flac":         strings.Repeat("a", 70_000),
+		"02. Tweezer.flac":       strings.Repeat("b", 1234),
+		"artwork/folder.jpg":     "jpeg",
+		"artwork/empty-file.txt": "",
+	}
+	for name, body := range files {
+		p := filepath.Join(show, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(body), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return show
+}
+
+func TestLocalProviderUploadListAndDelete(t *testing.T) {
+	src := writeShow(t, t.TempDir())
+	mirror := t.TempDir()
+	cfg := &model.Config{RcloneEnabled: true, RclonePath: mirror, DeleteAfterUpload: true, RcloneTransfers: 2}
+
+	provider := newProvider(localStore{})
+	recorded := ""
+	provider.recordUpload = func(_ *model.Config, _, remotePath string) error {
+		recorded = remotePath
+		return nil
+	}
+	var (
+		preBytes int64
+		last     model.UploadProgress
+		complete bool
+		deleted  string
+	)
+	err := provider.Upload(context.Background(), cfg, model.UploadRequest{LocalPath: src, ArtistFolder: "Phish"}, model.StorageHooks{
+		OnPreUpload:         func(total int64) { preBytes = total },
+		OnProgress:          func(p model.UploadProgress) { last = p },
+		OnComplete:          func() { complete = true },
+		OnDeleteAfterUpload: func(localPath string) { deleted = localPath },
+	})
+	if err != nil {
+		t.Fatalf("Upload() error = %v", err)
+	}
+	if preBytes != 70_000+1234+4 {
+		t.Fatalf("OnPreUpload total = %d", preBytes)
+	}
+	if last.Percent != 100 || last.Uploaded != last.Total {
+		t.Fatalf("final progress = %+v, want 100%%", last)
+	}
+	if !complete || deleted != src {
+		t.Fatalf("complete=%v deleted=%q", complete, deleted)
+	}
+	if _, err := os.Stat(src); !os.IsNotExist(err) {
+		t.Fatalf("local show still present after delete-after-upload: %v", err)
+	}
+	wantRemote := filepath.Join(mirror, "Phish", "Phish - 1997-12-31")
+	if recorded != wantRemote {
+		t.Fatalf("recorded remote = %q, want %q", recorded, wantRemote)
+	}
+	data, err := os.ReadFile(filepath.Join(wantRemote, "02. Tweezer.flac"))
+	if err != nil || len(data) != 1234 {
+		t.Fatalf("mirrored file: %d bytes, err %v", len(data), err)
+	}
+	if _, err := os.Stat(filepath.Join(wantRemote, "artwork", "empty-file.txt")); err != nil {
+		t.Fatalf("nested empty file not mirrored: %v", err)
+	}
+
+	exists, err := provider.PathExists(context.Background(), cfg, "Phish/Phish - 1997-12-31", false)
+	if err != nil || !exists {
+		t.Fatalf("PathExists = %v, %v", exists, err)
+	}
+	exists, err = provider.PathExists(context.Background(), cfg, "Phish/missing", false)
+	if err != nil || exists {
+		t.Fatalf("PathExists(missing) = %v, %v", exists, err)
+	}
+	folders, err := provider.ListArtistFolders(context.Background(), cfg, "Phish", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := folders["Phish - 1997-12-31"]; !ok || len(folders) != 1 {
+		t.Fatalf("ListArtistFolders = %v", folders)
+	}
+	artists, err := provider.ListArtistFolders(context.Background(), cfg, "", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := artists["Phish"]; !ok {
+		t.Fatalf("artist listing = %v", artists)
+	}
+	missing, err := provider.ListArtistFolders(context.Background(), cfg, "Nobody", false)
+	if err != nil || len(missing) != 0 {
+		t.Fatalf("missing artist listing = %v, %v", missing, err)
+	}
+}
+
+func TestProviderKeepsLocalFilesWhenVerifyFails(t *testing.T) {
+	src := writeShow(t, t.TempDir())
+	cfg := &model.Config{RcloneEnabled: true, RclonePath: t.TempDir(), DeleteAfterUpload: true}
+	provider := newProvider(truncatingStore{localStore{}})
+	provider.recordUpload = nil
+	provider.removeAll = func(string) error {
+		t.Fatal("local files removed despite failed verification")
+		return nil
+	}
+	err := provider.Upload(context.Background(), cfg, model.UploadRequest{LocalPath: src, ArtistFolder: "Phish"},
+		model.StorageHooks{OnProgress: func(model.UploadProgress) {}})
+	if err == nil || !strings.Contains(err.Error(), "NOT deleting") {
+		t.Fatalf("Upload() error = %v, want verification failure", err)
+	}
+}
+
+// truncatingStore reports every remote file one byte short.
+type truncatingStore struct{ localStore }
+
+func (s truncatingStore) size(ctx context.Context, remotePath string) (int64, bool, error) {
+	size, ok, err := s.localStore.size(ctx, remotePath)
+	return size - 1, ok, err
+}
+
+func TestProviderKeepsLocalFilesOnChecksumMismatch(t *testing.T) {
+	src := writeShow(t, t.TempDir())
+	cfg := &model.Config{RcloneEnabled: true, RclonePath: t.TempDir(), DeleteAfterUpload: true}
+	provider := newProvider(flippingStore{localStore{}})
+	provider.recordUpload = nil
+	provider.removeAll = func(string) error {
+		t.Fatal("local files removed despite checksum mismatch")
+		return nil
+	}
+	err := provider.Upload(context.Background(), cfg, model.UploadRequest{LocalPath: src, ArtistFolder: "Phish"},
+		model.StorageHooks{OnProgress: func(model.UploadProgress) {}})
+	if err == nil || !strings.Contains(err.Error(), "sha256 mismatch") {
+		t.Fatalf("Upload() error = %v, want checksum mismatch", err)
+	}
+}
+
+// flippingStore uploads every file with its first byte changed, so sizes
+// match but contents do not.
+type flippingStore struct{ localStore }
+
+func (s flippingStore) put(ctx context.Context, localPath, remotePath string, onBytes func(n int64)) error {
+	if err := s.localStore.put(ctx, localPath, remotePath, onBytes); err != nil {
+		return err
+	}
+	dest := filepath.FromSlash(remotePath)
+	data, err := os.ReadFile(dest)
+	if err != nil || len(data) == 0 {
+		return err
+	}
+	data[0] ^= 0xff
+	return os.WriteFile(dest, data, 0644)
+}
+
+func TestProviderReadFileAndHashes(t *testing.T) {
+	mirror := t.TempDir()
+	cfg := &model.Config{RcloneEnabled: true, RclonePath: mirror}
+	show := writeShow(t, filepath.Join(mirror, "Phish"))
+	if err := os.WriteFile(filepath.Join(show, "notes.txt"), []byte("abc"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	provider := newProvider(localStore{})
+
+	data, err := provider.ReadFile(context.Background(), cfg, "Phish/Phish - 1997-12-31/notes.txt", false)
+	if err != nil || string(data) != "abc" {
+		t.Fatalf("ReadFile = %q, %v", data, err)
+	}
+	if _, err := provider.ReadFile(context.Background(), cfg, "Phish/Phish - 1997-12-31/missing.json", false); !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("ReadFile(missing) error = %v, want fs.ErrNotExist", err)
+	}
+	hashes, err := provider.FileHashes(context.Background(), cfg, "Phish/Phish - 1997-12-31", []string{"notes.txt", "gone.flac"}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := model.FileHash{Algo: "sha256", Value: "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad"}
+	if len(hashes) != 1 || hashes["notes.txt"] != want {
+		t.Fatalf("FileHashes = %v, want only notes.txt %v", hashes, want)
+	}
+}
+
+func TestProviderSingleFileAndDisabled(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "show.mp4")
+	if err := os.WriteFile(file, []byte("video"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	mirror := t.TempDir()
+	cfg := &model.Config{RclonePath: "/unused", RcloneVideoPath: mirror}
+	provider := newProvider(localStore{})
+	provider.recordUpload = nil
+	req := model.UploadRequest{LocalPath: file, ArtistFolder: "Goose", IsVideo: true}
+	hooks := model.StorageHooks{OnProgress: func(model.UploadProgress) {}}
+
+	if err := provider.Upload(context.Background(), cfg, req, hooks); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(mirror, "Goose")); !os.IsNotExist(err) {
+		t.Fatal("upload ran with rcloneEnabled false")
+	}
+	cfg.RcloneEnabled = true
+	if err := provider.Upload(context.Background(), cfg, req, hooks); err != nil {
+		t.Fatal(err)
+	}
+	if data, err := os.ReadFile(filepath.Join(mirror, "Goose", "show.mp4")); err != nil || string(data) != "video" {
+		t.Fatalf("single file upload = %q, %v", data, err)
+	}
+	if err := provider.Upload(context.Background(), cfg, model.UploadRequest{LocalPath: file, ArtistFolder: "../x"}, hooks); err == nil {
+		t.Fatal("expected artist folder traversal to be rejected")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	t.Setenv("AWS_ACCESS_KEY_ID", "")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "")
+	tests := []struct {
+		name    string
+		cfg     model.Config
+		wantErr string
+	}{
+		{name: "default rclone", cfg: model.Config{}},
+		{name: "unknown", cfg: model.Config{StorageBackend: "ftp"}, wantErr: "unknown storageBackend"},
+		{name: "local relative", cfg: model.Config{StorageBackend: "local", RclonePath: "music"}, wantErr: "absolute"},
+		{name: "local ok", cfg: model.Config{StorageBackend: "Local", RclonePath: "/mnt/nas/music"}},
+		{name: "sftp no host", cfg: model.Config{StorageBackend: "sftp", RclonePath: "/music"}, wantErr: "sftpHost"},
+		{name: "sftp option host", cfg: model.Config{StorageBackend: "sftp", SftpHost: "-oProxyCommand=x", RclonePath: "/music"}, wantErr: "sftpHost"},
+		{name: "sftp ok", cfg: model.Config{StorageBackend: "sftp", SftpHost: "me@nas", RclonePath: "/music"}},
+		{name: "s3 no bucket", cfg: model.Config{StorageBackend: "s3"}, wantErr: "s3Bucket"},
+		{name: "s3 no keys", cfg: model.Config{StorageBackend: "s3", S3Bucket: "music"}, wantErr: "s3AccessKey"},
+		{name: "s3 ok", cfg: model.Config{StorageBackend: "s3", S3Bucket: "music", S3AccessKey: "a", S3SecretKey: "b"}},
+		{name: "destination without name", cfg: model.Config{UploadDestinations: []model.UploadDestination{{}}}, wantErr: "needs a name"},
+		{name: "duplicate destination", cfg: model.Config{UploadDestinations: []model.UploadDestination{{Name: "nas"}, {Name: "nas"}}}, wantErr: "listed twice"},
+		{name: "destination media", cfg: model.Config{UploadDestinations: []model.UploadDestination{{Name: "nas", Media: "flac"}}}, wantErr: "unknown media"},
+		{
+			name:    "destination backend",
+			cfg:     model.Config{UploadDestinations: []model.UploadDestination{{Name: "cold", StorageBackend: "s3"}}},
+			wantErr: `upload destination "cold": storageBackend s3 needs s3Bucket`,
+		},
+		{
+			name:    "delete needs a required destination",
+			cfg:     model.Config{DeleteAfterUpload: true, UploadDestinations: []model.UploadDestination{{Name: "nas", Optional: true}}},
+			wantErr: "not optional",
+		},
+		{
+			name: "destinations ok",
+			cfg: model.Config{DeleteAfterUpload: true, UploadDestinations: []model.UploadDestination{
+				{Name: "nas", StorageBackend: "local", RclonePath: "/mnt/nas"},
+				{Name: "cold", StorageBackend: "s3", S3Bucket: "b", S3AccessKey: "a", S3SecretKey: "s", Optional: true},
+			}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(&tt.cfg)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Validate() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Validate() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,3 @@
+// Package tagging reads and writes embedded metadata tags (FLAC Vorbis
+// comments and MP4 ilst atoms) in downloaded audio files without external tools.
+package tagging
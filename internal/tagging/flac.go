@@ -0,0 +1,300 @@
+package tagging
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+const (
+	flacBlockStreamInfo    = 0
+	flacBlockPadding       = 1
+	flacBlockVorbisComment = 4
+	flacBlockPicture       = 6
+
+	flacPictureFrontCover = 3
+
+	flacMaxBlockLen = 1<<24 - 1
+	flacVendor      = "nugs-cli"
+)
+
+var (
+	flacMagic = []byte("fLaC")
+
+	// ErrNotFLAC is returned when a file lacks the fLaC stream marker.
+	ErrNotFLAC = errors.New("not a FLAC stream")
+)
+
+// flacBlock is one parsed metadata block.
+type flacBlock struct {
+	blockType byte
+	data      []byte
+}
+
+// flacFile holds the parsed metadata of a FLAC stream and where audio frames start.
+type flacFile struct {
+	blocks      []flacBlock
+	audioOffset int64
+}
+
+// id3v2Len returns the total length of a leading ID3v2 tag, or 0 if absent.
+func id3v2Len(header []byte) int64 {
+	if len(header) < 10 || string(header[:3]) != "ID3" {
+		return 0
+	}
+	size := int64(header[6]&0x7f)<<21 | int64(header[7]&0x7f)<<14 | int64(header[8]&0x7f)<<7 | int64(header[9]&0x7f)
+	total := 10 + size
+	if header[5]&0x10 != 0 {
+		total += 10 // footer present
+	}
+	return total
+}
+
+// parseFLAC reads the metadata block chain from r.
+func parseFLAC(r io.ReadSeeker) (*flacFile, error) {
+	header := make([]byte, 10)
+	n, err := io.ReadFull(r, header)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return nil, err
+	}
+	prefix := id3v2Len(header[:n])
+	if _, err := r.Seek(prefix, io.SeekStart); err != nil {
+		return nil, err
+	}
+	br := bufio.NewReader(r)
+	magic := make([]byte, 4)
+	if _, err := io.ReadFull(br, magic); err != nil || !bytes.Equal(magic, flacMagic) {
+		return nil, ErrNotFLAC
+	}
+	ff := &flacFile{}
+	offset := prefix + 4
+	for {
+		blockHeader := make([]byte, 4)
+		if _, err := io.ReadFull(br, blockHeader); err != nil {
+			return nil, fmt.Errorf("read FLAC block header: %w", err)
+		}
+		last := blockHeader[0]&0x80 != 0
+		blockType := blockHeader[0] & 0x7f
+		length := int(blockHeader[1])<<16 | int(blockHeader[2])<<8 | int(blockHeader[3])
+		data := make([]byte, length)
+		if _, err := io.ReadFull(br, data); err != nil {
+			return nil, fmt.Errorf("read FLAC block type %d: %w", blockType, err)
+		}
+		ff.blocks = append(ff.blocks, flacBlock{blockType: blockType, data: data})
+		offset += 4 + int64(length)
+		if last {
+			break
+		}
+	}
+	if len(ff.blocks) == 0 || ff.blocks[0].blockType != flacBlockStreamInfo {
+		return nil, errors.New("FLAC stream is missing STREAMINFO")
+	}
+	ff.audioOffset = offset
+	return ff, nil
+}
+
+// parseVorbisComment decodes a VORBIS_COMMENT block body.
+func parseVorbisComment(data []byte) (string, []string, error) {
+	r := bytes.NewReader(data)
+	readString := func() (string, error) {
+		var length uint32
+		if err := binary.Read(r, binary.LittleEndian, &length); err != nil {
+			return "", err
+		}
+		if int64(length) > int64(r.Len()) {
+			return "", io.ErrUnexpectedEOF
+		}
+		buf := make([]byte, length)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return "", err
+		}
+		return string(buf), nil
+	}
+	vendor, err := readString()
+	if err != nil {
+		return "", nil, fmt.Errorf("read vorbis vendor: %w", err)
+	}
+	var count uint32
+	if err := binary.Read(r, binary.LittleEndian, &count); err != nil {
+		return "", nil, fmt.Errorf("read vorbis comment count: %w", err)
+	}
+	comments := make([]string, 0, min(int(count), 256))
+	for range count {
+		comment, err := readString()
+		if err != nil {
+			return "", nil, fmt.Errorf("read vorbis comment: %w", err)
+		}
+		comments = append(comments, comment)
+	}
+	return vendor, comments, nil
+}
+
+// buildVorbisComment encodes a VORBIS_COMMENT block body.
+func buildVorbisComment(vendor string, comments []string) []byte {
+	var buf bytes.Buffer
+	_ = binary.Write(&buf, binary.LittleEndian, uint32(len(vendor)))
+	buf.WriteString(vendor)
+	_ = binary.Write(&buf, binary.LittleEndian, uint32(len(comments)))
+	for _, comment := range comments {
+		_ = binary.Write(&buf, binary.LittleEndian, uint32(len(comment)))
+		buf.WriteString(comment)
+	}
+	return buf.Bytes()
+}
+
+// flacPictureType returns the APIC picture type of a PICTURE block body.
+func flacPictureType(data []byte) uint32 {
+	if len(data) < 4 {
+		return 0
+	}
+	return binary.BigEndian.Uint32(data[:4])
+}
+
+// buildFLACPicture encodes a front-cover PICTURE block body.
+func buildFLACPicture(pic *Picture) []byte {
+	var buf bytes.Buffer
+	writeUint32 := func(v uint32) { _ = binary.Write(&buf, binary.BigEndian, v) }
+	writeUint32(flacPictureFrontCover)
+	writeUint32(uint32(len(pic.MIMEType)))
+	buf.WriteString(pic.MIMEType)
+	writeUint32(0) // description length
+	writeUint32(uint32(pic.Width))
+	writeUint32(uint32(pic.Height))
+	writeUint32(24) // colour depth
+	writeUint32(0)  // indexed colours
+	writeUint32(uint32(len(pic.Data)))
+	buf.Write(pic.Data)
+	return buf.Bytes()
+}
+
+// commentKey returns the upper-cased field name of a KEY=value comment.
+func commentKey(comment string) string {
+	key, _, _ := strings.Cut(comment, "=")
+	return strings.ToUpper(key)
+}
+
+// mergeComments keeps existing comments whose keys are not being replaced and
+// appends the new fields after them.
+func mergeComments(existing []string, fields []field) []string {
+	replaced := make(map[string]struct{}, len(fields))
+	for _, f := range fields {
+		replaced[f.key] = struct{}{}
+	}
+	merged := make([]string, 0, len(existing)+len(fields))
+	for _, comment := range existing {
+		if _, ok := replaced[commentKey(comment)]; ok {
+			continue
+		}
+		merged = append(merged, comment)
+	}
+	for _, f := range fields {
+		merged = append(merged, f.key+"="+f.value)
+	}
+	return merged
+}
+
+func writeFLAC(path string, tags Tags) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	ff, err := parseFLAC(src)
+	if err != nil {
+		return err
+	}
+
+	vendor := flacVendor
+	var existing []string
+	blocks := make([]flacBlock, 0, len(ff.blocks)+1)
+	for _, block := range ff.blocks {
+		switch block.blockType {
+		case flacBlockVorbisComment:
+			if v, comments, parseErr := parseVorbisComment(block.data); parseErr == nil {
+				vendor, existing = v, comments
+			}
+		case flacBlockPadding:
+			// Dropped; the file is rewritten so padding buys nothing.
+		case flacBlockPicture:
+			if tags.Picture == nil || flacPictureType(block.data) != flacPictureFrontCover {
+				blocks = append(blocks, block)
+			}
+		default:
+			blocks = append(blocks, block)
+		}
+	}
+	comment := buildVorbisComment(vendor, mergeComments(existing, tags.fields()))
+	if len(comment) > flacMaxBlockLen {
+		return errors.New("vorbis comment block exceeds FLAC block size limit")
+	}
+	// VORBIS_COMMENT goes directly after STREAMINFO, where most readers expect it.
+	blocks = append(blocks[:1], append([]flacBlock{{blockType: flacBlockVorbisComment, data: comment}}, blocks[1:]...)...)
+	if tags.Picture != nil {
+		blocks = append(blocks, flacBlock{blockType: flacBlockPicture, data: buildFLACPicture(tags.Picture)})
+	}
+
+	// A leading ID3v2 tag is non-standard in FLAC and is not carried over.
+	return replaceFile(path, func(dst *os.File) error {
+		w := bufio.NewWriter(dst)
+		if _, err := w.Write(flacMagic); err != nil {
+			return err
+		}
+		for i, block := range blocks {
+			if len(block.data) > flacMaxBlockLen {
+				return fmt.Errorf("FLAC block type %d exceeds size limit", block.blockType)
+			}
+			typeByte := block.blockType
+			if i == len(blocks)-1 {
+				typeByte |= 0x80
+			}
+			length := len(block.data)
+			if _, err := w.Write([]byte{typeByte, byte(length >> 16), byte(length >> 8), byte(length)}); err != nil {
+				return err
+			}
+			if _, err := w.Write(block.data); err != nil {
+				return err
+			}
+		}
+		if _, err := src.Seek(ff.audioOffset, io.SeekStart); err != nil {
+			return err
+		}
+		if _, err := io.Copy(w, src); err != nil {
+			return fmt.Errorf("copy FLAC frames: %w", err)
+		}
+		return w.Flush()
+	})
+}
+
+func readFLAC(path string) (map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	ff, err := parseFLAC(f)
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[string]string)
+	for _, block := range ff.blocks {
+		if block.blockType != flacBlockVorbisComment {
+			continue
+		}
+		_, comments, err := parseVorbisComment(block.data)
+		if err != nil {
+			return nil, err
+		}
+		for _, comment := range comments {
+			key, value, ok := strings.Cut(comment, "=")
+			if ok {
+				out[strings.ToUpper(key)] = value
+			}
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,185 @@
+package tagging
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// minimalFLAC returns a FLAC stream with STREAMINFO, an optional vorbis
+// comment block, padding, and the given frame bytes.
+func minimalFLAC(t *testing.T, comments []string, frames []byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	buf.Write(flacMagic)
+	streamInfo := make([]byte, 34)
+	buf.Write([]byte{flacBlockStreamInfo, 0, 0, byte(len(streamInfo))})
+	buf.Write(streamInfo)
+	if comments != nil {
+		body := buildVorbisComment("reference", comments)
+		buf.Write([]byte{flacBlockVorbisComment, byte(len(body) >> 16), byte(len(body) >> 8), byte(len(body))})
+		buf.Write(body)
+	}
+	buf.Write([]byte{0x80 | flacBlockPadding, 0, 0, 16})
+	buf.Write(make([]byte, 16))
+	buf.Write(frames)
+	return buf.Bytes()
+}
+
+func TestWriteFLACTagsAndPreservesFrames(t *testing.T) {
+	frames := []byte{0xff, 0xf8, 0x01, 0x02, 0x03}
+	path := filepath.Join(t.TempDir(), "01. Song.flac")
+	if err := os.WriteFile(path, minimalFLAC(t, []string{"COMMENT=keep me", "TITLE=old"}, frames), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tags := Tags{
+		Artist:      "Billy Strings",
+		Album:       "2024-03-02 Red Rocks",
+		Title:       "Dust in a Baggie",
+		Date:        "2024-03-02",
+		Venue:       "Red Rocks Amphitheatre",
+		City:        "Morrison",
+		State:       "CO",
+		TrackNumber: 3,
+		TrackTotal:  24,
+		DiscNumber:  2,
+		DiscTotal:   3,
+	}
+	if err := WriteFile(path, tags); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	got, err := ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	want := map[string]string{
+		FieldArtist:      "Billy Strings",
+		FieldAlbum:       "2024-03-02 Red Rocks",
+		FieldTitle:       "Dust in a Baggie",
+		FieldDate:        "2024-03-02",
+		FieldTrackNumber: "3",
+		FieldTrackTotal:  "24",
+		FieldDiscNumber:  "2",
+		FieldDiscTotal:   "3",
+		FieldVenue:       "Red Rocks Amphitheatre",
+		FieldLocation:    "Morrison, CO",
+		"COMMENT":        "keep me",
+	}
+	for key, value := range want {
+		if got[key] != value {
+			t.Errorf("tag %s = %q, want %q", key, got[key], value)
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasSuffix(data, frames) {
+		t.Fatal("audio frames were not preserved after the metadata blocks")
+	}
+}
+
+func TestWriteFLACIsIdempotent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "track.flac")
+	if err := os.WriteFile(path, minimalFLAC(t, nil, []byte{0xff, 0xf8}), 0644); err != nil {
+		t.Fatal(err)
+	}
+	tags := Tags{Title: "Song", TrackNumber: 1}
+	for range 2 {
+		if err := WriteFile(path, tags); err != nil {
+			t.Fatalf("WriteFile: %v", err)
+		}
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	ff, err := parseFLAC(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var commentBlocks int
+	for _, block := range ff.blocks {
+		if block.blockType == flacBlockVorbisComment {
+			_, comments, err := parseVorbisComment(block.data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(comments) != 2 {
+				t.Fatalf("comments = %v, want exactly TITLE and TRACKNUMBER", comments)
+			}
+			commentBlocks++
+		}
+	}
+	if commentBlocks != 1 {
+		t.Fatalf("vorbis comment blocks = %d, want 1", commentBlocks)
+	}
+}
+
+func TestWriteFileRejectsNonFLACContent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "track.flac")
+	if err := os.WriteFile(path, []byte("audio-bytes"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteFile(path, Tags{Title: "x"}); !errors.Is(err, ErrNotFLAC) {
+		t.Fatalf("WriteFile error = %v, want ErrNotFLAC", err)
+	}
+	data, _ := os.ReadFile(path)
+	if string(data) != "audio-bytes" {
+		t.Fatal("file content changed after a rejected tag write")
+	}
+}
+
+func TestWriteFileUnsupportedExtension(t *testing.T) {
+	if err := WriteFile("track.ts", Tags{}); !errors.Is(err, ErrUnsupportedFormat) {
+		t.Fatalf("WriteFile error = %v, want ErrUnsupportedFormat", err)
+	}
+}
+
+func TestWriteFLACEmbedsSingleFrontCover(t *testing.T) {
+	frames := []byte{0xff, 0xf8, 0x09}
+	path := filepath.Join(t.TempDir(), "01. Song.flac")
+	if err := os.WriteFile(path, minimalFLAC(t, nil, frames), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	pic := &Picture{MIMEType: "image/jpeg", Width: 2, Height: 2, Data: []byte("jpeg-bytes")}
+	for i := 0; i < 2; i++ {
+		if err := WriteFile(path, Tags{Title: "Song", Picture: pic}); err != nil {
+			t.Fatalf("WriteFile #%d: %v", i+1, err)
+		}
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	parsed, err := parseFLAC(f)
+	if err != nil {
+		t.Fatalf("parseFLAC: %v", err)
+	}
+	var pictures [][]byte
+	for _, block := range parsed.blocks {
+		if block.blockType == flacBlockPicture {
+			pictures = append(pictures, block.data)
+		}
+	}
+	if len(pictures) != 1 {
+		t.Fatalf("picture blocks = %d, want 1 after rewriting twice", len(pictures))
+	}
+	if flacPictureType(pictures[0]) != flacPictureFrontCover || !bytes.HasSuffix(pictures[0], pic.Data) {
+		t.Fatalf("picture block does not hold the front cover image")
+	}
+
+	raw, _ := os.ReadFile(path)
+	if !bytes.HasSuffix(raw, frames) {
+		t.Fatalf("audio frames not preserved after embedding artwork")
+	}
+}
@@ -0,0 +1,556 @@
+package tagging
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+)
+
+const (
+	mp4DataTypeImplicit = 0
+	mp4DataTypeUTF8     = 1
+	mp4DataTypeJPEG     = 13
+	mp4DataTypePNG      = 14
+
+	mp4FreeformMean = "com.apple.iTunes"
+	mp4MaxMoovSize  = 64 << 20
+)
+
+var (
+	// ErrNotMP4 is returned when a file has no moov atom.
+	ErrNotMP4 = errors.New("not an MP4 file (no moov atom)")
+
+	// errFragmentBaseOffset is returned for fragmented files whose fragments
+	// address sample data absolutely; growing moov would corrupt them.
+	errFragmentBaseOffset = errors.New("fragmented MP4 with absolute base data offsets cannot be retagged")
+)
+
+// mp4TextAtoms maps canonical field names to iTunes-style ilst atom types.
+// Fields not listed here are stored as "----" freeform atoms.
+var mp4TextAtoms = map[string]string{
+	FieldArtist:      "\xa9ART",
+	FieldAlbumArtist: "aART",
+	FieldAlbum:       "\xa9alb",
+	FieldTitle:       "\xa9nam",
+	FieldDate:        "\xa9day",
+}
+
+// mp4Atom is one parsed atom: its four-character type and raw body.
+type mp4Atom struct {
+	typ  string
+	body []byte
+}
+
+// topLevelAtom records the position of an atom in the file.
+type topLevelAtom struct {
+	typ    string
+	offset int64
+	size   int64
+}
+
+// encodeAtom serialises an atom with a 32-bit size header.
+func encodeAtom(typ string, body []byte) []byte {
+	out := make([]byte, 8, 8+len(body))
+	binary.BigEndian.PutUint32(out[:4], uint32(8+len(body)))
+	copy(out[4:8], typ)
+	return append(out, body...)
+}
+
+// parseAtoms splits b into consecutive atoms.
+func parseAtoms(b []byte) ([]mp4Atom, error) {
+	var atoms []mp4Atom
+	for len(b) > 0 {
+		if len(b) < 8 {
+			return nil, errors.New("truncated MP4 atom header")
+		}
+		size := uint64(binary.BigEndian.Uint32(b[:4]))
+		typ := string(b[4:8])
+		headerLen := uint64(8)
+		switch size {
+		case 0:
+			size = uint64(len(b))
+		case 1:
+			if len(b) < 16 {
+				return nil, errors.New("truncated MP4 large atom header")
+			}
+			size = binary.BigEndian.Uint64(b[8:16])
+			headerLen = 16
+		}
+		if size < headerLen || size > uint64(len(b)) {
+			return nil, fmt.Errorf("invalid MP4 atom size %d for %q", size, typ)
+		}
+		atoms = append(atoms, mp4Atom{typ: typ, body: b[headerLen:size]})
+		b = b[size:]
+	}
+	return atoms, nil
+}
+
+// encodeAtoms serialises a list of atoms back to bytes.
+func encodeAtoms(atoms []mp4Atom) []byte {
+	var buf bytes.Buffer
+	for _, atom := range atoms {
+		buf.Write(encodeAtom(atom.typ, atom.body))
+	}
+	return buf.Bytes()
+}
+
+// findAtom returns the index of the first atom of typ, or -1.
+func findAtom(atoms []mp4Atom, typ string) int {
+	for i, atom := range atoms {
+		if atom.typ == typ {
+			return i
+		}
+	}
+	return -1
+}
+
+// scanTopLevel lists the top-level atoms of r without reading their bodies.
+func scanTopLevel(r io.ReadSeeker) ([]topLevelAtom, error) {
+	end, err := r.Seek(0, io.SeekEnd)
+	if err != nil {
+		return nil, err
+	}
+	var atoms []topLevelAtom
+	var offset int64
+	header := make([]byte, 16)
+	for offset < end {
+		if _, err := r.Seek(offset, io.SeekStart); err != nil {
+			return nil, err
+		}
+		if _, err := io.ReadFull(r, header[:8]); err != nil {
+			return nil, fmt.Errorf("read MP4 atom header at %d: %w", offset, err)
+		}
+		size := int64(binary.BigEndian.Uint32(header[:4]))
+		typ := string(header[4:8])
+		switch size {
+		case 0:
+			size = end - offset
+		case 1:
+			if _, err := io.ReadFull(r, header[8:16]); err != nil {
+				return nil, fmt.Errorf("read MP4 large atom header at %d: %w", offset, err)
+			}
+			large := binary.BigEndian.Uint64(header[8:16])
+			if large > math.MaxInt64 {
+				return nil, fmt.Errorf("invalid MP4 atom size for %q", typ)
+			}
+			size = int64(large)
+		}
+		if size < 8 || offset+size > end {
+			return nil, fmt.Errorf("invalid MP4 atom size %d for %q at %d", size, typ, offset)
+		}
+		atoms = append(atoms, topLevelAtom{typ: typ, offset: offset, size: size})
+		offset += size
+	}
+	return atoms, nil
+}
+
+// readMoov locates and loads the moov atom body.
+func readMoov(f *os.File) ([]topLevelAtom, int, []byte, error) {
+	atoms, err := scanTopLevel(f)
+	if err != nil {
+		return nil, -1, nil, err
+	}
+	for i, atom := range atoms {
+		if atom.typ != "moov" {
+			continue
+		}
+		if atom.size > mp4MaxMoovSize {
+			return nil, -1, nil, fmt.Errorf("moov atom too large (%d bytes)", atom.size)
+		}
+		buf := make([]byte, atom.size)
+		if _, err := f.ReadAt(buf, atom.offset); err != nil {
+			return nil, -1, nil, fmt.Errorf("read moov: %w", err)
+		}
+		moovAtoms, err := parseAtoms(buf)
+		if err != nil || len(moovAtoms) != 1 {
+			return nil, -1, nil, fmt.Errorf("parse moov: %w", err)
+		}
+		return atoms, i, moovAtoms[0].body, nil
+	}
+	return nil, -1, nil, ErrNotMP4
+}
+
+// ilstItemKey identifies an ilst item; freeform items are keyed by their name.
+func ilstItemKey(item mp4Atom) string {
+	if item.typ != "----" {
+		return item.typ
+	}
+	children, err := parseAtoms(item.body)
+	if err != nil {
+		return item.typ
+	}
+	if idx := findAtom(children, "name"); idx >= 0 && len(children[idx].body) >= 4 {
+		return "----:" + strings.ToUpper(string(children[idx].body[4:]))
+	}
+	return item.typ
+}
+
+// dataAtom builds an ilst "data" child with the given type indicator.
+func dataAtom(dataType uint32, payload []byte) []byte {
+	body := make([]byte, 8, 8+len(payload))
+	binary.BigEndian.PutUint32(body[:4], dataType)
+	return encodeAtom("data", append(body, payload...))
+}
+
+// numberPairAtom builds a trkn/disk item body.
+func numberPairAtom(number, total int, trailing bool) []byte {
+	payload := make([]byte, 6, 8)
+	binary.BigEndian.PutUint16(payload[2:4], uint16(number))
+	binary.BigEndian.PutUint16(payload[4:6], uint16(total))
+	if trailing {
+		payload = append(payload, 0, 0)
+	}
+	return dataAtom(mp4DataTypeImplicit, payload)
+}
+
+// freeformAtom builds a "----" item body storing name=value.
+func freeformAtom(name, value string) []byte {
+	var body []byte
+	body = append(body, encodeAtom("mean", append(make([]byte, 4), mp4FreeformMean...))...)
+	body = append(body, encodeAtom("name", append(make([]byte, 4), name...))...)
+	return append(body, dataAtom(mp4DataTypeUTF8, []byte(value))...)
+}
+
+// buildIlstItems converts Tags into ilst items.
+func buildIlstItems(tags Tags) []mp4Atom {
+	var items []mp4Atom
+	for _, f := range tags.fields() {
+		switch f.key {
+		case FieldTrackNumber, FieldTrackTotal, FieldDiscNumber, FieldDiscTotal:
+			continue
+		}
+		if typ, ok := mp4TextAtoms[f.key]; ok {
+			items = append(items, mp4Atom{typ: typ, body: dataAtom(mp4DataTypeUTF8, []byte(f.value))})
+			continue
+		}
+		items = append(items, mp4Atom{typ: "----", body: freeformAtom(f.key, f.value)})
+	}
+	if tags.TrackNumber > 0 {
+		items = append(items, mp4Atom{typ: "trkn", body: numberPairAtom(tags.TrackNumber, tags.TrackTotal, true)})
+	}
+	if tags.DiscNumber > 0 {
+		items = append(items, mp4Atom{typ: "disk", body: numberPairAtom(tags.DiscNumber, tags.DiscTotal, false)})
+	}
+	if tags.Picture != nil {
+		dataType := uint32(mp4DataTypeJPEG)
+		if tags.Picture.MIMEType == "image/png" {
+			dataType = mp4DataTypePNG
+		}
+		items = append(items, mp4Atom{typ: "covr", body: dataAtom(dataType, tags.Picture.Data)})
+	}
+	return items
+}
+
+// mergeIlst replaces existing items that share a key with a new item.
+func mergeIlst(existing, items []mp4Atom) []mp4Atom {
+	replaced := make(map[string]struct{}, len(items))
+	for _, item := range items {
+		replaced[ilstItemKey(item)] = struct{}{}
+	}
+	merged := make([]mp4Atom, 0, len(existing)+len(items))
+	for _, item := range existing {
+		if _, ok := replaced[ilstItemKey(item)]; ok {
+			continue
+		}
+		merged = append(merged, item)
+	}
+	return append(merged, items...)
+}
+
+// mdirHandler is the hdlr body iTunes-style metadata readers require.
+func mdirHandler() []byte {
+	body := make([]byte, 25)
+	copy(body[8:12], "mdir")
+	copy(body[12:16], "appl")
+	return body
+}
+
+// rebuildMoov returns a new moov body with tags merged into udta/meta/ilst.
+func rebuildMoov(moovBody []byte, tags Tags) ([]byte, error) {
+	moov, err := parseAtoms(moovBody)
+	if err != nil {
+		return nil, err
+	}
+	var udta []mp4Atom
+	udtaIdx := findAtom(moov, "udta")
+	if udtaIdx >= 0 {
+		if udta, err = parseAtoms(moov[udtaIdx].body); err != nil {
+			return nil, fmt.Errorf("parse udta: %w", err)
+		}
+	}
+	metaHeader := make([]byte, 4)
+	var meta []mp4Atom
+	metaIdx := findAtom(udta, "meta")
+	if metaIdx >= 0 {
+		body := udta[metaIdx].body
+		if len(body) < 4 {
+			return nil, errors.New("truncated meta atom")
+		}
+		copy(metaHeader, body[:4])
+		if meta, err = parseAtoms(body[4:]); err != nil {
+			return nil, fmt.Errorf("parse meta: %w", err)
+		}
+	}
+	if findAtom(meta, "hdlr") < 0 {
+		meta = append([]mp4Atom{{typ: "hdlr", body: mdirHandler()}}, meta...)
+	}
+	var ilst []mp4Atom
+	ilstIdx := findAtom(meta, "ilst")
+	if ilstIdx >= 0 {
+		if ilst, err = parseAtoms(meta[ilstIdx].body); err != nil {
+			return nil, fmt.Errorf("parse ilst: %w", err)
+		}
+	}
+	newIlst := mp4Atom{typ: "ilst", body: encodeAtoms(mergeIlst(ilst, buildIlstItems(tags)))}
+	if ilstIdx >= 0 {
+		meta[ilstIdx] = newIlst
+	} else {
+		meta = append(meta, newIlst)
+	}
+	newMeta := mp4Atom{typ: "meta", body: append(metaHeader, encodeAtoms(meta)...)}
+	if metaIdx >= 0 {
+		udta[metaIdx] = newMeta
+	} else {
+		udta = append(udta, newMeta)
+	}
+	newUdta := mp4Atom{typ: "udta", body: encodeAtoms(udta)}
+	if udtaIdx >= 0 {
+		moov[udtaIdx] = newUdta
+	} else {
+		moov = append(moov, newUdta)
+	}
+	return encodeAtoms(moov), nil
+}
+
+// shiftChunkOffsets adds delta to every stco/co64 entry at or beyond threshold.
+// Bodies are modified in place.
+func shiftChunkOffsets(atoms []mp4Atom, threshold, delta int64) error {
+	for _, atom := range atoms {
+		switch atom.typ {
+		case "trak", "mdia", "minf", "stbl":
+			children, err := parseAtoms(atom.body)
+			if err != nil {
+				return fmt.Errorf("parse %s: %w", atom.typ, err)
+			}
+			if err := shiftChunkOffsets(children, threshold, delta); err != nil {
+				return err
+			}
+		case "stco":
+			if len(atom.body) < 8 {
+				return errors.New("truncated stco atom")
+			}
+			count := int(binary.BigEndian.Uint32(atom.body[4:8]))
+			if len(atom.body) < 8+count*4 {
+				return errors.New("truncated stco entries")
+			}
+			for i := range count {
+				pos := 8 + i*4
+				offset := int64(binary.BigEndian.Uint32(atom.body[pos : pos+4]))
+				if offset < threshold {
+					continue
+				}
+				shifted := offset + delta
+				if shifted < 0 || shifted > math.MaxUint32 {
+					return errors.New("stco offset overflow after retagging")
+				}
+				binary.BigEndian.PutUint32(atom.body[pos:pos+4], uint32(shifted))
+			}
+		case "co64":
+			if len(atom.body) < 8 {
+				return errors.New("truncated co64 atom")
+			}
+			count := int(binary.BigEndian.Uint32(atom.body[4:8]))
+			if len(atom.body) < 8+count*8 {
+				return errors.New("truncated co64 entries")
+			}
+			for i := range count {
+				pos := 8 + i*8
+				offset := int64(binary.BigEndian.Uint64(atom.body[pos : pos+8]))
+				if offset >= threshold {
+					binary.BigEndian.PutUint64(atom.body[pos:pos+8], uint64(offset+delta))
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// checkFragments rejects fragmented layouts that would break when moov grows.
+func checkFragments(f *os.File, atoms []topLevelAtom, afterOffset int64) error {
+	for _, atom := range atoms {
+		if atom.typ != "moof" || atom.offset < afterOffset {
+			continue
+		}
+		buf := make([]byte, atom.size-8)
+		if _, err := f.ReadAt(buf, atom.offset+8); err != nil {
+			return fmt.Errorf("read moof: %w", err)
+		}
+		children, err := parseAtoms(buf)
+		if err != nil {
+			return fmt.Errorf("parse moof: %w", err)
+		}
+		for _, child := range children {
+			if child.typ != "traf" {
+				continue
+			}
+			trafChildren, err := parseAtoms(child.body)
+			if err != nil {
+				return fmt.Errorf("parse traf: %w", err)
+			}
+			if idx := findAtom(trafChildren, "tfhd"); idx >= 0 {
+				body := trafChildren[idx].body
+				if len(body) >= 4 && binary.BigEndian.Uint32(body[:4])&0x1 != 0 {
+					return errFragmentBaseOffset
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func writeMP4(path string, tags Tags) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	atoms, moovIdx, moovBody, err := readMoov(src)
+	if err != nil {
+		return err
+	}
+	moovAtom := atoms[moovIdx]
+	moovEnd := moovAtom.offset + moovAtom.size
+
+	newBody, err := rebuildMoov(moovBody, tags)
+	if err != nil {
+		return err
+	}
+	delta := int64(8+len(newBody)) - moovAtom.size
+	if delta != 0 {
+		// Sample data located after moov moves by delta bytes.
+		children, err := parseAtoms(newBody)
+		if err != nil {
+			return err
+		}
+		if err := shiftChunkOffsets(children, moovEnd, delta); err != nil {
+			return err
+		}
+		if err := checkFragments(src, atoms, moovEnd); err != nil {
+			return err
+		}
+	}
+
+	return replaceFile(path, func(dst *os.File) error {
+		w := bufio.NewWriter(dst)
+		if _, err := io.Copy(w, io.NewSectionReader(src, 0, moovAtom.offset)); err != nil {
+			return err
+		}
+		if _, err := w.Write(encodeAtom("moov", newBody)); err != nil {
+			return err
+		}
+		info, err := src.Stat()
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(w, io.NewSectionReader(src, moovEnd, info.Size()-moovEnd)); err != nil {
+			return fmt.Errorf("copy MP4 media data: %w", err)
+		}
+		return w.Flush()
+	})
+}
+
+// ilstDataPayload returns the payload of an item's first data child.
+func ilstDataPayload(item mp4Atom) ([]byte, bool) {
+	children, err := parseAtoms(item.body)
+	if err != nil {
+		return nil, false
+	}
+	idx := findAtom(children, "data")
+	if idx < 0 || len(children[idx].body) < 8 {
+		return nil, false
+	}
+	return children[idx].body[8:], true
+}
+
+func readMP4(path string) (map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	_, _, moovBody, err := readMoov(f)
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[string]string)
+	moov, err := parseAtoms(moovBody)
+	if err != nil {
+		return nil, err
+	}
+	udtaIdx := findAtom(moov, "udta")
+	if udtaIdx < 0 {
+		return out, nil
+	}
+	udta, err := parseAtoms(moov[udtaIdx].body)
+	if err != nil {
+		return nil, err
+	}
+	metaIdx := findAtom(udta, "meta")
+	if metaIdx < 0 || len(udta[metaIdx].body) < 4 {
+		return out, nil
+	}
+	meta, err := parseAtoms(udta[metaIdx].body[4:])
+	if err != nil {
+		return nil, err
+	}
+	ilstIdx := findAtom(meta, "ilst")
+	if ilstIdx < 0 {
+		return out, nil
+	}
+	items, err := parseAtoms(meta[ilstIdx].body)
+	if err != nil {
+		return nil, err
+	}
+	textFields := make(map[string]string, len(mp4TextAtoms))
+	for key, typ := range mp4TextAtoms {
+		textFields[typ] = key
+	}
+	for _, item := range items {
+		payload, ok := ilstDataPayload(item)
+		if !ok {
+			continue
+		}
+		switch {
+		case item.typ == "trkn" || item.typ == "disk":
+			if len(payload) < 6 {
+				continue
+			}
+			numberKey, totalKey := FieldTrackNumber, FieldTrackTotal
+			if item.typ == "disk" {
+				numberKey, totalKey = FieldDiscNumber, FieldDiscTotal
+			}
+			if n := binary.BigEndian.Uint16(payload[2:4]); n > 0 {
+				out[numberKey] = strconv.Itoa(int(n))
+			}
+			if total := binary.BigEndian.Uint16(payload[4:6]); total > 0 {
+				out[totalKey] = strconv.Itoa(int(total))
+			}
+		case item.typ == "----":
+			if key := ilstItemKey(item); strings.HasPrefix(key, "----:") {
+				out[strings.TrimPrefix(key, "----:")] = string(payload)
+			}
+		default:
+			if key, ok := textFields[item.typ]; ok {
+				out[key] = string(payload)
+			}
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,195 @@
+package tagging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// minimalMP4 builds ftyp + moov + mdat where the single stco entry points at
+// the mdat payload. When moovFirst is false, mdat precedes moov.
+func minimalMP4(payload []byte, moovFirst bool) []byte {
+	ftyp := encodeAtom("ftyp", []byte("M4A \x00\x00\x00\x00M4A mp42isom"))
+	mdat := encodeAtom("mdat", payload)
+
+	buildMoov := func(chunkOffset uint32) []byte {
+		stco := make([]byte, 12)
+		binary.BigEndian.PutUint32(stco[4:8], 1)
+		binary.BigEndian.PutUint32(stco[8:12], chunkOffset)
+		stbl := encodeAtom("stbl", encodeAtom("stco", stco))
+		minf := encodeAtom("minf", stbl)
+		mdia := encodeAtom("mdia", minf)
+		trak := encodeAtom("trak", mdia)
+		return encodeAtom("moov", append(encodeAtom("mvhd", make([]byte, 100)), trak...))
+	}
+
+	var buf bytes.Buffer
+	buf.Write(ftyp)
+	if moovFirst {
+		moovLen := len(buildMoov(0))
+		buf.Write(buildMoov(uint32(len(ftyp) + moovLen + 8)))
+		buf.Write(mdat)
+	} else {
+		buf.Write(mdat)
+		buf.Write(buildMoov(uint32(len(ftyp) + 8)))
+	}
+	return buf.Bytes()
+}
+
+// chunkOffset returns the first stco entry of the file.
+func chunkOffset(t *testing.T, path string) uint32 {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	_, _, moovBody, err := readMoov(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	atoms, _ := parseAtoms(moovBody)
+	for _, typ := range []string{"trak", "mdia", "minf", "stbl", "stco"} {
+		idx := findAtom(atoms, typ)
+		if idx < 0 {
+			t.Fatalf("missing %s atom", typ)
+		}
+		if typ == "stco" {
+			return binary.BigEndian.Uint32(atoms[idx].body[8:12])
+		}
+		atoms, _ = parseAtoms(atoms[idx].body)
+	}
+	return 0
+}
+
+func TestWriteMP4TagsShiftsChunkOffsets(t *testing.T) {
+	for _, moovFirst := range []bool{true, false} {
+		payload := []byte("sample-data")
+		path := filepath.Join(t.TempDir(), "01. Song.m4a")
+		if err := os.WriteFile(path, minimalMP4(payload, moovFirst), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		tags := Tags{
+			Artist:      "Goose",
+			AlbumArtist: "Goose",
+			Album:       "2024-06-22 Forest Hills",
+			Title:       "Arcadia",
+			Date:        "2024-06-22",
+			Venue:       "Forest Hills Stadium",
+			City:        "Queens",
+			State:       "NY",
+			TrackNumber: 4,
+			TrackTotal:  18,
+			DiscNumber:  1,
+			DiscTotal:   2,
+			ContainerID: 12345,
+		}
+		if err := WriteFile(path, tags); err != nil {
+			t.Fatalf("moovFirst=%v WriteFile: %v", moovFirst, err)
+		}
+
+		got, err := ReadFile(path)
+		if err != nil {
+			t.Fatalf("ReadFile: %v", err)
+		}
+		want := map[string]string{
+			FieldArtist:      "Goose",
+			FieldAlbumArtist: "Goose",
+			FieldAlbum:       "2024-06-22 Forest Hills",
+			FieldTitle:       "Arcadia",
+			FieldDate:        "2024-06-22",
+			FieldTrackNumber: "4",
+			FieldTrackTotal:  "18",
+			FieldDiscNumber:  "1",
+			FieldDiscTotal:   "2",
+			FieldVenue:       "Forest Hills Stadium",
+			FieldLocation:    "Queens, NY",
+			FieldContainerID: "12345",
+		}
+		for key, value := range want {
+			if got[key] != value {
+				t.Errorf("moovFirst=%v tag %s = %q, want %q", moovFirst, key, got[key], value)
+			}
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		offset := chunkOffset(t, path)
+		if int(offset)+len(payload) > len(data) || !bytes.Equal(data[offset:int(offset)+len(payload)], payload) {
+			t.Fatalf("moovFirst=%v stco offset %d no longer points at sample data", moovFirst, offset)
+		}
+	}
+}
+
+func TestWriteMP4ReplacesExistingItems(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "track.m4a")
+	if err := os.WriteFile(path, minimalMP4([]byte("x"), true), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteFile(path, Tags{Title: "First", Venue: "Old Venue"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteFile(path, Tags{Title: "Second", Venue: "New Venue"}); err != nil {
+		t.Fatal(err)
+	}
+	got, err := ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got[FieldTitle] != "Second" || got[FieldVenue] != "New Venue" {
+		t.Fatalf("tags = %v, want replaced title and venue", got)
+	}
+}
+
+func TestWriteMP4EmbedsCoverArt(t *testing.T) {
+	payload := []byte("sample-data")
+	path := filepath.Join(t.TempDir(), "01. Song.m4a")
+	if err := os.WriteFile(path, minimalMP4(payload, true), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	pic := &Picture{MIMEType: "image/png", Data: []byte("png-bytes")}
+	if err := WriteFile(path, Tags{Title: "Arcadia", Picture: pic}); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	_, _, moovBody, err := readMoov(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	atoms, _ := parseAtoms(moovBody)
+	for _, typ := range []string{"udta", "meta", "ilst"} {
+		idx := findAtom(atoms, typ)
+		if idx < 0 {
+			t.Fatalf("missing %s atom", typ)
+		}
+		body := atoms[idx].body
+		if typ == "meta" {
+			body = body[4:]
+		}
+		atoms, _ = parseAtoms(body)
+	}
+	idx := findAtom(atoms, "covr")
+	if idx < 0 {
+		t.Fatal("missing covr item")
+	}
+	data, _ := parseAtoms(atoms[idx].body)
+	if len(data) != 1 || binary.BigEndian.Uint32(data[0].body[:4]) != mp4DataTypePNG || !bytes.Equal(data[0].body[8:], pic.Data) {
+		t.Fatalf("covr item = %+v, want PNG data atom", data)
+	}
+
+	raw, _ := os.ReadFile(path)
+	if got := chunkOffset(t, path); !bytes.Equal(raw[got:int(got)+len(payload)], payload) {
+		t.Fatalf("chunk offset %d no longer points at sample data", got)
+	}
+}
@@ -0,0 +1,166 @@
+package tagging
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// ErrUnsupportedFormat is returned when a file extension has no tag writer.
+var ErrUnsupportedFormat = errors.New("unsupported audio format for tagging")
+
+// Canonical Vorbis comment field names. MP4 atoms are mapped onto the same
+// names by ReadFile so callers can compare tags independent of container.
+const (
+	FieldArtist      = "ARTIST"
+	FieldAlbumArtist = "ALBUMARTIST"
+	FieldAlbum       = "ALBUM"
+	FieldTitle       = "TITLE"
+	FieldDate        = "DATE"
+	FieldTrackNumber = "TRACKNUMBER"
+	FieldTrackTotal  = "TRACKTOTAL"
+	FieldDiscNumber  = "DISCNUMBER"
+	FieldDiscTotal   = "DISCTOTAL"
+	FieldVenue       = "VENUE"
+	FieldLocation    = "LOCATION"
+	FieldContainerID = "NUGS_CONTAINER_ID"
+	FieldTrackID     = "NUGS_TRACK_ID"
+)
+
+// Tags holds the metadata written into a single audio file.
+// Zero values are omitted from the output.
+type Tags struct {
+	Artist      string
+	AlbumArtist string
+	Album       string
+	Title       string
+	Date        string // YYYY-MM-DD when known, otherwise the raw API value.
+	Venue       string
+	City        string
+	State       string
+	TrackNumber int
+	TrackTotal  int
+	DiscNumber  int
+	DiscTotal   int
+	ContainerID int
+	TrackID     int
+	Picture     *Picture // Front cover; nil leaves existing artwork untouched.
+}
+
+// Picture is an embedded cover image.
+type Picture struct {
+	MIMEType string // image/jpeg or image/png
+	Width    int
+	Height   int
+	Data     []byte
+}
+
+// field is one ordered key/value pair produced from Tags.
+type field struct {
+	key   string
+	value string
+}
+
+// Location joins city and state into a single display value.
+func (t Tags) Location() string {
+	parts := make([]string, 0, 2)
+	for _, part := range []string{t.City, t.State} {
+		if trimmed := strings.TrimSpace(part); trimmed != "" {
+			parts = append(parts, trimmed)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// fields flattens Tags into Vorbis-style key/value pairs, skipping empty values.
+func (t Tags) fields() []field {
+	var out []field
+	addStr := func(key, value string) {
+		if value = strings.TrimSpace(value); value != "" {
+			out = append(out, field{key: key, value: value})
+		}
+	}
+	addInt := func(key string, value int) {
+		if value > 0 {
+			out = append(out, field{key: key, value: strconv.Itoa(value)})
+		}
+	}
+	addStr(FieldArtist, t.Artist)
+	addStr(FieldAlbumArtist, t.AlbumArtist)
+	addStr(FieldAlbum, t.Album)
+	addStr(FieldTitle, t.Title)
+	addStr(FieldDate, t.Date)
+	addInt(FieldTrackNumber, t.TrackNumber)
+	addInt(FieldTrackTotal, t.TrackTotal)
+	addInt(FieldDiscNumber, t.DiscNumber)
+	addInt(FieldDiscTotal, t.DiscTotal)
+	addStr(FieldVenue, t.Venue)
+	addStr(FieldLocation, t.Location())
+	addInt(FieldContainerID, t.ContainerID)
+	addInt(FieldTrackID, t.TrackID)
+	return out
+}
+
+// WriteFile embeds tags into the audio file at path, choosing the container
+// format from the file extension. Existing tags with other keys are preserved.
+func WriteFile(path string, tags Tags) error {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".flac":
+		return writeFLAC(path, tags)
+	case ".m4a", ".mp4", ".aac", ".alac":
+		return writeMP4(path, tags)
+	default:
+		return fmt.Errorf("%w: %s", ErrUnsupportedFormat, filepath.Ext(path))
+	}
+}
+
+// ReadFile returns the embedded tags of the audio file at path keyed by the
+// canonical Vorbis field names.
+func ReadFile(path string) (map[string]string, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".flac":
+		return readFLAC(path)
+	case ".m4a", ".mp4", ".aac", ".alac":
+		return readMP4(path)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedFormat, filepath.Ext(path))
+	}
+}
+
+// replaceFile atomically swaps path with the output of write. The temp file
+// lives in the same directory so the final rename never crosses filesystems.
+func replaceFile(path string, write func(f *os.File) error) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tagtmp")
+	if err != nil {
+		return fmt.Errorf("create temp file: %w", err)
+	}
+	tmpPath := tmp.Name()
+	committed := false
+	defer func() {
+		_ = tmp.Close()
+		if !committed {
+			_ = os.Remove(tmpPath)
+		}
+	}()
+	if err := write(tmp); err != nil {
+		return err
+	}
+	if err := tmp.Chmod(info.Mode().Perm()); err != nil {
+		return fmt.Errorf("chmod temp file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("close temp file: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("rename temp file: %w", err)
+	}
+	committed = true
+	return nil
+}
@@ -0,0 +1,171 @@
+package testutil
+
+import (
+	"crypto/md5"
+	"math"
+)
+
+// flacBitWriter packs big-endian bit fields for the test encoder.
+type flacBitWriter struct {
+	buf []byte
+	n   uint // bits used in the last byte
+}
+
+func (w *flacBitWriter) write(v uint64, n uint) {
+	for i := int(n) - 1; i >= 0; i-- {
+		if w.n == 0 {
+			w.buf = append(w.buf, 0)
+		}
+		w.buf[len(w.buf)-1] |= byte(v>>uint(i)&1) << (7 - w.n)
+		w.n = (w.n + 1) % 8
+	}
+}
+
+func (w *flacBitWriter) signed(v int64, n uint) { w.write(uint64(v)&(1<<n-1), n) }
+
+func (w *flacBitWriter) rice(v int64, param uint) {
+	u := uint64(v<<1) ^ uint64(v>>63)
+	for q := u >> param; q > 0; q-- {
+		w.write(0, 1)
+	}
+	w.write(1, 1)
+	w.write(u, param)
+}
+
+func (w *flacBitWriter) align() { w.n = 0 }
+
+// writeFLACSubframe encodes s as a verbatim, fixed order-2, or LPC order-2 subframe.
+func writeFLACSubframe(w *flacBitWriter, s []int64, bps uint, kind int) {
+	switch kind {
+	case 0:
+		w.write(1<<1, 8) // verbatim
+		for _, v := range s {
+			w.signed(v, bps)
+		}
+		return
+	case 1:
+		w.write((8+2)<<1, 8) // fixed, order 2
+	default:
+		w.write((31+2)<<1, 8) // LPC, order 2
+	}
+	w.signed(s[0], bps)
+	w.signed(s[1], bps)
+	if kind == 2 {
+		w.write(3, 4)  // precision 4 bits
+		w.signed(0, 5) // shift
+		w.signed(2, 4) // coefficients equivalent to the fixed order-2 predictor
+		w.signed(-1, 4)
+	}
+	w.write(0, 2) // Rice, 4-bit parameters
+	w.write(0, 4) // one partition
+	w.write(6, 4)
+	for i := 2; i < len(s); i++ {
+		w.rice(s[i]-(2*s[i-1]-s[i-2]), 6)
+	}
+}
+
+func flacCRC8(data []byte) byte {
+	var crc byte
+	for _, c := range data {
+		crc ^= c
+		for range 8 {
+			if crc&0x80 != 0 {
+				crc = crc<<1 ^ 0x07
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
+
+func flacCRC16(data []byte) uint16 {
+	var crc uint16
+	for _, c := range data {
+		crc ^= uint16(c) << 8
+		for range 8 {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x8005
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
+
+// EncodeFLAC encodes 16-bit 44.1 kHz stereo audio as a FLAC stream, rotating
+// the stereo mode and subframe types across frames so every decoder path is
+// exercised.
+func EncodeFLAC(left, right []int64, blockSize int) []byte {
+	const bps = 16
+	total := len(left)
+	sum := md5.New()
+	for i := range total {
+		sum.Write([]byte{byte(left[i]), byte(left[i] >> 8), byte(right[i]), byte(right[i] >> 8)})
+	}
+
+	info := make([]byte, 34)
+	info[0], info[1] = byte(blockSize>>8), byte(blockSize)
+	info[2], info[3] = byte(blockSize>>8), byte(blockSize)
+	rate := 44100
+	info[10], info[11] = byte(rate>>12), byte(rate>>4)
+	info[12] = byte(rate&0x0f)<<4 | 1<<1 | (bps-1)>>4
+	info[13] = byte((bps-1)&0x0f) << 4
+	info[14], info[15], info[16], info[17] = byte(total>>24), byte(total>>16), byte(total>>8), byte(total)
+	copy(info[18:], sum.Sum(nil))
+
+	out := []byte("fLaC")
+	out = append(out, 0x80, 0, 0, byte(len(info))) // last block, STREAMINFO
+	out = append(out, info...)
+
+	for frame, start := 0, 0; start < total; frame, start = frame+1, start+blockSize {
+		end := min(start+blockSize, total)
+		l, r := left[start:end], right[start:end]
+		n := end - start
+		channelCode := []uint64{1, 8, 9, 10}[frame%4]
+		a, b := make([]int64, n), make([]int64, n)
+		var aBPS, bBPS uint = bps, bps
+		for i := range n {
+			switch channelCode {
+			case 1:
+				a[i], b[i] = l[i], r[i]
+			case 8:
+				a[i], b[i], bBPS = l[i], l[i]-r[i], bps+1
+			case 9:
+				a[i], b[i], aBPS = l[i]-r[i], r[i], bps+1
+			case 10:
+				a[i], b[i], bBPS = (l[i]+r[i])>>1, l[i]-r[i], bps+1
+			}
+		}
+
+		w := &flacBitWriter{}
+		w.write(0x3ffe, 14) // frame sync
+		w.write(0, 2)
+		w.write(7, 4) // 16-bit block size at end of header
+		w.write(0, 4) // sample rate from STREAMINFO
+		w.write(channelCode, 4)
+		w.write(4, 3) // 16 bits per sample
+		w.write(0, 1)
+		w.write(uint64(frame), 8)
+		w.write(uint64(n-1), 16)
+		w.write(uint64(flacCRC8(w.buf)), 8)
+		writeFLACSubframe(w, a, aBPS, frame%3)
+		writeFLACSubframe(w, b, bBPS, (frame+1)%3)
+		w.align()
+		w.write(uint64(flacCRC16(w.buf)), 16)
+		out = append(out, w.buf...)
+	}
+	return out
+}
+
+// ToneSamples returns n samples of a stereo test tone; phase offsets the
+// waveform so consecutive calls can stand in for different tracks.
+func ToneSamples(n, phase int) (left, right []int64) {
+	left, right = make([]int64, n), make([]int64, n)
+	for i := range n {
+		left[i] = int64(12000 * math.Sin(float64(i+phase)/20))
+		right[i] = left[i]/2 + int64((i+phase)%13) - 6
+	}
+	return left, right
+}
@@ -0,0 +1,3 @@
+// Package transcode makes the portable copies described by transcode
+// profiles, running FFmpeg after downloads and for `nugs transcode`.
+package transcode
@@ -0,0 +1,328 @@
+package transcode
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/jmagar/nugs-cli/internal/helpers"
+	"github.com/jmagar/nugs-cli/internal/model"
+	"github.com/jmagar/nugs-cli/internal/tagging"
+)
+
+// codec describes how FFmpeg encodes one profile codec.
+type codec struct {
+	ext     string
+	encoder string
+	bitrate string // default for lossy codecs; empty means lossless
+	rates   []int  // sample rates the encoder accepts; nil accepts any
+	cover   bool   // the container can carry embedded cover art
+}
+
+var codecs = map[string]codec{
+	"aac":  {ext: ".m4a", encoder: "aac", bitrate: "256k", cover: true},
+	"opus": {ext: ".opus", encoder: "libopus", bitrate: "160k", rates: []int{8000, 12000, 16000, 24000, 48000}},
+	"mp3":  {ext: ".mp3", encoder: "libmp3lame", bitrate: "320k", cover: true},
+	"flac": {ext: ".flac", encoder: "flac", cover: true},
+	"alac": {ext: ".m4a", encoder: "alac", cover: true},
+}
+
+var bitratePattern = regexp.MustCompile(`^[1-9][0-9]*k$`)
+
+// Validate normalises and checks transcode profiles: names must be unique,
+// codecs known, bitrates only set for lossy codecs, and each profile needs an
+// output folder.
+func Validate(profiles []model.TranscodeProfile) error {
+	seen := make(map[string]bool, len(profiles))
+	for i := range profiles {
+		p := &profiles[i]
+		p.Name = strings.TrimSpace(p.Name)
+		p.Codec = strings.ToLower(strings.TrimSpace(p.Codec))
+		p.Bitrate = strings.ToLower(strings.TrimSpace(p.Bitrate))
+		p.OutPath = strings.TrimSpace(p.OutPath)
+		p.FolderTemplate = strings.TrimSpace(p.FolderTemplate)
+		p.FileTemplate = strings.TrimSpace(p.FileTemplate)
+		if p.Name == "" {
+			return fmt.Errorf("transcodeProfiles[%d]: name is required", i)
+		}
+		if seen[p.Name] {
+			return fmt.Errorf("transcodeProfiles: duplicate name %q", p.Name)
+		}
+		seen[p.Name] = true
+		c, ok := codecs[p.Codec]
+		if !ok {
+			return fmt.Errorf("transcode profile %q: invalid codec %q (must be aac, opus, mp3, flac or alac)", p.Name, p.Codec)
+		}
+		if p.Bitrate != "" {
+			if c.bitrate == "" {
+				return fmt.Errorf("transcode profile %q: %s is lossless and takes no bitrate", p.Name, p.Codec)
+			}
+			if !bitratePattern.MatchString(p.Bitrate) {
+				return fmt.Errorf("transcode profile %q: invalid bitrate %q (use kilobits, e.g. 256k)", p.Name, p.Bitrate)
+			}
+		}
+		if p.SampleRate != 0 {
+			if p.SampleRate < 8000 || p.SampleRate > 384000 {
+				return fmt.Errorf("transcode profile %q: sampleRate %d is out of range", p.Name, p.SampleRate)
+			}
+			if c.rates != nil && !slices.Contains(c.rates, p.SampleRate) {
+				return fmt.Errorf("transcode profile %q: %s does not support sampleRate %d", p.Name, p.Codec, p.SampleRate)
+			}
+		}
+		if p.OutPath == "" {
+			return fmt.Errorf("transcode profile %q: outPath is required", p.Name)
+		}
+		if err := helpers.ValidateNamingTemplates(p.FolderTemplate, p.FileTemplate); err != nil {
+			return fmt.Errorf("transcode profile %q: %w", p.Name, err)
+		}
+	}
+	return nil
+}
+
+// Select returns the profiles named in names, or every profile when names is
+// empty.
+func Select(profiles []model.TranscodeProfile, names []string) ([]model.TranscodeProfile, error) {
+	if len(names) == 0 {
+		return profiles, nil
+	}
+	selected := make([]model.TranscodeProfile, 0, len(names))
+	for _, name := range names {
+		i := slices.IndexFunc(profiles, func(p model.TranscodeProfile) bool { return strings.EqualFold(p.Name, name) })
+		if i < 0 {
+			return nil, fmt.Errorf("no transcode profile named %q", name)
+		}
+		selected = append(selected, profiles[i])
+	}
+	return selected, nil
+}
+
+// Job converts one source file for one profile.
+type Job struct {
+	Profile model.TranscodeProfile
+	Source  string
+	Dest    string
+}
+
+// Jobs plans the copies of files, which sit in sourceDir, for each profile.
+// A profile with a folder template places them by show; otherwise, or when
+// show is nil, sourceDir's path below baseDir is mirrored under the profile's
+// outPath. A profile with a file template names each copy from the track
+// tags embedded in its source; otherwise, for untagged sources, or when two
+// copies would collide, copies keep the source file names. Either way they
+// take the codec's extension.
+func Jobs(profiles []model.TranscodeProfile, baseDir, sourceDir string, files []string, show *model.AlbArtResp) []Job {
+	var (
+		jobs   []Job
+		tagged map[string]taggedTrack
+	)
+	for _, profile := range profiles {
+		c, ok := codecs[profile.Codec]
+		if !ok {
+			continue
+		}
+		if profile.FileTemplate != "" && tagged == nil {
+			tagged = readTrackTags(files, show)
+		}
+		dir := destDir(profile, baseDir, sourceDir, show)
+		used := make(map[string]bool, len(files))
+		for _, file := range files {
+			name := filepath.Base(file)
+			stem := strings.TrimSuffix(name, filepath.Ext(name))
+			if track, ok := tagged[file]; ok && profile.FileTemplate != "" {
+				cfg := &model.Config{TrackTemplate: profile.FileTemplate}
+				if templated := helpers.TrackFileName(cfg, track.show, track.info); !used[templated] {
+					stem = templated
+				}
+			}
+			used[stem] = true
+			dest := filepath.Join(dir, stem+c.ext)
+			if filepath.Clean(dest) == filepath.Clean(file) {
+				continue
+			}
+			jobs = append(jobs, Job{Profile: profile, Source: file, Dest: dest})
+		}
+	}
+	return jobs
+}
+
+// taggedTrack is what a file template needs from a source's tags.
+type taggedTrack struct {
+	show *model.AlbArtResp
+	info helpers.TrackNameInfo
+}
+
+// readTrackTags reads the track tags of files, skipping those without a
+// title. show supplies the show values when given; otherwise they also come
+// from each file's tags.
+func readTrackTags(files []string, show *model.AlbArtResp) map[string]taggedTrack {
+	tracks := make(map[string]taggedTrack, len(files))
+	for _, file := range files {
+		tags, err := tagging.ReadFile(file)
+		if err != nil || tags[tagging.FieldTitle] == "" {
+			continue
+		}
+		number := func(key string) int {
+			n, _ := strconv.Atoi(tags[key])
+			return n
+		}
+		track := taggedTrack{show: show, info: helpers.TrackNameInfo{
+			Number: number(tagging.FieldTrackNumber),
+			Total:  number(tagging.FieldTrackTotal),
+			Disc:   number(tagging.FieldDiscNumber),
+			Title:  tags[tagging.FieldTitle],
+			ID:     number(tagging.FieldTrackID),
+		}}
+		if track.show == nil {
+			track.show = showFromTagMap(tags)
+		}
+		tracks[file] = track
+	}
+	return tracks
+}
+
+func destDir(profile model.TranscodeProfile, baseDir, sourceDir string, show *model.AlbArtResp) string {
+	if profile.FolderTemplate != "" && show != nil {
+		segments := helpers.ShowPathSegments(&model.Config{FolderTemplate: profile.FolderTemplate}, show)
+		return filepath.Join(append([]string{profile.OutPath}, segments...)...)
+	}
+	rel, err := filepath.Rel(baseDir, sourceDir)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		rel = filepath.Base(sourceDir)
+	}
+	return filepath.Join(profile.OutPath, rel)
+}
+
+// Args builds the FFmpeg arguments that convert src to dst for profile.
+// Tags and, where the container allows, cover art are carried across.
+func Args(profile model.TranscodeProfile, src, dst string) []string {
+	c := codecs[profile.Codec]
+	args := []string{"-hide_banner", "-nostdin", "-loglevel", "error", "-y", "-i", src,
+		"-map", "0:a", "-map_metadata", "0"}
+	if c.cover {
+		args = append(args, "-map", "0:v?", "-c:v", "copy", "-disposition:v", "attached_pic")
+	}
+	args = append(args, "-c:a", c.encoder)
+	if c.bitrate != "" {
+		bitrate := profile.Bitrate
+		if bitrate == "" {
+			bitrate = c.bitrate
+		}
+		args = append(args, "-b:a", bitrate)
+	}
+	if profile.SampleRate > 0 {
+		args = append(args, "-ar", strconv.Itoa(profile.SampleRate))
+	}
+	return append(args, dst)
+}
+
+// Result is the outcome of one job. Skipped copies were already up to date.
+type Result struct {
+	Job
+	Skipped bool
+	Err     error
+}
+
+// Run converts jobs with up to concurrency FFmpeg processes at once and
+// returns their results in job order. Copies newer than their source are
+// skipped, so re-runs only fill gaps. progress, when set, is called after
+// each job, one call at a time.
+func Run(ctx context.Context, ffmpegNameStr string, jobs []Job, concurrency int, progress func(Result)) []Result {
+	results := make([]Result, len(jobs))
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	next := make(chan int)
+	for range min(max(concurrency, 1), len(jobs)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				result := Result{Job: jobs[i]}
+				if err := ctx.Err(); err != nil {
+					result.Err = err
+				} else {
+					result.Skipped, result.Err = convert(ctx, ffmpegNameStr, jobs[i])
+				}
+				results[i] = result
+				if progress != nil {
+					mu.Lock()
+					progress(result)
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+	for i := range jobs {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+	return results
+}
+
+// convert runs one job through a temporary file so an interrupted run never
+// leaves a partial copy that later looks up to date.
+func convert(ctx context.Context, ffmpegNameStr string, job Job) (bool, error) {
+	src, err := os.Stat(job.Source)
+	if err != nil {
+		return false, err
+	}
+	if dst, err := os.Stat(job.Dest); err == nil && !dst.ModTime().Before(src.ModTime()) {
+		return true, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(job.Dest), 0755); err != nil {
+		return false, err
+	}
+	ext := filepath.Ext(job.Dest)
+	tmpPath := strings.TrimSuffix(job.Dest, ext) + ".part" + ext
+	defer os.Remove(tmpPath)
+
+	var errBuffer bytes.Buffer
+	cmd := exec.CommandContext(ctx, ffmpegNameStr, Args(job.Profile, job.Source, tmpPath)...)
+	cmd.Stderr = &errBuffer
+	if err := cmd.Run(); err != nil {
+		return false, fmt.Errorf("ffmpeg %s: %w: %s", job.Profile.Name, err, strings.TrimSpace(errBuffer.String()))
+	}
+	if err := os.Rename(tmpPath, job.Dest); err != nil {
+		return false, err
+	}
+	return false, nil
+}
+
+// ShowFromTags rebuilds the show values a folder template needs from the
+// tags embedded in a downloaded track. It returns nil when the file has no
+// artist tag.
+func ShowFromTags(path string) *model.AlbArtResp {
+	tags, err := tagging.ReadFile(path)
+	if err != nil || tags[tagging.FieldArtist] == "" {
+		return nil
+	}
+	return showFromTagMap(tags)
+}
+
+// showFromTagMap builds the show values from a file's tags.
+func showFromTagMap(tags map[string]string) *model.AlbArtResp {
+	show := &model.AlbArtResp{
+		ArtistName:      tags[tagging.FieldArtist],
+		ContainerInfo:   tags[tagging.FieldAlbum],
+		PerformanceDate: tags[tagging.FieldDate],
+		VenueName:       tags[tagging.FieldVenue],
+	}
+	location := tags[tagging.FieldLocation]
+	if i := strings.LastIndex(location, ", "); i >= 0 {
+		show.VenueCity, show.VenueState = location[:i], location[i+2:]
+	} else {
+		show.VenueCity = location
+	}
+	show.ContainerID, _ = strconv.Atoi(tags[tagging.FieldContainerID])
+	return show
+}
@@ -0,0 +1,240 @@
+package transcode
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jmagar/nugs-cli/internal/model"
+	"github.com/jmagar/nugs-cli/internal/tagging"
+	"github.com/jmagar/nugs-cli/internal/testutil"
+)
+
+// fakeFfmpeg copies the -i input to the last argument and counts its runs.
+const fakeFfmpeg = `#!/bin/sh
+echo run >> "$(dirname "$0")/runs"
+while [ $# -gt 1 ]; do
+  if [ "$1" = "-i" ]; then in="$2"; fi
+  shift
+done
+cp "$in" "$1"
+`
+
+func writeFakeFfmpeg(t *testing.T) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("test uses POSIX shell script, not portable to Windows")
+	}
+	path := filepath.Join(t.TempDir(), "ffmpeg")
+	if err := os.WriteFile(path, []byte(fakeFfmpeg), 0o755); err != nil {
+		t.Fatalf("write mock ffmpeg: %v", err)
+	}
+	return path
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		profile model.TranscodeProfile
+		want    string
+	}{
+		{name: "missing name", profile: model.TranscodeProfile{Codec: "aac", OutPath: "/x"}, want: "name is required"},
+		{name: "unknown codec", profile: model.TranscodeProfile{Name: "p", Codec: "wma", OutPath: "/x"}, want: "invalid codec"},
+		{name: "lossless bitrate", profile: model.TranscodeProfile{Name: "p", Codec: "flac", Bitrate: "256k", OutPath: "/x"}, want: "lossless"},
+		{name: "bad bitrate", profile: model.TranscodeProfile{Name: "p", Codec: "mp3", Bitrate: "256", OutPath: "/x"}, want: "invalid bitrate"},
+		{name: "opus rate", profile: model.TranscodeProfile{Name: "p", Codec: "opus", SampleRate: 44100, OutPath: "/x"}, want: "does not support sampleRate"},
+		{name: "rate range", profile: model.TranscodeProfile{Name: "p", Codec: "aac", SampleRate: 100, OutPath: "/x"}, want: "out of range"},
+		{name: "missing outPath", profile: model.TranscodeProfile{Name: "p", Codec: "aac"}, want: "outPath is required"},
+		{name: "bad template", profile: model.TranscodeProfile{Name: "p", Codec: "aac", OutPath: "/x", FolderTemplate: "{nope}"}, want: "nope"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := Validate([]model.TranscodeProfile{tc.profile})
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Fatalf("Validate() error = %v, want %q", err, tc.want)
+			}
+		})
+	}
+
+	profiles := []model.TranscodeProfile{
+		{Name: " phone ", Codec: " AAC ", Bitrate: "192K", OutPath: " /phone "},
+		{Name: "phone", Codec: "mp3", OutPath: "/mp3"},
+	}
+	if err := Validate(profiles); err == nil || !strings.Contains(err.Error(), "duplicate name") {
+		t.Fatalf("Validate(duplicates) error = %v", err)
+	}
+	want := model.TranscodeProfile{Name: "phone", Codec: "aac", Bitrate: "192k", OutPath: "/phone"}
+	if profiles[0] != want {
+		t.Fatalf("normalised profile = %+v, want %+v", profiles[0], want)
+	}
+}
+
+func TestSelect(t *testing.T) {
+	profiles := []model.TranscodeProfile{{Name: "phone"}, {Name: "car"}}
+	got, err := Select(profiles, []string{"CAR"})
+	if err != nil || len(got) != 1 || got[0].Name != "car" {
+		t.Fatalf("Select(CAR) = %+v, %v", got, err)
+	}
+	if _, err := Select(profiles, []string{"tablet"}); err == nil {
+		t.Fatal("Select(tablet) succeeded, want error")
+	}
+}
+
+func TestJobs(t *testing.T) {
+	base := filepath.Join("music", "nugs")
+	source := filepath.Join(base, "Goose", "Goose - Live")
+	files := []string{filepath.Join(source, "01. Opener.flac"), filepath.Join(source, "02. Jam.flac")}
+	show := &model.AlbArtResp{ArtistName: "Goose", ContainerInfo: "Live", PerformanceDate: "2024-06-22", VenueName: "Forest Hills"}
+	profiles := []model.TranscodeProfile{
+		{Name: "phone", Codec: "aac", OutPath: "phone"},
+		{Name: "archive", Codec: "flac", OutPath: "flat", FolderTemplate: "{date} {venue}"},
+	}
+
+	jobs := Jobs(profiles, base, source, files, show)
+	var got []string
+	for _, job := range jobs {
+		got = append(got, job.Dest)
+	}
+	want := []string{
+		filepath.Join("phone", "Goose", "Goose - Live", "01. Opener.m4a"),
+		filepath.Join("phone", "Goose", "Goose - Live", "02. Jam.m4a"),
+		filepath.Join("flat", "2024-06-22 Forest Hills", "01. Opener.flac"),
+		filepath.Join("flat", "2024-06-22 Forest Hills", "02. Jam.flac"),
+	}
+	if !slices.Equal(got, want) {
+		t.Fatalf("Jobs() dests = %q, want %q", got, want)
+	}
+
+	// Without show metadata a templated profile mirrors the download layout,
+	// and a copy that would overwrite its source is dropped.
+	same := []model.TranscodeProfile{{Name: "inplace", Codec: "flac", OutPath: base, FolderTemplate: "{date}"}}
+	if jobs := Jobs(same, base, source, files, nil); len(jobs) != 0 {
+		t.Fatalf("Jobs(in place) = %+v, want none", jobs)
+	}
+}
+
+func TestJobsFileTemplate(t *testing.T) {
+	base := t.TempDir()
+	source := filepath.Join(base, "Goose", "Goose - Live")
+	if err := os.MkdirAll(source, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	left, right := testutil.ToneSamples(1000, 0)
+	audio := testutil.EncodeFLAC(left, right, 512)
+	write := func(name string, tags *tagging.Tags) string {
+		path := filepath.Join(source, name)
+		if err := os.WriteFile(path, audio, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if tags != nil {
+			if err := tagging.WriteFile(path, *tags); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return path
+	}
+	files := []string{
+		write("01. Opener.flac", &tagging.Tags{Artist: "Goose", Title: "Opener", TrackNumber: 1, TrackTotal: 3, DiscNumber: 1, Date: "2024-06-22"}),
+		write("02. Jam.flac", &tagging.Tags{Artist: "Goose", Title: "Jam", TrackNumber: 2, TrackTotal: 3, DiscNumber: 2, Date: "2024-06-22"}),
+		write("03. Untagged.flac", nil),
+	}
+	profiles := []model.TranscodeProfile{
+		{Name: "phone", Codec: "aac", OutPath: "phone", FileTemplate: "{disc}-{track:02} {title} ({date})"},
+		{Name: "titles", Codec: "mp3", OutPath: "titles", FileTemplate: "{artist}"},
+	}
+
+	var got []string
+	for _, job := range Jobs(profiles, base, source, files, nil) {
+		got = append(got, job.Dest)
+	}
+	dir := filepath.Join("Goose", "Goose - Live")
+	want := []string{
+		filepath.Join("phone", dir, "1-01 Opener (2024-06-22).m4a"),
+		filepath.Join("phone", dir, "2-02 Jam (2024-06-22).m4a"),
+		filepath.Join("phone", dir, "03. Untagged.m4a"),
+		// Names that would collide fall back to the source name.
+		filepath.Join("titles", dir, "Goose.mp3"),
+		filepath.Join("titles", dir, "02. Jam.mp3"),
+		filepath.Join("titles", dir, "03. Untagged.mp3"),
+	}
+	if !slices.Equal(got, want) {
+		t.Fatalf("Jobs() dests = %q, want %q", got, want)
+	}
+
+	if err := Validate([]model.TranscodeProfile{{Name: "bad", Codec: "aac", OutPath: "x", FileTemplate: "{track}/{title}"}}); err == nil {
+		t.Fatal("Validate accepted a fileTemplate with '/'")
+	}
+}
+
+func TestArgs(t *testing.T) {
+	got := strings.Join(Args(model.TranscodeProfile{Codec: "opus", SampleRate: 48000}, "in.flac", "out.opus"), " ")
+	for _, want := range []string{"-i in.flac", "-c:a libopus", "-b:a 160k", "-ar 48000"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Args(opus) = %q, missing %q", got, want)
+		}
+	}
+	if strings.Contains(got, "attached_pic") {
+		t.Errorf("Args(opus) = %q, should not map cover art", got)
+	}
+	got = strings.Join(Args(model.TranscodeProfile{Codec: "alac"}, "in.flac", "out.m4a"), " ")
+	if strings.Contains(got, "-b:a") || !strings.Contains(got, "attached_pic") || !strings.HasSuffix(got, " out.m4a") {
+		t.Errorf("Args(alac) = %q", got)
+	}
+}
+
+func TestRunSkipsUpToDateCopies(t *testing.T) {
+	ffmpeg := writeFakeFfmpeg(t)
+	dir := t.TempDir()
+	profiles := []model.TranscodeProfile{{Name: "phone", Codec: "mp3", OutPath: filepath.Join(dir, "phone")}}
+	var files []string
+	for _, name := range []string{"01. A.flac", "02. B.flac", "03. C.flac"} {
+		path := filepath.Join(dir, "show", name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(name), 0644); err != nil {
+			t.Fatal(err)
+		}
+		files = append(files, path)
+	}
+	jobs := Jobs(profiles, dir, filepath.Join(dir, "show"), files, nil)
+
+	var seen int
+	results := Run(context.Background(), ffmpeg, jobs, 2, func(Result) { seen++ })
+	if seen != len(jobs) {
+		t.Fatalf("progress called %d times, want %d", seen, len(jobs))
+	}
+	for _, result := range results {
+		if result.Err != nil || result.Skipped {
+			t.Fatalf("first run result = %+v", result)
+		}
+		data, err := os.ReadFile(result.Dest)
+		if err != nil || string(data) != filepath.Base(result.Source) {
+			t.Fatalf("copy %s = %q, %v", result.Dest, data, err)
+		}
+	}
+
+	// Touch one source so only its copy is redone.
+	later := time.Now().Add(time.Hour)
+	if err := os.Chtimes(files[1], later, later); err != nil {
+		t.Fatal(err)
+	}
+	results = Run(context.Background(), ffmpeg, jobs, 2, nil)
+	for i, result := range results {
+		if result.Err != nil || result.Skipped != (i != 1) {
+			t.Fatalf("second run result %d = %+v", i, result)
+		}
+	}
+	runs, err := os.ReadFile(filepath.Join(filepath.Dir(ffmpeg), "runs"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(string(runs), "run"); n != 4 {
+		t.Fatalf("ffmpeg ran %d times, want 4", n)
+	}
+}
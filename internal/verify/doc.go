@@ -0,0 +1,3 @@
+// Package verify checks downloaded audio files for corruption and truncation
+// (FLAC frame CRCs and STREAMINFO MD5, MP4 atom structure) without external tools.
+package verify
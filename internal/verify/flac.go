@@ -0,0 +1,689 @@
+package verify
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/md5"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"math/bits"
+	"os"
+	"time"
+)
+
+const (
+	flacBlockStreamInfo = 0
+	flacStreamInfoLen   = 34
+	flacFrameSync       = 0x3ffe
+)
+
+var (
+	flacMagic = []byte("fLaC")
+
+	flacCRC8Table  = makeCRC8Table(0x07)
+	flacCRC16Table = makeCRC16Table(0x8005)
+)
+
+func makeCRC8Table(poly byte) (table [256]byte) {
+	for i := range table {
+		crc := byte(i)
+		for range 8 {
+			if crc&0x80 != 0 {
+				crc = crc<<1 ^ poly
+			} else {
+				crc <<= 1
+			}
+		}
+		table[i] = crc
+	}
+	return table
+}
+
+func makeCRC16Table(poly uint16) (table [256]uint16) {
+	for i := range table {
+		crc := uint16(i) << 8
+		for range 8 {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ poly
+			} else {
+				crc <<= 1
+			}
+		}
+		table[i] = crc
+	}
+	return table
+}
+
+// flacStreamInfo is the decoded STREAMINFO block.
+type flacStreamInfo struct {
+	sampleRate    int
+	channels      int
+	bitsPerSample int
+	totalSamples  uint64 // 0 when unknown
+	md5           [16]byte
+}
+
+func parseStreamInfo(b []byte) (flacStreamInfo, error) {
+	if len(b) < flacStreamInfoLen {
+		return flacStreamInfo{}, fmt.Errorf("%w: short STREAMINFO block", ErrCorrupt)
+	}
+	info := flacStreamInfo{
+		sampleRate:    int(b[10])<<12 | int(b[11])<<4 | int(b[12])>>4,
+		channels:      int(b[12]>>1&0x07) + 1,
+		bitsPerSample: int(b[12]&0x01)<<4 | int(b[13])>>4 + 1,
+		totalSamples:  uint64(b[13]&0x0f)<<32 | uint64(b[14])<<24 | uint64(b[15])<<16 | uint64(b[16])<<8 | uint64(b[17]),
+	}
+	copy(info.md5[:], b[18:34])
+	if info.bitsPerSample < 4 || info.bitsPerSample > 32 {
+		return flacStreamInfo{}, fmt.Errorf("%w: invalid STREAMINFO sample size %d", ErrCorrupt, info.bitsPerSample)
+	}
+	return info, nil
+}
+
+// readFLACHeader validates the metadata block chain and returns STREAMINFO.
+// On return r is positioned at the first audio frame.
+func readFLACHeader(r *bufio.Reader) (flacStreamInfo, error) {
+	truncated := func(what string) error { return fmt.Errorf("%w: file ends inside %s", ErrTruncated, what) }
+
+	head, err := r.Peek(10)
+	if len(head) >= 10 && string(head[:3]) == "ID3" {
+		size := int(head[6]&0x7f)<<21 | int(head[7]&0x7f)<<14 | int(head[8]&0x7f)<<7 | int(head[9]&0x7f)
+		if head[5]&0x10 != 0 {
+			size += 10
+		}
+		if _, err := r.Discard(10 + size); err != nil {
+			return flacStreamInfo{}, truncated("ID3v2 tag")
+		}
+	} else if err != nil && len(head) < 4 {
+		return flacStreamInfo{}, fmt.Errorf("%w: missing fLaC marker", ErrCorrupt)
+	}
+	magic := make([]byte, 4)
+	if _, err := io.ReadFull(r, magic); err != nil || !bytes.Equal(magic, flacMagic) {
+		return flacStreamInfo{}, fmt.Errorf("%w: missing fLaC marker", ErrCorrupt)
+	}
+
+	var info flacStreamInfo
+	for first := true; ; first = false {
+		header := make([]byte, 4)
+		if _, err := io.ReadFull(r, header); err != nil {
+			return flacStreamInfo{}, truncated("metadata")
+		}
+		last := header[0]&0x80 != 0
+		blockType := header[0] & 0x7f
+		length := int(header[1])<<16 | int(header[2])<<8 | int(header[3])
+		if first != (blockType == flacBlockStreamInfo) {
+			return flacStreamInfo{}, fmt.Errorf("%w: STREAMINFO must be the first metadata block", ErrCorrupt)
+		}
+		if blockType == flacBlockStreamInfo {
+			data := make([]byte, length)
+			if _, err := io.ReadFull(r, data); err != nil {
+				return flacStreamInfo{}, truncated("STREAMINFO")
+			}
+			if info, err = parseStreamInfo(data); err != nil {
+				return flacStreamInfo{}, err
+			}
+		} else if _, err := r.Discard(length); err != nil {
+			return flacStreamInfo{}, truncated("metadata")
+		}
+		if last {
+			return info, nil
+		}
+	}
+}
+
+// flacBitReader reads big-endian bit fields and keeps the running frame
+// CRC-8 and CRC-16 over every byte consumed. Bytes are fetched one at a time,
+// so once byte aligned no buffered bits belong to the next frame.
+type flacBitReader struct {
+	r     *bufio.Reader
+	cache uint64 // valid bits are left-aligned; the rest are zero
+	n     uint
+	crc8  byte
+	crc16 uint16
+}
+
+func (br *flacBitReader) fill() error {
+	b, err := br.r.ReadByte()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			err = io.ErrUnexpectedEOF
+		}
+		return err
+	}
+	br.crc8 = flacCRC8Table[br.crc8^b]
+	br.crc16 = br.crc16<<8 ^ flacCRC16Table[byte(br.crc16>>8)^b]
+	br.cache |= uint64(b) << (56 - br.n)
+	br.n += 8
+	return nil
+}
+
+// bits reads an n-bit unsigned field (n <= 48).
+func (br *flacBitReader) bits(n uint) (uint64, error) {
+	if n == 0 {
+		return 0, nil
+	}
+	for br.n < n {
+		if err := br.fill(); err != nil {
+			return 0, err
+		}
+	}
+	v := br.cache >> (64 - n)
+	br.cache <<= n
+	br.n -= n
+	return v, nil
+}
+
+// signed reads an n-bit two's complement field.
+func (br *flacBitReader) signed(n uint) (int64, error) {
+	v, err := br.bits(n)
+	if err != nil || n == 0 {
+		return 0, err
+	}
+	shift := 64 - n
+	return int64(v<<shift) >> shift, nil
+}
+
+// unary counts zero bits up to and including the next one bit.
+func (br *flacBitReader) unary() (uint64, error) {
+	var count uint64
+	for {
+		if br.n == 0 {
+			if err := br.fill(); err != nil {
+				return 0, err
+			}
+		}
+		zeros := uint(bits.LeadingZeros64(br.cache))
+		if zeros < br.n {
+			br.cache <<= zeros + 1
+			br.n -= zeros + 1
+			return count + uint64(zeros), nil
+		}
+		count += uint64(br.n)
+		br.cache, br.n = 0, 0
+	}
+}
+
+// align discards the padding bits up to the next byte boundary.
+func (br *flacBitReader) align() {
+	pad := br.n % 8
+	br.cache <<= pad
+	br.n -= pad
+}
+
+// flacDecoder decodes frames and feeds the reconstructed samples to an MD5
+// hash in the layout the FLAC encoder used for STREAMINFO.
+type flacDecoder struct {
+	br      *flacBitReader
+	info    flacStreamInfo
+	md5     hash.Hash
+	samples [][]int64
+	out     []byte
+}
+
+// frame decodes one frame and returns the number of samples per channel.
+func (d *flacDecoder) frame() (int, error) {
+	br := d.br
+	br.crc8, br.crc16 = 0, 0
+	sync, err := br.bits(14)
+	if err != nil {
+		return 0, err
+	}
+	if sync != flacFrameSync {
+		return 0, errors.New("lost frame sync")
+	}
+	if _, err := br.bits(2); err != nil { // reserved bit and blocking strategy
+		return 0, err
+	}
+	blockSizeCode, err := br.bits(4)
+	if err != nil {
+		return 0, err
+	}
+	sampleRateCode, err := br.bits(4)
+	if err != nil {
+		return 0, err
+	}
+	channelCode, err := br.bits(4)
+	if err != nil {
+		return 0, err
+	}
+	sampleSizeCode, err := br.bits(3)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := br.bits(1); err != nil {
+		return 0, err
+	}
+	// Frame or sample number, UTF-8 style coded; only its length matters here.
+	first, err := br.bits(8)
+	if err != nil {
+		return 0, err
+	}
+	extra := bits.LeadingZeros8(^byte(first))
+	if extra == 1 || extra > 7 {
+		return 0, errors.New("invalid frame number")
+	}
+	for range max(extra-1, 0) {
+		b, err := br.bits(8)
+		if err != nil {
+			return 0, err
+		}
+		if b&0xc0 != 0x80 {
+			return 0, errors.New("invalid frame number")
+		}
+	}
+
+	var blockSize int
+	switch {
+	case blockSizeCode == 0:
+		return 0, errors.New("reserved block size")
+	case blockSizeCode == 1:
+		blockSize = 192
+	case blockSizeCode <= 5:
+		blockSize = 576 << (blockSizeCode - 2)
+	case blockSizeCode == 6:
+		v, err := br.bits(8)
+		if err != nil {
+			return 0, err
+		}
+		blockSize = int(v) + 1
+	case blockSizeCode == 7:
+		v, err := br.bits(16)
+		if err != nil {
+			return 0, err
+		}
+		blockSize = int(v) + 1
+	default:
+		blockSize = 256 << (blockSizeCode - 8)
+	}
+	switch sampleRateCode {
+	case 12:
+		_, err = br.bits(8)
+	case 13, 14:
+		_, err = br.bits(16)
+	case 15:
+		err = errors.New("invalid sample rate")
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	bps := d.info.bitsPerSample
+	switch sampleSizeCode {
+	case 0:
+	case 1:
+		bps = 8
+	case 2:
+		bps = 12
+	case 4:
+		bps = 16
+	case 5:
+		bps = 20
+	case 6:
+		bps = 24
+	case 7:
+		bps = 32
+	default:
+		return 0, errors.New("reserved sample size")
+	}
+	if bps != d.info.bitsPerSample {
+		return 0, fmt.Errorf("frame sample size %d differs from STREAMINFO %d", bps, d.info.bitsPerSample)
+	}
+	channels := int(channelCode) + 1
+	if channelCode >= 8 {
+		if channelCode > 10 {
+			return 0, errors.New("reserved channel assignment")
+		}
+		channels = 2
+	}
+	if channels != d.info.channels {
+		return 0, fmt.Errorf("frame has %d channels, STREAMINFO %d", channels, d.info.channels)
+	}
+
+	wantCRC8 := br.crc8
+	gotCRC8, err := br.bits(8)
+	if err != nil {
+		return 0, err
+	}
+	if byte(gotCRC8) != wantCRC8 {
+		return 0, errors.New("frame header CRC mismatch")
+	}
+
+	for len(d.samples) < channels {
+		d.samples = append(d.samples, nil)
+	}
+	for ch := range channels {
+		if cap(d.samples[ch]) < blockSize {
+			d.samples[ch] = make([]int64, blockSize)
+		}
+		d.samples[ch] = d.samples[ch][:blockSize]
+		subBPS := uint(bps)
+		// The side channel carries one extra bit.
+		if (channelCode == 8 && ch == 1) || (channelCode == 9 && ch == 0) || (channelCode == 10 && ch == 1) {
+			subBPS++
+		}
+		if err := d.subframe(d.samples[ch], subBPS); err != nil {
+			return 0, err
+		}
+	}
+
+	br.align()
+	wantCRC16 := br.crc16
+	gotCRC16, err := br.bits(16)
+	if err != nil {
+		return 0, err
+	}
+	if uint16(gotCRC16) != wantCRC16 {
+		return 0, errors.New("frame CRC mismatch")
+	}
+
+	d.decorrelate(channelCode, blockSize)
+	d.hashSamples(channels, blockSize, bps)
+	return blockSize, nil
+}
+
+func (d *flacDecoder) subframe(samples []int64, bps uint) error {
+	br := d.br
+	header, err := br.bits(8)
+	if err != nil {
+		return err
+	}
+	if header&0x80 != 0 {
+		return errors.New("subframe padding bit set")
+	}
+	kind := header >> 1 & 0x3f
+	var wasted uint
+	if header&0x01 != 0 {
+		k, err := br.unary()
+		if err != nil {
+			return err
+		}
+		wasted = uint(k) + 1
+		if wasted >= bps {
+			return errors.New("invalid wasted bits")
+		}
+		bps -= wasted
+	}
+
+	switch {
+	case kind == 0:
+		v, err := br.signed(bps)
+		if err != nil {
+			return err
+		}
+		for i := range samples {
+			samples[i] = v
+		}
+	case kind == 1:
+		for i := range samples {
+			if samples[i], err = br.signed(bps); err != nil {
+				return err
+			}
+		}
+	case kind >= 8 && kind <= 12:
+		order := int(kind - 8)
+		if err := d.warmup(samples, order, bps); err != nil {
+			return err
+		}
+		if err := d.residual(samples, order); err != nil {
+			return err
+		}
+		fixedPredict(samples, order)
+	case kind >= 32:
+		order := int(kind - 31)
+		if err := d.warmup(samples, order, bps); err != nil {
+			return err
+		}
+		precision, err := br.bits(4)
+		if err != nil {
+			return err
+		}
+		if precision == 15 {
+			return errors.New("invalid LPC precision")
+		}
+		shift, err := br.signed(5)
+		if err != nil {
+			return err
+		}
+		if shift < 0 {
+			return errors.New("negative LPC shift")
+		}
+		coeffs := make([]int64, order)
+		for i := range coeffs {
+			if coeffs[i], err = br.signed(uint(precision) + 1); err != nil {
+				return err
+			}
+		}
+		if err := d.residual(samples, order); err != nil {
+			return err
+		}
+		lpcPredict(samples, coeffs, uint(shift))
+	default:
+		return fmt.Errorf("reserved subframe type %d", kind)
+	}
+
+	if wasted > 0 {
+		for i := range samples {
+			samples[i] <<= wasted
+		}
+	}
+	return nil
+}
+
+func (d *flacDecoder) warmup(samples []int64, order int, bps uint) error {
+	if order > len(samples) {
+		return errors.New("predictor order exceeds block size")
+	}
+	for i := range order {
+		v, err := d.br.signed(bps)
+		if err != nil {
+			return err
+		}
+		samples[i] = v
+	}
+	return nil
+}
+
+// residual decodes the Rice-coded residual into samples[order:].
+func (d *flacDecoder) residual(samples []int64, order int) error {
+	br := d.br
+	method, err := br.bits(2)
+	if err != nil {
+		return err
+	}
+	paramBits, escape := uint(4), uint64(15)
+	switch method {
+	case 0:
+	case 1:
+		paramBits, escape = 5, 31
+	default:
+		return errors.New("reserved residual coding method")
+	}
+	partitionOrder, err := br.bits(4)
+	if err != nil {
+		return err
+	}
+	partitions := 1 << partitionOrder
+	partitionSize := len(samples) >> partitionOrder
+	if partitionSize<<partitionOrder != len(samples) || partitionSize < order {
+		return errors.New("invalid residual partition order")
+	}
+
+	i := order
+	for p := range partitions {
+		n := partitionSize
+		if p == 0 {
+			n -= order
+		}
+		param, err := br.bits(paramBits)
+		if err != nil {
+			return err
+		}
+		if param == escape {
+			raw, err := br.bits(5)
+			if err != nil {
+				return err
+			}
+			for range n {
+				if samples[i], err = br.signed(uint(raw)); err != nil {
+					return err
+				}
+				i++
+			}
+			continue
+		}
+		for range n {
+			q, err := br.unary()
+			if err != nil {
+				return err
+			}
+			low, err := br.bits(uint(param))
+			if err != nil {
+				return err
+			}
+			v := q<<param | low
+			samples[i] = int64(v>>1) ^ -int64(v&1)
+			i++
+		}
+	}
+	return nil
+}
+
+func fixedPredict(s []int64, order int) {
+	switch order {
+	case 1:
+		for i := 1; i < len(s); i++ {
+			s[i] += s[i-1]
+		}
+	case 2:
+		for i := 2; i < len(s); i++ {
+			s[i] += 2*s[i-1] - s[i-2]
+		}
+	case 3:
+		for i := 3; i < len(s); i++ {
+			s[i] += 3*s[i-1] - 3*s[i-2] + s[i-3]
+		}
+	case 4:
+		for i := 4; i < len(s); i++ {
+			s[i] += 4*s[i-1] - 6*s[i-2] + 4*s[i-3] - s[i-4]
+		}
+	}
+}
+
+func lpcPredict(s []int64, coeffs []int64, shift uint) {
+	for i := len(coeffs); i < len(s); i++ {
+		var sum int64
+		for j, c := range coeffs {
+			sum += c * s[i-1-j]
+		}
+		s[i] += sum >> shift
+	}
+}
+
+// decorrelate undoes the stereo channel decorrelation of the frame.
+func (d *flacDecoder) decorrelate(channelCode uint64, n int) {
+	if channelCode < 8 {
+		return
+	}
+	a, b := d.samples[0][:n], d.samples[1][:n]
+	for i := range n {
+		switch channelCode {
+		case 8: // left, side
+			b[i] = a[i] - b[i]
+		case 9: // side, right
+			a[i] += b[i]
+		case 10: // mid, side
+			mid := a[i]<<1 | b[i]&1
+			a[i], b[i] = (mid+b[i])>>1, (mid-b[i])>>1
+		}
+	}
+}
+
+// hashSamples adds interleaved little-endian samples to the MD5, using the
+// byte width the reference encoder uses for the STREAMINFO signature.
+func (d *flacDecoder) hashSamples(channels, n, bps int) {
+	width := (bps + 7) / 8
+	size := channels * n * width
+	if cap(d.out) < size {
+		d.out = make([]byte, size)
+	}
+	out := d.out[:size]
+	pos := 0
+	for i := range n {
+		for ch := range channels {
+			v := d.samples[ch][i]
+			for b := range width {
+				out[pos] = byte(v >> (8 * b))
+				pos++
+			}
+		}
+	}
+	d.md5.Write(out)
+}
+
+// verifyFLAC decodes every frame, checking header and frame CRCs, that the
+// decoded sample count reaches STREAMINFO's total, and the audio MD5.
+func verifyFLAC(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	r := bufio.NewReaderSize(f, 1<<16)
+	info, err := readFLACHeader(r)
+	if err != nil {
+		return err
+	}
+
+	d := &flacDecoder{br: &flacBitReader{r: r}, info: info, md5: md5.New()}
+	var decoded uint64
+	for info.totalSamples == 0 || decoded < info.totalSamples {
+		if _, err := r.Peek(1); errors.Is(err, io.EOF) {
+			break
+		}
+		n, err := d.frame()
+		if err != nil {
+			if errors.Is(err, io.ErrUnexpectedEOF) {
+				return fmt.Errorf("%w: file ends inside the frame at sample %d", ErrTruncated, decoded)
+			}
+			return fmt.Errorf("%w: frame at sample %d: %v", ErrCorrupt, decoded, err)
+		}
+		decoded += uint64(n)
+	}
+
+	if info.totalSamples > 0 && decoded < info.totalSamples {
+		return fmt.Errorf("%w: decoded %d of %d samples", ErrTruncated, decoded, info.totalSamples)
+	}
+	// A stream-copied join keeps the first input's STREAMINFO, so the audio
+	// runs past the declared length. Trailing tags are fine; another frame
+	// is not.
+	next, _ := r.Peek(2)
+	moreFrames := len(next) == 2 && uint16(next[0])<<6|uint16(next[1])>>2 == flacFrameSync
+	if info.totalSamples > 0 && (decoded > info.totalSamples || moreFrames) {
+		return fmt.Errorf("%w: audio continues past the %d samples STREAMINFO declares", ErrCorrupt, info.totalSamples)
+	}
+	if info.md5 != ([16]byte{}) && !bytes.Equal(d.md5.Sum(nil), info.md5[:]) {
+		return fmt.Errorf("%w: audio MD5 does not match STREAMINFO", ErrCorrupt)
+	}
+	return nil
+}
+
+// flacDuration reads STREAMINFO and converts its sample count to a duration.
+func flacDuration(path string) (time.Duration, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	info, err := readFLACHeader(bufio.NewReader(f))
+	if err != nil {
+		return 0, err
+	}
+	if info.totalSamples == 0 || info.sampleRate == 0 {
+		return 0, fmt.Errorf("%w: FLAC file does not declare its length", ErrUnsupportedFormat)
+	}
+	seconds := info.totalSamples / uint64(info.sampleRate)
+	rest := info.totalSamples % uint64(info.sampleRate)
+	return time.Duration(seconds)*time.Second + time.Duration(rest*uint64(time.Second)/uint64(info.sampleRate)), nil
+}
@@ -0,0 +1,92 @@
+package verify
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jmagar/nugs-cli/internal/testutil"
+)
+
+func testFLAC(t *testing.T) []byte {
+	t.Helper()
+	left, right := testutil.ToneSamples(4000, 0)
+	return testutil.EncodeFLAC(left, right, 512)
+}
+
+func writeTestFile(t *testing.T, name string, data []byte) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestVerifyFLACAcceptsIntactStream(t *testing.T) {
+	path := writeTestFile(t, "01. Song.flac", testFLAC(t))
+	if err := File(path); err != nil {
+		t.Fatalf("File() = %v, want nil", err)
+	}
+}
+
+func TestVerifyFLACDetectsTruncation(t *testing.T) {
+	data := testFLAC(t)
+	for _, cut := range []int{len(data) / 2, len(data) - 1, flacStreamInfoLen} {
+		path := writeTestFile(t, "01. Song.flac", data[:cut])
+		if err := File(path); !errors.Is(err, ErrTruncated) {
+			t.Errorf("File() on %d of %d bytes = %v, want ErrTruncated", cut, len(data), err)
+		}
+	}
+}
+
+func TestVerifyFLACDetectsCorruption(t *testing.T) {
+	data := testFLAC(t)
+	flipped := append([]byte{}, data...)
+	flipped[len(flipped)*2/3] ^= 0x10
+	path := writeTestFile(t, "01. Song.flac", flipped)
+	if err := File(path); !errors.Is(err, ErrCorrupt) {
+		t.Errorf("File() with a flipped audio bit = %v, want ErrCorrupt", err)
+	}
+
+	badMD5 := append([]byte{}, data...)
+	badMD5[8+18] ^= 0xff // first STREAMINFO MD5 byte
+	path = writeTestFile(t, "02. Song.flac", badMD5)
+	if err := File(path); !errors.Is(err, ErrCorrupt) {
+		t.Errorf("File() with a wrong MD5 = %v, want ErrCorrupt", err)
+	}
+}
+
+func TestDurationReadsStreamInfo(t *testing.T) {
+	path := writeTestFile(t, "01. Song.flac", testFLAC(t))
+	got, err := Duration(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := 4000 * time.Second / 44100; got != want {
+		t.Fatalf("Duration() = %v, want %v", got, want)
+	}
+	if _, err := Duration(writeTestFile(t, "01. Song.m4a", nil)); !errors.Is(err, ErrUnsupportedFormat) {
+		t.Fatalf("Duration() on m4a = %v, want ErrUnsupportedFormat", err)
+	}
+}
+
+func TestVerifyFLACDetectsFramesPastDeclaredLength(t *testing.T) {
+	// Stream-copying two files keeps the first STREAMINFO and appends the
+	// second file's frames after it.
+	first := testFLAC(t)
+	left, right := testutil.ToneSamples(3000, 4000)
+	second := testutil.EncodeFLAC(left, right, 512)
+	joined := append(append([]byte{}, first...), second[8+flacStreamInfoLen:]...)
+	path := writeTestFile(t, "Set 1.flac", joined)
+	if err := File(path); !errors.Is(err, ErrCorrupt) {
+		t.Fatalf("File() on a stream-copied join = %v, want ErrCorrupt", err)
+	}
+
+	tagged := append(append([]byte{}, first...), []byte("TAG trailing id3v1")...)
+	if err := File(writeTestFile(t, "01. Song.flac", tagged)); err != nil {
+		t.Fatalf("File() with trailing non-frame bytes = %v, want nil", err)
+	}
+}
@@ -0,0 +1,283 @@
+package verify
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+)
+
+const mp4MaxMoovSize = 64 << 20
+
+// mp4Atom is one atom: its type, the offset of its body, and the body length.
+type mp4Atom struct {
+	typ    string
+	offset int64
+	size   int64
+}
+
+// mp4ContainerAtoms are the sample-table ancestors walked below moov.
+var mp4ContainerAtoms = map[string]bool{"trak": true, "mdia": true, "minf": true, "stbl": true}
+
+// readAtomHeader parses the atom header at b[0:], returning the type, header
+// length, and total size (0 means "to the end of the enclosing space").
+func readAtomHeader(b []byte) (typ string, headerLen, size int64, err error) {
+	if len(b) < 8 {
+		return "", 0, 0, io.ErrUnexpectedEOF
+	}
+	size = int64(binary.BigEndian.Uint32(b[:4]))
+	typ = string(b[4:8])
+	headerLen = 8
+	if size == 1 {
+		if len(b) < 16 {
+			return "", 0, 0, io.ErrUnexpectedEOF
+		}
+		large := binary.BigEndian.Uint64(b[8:16])
+		if large > math.MaxInt64 {
+			return "", 0, 0, fmt.Errorf("invalid size for %q atom", typ)
+		}
+		size, headerLen = int64(large), 16
+	}
+	if size != 0 && size < headerLen {
+		return "", 0, 0, fmt.Errorf("invalid size %d for %q atom", size, typ)
+	}
+	return typ, headerLen, size, nil
+}
+
+// scanMP4TopLevel lists the top-level atoms of f, which must start with ftyp.
+// An atom running past the end of the file means the download stopped early.
+func scanMP4TopLevel(f *os.File, fileSize int64) ([]mp4Atom, error) {
+	var atoms []mp4Atom
+	header := make([]byte, 16)
+	for offset := int64(0); offset < fileSize; {
+		n, err := f.ReadAt(header, offset)
+		if err != nil && !errors.Is(err, io.EOF) {
+			return nil, err
+		}
+		typ, headerLen, size, err := readAtomHeader(header[:n])
+		if errors.Is(err, io.ErrUnexpectedEOF) {
+			return nil, fmt.Errorf("%w: file ends inside an atom header at offset %d", ErrTruncated, offset)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v at offset %d", ErrCorrupt, err, offset)
+		}
+		if offset == 0 && typ != "ftyp" {
+			return nil, fmt.Errorf("%w: file does not start with an ftyp atom", ErrCorrupt)
+		}
+		if size == 0 {
+			size = fileSize - offset
+		}
+		if offset+size > fileSize {
+			return nil, fmt.Errorf("%w: %q atom at offset %d needs %d bytes, %d remain", ErrTruncated, typ, offset, size, fileSize-offset)
+		}
+		atoms = append(atoms, mp4Atom{typ: typ, offset: offset + headerLen, size: size - headerLen})
+		offset += size
+	}
+	return atoms, nil
+}
+
+// parseMP4Children splits an in-memory atom body into its children.
+func parseMP4Children(body []byte) (map[string][][]byte, error) {
+	children := make(map[string][][]byte)
+	for len(body) > 0 {
+		typ, headerLen, size, err := readAtomHeader(body)
+		if err != nil {
+			return nil, fmt.Errorf("%w: malformed atom inside moov: %v", ErrCorrupt, err)
+		}
+		if size == 0 {
+			size = int64(len(body))
+		}
+		if size > int64(len(body)) {
+			return nil, fmt.Errorf("%w: %q atom overruns its parent", ErrCorrupt, typ)
+		}
+		children[typ] = append(children[typ], body[headerLen:size])
+		body = body[size:]
+	}
+	return children, nil
+}
+
+// collectSampleTables walks moov down to every stbl atom.
+func collectSampleTables(body []byte, typ string, out *[][]byte) error {
+	children, err := parseMP4Children(body)
+	if err != nil {
+		return err
+	}
+	for childType, bodies := range children {
+		for _, child := range bodies {
+			if childType == "stbl" {
+				*out = append(*out, child)
+			} else if mp4ContainerAtoms[childType] {
+				if err := collectSampleTables(child, childType, out); err != nil {
+					return fmt.Errorf("%s: %w", typ, err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// fullAtomTable returns the entry count and entries of a full atom body
+// (version/flags, count, entries) after checking the entries fit.
+func fullAtomTable(name string, body []byte, skip, entrySize int) (int, []byte, error) {
+	if len(body) < 8+skip {
+		return 0, nil, fmt.Errorf("%w: short %s atom", ErrCorrupt, name)
+	}
+	count := int(binary.BigEndian.Uint32(body[4+skip : 8+skip]))
+	entries := body[8+skip:]
+	if len(entries)/entrySize < count {
+		return 0, nil, fmt.Errorf("%w: %s atom lists %d entries but holds %d", ErrCorrupt, name, count, len(entries)/entrySize)
+	}
+	return count, entries, nil
+}
+
+// checkSampleTable resolves every chunk of one track to a byte range and
+// checks that the sample data lies within the file.
+func checkSampleTable(stbl []byte, fileSize int64) error {
+	children, err := parseMP4Children(stbl)
+	if err != nil {
+		return err
+	}
+	if len(children["stsz"]) == 0 || len(children["stsc"]) == 0 {
+		return fmt.Errorf("%w: sample table is missing stsz or stsc", ErrCorrupt)
+	}
+
+	stsz := children["stsz"][0]
+	if len(stsz) < 12 {
+		return fmt.Errorf("%w: short stsz atom", ErrCorrupt)
+	}
+	fixedSize := int64(binary.BigEndian.Uint32(stsz[4:8]))
+	sampleCount := int(binary.BigEndian.Uint32(stsz[8:12]))
+	if fixedSize == 0 {
+		if _, _, err := fullAtomTable("stsz", stsz, 4, 4); err != nil {
+			return err
+		}
+	}
+	sampleSize := func(i int) int64 {
+		if fixedSize != 0 {
+			return fixedSize
+		}
+		return int64(binary.BigEndian.Uint32(stsz[12+4*i:]))
+	}
+
+	var chunkOffsets []int64
+	switch {
+	case len(children["stco"]) > 0:
+		count, entries, err := fullAtomTable("stco", children["stco"][0], 0, 4)
+		if err != nil {
+			return err
+		}
+		for i := range count {
+			chunkOffsets = append(chunkOffsets, int64(binary.BigEndian.Uint32(entries[4*i:])))
+		}
+	case len(children["co64"]) > 0:
+		count, entries, err := fullAtomTable("co64", children["co64"][0], 0, 8)
+		if err != nil {
+			return err
+		}
+		for i := range count {
+			offset := binary.BigEndian.Uint64(entries[8*i:])
+			if offset > math.MaxInt64 {
+				return fmt.Errorf("%w: invalid chunk offset", ErrCorrupt)
+			}
+			chunkOffsets = append(chunkOffsets, int64(offset))
+		}
+	default:
+		if sampleCount > 0 {
+			return fmt.Errorf("%w: sample table has no chunk offsets", ErrCorrupt)
+		}
+	}
+
+	stscCount, stsc, err := fullAtomTable("stsc", children["stsc"][0], 0, 12)
+	if err != nil {
+		return err
+	}
+	sample := 0
+	for e := range stscCount {
+		firstChunk := int(binary.BigEndian.Uint32(stsc[12*e:]))
+		perChunk := int(binary.BigEndian.Uint32(stsc[12*e+4:]))
+		lastChunk := len(chunkOffsets)
+		if e+1 < stscCount {
+			lastChunk = int(binary.BigEndian.Uint32(stsc[12*(e+1):])) - 1
+		}
+		if firstChunk < 1 || lastChunk > len(chunkOffsets) {
+			return fmt.Errorf("%w: stsc references chunks outside stco", ErrCorrupt)
+		}
+		for chunk := firstChunk; chunk <= lastChunk; chunk++ {
+			end := chunkOffsets[chunk-1]
+			for range perChunk {
+				if sample >= sampleCount {
+					return fmt.Errorf("%w: chunks hold more samples than stsz lists", ErrCorrupt)
+				}
+				end += sampleSize(sample)
+				sample++
+			}
+			if end > fileSize {
+				return fmt.Errorf("%w: sample data for chunk %d ends at byte %d of %d", ErrTruncated, chunk, end, fileSize)
+			}
+		}
+	}
+	if sample != sampleCount {
+		return fmt.Errorf("%w: chunks hold %d of %d samples", ErrCorrupt, sample, sampleCount)
+	}
+	return nil
+}
+
+// verifyMP4 checks that the top-level atoms tile the file, that moov parses,
+// and that every track's samples lie inside the file.
+func verifyMP4(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	atoms, err := scanMP4TopLevel(f, info.Size())
+	if err != nil {
+		return err
+	}
+
+	var moov *mp4Atom
+	hasMdat, fragmented := false, false
+	for i, atom := range atoms {
+		switch atom.typ {
+		case "moov":
+			moov = &atoms[i]
+		case "mdat":
+			hasMdat = true
+		case "moof":
+			fragmented = true
+		}
+	}
+	if moov == nil {
+		return fmt.Errorf("%w: no moov atom", ErrCorrupt)
+	}
+	if !hasMdat {
+		return fmt.Errorf("%w: no mdat atom", ErrCorrupt)
+	}
+	if moov.size > mp4MaxMoovSize {
+		return fmt.Errorf("%w: moov atom too large (%d bytes)", ErrCorrupt, moov.size)
+	}
+	body := make([]byte, moov.size)
+	if _, err := f.ReadAt(body, moov.offset); err != nil {
+		return fmt.Errorf("read moov: %w", err)
+	}
+
+	var tables [][]byte
+	if err := collectSampleTables(body, "moov", &tables); err != nil {
+		return err
+	}
+	if len(tables) == 0 && !fragmented {
+		return fmt.Errorf("%w: moov has no sample tables", ErrCorrupt)
+	}
+	for _, stbl := range tables {
+		if err := checkSampleTable(stbl, info.Size()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
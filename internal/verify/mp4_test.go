@@ -0,0 +1,75 @@
+package verify
+
+import (
+	"encoding/binary"
+	"errors"
+	"testing"
+)
+
+func testAtom(typ string, body []byte) []byte {
+	out := make([]byte, 8, 8+len(body))
+	binary.BigEndian.PutUint32(out, uint32(8+len(body)))
+	copy(out[4:], typ)
+	return append(out, body...)
+}
+
+func uint32s(values ...uint32) []byte {
+	out := make([]byte, 4*len(values))
+	for i, v := range values {
+		binary.BigEndian.PutUint32(out[4*i:], v)
+	}
+	return out
+}
+
+// testMP4 builds ftyp + moov + mdat with one track of four 10-byte samples
+// split across two chunks.
+func testMP4() []byte {
+	ftyp := testAtom("ftyp", []byte("M4A \x00\x00\x00\x00M4A mp42isom"))
+	payload := make([]byte, 40)
+	buildMoov := func(dataStart uint32) []byte {
+		stbl := testAtom("stbl", append(append(append(
+			testAtom("stsz", uint32s(0, 0, 4, 10, 10, 10, 10)),
+			testAtom("stsc", uint32s(0, 1, 1, 2, 1))...),
+			testAtom("stco", uint32s(0, 2, dataStart, dataStart+20))...),
+			testAtom("stsd", uint32s(0, 0))...))
+		trak := testAtom("trak", testAtom("mdia", testAtom("minf", stbl)))
+		return testAtom("moov", append(testAtom("mvhd", make([]byte, 100)), trak...))
+	}
+	moovLen := len(buildMoov(0))
+	out := append([]byte{}, ftyp...)
+	out = append(out, buildMoov(uint32(len(ftyp)+moovLen+8))...)
+	return append(out, testAtom("mdat", payload)...)
+}
+
+func TestVerifyMP4AcceptsIntactFile(t *testing.T) {
+	path := writeTestFile(t, "01. Song.m4a", testMP4())
+	if err := File(path); err != nil {
+		t.Fatalf("File() = %v, want nil", err)
+	}
+}
+
+func TestVerifyMP4DetectsTruncation(t *testing.T) {
+	data := testMP4()
+	path := writeTestFile(t, "01. Song.m4a", data[:len(data)-5])
+	if err := File(path); !errors.Is(err, ErrTruncated) {
+		t.Fatalf("File() = %v, want ErrTruncated", err)
+	}
+
+	// An mdat whose size reaches to EOF hides truncation from the atom scan,
+	// so the sample table has to catch it.
+	open := append([]byte{}, data[:len(data)-5]...)
+	mdatAt := len(open) - (48 - 5)
+	binary.BigEndian.PutUint32(open[mdatAt:], 0)
+	path = writeTestFile(t, "02. Song.m4a", open)
+	if err := File(path); !errors.Is(err, ErrTruncated) {
+		t.Fatalf("File() with open-ended mdat = %v, want ErrTruncated", err)
+	}
+}
+
+func TestVerifyMP4DetectsMissingMoov(t *testing.T) {
+	data := append(testAtom("ftyp", []byte("M4A \x00\x00\x00\x00")), testAtom("mdat", make([]byte, 16))...)
+	path := writeTestFile(t, "01. Song.m4a", data)
+	if err := File(path); !errors.Is(err, ErrCorrupt) {
+		t.Fatalf("File() = %v, want ErrCorrupt", err)
+	}
+}
@@ -0,0 +1,137 @@
+package verify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrUnsupportedFormat is returned when a file extension has no verifier.
+	ErrUnsupportedFormat = errors.New("unsupported audio format for verification")
+
+	// ErrCorrupt indicates a file whose structure or audio data is damaged.
+	ErrCorrupt = errors.New("corrupt audio file")
+
+	// ErrTruncated indicates a file that ends before its declared length.
+	ErrTruncated = errors.New("truncated audio file")
+)
+
+// Result statuses reported by Scan.
+const (
+	StatusOK      = "ok"
+	StatusCorrupt = "corrupt"
+	StatusShort   = "short"
+)
+
+// Result is the outcome of verifying one file.
+type Result struct {
+	Path   string `json:"path"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// IsAudioFile reports whether path has an extension File can verify.
+func IsAudioFile(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".flac", ".m4a", ".mp4", ".aac", ".alac":
+		return true
+	default:
+		return false
+	}
+}
+
+// File checks the audio file at path, choosing the container format from the
+// file extension. FLAC files are fully decoded so frame CRCs and the
+// STREAMINFO MD5 are checked; MP4 files have their atom tree and sample table
+// checked against the file length.
+func File(path string) error {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".flac":
+		return verifyFLAC(path)
+	case ".m4a", ".mp4", ".aac", ".alac":
+		return verifyMP4(path)
+	default:
+		return fmt.Errorf("%w: %s", ErrUnsupportedFormat, filepath.Ext(path))
+	}
+}
+
+// Duration returns the playing time of the audio file at path from its
+// header. Only FLAC is supported; other formats return ErrUnsupportedFormat,
+// as do FLAC files that do not declare their length.
+func Duration(path string) (time.Duration, error) {
+	if !strings.EqualFold(filepath.Ext(path), ".flac") {
+		return 0, fmt.Errorf("%w: %s", ErrUnsupportedFormat, filepath.Ext(path))
+	}
+	return flacDuration(path)
+}
+
+// Size checks that the file at path is exactly expected bytes long, as
+// announced by the server's Content-Length.
+func Size(path string, expected int64) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	switch {
+	case info.Size() < expected:
+		return fmt.Errorf("%w: %d of %d bytes", ErrTruncated, info.Size(), expected)
+	case info.Size() > expected:
+		return fmt.Errorf("%w: %d bytes, expected %d", ErrCorrupt, info.Size(), expected)
+	}
+	return nil
+}
+
+// StatusOf maps a verification error to a Result status.
+func StatusOf(err error) string {
+	switch {
+	case err == nil:
+		return StatusOK
+	case errors.Is(err, ErrTruncated):
+		return StatusShort
+	default:
+		return StatusCorrupt
+	}
+}
+
+// Scan verifies every audio file below root, calling progress (when non-nil)
+// after each file. Results are sorted by path.
+func Scan(ctx context.Context, root string, progress func(Result)) ([]Result, error) {
+	var paths []string
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && IsAudioFile(path) && !strings.HasPrefix(d.Name(), ".") {
+			paths = append(paths, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+
+	results := make([]Result, 0, len(paths))
+	for _, path := range paths {
+		if err := ctx.Err(); err != nil {
+			return results, err
+		}
+		err := File(path)
+		result := Result{Path: path, Status: StatusOf(err)}
+		if err != nil {
+			result.Error = err.Error()
+		}
+		results = append(results, result)
+		if progress != nil {
+			progress(result)
+		}
+	}
+	return results, nil
+}
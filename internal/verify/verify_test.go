@@ -0,0 +1,61 @@
+package verify
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestScanReportsCorruptAndShortFiles(t *testing.T) {
+	root := t.TempDir()
+	show := filepath.Join(root, "Artist", "Artist - Show")
+	if err := os.MkdirAll(show, 0755); err != nil {
+		t.Fatal(err)
+	}
+	data := testFLAC(t)
+	files := map[string][]byte{
+		"01. Good.flac":  data,
+		"02. Short.flac": data[:len(data)/2],
+		"03. Junk.m4a":   []byte("not an mp4 file at all"),
+		"folder.jpg":     {0xff, 0xd8},
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(show, name), content, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var seen int
+	results, err := Scan(context.Background(), root, func(Result) { seen++ })
+	if err != nil {
+		t.Fatalf("Scan: %v", err)
+	}
+	want := map[string]string{
+		"01. Good.flac":  StatusOK,
+		"02. Short.flac": StatusShort,
+		"03. Junk.m4a":   StatusCorrupt,
+	}
+	if len(results) != len(want) || seen != len(want) {
+		t.Fatalf("Scan returned %d results (%d progress calls), want %d: %+v", len(results), seen, len(want), results)
+	}
+	for _, result := range results {
+		if got := result.Status; got != want[filepath.Base(result.Path)] {
+			t.Errorf("%s: status %q (%s), want %q", filepath.Base(result.Path), got, result.Error, want[filepath.Base(result.Path)])
+		}
+	}
+}
+
+func TestSizeChecksContentLength(t *testing.T) {
+	path := writeTestFile(t, "01. Song.flac", make([]byte, 100))
+	if err := Size(path, 100); err != nil {
+		t.Fatalf("Size(100) = %v", err)
+	}
+	if err := Size(path, 200); !errors.Is(err, ErrTruncated) {
+		t.Fatalf("Size(200) = %v, want ErrTruncated", err)
+	}
+	if err := Size(path, 50); !errors.Is(err, ErrCorrupt) {
+		t.Fatalf("Size(50) = %v, want ErrCorrupt", err)
+	}
+}